@@ -0,0 +1,109 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Token scopes: read-only tokens may only call tools that change nothing
+const (
+	ScopeReadOnly = "read-only"
+	ScopeFull     = "full"
+)
+
+// TokensFile is the --tokens file of an HTTP server
+type TokensFile struct {
+	Tokens []Token `json:"tokens"`
+}
+
+// Token is a bearer token allowed to call the HTTP server. Its secret is given
+// as Token, or as the hex SHA-256 of the token so the file holds no secret.
+type Token struct {
+	// Name identifies the token's holder in the audit log
+	Name   string `json:"name"`
+	Token  string `json:"token,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	// Scope is ScopeReadOnly or ScopeFull
+	Scope string `json:"scope"`
+	// Tools, when set, further limits the tools the token may call
+	Tools []string `json:"tools,omitempty"`
+}
+
+// loadTokens reads and validates a tokens file
+func loadTokens(path string) ([]Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+	var file TokensFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid tokens file %s: %w", path, err)
+	}
+	if len(file.Tokens) == 0 {
+		return nil, fmt.Errorf("tokens file %s defines no tokens", path)
+	}
+
+	names := make(map[string]bool)
+	for i, t := range file.Tokens {
+		switch {
+		case t.Name == "":
+			return nil, fmt.Errorf("token %d in %s has no name", i+1, path)
+		case names[t.Name]:
+			return nil, fmt.Errorf("duplicate token name %q in %s", t.Name, path)
+		case (t.Token == "") == (t.SHA256 == ""):
+			return nil, fmt.Errorf("token %q needs exactly one of token or sha256", t.Name)
+		case t.Scope != ScopeReadOnly && t.Scope != ScopeFull:
+			return nil, fmt.Errorf("token %q has scope %q, want %s or %s", t.Name, t.Scope, ScopeReadOnly, ScopeFull)
+		}
+		names[t.Name] = true
+		if t.SHA256 != "" {
+			if _, err := hex.DecodeString(t.SHA256); err != nil || len(t.SHA256) != sha256.Size*2 {
+				return nil, fmt.Errorf("token %q: sha256 must be 64 hex digits", t.Name)
+			}
+		}
+	}
+	return file.Tokens, nil
+}
+
+// authenticate returns the token presented in an Authorization header
+func authenticate(tokens []Token, header string) (*Token, bool) {
+	secret, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || secret == "" {
+		return nil, false
+	}
+	sum := sha256.Sum256([]byte(secret))
+	digest := hex.EncodeToString(sum[:])
+	for i := range tokens {
+		t := &tokens[i]
+		want, got := t.Token, secret
+		if t.SHA256 != "" {
+			want, got = strings.ToLower(t.SHA256), digest
+		}
+		if subtle.ConstantTimeCompare([]byte(want), []byte(got)) == 1 {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// allows reports whether the token may call tool with args. Read-only tokens
+// may call the tools annotated read-only. Toggling a task with mp_issue_tasks
+// edits the issue, so read-only tokens can only list them.
+func (t *Token) allows(tool string, args map[string]string) bool {
+	if len(t.Tools) > 0 && !slices.Contains(t.Tools, tool) {
+		return false
+	}
+	if t.Scope == ScopeFull {
+		return true
+	}
+	if tool == "mp_issue_tasks" {
+		return args["check"] == ""
+	}
+	return isReadOnly(tool)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
+)
+
+// errIncompatible answers initialize when the mp binary can't serve this
+// server: it doesn't run, or speaks another API version
+const errIncompatible = -32002
+
+// apiVersionPattern finds the API version in mp --version: "mp version 1.2.0 (api v1)"
+var apiVersionPattern = regexp.MustCompile(`\(api (v\d+)\)`)
+
+// Compatibility is the error data of an incompatible mp binary
+type Compatibility struct {
+	MPPath string `json:"mp_path"`
+	// MPVersion is what mp --version printed
+	MPVersion  string `json:"mp_version,omitempty"`
+	APIVersion string `json:"api_version,omitempty"`
+	// ServerAPIVersion is the API version mp-mcp expects
+	ServerAPIVersion string `json:"server_api_version"`
+}
+
+// checkMp verifies that the mp binary runs and speaks the API version mp-mcp
+// parses, so a client learns of a mismatched install when it connects rather
+// than from garbled tool results
+func (s *Server) checkMp() (Compatibility, error) {
+	compat := Compatibility{MPPath: s.mpPath, ServerAPIVersion: meta.APIVersion}
+	out, err := exec.Command(s.mpPath, "--version").Output()
+	if err != nil {
+		return compat, fmt.Errorf("cannot run mp at %s: %w; run mp mcp install or pass --mp", s.mpPath, err)
+	}
+	compat.MPVersion = strings.TrimSpace(string(out))
+	if m := apiVersionPattern.FindStringSubmatch(compat.MPVersion); m != nil {
+		compat.APIVersion = m[1]
+	}
+	switch compat.APIVersion {
+	case meta.APIVersion:
+		return compat, nil
+	case "":
+		return compat, fmt.Errorf("mp at %s predates API versions; upgrade it to match mp-mcp %s", s.mpPath, meta.Version())
+	default:
+		return compat, fmt.Errorf("mp at %s speaks API %s, mp-mcp %s speaks %s; install matching versions of both",
+			s.mpPath, compat.APIVersion, meta.Version(), meta.APIVersion)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// EventToolCall is the audit log entry of a tool called over HTTP
+const EventToolCall = "mcp.tool_call"
+
+// maxRequestBytes caps the size of an HTTP request body, like the stdio line buffer
+const maxRequestBytes = 1024 * 1024
+
+// errForbidden is the JSON-RPC error code of a tool call the token may not make
+const errForbidden = -32001
+
+// Tool call outcomes recorded in the audit log
+const (
+	outcomeOK     = "ok"
+	outcomeError  = "error"
+	outcomeDenied = "denied"
+)
+
+// serveHTTP serves JSON-RPC requests POSTed to addr, each authenticated with
+// one of tokens as a bearer token, until a signal arrives. It then stops
+// accepting connections and returns once in-flight requests are answered.
+func (s *Server) serveHTTP(addr string, tokens []Token, signals <-chan os.Signal) error {
+	srv := &http.Server{Addr: addr, Handler: s.httpHandler(tokens)}
+	served := make(chan error, 1)
+	go func() {
+		log.Printf("mp-mcp listening on %s", addr)
+		served <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case sig := <-signals:
+		log.Printf("mp-mcp: received %s, finishing in-flight requests", sig)
+	}
+	s.draining.Store(true)
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- srv.Shutdown(context.Background()) }()
+	select {
+	case err := <-shutdown:
+		if err != nil {
+			return err
+		}
+	case sig := <-signals:
+		log.Printf("mp-mcp: received %s again, exiting without waiting", sig)
+		return srv.Close()
+	}
+	log.Printf("mp-mcp: shut down")
+	return nil
+}
+
+// httpHandler answers one JSON-RPC request per POST. Requests without a
+// known bearer token get 401; notifications get 202 and no body.
+func (s *Server) httpHandler(tokens []Token) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		caller, ok := authenticate(tokens, r.Header.Get("Authorization"))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mp-mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			writeHTTPResponse(w, errorResponse(nil, -32700, "Parse error", err.Error()))
+			return
+		}
+		if s.draining.Load() {
+			// Requests on connections kept alive while Shutdown waits
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(shuttingDownResponse(req.ID))
+			return
+		}
+		resp := s.handle(&req, caller)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writeHTTPResponse(w, resp)
+	})
+}
+
+func writeHTTPResponse(w http.ResponseWriter, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// audit records a tool call made with caller in the events log of the
+// repository at cwd, or on stderr outside a monkeypuzzle repository
+func (s *Server) audit(caller *Token, tool, cwd, outcome string) {
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+	}
+	fs := adapters.NewOSFS("")
+	root, ok := initcmd.FindRepoConfig(fs, cwd)
+	if !ok {
+		log.Printf("audit: token %s called %s in %s: %s", caller.Name, tool, cwd, outcome)
+		return
+	}
+
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+	err := events.Append(fs, root, events.Event{
+		Type: EventToolCall,
+		Data: map[string]any{"tool": tool, "token": caller.Name, "scope": caller.Scope, "outcome": outcome},
+	})
+	if err != nil {
+		log.Printf("audit: failed to log %s call by token %s: %v", tool, caller.Name, err)
+	}
+}
+
+// httpFlagsError explains flag combinations the HTTP transport refuses
+func httpFlagsError(addr, tokensPath string) error {
+	if addr == "" && tokensPath != "" {
+		return fmt.Errorf("--tokens requires --http")
+	}
+	if addr != "" && tokensPath == "" {
+		return fmt.Errorf("--http requires --tokens: anyone who can reach the server could otherwise create branches and PRs")
+	}
+	return nil
+}
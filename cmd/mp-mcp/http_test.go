@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+)
+
+// setupRepo writes a monkeypuzzle repository with one issue, add-login
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	config := `{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}}}`
+	for path, content := range map[string]string{
+		".monkeypuzzle/monkeypuzzle.json": config,
+		"issues/add-login.md":             "---\ntitle: Add login\n---\n",
+	} {
+		_ = os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0755)
+		if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func testTokens() []Token {
+	sum := sha256.Sum256([]byte("reader-secret"))
+	return []Token{
+		{Name: "ci", SHA256: hex.EncodeToString(sum[:]), Scope: ScopeReadOnly},
+		{Name: "alice", Token: "alice-secret", Scope: ScopeFull},
+	}
+}
+
+// post sends a JSON-RPC request to handler with token, returning the HTTP
+// status and the decoded response
+func post(t *testing.T, handler http.Handler, token string, req Request) (int, Response) {
+	t.Helper()
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httpReq)
+
+	var resp Response
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON-RPC response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func toolCall(name string, args map[string]string) Request {
+	arguments, _ := json.Marshal(args)
+	params, _ := json.Marshal(ToolCallParams{Name: name, Arguments: arguments})
+	return Request{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params}
+}
+
+func TestHTTP_RequiresBearerToken(t *testing.T) {
+	handler := (&Server{mpPath: "mp"}).httpHandler(testTokens())
+	list := Request{JSONRPC: "2.0", ID: 1, Method: "tools/list"}
+
+	for _, token := range []string{"", "wrong", "sha256-of-nothing"} {
+		if code, _ := post(t, handler, token, list); code != http.StatusUnauthorized {
+			t.Errorf("token %q: expected 401, got %d", token, code)
+		}
+	}
+	if code, _ := post(t, handler, "alice-secret", list); code != http.StatusOK {
+		t.Errorf("expected a valid token to be accepted, got %d", code)
+	}
+}
+
+func TestHTTP_ReadOnlyTokenScope(t *testing.T) {
+	root := setupRepo(t)
+	handler := (&Server{mpPath: "mp"}).httpHandler(testTokens())
+
+	_, resp := post(t, handler, "reader-secret", Request{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+	data, _ := json.Marshal(resp.Result)
+	var list ToolsListResult
+	_ = json.Unmarshal(data, &list)
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "mp_pr_comments,mp_issue_list,mp_issue_tasks,mp_issue_read" {
+		t.Errorf("expected only read-only tools, got %v", names)
+	}
+
+	_, resp = post(t, handler, "reader-secret", toolCall("mp_piece_new", map[string]string{"cwd": root}))
+	if resp.Error == nil || resp.Error.Code != errForbidden {
+		t.Fatalf("expected mp_piece_new to be forbidden, got %+v", resp)
+	}
+	_, resp = post(t, handler, "reader-secret", toolCall("mp_issue_tasks", map[string]string{"issue": "add-login", "check": "1", "cwd": root}))
+	if resp.Error == nil || resp.Error.Code != errForbidden {
+		t.Errorf("expected toggling a task to be forbidden, got %+v", resp)
+	}
+	_, resp = post(t, handler, "reader-secret", toolCall("mp_issue_read", map[string]string{"path": "add-login", "cwd": root}))
+	if resp.Error != nil {
+		t.Fatalf("expected mp_issue_read to be allowed, got %+v", resp.Error)
+	}
+
+	logged, err := events.Read(adapters.NewOSFS(""), root)
+	if err != nil || len(logged) != 3 {
+		t.Fatalf("expected 3 audit entries, got %+v (%v)", logged, err)
+	}
+	for i, want := range []string{outcomeDenied, outcomeDenied, outcomeOK} {
+		e := logged[i]
+		if e.Type != EventToolCall || e.Data["token"] != "ci" || e.Data["outcome"] != want {
+			t.Errorf("entry %d: expected a %s call by ci, got %+v", i, want, e)
+		}
+	}
+}
+
+func TestHTTP_ToolAllowlist(t *testing.T) {
+	token := Token{Name: "bot", Token: "x", Scope: ScopeFull, Tools: []string{"mp_issue_list"}}
+	if !token.allows("mp_issue_list", nil) || token.allows("mp_piece_merge", nil) {
+		t.Error("expected tools to limit a full token")
+	}
+}
+
+func TestLoadTokens_Invalid(t *testing.T) {
+	tests := map[string]string{
+		`{"tokens": []}`: "defines no tokens",
+		`{"tokens": [{"token": "x", "scope": "full"}]}`:                                                            "has no name",
+		`{"tokens": [{"name": "a", "scope": "full"}]}`:                                                             "exactly one of token or sha256",
+		`{"tokens": [{"name": "a", "token": "x", "scope": "admin"}]}`:                                              `scope "admin"`,
+		`{"tokens": [{"name": "a", "sha256": "abc", "scope": "full"}]}`:                                            "64 hex digits",
+		`{"tokens": [{"name": "a", "token": "x", "scope": "full"}, {"name": "a", "token": "y", "scope": "full"}]}`: "duplicate token name",
+	}
+	for content, want := range tests {
+		path := filepath.Join(t.TempDir(), "tokens.json")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadTokens(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", content, want, err)
+		}
+	}
+}
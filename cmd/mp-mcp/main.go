@@ -1,13 +1,25 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
 )
 
 // JSON-RPC 2.0 types
@@ -39,7 +51,8 @@ type InitializeResult struct {
 }
 
 type Capabilities struct {
-	Tools *ToolsCapability `json:"tools,omitempty"`
+	Tools     *ToolsCapability     `json:"tools,omitempty"`
+	Resources *ResourcesCapability `json:"resources,omitempty"`
 }
 
 type ToolsCapability struct{}
@@ -50,11 +63,33 @@ type ServerInfo struct {
 }
 
 type Tool struct {
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	InputSchema JSONSchema `json:"inputSchema"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema JSONSchema      `json:"inputSchema"`
+	Annotations ToolAnnotations `json:"annotations"`
 }
 
+// ToolAnnotations tell clients what a tool does to the repository, issues and
+// PRs, e.g. to ask before calling a destructive one
+type ToolAnnotations struct {
+	// ReadOnlyHint: the tool changes nothing
+	ReadOnlyHint bool `json:"readOnlyHint"`
+	// DestructiveHint: the tool may change existing state in a way another
+	// call can't undo, rather than only adding to it
+	DestructiveHint bool `json:"destructiveHint"`
+	// IdempotentHint: calling it again with the same arguments has no further effect
+	IdempotentHint bool `json:"idempotentHint"`
+}
+
+// Annotations of the kinds of tools: reading, writing what another call can
+// undo, and destructive
+var (
+	readOnly         = ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+	writes           = ToolAnnotations{}
+	writesIdempotent = ToolAnnotations{IdempotentHint: true}
+	destructive      = ToolAnnotations{DestructiveHint: true}
+)
+
 type JSONSchema struct {
 	Type       string              `json:"type"`
 	Properties map[string]Property `json:"properties,omitempty"`
@@ -78,6 +113,8 @@ type ToolCallParams struct {
 type ToolCallResult struct {
 	Content []ContentItem `json:"content"`
 	IsError bool          `json:"isError,omitempty"`
+	// Meta carries the API version of the JSON in Content
+	Meta *meta.APIInfo `json:"_meta,omitempty"`
 }
 
 type ContentItem struct {
@@ -87,34 +124,53 @@ type ContentItem struct {
 
 type Server struct {
 	mpPath string
+	// auditMu serializes audit log writes of concurrent HTTP requests
+	auditMu sync.Mutex
+	// draining is set once a shutdown signal arrives
+	draining atomic.Bool
+	// maxResultBytes caps the text of a tool result, 0 for no limit
+	maxResultBytes int
+	// hideDestructive hides destructive tools and refuses calls to them
+	hideDestructive bool
+	continuations   continuations
 }
 
 func main() {
-	server := &Server{mpPath: findMpBinary()}
+	httpAddr := flag.String("http", "", "Serve JSON-RPC over HTTP on this address (e.g. 127.0.0.1:8765) instead of stdio")
+	tokensPath := flag.String("tokens", "", "JSON file of bearer tokens allowed to call the HTTP server (required with --http)")
+	mpPath := flag.String("mp", "", "Path of the mp binary to run (default: next to mp-mcp, then on PATH)")
+	maxResultBytes := flag.Int("max-result-bytes", defaultMaxResultBytes, "Truncate tool results longer than this many bytes, to be paged through with continuation (0 for no limit)")
+	hideDestructive := flag.Bool("hide-destructive", false, "Hide tools annotated destructive (mp_piece_merge) from tools/list and refuse calls to them")
+	flag.Parse()
+	if err := httpFlagsError(*httpAddr, *tokensPath); err != nil {
+		log.Fatal(err)
+	}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	server := &Server{mpPath: findMpBinary(*mpPath), maxResultBytes: *maxResultBytes, hideDestructive: *hideDestructive}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			continue
-		}
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
 
-		var req Request
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
-			writeResponse(errorResponse(nil, -32700, "Parse error", err.Error()))
-			continue
+	if *httpAddr != "" {
+		tokens, err := loadTokens(*tokensPath)
+		if err != nil {
+			log.Fatal(err)
 		}
-
-		resp := server.handleRequest(&req)
-		if resp != nil {
-			writeResponse(resp)
+		if err := server.serveHTTP(*httpAddr, tokens, signals); err != nil {
+			log.Fatal(err)
 		}
+		return
 	}
+
+	server.serveStdio(os.Stdin, os.Stdout, signals)
 }
 
-func findMpBinary() string {
+// findMpBinary returns the mp binary to run: flagPath when given (mp mcp
+// install passes it), else the one next to mp-mcp, else the one on PATH
+func findMpBinary(flagPath string) string {
+	if flagPath != "" {
+		return flagPath
+	}
 	if exe, err := os.Executable(); err == nil {
 		mpPath := filepath.Join(filepath.Dir(exe), "mp")
 		if _, err := os.Stat(mpPath); err == nil {
@@ -127,30 +183,49 @@ func findMpBinary() string {
 	return "mp"
 }
 
+// handleRequest answers a request read from stdio, where every tool is allowed
 func (s *Server) handleRequest(req *Request) *Response {
+	return s.handle(req, nil)
+}
+
+// handle answers a request made with caller's token, or over stdio when
+// caller is nil
+func (s *Server) handle(req *Request, caller *Token) *Response {
 	switch req.Method {
 	case "initialize":
+		if compat, err := s.checkMp(); err != nil {
+			return errorResponse(req.ID, errIncompatible, err.Error(), compat)
+		}
 		return successResponse(req.ID, InitializeResult{
 			ProtocolVersion: "2024-11-05",
-			Capabilities:    Capabilities{Tools: &ToolsCapability{}},
-			ServerInfo:      ServerInfo{Name: "monkeypuzzle-mcp", Version: "0.1.0"},
+			Capabilities:    Capabilities{Tools: &ToolsCapability{}, Resources: &ResourcesCapability{}},
+			ServerInfo:      ServerInfo{Name: "monkeypuzzle-mcp", Version: meta.Version()},
 		})
 	case "initialized":
 		return nil
 	case "tools/list":
-		return s.handleToolsList(req)
+		return s.handleToolsList(req, caller)
 	case "tools/call":
-		return s.handleToolsCall(req)
+		return s.handleToolsCall(req, caller)
+	case "resources/list":
+		return s.handleResourcesList(req)
+	case "resources/read":
+		return s.handleResourcesRead(req)
 	default:
 		return errorResponse(req.ID, -32601, "Method not found", nil)
 	}
 }
 
-func (s *Server) handleToolsList(req *Request) *Response {
+// issueArgDescription describes tool arguments naming an issue
+const issueArgDescription = "Issue short ID from mp_issue_list (e.g. add-login) or path to the issue file"
+
+// toolList returns every tool mp-mcp serves
+func toolList() []Tool {
 	tools := []Tool{
 		{
 			Name:        "mp_init",
 			Description: "Initialize monkeypuzzle in a directory",
+			Annotations: writes,
 			InputSchema: JSONSchema{
 				Type: "object",
 				Properties: map[string]Property{
@@ -164,18 +239,22 @@ func (s *Server) handleToolsList(req *Request) *Response {
 		{
 			Name:        "mp_piece_new",
 			Description: "Create new piece (git worktree + tmux session)",
+			Annotations: writes,
 			InputSchema: JSONSchema{
 				Type: "object",
 				Properties: map[string]Property{
-					"name":  {Type: "string", Description: "Piece name"},
-					"issue": {Type: "string", Description: "Path to issue file"},
-					"cwd":   {Type: "string", Description: "Working directory"},
+					"name":     {Type: "string", Description: "Piece name"},
+					"issue":    {Type: "string", Description: issueArgDescription},
+					"title":    {Type: "string", Description: "Title of an issue to create for ad-hoc work, instead of name or issue"},
+					"template": {Type: "string", Description: "Piece template from .monkeypuzzle/piece-templates (e.g. bugfix)"},
+					"cwd":      {Type: "string", Description: "Working directory"},
 				},
 			},
 		},
 		{
 			Name:        "mp_piece_update",
 			Description: "Update piece with latest from main branch",
+			Annotations: writesIdempotent,
 			InputSchema: JSONSchema{
 				Type: "object",
 				Properties: map[string]Property{
@@ -187,6 +266,7 @@ func (s *Server) handleToolsList(req *Request) *Response {
 		{
 			Name:        "mp_piece_merge",
 			Description: "Merge piece back into main branch",
+			Annotations: destructive,
 			InputSchema: JSONSchema{
 				Type: "object",
 				Properties: map[string]Property{
@@ -195,9 +275,46 @@ func (s *Server) handleToolsList(req *Request) *Response {
 				},
 			},
 		},
+		{
+			Name:        "mp_pr_comments",
+			Description: "List unresolved review threads on the piece's PR (file, line, author, body)",
+			Annotations: readOnly,
+			InputSchema: JSONSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"all": {Type: "string", Description: "Set to 'true' to include resolved threads"},
+					"cwd": {Type: "string", Description: "Working directory (piece worktree)"},
+				},
+			},
+		},
+		{
+			Name:        "mp_issue_link",
+			Description: "Link the current piece to an issue (marks it in-progress, reverts a previously linked issue to todo)",
+			Annotations: writesIdempotent,
+			InputSchema: JSONSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"path": {Type: "string", Description: issueArgDescription},
+					"cwd":  {Type: "string", Description: "Working directory (piece worktree)"},
+				},
+				Required: []string{"path"},
+			},
+		},
+		{
+			Name:        "mp_issue_unlink",
+			Description: "Unlink the current piece from its issue (reverts it to todo if in-progress)",
+			Annotations: writesIdempotent,
+			InputSchema: JSONSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"cwd": {Type: "string", Description: "Working directory (piece worktree)"},
+				},
+			},
+		},
 		{
 			Name:        "mp_issue_list",
-			Description: "List issues in the issues directory",
+			Description: "List issues in the issues directory with task list completion",
+			Annotations: readOnly,
 			InputSchema: JSONSchema{
 				Type: "object",
 				Properties: map[string]Property{
@@ -206,20 +323,79 @@ func (s *Server) handleToolsList(req *Request) *Response {
 				},
 			},
 		},
+		{
+			Name:        "mp_issue_create",
+			Description: "Create an issue; fields defined in the project's issues.fields get their defaults and are validated",
+			Annotations: writes,
+			InputSchema: JSONSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"title":       {Type: "string", Description: "Issue title"},
+					"description": {Type: "string", Description: "Issue description"},
+					"fields":      {Type: "string", Description: `Frontmatter fields as a JSON object, e.g. {"priority": "high", "components": "api, db"}`},
+					"cwd":         {Type: "string", Description: "Working directory"},
+				},
+				Required: []string{"title"},
+			},
+		},
+		{
+			Name:        "mp_issue_tasks",
+			Description: "List an issue's task list items, or toggle one with check",
+			Annotations: writes,
+			InputSchema: JSONSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"issue": {Type: "string", Description: issueArgDescription},
+					"check": {Type: "string", Description: "Task number to toggle"},
+					"cwd":   {Type: "string", Description: "Working directory"},
+				},
+				Required: []string{"issue"},
+			},
+		},
 		{
 			Name:        "mp_issue_read",
-			Description: "Read content of an issue file",
+			Description: "Read an issue, returning its canonical id, path, content, and attached asset paths as JSON",
+			Annotations: readOnly,
 			InputSchema: JSONSchema{
 				Type:       "object",
-				Properties: map[string]Property{"path": {Type: "string", Description: "Path to issue file"}, "cwd": {Type: "string", Description: "Working directory"}},
+				Properties: map[string]Property{"path": {Type: "string", Description: issueArgDescription}, "cwd": {Type: "string", Description: "Working directory"}},
 				Required:   []string{"path"},
 			},
 		},
 	}
+	for i := range tools {
+		tools[i].InputSchema.Properties["continuation"] = Property{Type: "string", Description: continuationArgDescription}
+	}
+	return tools
+}
+
+// isReadOnly reports whether tool is annotated read-only
+func isReadOnly(tool string) bool {
+	tools := toolList()
+	i := slices.IndexFunc(tools, func(t Tool) bool { return t.Name == tool })
+	return i >= 0 && tools[i].Annotations.ReadOnlyHint
+}
+
+// isDestructive reports whether tool is annotated destructive
+func isDestructive(tool string) bool {
+	tools := toolList()
+	i := slices.IndexFunc(tools, func(t Tool) bool { return t.Name == tool })
+	return i >= 0 && tools[i].Annotations.DestructiveHint
+}
+
+func (s *Server) handleToolsList(req *Request, caller *Token) *Response {
+	tools := toolList()
+	if s.hideDestructive {
+		tools = slices.DeleteFunc(tools, func(t Tool) bool { return t.Annotations.DestructiveHint })
+	}
+	if caller != nil {
+		// List only the tools the token may call at all
+		tools = slices.DeleteFunc(tools, func(t Tool) bool { return !caller.allows(t.Name, nil) })
+	}
 	return successResponse(req.ID, ToolsListResult{Tools: tools})
 }
 
-func (s *Server) handleToolsCall(req *Request) *Response {
+func (s *Server) handleToolsCall(req *Request, caller *Token) *Response {
 	var params ToolCallParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		return errorResponse(req.ID, -32602, "Invalid params", err.Error())
@@ -235,10 +411,43 @@ func (s *Server) handleToolsCall(req *Request) *Response {
 		args = make(map[string]string)
 	}
 
-	result, isError := s.executeTool(params.Name, args)
+	if s.hideDestructive && isDestructive(params.Name) {
+		if caller != nil {
+			s.audit(caller, params.Name, args["cwd"], outcomeDenied)
+		}
+		return errorResponse(req.ID, errForbidden, "Forbidden",
+			fmt.Sprintf("%s is destructive and mp-mcp runs with --hide-destructive", params.Name))
+	}
+	if caller != nil && !caller.allows(params.Name, args) {
+		s.audit(caller, params.Name, args["cwd"], outcomeDenied)
+		return errorResponse(req.ID, errForbidden, "Forbidden",
+			fmt.Sprintf("token %s (%s) may not call %s", caller.Name, caller.Scope, params.Name))
+	}
+
+	var callerName string
+	if caller != nil {
+		callerName = caller.Name
+	}
+	var result string
+	var isError bool
+	if continuation := args["continuation"]; continuation != "" {
+		result, isError = s.continueResult(params.Name, callerName, continuation)
+	} else {
+		result, isError = s.executeTool(params.Name, args)
+		result = s.page(params.Name, callerName, result, 0, 0)
+	}
+	if caller != nil {
+		outcome := outcomeOK
+		if isError {
+			outcome = outcomeError
+		}
+		s.audit(caller, params.Name, args["cwd"], outcome)
+	}
+	info := meta.Info()
 	return successResponse(req.ID, ToolCallResult{
 		Content: []ContentItem{{Type: "text", Text: result}},
 		IsError: isError,
+		Meta:    &info,
 	})
 }
 
@@ -275,7 +484,17 @@ func (s *Server) executeTool(name string, args map[string]string) (string, bool)
 			cmdArgs = append(cmdArgs, "--name", v)
 		}
 		if v := args["issue"]; v != "" {
-			cmdArgs = append(cmdArgs, "--issue", v)
+			path, err := canonicalIssuePath(cwd, v)
+			if err != nil {
+				return fmt.Sprintf("Error: %v", err), true
+			}
+			cmdArgs = append(cmdArgs, "--issue", path)
+		}
+		if v := args["title"]; v != "" {
+			cmdArgs = append(cmdArgs, "--title", v)
+		}
+		if v := args["template"]; v != "" {
+			cmdArgs = append(cmdArgs, "--template", v)
 		}
 
 	case "mp_piece_update":
@@ -290,8 +509,59 @@ func (s *Server) executeTool(name string, args map[string]string) (string, bool)
 			cmdArgs = append(cmdArgs, "--main-branch", v)
 		}
 
+	case "mp_pr_comments":
+		cmdArgs = []string{"piece", "pr", "comments"}
+		if args["all"] == "true" {
+			cmdArgs = append(cmdArgs, "--all")
+		}
+
+	case "mp_issue_link":
+		if args["path"] == "" {
+			return "Error: path is required", true
+		}
+		path, err := canonicalIssuePath(cwd, args["path"])
+		if err != nil {
+			return fmt.Sprintf("Error: %v", err), true
+		}
+		cmdArgs = []string{"issue", "link", path}
+
+	case "mp_issue_unlink":
+		cmdArgs = []string{"issue", "unlink"}
+
 	case "mp_issue_list":
-		return s.listIssues(cwd, args["status"])
+		cmdArgs = []string{"issue", "list"}
+		if v := args["status"]; v != "" {
+			cmdArgs = append(cmdArgs, "--status", v)
+		}
+
+	case "mp_issue_create":
+		if args["title"] == "" {
+			return "Error: title is required", true
+		}
+		input := map[string]any{"title": args["title"], "description": args["description"]}
+		if v := args["fields"]; v != "" {
+			var fields map[string]string
+			if err := json.Unmarshal([]byte(v), &fields); err != nil {
+				return fmt.Sprintf("Error: fields must be a JSON object of strings: %v", err), true
+			}
+			input["fields"] = fields
+		}
+		data, _ := json.Marshal(input)
+		cmdArgs = []string{"issue", "create"}
+		stdin = string(data)
+
+	case "mp_issue_tasks":
+		if args["issue"] == "" {
+			return "Error: issue is required", true
+		}
+		id, err := canonicalIssuePath(cwd, args["issue"])
+		if err != nil {
+			return fmt.Sprintf("Error: %v", err), true
+		}
+		cmdArgs = []string{"issue", "tasks", id}
+		if v := args["check"]; v != "" {
+			cmdArgs = []string{"issue", "tasks", "check", id, v}
+		}
 
 	case "mp_issue_read":
 		if path := args["path"]; path != "" {
@@ -307,8 +577,11 @@ func (s *Server) executeTool(name string, args map[string]string) (string, bool)
 }
 
 func (s *Server) runMp(cwd string, args []string, stdin string) (string, bool) {
-	cmd := exec.Command(s.mpPath, args...)
+	// Failures end with a JSON error carrying a fix hint, when one is known
+	cmd := exec.Command(s.mpPath, append(args, "--json-errors")...)
 	cmd.Dir = cwd
+	// Issue edits wait for a human where issues.require_approval is set
+	cmd.Env = append(os.Environ(), issue.StageEnv+"=1")
 	if stdin != "" {
 		cmd.Stdin = strings.NewReader(stdin)
 	}
@@ -322,79 +595,66 @@ func (s *Server) runMp(cwd string, args []string, stdin string) (string, bool) {
 	return string(output), false
 }
 
-func (s *Server) listIssues(cwd, statusFilter string) (string, bool) {
-	issuesDir := filepath.Join(cwd, "issues")
-	entries, err := os.ReadDir(issuesDir)
+// issueContent is the result of mp_issue_read
+type issueContent struct {
+	issue.IssueRef
+	Content string `json:"content"`
+	// Assets are the files attached to the issue, relative to the repository
+	Assets []string `json:"assets,omitempty"`
+}
+
+func (s *Server) readIssue(cwd, id string) (string, bool) {
+	ref, root, err := resolveIssue(cwd, id)
 	if err != nil {
 		return fmt.Sprintf("Error: %v", err), true
 	}
-
-	type Issue struct {
-		Path   string `json:"path"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
-	}
-	var issues []Issue
-
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
-			continue
-		}
-		path := filepath.Join("issues", e.Name())
-		title, status := parseIssue(filepath.Join(cwd, path))
-		if statusFilter != "" && status != statusFilter {
-			continue
-		}
-		issues = append(issues, Issue{Path: path, Title: title, Status: status})
-	}
-
-	data, _ := json.MarshalIndent(issues, "", "  ")
-	return string(data), false
-}
-
-func (s *Server) readIssue(cwd, path string) (string, bool) {
-	content, err := os.ReadFile(filepath.Join(cwd, path))
+	content, err := issueFS().ReadFile(filepath.Join(root, ref.Path))
 	if err != nil {
 		return fmt.Sprintf("Error: %v", err), true
 	}
-	return string(content), false
+	assets := issue.NewHandler(core.Deps{FS: issueFS(), Output: adapters.NewBufferOutput()}, root).Assets(ref.Path)
+	data, _ := json.MarshalIndent(issueContent{IssueRef: ref, Content: string(content), Assets: assets}, "", "  ")
+	return string(data), false
 }
 
-func parseIssue(path string) (title, status string) {
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return filepath.Base(path), "todo"
+// resolveIssue checks that an issue short ID or path names an existing issue of
+// the repository containing cwd, returning its canonical reference and the
+// repository root. Agents often guess paths; failing here keeps tools from
+// acting on the wrong file.
+func resolveIssue(cwd, id string) (issue.IssueRef, string, error) {
+	fs := issueFS()
+	root, ok := initcmd.FindRepoConfig(fs, cwd)
+	if !ok {
+		return issue.IssueRef{}, "", fmt.Errorf("no monkeypuzzle config found from %s (run mp init first)", cwd)
 	}
-	text := string(content)
-	status = "todo"
-
-	if strings.HasPrefix(text, "---\n") {
-		if end := strings.Index(text[4:], "\n---"); end > 0 {
-			fm := text[4 : 4+end]
-			for _, line := range strings.Split(fm, "\n") {
-				line = strings.TrimSpace(line)
-				if strings.HasPrefix(line, "title:") {
-					title = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "title:")), `"'`)
-				}
-				if strings.HasPrefix(line, "status:") {
-					status = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "status:")), `"'`)
-				}
-			}
+	// Paths relative to cwd win; otherwise IDs and paths resolve from the root
+	if !filepath.IsAbs(id) {
+		if _, err := os.Stat(filepath.Join(cwd, id)); err == nil {
+			id = filepath.Join(cwd, id)
 		}
 	}
 
-	if title == "" {
-		for _, line := range strings.Split(text, "\n") {
-			if strings.HasPrefix(strings.TrimSpace(line), "# ") {
-				title = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "# "))
-				break
-			}
-		}
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, root)
+	ref, err := handler.Resolve(id)
+	if err != nil {
+		return issue.IssueRef{}, "", fmt.Errorf("%w (use mp_issue_list to see issue IDs)", err)
 	}
-	if title == "" {
-		title = strings.TrimSuffix(filepath.Base(path), ".md")
+	return ref, root, nil
+}
+
+// issueFS returns the filesystem issues are read through: the staged edits
+// of agents awaiting approval included, as the mp commands tools run see them
+func issueFS() core.FS {
+	return issue.NewStagingFS(adapters.NewOSFS(""), "")
+}
+
+// canonicalIssuePath resolves an issue short ID or path to the issue's absolute path
+func canonicalIssuePath(cwd, id string) (string, error) {
+	ref, root, err := resolveIssue(cwd, id)
+	if err != nil {
+		return "", err
 	}
-	return
+	return filepath.Join(root, ref.Path), nil
 }
 
 func successResponse(id any, result any) *Response {
@@ -404,8 +664,3 @@ func successResponse(id any, result any) *Response {
 func errorResponse(id any, code int, message string, data any) *Response {
 	return &Response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message, Data: data}}
 }
-
-func writeResponse(resp *Response) {
-	data, _ := json.Marshal(resp)
-	fmt.Println(string(data))
-}
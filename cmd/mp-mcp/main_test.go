@@ -2,11 +2,26 @@ package main
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
 )
 
+// fakeMp writes an mp whose --version prints version, returning its path
+func fakeMp(t *testing.T, version string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mp")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho '"+version+"'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
 func TestHandleInitialize(t *testing.T) {
-	server := &Server{mpPath: "mp"}
+	server := &Server{mpPath: fakeMp(t, "mp version 1.2.0 (api "+meta.APIVersion+")")}
 	req := &Request{
 		JSONRPC: "2.0",
 		ID:      1,
@@ -33,6 +48,24 @@ func TestHandleInitialize(t *testing.T) {
 	}
 }
 
+func TestHandleInitialize_IncompatibleMp(t *testing.T) {
+	for _, version := range []string{"mp version 0.9.0", "mp version 9.0.0 (api v9)"} {
+		server := &Server{mpPath: fakeMp(t, version)}
+		resp := server.handleRequest(&Request{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+		if resp.Error == nil || resp.Error.Code != errIncompatible {
+			t.Fatalf("%s: expected an incompatibility error, got %+v", version, resp)
+		}
+		if compat, ok := resp.Error.Data.(Compatibility); !ok || compat.MPVersion != version {
+			t.Errorf("%s: expected the mp version in the error data, got %+v", version, resp.Error.Data)
+		}
+	}
+
+	resp := (&Server{mpPath: "/nonexistent/mp"}).handleRequest(&Request{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+	if resp.Error == nil || !strings.Contains(resp.Error.Message, "cannot run mp") {
+		t.Errorf("expected a missing mp to fail initialize, got %+v", resp)
+	}
+}
+
 func TestHandleToolsList(t *testing.T) {
 	server := &Server{mpPath: "mp"}
 	req := &Request{
@@ -59,7 +92,12 @@ func TestHandleToolsList(t *testing.T) {
 		"mp_piece_new",
 		"mp_piece_update",
 		"mp_piece_merge",
+		"mp_pr_comments",
+		"mp_issue_link",
+		"mp_issue_unlink",
 		"mp_issue_list",
+		"mp_issue_create",
+		"mp_issue_tasks",
 		"mp_issue_read",
 	}
 
@@ -79,6 +117,40 @@ func TestHandleToolsList(t *testing.T) {
 	}
 }
 
+func TestToolAnnotations(t *testing.T) {
+	readOnlyToken := &Token{Name: "ci", Scope: ScopeReadOnly}
+	for _, tool := range toolList() {
+		// Read-only tokens are limited to the read-only tools, and may list
+		// but not toggle tasks with mp_issue_tasks
+		if readOnlyToken.allows(tool.Name, map[string]string{}) != (tool.Annotations.ReadOnlyHint || tool.Name == "mp_issue_tasks") {
+			t.Errorf("%s: readOnlyHint %v disagrees with the read-only token scope", tool.Name, tool.Annotations.ReadOnlyHint)
+		}
+		if tool.Annotations.ReadOnlyHint && tool.Annotations.DestructiveHint {
+			t.Errorf("%s: a read-only tool can't be destructive", tool.Name)
+		}
+	}
+	if !isDestructive("mp_piece_merge") || isDestructive("mp_issue_list") {
+		t.Error("expected only mp_piece_merge among these to be destructive")
+	}
+}
+
+func TestHideDestructive(t *testing.T) {
+	server := &Server{mpPath: "mp", hideDestructive: true}
+
+	resp := server.handleRequest(&Request{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+	for _, tool := range resp.Result.(ToolsListResult).Tools {
+		if tool.Annotations.DestructiveHint {
+			t.Errorf("expected %s to be hidden", tool.Name)
+		}
+	}
+
+	params, _ := json.Marshal(ToolCallParams{Name: "mp_piece_merge", Arguments: json.RawMessage(`{}`)})
+	resp = server.handleRequest(&Request{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: params})
+	if resp.Error == nil || resp.Error.Code != errForbidden {
+		t.Errorf("expected a call to a hidden tool to be refused, got %+v", resp)
+	}
+}
+
 func TestHandleUnknownMethod(t *testing.T) {
 	server := &Server{mpPath: "mp"}
 	req := &Request{
@@ -137,42 +209,78 @@ func TestToolCallInvalidArguments(t *testing.T) {
 	if !result.IsError {
 		t.Error("expected IsError=true for missing required path")
 	}
+	if result.Meta == nil || result.Meta.APIVersion != meta.APIVersion {
+		t.Errorf("expected _meta with api_version %s, got %+v", meta.APIVersion, result.Meta)
+	}
 }
 
-func TestParseIssue(t *testing.T) {
-	tests := []struct {
-		name           string
-		content        string
-		expectedTitle  string
-		expectedStatus string
-	}{
-		{
-			name: "frontmatter with title and status",
-			content: `---
-title: Test Issue
-status: in-progress
----
-
-# Test Issue`,
-			expectedTitle:  "Test Issue",
-			expectedStatus: "in-progress",
-		},
-		{
-			name: "frontmatter without status",
-			content: `---
-title: Another Issue
----
-
-# Another Issue`,
-			expectedTitle:  "Another Issue",
-			expectedStatus: "todo",
-		},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			// Note: parseIssue requires a file path, this is a simplified test
-			// Full integration testing would require temp files
-		})
+func TestReadIssue_ShortID(t *testing.T) {
+	root := t.TempDir()
+	config := `{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}}}`
+	for path, content := range map[string]string{
+		".monkeypuzzle/monkeypuzzle.json": config,
+		"issues/add-login.md":             "---\ntitle: Add login\n---\n",
+	} {
+		_ = os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0755)
+		if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := &Server{mpPath: "mp"}
+
+	// IDs resolve from subdirectories too
+	text, isError := server.executeTool("mp_issue_read", map[string]string{"path": "add-login", "cwd": filepath.Join(root, "issues")})
+	if isError {
+		t.Fatalf("expected success, got: %s", text)
+	}
+	var result struct {
+		ID      string `json:"id"`
+		Path    string `json:"path"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("expected JSON result, got: %s", text)
+	}
+	if result.ID != "add-login" || result.Path != "issues/add-login.md" || !strings.Contains(result.Content, "Add login") {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	text, isError = server.executeTool("mp_issue_tasks", map[string]string{"issue": "add-logn", "cwd": root})
+	if !isError || !strings.Contains(text, "issue not found") {
+		t.Errorf("expected unknown ID to fail before running mp, got: %s", text)
+	}
+}
+
+func TestResources_Context(t *testing.T) {
+	root := t.TempDir()
+	config := `{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}}}`
+	_ = os.MkdirAll(filepath.Join(root, ".monkeypuzzle"), 0755)
+	if err := os.WriteFile(filepath.Join(root, ".monkeypuzzle", "monkeypuzzle.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+	server := &Server{mpPath: "mp"}
+
+	resp := server.handleRequest(&Request{JSONRPC: "2.0", ID: 1, Method: "resources/list"})
+	list, ok := resp.Result.(ResourcesListResult)
+	if !ok || len(list.Resources) != 1 || list.Resources[0].URI != contextURI {
+		t.Fatalf("expected the context resource listed, got %+v", resp)
+	}
+
+	read := func() *Response {
+		params, _ := json.Marshal(ResourceReadParams{URI: contextURI})
+		return server.handleRequest(&Request{JSONRPC: "2.0", ID: 2, Method: "resources/read", Params: params})
+	}
+	if resp := read(); resp.Error == nil || resp.Error.Code != errResourceNotFound {
+		t.Errorf("expected a missing context.md not to be found, got %+v", resp)
+	}
+
+	content := "## Review checklist\n\n- [ ] Tests added\n"
+	if err := os.WriteFile(filepath.Join(root, ".monkeypuzzle", "context.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, ok := read().Result.(ResourceReadResult)
+	if !ok || len(result.Contents) != 1 || result.Contents[0].Text != strings.TrimSpace(content) {
+		t.Errorf("expected context.md as the resource, got %+v", result)
 	}
 }
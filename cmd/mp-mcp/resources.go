@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/conventions"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// contextURI is the resource of the repository conventions, context.md of the
+// repository containing mp-mcp's working directory
+const contextURI = "monkeypuzzle://context"
+
+// errResourceNotFound answers resources/read of an unknown resource, or of
+// context.md in a repository without one
+const errResourceNotFound = -32003
+
+type ResourcesCapability struct{}
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+type ResourcesListResult struct {
+	Resources []Resource `json:"resources"`
+}
+
+type ResourceReadParams struct {
+	URI string `json:"uri"`
+}
+
+type ResourceReadResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// resourceList returns every resource mp-mcp serves
+func resourceList() []Resource {
+	return []Resource{
+		{
+			URI:         contextURI,
+			Name:        conventions.Filename,
+			Description: "Repository conventions from .monkeypuzzle/context.md: architecture notes, coding standards and review checklist",
+			MimeType:    "text/markdown",
+		},
+	}
+}
+
+func (s *Server) handleResourcesList(req *Request) *Response {
+	return successResponse(req.ID, ResourcesListResult{Resources: resourceList()})
+}
+
+func (s *Server) handleResourcesRead(req *Request) *Response {
+	var params ResourceReadParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return errorResponse(req.ID, -32602, "Invalid params", err.Error())
+	}
+	if params.URI != contextURI {
+		return errorResponse(req.ID, errResourceNotFound, "Resource not found", params.URI)
+	}
+
+	cwd, _ := os.Getwd()
+	root, ok := initcmd.FindRepoConfig(issueFS(), cwd)
+	if !ok {
+		return errorResponse(req.ID, errResourceNotFound, "Resource not found",
+			fmt.Sprintf("no monkeypuzzle config found from %s (run mp init first)", cwd))
+	}
+	projectContext, err := conventions.Load(issueFS(), root)
+	if err != nil {
+		return errorResponse(req.ID, -32603, "Internal error", err.Error())
+	}
+	if !projectContext.Defined() {
+		return errorResponse(req.ID, errResourceNotFound, "Resource not found",
+			fmt.Sprintf("%s has no %s (scaffold one with mp init --with-examples)", root, conventions.Filename))
+	}
+	return successResponse(req.ID, ResourceReadResult{Contents: []ResourceContents{
+		{URI: contextURI, MimeType: "text/markdown", Text: projectContext.Content},
+	}})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// errShuttingDown is the JSON-RPC error code of a request received while the
+// server drains after a shutdown signal
+const errShuttingDown = -32000
+
+// responseWriter writes newline-delimited responses, one at a time
+type responseWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (w *responseWriter) write(resp *Response) {
+	data, _ := json.Marshal(resp)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintln(w.out, string(data))
+}
+
+// shuttingDownResponse rejects a request received during drain
+func shuttingDownResponse(id any) *Response {
+	return errorResponse(id, errShuttingDown, "Server shutting down", "the server is finishing in-flight requests and accepts no new ones")
+}
+
+// serveStdio answers newline-delimited requests from in on out, one at a time
+// and in order. It returns once in is closed or a signal arrives, after
+// finishing the request in flight; requests read meanwhile are rejected.
+func (s *Server) serveStdio(in io.Reader, out io.Writer, signals <-chan os.Signal) {
+	w := &responseWriter{out: out}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		if err := scanner.Err(); err != nil {
+			log.Printf("mp-mcp: failed to read stdin: %v", err)
+		}
+		close(lines)
+	}()
+
+	requests := make(chan *Request)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for req := range requests {
+			if resp := s.handleRequest(req); resp != nil {
+				w.write(resp)
+			}
+		}
+	}()
+
+	draining := false
+	drain := func(sig os.Signal) {
+		log.Printf("mp-mcp: received %s, finishing in-flight requests", sig)
+		draining = true
+		close(requests)
+	}
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if !draining {
+					log.Printf("mp-mcp: stdin closed, finishing in-flight requests")
+					close(requests)
+				}
+				<-done
+				log.Printf("mp-mcp: shut down")
+				return
+			}
+			req := parseRequest(w, line)
+			if req == nil {
+				continue
+			}
+			if draining {
+				if req.ID != nil {
+					w.write(shuttingDownResponse(req.ID))
+				}
+				continue
+			}
+			select {
+			case requests <- req:
+			case sig := <-signals:
+				drain(sig)
+				if req.ID != nil {
+					w.write(shuttingDownResponse(req.ID))
+				}
+			}
+		case sig := <-signals:
+			if draining {
+				log.Printf("mp-mcp: received %s again, exiting without waiting", sig)
+				return
+			}
+			drain(sig)
+		case <-done:
+			log.Printf("mp-mcp: shut down")
+			return
+		}
+	}
+}
+
+// parseRequest decodes a line, answering parse errors itself. Blank lines
+// and invalid requests return nil.
+func parseRequest(w *responseWriter, line string) *Request {
+	if line == "" {
+		return nil
+	}
+	var req Request
+	if err := json.Unmarshal([]byte(line), &req); err != nil {
+		w.write(errorResponse(nil, -32700, "Parse error", err.Error()))
+		return nil
+	}
+	return &req
+}
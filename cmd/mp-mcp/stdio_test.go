@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
+)
+
+// syncBuffer is a bytes.Buffer safe to read while serveStdio writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// responses decodes the responses written so far, by ID
+func (b *syncBuffer) responses(t *testing.T) map[float64]Response {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	byID := make(map[float64]Response)
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var resp Response
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+		id, _ := resp.ID.(float64)
+		byID[id] = resp
+	}
+	return byID
+}
+
+func TestServeStdio_AnswersUntilEOF(t *testing.T) {
+	// The last request has no trailing newline, as when stdin closes mid-line
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}` + "\n\n" +
+		`{"jsonrpc":"2.0","method":"initialized"}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	out := &syncBuffer{}
+
+	(&Server{mpPath: fakeMp(t, "mp version dev (api "+meta.APIVersion+")")}).serveStdio(in, out, nil)
+
+	responses := out.responses(t)
+	if len(responses) != 2 || responses[1].Error != nil || responses[2].Error != nil {
+		t.Errorf("expected answers to both requests, got %+v", responses)
+	}
+}
+
+func TestServeStdio_DrainsOnSignal(t *testing.T) {
+	// mp_issue_list runs `<mp> issue list`: with sh as mp, that's the script
+	// ./issue, which marks the call in flight and takes a while
+	dir := t.TempDir()
+	script := "touch started; sleep 1; echo listed\n"
+	if err := os.WriteFile(filepath.Join(dir, "issue"), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inR, inW := io.Pipe()
+	out := &syncBuffer{}
+	signals := make(chan os.Signal)
+	returned := make(chan struct{})
+	go func() {
+		(&Server{mpPath: "sh"}).serveStdio(inR, out, signals)
+		close(returned)
+	}()
+
+	call, _ := json.Marshal(toolCall("mp_issue_list", map[string]string{"cwd": dir}))
+	_, _ = inW.Write(append(call, '\n'))
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(filepath.Join(dir, "started")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("tool call never started")
+		}
+	}
+
+	signals <- syscall.SIGTERM
+	_, _ = inW.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"))
+
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveStdio did not return after draining")
+	}
+
+	responses := out.responses(t)
+	if resp := responses[2]; resp.Error == nil || resp.Error.Code != errShuttingDown {
+		t.Errorf("expected the request during drain to be rejected, got %+v", resp)
+	}
+	data, _ := json.Marshal(responses[1].Result)
+	if !strings.Contains(string(data), "listed") {
+		t.Errorf("expected the in-flight call to finish, got %s", data)
+	}
+}
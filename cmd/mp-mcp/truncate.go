@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// defaultMaxResultBytes is the default --max-result-bytes: roughly 16k tokens
+const defaultMaxResultBytes = 64 * 1024
+
+// maxContinuations is how many truncated results are kept for continuation;
+// older ones expire
+const maxContinuations = 32
+
+// continuationArgDescription describes the continuation argument every tool takes
+const continuationArgDescription = "Continuation from a truncated result's marker: returns the next part of that result instead of calling the tool again"
+
+// truncatedResult is the full text of a result that was cut, kept so the
+// caller can page through it
+type truncatedResult struct {
+	id   int
+	tool string
+	// caller is the name of the token that made the call, empty over stdio
+	caller string
+	text   string
+}
+
+// continuations holds the most recent truncated results
+type continuations struct {
+	mu      sync.Mutex
+	nextID  int
+	results []truncatedResult
+}
+
+// add keeps text and returns its ID, expiring the oldest result when full
+func (c *continuations) add(tool, caller, text string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	c.results = append(c.results, truncatedResult{id: c.nextID, tool: tool, caller: caller, text: text})
+	if len(c.results) > maxContinuations {
+		c.results = c.results[1:]
+	}
+	return c.nextID
+}
+
+func (c *continuations) get(id int) (truncatedResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range c.results {
+		if r.id == id {
+			return r, true
+		}
+	}
+	return truncatedResult{}, false
+}
+
+// page returns text from offset, cut to at most max bytes (no limit when
+// max is 0). A cut part ends with a marker giving the continuation of the rest.
+func (s *Server) page(tool, caller, text string, offset, id int) string {
+	rest := text[offset:]
+	if s.maxResultBytes <= 0 || len(rest) <= s.maxResultBytes {
+		return rest
+	}
+	if id == 0 {
+		id = s.continuations.add(tool, caller, text)
+	}
+
+	end := cutPoint(rest, s.maxResultBytes)
+	next := offset + end
+	return fmt.Sprintf("%s\n[truncated: bytes %d-%d of %d shown; call %s again with continuation=%q for the rest]",
+		rest[:end], offset, next, len(text), tool, formatContinuation(id, next))
+}
+
+// cutPoint returns where to cut text to at most max bytes: after the last
+// newline in the second half of that span, so lines stay whole, or else at
+// the last rune boundary
+func cutPoint(text string, max int) int {
+	if i := strings.LastIndexByte(text[:max], '\n'); i >= max/2 {
+		return i + 1
+	}
+	end := max
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return end
+}
+
+func formatContinuation(id, offset int) string {
+	return fmt.Sprintf("%d:%d", id, offset)
+}
+
+// continueResult returns the part of a truncated result a continuation names
+func (s *Server) continueResult(tool, caller, continuation string) (string, bool) {
+	idText, offsetText, ok := strings.Cut(continuation, ":")
+	id, idErr := strconv.Atoi(idText)
+	offset, offsetErr := strconv.Atoi(offsetText)
+	if !ok || idErr != nil || offsetErr != nil {
+		return fmt.Sprintf("Error: invalid continuation %q", continuation), true
+	}
+
+	r, found := s.continuations.get(id)
+	if !found || r.caller != caller {
+		return fmt.Sprintf("Error: continuation %q has expired: call %s again without it", continuation, tool), true
+	}
+	if r.tool != tool {
+		return fmt.Sprintf("Error: continuation %q belongs to %s, not %s", continuation, r.tool, tool), true
+	}
+	if offset < 0 || offset > len(r.text) {
+		return fmt.Sprintf("Error: continuation %q is past the end of the result", continuation), true
+	}
+	return s.page(tool, caller, r.text, offset, id), false
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var markerRegex = regexp.MustCompile(`\n\[truncated: bytes \d+-\d+ of \d+ shown; call \w+ again with continuation="([0-9:]+)" for the rest\]$`)
+
+func TestPage_CutsWholeLines(t *testing.T) {
+	server := &Server{maxResultBytes: 20}
+	text := "line one\nline two\nline three\n"
+
+	got := server.page("mp_issue_list", "", text, 0, 0)
+	want := "line one\nline two\n\n[truncated: bytes 0-18 of 29 shown; call mp_issue_list again with continuation=\"1:18\" for the rest]"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if rest, isError := server.continueResult("mp_issue_list", "", "1:18"); isError || rest != "line three\n" {
+		t.Errorf("expected the last line, got %q", rest)
+	}
+}
+
+func TestPage_NoLimit(t *testing.T) {
+	text := strings.Repeat("x", 100)
+	if got := (&Server{}).page("mp_issue_list", "", text, 0, 0); got != text {
+		t.Errorf("expected no truncation without a limit, got %q", got)
+	}
+}
+
+func TestCutPoint_RuneBoundary(t *testing.T) {
+	// "é" is two bytes; cutting at 3 would split the second one
+	if got := cutPoint("aéé", 4); got != 3 {
+		t.Errorf("expected cut at 3, got %d", got)
+	}
+}
+
+func TestToolCall_PagesThroughContinuations(t *testing.T) {
+	root := setupRepo(t)
+	body := strings.Repeat("Lots of detail about logging in. ", 200)
+	if err := os.WriteFile(filepath.Join(root, "issues", "add-login.md"), []byte("---\ntitle: Add login\n---\n"+body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{mpPath: "mp", maxResultBytes: 1000}
+	full, _ := server.readIssue(root, "add-login")
+
+	var parts []string
+	args := map[string]string{"path": "add-login", "cwd": root}
+	for range 20 {
+		req := toolCall("mp_issue_read", args)
+		resp := server.handleRequest(&req)
+		text := resp.Result.(ToolCallResult).Content[0].Text
+		m := markerRegex.FindStringSubmatch(text)
+		if m == nil {
+			parts = append(parts, text)
+			break
+		}
+		if len(text)-len(m[0]) > 1000 {
+			t.Fatalf("part of %d bytes exceeds the limit", len(text)-len(m[0]))
+		}
+		parts = append(parts, strings.TrimSuffix(text, m[0]))
+		args["continuation"] = m[1]
+	}
+	if strings.Join(parts, "") != full {
+		t.Error("expected the parts to add up to the full result")
+	}
+
+	req := toolCall("mp_issue_list", map[string]string{"continuation": args["continuation"]})
+	resp := server.handleRequest(&req)
+	if result := resp.Result.(ToolCallResult); !result.IsError || !strings.Contains(result.Content[0].Text, "belongs to mp_issue_read") {
+		t.Errorf("expected a continuation of another tool to be refused, got %+v", result)
+	}
+}
+
+func TestContinueResult_Expired(t *testing.T) {
+	server := &Server{maxResultBytes: 1}
+	for range maxContinuations + 1 {
+		server.page("mp_issue_list", "", "ab", 0, 0)
+	}
+	if text, isError := server.continueResult("mp_issue_list", "", "1:1"); !isError || !strings.Contains(text, "has expired") {
+		t.Errorf("expected the oldest continuation to expire, got %q", text)
+	}
+	if _, isError := server.continueResult("mp_issue_list", "other-token", "2:1"); !isError {
+		t.Error("expected another token's continuation to be refused")
+	}
+}
@@ -0,0 +1,71 @@
+package mp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/alias"
+)
+
+// registerAliases adds a command for each alias in the repository and user
+// config, expanding to its mp command line plus any extra arguments.
+// Aliases that shadow a command or expand to another alias are skipped.
+// The returned function removes the added commands again.
+func registerAliases() (remove func()) {
+	wd, err := getwd()
+	if err != nil {
+		return func() {}
+	}
+	aliases, err := alias.Load(env.FS, wd)
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "Warning: ignoring aliases: %v\n", err)
+		return func() {}
+	}
+
+	var usable []alias.Alias
+	names := map[string]bool{}
+	for _, a := range aliases {
+		if cmd, _, err := rootCmd.Find([]string{a.Name}); err == nil && cmd != rootCmd {
+			fmt.Fprintf(env.Stderr, "Warning: ignoring %s alias %q: it shadows mp %s\n", a.Source, a.Name, cmd.Name())
+			continue
+		}
+		usable = append(usable, a)
+		names[a.Name] = true
+	}
+
+	var added []*cobra.Command
+	for _, a := range usable {
+		if names[a.Args[0]] {
+			fmt.Fprintf(env.Stderr, "Warning: ignoring %s alias %q: aliases cannot expand to other aliases\n", a.Source, a.Name)
+			continue
+		}
+
+		cmd := aliasCommand(a)
+		rootCmd.AddCommand(cmd)
+		added = append(added, cmd)
+	}
+
+	return func() { rootCmd.RemoveCommand(added...) }
+}
+
+func aliasCommand(a alias.Alias) *cobra.Command {
+	return &cobra.Command{
+		Use:   a.Name + " [args...]",
+		Short: fmt.Sprintf("Alias for: mp %s (%s config)", a.Expansion, a.Source),
+		// Flags belong to the expanded command
+		DisableFlagParsing: true,
+		// The expanded command reports its own errors
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expanded := append(append([]string{}, a.Args...), args...)
+			if len(args) == 1 && (args[0] == "--help" || args[0] == "-h") {
+				fmt.Fprintf(env.Stderr, "%s is an alias for: mp %s\n\n", a.Name, strings.Join(a.Args, " "))
+			}
+			rootCmd.SetArgs(expanded)
+			_, err := rootCmd.ExecuteC()
+			return err
+		},
+	}
+}
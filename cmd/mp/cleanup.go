@@ -0,0 +1,94 @@
+package mp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/cleanup"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+var (
+	flagCleanupAll  bool
+	flagCleanupJSON bool
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Run all maintenance tasks",
+	Long: `Run every maintenance task in sequence with a consolidated report:
+
+  1. Remove merged pieces (as mp piece cleanup)
+  2. Prune stale git worktree metadata
+  3. Kill tmux sessions of pieces that no longer exist
+  4. Move done issues to the issues archive/ directory
+  5. Rotate .monkeypuzzle/events.jsonl once it exceeds 1 MiB
+
+A failing step is reported and the remaining steps still run; the command exits
+non-zero if any step failed. With --json, only a single-line JSON report is written
+to stdout, suitable for appending to a log from cron.
+
+Examples:
+  mp cleanup --all
+  mp cleanup --all --json >> ~/mp-cleanup.log`,
+	Args: cobra.NoArgs,
+	RunE: runCleanup,
+}
+
+func init() {
+	cleanupCmd.Flags().BoolVar(&flagCleanupAll, "all", false, "Run all maintenance tasks")
+	cleanupCmd.Flags().BoolVar(&flagCleanupJSON, "json", false, "Write only a single-line JSON report to stdout")
+	cleanupCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Main branch name to check for merged status (default: main)")
+	rootCmd.AddCommand(cleanupCmd)
+}
+
+func runCleanup(cmd *cobra.Command, args []string) error {
+	if !flagCleanupAll {
+		return fmt.Errorf("specify --all to run all maintenance tasks (or use mp piece cleanup for merged pieces only)")
+	}
+
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	if flagCleanupJSON {
+		deps.Output = adapters.NewTextOutput(io.Discard)
+	}
+
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	report, err := cleanup.NewHandler(deps).RunAll(status.RepoRoot, cleanup.Options{MainBranch: flagMainBranch})
+	if err != nil {
+		return err
+	}
+
+	// Output JSON to stdout (one line with --json, for log files)
+	var jsonData []byte
+	if flagCleanupJSON {
+		jsonData, err = json.Marshal(report)
+	} else {
+		jsonData, err = json.MarshalIndent(report, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
+
+	if !report.OK {
+		return fmt.Errorf("cleanup had failing steps")
+	}
+	return nil
+}
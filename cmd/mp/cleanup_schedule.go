@@ -0,0 +1,99 @@
+package mp
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/cleanup"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+var flagScheduleAt string
+
+var cleanupScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run mp cleanup --all daily in the background",
+	Long: `Manage a user-level job that runs mp cleanup --all --json daily for this repository.
+
+On Linux this is a systemd user timer in ~/.config/systemd/user; on macOS a launchd
+agent in ~/Library/LaunchAgents. Each run appends its JSON report to a log file under
+$XDG_DATA_HOME/monkeypuzzle/logs/.
+
+Examples:
+  mp cleanup schedule install
+  mp cleanup schedule install --at 22:15
+  mp cleanup schedule status
+  mp cleanup schedule uninstall`,
+}
+
+var cleanupScheduleInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install and load the scheduled cleanup job",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mpPath, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate mp binary: %w", err)
+		}
+		return runCleanupSchedule(func(h *cleanup.Handler, repoRoot string) (cleanup.Schedule, error) {
+			return h.InstallSchedule(repoRoot, cleanup.ScheduleOptions{MpPath: mpPath, Time: flagScheduleAt})
+		})
+	},
+}
+
+var cleanupScheduleStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the scheduled cleanup job is installed and loaded",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCleanupSchedule(func(h *cleanup.Handler, repoRoot string) (cleanup.Schedule, error) {
+			return h.ScheduleStatus(repoRoot, cleanup.ScheduleOptions{})
+		})
+	},
+}
+
+var cleanupScheduleUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Unload and remove the scheduled cleanup job",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCleanupSchedule(func(h *cleanup.Handler, repoRoot string) (cleanup.Schedule, error) {
+			return h.UninstallSchedule(repoRoot, cleanup.ScheduleOptions{})
+		})
+	},
+}
+
+func init() {
+	cleanupScheduleInstallCmd.Flags().StringVar(&flagScheduleAt, "at", cleanup.DefaultScheduleTime, "Daily run time (HH:MM, local time)")
+	cleanupScheduleCmd.AddCommand(cleanupScheduleInstallCmd)
+	cleanupScheduleCmd.AddCommand(cleanupScheduleStatusCmd)
+	cleanupScheduleCmd.AddCommand(cleanupScheduleUninstallCmd)
+	cleanupCmd.AddCommand(cleanupScheduleCmd)
+}
+
+// runCleanupSchedule resolves the repository root and prints the resulting schedule as JSON
+func runCleanupSchedule(fn func(h *cleanup.Handler, repoRoot string) (cleanup.Schedule, error)) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	sched, err := fn(cleanup.NewHandler(deps), status.RepoRoot)
+	if err != nil {
+		return err
+	}
+	return printJSON(sched)
+}
@@ -0,0 +1,263 @@
+package mp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/clitest"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
+)
+
+const testConfig = `{"version":"1","project":{"name":"repo"},"issues":{"provider":"markdown","config":{"directory":"issues"}},"pr":{"provider":"github"}}`
+
+// newRepo returns a fixture of an initialized repository with two issues
+func newRepo(t *testing.T) *clitest.Fixture {
+	f := clitest.New(t)
+	f.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", testConfig)
+	f.WriteFile("/repo/issues/add-login.md", "---\ntitle: Add login\nstatus: todo\n---\n\n- [x] Form\n- [ ] Session handling\n")
+	f.WriteFile("/repo/issues/fix-crash.md", "---\ntitle: Fix crash\nstatus: in-progress\n---\n\nCrashes on start.\n")
+	return f
+}
+
+// inMainRepo mocks git for a working directory in the main repository
+func inMainRepo(f *clitest.Fixture) {
+	f.Exec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git\n"), nil)
+	f.Exec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+}
+
+// inPiece mocks git for a working directory in piece p1
+func inPiece(f *clitest.Fixture, branch string) {
+	f.WorkDir = "/test-data/monkeypuzzle/pieces/p1"
+	f.Exec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	f.Exec.AddResponse("git", []string{"rev-parse", "--git-common-dir"}, []byte("/repo/.git\n"), nil)
+	f.Exec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nHEAD aaa111\nbranch refs/heads/main\n\n"), nil)
+	f.Exec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(f.WorkDir+"\n"), nil)
+	f.Exec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte(branch+"\n"), nil)
+}
+
+func TestCLI_InitSchema(t *testing.T) {
+	f := clitest.New(t)
+	f.Run("", "init", "--schema").Golden(t, "init_schema")
+}
+
+func TestCLI_IssueCreateSchema(t *testing.T) {
+	f := clitest.New(t)
+	f.Run("", "issue", "create", "--schema").Golden(t, "issue_create_schema")
+}
+
+func TestCLI_IssueCreate(t *testing.T) {
+	f := newRepo(t)
+	f.Run(`{"title":"Dark mode","description":"Add a dark theme."}`, "issue", "create").Golden(t, "issue_create_stdin")
+
+	content, err := f.FS.ReadFile("/repo/issues/dark-mode.md")
+	if err != nil {
+		t.Fatalf("expected issue file to be created: %v", err)
+	}
+	clitest.Golden(t, "issue_create_file", string(content))
+}
+
+func TestCLI_IssueList(t *testing.T) {
+	f := newRepo(t)
+	f.Run("", "issue", "list").Golden(t, "issue_list")
+	f.Run("", "issue", "list", "--status", "todo").Golden(t, "issue_list_todo")
+}
+
+func TestCLI_IssueListOutput(t *testing.T) {
+	f := newRepo(t)
+	f.Run("", "issue", "list", "--output", "yaml").Golden(t, "issue_list_yaml")
+	f.Run("", "issue", "list", "-o", "table").Golden(t, "issue_list_table")
+	f.Run("", "issue", "list", "-o", "template={{.ID}}: {{.Title}}").Golden(t, "issue_list_template")
+
+	res := f.Run("", "issue", "list", "-o", "xml")
+	if res.Err == nil || res.Stdout != "" {
+		t.Errorf("expected an error and no output for an invalid format, got %q (%v)", res.Stdout, res.Err)
+	}
+}
+
+func TestCLI_IssueTasks(t *testing.T) {
+	f := newRepo(t)
+	f.Run("", "issue", "tasks", "add-login").Golden(t, "issue_tasks")
+}
+
+func TestCLI_PieceStatus(t *testing.T) {
+	f := newRepo(t)
+	inMainRepo(f)
+	f.Run("", "piece").Golden(t, "piece_status_main")
+}
+
+func TestCLI_JSONErrors(t *testing.T) {
+	f := newRepo(t)
+	inPiece(f, "HEAD")
+
+	result := f.Run("", "piece", "update", "--json-errors")
+	if result.Err == nil {
+		t.Fatal("expected error for detached HEAD")
+	}
+	result.Golden(t, "error_detached_head")
+}
+
+func TestCLI_RecordReplay(t *testing.T) {
+	f := newRepo(t)
+	inMainRepo(f)
+	t.Setenv("MP_RECORD", "/trace.json")
+	recorded := f.Run("", "piece")
+	data, err := f.FS.ReadFile("/trace.json")
+	if err != nil {
+		t.Fatalf("expected a trace to be recorded: %v", err)
+	}
+	if !strings.Contains(string(data), `"command": "mp piece"`) || !strings.Contains(string(data), `"rev-parse"`) {
+		t.Errorf("expected the trace to record the git commands, got %s", data)
+	}
+
+	// The replayed run has no files or mocked commands: everything comes from
+	// the trace, relocated to the directory it replays in
+	replay := clitest.New(t)
+	replay.WriteFile("/trace.json", string(data))
+	replay.WorkDir = "/elsewhere"
+	t.Setenv("MP_REPLAY", "/trace.json")
+	replayed := replay.Run("", "piece")
+	want := strings.ReplaceAll(recorded.Stdout, "/repo", "/elsewhere")
+	if replayed.Err != nil || replayed.Stdout != want {
+		t.Errorf("expected the replay to print %q, got %q (%v)", want, replayed.Stdout, replayed.Err)
+	}
+	if replay.Exec.WasCalled("git", "rev-parse", "--git-dir") {
+		t.Error("expected no command to run during a replay")
+	}
+}
+
+func TestCLI_Messages(t *testing.T) {
+	f := newRepo(t)
+	inPiece(f, "HEAD")
+	f.WriteFile("/messages/de.json", `{"how_to_fix": "So geht's:", "detached_head.hint": "Branch in %s auschecken."}`)
+	t.Setenv("MP_MESSAGES", "/messages/de.json")
+
+	result := f.Run("", "piece", "update", "--json-errors")
+	if !strings.Contains(result.Stderr, "So geht's:\n  Branch in /test-data/monkeypuzzle/pieces/p1 auschecken.") {
+		t.Errorf("expected translated hint, got:\n%s", result.Stderr)
+	}
+	// Codes stay stable across languages
+	if !strings.Contains(result.Stdout, `"code": "detached_head"`) {
+		t.Errorf("expected detached_head code, got:\n%s", result.Stdout)
+	}
+}
+
+func TestCLI_Aliases(t *testing.T) {
+	f := clitest.New(t)
+	f.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json",
+		`{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}},"aliases":{"todo":"issue list --status","issue":"issue list","again":"todo"}}`)
+	f.WriteFile("/repo/issues/add-login.md", "---\ntitle: Add login\nstatus: todo\n---\n")
+	f.WriteFile("/repo/issues/fix-crash.md", "---\ntitle: Fix crash\nstatus: in-progress\n---\n")
+
+	f.Run("", "todo", "todo").Golden(t, "alias_expand")
+}
+
+func TestCLI_Plugin(t *testing.T) {
+	// Plugins are discovered through the fixture FS but run for real
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"hello $*\"\necho \"bin: ${MP_BIN:+set}\" >&2\nexit 3\n"
+	if err := os.WriteFile(filepath.Join(dir, "mp-hello"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	f := clitest.New(t)
+	f.WorkDir = dir
+	if err := f.FS.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.FS.WriteFile(filepath.Join(dir, "mp-hello"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result := f.Run("", "hello", "--name", "world")
+	if result.Stdout != "hello --name world\n" || result.Stderr != "bin: set\n" {
+		t.Errorf("unexpected plugin output:\n%s", result)
+	}
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(result.Err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("expected plugin exit code 3, got %v", result.Err)
+	}
+
+	if help := f.Run("", "--help"); !strings.Contains(help.Stdout, "Plugin: "+filepath.Join(dir, "mp-hello")) {
+		t.Errorf("expected plugin in help, got:\n%s", help.Stdout)
+	}
+}
+
+func TestCLI_MetaCommands(t *testing.T) {
+	f := clitest.New(t)
+	result := f.Run("", "meta", "commands")
+	if result.Err != nil {
+		t.Fatalf("expected no error, got: %v", result.Err)
+	}
+
+	var manifest meta.Manifest
+	if err := json.Unmarshal([]byte(result.Stdout), &manifest); err != nil {
+		t.Fatalf("expected JSON manifest, got: %v\n%s", err, result.Stdout)
+	}
+	if manifest.APIVersion != meta.APIVersion || manifest.MPVersion == "" {
+		t.Errorf("expected api_version %s and mp_version, got %+v", meta.APIVersion, manifest.APIInfo)
+	}
+	commands := map[string]meta.Command{}
+	for _, c := range manifest.Commands {
+		commands[c.Path] = c
+	}
+
+	split, ok := commands["mp issue split"]
+	if !ok {
+		t.Fatalf("expected mp issue split in manifest, got %d commands", len(manifest.Commands))
+	}
+	if split.Args != "<issue>" || split.Output["type"] != "object" {
+		t.Errorf("unexpected issue split entry: %+v", split)
+	}
+	if create := commands["mp issue create"]; create.Input == nil {
+		t.Error("expected mp issue create to describe its stdin input")
+	}
+
+	var flags []string
+	for _, fl := range commands["mp piece delete"].Flags {
+		flags = append(flags, fl.Name)
+	}
+	if strings.Join(flags, ",") != "force,allow-nested-repo,json-errors" {
+		t.Errorf("expected force and inherited allow-nested-repo and json-errors flags, got %v", flags)
+	}
+}
+
+// TestCLI_OutputContract runs every command that writes JSON, without
+// arguments, and checks its stdout is nothing but JSON, whether it succeeds
+// or fails: human-readable text belongs on stderr.
+func TestCLI_OutputContract(t *testing.T) {
+	manifestRun := clitest.New(t).Run("", "meta", "commands")
+	var manifest meta.Manifest
+	if err := json.Unmarshal([]byte(manifestRun.Stdout), &manifest); err != nil {
+		t.Fatalf("expected JSON manifest, got: %v", err)
+	}
+
+	// Commands running until interrupted
+	skip := map[string]bool{"mp watch": true}
+	for _, c := range manifest.Commands {
+		if !c.Runnable || c.Output == nil || skip[c.Path] {
+			continue
+		}
+		t.Run(c.Path, func(t *testing.T) {
+			f := newRepo(t)
+			inMainRepo(f)
+			args := append(strings.Fields(strings.TrimPrefix(c.Path, "mp")), "--json-errors")
+			result := f.Run("", args...)
+
+			dec := json.NewDecoder(strings.NewReader(result.Stdout))
+			for {
+				var v any
+				if err := dec.Decode(&v); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("stdout is not JSON: %v\n%s", err, result)
+				}
+			}
+		})
+	}
+}
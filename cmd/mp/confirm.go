@@ -0,0 +1,42 @@
+package mp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// flagConfirmYes skips confirmation of history-modifying piece commands
+var flagConfirmYes bool
+
+// confirm asks a yes/no question on stderr, defaulting to no
+func confirm(question string) (bool, error) {
+	fmt.Fprintf(env.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(env.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// confirmPlan shows a history-modifying operation and asks to proceed when
+// run on a terminal without --yes. Other callers proceed and get the plan in
+// the command's JSON output instead.
+func confirmPlan(plan *piececmd.Plan) (bool, error) {
+	if flagConfirmYes || !isTerminal() {
+		return true, nil
+	}
+	fmt.Fprint(env.Stderr, plan.Summary())
+	ok, err := confirm("Proceed?")
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		fmt.Fprintln(env.Stderr, "Cancelled.")
+	}
+	return ok, nil
+}
@@ -0,0 +1,61 @@
+package mp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/doctor"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that monkeypuzzle is set up correctly",
+	Long: `Diagnose the monkeypuzzle setup of the current repository.
+Verifies the config is readable and that each configured provider is usable
+(markdown: issues directory writable; github: gh authenticated).
+
+Exits non-zero if any check fails.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	report, err := doctor.NewHandler(deps).Run(status.RepoRoot)
+	if err != nil {
+		return err
+	}
+
+	// Output JSON to stdout
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
+
+	if !report.OK {
+		return fmt.Errorf("doctor found problems")
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package mp
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/childenv"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/messages"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/trace"
+)
+
+// Env is what commands run against: the filesystem, command runner, standard
+// streams, and working directory. Execute uses the OS; tests pass fixtures to Run.
+type Env struct {
+	FS     core.FS
+	Exec   core.Exec
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// WorkDir replaces the process working directory when set
+	WorkDir string
+}
+
+// osEnv returns the environment of the running process
+func osEnv() Env {
+	return Env{
+		FS:     adapters.NewOSFS(""),
+		Exec:   adapters.NewOSExec(),
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// env is the environment commands currently run against
+var env = osEnv()
+
+// Run executes the mp command line args against e instead of the OS.
+// Flags are reset to their defaults first, so successive runs are independent.
+// Run swaps package state and must not be called concurrently.
+func Run(e Env, args []string) error {
+	saved := env
+	defer func() { env = saved }()
+	env = e
+	r := redactStreams()
+	if err := traceExec(args, r); err != nil {
+		fmt.Fprintln(env.Stderr, "Error:", err)
+		reportError(err)
+		return err
+	}
+	injectEnv()
+	staging := stageIssueEdits(args)
+	defer messages.Use(messages.Use(loadMessages()))
+
+	resetFlags(rootCmd)
+	rootCmd.SetIn(env.Stdin)
+	rootCmd.SetOut(env.Stdout)
+	rootCmd.SetErr(env.Stderr)
+	rootCmd.SetArgs(args)
+	defer registerAliases()()
+	defer registerPlugins()()
+	defer func() {
+		rootCmd.SetIn(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	}()
+
+	cmd, err := rootCmd.ExecuteC()
+	reportStaged(staging)
+	if err != nil {
+		reportUsage(cmd)
+		reportError(err)
+	}
+	return err
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default value
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// newDeps builds handler dependencies from the environment,
+// with human-readable messages going to stderr
+func newDeps() core.Deps {
+	return core.Deps{
+		FS:     env.FS,
+		Output: adapters.NewTextOutput(env.Stderr),
+		Exec:   env.Exec,
+	}
+}
+
+// redactStreams masks secrets in everything commands write to stdout and
+// stderr, using the known token formats plus the repository's redact.patterns,
+// and returns the redactor it uses
+func redactStreams() *redact.Redactor {
+	r := redact.Default()
+	if wd, err := getwd(); err == nil {
+		if root, ok := initcmd.FindRepoConfig(env.FS, wd); ok {
+			configured, err := redact.Load(env.FS, root)
+			if err != nil {
+				fmt.Fprintf(env.Stderr, "Warning: ignoring redact.patterns: %v\n", err)
+			} else {
+				r = configured
+			}
+		}
+	}
+	env.Stdout = redact.NewWriter(env.Stdout, r)
+	env.Stderr = redact.NewWriter(env.Stderr, r)
+	return r
+}
+
+// traceExec records the commands mp runs and the files it reads to the trace
+// file named by MP_RECORD, or replays them from the one named by MP_REPLAY,
+// with the working directory as the repository root. The variables are unset
+// so that mp processes started by this one keep the trace alone.
+func traceExec(args []string, r *redact.Redactor) error {
+	record, replay := os.Getenv(trace.RecordEnv), os.Getenv(trace.ReplayEnv)
+	os.Unsetenv(trace.RecordEnv)
+	os.Unsetenv(trace.ReplayEnv)
+	if record == "" && replay == "" {
+		return nil
+	}
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if replay != "" {
+		data, err := env.FS.ReadFile(replay)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", trace.ReplayEnv, err)
+		}
+		t, err := trace.Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", trace.ReplayEnv, replay, err)
+		}
+		env.WorkDir = t.Relocate(trace.LocalLocations(wd))
+		env.FS = t.FS()
+		env.Exec = trace.Replay(t)
+		return nil
+	}
+	root, ok := initcmd.FindRepoConfig(env.FS, wd)
+	if !ok {
+		root = wd
+	}
+	rec := trace.Record(env.Exec, env.FS, record, "mp "+strings.Join(args, " "), wd, trace.LocalLocations(root), r)
+	env.Exec = rec
+	env.FS = rec.FS(env.FS)
+	return nil
+}
+
+// injectEnv adds the variables configured under env in the user and
+// repository configs to every command mp runs
+func injectEnv() {
+	root := ""
+	if wd, err := getwd(); err == nil {
+		root, _ = initcmd.FindRepoConfig(env.FS, wd)
+	}
+	vars, err := childenv.Load(env.FS, root)
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "Warning: ignoring env config: %v\n", err)
+		return
+	}
+	env.Exec = childenv.Wrap(env.Exec, vars)
+}
+
+// stageIssueEdits stages edits to issue files for approval instead of
+// applying them when mp runs on behalf of an agent (see issue.StageEnv)
+func stageIssueEdits(args []string) *issue.StagingFS {
+	if os.Getenv(issue.StageEnv) == "" {
+		return nil
+	}
+	staging := issue.NewStagingFS(env.FS, "mp "+strings.Join(args, " "))
+	env.FS = staging
+	return staging
+}
+
+// reportStaged tells the agent which issue edits wait for approval
+func reportStaged(staging *issue.StagingFS) {
+	if staging != nil && len(staging.Staged()) > 0 {
+		fmt.Fprintf(env.Stderr, "Staged changes to %s for approval; they apply once a human runs mp issue approve\n",
+			strings.Join(staging.Staged(), ", "))
+	}
+}
+
+// loadMessages returns the message catalog of the user's language: the file
+// named by MP_MESSAGES, or messages/<lang>.json next to the user config.
+// Without a translation, or with an invalid one, messages stay English.
+func loadMessages() messages.Catalog {
+	path := os.Getenv("MP_MESSAGES")
+	if path == "" {
+		lang := messages.Lang(os.Getenv)
+		if lang == "en" {
+			return messages.English
+		}
+		configPath, err := initcmd.UserConfigPath()
+		if err != nil {
+			return messages.English
+		}
+		path = filepath.Join(filepath.Dir(configPath), "messages", lang+".json")
+		if _, err := env.FS.Stat(path); err != nil {
+			return messages.English
+		}
+	}
+
+	data, err := env.FS.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "Warning: ignoring messages: %v\n", err)
+		return messages.English
+	}
+	catalog, err := messages.Parse(data)
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "Warning: ignoring messages %s: %v\n", path, err)
+		return messages.English
+	}
+	return catalog
+}
+
+// getwd returns the working directory commands operate in
+func getwd() (string, error) {
+	if env.WorkDir != "" {
+		return env.WorkDir, nil
+	}
+	return os.Getwd()
+}
@@ -0,0 +1,74 @@
+package mp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Inspect the events log",
+	Long:  `Commands for the .monkeypuzzle/events.jsonl activity log.`,
+}
+
+var eventsVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the events log hash chain for tampering",
+	Long: `Verifies the hash chain of .monkeypuzzle/events.jsonl and its rotated copy.
+Entries are chained when events.hash_chain is enabled in the config: each entry
+records the hash of the previous one, so modified, removed, reordered, or
+inserted entries are detected.
+
+Prints a JSON report to stdout. Record its "head" hash elsewhere to also detect
+entries removed from the end of the log later.
+
+Exits non-zero if verification fails.`,
+	Args: cobra.NoArgs,
+	RunE: runEventsVerify,
+}
+
+func init() {
+	eventsCmd.AddCommand(eventsVerifyCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsVerify(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	result, err := events.Verify(deps.FS, status.RepoRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range result.Problems {
+		fmt.Fprintf(env.Stderr, "%s:%d: %s\n", p.File, p.Line, p.Reason)
+	}
+	if result.Entries > 0 && result.Chained == 0 {
+		fmt.Fprintf(env.Stderr, "No chained entries (set events.hash_chain to true in the config)\n")
+	}
+
+	if err := printJSON(result); err != nil {
+		return err
+	}
+	if !result.Valid {
+		return fmt.Errorf("events log failed verification: %d problem(s)", len(result.Problems))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package mp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/cleanup"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+var (
+	flagGCMinFree string
+	flagGCRemove  bool
+	flagGCRepack  bool
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Check object sharing and free disk space taken by pieces",
+	Long: `Check that every piece shares the repository's git object store and free
+disk space taken by pieces.
+
+Worktrees share the objects of the main repository, so a piece costs only its
+checkout. A piece that is a clone or copy with its own .git directory, a
+worktree of another repository, or a worktree whose git dir holds objects of
+its own does not; these are listed in objects with the space they duplicate,
+and warned about.
+
+--min-free removes merged pieces, oldest first, until the filesystem holding
+the pieces directory has that much available. Pieces are ordered by their last
+event in the events log. Without --remove, the pieces that would be removed
+are only listed. Removal is as mp piece cleanup: the worktree and tmux session
+go, and the linked issue is marked done. Sizes are measured with du and cached
+for 10 minutes (see mp piece list --du), and take K, M, G or T suffixes
+(powers of 1024).
+
+--repack runs git gc in the repository's common git dir, repacking the objects
+shared by every piece.
+
+Examples:
+  mp gc
+  mp gc --min-free 10G
+  mp gc --min-free 10G --remove
+  mp gc --repack`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	gcCmd.Flags().StringVar(&flagGCMinFree, "min-free", "", "Free space to reach, e.g. 10G")
+	gcCmd.Flags().BoolVar(&flagGCRemove, "remove", false, "Remove the chosen pieces instead of listing them")
+	gcCmd.Flags().BoolVar(&flagGCRepack, "repack", false, "Run git gc on the shared object store")
+	gcCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Main branch name to check for merged status (default: main)")
+	rootCmd.AddCommand(gcCmd)
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	var minFree int64
+	if flagGCMinFree != "" {
+		var err error
+		if minFree, err = piececmd.ParseSize(flagGCMinFree); err != nil {
+			return err
+		}
+	}
+	if flagGCRemove && minFree == 0 {
+		return fmt.Errorf("--remove needs --min-free, e.g. mp gc --min-free 10G --remove")
+	}
+
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	report, err := cleanup.NewHandler(deps).GC(status.RepoRoot, cleanup.GCOptions{
+		MainBranch: flagMainBranch,
+		MinFree:    minFree,
+		Remove:     flagGCRemove,
+		Repack:     flagGCRepack,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
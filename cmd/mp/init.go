@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
-	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/conventions"
 	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
 	initTUI "github.com/jewell-lgtm/monkeypuzzle/internal/tui/init"
 )
 
@@ -22,6 +25,9 @@ var (
 	flagPRProvider    string
 	flagYes           bool
 	flagSchema        bool
+	flagInitGit       bool
+	flagDefaultBranch string
+	flagInitExamples  bool
 )
 
 var initCmd = &cobra.Command{
@@ -38,7 +44,9 @@ Modes:
 Examples:
   mp init                                    # Interactive wizard
   mp init --schema | jq '.name = "foo"' | mp init  # Pipe JSON
-  mp init --name foo --issue-provider markdown --pr-provider github`,
+  mp init --name foo --issue-provider markdown --pr-provider github
+  mp init --git                              # Also git init and commit the scaffolding
+  mp init --with-examples                    # Also scaffold .monkeypuzzle/context.md`,
 	RunE: runInit,
 }
 
@@ -49,10 +57,13 @@ func init() {
 	initCmd.Flags().StringVar(&flagPRProvider, "pr-provider", "", "PR provider (github)")
 	initCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Overwrite existing config without prompting")
 	initCmd.Flags().BoolVar(&flagSchema, "schema", false, "Output JSON schema with defaults and exit")
+	initCmd.Flags().BoolVar(&flagInitGit, "git", false, "Initialize a git repository and commit the scaffolding")
+	initCmd.Flags().StringVar(&flagDefaultBranch, "default-branch", initcmd.DefaultBranch, "Default branch name for --git")
+	initCmd.Flags().BoolVar(&flagInitExamples, "with-examples", false, "Scaffold example files to fill in, such as the conventions file context.md")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
@@ -63,16 +74,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(schema))
+		fmt.Fprintln(env.Stdout, string(schema))
 		return nil
 	}
 
 	// Create dependencies
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := initcmd.NewHandler(deps)
 
 	// Check for existing config
@@ -80,16 +87,30 @@ func runInit(cmd *cobra.Command, args []string) error {
 		if !isTerminal() {
 			return fmt.Errorf("config already exists, use --yes to overwrite")
 		}
-		fmt.Print("Config already exists. Overwrite? [y/N] ")
-		reader := bufio.NewReader(os.Stdin)
-		answer, err := reader.ReadString('\n')
+		ok, err := confirm("Config already exists. Overwrite?")
 		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(env.Stderr, "Cancelled.")
+			return nil
+		}
+	}
+
+	// Outside a repository, offer to create one rather than leave piece commands to fail
+	initGit := flagInitGit
+	if !initGit && isTerminal() && handler.RepoRoot(wd) == "" {
+		fmt.Fprint(env.Stderr, "Not a git repository. Run git init here? [Y/n] ")
+		reader := bufio.NewReader(env.Stdin)
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
 			return fmt.Errorf("failed to read input: %w", err)
 		}
+		// End of input declines, leaving the warning from RecordRepoRoot
 		answer = strings.TrimSpace(strings.ToLower(answer))
-		if answer != "y" && answer != "yes" {
-			fmt.Println("Cancelled.")
-			return nil
+		initGit = err == nil && (answer == "" || answer == "y" || answer == "yes")
+		if err == io.EOF {
+			fmt.Fprintln(env.Stderr)
 		}
 	}
 
@@ -99,7 +120,51 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return handler.Run(input)
+	if err := handler.Run(input); err != nil {
+		return err
+	}
+	if flagInitExamples {
+		if err := scaffoldExamples(deps, wd); err != nil {
+			return err
+		}
+	}
+
+	recordRoot := true
+	if initGit {
+		result, err := handler.InitGit(wd, flagDefaultBranch)
+		if err != nil {
+			return err
+		}
+		recordRoot = !result.Initialized
+	}
+	if recordRoot {
+		if _, err := handler.RecordRepoRoot(wd); err != nil {
+			return err
+		}
+	}
+
+	// Surface unusable providers immediately (non-fatal)
+	if cfg, err := piececmd.ReadConfig(wd, deps.FS); err == nil {
+		handler.CheckProviders(wd, *cfg)
+	}
+
+	return nil
+}
+
+// scaffoldExamples writes the example files of --with-examples, keeping
+// files that exist
+func scaffoldExamples(deps core.Deps, workDir string) error {
+	path := filepath.Join(initcmd.DirName, conventions.Filename)
+	written, err := conventions.Scaffold(deps.FS, workDir)
+	if err != nil {
+		return err
+	}
+	if !written {
+		deps.Output.Write(core.Message{Type: core.MsgInfo, Content: "Kept existing " + path})
+		return nil
+	}
+	deps.Output.Write(core.Message{Type: core.MsgSuccess, Content: "Created " + path})
+	return nil
 }
 
 func getInput(workDir string) (initcmd.Input, error) {
@@ -118,7 +183,7 @@ func getInput(workDir string) (initcmd.Input, error) {
 		}
 
 	case hasStdin:
-		data, err := io.ReadAll(os.Stdin)
+		data, err := io.ReadAll(env.Stdin)
 		if err != nil {
 			return initcmd.Input{}, fmt.Errorf("failed to read stdin: %w", err)
 		}
@@ -149,7 +214,7 @@ func getInput(workDir string) (initcmd.Input, error) {
 }
 
 func runInteractiveMode(workDir string) (initcmd.Input, error) {
-	p := tea.NewProgram(initTUI.New())
+	p := tea.NewProgram(initTUI.New(), tea.WithOutput(redact.Unwrap(env.Stderr)))
 	m, err := p.Run()
 	if err != nil {
 		return initcmd.Input{}, err
@@ -186,7 +251,11 @@ func runInteractiveMode(workDir string) (initcmd.Input, error) {
 }
 
 func isTerminal() bool {
-	fi, err := os.Stdin.Stat()
+	f, ok := env.Stdin.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
 	if err != nil {
 		return false
 	}
@@ -194,7 +263,12 @@ func isTerminal() bool {
 }
 
 func hasStdinData() bool {
-	fi, err := os.Stdin.Stat()
+	f, ok := env.Stdin.(*os.File)
+	if !ok {
+		// Readers other than files are fixtures provided by tests
+		return env.Stdin != nil
+	}
+	fi, err := f.Stat()
 	if err != nil {
 		return false
 	}
@@ -1,23 +1,49 @@
 package mp
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
-	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
-	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/owners"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/render"
 	issueTUI "github.com/jewell-lgtm/monkeypuzzle/internal/tui/issue"
+	splitTUI "github.com/jewell-lgtm/monkeypuzzle/internal/tui/split"
 )
 
 var (
 	flagIssueTitle       string
 	flagIssueDescription string
 	flagIssueSchema      bool
+	flagIssueReindex     bool
+	flagIssueStatus      string
+	flagIssueTeam        string
+	flagIssueOwner       string
+	flagIssueMine        bool
+	flagIssueDir         string
+	flagIssueSplitTasks  string
+	flagIssueLintFix     bool
+	flagIssueLintLinks   bool
+	flagIssueFormat      string
+	flagIssueFilters     []string
+	flagIssueFields      []string
+	flagIssueColumns     []string
+
+	flagIssueDupesThreshold   float64
+	flagIssueDupesIncludeDone bool
+	flagIssueDupesMerge       bool
+
+	flagIssuePendingAll   bool
+	flagIssueApproveForce bool
 )
 
 var issueCmd = &cobra.Command{
@@ -27,8 +53,9 @@ var issueCmd = &cobra.Command{
 }
 
 var issueCreateCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create a new issue",
+	Use:     "create",
+	Aliases: []string{"new"},
+	Short:   "Create a new issue",
 	Long: `Create a new markdown issue file.
 
 Modes:
@@ -40,15 +67,322 @@ Modes:
 Examples:
   mp issue create                              # Interactive wizard
   mp issue create --title "Add feature X"     # Direct mode
-  mp issue create --schema | jq '.title = "foo"' | mp issue create  # Pipe JSON`,
+  mp issue create --schema | jq '.title = "foo"' | mp issue create  # Pipe JSON
+  mp issue create --dir backend --title "Fix API"  # In the backend issues directory
+  mp issue create --title "Fix API" --field priority=high --field components=api,db
+
+Fields defined in issues.fields get their defaults and are validated; a
+required field without a default must be given with --field.`,
 	RunE: runIssueCreate,
 }
 
+var issueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List issues",
+	Long: `List issues in the issues directory as JSON, with task list completion
+for issues that have one.
+
+Examples:
+  mp issue list                  # All issues
+  mp issue list --status todo    # Only todo issues
+  mp issue list --team backend   # Only issues in the backend issues directory
+  mp issue list --mine           # Only issues you own (.monkeypuzzle/owners)
+  mp issue list --reindex        # Re-parse every issue, rebuilding the index
+  mp issue list --filter 'status=todo AND label~infra AND created<30d'
+  mp issue list --columns id,status,priority   # Table with a custom field
+
+Filter expressions compare the fields of mp issue export (id, status,
+labels, created, age_days, any frontmatter field, ...) with values:
+= != ~ (contains) !~ < <= > >=, joined by AND and OR. A duration (30d, 2w,
+12h) compares a time's age. Repeated --filter flags must all match.
+
+--columns prints a table instead of JSON, with a column per listed field
+(id, title, status, labels, tasks.percent, ...) or frontmatter field.
+
+Parsed issues are cached in .monkeypuzzle/issues.index.json and re-parsed
+when their file changes, so listing stays fast with many issues.`,
+	Args: cobra.NoArgs,
+	RunE: runIssueList,
+}
+
+var issueExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export issues as CSV or JSON for reporting",
+	Long: `Write every issue to stdout with all its frontmatter fields and metrics
+from git history: when the issue was created, started (first in-progress)
+and completed (last done), its age in days and its cycle time (started to
+completed) in days.
+
+CSV has a header row; frontmatter fields beyond the standard ones get a
+column each. List values (labels, owners) are joined with ";".
+
+--filter keeps issues matching a filter expression (see mp issue list).
+
+Examples:
+  mp issue export --format csv > backlog.csv
+  mp issue export --filter 'status=done AND cycle_time_days>5'`,
+	Args: cobra.NoArgs,
+	RunE: runIssueExport,
+}
+
+var issueTasksCmd = &cobra.Command{
+	Use:   "tasks <issue>",
+	Short: "List an issue's task list",
+	Long: `List the GitHub-style task list items ("- [ ]" / "- [x]") in an issue body.
+
+The issue is given by path or by file name in the issues directory.
+
+Examples:
+  mp issue tasks add-feature-x
+  mp issue tasks check add-feature-x 2    # Toggle task 2`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueTasks,
+}
+
+var issueTasksCheckCmd = &cobra.Command{
+	Use:   "check <issue> <n>",
+	Short: "Toggle task n of an issue's task list",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runIssueTasksCheck,
+}
+
+var issueSplitCmd = &cobra.Command{
+	Use:   "split <issue>",
+	Short: "Split task list items into child issues",
+	Long: `Create a child issue for each selected task list item of an issue.
+
+Children get a parent: frontmatter link back to the issue, and each split task
+in the parent references its child issue.
+
+Modes:
+  Interactive (default): pick tasks in a TUI
+  --tasks:               Comma-separated task numbers (see mp issue tasks)
+
+Examples:
+  mp issue split big-feature
+  mp issue split big-feature --tasks 2,4`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueSplit,
+}
+
+var issueRenameCmd = &cobra.Command{
+	Use:   "rename <issue> <new-id>",
+	Short: "Rename an issue file and update references to it",
+	Long: `Move an issue file to <new-id>.md in the same issues directory and update
+what refers to it:
+  - the issue markers and PR metadata of pieces working on it
+  - parent: and depends_on: references in other issues
+
+The old path is recorded as an alias in .monkeypuzzle/issues.index.json, so
+the old ID still resolves (e.g. mp piece new --issue <old-id>).
+
+Examples:
+  mp issue rename add-login add-sso-login`,
+	Args: cobra.ExactArgs(2),
+	RunE: runIssueRename,
+}
+
+var issueAttachCmd = &cobra.Command{
+	Use:   "attach <issue> <file>...",
+	Short: "Copy files into an issue's assets directory and link them",
+	Long: `Copy screenshots, design files and the like into the issue's assets
+directory, <issue>.assets/ next to the issue file, and link them from the issue.
+
+Relative links in the issue to a file now point at its copy: links that
+resolve to the file, and links with its file name that resolve to nothing
+(e.g. ![form](form.png) written before the screenshot was added). A file the
+issue doesn't link yet gets a link appended, as an image for image files.
+
+Assets move with the issue on mp issue rename and archiving, are listed under
+"assets" by mp issue export, and by mp_issue_read in mp-mcp.
+
+Examples:
+  mp issue attach add-login ~/Desktop/form.png
+  mp issue attach add-login design/login.fig notes.pdf`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runIssueAttach,
+}
+
+var issueDupesCmd = &cobra.Command{
+	Use:   "dupes [--merge <keep> <duplicate>]",
+	Short: "Find likely duplicate issues, or merge two",
+	Long: `Report pairs of issues with similar titles as JSON, most similar first, e.g.
+after agents filed many follow-ups. The score is the overlap of the titles'
+words, raised when the issues share labels; pairs from --threshold up are
+reported. Done issues are skipped unless --include-done is given.
+
+With --merge, combines <duplicate> into <keep>:
+  - the duplicate's body is appended under "## Merged from <duplicate>"
+  - <keep> gets the earlier created_at of the two (frontmatter, else first commit)
+  - piece markers, PR metadata and parent:/depends_on: references are redirected
+    as by mp issue rename, and the duplicate's ID resolves to <keep>
+  - the duplicate's file is removed
+
+Examples:
+  mp issue dupes
+  mp issue dupes --threshold 0.8
+  mp issue dupes --merge add-login add-login-page`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if flagIssueDupesMerge {
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+		return cobra.NoArgs(cmd, args)
+	},
+	RunE: runIssueDupes,
+}
+
+var issueSetStatusCmd = &cobra.Command{
+	Use:   "set-status [<issue>...] <status>",
+	Short: "Set the status of many issues at once",
+	Long: `Set the status (todo, in-progress or done) of the issues matching --filter
+and of the issues named, in one pass: every issue is read before any is
+written, and if writing one fails the others are restored.
+
+--dry-run prints the changes without writing them. Prints JSON with the
+issues changed and the number that already had the status.
+
+Examples:
+  mp issue set-status --filter "label=bug AND status=in-progress" done --dry-run
+  mp issue set-status --filter "label=bug AND status=in-progress" done
+  mp issue set-status add-login add-sso todo`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runIssueSetStatus,
+}
+
+var issuePendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List issue edits staged for approval",
+	Long: `List the edits to issues staged in .monkeypuzzle/pending as JSON, with the
+content each issue would have, the command that made it and when.
+
+With issues.require_approval set in the config, edits agents make to issues
+through mp-mcp (status changes, new issues, checked tasks, ...) are staged
+instead of applied. mp issue approve applies them; mp issue reject discards
+them.`,
+	Args: cobra.NoArgs,
+	RunE: runIssuePending,
+}
+
+var issueApproveCmd = &cobra.Command{
+	Use:   "approve [<issue>...]",
+	Short: "Apply staged issue edits",
+	Long: `Apply the staged edits to the issues named, or to every issue with --all.
+
+Fails if an issue has changed since its edit was staged, unless --force is
+given. If applying one edit fails, the issues already written are restored.
+
+Examples:
+  mp issue pending
+  mp issue approve add-login
+  mp issue approve --all`,
+	RunE: runIssueApprove,
+}
+
+var issueRejectCmd = &cobra.Command{
+	Use:   "reject [<issue>...]",
+	Short: "Discard staged issue edits",
+	Long: `Discard the staged edits to the issues named, or to every issue with --all.
+
+Examples:
+  mp issue reject add-login
+  mp issue reject --all`,
+	RunE: runIssueReject,
+}
+
+var issueLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check issue files for problems",
+	Long: `Validate every issue file and print a JSON report:
+  - frontmatter is present, closed with --- and parsable
+  - title and status are set, and status is todo, in-progress or done
+  - short IDs (file names) are unique across issues directories
+  - parent: and depends_on: name existing issues
+  - the fields defined in issues.fields are set when required, with values
+    of their type and among their allowed values
+  - with --links, relative links in issue bodies point at existing files:
+    other issues, repository files and assets; links starting with / are
+    relative to the repository root, URLs and code are skipped
+
+--fix corrects what it can in place: missing frontmatter, titles (from the
+H1 heading or file name) and statuses (todo), misspelled statuses such as
+"In Progress", missing required fields that have a default, and links to
+renamed or archived issues. Exits non-zero if problems remain, for CI gating.
+
+Examples:
+  mp issue lint
+  mp issue lint --fix
+  mp issue lint --links`,
+	Args: cobra.NoArgs,
+	RunE: runIssueLint,
+}
+
+var issueLinkCmd = &cobra.Command{
+	Use:   "link <issue-path>",
+	Short: "Link the current piece to an issue",
+	Long: `Associate the current piece with an issue after creation.
+
+The issue is marked in-progress. If the piece was linked to another issue,
+that issue is unlinked and reverted to todo. Must be run from within a piece worktree.
+
+Examples:
+  mp issue link issues/add-feature-x.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueLink,
+}
+
+var issueUnlinkCmd = &cobra.Command{
+	Use:   "unlink",
+	Short: "Unlink the current piece from its issue",
+	Long: `Remove the current piece's issue association and revert the issue to todo
+if it is still in progress. Must be run from within a piece worktree.`,
+	Args: cobra.NoArgs,
+	RunE: runIssueUnlink,
+}
+
 func init() {
 	issueCreateCmd.Flags().StringVar(&flagIssueTitle, "title", "", "Issue title")
 	issueCreateCmd.Flags().StringVar(&flagIssueDescription, "description", "", "Issue description")
 	issueCreateCmd.Flags().BoolVar(&flagIssueSchema, "schema", false, "Output JSON schema with defaults and exit")
+	issueCreateCmd.Flags().StringArrayVar(&flagIssueFields, "field", nil, "Set a frontmatter field, e.g. priority=high (repeatable)")
+	issueListCmd.Flags().StringVar(&flagIssueStatus, "status", "", "Filter by status: todo, in-progress, done")
+	issueCreateCmd.Flags().StringVar(&flagIssueDir, "dir", "", "Issues directory to create in, by path or team name (default: the first configured)")
+	issueListCmd.Flags().StringVar(&flagIssueTeam, "team", "", "Filter by team (issues directory name or path)")
+	issueListCmd.Flags().StringVar(&flagIssueOwner, "owner", "", "Filter by owner handle or email (from .monkeypuzzle/owners)")
+	issueListCmd.Flags().BoolVar(&flagIssueMine, "mine", false, "Filter to issues you own (git config "+owners.IdentityKey+", else user.email)")
+	issueListCmd.MarkFlagsMutuallyExclusive("owner", "mine")
+	issueListCmd.Flags().BoolVar(&flagIssueReindex, "reindex", false, "Ignore the issue index and re-parse every issue")
+	issueListCmd.Flags().StringSliceVar(&flagIssueColumns, "columns", nil, "Print a table of these fields instead of JSON, e.g. id,status,priority (implies --output table)")
+	addOutputFlag(issueListCmd)
+	issueExportCmd.Flags().StringVar(&flagIssueFormat, "format", issue.FormatJSON, "Output format: json or csv")
+	issueExportCmd.Flags().StringArrayVar(&flagIssueFilters, "filter", nil, "Keep issues matching a filter expression (repeatable)")
+	issueListCmd.Flags().StringArrayVar(&flagIssueFilters, "filter", nil, "Keep issues matching a filter expression, e.g. 'status=todo AND label~infra' (repeatable)")
+	issueTasksCmd.AddCommand(issueTasksCheckCmd)
+	issueLintCmd.Flags().BoolVar(&flagIssueLintFix, "fix", false, "Correct fixable problems in place")
+	issueLintCmd.Flags().BoolVar(&flagIssueLintLinks, "links", false, "Also check relative links in issue bodies")
+	issueSplitCmd.Flags().StringVar(&flagIssueSplitTasks, "tasks", "", "Comma-separated task numbers to split (e.g. 2,4)")
 	issueCmd.AddCommand(issueCreateCmd)
+	issueCmd.AddCommand(issueListCmd)
+	issueCmd.AddCommand(issueExportCmd)
+	issueCmd.AddCommand(issueTasksCmd)
+	issueCmd.AddCommand(issueSplitCmd)
+	issueCmd.AddCommand(issueLintCmd)
+	issueCmd.AddCommand(issueRenameCmd)
+	issueCmd.AddCommand(issueAttachCmd)
+	issueDupesCmd.Flags().Float64Var(&flagIssueDupesThreshold, "threshold", issue.DefaultDupeThreshold, "Lowest similarity score reported, from 0 to 1")
+	issueDupesCmd.Flags().BoolVar(&flagIssueDupesIncludeDone, "include-done", false, "Also compare issues with status done")
+	issueDupesCmd.Flags().BoolVar(&flagIssueDupesMerge, "merge", false, "Merge the second issue given into the first")
+	issueCmd.AddCommand(issueDupesCmd)
+	issueSetStatusCmd.Flags().StringArrayVar(&flagIssueFilters, "filter", nil, "Select issues matching a filter expression, e.g. 'label=bug AND status=in-progress' (repeatable)")
+	issueSetStatusCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show the changes without writing them")
+	issueCmd.AddCommand(issueSetStatusCmd)
+	issueApproveCmd.Flags().BoolVar(&flagIssuePendingAll, "all", false, "Apply every staged edit")
+	issueApproveCmd.Flags().BoolVar(&flagIssueApproveForce, "force", false, "Apply edits to issues changed since they were staged")
+	issueRejectCmd.Flags().BoolVar(&flagIssuePendingAll, "all", false, "Discard every staged edit")
+	issueCmd.AddCommand(issuePendingCmd)
+	issueCmd.AddCommand(issueApproveCmd)
+	issueCmd.AddCommand(issueRejectCmd)
+	issueCmd.AddCommand(issueLinkCmd)
+	issueCmd.AddCommand(issueUnlinkCmd)
 	rootCmd.AddCommand(issueCmd)
 }
 
@@ -59,21 +393,17 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(schema))
+		fmt.Fprintln(env.Stdout, string(schema))
 		return nil
 	}
 
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
 	// Create dependencies
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := issue.NewHandler(deps, wd)
 
 	// Get input based on mode
@@ -82,10 +412,351 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	_, err = handler.Run(input)
+	_, err = handler.RunInDir(input, flagIssueDir)
 	return err
 }
 
+func runIssueList(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	owner := flagIssueOwner
+	if flagIssueMine {
+		wd, err := getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		if owner = owners.Identity(env.Exec, wd); owner == "" {
+			return fmt.Errorf("cannot tell who you are for --mine: set git config %s (e.g. @alice) or user.email", owners.IdentityKey)
+		}
+	}
+
+	expr, err := filter.ParseAll(flagIssueFilters)
+	if err != nil {
+		return err
+	}
+
+	issues, err := handler.ListWithOptions(issue.ListOptions{Status: flagIssueStatus, Team: flagIssueTeam, Owner: owner, Reindex: flagIssueReindex, Filter: expr})
+	if err != nil {
+		return err
+	}
+
+	var table render.Table
+	if len(flagIssueColumns) > 0 {
+		if !cmd.Flags().Changed("output") {
+			flagOutput = render.FormatTable
+		} else if flagOutput != render.FormatTable {
+			return fmt.Errorf("--columns requires --output table")
+		}
+		table = render.Table{Columns: flagIssueColumns, Rows: handler.Columns(issues, flagIssueColumns)}
+	}
+	return printResult(issues, table)
+}
+
+func runIssueExport(cmd *cobra.Command, args []string) error {
+	if flagIssueFormat != issue.FormatJSON && flagIssueFormat != issue.FormatCSV {
+		return fmt.Errorf("invalid format %q (valid: %s, %s)", flagIssueFormat, issue.FormatJSON, issue.FormatCSV)
+	}
+	expr, err := filter.ParseAll(flagIssueFilters)
+	if err != nil {
+		return err
+	}
+
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+	records, err := handler.Export(issue.ExportOptions{Filter: expr})
+	if err != nil {
+		return err
+	}
+
+	if flagIssueFormat == issue.FormatCSV {
+		data, err := issue.ExportCSV(records)
+		if err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+		_, err = env.Stdout.Write(data)
+		return err
+	}
+	return printJSON(records)
+}
+
+func runIssueTasks(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	list, err := handler.Tasks(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Human-readable checklist to stderr
+	for _, t := range list.Tasks {
+		mark := " "
+		if t.Done {
+			mark = "x"
+		}
+		fmt.Fprintf(env.Stderr, "%d. [%s] %s\n", t.Number, mark, t.Text)
+	}
+	fmt.Fprintf(env.Stderr, "%d/%d done (%d%%)\n", list.Summary.Done, list.Summary.Total, list.Summary.Percent)
+
+	return printJSON(list)
+}
+
+func runIssueTasksCheck(cmd *cobra.Command, args []string) error {
+	n, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid task number %q", args[1])
+	}
+
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	task, err := handler.CheckTask(args[0], n)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(task)
+}
+
+func runIssueSplit(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	var numbers []int
+	switch {
+	case flagIssueSplitTasks != "":
+		numbers, err = issue.ParseTaskNumbers(flagIssueSplitTasks)
+		if err != nil {
+			return err
+		}
+
+	case isTerminal():
+		numbers, err = runIssueSplitInteractiveMode(handler, args[0])
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("no tasks selected; provide --tasks (e.g. --tasks 2,4)")
+	}
+
+	result, err := handler.Split(args[0], numbers)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(result)
+}
+
+func runIssueSplitInteractiveMode(handler *issue.Handler, id string) ([]int, error) {
+	list, err := handler.Tasks(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Tasks) == 0 {
+		return nil, fmt.Errorf("%s has no task list items to split", list.Path)
+	}
+
+	p := tea.NewProgram(splitTUI.New(list.Path, list.Tasks), tea.WithOutput(redact.Unwrap(env.Stderr)))
+	m, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	finalModel := m.(splitTUI.Model)
+	if finalModel.Cancelled {
+		return nil, fmt.Errorf("cancelled")
+	}
+
+	return finalModel.Selected(), nil
+}
+
+func runIssueDupes(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	if flagIssueDupesMerge {
+		result, err := handler.Merge(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		return printJSON(result)
+	}
+
+	candidates, err := handler.Dupes(issue.DupesOptions{Threshold: flagIssueDupesThreshold, IncludeDone: flagIssueDupesIncludeDone})
+	if err != nil {
+		return err
+	}
+	return printJSON(candidates)
+}
+
+func runIssueSetStatus(cmd *cobra.Command, args []string) error {
+	expr, err := filter.ParseAll(flagIssueFilters)
+	if err != nil {
+		return err
+	}
+
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+	status := args[len(args)-1]
+	result, err := handler.SetStatus(status, issue.SetStatusOptions{Filter: expr, IDs: args[:len(args)-1], DryRun: flagDryRun})
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runIssuePending(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	changes, err := handler.Pending()
+	if err != nil {
+		return err
+	}
+	return printJSON(changes)
+}
+
+func runIssueApprove(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	changes, err := handler.Approve(issue.PendingOptions{IDs: args, All: flagIssuePendingAll, Force: flagIssueApproveForce})
+	if err != nil {
+		return err
+	}
+	return printJSON(changes)
+}
+
+func runIssueReject(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	changes, err := handler.Reject(issue.PendingOptions{IDs: args, All: flagIssuePendingAll})
+	if err != nil {
+		return err
+	}
+	return printJSON(changes)
+}
+
+func runIssueRename(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.Rename(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runIssueAttach(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.Attach(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runIssueLint(cmd *cobra.Command, args []string) error {
+	handler, err := newIssueHandler()
+	if err != nil {
+		return err
+	}
+
+	report, err := handler.Lint(issue.LintOptions{Fix: flagIssueLintFix, Links: flagIssueLintLinks})
+	if err != nil {
+		return err
+	}
+
+	if err := printJSON(report); err != nil {
+		return err
+	}
+	if !report.OK {
+		return fmt.Errorf("issue lint found problems")
+	}
+	return nil
+}
+
+// newIssueHandler creates an issue handler for the working directory
+func newIssueHandler() (*issue.Handler, error) {
+	wd, err := getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	return issue.NewHandler(deps, wd), nil
+}
+
+// printJSON writes v as indented JSON to stdout
+func printJSON(v any) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
+	return nil
+}
+
+func runIssueLink(cmd *cobra.Command, args []string) error {
+	return runIssueLinkChange(func(handler *piececmd.Handler, wd string) (piececmd.LinkResult, error) {
+		return handler.LinkIssue(wd, args[0])
+	})
+}
+
+func runIssueUnlink(cmd *cobra.Command, args []string) error {
+	return runIssueLinkChange(func(handler *piececmd.Handler, wd string) (piececmd.LinkResult, error) {
+		return handler.UnlinkIssue(wd)
+	})
+}
+
+// runIssueLinkChange runs a link or unlink in the current piece and prints the result as JSON
+func runIssueLinkChange(change func(handler *piececmd.Handler, wd string) (piececmd.LinkResult, error)) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	handler := piececmd.NewHandler(deps)
+
+	result, err := change(handler, wd)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(result)
+}
+
 func getIssueInput() (issue.Input, error) {
 	allFlagsProvided := flagIssueTitle != ""
 	hasStdin := hasStdinData()
@@ -101,7 +772,7 @@ func getIssueInput() (issue.Input, error) {
 		}
 
 	case hasStdin:
-		data, err := io.ReadAll(os.Stdin)
+		data, err := io.ReadAll(env.Stdin)
 		if err != nil {
 			return issue.Input{}, fmt.Errorf("failed to read stdin: %w", err)
 		}
@@ -120,6 +791,17 @@ func getIssueInput() (issue.Input, error) {
 		return issue.Input{}, fmt.Errorf("no input provided; use --schema to see expected format, or provide --title flag")
 	}
 
+	fields, err := parseIssueFields(flagIssueFields)
+	if err != nil {
+		return issue.Input{}, err
+	}
+	for name, value := range fields {
+		if input.Fields == nil {
+			input.Fields = map[string]string{}
+		}
+		input.Fields[name] = value
+	}
+
 	// Apply defaults
 	input = issue.WithDefaults(input)
 
@@ -131,8 +813,21 @@ func getIssueInput() (issue.Input, error) {
 	return input, nil
 }
 
+// parseIssueFields parses --field name=value flags
+func parseIssueFields(flags []string) (map[string]string, error) {
+	fields := map[string]string{}
+	for _, flag := range flags {
+		name, value, ok := strings.Cut(flag, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --field %q: expected name=value", flag)
+		}
+		fields[strings.TrimSpace(name)] = value
+	}
+	return fields, nil
+}
+
 func runIssueInteractiveMode() (issue.Input, error) {
-	p := tea.NewProgram(issueTUI.New())
+	p := tea.NewProgram(issueTUI.New(), tea.WithOutput(redact.Unwrap(env.Stderr)))
 	m, err := p.Run()
 	if err != nil {
 		return issue.Input{}, err
@@ -0,0 +1,93 @@
+package mp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/labels"
+)
+
+var (
+	flagLabelColor       string
+	flagLabelDescription string
+	flagLabelGitHub      string
+)
+
+var labelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Manage the labels issues may carry",
+	Long: `Commands for the label taxonomy in .monkeypuzzle/labels.json. Once it
+defines labels, mp issue create rejects other labels and mp issue lint reports
+them; mp piece pr create adds an issue's labels to its PR where the GitHub
+repository has them.`,
+}
+
+var labelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the defined labels",
+	Long: `Print the labels defined in .monkeypuzzle/labels.json as JSON, in file
+order; an empty list when the file does not exist.`,
+	Args: cobra.NoArgs,
+	RunE: runLabelsList,
+}
+
+var labelsAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Define a label",
+	Long: `Add a label to .monkeypuzzle/labels.json, creating it if needed. --color
+is a hex color as GitHub takes it; --github names the repository label PRs
+get for it, when it is named differently there.
+
+Examples:
+  mp labels add bug --color d73a4a --description "Something is broken"
+  mp labels add infra --github "area: infrastructure"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLabelsAdd,
+}
+
+func init() {
+	labelsAddCmd.Flags().StringVar(&flagLabelColor, "color", "", "Hex color, e.g. d73a4a")
+	labelsAddCmd.Flags().StringVar(&flagLabelDescription, "description", "", "What the label means")
+	labelsAddCmd.Flags().StringVar(&flagLabelGitHub, "github", "", "Repository label PRs get for it (default: the same name)")
+	labelsCmd.AddCommand(labelsListCmd)
+	labelsCmd.AddCommand(labelsAddCmd)
+	rootCmd.AddCommand(labelsCmd)
+}
+
+func runLabelsList(cmd *cobra.Command, args []string) error {
+	handler, err := newLabelsHandler()
+	if err != nil {
+		return err
+	}
+	list, err := handler.List()
+	if err != nil {
+		return err
+	}
+	return printJSON(list)
+}
+
+func runLabelsAdd(cmd *cobra.Command, args []string) error {
+	handler, err := newLabelsHandler()
+	if err != nil {
+		return err
+	}
+	label, err := handler.Add(labels.Label{
+		Name:        args[0],
+		Color:       flagLabelColor,
+		Description: flagLabelDescription,
+		GitHub:      flagLabelGitHub,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(label)
+}
+
+func newLabelsHandler() (*labels.Handler, error) {
+	wd, err := getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return labels.NewHandler(newDeps(), wd), nil
+}
@@ -0,0 +1,72 @@
+package mp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/doctor"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+var flagLintSkipProviders bool
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the monkeypuzzle configuration for CI",
+	Long: `Check the monkeypuzzle configuration strictly enough to gate changes to it
+in pull requests. Runs the mp doctor checks plus:
+  - unknown fields in .monkeypuzzle/monkeypuzzle.json (e.g. misspelled keys)
+  - invalid values: providers, hooks.sandbox, pieces.wip_limit,
+    pieces.max_name_length, pieces.auto_checkpoint_minutes, merge.strategy,
+    issues.fields, redact.patterns, empty aliases
+  - hook scripts that would not run: unknown names, no shebang line,
+    not executable
+
+Provider checks (e.g. gh auth status) need credentials and are marked
+skippable; --skip-providers skips them in CI.
+
+Prints a JSON report and exits non-zero if any check fails.
+
+Examples:
+  mp lint
+  mp lint --skip-providers    # In CI without gh credentials`,
+	Args: cobra.NoArgs,
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&flagLintSkipProviders, "skip-providers", false, "Skip provider checks that need the network or credentials")
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	report, err := doctor.NewHandler(deps).Lint(status.RepoRoot, doctor.LintOptions{SkipProviders: flagLintSkipProviders})
+	if err != nil {
+		return err
+	}
+
+	if err := printJSON(report); err != nil {
+		return err
+	}
+	if !report.OK {
+		return fmt.Errorf("lint found problems")
+	}
+	return nil
+}
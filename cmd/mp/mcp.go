@@ -0,0 +1,97 @@
+package mp
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/mcpconfig"
+)
+
+var (
+	flagMCPClient string
+	flagMCPServer string
+	flagMCPConfig string
+	flagMCPName   string
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Configure MCP clients to use mp-mcp",
+}
+
+var mcpInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register mp-mcp with an MCP client",
+	Long: `Register the mp-mcp server with an MCP client, by the absolute paths of the
+mp-mcp binary and of this mp, which mp-mcp then runs for every tool call.
+mp-mcp is looked for next to mp, then on PATH; --server overrides it.
+
+For claude-desktop, the server is added to claude_desktop_config.json (or
+--config), keeping the other servers and settings; restart the app to load it.
+For generic, the mcpServers entry is printed under "config", and written to
+--config when given.
+
+Examples:
+  mp mcp install
+  mp mcp install --dry-run
+  mp mcp install --client generic | jq .config
+  mp mcp install --client generic --config .cursor/mcp.json`,
+	Args: cobra.NoArgs,
+	RunE: runMCPInstall,
+}
+
+func init() {
+	mcpInstallCmd.Flags().StringVar(&flagMCPClient, "client", mcpconfig.ClientClaudeDesktop, "MCP client: claude-desktop or generic")
+	mcpInstallCmd.Flags().StringVar(&flagMCPServer, "server", "", "Path of the mp-mcp binary (default: next to mp, then on PATH)")
+	mcpInstallCmd.Flags().StringVar(&flagMCPConfig, "config", "", "Client config file to write (default: the client's own)")
+	mcpInstallCmd.Flags().StringVar(&flagMCPName, "name", mcpconfig.DefaultName, "Name of the server in the client config")
+	mcpInstallCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show the entry without writing the config")
+	mcpCmd.AddCommand(mcpInstallCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCPInstall(cmd *cobra.Command, args []string) error {
+	mpPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate mp binary: %w", err)
+	}
+	serverPath, err := findMCPServer(mpPath)
+	if err != nil {
+		return err
+	}
+
+	result, err := mcpconfig.NewHandler(newDeps()).Install(mcpconfig.Options{
+		Client:     flagMCPClient,
+		Name:       flagMCPName,
+		ServerPath: serverPath,
+		MPPath:     mpPath,
+		ConfigPath: flagMCPConfig,
+		DryRun:     flagDryRun,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+// findMCPServer returns the absolute path of mp-mcp: --server, else the one
+// next to mp, else the one on PATH
+func findMCPServer(mpPath string) (string, error) {
+	path := flagMCPServer
+	if path == "" {
+		path = filepath.Join(filepath.Dir(mpPath), "mp-mcp")
+		if _, err := os.Stat(path); err != nil {
+			if path, err = exec.LookPath("mp-mcp"); err != nil {
+				return "", fmt.Errorf("mp-mcp not found next to %s or on PATH; install it or pass --server", mpPath)
+			}
+		}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("mp-mcp not found: %w", err)
+	}
+	return filepath.Abs(path)
+}
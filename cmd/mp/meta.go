@@ -0,0 +1,204 @@
+package mp
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/cleanup"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/doctor"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/labels"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/mcpconfig"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/messages"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	prcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/selftest"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/stats"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/watch"
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Describe mp itself for tooling",
+}
+
+var metaCommandsCmd = &cobra.Command{
+	Use:   "commands",
+	Short: "Print a JSON manifest of all commands, flags, and JSON input/output",
+	Long: `Print a JSON description of every command: its usage, arguments, flags,
+the schema of JSON it reads from stdin, and the schema of JSON it writes to stdout.
+
+MCP clients, shell wrappers, and documentation generators can use it to stay
+in sync with mp. Aliases and plugins available in the current environment are
+included.`,
+	Args: cobra.NoArgs,
+}
+
+var flagMetaMessagesEnglish bool
+
+var metaMessagesCmd = &cobra.Command{
+	Use:   "messages",
+	Short: "Print the message catalog as JSON",
+	Long: `Print the user-facing messages of mp as a JSON object of message IDs and
+format strings, in the active language. Error message IDs are the codes of
+--json-errors output.
+
+Translators start from the English catalog (--english): translate any subset
+of the messages, keeping each message's format verbs (%s, %q, %d), and save
+the file as ~/.config/monkeypuzzle/messages/<lang>.json, or point MP_MESSAGES
+at it. The language comes from MP_LANG, LC_ALL, LC_MESSAGES or LANG.`,
+	Args: cobra.NoArgs,
+	RunE: runMetaMessages,
+}
+
+func init() {
+	// Set here: the manifest describes metaCommandsCmd itself
+	metaCommandsCmd.RunE = runMetaCommands
+	metaMessagesCmd.Flags().BoolVar(&flagMetaMessagesEnglish, "english", false, "Print the built-in English catalog instead of the active one")
+	metaCmd.AddCommand(metaCommandsCmd)
+	metaCmd.AddCommand(metaMessagesCmd)
+	rootCmd.AddCommand(metaCmd)
+}
+
+// commandInputs maps commands reading JSON from stdin to their input type
+func commandInputs() map[*cobra.Command]any {
+	return map[*cobra.Command]any{
+		initCmd:        initcmd.Input{},
+		issueCreateCmd: issue.Input{},
+	}
+}
+
+// commandOutputs maps commands writing JSON to stdout to their result type
+func commandOutputs() map[*cobra.Command]any {
+	return map[*cobra.Command]any{
+		cleanupCmd:                  cleanup.Report{},
+		cleanupScheduleInstallCmd:   cleanup.Schedule{},
+		cleanupScheduleStatusCmd:    cleanup.Schedule{},
+		cleanupScheduleUninstallCmd: cleanup.Schedule{},
+		gcCmd:                       cleanup.GCReport{},
+		doctorCmd:                   doctor.Report{},
+		lintCmd:                     doctor.Report{},
+		eventsVerifyCmd:             events.Verification{},
+		issueListCmd:                []issue.IssueSummary{},
+		issueExportCmd:              []issue.ExportRecord{},
+		issueTasksCmd:               issue.TaskList{},
+		issueTasksCheckCmd:          issue.Task{},
+		issueSplitCmd:               issue.SplitResult{},
+		issueLintCmd:                issue.LintReport{},
+		issueRenameCmd:              issue.RenameResult{},
+		issueAttachCmd:              issue.AttachResult{},
+		issueDupesCmd:               []issue.DupeCandidate{},
+		issueSetStatusCmd:           issue.SetStatusResult{},
+		issuePendingCmd:             []issue.PendingChange{},
+		issueApproveCmd:             []issue.PendingChange{},
+		issueRejectCmd:              []issue.PendingChange{},
+		issueLinkCmd:                piececmd.LinkResult{},
+		issueUnlinkCmd:              piececmd.LinkResult{},
+		labelsListCmd:               []labels.Label{},
+		labelsAddCmd:                labels.Label{},
+		pieceCmd:                    piececmd.PieceStatus{},
+		pieceNewCmd:                 piececmd.PieceInfo{},
+		pieceAdoptCmd:               piececmd.PieceInfo{},
+		pieceCleanupCmd:             []piececmd.CleanupResult{},
+		pieceDoctorCmd:              piececmd.DoctorResult{},
+		pieceMergeCmd:               piececmd.Plan{},
+		pieceDeleteCmd:              piececmd.DeleteResult{},
+		pieceLockCmd:                piececmd.LockResult{},
+		pieceUnlockCmd:              piececmd.LockResult{},
+		pieceOpenCmd:                piececmd.OpenTarget{},
+		pieceHistoryCmd:             piececmd.PieceHistory{},
+		pieceListCmd:                []piececmd.PieceStatus{},
+		pieceVerifyCmd:              piececmd.VerifyReport{},
+		pieceCheckpointCmd:          piececmd.CheckpointResult{},
+		pieceTidyCmd:                piececmd.TidyResult{},
+		pieceCommitHookCmd:          piececmd.CommitHookResult{},
+		pieceBisectCmd:              piececmd.BisectResult{},
+		pieceTemplatesCmd:           []piececmd.Template{},
+		pieceRefreshTitleCmd:        piececmd.TitleResult{},
+		prCreateCmd:                 prcmd.PRCreateResult{},
+		prUpdateCmd:                 prcmd.PRUpdateResult{},
+		prChecksCmd:                 prcmd.ChecksResult{},
+		prCommentsCmd:               prcmd.CommentsResult{},
+		prAddressCmd:                prcmd.AddressResult{},
+		prOpenCmd:                   prcmd.PRLink{},
+		statsCmd:                    stats.Report{},
+		selftestCmd:                 selftest.Report{},
+		mcpInstallCmd:               mcpconfig.Result{},
+		// One event per line
+		watchCmd:        watch.Event{},
+		metaCommandsCmd: meta.Manifest{},
+		metaMessagesCmd: messages.Catalog{},
+	}
+}
+
+func runMetaCommands(cmd *cobra.Command, args []string) error {
+	return printJSON(buildManifest(rootCmd))
+}
+
+func runMetaMessages(cmd *cobra.Command, args []string) error {
+	if flagMetaMessagesEnglish {
+		return printJSON(messages.English)
+	}
+	return printJSON(messages.Active())
+}
+
+// buildManifest describes root and all its visible subcommands, depth first
+func buildManifest(root *cobra.Command) meta.Manifest {
+	inputs := commandInputs()
+	outputs := commandOutputs()
+	manifest := meta.Manifest{Version: meta.ManifestVersion, APIInfo: meta.Info()}
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		command := meta.Command{
+			Path:     c.CommandPath(),
+			Use:      c.UseLine(),
+			Short:    c.Short,
+			Long:     c.Long,
+			Aliases:  c.Aliases,
+			Runnable: c.Runnable(),
+		}
+		if _, args, ok := strings.Cut(c.Use, " "); ok {
+			command.Args = args
+		}
+		if v, ok := inputs[c]; ok {
+			command.Input = meta.Schema(v)
+		}
+		if v, ok := outputs[c]; ok {
+			command.Output = meta.Schema(v)
+		}
+
+		addFlag := func(persistent bool) func(f *pflag.Flag) {
+			return func(f *pflag.Flag) {
+				if f.Hidden {
+					return
+				}
+				command.Flags = append(command.Flags, meta.Flag{
+					Name:       f.Name,
+					Shorthand:  f.Shorthand,
+					Type:       f.Value.Type(),
+					Default:    f.DefValue,
+					Usage:      f.Usage,
+					Persistent: persistent,
+				})
+			}
+		}
+		c.NonInheritedFlags().VisitAll(addFlag(false))
+		c.InheritedFlags().VisitAll(addFlag(true))
+
+		manifest.Commands = append(manifest.Commands, command)
+		for _, sub := range c.Commands() {
+			if sub.Hidden || sub.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			walk(sub)
+		}
+	}
+	walk(root)
+	return manifest
+}
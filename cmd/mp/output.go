@@ -0,0 +1,26 @@
+package mp
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/render"
+)
+
+// flagOutput selects the format of a command's result on stdout
+var flagOutput string
+
+// addOutputFlag gives cmd the --output flag read by printResult
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", render.FormatJSON,
+		"Output format: json, yaml, table, or template='{{.Field}}' (a Go template run per list item)")
+}
+
+// printResult writes v to stdout in the --output format; table configures
+// the table format
+func printResult(v any, table render.Table) error {
+	format, err := render.Parse(flagOutput)
+	if err != nil {
+		return err
+	}
+	return format.Write(env.Stdout, v, table)
+}
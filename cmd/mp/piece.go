@@ -1,17 +1,29 @@
 package mp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
 	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	prcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/render"
 )
 
 var pieceCmd = &cobra.Command{
@@ -25,22 +37,188 @@ var pieceNewCmd = &cobra.Command{
 	Use:   "new",
 	Short: "Create a new puzzle piece",
 	Long: `Create a new puzzle piece by initializing a git worktree and opening a tmux session.
-The worktree will be created in XDG_DATA_HOME/monkeypuzzle/pieces (default: ~/.local/share/monkeypuzzle/pieces).`,
+The worktree will be created in XDG_DATA_HOME/monkeypuzzle/pieces (default: ~/.local/share/monkeypuzzle/pieces).
+
+With --title, creates a markdown issue with that title in the first issues
+directory and proceeds as with --issue, so unplanned work is tracked too.
+
+With --template, applies .monkeypuzzle/piece-templates/<name>.json: the branch is
+named <branch_prefix><name>, bootstrap commands run in the worktree, tmux windows are
+opened, and the template is recorded for 'mp piece verify' and 'mp piece merge'.`,
 	RunE: runPieceNew,
 }
 
+var pieceAdoptCmd = &cobra.Command{
+	Use:   "adopt <branch>",
+	Short: "Create a piece for an existing branch",
+	Long: `Creates a piece like 'mp piece new', but checks out an existing branch in the
+worktree instead of creating one, e.g. to continue work left on a branch from an
+earlier piece. The piece is named after the branch unless --name is given.
+
+mp piece new fails with branch_exists when the piece's branch exists already.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPieceAdopt,
+}
+
+var pieceVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Run the verify commands of the piece's template",
+	Long: `Runs the verify commands of the template the current piece was created from
+(mp piece new --template) in its worktree, and prints each command's result as
+JSON. Exits non-zero if a command fails. Pieces without a template pass.
+
+mp piece merge runs the same commands first and refuses to merge if one fails.`,
+	Args: cobra.NoArgs,
+	RunE: runPieceVerify,
+}
+
+var pieceBisectCmd = &cobra.Command{
+	Use:   "bisect [--good <commit>] [--bad <commit>] [-- <test command>]",
+	Short: "Find the piece commit that broke a test",
+	Long: `Runs git bisect in the current piece's worktree to find the first commit
+between --good (default: where the piece branched off its base) and --bad
+(default: HEAD) where the test command fails. The command follows --: a single
+argument is a shell command line, several are a command and its arguments.
+Without one, the verify commands of the piece's template are used.
+
+The worktree must have no uncommitted changes; it is back on its branch when
+bisect ends. The first bad commit is printed as JSON and appended to the
+piece's notes (notes_path).
+
+Examples:
+  mp piece bisect -- go test ./...
+  mp piece bisect --good v1.2.0 -- 'make test && make lint'
+  mp piece bisect                  # With the template's verify commands`,
+	RunE: runPieceBisect,
+}
+
+var pieceCheckpointCmd = &cobra.Command{
+	Use:   "checkpoint [-m <message>]",
+	Short: "Commit work in progress to the piece branch",
+	Long: `Commits all uncommitted changes in the current piece's worktree, untracked
+files included, as "wip: <message>" on the piece branch, so unattended agent
+work survives a crashed session or a lost worktree. Commit hooks are skipped.
+A clean worktree is left as it is ("committed": false).
+
+With --watch, checkpoints every --every (default: pieces.auto_checkpoint_minutes
+from .monkeypuzzle/monkeypuzzle.json) until interrupted. When that is set, new
+pieces get a tmux window named checkpoint running it.
+
+mp piece merge --squash-checkpoints lists each run of checkpoints as one line in
+the squash commit message.
+
+Examples:
+  mp piece checkpoint
+  mp piece checkpoint -m "login form renders"
+  mp piece checkpoint --watch --every 10m`,
+	Args: cobra.NoArgs,
+	RunE: runPieceCheckpoint,
+}
+
+var pieceTidyCmd = &cobra.Command{
+	Use:   "tidy",
+	Short: "Fold wip: checkpoint commits into the piece's other commits",
+	Long: `Rewrites the current piece's branch so no "wip:" checkpoint commits remain,
+for teams that merge PRs without squashing: run it before mp piece pr create. Each
+checkpoint is folded into the commit before it; checkpoints before the first
+other commit are folded into that commit, keeping its message. A branch of
+checkpoints only becomes one commit, with --message (default: "feat: <piece>").
+
+The worktree must be clean, and the branch must have no merge commits (from mp
+piece update), which the rebase would drop. The old head is printed as
+"before": git reset --hard to it undoes the tidy. With --interactive, git
+rebase -i opens the prepared todo list in your editor to adjust first.
+
+Examples:
+  mp piece tidy --dry-run   # Print the todo list only
+  mp piece tidy
+  mp piece tidy -i`,
+	Args: cobra.NoArgs,
+	RunE: runPieceTidy,
+}
+
+var pieceCommitHookCmd = &cobra.Command{
+	Use:   "commit-hook",
+	Short: "Install the git hook prefixing piece commits with the issue ID",
+	Long: `Installs a commit-msg git hook that prefixes commit messages in pieces with
+the short ID of the piece's issue, e.g. "[add-login] Add login form", so every
+commit can be traced to its issue. The hook is shared by all worktrees of the
+repository and runs mp piece commit-msg once the message is written; it does
+nothing outside pieces, in pieces without an issue, or where mp is not
+installed. A commit-msg hook not written by mp is left alone.
+
+Set pieces.issue_prefix in .monkeypuzzle/monkeypuzzle.json to have mp piece
+verify and mp piece merge check that the piece's commits carry the prefix.
+
+Examples:
+  mp piece commit-hook`,
+	Args: cobra.NoArgs,
+	RunE: runPieceCommitHook,
+}
+
+var pieceCommitMsgCmd = &cobra.Command{
+	Use:   "commit-msg <file>",
+	Short: "Prefix a commit message with the piece's issue ID",
+	Long: `Prefixes the subject of the commit message in <file> with the short ID of the
+current piece's issue, as the commit-msg hook installed by mp piece commit-hook
+does. Merge commits, "wip:" checkpoints, fixup!/squash!/amend! commits and
+messages already prefixed are left as they are, and so are messages without a
+subject, which git then aborts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPieceCommitMsg,
+}
+
+var pieceTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List piece templates",
+	Long:  `Prints the piece templates in .monkeypuzzle/piece-templates as JSON.`,
+	Args:  cobra.NoArgs,
+	RunE:  runPieceTemplates,
+}
+
+var pieceRefreshTitleCmd = &cobra.Command{
+	Use:   "refresh-title",
+	Short: "Title the piece's tmux session after its issue and status",
+	Long: `Renames the first window of the current piece's tmux session to the piece's
+issue title, truncated, and status, e.g. "Add login [in-progress]", so the tmux
+session list stays readable with many pieces running. Pieces without an issue
+are titled with their name. Does nothing if the session isn't running.
+
+mp piece new --issue, mp issue link and mp issue unlink set the title already;
+call this from hooks or scripts that change an issue's status.`,
+	Args: cobra.NoArgs,
+	RunE: runPieceRefreshTitle,
+}
+
 var pieceUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update piece with latest from main branch",
-	Long:  `Merges the main branch into the current piece's history. Must be run from within a piece worktree.`,
-	RunE:  runPieceUpdate,
+	Long: `Merges the main branch into the current piece's history. Must be run from within a piece worktree.
+
+With --check, predicts conflicts using git merge-tree and prints them as JSON
+without changing the worktree.
+
+With --all, updates every active piece from its base branch (can be run from
+anywhere), skipping pieces with uncommitted changes or predicted conflicts, and
+prints a JSON summary. Combine with --check for a dry run.`,
+	RunE: runPieceUpdate,
 }
 
 var pieceMergeCmd = &cobra.Command{
 	Use:   "merge",
 	Short: "Merge piece back into main branch",
-	Long:  `Merges the piece branch back into main. Fails if main has commits not in the piece worktree. Must be run from within a piece worktree.`,
-	RunE:  runPieceMerge,
+	Long: `Merges the piece branch back into main. Fails if main has commits not in the piece worktree. Must be run from within a piece worktree.
+
+The strategy is --strategy, else merge.strategy in .monkeypuzzle/monkeypuzzle.json:
+  squash     one commit listing the piece's commits (the default)
+  merge      the piece's commits and a merge commit
+  rebase-ff  the piece branch rebased onto main, then main fast-forwarded to it
+
+With --push, the merged branch is pushed to the remote. If GitHub branch protection
+blocks direct pushes to it (e.g. it requires pull requests or status checks), the
+merge is refused before anything changes. On a terminal, mp offers to open a pull
+request for the piece instead; --pr-fallback opens it without asking.`,
+	RunE: runPieceMerge,
 }
 
 var pieceCleanupCmd = &cobra.Command{
@@ -50,91 +228,330 @@ var pieceCleanupCmd = &cobra.Command{
 	RunE:  runPieceCleanup,
 }
 
+var pieceDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the piece branch against origin",
+	Long: `Compares the current piece branch with its origin counterpart and warns if it was
+force-updated or deleted upstream. With --reset-to-remote, resets the local branch to
+origin (refusing if the worktree has uncommitted changes). Must be run from within a piece worktree.`,
+	RunE: runPieceDoctor,
+}
+
+var pieceLockCmd = &cobra.Command{
+	Use:   "lock [name]",
+	Short: "Protect a piece from cleanup and deletion",
+	Long: `Locks a piece's worktree with git worktree lock. Cleanup skips locked pieces and
+delete refuses them until the piece is unlocked. Defaults to the current piece when run
+from within a piece worktree.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPieceLock,
+}
+
+var pieceUnlockCmd = &cobra.Command{
+	Use:   "unlock [name]",
+	Short: "Unlock a piece locked with 'mp piece lock'",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runPieceUnlock,
+}
+
+var pieceOpenCmd = &cobra.Command{
+	Use:   "open [name]",
+	Short: "Open a piece in your editor or file manager",
+	Long: `Opens the current or named piece's worktree with the "editor" command line from
+.monkeypuzzle/monkeypuzzle.json (e.g. "code -n"), falling back to $VISUAL, then $EDITOR,
+then the OS file manager.
+
+With --print-path, prints the worktree path instead, for shell scripting:
+
+  cd "$(mp piece open --print-path my-feature)"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPieceOpen,
+}
+
+var pieceDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete an abandoned piece",
+	Long: `Removes a piece's worktree and tmux session without merging it. Defaults to the
+current piece when run from within a piece worktree.
+
+If the piece's issue is in-progress and neither its PR was merged nor its branch is
+already in its base branch, the issue is reverted to todo and the rollback is recorded
+in .monkeypuzzle/events.jsonl. If the merge status can't be checked, the issue is left
+unchanged.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPieceDelete,
+}
+
+var pieceDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show the piece's changes or commit log",
+	Long: `Prints the changes of the current piece since it diverged from its base branch
+(the branch recorded by 'mp piece merge --into' or the PR base, else --main-branch).
+
+With --log, prints the piece's commits as JSON: hash, author, subject, body and
+trailers (e.g. Co-authored-by). With --changelog, prints a markdown changelog
+fragment titled with the piece's issue and PR. Must be run from within a piece worktree.
+
+Examples:
+  mp piece diff
+  mp piece diff --log | jq '.commits[].subject'
+  mp piece diff --changelog > changelog.d/my-feature.md`,
+	Args: cobra.NoArgs,
+	RunE: runPieceDiff,
+}
+
+var piecePromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the brief an agent starts the piece from",
+	Long: `Prints a markdown brief for a coding agent working on the current piece: the
+piece's worktree, branch and base branch, its linked issue, and the repository's
+conventions from .monkeypuzzle/context.md (architecture notes, coding standards
+and review checklist). Must be run from within a piece worktree.
+
+Examples:
+  mp piece prompt
+  mp piece prompt | claude`,
+	Args: cobra.NoArgs,
+	RunE: runPiecePrompt,
+}
+
+var pieceHistoryCmd = &cobra.Command{
+	Use:   "history [name]",
+	Short: "Show the timeline of a piece",
+	Long: `Prints what happened to a piece, oldest first: creation, commits, updates from
+main, PR creation and updates, hook runs, merge, and cleanup or deletion.
+
+The timeline is rebuilt from .monkeypuzzle/events.jsonl and, while the piece's
+worktree exists, its commits since the base branch and its PR metadata, so it
+also works for pieces that were already cleaned up. A human-readable timeline
+goes to stderr and JSON to stdout. Defaults to the current piece.
+
+Examples:
+  mp piece history
+  mp piece history my-feature | jq '.entries[] | select(.type == "hook.run")'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPieceHistory,
+}
+
+var pieceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List active pieces",
+	Long: `Prints every active piece as JSON, with the fields of mp piece: branch,
+base branch, commits ahead and behind, uncommitted changes, linked issue and
+PR. Remote branches are not checked.
+
+--filter keeps pieces matching a filter expression over these fields (see
+mp issue list).
+
+--du adds disk_bytes, the disk space of each worktree, measured with du in
+parallel and cached for 10 minutes. Combine it with --filter to find large
+pieces; mp gc removes merged ones to free space.
+
+Examples:
+  mp piece list
+  mp piece list --filter 'dirty=true OR behind>0'
+  mp piece list --filter 'issue_id~login AND pr_number>0'
+  mp piece list --du --filter 'disk_bytes>1000000000'`,
+	Args: cobra.NoArgs,
+	RunE: runPieceList,
+}
+
 var flagMainBranch string
+var flagMergeInto string
+var flagUpdateCheck bool
+var flagUpdateAll bool
 var flagPieceName string
+var flagPieceListDU bool
 var flagIssuePath string
+var flagPieceTemplate string
+var flagPieceTitle string
 var flagDryRun bool
 var flagForce bool
+var flagResetToRemote bool
+var flagEnforceWIP bool
+var flagPieceFast bool
+var flagLockReason string
+var flagOpenPrintPath bool
+var flagOpenReveal bool
+var flagDiffLog bool
+var flagDiffChangelog bool
+var flagPieceFilters []string
+var flagBisectGood string
+var flagBisectBad string
+var flagCheckpointMessage string
+var flagCheckpointWatch bool
+var flagCheckpointEvery time.Duration
+var flagSquashCheckpoints bool
+var flagMergeStrategy string
+var flagMergePush bool
+var flagMergePRFallback bool
+var flagTidyInteractive bool
+var flagTidyMessage string
 
 func init() {
 	pieceNewCmd.Flags().StringVar(&flagPieceName, "name", "", "Optional piece name (default: auto-generated)")
-	pieceNewCmd.Flags().StringVar(&flagIssuePath, "issue", "", "Create piece from issue file (e.g., issues/foo.md)")
+	pieceNewCmd.Flags().StringVar(&flagIssuePath, "issue", "", "Create piece from issue file or short ID (e.g., issues/foo.md or foo)")
+	pieceNewCmd.Flags().BoolVar(&flagEnforceWIP, "enforce", false, "Fail instead of warning when pieces.wip_limit is reached")
+	pieceNewCmd.Flags().StringVar(&flagPieceTitle, "title", "", "Create an issue with this title and start the piece from it (ad-hoc work)")
+	pieceNewCmd.Flags().StringVar(&flagPieceTemplate, "template", "", "Apply a piece template from .monkeypuzzle/piece-templates (e.g., bugfix)")
+	pieceAdoptCmd.Flags().StringVar(&flagPieceName, "name", "", "Optional piece name (default: the branch name)")
+	pieceAdoptCmd.Flags().StringVar(&flagPieceTemplate, "template", "", "Apply a piece template from .monkeypuzzle/piece-templates (e.g., bugfix)")
 	pieceUpdateCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Main branch name to merge (default: main)")
+	pieceUpdateCmd.Flags().BoolVar(&flagUpdateCheck, "check", false, "Predict merge conflicts without changing the worktree")
+	pieceUpdateCmd.Flags().BoolVar(&flagUpdateAll, "all", false, "Update every active piece, skipping those with uncommitted changes or predicted conflicts")
 	pieceMergeCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Main branch name to merge into (default: main)")
+	pieceUpdateCmd.Flags().BoolVarP(&flagConfirmYes, "yes", "y", false, "Update without confirming on a terminal")
+	pieceMergeCmd.Flags().BoolVarP(&flagConfirmYes, "yes", "y", false, "Merge without confirming on a terminal")
+	pieceMergeCmd.Flags().StringVar(&flagMergeStrategy, "strategy", "", "Merge strategy: squash, merge or rebase-ff (default: merge.strategy, else squash)")
+	pieceMergeCmd.Flags().BoolVar(&flagSquashCheckpoints, "squash-checkpoints", false, "List each run of wip: checkpoint commits as one line in the commit message")
+	pieceMergeCmd.Flags().BoolVar(&flagMergePush, "push", false, "Push the merged branch to the remote, refusing branches GitHub protects against direct pushes")
+	pieceMergeCmd.Flags().BoolVar(&flagMergePRFallback, "pr-fallback", false, "With --push, open a pull request instead when the branch is protected")
+	pieceMergeCmd.Flags().StringVar(&flagMergeInto, "into", "", "Merge into another existing branch (e.g., release/1.2) and record it as the piece's base")
 	pieceCleanupCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Main branch name to check for merged status (default: main)")
 	pieceCleanupCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show what would be cleaned without making changes")
 	pieceCleanupCmd.Flags().BoolVar(&flagForce, "force", false, "Skip confirmation prompts")
+	pieceDeleteCmd.Flags().BoolVar(&flagForce, "force", false, "Remove the worktree even if it has uncommitted changes")
+	pieceDoctorCmd.Flags().BoolVar(&flagResetToRemote, "reset-to-remote", false, "Reset the piece branch to its origin head")
+	pieceDoctorCmd.Flags().BoolVarP(&flagConfirmYes, "yes", "y", false, "Reset without confirming on a terminal")
+	pieceDoctorCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Main branch to predict update conflicts against (default: main)")
+	pieceCmd.Flags().BoolVar(&flagPieceFast, "fast", false, "Skip checks that contact the remote")
+	pieceCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Base branch for pieces without a recorded base (default: main)")
+	addOutputFlag(pieceCmd)
 	pieceCmd.AddCommand(pieceNewCmd)
+	pieceCmd.AddCommand(pieceAdoptCmd)
 	pieceCmd.AddCommand(pieceUpdateCmd)
 	pieceCmd.AddCommand(pieceMergeCmd)
 	pieceCmd.AddCommand(pieceCleanupCmd)
+	pieceCmd.AddCommand(pieceDoctorCmd)
+	pieceCmd.AddCommand(pieceDeleteCmd)
+	pieceLockCmd.Flags().StringVar(&flagLockReason, "reason", "", "Why the piece is locked")
+	pieceCmd.AddCommand(pieceLockCmd)
+	pieceCmd.AddCommand(pieceUnlockCmd)
+	pieceOpenCmd.Flags().BoolVar(&flagOpenPrintPath, "print-path", false, "Print the worktree path instead of opening it")
+	pieceOpenCmd.Flags().BoolVar(&flagOpenReveal, "reveal", false, "Open the file manager even if an editor is configured")
+	pieceOpenCmd.MarkFlagsMutuallyExclusive("print-path", "reveal")
+	pieceCmd.AddCommand(pieceOpenCmd)
+	pieceDiffCmd.Flags().BoolVar(&flagDiffLog, "log", false, "Print the piece's commits as JSON instead of the diff")
+	pieceDiffCmd.Flags().BoolVar(&flagDiffChangelog, "changelog", false, "Print a markdown changelog fragment instead of the diff")
+	pieceDiffCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Base branch for pieces without a recorded base (default: main)")
+	pieceDiffCmd.MarkFlagsMutuallyExclusive("log", "changelog")
+	pieceCmd.AddCommand(pieceDiffCmd)
+	piecePromptCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Base branch for pieces without a recorded base (default: main)")
+	pieceCmd.AddCommand(piecePromptCmd)
+	pieceHistoryCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Base branch for pieces without a recorded base (default: main)")
+	pieceCmd.AddCommand(pieceHistoryCmd)
+	pieceListCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Base branch for pieces without a recorded base (default: main)")
+	pieceListCmd.Flags().StringArrayVar(&flagPieceFilters, "filter", nil, "Keep pieces matching a filter expression, e.g. 'dirty=true' (repeatable)")
+	pieceListCmd.Flags().BoolVar(&flagPieceListDU, "du", false, "Measure the disk space of each worktree (disk_bytes)")
+	addOutputFlag(pieceListCmd)
+	pieceCmd.AddCommand(pieceListCmd)
+	pieceCmd.AddCommand(pieceVerifyCmd)
+	pieceBisectCmd.Flags().StringVar(&flagBisectGood, "good", "", "Commit without the regression (default: where the piece branched off)")
+	pieceBisectCmd.Flags().StringVar(&flagBisectBad, "bad", "", "Commit with the regression (default: HEAD)")
+	pieceCmd.AddCommand(pieceBisectCmd)
+	pieceCheckpointCmd.Flags().StringVarP(&flagCheckpointMessage, "message", "m", "", "Checkpoint message, after the wip: prefix (default: the time)")
+	pieceCheckpointCmd.Flags().BoolVar(&flagCheckpointWatch, "watch", false, "Checkpoint periodically until interrupted")
+	pieceCheckpointCmd.Flags().DurationVar(&flagCheckpointEvery, "every", 0, "Interval for --watch (default: pieces.auto_checkpoint_minutes)")
+	pieceCheckpointCmd.MarkFlagsMutuallyExclusive("message", "watch")
+	pieceCmd.AddCommand(pieceCheckpointCmd)
+	pieceTidyCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Print the planned history without rewriting the branch")
+	pieceTidyCmd.Flags().BoolVarP(&flagTidyInteractive, "interactive", "i", false, "Edit the prepared todo list with git rebase -i")
+	pieceTidyCmd.Flags().StringVarP(&flagTidyMessage, "message", "m", "", "Commit message when every commit is a checkpoint (default: feat: <piece>)")
+	pieceTidyCmd.Flags().StringVar(&flagMainBranch, "main-branch", "main", "Base branch for pieces without a recorded base (default: main)")
+	pieceTidyCmd.MarkFlagsMutuallyExclusive("dry-run", "interactive")
+	pieceCmd.AddCommand(pieceTidyCmd)
+	pieceCmd.AddCommand(pieceCommitHookCmd)
+	pieceCmd.AddCommand(pieceCommitMsgCmd)
+	pieceCmd.AddCommand(pieceTemplatesCmd)
+	pieceCmd.AddCommand(pieceRefreshTitleCmd)
 	rootCmd.AddCommand(pieceCmd)
 }
 
 func runPieceStatus(cmd *cobra.Command, args []string) error {
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := piececmd.NewHandler(deps)
 
-	status, err := handler.Status(wd)
+	status, err := handler.Details(wd, piececmd.DetailsOptions{MainBranch: flagMainBranch, Fast: flagPieceFast})
 	if err != nil {
 		return err
 	}
 
 	// Output to stderr for human-readable text
 	if status.InPiece {
-		fmt.Fprintf(os.Stderr, "Working on piece: %s\n", status.PieceName)
-		fmt.Fprintf(os.Stderr, "Worktree path: %s\n", status.WorktreePath)
+		fmt.Fprintf(env.Stderr, "Working on piece: %s\n", status.PieceName)
+		fmt.Fprintf(env.Stderr, "Worktree path: %s\n", status.WorktreePath)
+		fmt.Fprintf(env.Stderr, "Branch: %s (%d ahead, %d behind %s)\n", status.Branch, status.Ahead, status.Behind, status.BaseBranch)
+		if status.Dirty {
+			fmt.Fprintf(env.Stderr, "Uncommitted changes: yes\n")
+		}
+		if status.IssuePath != "" {
+			fmt.Fprintf(env.Stderr, "Issue: %s\n", status.IssuePath)
+		}
+		if status.Template != "" {
+			fmt.Fprintf(env.Stderr, "Template: %s\n", status.Template)
+		}
+		if status.SourceDir != "" {
+			fmt.Fprintf(env.Stderr, "Source dir: %s\n", status.SourceDir)
+		}
+		if status.PRNumber != 0 {
+			fmt.Fprintf(env.Stderr, "PR: #%d %s\n", status.PRNumber, status.PRURL)
+		}
+		if status.Remote != "" {
+			fmt.Fprintf(env.Stderr, "Remote: %s\n", status.Remote)
+		}
 	} else {
-		fmt.Fprintf(os.Stderr, "In main repository\n")
+		fmt.Fprintf(env.Stderr, "In main repository\n")
 		if status.RepoRoot != "" {
-			fmt.Fprintf(os.Stderr, "Repo root: %s\n", status.RepoRoot)
+			fmt.Fprintf(env.Stderr, "Repo root: %s\n", status.RepoRoot)
 		}
 	}
 
-	// Output JSON to stdout
-	jsonData, err := json.MarshalIndent(status, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal status: %w", err)
-	}
-	fmt.Println(string(jsonData))
-
-	return nil
+	// Output result to stdout
+	return printResult(status, render.Table{})
 }
 
 func runPieceNew(cmd *cobra.Command, args []string) error {
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	// Detect monkeypuzzle source directory
-	// Try to find it by looking for the monkeypuzzle source repo
-	// Start from current directory and walk up looking for go.mod with monkeypuzzle module
-	monkeypuzzleSourceDir, err := findMonkeypuzzleSource(wd)
+	// The monkeypuzzle source directory is optional: $MP_SOURCE_DIR or
+	// tool.source_dir in the user config
+	monkeypuzzleSourceDir, err := initcmd.SourceDir(env.FS)
 	if err != nil {
-		return fmt.Errorf("failed to find monkeypuzzle source directory: %w", err)
+		return fmt.Errorf("failed to read user config: %w", err)
 	}
 
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := piececmd.NewHandler(deps)
 
+	// Warn (or block with --enforce) when at the WIP limit
+	if status, err := handler.Status(wd); err == nil && status.RepoRoot != "" {
+		if _, err := handler.CheckWIPLimit(status.RepoRoot, flagEnforceWIP); err != nil {
+			return err
+		}
+	}
+
 	var info piececmd.PieceInfo
+	opts := piececmd.CreateOptions{SourceDir: monkeypuzzleSourceDir, Name: flagPieceName, Template: flagPieceTemplate}
 
-	// Check if --issue flag is set
-	if flagIssuePath != "" {
+	// --title starts ad-hoc work from an issue created on the fly
+	if flagPieceTitle != "" {
+		if flagIssuePath != "" || flagPieceName != "" {
+			return fmt.Errorf("cannot use --title with --issue or --name")
+		}
+		repoRoot, rootErr := adapters.NewGit(deps.Exec).RepoRoot(wd)
+		if rootErr != nil {
+			return fmt.Errorf("not in a git repository: %w", rootErr)
+		}
+		info, err = issue.NewHandler(deps, repoRoot).StartPiece(flagPieceTitle, opts)
+	} else if flagIssuePath != "" {
 		// Validate that --name is not also set (they're mutually exclusive)
 		if flagPieceName != "" {
 			return fmt.Errorf("cannot use both --name and --issue flags together")
@@ -143,9 +560,9 @@ func runPieceNew(cmd *cobra.Command, args []string) error {
 		if strings.TrimSpace(flagIssuePath) == "" {
 			return fmt.Errorf("--issue flag requires a non-empty path")
 		}
-		info, err = handler.CreatePieceFromIssue(monkeypuzzleSourceDir, flagIssuePath)
+		info, err = handler.CreatePieceFromIssueWithOptions(opts, flagIssuePath)
 	} else {
-		info, err = handler.CreatePiece(monkeypuzzleSourceDir, flagPieceName)
+		info, err = handler.CreatePieceWithOptions(opts)
 	}
 
 	if err != nil {
@@ -157,13 +574,32 @@ func runPieceNew(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal info: %w", err)
 	}
-	fmt.Println(string(jsonData))
+	fmt.Fprintln(env.Stdout, string(jsonData))
 
 	return nil
 }
 
+func runPieceAdopt(cmd *cobra.Command, args []string) error {
+	monkeypuzzleSourceDir, err := initcmd.SourceDir(env.FS)
+	if err != nil {
+		return fmt.Errorf("failed to read user config: %w", err)
+	}
+
+	handler := piececmd.NewHandler(newDeps())
+	info, err := handler.CreatePieceWithOptions(piececmd.CreateOptions{
+		SourceDir: monkeypuzzleSourceDir,
+		Name:      flagPieceName,
+		Template:  flagPieceTemplate,
+		Branch:    args[0],
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(info)
+}
+
 func runPieceUpdate(cmd *cobra.Command, args []string) error {
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
@@ -174,22 +610,51 @@ func runPieceUpdate(cmd *cobra.Command, args []string) error {
 		mainBranch = "main"
 	}
 
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := piececmd.NewHandler(deps)
 
+	if flagUpdateAll {
+		status, err := handler.Status(wd)
+		if err != nil {
+			return fmt.Errorf("failed to get piece status: %w", err)
+		}
+		if status.RepoRoot == "" {
+			return core.NewNotInRepoError()
+		}
+		results, err := handler.UpdateAllPieces(status.RepoRoot, piececmd.UpdateAllOptions{MainBranch: mainBranch, DryRun: flagUpdateCheck})
+		if err != nil {
+			return err
+		}
+		return printJSON(results)
+	}
+
+	mainBranch = pieceBaseBranch(cmd, handler, wd, mainBranch)
+
+	if flagUpdateCheck {
+		check, err := handler.CheckConflicts(wd, mainBranch)
+		if err != nil {
+			return err
+		}
+		return printJSON(check)
+	}
+
+	plan, err := handler.PlanUpdate(wd, mainBranch)
+	if err != nil {
+		return err
+	}
+	if ok, err := confirmPlan(plan); !ok {
+		return err
+	}
+
 	if err := handler.UpdatePiece(wd, mainBranch); err != nil {
 		return err
 	}
 
-	return nil
+	return printJSON(plan)
 }
 
 func runPieceMerge(cmd *cobra.Command, args []string) error {
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
@@ -200,22 +665,87 @@ func runPieceMerge(cmd *cobra.Command, args []string) error {
 		mainBranch = "main"
 	}
 
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := piececmd.NewHandler(deps)
 
-	if err := handler.MergePiece(wd, mainBranch); err != nil {
+	merge := handler.MergePieceWithOptions
+	if flagMergeInto != "" {
+		if cmd.Flags().Changed("main-branch") {
+			return fmt.Errorf("--into and --main-branch cannot be used together")
+		}
+		mainBranch, merge = flagMergeInto, handler.MergePieceIntoWithOptions
+	} else {
+		mainBranch = pieceBaseBranch(cmd, handler, wd, mainBranch)
+	}
+
+	if err := piececmd.ValidateMergeStrategy(flagMergeStrategy); err != nil {
+		return fmt.Errorf("--strategy: %w", err)
+	}
+	plan, err := handler.PlanMerge(wd, mainBranch)
+	if err != nil {
+		return err
+	}
+	if flagMergeStrategy != "" {
+		plan.Strategy = flagMergeStrategy
+	}
+	if flagMergePRFallback && !flagMergePush {
+		return fmt.Errorf("--pr-fallback requires --push")
+	}
+	plan.Push = flagMergePush
+	if ok, err := confirmPlan(plan); !ok {
 		return err
 	}
 
-	return nil
+	opts := piececmd.MergeOptions{Strategy: flagMergeStrategy, SquashCheckpoints: flagSquashCheckpoints, Push: flagMergePush}
+	if err := merge(wd, mainBranch, opts); err != nil {
+		if re, ok := core.AsRemediable(err); ok && re.Code == core.CodeProtectedBranch {
+			return mergeViaPR(wd, mainBranch, err)
+		}
+		return err
+	}
+
+	return printJSON(plan)
+}
+
+// mergeViaPR opens a pull request into target for a merge --push that branch
+// protection refused, with --pr-fallback or when confirmed on a terminal.
+// Otherwise the protection error is returned, with its hint.
+func mergeViaPR(wd, target string, protectedErr error) error {
+	if !flagMergePRFallback {
+		if !isTerminal() {
+			return protectedErr
+		}
+		fmt.Fprintf(env.Stderr, "%v\n", protectedErr)
+		ok, err := confirm(fmt.Sprintf("Open a pull request into %s instead?", target))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return protectedErr
+		}
+	}
+	result, err := prcmd.NewHandler(newDeps()).CreatePR(wd, prcmd.Input{Base: target})
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+// pieceBaseBranch returns the base branch recorded for the current piece
+// unless --main-branch was given explicitly.
+func pieceBaseBranch(cmd *cobra.Command, handler *piececmd.Handler, wd, mainBranch string) string {
+	if cmd.Flags().Changed("main-branch") {
+		return mainBranch
+	}
+	status, err := handler.Status(wd)
+	if err != nil || !status.InPiece {
+		return mainBranch
+	}
+	return handler.BaseBranch(status.WorktreePath, mainBranch)
 }
 
 func runPieceCleanup(cmd *cobra.Command, args []string) error {
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
@@ -226,11 +756,7 @@ func runPieceCleanup(cmd *cobra.Command, args []string) error {
 		mainBranch = "main"
 	}
 
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := piececmd.NewHandler(deps)
 
 	// Get repo root (either from piece or main repo)
@@ -241,7 +767,7 @@ func runPieceCleanup(cmd *cobra.Command, args []string) error {
 
 	repoRoot := status.RepoRoot
 	if repoRoot == "" {
-		return fmt.Errorf("not in a git repository")
+		return core.NewNotInRepoError()
 	}
 
 	opts := piececmd.CleanupOptions{
@@ -260,40 +786,440 @@ func runPieceCleanup(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal results: %w", err)
 	}
-	fmt.Println(string(jsonData))
+	fmt.Fprintln(env.Stdout, string(jsonData))
 
 	return nil
 }
 
-// findMonkeypuzzleSource tries to find the monkeypuzzle source directory
-// by walking up from the current directory looking for go.mod with monkeypuzzle module
-func findMonkeypuzzleSource(startDir string) (string, error) {
-	dir := startDir
-	for {
-		goModPath := filepath.Join(dir, "go.mod")
-		if data, err := os.ReadFile(goModPath); err == nil {
-			// Check if this is the monkeypuzzle module
-			content := string(data)
-			if containsMonkeypuzzleModule(content) {
-				return dir, nil
-			}
+func runPieceDelete(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	handler := piececmd.NewHandler(deps)
+
+	repoRoot, pieceName, err := namedPiece(handler, wd, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.DeletePiece(repoRoot, pieceName, piececmd.DeleteOptions{Force: flagForce})
+	if err != nil {
+		return err
+	}
+
+	// Output JSON to stdout
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
+
+	return nil
+}
+
+// namedPiece returns the repository root and the piece named by args, defaulting
+// to the current piece
+func namedPiece(handler *piececmd.Handler, wd string, args []string) (string, string, error) {
+	status, err := handler.Status(wd)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return "", "", core.NewNotInRepoError()
+	}
+
+	pieceName := status.PieceName
+	if len(args) > 0 {
+		pieceName = args[0]
+	}
+	if pieceName == "" {
+		return "", "", fmt.Errorf("piece name required when not in a piece worktree")
+	}
+	return status.RepoRoot, pieceName, nil
+}
+
+func runPieceLock(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	handler := piececmd.NewHandler(newDeps())
+	repoRoot, pieceName, err := namedPiece(handler, wd, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.LockPiece(repoRoot, pieceName, flagLockReason)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runPieceUnlock(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	handler := piececmd.NewHandler(newDeps())
+	repoRoot, pieceName, err := namedPiece(handler, wd, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.UnlockPiece(repoRoot, pieceName)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runPieceOpen(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	handler := piececmd.NewHandler(newDeps())
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	pieceName, worktreePath, err := handler.PieceWorktree(wd, name)
+	if err != nil {
+		return err
+	}
+
+	if flagOpenPrintPath {
+		fmt.Fprintln(env.Stdout, worktreePath)
+		return nil
+	}
+
+	opts := piececmd.OpenOptions{Getenv: os.Getenv, GOOS: runtime.GOOS, Reveal: flagOpenReveal}
+	if status, err := handler.Status(wd); err == nil && status.RepoRoot != "" {
+		if cfg, err := piececmd.ReadConfig(status.RepoRoot, env.FS); err == nil {
+			opts.Editor = cfg.Editor
 		}
+	}
+	via, command := piececmd.OpenCommand(worktreePath, opts)
 
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			// Reached root
-			break
+	// Terminal editors need the terminal
+	run := exec.Command(command[0], command[1:]...)
+	run.Dir = worktreePath
+	run.Stdin = env.Stdin
+	run.Stdout = redact.Unwrap(env.Stdout)
+	run.Stderr = redact.Unwrap(env.Stderr)
+	if err := run.Run(); err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w", pieceName, strings.Join(command, " "), err)
+	}
+	return printJSON(piececmd.OpenTarget{PieceName: pieceName, WorktreePath: worktreePath, Via: via, Command: command})
+}
+
+func runPieceDiff(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	handler := piececmd.NewHandler(newDeps())
+
+	if !flagDiffLog && !flagDiffChangelog {
+		diff, err := handler.Diff(wd, flagMainBranch)
+		if err != nil {
+			return err
 		}
-		dir = parent
+		fmt.Fprint(env.Stdout, diff)
+		return nil
+	}
+
+	log, err := handler.Log(wd, flagMainBranch)
+	if err != nil {
+		return err
+	}
+	if flagDiffChangelog {
+		fmt.Fprint(env.Stdout, log.ChangelogFragment())
+		return nil
+	}
+
+	// One line per commit to stderr for humans
+	for _, c := range log.Commits {
+		fmt.Fprintf(env.Stderr, "%.7s %s (%s)\n", c.Hash, c.Subject, c.Author)
+	}
+	return printJSON(log)
+}
+
+func runPiecePrompt(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	prompt, err := piececmd.NewHandler(newDeps()).Prompt(wd, flagMainBranch)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(env.Stdout, prompt.Prompt)
+	return nil
+}
+
+func runPieceHistory(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	handler := piececmd.NewHandler(newDeps())
+	repoRoot, pieceName, err := namedPiece(handler, wd, args)
+	if err != nil {
+		return err
+	}
+
+	history, err := handler.History(repoRoot, pieceName, flagMainBranch)
+	if err != nil {
+		return err
 	}
 
-	// Fallback: return current directory if we can't find it
-	// This allows the command to work even if not in the monkeypuzzle repo
-	return startDir, nil
+	// Timeline to stderr for humans
+	for _, e := range history.Entries {
+		fmt.Fprintf(env.Stderr, "%s  %-13s %s\n", e.Time.Local().Format("2006-01-02 15:04:05"), e.Type, e.Summary)
+	}
+	return printJSON(history)
 }
 
-func containsMonkeypuzzleModule(content string) bool {
-	// Check for monkeypuzzle module name in go.mod
-	return strings.Contains(content, "module github.com/jewell-lgtm/monkeypuzzle") ||
-		strings.Contains(content, "module monkeypuzzle")
+func runPieceList(cmd *cobra.Command, args []string) error {
+	expr, err := filter.ParseAll(flagPieceFilters)
+	if err != nil {
+		return err
+	}
+
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	handler := piececmd.NewHandler(newDeps())
+	status, err := handler.Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+	pieces, err := handler.List(status.RepoRoot, piececmd.ListOptions{MainBranch: flagMainBranch, Filter: expr, DiskUsage: flagPieceListDU})
+	if err != nil {
+		return err
+	}
+	return printResult(pieces, render.Table{})
+}
+
+func runPieceVerify(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	report, err := piececmd.NewHandler(newDeps()).VerifyPiece(wd)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(report); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("verify commands failed")
+	}
+	return nil
+}
+
+func runPieceBisect(cmd *cobra.Command, args []string) error {
+	if dash := cmd.ArgsLenAtDash(); dash != 0 && len(args) > 0 {
+		return fmt.Errorf("the test command goes after --, e.g. mp piece bisect -- go test ./...")
+	}
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	result, err := piececmd.NewHandler(newDeps()).Bisect(wd, piececmd.BisectOptions{
+		Good:    flagBisectGood,
+		Bad:     flagBisectBad,
+		Command: args,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runPieceCheckpoint(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	handler := piececmd.NewHandler(newDeps())
+
+	if !flagCheckpointWatch {
+		result, err := handler.Checkpoint(wd, flagCheckpointMessage)
+		if err != nil {
+			return err
+		}
+		return printJSON(result)
+	}
+
+	interval := flagCheckpointEvery
+	if interval == 0 {
+		status, err := handler.Status(wd)
+		if err != nil {
+			return fmt.Errorf("failed to get piece status: %w", err)
+		}
+		if interval = handler.CheckpointInterval(status.RepoRoot); interval == 0 {
+			return fmt.Errorf("no checkpoint interval: pass --every or set pieces.auto_checkpoint_minutes")
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return handler.AutoCheckpoint(ctx, wd, interval)
+}
+
+func runPieceCommitHook(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	result, err := piececmd.NewHandler(newDeps()).InstallCommitHook(wd)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runPieceCommitMsg(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	// git passes the message file relative to the directory it runs the hook in
+	path := args[0]
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(wd, path)
+	}
+	_, err = piececmd.NewHandler(newDeps()).PrefixCommitMessage(wd, path)
+	return err
+}
+
+func runPieceTidy(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	handler := piececmd.NewHandler(newDeps())
+	opts := piececmd.TidyOptions{MainBranch: flagMainBranch, Message: flagTidyMessage, DryRun: flagDryRun}
+
+	if !flagTidyInteractive {
+		result, err := handler.Tidy(wd, opts)
+		if err != nil {
+			return err
+		}
+		return printJSON(result)
+	}
+
+	result, rebase, err := handler.TidyInteractive(wd, opts)
+	if err != nil {
+		return err
+	}
+	// The editor needs the terminal
+	run := exec.Command(rebase.Args[0], rebase.Args[1:]...)
+	run.Dir = rebase.Dir
+	run.Env = append(os.Environ(), rebase.Env...)
+	run.Stdin = env.Stdin
+	run.Stdout = redact.Unwrap(env.Stderr)
+	run.Stderr = redact.Unwrap(env.Stderr)
+	if err := run.Run(); err != nil {
+		return fmt.Errorf("git rebase -i failed (undo with git rebase --abort or git reset --hard %s): %w", result.Before, err)
+	}
+	result.Rewritten = true
+	return printJSON(result)
+}
+
+func runPieceTemplates(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	status, err := piececmd.NewHandler(newDeps()).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	templates, err := piececmd.ListTemplates(env.FS, status.RepoRoot)
+	if err != nil {
+		return err
+	}
+	if templates == nil {
+		templates = []piececmd.Template{}
+	}
+	return printJSON(templates)
+}
+
+func runPieceRefreshTitle(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	result, err := piececmd.NewHandler(newDeps()).RefreshTitle(wd)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runPieceDoctor(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	handler := piececmd.NewHandler(deps)
+
+	if flagResetToRemote {
+		plan, err := handler.PlanReset(wd)
+		if err != nil {
+			return err
+		}
+		if plan != nil {
+			if ok, err := confirmPlan(plan); !ok {
+				return err
+			}
+		}
+	}
+
+	result, err := handler.Doctor(wd, piececmd.DoctorOptions{
+		ResetToRemote: flagResetToRemote,
+		MainBranch:    pieceBaseBranch(cmd, handler, wd, flagMainBranch),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Output JSON to stdout
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
+
+	return nil
 }
@@ -0,0 +1,81 @@
+package mp
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/plugin"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+)
+
+// registerPlugins adds a command for each mp-<name> executable on PATH that
+// doesn't collide with a command or alias, so it shows up in mp help.
+// The returned function removes the added commands again.
+func registerPlugins() (remove func()) {
+	var added []*cobra.Command
+	for _, p := range plugin.Discover(env.FS, os.Getenv("PATH")) {
+		if cmd, _, err := rootCmd.Find([]string{p.Name}); err == nil && cmd != rootCmd {
+			continue
+		}
+		cmd := pluginCommand(p)
+		rootCmd.AddCommand(cmd)
+		added = append(added, cmd)
+	}
+	return func() { rootCmd.RemoveCommand(added...) }
+}
+
+func pluginCommand(p plugin.Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:   p.Name + " [args...]",
+		Short: "Plugin: " + p.Path,
+		// Flags belong to the plugin
+		DisableFlagParsing: true,
+		// The plugin reports its own errors; its exit code is passed on
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wd, err := getwd()
+			if err != nil {
+				return err
+			}
+
+			run := exec.Command(p.Path, args...)
+			run.Dir = wd
+			run.Env = append(os.Environ(), pluginContext(wd).Environ()...)
+			run.Stdin = env.Stdin
+			// Plugins own their terminal; their output is not redacted
+			run.Stdout = redact.Unwrap(env.Stdout)
+			run.Stderr = redact.Unwrap(env.Stderr)
+			return run.Run()
+		},
+	}
+}
+
+// pluginContext describes the repository and piece of workDir for a plugin
+func pluginContext(workDir string) plugin.Context {
+	var ctx plugin.Context
+	if mpPath, err := os.Executable(); err == nil {
+		ctx.MpPath = mpPath
+	}
+
+	status, err := piececmd.NewHandler(newDeps()).Status(workDir)
+	if err != nil {
+		return ctx
+	}
+	ctx.RepoRoot = status.RepoRoot
+	if status.InPiece {
+		ctx.PieceName = status.PieceName
+		ctx.WorktreePath = status.WorktreePath
+	}
+	if ctx.RepoRoot != "" {
+		configPath := filepath.Join(ctx.RepoRoot, initcmd.DirName, initcmd.ConfigFile)
+		if _, err := env.FS.Stat(configPath); err == nil {
+			ctx.ConfigPath = configPath
+		}
+	}
+	return ctx
+}
@@ -3,13 +3,17 @@ package mp
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"os/exec"
+	"runtime"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
-	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
 	prcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+	checksTUI "github.com/jewell-lgtm/monkeypuzzle/internal/tui/checks"
 )
 
 var prCmd = &cobra.Command{
@@ -28,31 +32,110 @@ If the piece was created from an issue, the issue title is used as the default P
 	RunE: runPRCreate,
 }
 
+var prUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Push the current piece and refresh its pull request",
+	Long: `Push the current piece branch and refresh its existing pull request.
+
+The title and body are only edited when --title, --body, or --regenerate-body is given.
+--regenerate-body rebuilds the description from the linked issue, the piece's commits and
+the review checklist of .monkeypuzzle/context.md.
+The update is recorded in the piece's PR metadata.`,
+	RunE: runPRUpdate,
+}
+
+var prChecksCmd = &cobra.Command{
+	Use:   "checks",
+	Short: "Show CI check status for the current piece's PR",
+	Long: `Show CI check status for the current piece's pull request.
+
+On a terminal, a live view refreshes until every check has finished.
+With --wait, blocks until checks finish and exits non-zero if any failed,
+so scripts can gate merges on CI. Without a terminal, prints the current status once.
+
+A PR with no checks reported has state "none", not "success". The live view and
+--wait keep polling then, since CI may not have picked up the PR yet.`,
+	RunE: runPRChecks,
+}
+
+var prCommentsCmd = &cobra.Command{
+	Use:   "comments",
+	Short: "List review comments on the current piece's PR",
+	Long: `List review threads on the current piece's pull request as JSON.
+
+Each thread includes the file, line, resolution state, and its comments
+(author, body, URL). Only unresolved threads are shown unless --all is given.`,
+	RunE: runPRComments,
+}
+
+var prAddressCmd = &cobra.Command{
+	Use:   "address",
+	Short: "Hand unresolved review comments to the coding agent",
+	Long: `Collect unresolved review threads and the piece diff into a brief.
+
+If agent.command is set in monkeypuzzle.json, the brief is fed to that command
+on stdin inside the piece's tmux session. Otherwise (or with --print) the brief
+is printed to stdout. Threads sent to the agent are marked as attempted and
+skipped by later runs unless --retry is given. Each hand-off is logged to the
+events file.`,
+	RunE: runPRAddress,
+}
+
+var prOpenCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the current piece's PR in a browser",
+	Long: `Open the current piece's pull request with the OS opener (open, xdg-open).
+
+The URL comes from the piece's PR metadata, else from gh pr view for the piece's
+branch. With --print, the URL is printed instead, for headless environments.`,
+	Args: cobra.NoArgs,
+	RunE: runPROpen,
+}
+
 var (
-	flagPRTitle string
-	flagPRBody  string
-	flagPRBase  string
+	flagPRTitle          string
+	flagPRBody           string
+	flagPRBase           string
+	flagPRRegenerateBody bool
+	flagPRChecksWait     bool
+	flagPRChecksInterval time.Duration
+	flagPRChecksTimeout  time.Duration
+	flagPRCommentsAll    bool
+	flagPRAddressPrint   bool
+	flagPRAddressRetry   bool
+	flagPROpenPrint      bool
 )
 
 func init() {
 	prCreateCmd.Flags().StringVar(&flagPRTitle, "title", "", "PR title (default: issue title or piece name)")
 	prCreateCmd.Flags().StringVar(&flagPRBody, "body", "", "PR description")
-	prCreateCmd.Flags().StringVar(&flagPRBase, "base", "main", "Base branch to merge into")
+	prCreateCmd.Flags().StringVar(&flagPRBase, "base", "", "Base branch to merge into (default: the piece's recorded base, else main)")
+	prUpdateCmd.Flags().StringVar(&flagPRTitle, "title", "", "New PR title")
+	prUpdateCmd.Flags().StringVar(&flagPRBody, "body", "", "New PR description")
+	prUpdateCmd.Flags().BoolVar(&flagPRRegenerateBody, "regenerate-body", false, "Regenerate description from the issue and commits")
+	prChecksCmd.Flags().BoolVar(&flagPRChecksWait, "wait", false, "Block until checks finish; exit non-zero on failure")
+	prChecksCmd.Flags().DurationVar(&flagPRChecksInterval, "interval", prcmd.DefaultChecksInterval, "Delay between status polls")
+	prChecksCmd.Flags().DurationVar(&flagPRChecksTimeout, "timeout", 0, "Give up waiting after this duration (0 = no timeout)")
+	prCommentsCmd.Flags().BoolVar(&flagPRCommentsAll, "all", false, "Include resolved threads")
+	prAddressCmd.Flags().BoolVar(&flagPRAddressPrint, "print", false, "Print the brief instead of sending it to the agent")
+	prAddressCmd.Flags().BoolVar(&flagPRAddressRetry, "retry", false, "Include threads attempted by a previous run")
+	prOpenCmd.Flags().BoolVar(&flagPROpenPrint, "print", false, "Print the PR URL instead of opening it")
 	prCmd.AddCommand(prCreateCmd)
+	prCmd.AddCommand(prUpdateCmd)
+	prCmd.AddCommand(prChecksCmd)
+	prCmd.AddCommand(prCommentsCmd)
+	prCmd.AddCommand(prAddressCmd)
+	prCmd.AddCommand(prOpenCmd)
 	pieceCmd.AddCommand(prCmd)
 }
 
 func runPRCreate(cmd *cobra.Command, args []string) error {
-	wd, err := os.Getwd()
+	wd, err := getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	deps := core.Deps{
-		FS:     adapters.NewOSFS(""),
-		Output: adapters.NewTextOutput(os.Stderr),
-		Exec:   adapters.NewOSExec(),
-	}
+	deps := newDeps()
 	handler := prcmd.NewHandler(deps)
 
 	input := prcmd.Input{
@@ -71,7 +154,173 @@ func runPRCreate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal result: %w", err)
 	}
-	fmt.Println(string(jsonData))
+	fmt.Fprintln(env.Stdout, string(jsonData))
+
+	return nil
+}
+
+func runPRUpdate(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	handler := prcmd.NewHandler(deps)
+
+	result, err := handler.UpdatePR(wd, prcmd.UpdateInput{
+		Title:          flagPRTitle,
+		Body:           flagPRBody,
+		RegenerateBody: flagPRRegenerateBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Output JSON to stdout
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
+
+	return nil
+}
+
+func runPRChecks(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	handler := prcmd.NewHandler(deps)
+
+	var result *prcmd.ChecksResult
+	var checksErr error
+
+	switch {
+	case flagPRChecksWait:
+		result, checksErr = handler.WaitForChecks(wd, prcmd.WaitOptions{
+			Interval: flagPRChecksInterval,
+			Timeout:  flagPRChecksTimeout,
+			OnPoll: func(r *prcmd.ChecksResult) {
+				deps.Output.Write(core.Message{
+					Type:    core.MsgInfo,
+					Content: fmt.Sprintf("PR #%d: %d passed, %d failed, %d pending", r.PRNumber, r.Passed, r.Failed, r.Pending),
+				})
+			},
+		})
+
+	case isTerminal():
+		p := tea.NewProgram(checksTUI.New(func() (*prcmd.ChecksResult, error) {
+			return handler.Checks(wd)
+		}, flagPRChecksInterval), tea.WithOutput(redact.Unwrap(env.Stderr)))
+		m, err := p.Run()
+		if err != nil {
+			return err
+		}
+		finalModel := m.(checksTUI.Model)
+		if finalModel.Err != nil {
+			return finalModel.Err
+		}
+		result = finalModel.Result
+
+	default:
+		result, checksErr = handler.Checks(wd)
+	}
+
+	if result != nil {
+		// Output JSON to stdout
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result: %w", err)
+		}
+		fmt.Fprintln(env.Stdout, string(jsonData))
+	}
+
+	return checksErr
+}
+
+func runPRComments(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	handler := prcmd.NewHandler(deps)
+
+	result, err := handler.Comments(wd, flagPRCommentsAll)
+	if err != nil {
+		return err
+	}
+
+	// Output JSON to stdout
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
+
+	return nil
+}
+
+func runPRAddress(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+	handler := prcmd.NewHandler(deps)
+
+	result, err := handler.Address(wd, prcmd.AddressOptions{
+		Print: flagPRAddressPrint,
+		Retry: flagPRAddressRetry,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The brief itself is the output in print mode
+	if result.Mode == prcmd.AddressModePrint {
+		fmt.Fprint(env.Stdout, result.Brief)
+		return nil
+	}
+
+	// Output JSON to stdout
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	fmt.Fprintln(env.Stdout, string(jsonData))
 
 	return nil
 }
+
+func runPROpen(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	link, err := prcmd.NewHandler(newDeps()).Link(wd)
+	if err != nil {
+		return err
+	}
+
+	if flagPROpenPrint {
+		fmt.Fprintln(env.Stdout, link.PRURL)
+		return nil
+	}
+
+	link.Command = prcmd.BrowserCommand(link.PRURL, runtime.GOOS)
+	run := exec.Command(link.Command[0], link.Command[1:]...)
+	run.Stdout = redact.Unwrap(env.Stderr)
+	run.Stderr = redact.Unwrap(env.Stderr)
+	if err := run.Run(); err != nil {
+		return fmt.Errorf("failed to open %s with %s (use --print): %w", link.PRURL, link.Command[0], err)
+	}
+	return printJSON(link)
+}
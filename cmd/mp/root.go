@@ -1,14 +1,98 @@
 package mp
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/messages"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
 )
 
+var flagJSONErrors bool
+var flagAllowNestedRepo bool
+
 var rootCmd = &cobra.Command{
 	Use:   "mp",
 	Short: "Monkeypuzzle - development workflow CLI",
+	// --version prints mp's version and the API version of its JSON output
+	Version: meta.Version() + " (api " + meta.APIVersion + ")",
+	// cobra would print usage to stdout; reportUsage prints it to stderr
+	SilenceUsage: true,
+	// Flags and args are valid once a command runs, so its errors
+	// are not usage mistakes and shouldn't print usage
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.SilenceUsage = true
+		if flagAllowNestedRepo {
+			os.Setenv(piececmd.AllowNestedRepoEnv, "1")
+		}
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagJSONErrors, "json-errors", false, "On failure, write the error with its code and fix hint as JSON to stdout")
+	rootCmd.PersistentFlags().BoolVar(&flagAllowNestedRepo, "allow-nested-repo", false, "Run in a repository nested inside a piece worktree (e.g. a vendored sub-repo) instead of refusing")
+}
+
+// errorOutput is the JSON written to stdout on failure with --json-errors
+type errorOutput struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+	Hint  string `json:"hint,omitempty"`
+	meta.APIInfo
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	r := redactStreams()
+	if err := traceExec(os.Args[1:], r); err != nil {
+		fmt.Fprintln(env.Stderr, "Error:", err)
+		reportError(err)
+		return err
+	}
+	injectEnv()
+	staging := stageIssueEdits(os.Args[1:])
+	messages.Use(loadMessages())
+	rootCmd.SetOut(env.Stdout)
+	rootCmd.SetErr(env.Stderr)
+	defer registerAliases()()
+	defer registerPlugins()()
+	cmd, err := rootCmd.ExecuteC()
+	reportStaged(staging)
+	if err != nil {
+		reportUsage(cmd)
+		reportError(err)
+	}
+	recordUsage(cmd, err)
+	return err
+}
+
+// reportUsage prints the usage of cmd to stderr after a usage mistake: a
+// failure before the command ran
+func reportUsage(cmd *cobra.Command) {
+	if cmd != nil && !cmd.SilenceUsage {
+		fmt.Fprintln(env.Stderr, cmd.UsageString())
+	}
+}
+
+// reportError prints a "how to fix" section for errors with a known remediation
+// and, with --json-errors, the error as JSON for agents
+func reportError(err error) {
+	out := errorOutput{Error: err.Error(), APIInfo: meta.Info()}
+	if re, ok := core.AsRemediable(err); ok {
+		out.Code = re.Code
+		out.Hint = re.Hint
+		fmt.Fprintf(env.Stderr, "\n%s\n  %s\n", messages.T(messages.HowToFix), re.Hint)
+	}
+
+	if flagJSONErrors {
+		// Hints quote shell syntax like <branch>; keep it readable
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(out)
+	}
 }
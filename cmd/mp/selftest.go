@@ -0,0 +1,64 @@
+package mp
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/selftest"
+)
+
+var flagSelftestKeep bool
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check mp works on this machine, end to end",
+	Long: `Check mp works on this machine by running a whole piece lifecycle in a
+throwaway repository: mp init, mp issue create, mp piece new, a commit in the
+piece, mp piece update, mp piece merge and mp piece cleanup, with the real git
+and tmux. Run it after installing or upgrading mp.
+
+Each stage is reported as it finishes; the first failure stops the test and is
+reported with what mp printed. Warnings, e.g. that tmux or gh is missing, are
+reported without failing. The repository and its pieces are created in a
+temporary directory, removed afterwards unless --keep is given, so your pieces
+and event logs are not touched.
+
+Examples:
+  mp selftest
+  mp selftest --keep   # Keep the repository to inspect it`,
+	Args: cobra.NoArgs,
+	RunE: runSelftest,
+}
+
+func init() {
+	selftestCmd.Flags().BoolVar(&flagSelftestKeep, "keep", false, "Keep the temporary repository and pieces")
+	rootCmd.AddCommand(selftestCmd)
+}
+
+func runSelftest(cmd *cobra.Command, args []string) error {
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the mp executable: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "mp-selftest-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if !flagSelftestKeep {
+		defer os.RemoveAll(dir)
+	}
+
+	report := selftest.NewHandler(newDeps()).Run(selftest.Options{Binary: binary, Dir: dir})
+	if flagSelftestKeep {
+		fmt.Fprintf(env.Stderr, "Kept %s\n", dir)
+	}
+	if err := printJSON(report); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("selftest failed at stage %s", report.FailedStage)
+	}
+	return nil
+}
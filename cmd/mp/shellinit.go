@@ -0,0 +1,43 @@
+package mp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/shellinit"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <bash|zsh|fish>",
+	Short: "Print shell integration (mp cd, prompt segment, completion)",
+	Long: `Prints shell functions to install in your shell's startup file:
+
+  eval "$(mp shell-init bash)"    # ~/.bashrc
+  eval "$(mp shell-init zsh)"     # ~/.zshrc
+  mp shell-init fish | source     # ~/.config/fish/config.fish
+
+They add 'mp cd [piece]' to change into a piece, an mp_prompt_info function
+printing the current piece and issue for your prompt, and tab completion.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: shellinit.Shells,
+	RunE:      runShellInit,
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
+
+func runShellInit(cmd *cobra.Command, args []string) error {
+	piecesDir, err := piececmd.PiecesDir()
+	if err != nil {
+		return fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	script, err := shellinit.Script(args[0], piecesDir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(env.Stdout, script)
+	return nil
+}
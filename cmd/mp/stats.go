@@ -0,0 +1,137 @@
+package mp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/render"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/stats"
+)
+
+var (
+	flagStatsUsage   bool
+	flagUsageEnable  bool
+	flagUsageDisable bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report work in progress over time",
+	Long: `Report the number of active pieces against pieces.wip_limit and replay piece
+create/remove events from .monkeypuzzle/events.jsonl into a per-day WIP timeline.
+
+With --usage, show local usage counters instead: runs and failures per command and
+failures by error type. Counting is opt-in (--usage --enable), stored only in
+$XDG_DATA_HOME/monkeypuzzle/usage.json, and never transmitted anywhere.
+--usage --disable stops counting and deletes the counters.
+
+Examples:
+  mp stats
+  mp stats --usage --enable
+  mp stats --usage`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&flagStatsUsage, "usage", false, "Show local usage counters")
+	statsCmd.Flags().BoolVar(&flagUsageEnable, "enable", false, "Opt in to local usage counting (with --usage)")
+	statsCmd.Flags().BoolVar(&flagUsageDisable, "disable", false, "Stop local usage counting and delete counters (with --usage)")
+	statsCmd.MarkFlagsMutuallyExclusive("enable", "disable")
+	addOutputFlag(statsCmd)
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if flagStatsUsage {
+		return runStatsUsage()
+	}
+	if flagUsageEnable || flagUsageDisable {
+		return fmt.Errorf("--enable and --disable require --usage")
+	}
+
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	report, err := stats.NewHandler(deps).Run(status.RepoRoot)
+	if err != nil {
+		return err
+	}
+
+	// Human-readable summary to stderr
+	if report.Limit > 0 {
+		fmt.Fprintf(env.Stderr, "Active pieces: %d (limit %d)\n", report.Active, report.Limit)
+	} else {
+		fmt.Fprintf(env.Stderr, "Active pieces: %d\n", report.Active)
+	}
+	for _, day := range report.Timeline {
+		fmt.Fprintf(env.Stderr, "%s  WIP %d  (+%d/-%d)\n", day.Date, day.WIP, day.Created, day.Removed)
+	}
+
+	// Output result to stdout, the timeline as the table
+	return printResult(report, render.Table{Rows: report.Timeline})
+}
+
+func runStatsUsage() error {
+	deps := newDeps()
+	handler := stats.NewHandler(deps)
+
+	var usage stats.Usage
+	var err error
+	switch {
+	case flagUsageEnable:
+		usage, err = handler.SetUsageTracking(true)
+	case flagUsageDisable:
+		usage, err = handler.SetUsageTracking(false)
+	default:
+		usage, err = handler.Usage()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Human-readable summary to stderr
+	if !usage.Enabled {
+		fmt.Fprintln(env.Stderr, "Usage counting is off; enable it with: mp stats --usage --enable")
+	} else {
+		fmt.Fprintf(env.Stderr, "Usage counted locally since %s\n", usage.Since)
+		for _, e := range usage.TopErrors() {
+			fmt.Fprintf(env.Stderr, "%5d  %s\n", e.Count, e.Type)
+		}
+	}
+
+	// Output result to stdout
+	return printResult(usage, render.Table{})
+}
+
+// recordUsage counts the executed command in the local usage counters, if enabled
+func recordUsage(cmd *cobra.Command, runErr error) {
+	if cmd == nil {
+		return
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+	if command == rootCmd.Name() {
+		return
+	}
+	deps := newDeps()
+	deps.Output = adapters.NewTextOutput(io.Discard)
+	stats.NewHandler(deps).RecordUsage(command, runErr)
+}
@@ -0,0 +1,72 @@
+package mp
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+var (
+	flagStatuslineRefresh    bool
+	flagStatuslineMainBranch string
+)
+
+var statuslineCmd = &cobra.Command{
+	Use:   "statusline",
+	Short: "Print a one-line piece summary for tmux or starship",
+	Long: `Prints the current piece, its issue, commits ahead/behind its base (* for
+uncommitted changes), and PR state on one line, e.g.
+
+  add-login #add-login ↑3↓1* PR#42 open
+
+Outside a piece it prints nothing. State is cached in the piece's metadata for a
+few seconds so it is cheap to run on every prompt; the PR state is refreshed in
+the background when it is older than two minutes. --refresh updates everything now.`,
+	Args: cobra.NoArgs,
+	RunE: runStatusline,
+}
+
+func init() {
+	statuslineCmd.Flags().BoolVar(&flagStatuslineRefresh, "refresh", false, "Recompute everything, including the PR state")
+	statuslineCmd.Flags().StringVar(&flagStatuslineMainBranch, "main-branch", "main", "Base branch for pieces without a recorded base")
+	rootCmd.AddCommand(statuslineCmd)
+}
+
+func runStatusline(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	line, err := piececmd.NewHandler(newDeps()).Statusline(wd, piececmd.StatuslineOptions{
+		MainBranch: flagStatuslineMainBranch,
+		Refresh:    flagStatuslineRefresh,
+	})
+	if err != nil || line == nil {
+		return err
+	}
+
+	if line.PRStale && !flagStatuslineRefresh {
+		refreshStatuslineInBackground(wd)
+	}
+	fmt.Fprintln(env.Stdout, line.String())
+	return nil
+}
+
+// refreshStatuslineInBackground starts `mp statusline --refresh` without waiting
+// for it, so a slow gh call never delays the status line
+func refreshStatuslineInBackground(wd string) {
+	mpPath, err := os.Executable()
+	if err != nil {
+		return
+	}
+	refresh := exec.Command(mpPath, "statusline", "--refresh", "--main-branch", flagStatuslineMainBranch)
+	refresh.Dir = wd
+	if refresh.Start() == nil {
+		_ = refresh.Process.Release()
+	}
+}
@@ -0,0 +1,81 @@
+package mp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	piececmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/watch"
+)
+
+var (
+	flagWatchInterval   time.Duration
+	flagWatchPRInterval time.Duration
+	flagWatchNoPRs      bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print issue, piece, and PR changes as NDJSON",
+	Long: `Watch the issues directory, pieces, and the PRs of pieces, printing one JSON
+event per line to stdout as they change, until interrupted:
+
+  issue.created, issue.status, issue.removed
+  piece.created, piece.removed
+  pr.opened, pr.state, pr.merged
+
+The state at startup produces no events. PRs are polled less often than
+issues and pieces, since each poll calls gh once per piece with a PR.
+
+Examples:
+  mp watch
+  mp watch --interval 5s --pr-interval 1m
+  mp watch --no-prs | jq -c 'select(.type == "issue.status")'`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&flagWatchInterval, "interval", watch.DefaultInterval, "Delay between issue and piece polls")
+	watchCmd.Flags().DurationVar(&flagWatchPRInterval, "pr-interval", watch.DefaultPRInterval, "Delay between PR polls")
+	watchCmd.Flags().BoolVar(&flagWatchNoPRs, "no-prs", false, "Don't poll PR states")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	wd, err := getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	deps := newDeps()
+
+	status, err := piececmd.NewHandler(deps).Status(wd)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if status.RepoRoot == "" {
+		return core.NewNotInRepoError()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	enc := json.NewEncoder(env.Stdout)
+	opts := watch.Options{
+		Interval:   flagWatchInterval,
+		PRInterval: flagWatchPRInterval,
+		NoPRs:      flagWatchNoPRs,
+	}
+	return watch.NewHandler(deps).Watch(ctx, status.RepoRoot, opts, func(e watch.Event) error {
+		return enc.Encode(e)
+	})
+}
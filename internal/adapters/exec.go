@@ -1,8 +1,11 @@
 package adapters
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -25,6 +28,11 @@ func NewOSExec() *OSExec {
 	return &OSExec{}
 }
 
+// ShellQuote quotes s for safe use as a single /bin/sh word
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // Run executes a command and returns its output
 func (e *OSExec) Run(name string, args ...string) ([]byte, error) {
 	cmd := exec.Command(name, args...)
@@ -58,12 +66,83 @@ func (e *OSExec) RunWithEnv(dir string, env []string, name string, args ...strin
 	return output, nil
 }
 
+// RunSplit executes a command in the specified directory, capturing stdout and
+// stderr separately so warnings on stderr cannot corrupt parsed output
+func (e *OSExec) RunSplit(dir string, env []string, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// maxStreamLine is the longest line RunStream passes to a callback; the rest
+// of a longer line is dropped
+const maxStreamLine = 1024 * 1024
+
+// RunStream executes a command, passing stdout and stderr to the callbacks line
+// by line as the command writes them, and waits for it to exit
+func (e *OSExec) RunStream(opts core.StreamOptions, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = opts.Dir
+	cmd.Env = opts.Env
+	cmd.Stdin = opts.Stdin
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// One lock for both streams so callbacks are never called concurrently
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		streamLines(stdout, &mu, opts.Stdout)
+	}()
+	go func() {
+		defer wg.Done()
+		streamLines(stderr, &mu, opts.Stderr)
+	}()
+	// Pipes must be drained before Wait closes them
+	wg.Wait()
+	return cmd.Wait()
+}
+
+// streamLines calls emit for each line read from r, then drains r
+func streamLines(r io.Reader, mu *sync.Mutex, emit func(string)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxStreamLine)
+	for scanner.Scan() {
+		if emit != nil {
+			mu.Lock()
+			emit(scanner.Text())
+			mu.Unlock()
+		}
+	}
+	// Keep the command from blocking on a full pipe after a scan error
+	_, _ = io.Copy(io.Discard, r)
+}
+
 // CallRecord represents a recorded command call
 type CallRecord struct {
 	Name string
 	Args []string
 	Dir  string
 	Env  []string
+	// Stdin is what RunStream read from StreamOptions.Stdin
+	Stdin []byte
 }
 
 // MockExec implements core.Exec for testing, recording calls and returning configurable outputs
@@ -75,9 +154,18 @@ type MockExec struct {
 
 type responseEntry struct {
 	output []byte
+	stderr []byte
 	err    error
 }
 
+// combined returns the output as CombinedOutput would: stdout, then stderr
+func (r responseEntry) combined() []byte {
+	if len(r.stderr) == 0 {
+		return r.output
+	}
+	return append(append([]byte(nil), r.output...), r.stderr...)
+}
+
 // NewMockExec creates a MockExec instance for testing
 func NewMockExec() *MockExec {
 	return &MockExec{
@@ -98,6 +186,19 @@ func (m *MockExec) AddResponse(name string, args []string, output []byte, err er
 	m.responses[name][key] = responseEntry{output: output, err: err}
 }
 
+// AddSplitResponse configures a mock response with separate stdout and stderr.
+// Run, RunWithDir, and RunWithEnv return both combined.
+func (m *MockExec) AddSplitResponse(name string, args []string, stdout, stderr []byte, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := strings.Join(args, " ")
+	if m.responses[name] == nil {
+		m.responses[name] = make(map[string]responseEntry)
+	}
+	m.responses[name][key] = responseEntry{output: stdout, stderr: stderr, err: err}
+}
+
 // Run executes a command and returns configured output or an error
 func (m *MockExec) Run(name string, args ...string) ([]byte, error) {
 	m.mu.Lock()
@@ -111,7 +212,7 @@ func (m *MockExec) Run(name string, args ...string) ([]byte, error) {
 
 	key := strings.Join(args, " ")
 	if resp, ok := m.responses[name][key]; ok {
-		return resp.output, resp.err
+		return resp.combined(), resp.err
 	}
 
 	// Default: return error indicating no response configured
@@ -132,7 +233,7 @@ func (m *MockExec) RunWithDir(dir, name string, args ...string) ([]byte, error)
 
 	key := strings.Join(args, " ")
 	if resp, ok := m.responses[name][key]; ok {
-		return resp.output, resp.err
+		return resp.combined(), resp.err
 	}
 
 	// Default: return error indicating no response configured
@@ -156,13 +257,79 @@ func (m *MockExec) RunWithEnv(dir string, env []string, name string, args ...str
 
 	key := strings.Join(args, " ")
 	if resp, ok := m.responses[name][key]; ok {
-		return resp.output, resp.err
+		return resp.combined(), resp.err
 	}
 
 	// Default: return error indicating no response configured
 	return nil, fmt.Errorf("no response configured for %s %s (dir: %s)", name, key, dir)
 }
 
+// RunSplit executes a command in the specified directory and returns configured
+// stdout and stderr or an error. Responses added with AddResponse have no stderr.
+func (m *MockExec) RunSplit(dir string, env []string, name string, args ...string) ([]byte, []byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir, _ = filepath.Abs(dir)
+	m.calls = append(m.calls, CallRecord{
+		Name: name,
+		Args: args,
+		Dir:  dir,
+		Env:  env,
+	})
+
+	key := strings.Join(args, " ")
+	if resp, ok := m.responses[name][key]; ok {
+		return resp.output, resp.stderr, resp.err
+	}
+
+	// Default: return error indicating no response configured
+	return nil, nil, fmt.Errorf("no response configured for %s %s (dir: %s)", name, key, dir)
+}
+
+// RunStream records the call, reading all of stdin, and passes the configured
+// stdout and stderr to the callbacks line by line
+func (m *MockExec) RunStream(opts core.StreamOptions, name string, args ...string) error {
+	var stdin []byte
+	if opts.Stdin != nil {
+		stdin, _ = io.ReadAll(opts.Stdin)
+	}
+
+	m.mu.Lock()
+	dir := opts.Dir
+	if dir != "" {
+		dir, _ = filepath.Abs(dir)
+	}
+	m.calls = append(m.calls, CallRecord{
+		Name:  name,
+		Args:  args,
+		Dir:   dir,
+		Env:   opts.Env,
+		Stdin: stdin,
+	})
+	key := strings.Join(args, " ")
+	resp, ok := m.responses[name][key]
+	m.mu.Unlock()
+
+	if !ok {
+		// Default: return error indicating no response configured
+		return fmt.Errorf("no response configured for %s %s (dir: %s)", name, key, dir)
+	}
+	emitLines(resp.output, opts.Stdout)
+	emitLines(resp.stderr, opts.Stderr)
+	return resp.err
+}
+
+// emitLines calls emit for each line of output
+func emitLines(output []byte, emit func(string)) {
+	if emit == nil || len(output) == 0 {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(string(output), "\n"), "\n") {
+		emit(line)
+	}
+}
+
 // WasCalled checks if a command was called with the specified arguments
 func (m *MockExec) WasCalled(name string, args ...string) bool {
 	m.mu.RLock()
@@ -0,0 +1,20 @@
+package adapters_test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+)
+
+func TestShellQuote(t *testing.T) {
+	for _, s := range []string{"", "plain", "two words", "it's", `$HOME "quoted" \back`} {
+		out, err := exec.Command("sh", "-c", "printf %s "+adapters.ShellQuote(s)).Output()
+		if err != nil {
+			t.Fatalf("sh failed for %q: %v", s, err)
+		}
+		if string(out) != s {
+			t.Errorf("expected %q to round-trip through sh, got %q", s, out)
+		}
+	}
+}
@@ -69,6 +69,10 @@ func (f *OSFS) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(f.path(path), perm)
 }
 
+func (f *OSFS) Mkdir(path string, perm os.FileMode) error {
+	return os.Mkdir(f.path(path), perm)
+}
+
 func (f *OSFS) WriteFile(name string, data []byte, perm os.FileMode) error {
 	return os.WriteFile(f.path(name), data, perm)
 }
@@ -89,6 +93,10 @@ func (f *OSFS) Symlink(oldname, newname string) error {
 	return os.Symlink(oldname, f.path(newname))
 }
 
+func (f *OSFS) Readlink(name string) (string, error) {
+	return os.Readlink(f.path(name))
+}
+
 func (f *OSFS) ReadDir(name string) ([]fs.DirEntry, error) {
 	return os.ReadDir(f.path(name))
 }
@@ -135,6 +143,23 @@ func (f *MemoryFS) MkdirAll(path string, perm os.FileMode) error {
 	return nil
 }
 
+// Mkdir creates path, failing with os.ErrExist if a file or directory is
+// already there. Unlike os.Mkdir, missing parents are not an error.
+func (f *MemoryFS) Mkdir(path string, perm os.FileMode) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := filepath.Clean(path)
+	if filepath.IsAbs(name) && len(name) > 1 {
+		name = name[1:] // Match how MkdirAll stores paths
+	}
+	if _, ok := f.files[name]; ok || f.dirs[name] {
+		return &os.PathError{Op: "mkdir", Path: path, Err: os.ErrExist}
+	}
+	f.dirs[name] = true
+	return nil
+}
+
 func (f *MemoryFS) WriteFile(name string, data []byte, perm os.FileMode) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -229,6 +254,25 @@ func (f *MemoryFS) Symlink(oldname, newname string) error {
 	return nil
 }
 
+func (f *MemoryFS) Readlink(name string) (string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	name = filepath.Clean(name)
+	// Normalize path to match how Symlink stores it
+	if filepath.IsAbs(name) && len(name) > 1 {
+		name = name[1:]
+	}
+	file, ok := f.files[name]
+	if !ok {
+		return "", &os.PathError{Op: "readlink", Path: name, Err: os.ErrNotExist}
+	}
+	if file.mode&os.ModeSymlink == 0 {
+		return "", &os.PathError{Op: "readlink", Path: name, Err: os.ErrInvalid}
+	}
+	return string(file.data), nil
+}
+
 func (f *MemoryFS) ReadDir(name string) ([]fs.DirEntry, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
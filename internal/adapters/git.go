@@ -4,42 +4,208 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
 )
 
+// DefaultRemote is the remote used when none is configured
+const DefaultRemote = "origin"
+
 // Git provides git operations using an Exec interface
 type Git struct {
-	exec core.Exec
+	exec   core.Exec
+	remote string
 }
 
 // NewGit creates a Git adapter with the provided Exec interface
 func NewGit(exec core.Exec) *Git {
-	return &Git{exec: exec}
+	return &Git{exec: exec, remote: DefaultRemote}
+}
+
+// SetRemote sets the remote used for remote operations. Empty keeps the current remote.
+func (g *Git) SetRemote(remote string) {
+	if remote != "" {
+		g.remote = remote
+	}
+}
+
+// Remote returns the remote used for remote operations
+func (g *Git) Remote() string {
+	return g.remote
+}
+
+// run runs git in workDir and returns its stdout. When git fails, stderr is
+// appended so callers can report and classify the failure.
+func (g *Git) run(workDir string, args ...string) ([]byte, error) {
+	stdout, stderr, err := g.exec.RunSplit(workDir, nil, "git", args...)
+	if err != nil {
+		return append(stdout, stderr...), err
+	}
+	return stdout, nil
 }
 
 // WorktreeAdd creates a new git worktree at the specified path
 func (g *Git) WorktreeAdd(repoRoot, worktreePath string) error {
-	_, err := g.exec.RunWithDir(repoRoot, "git", "worktree", "add", worktreePath)
+	_, err := g.run(repoRoot, "worktree", "add", worktreePath)
 	if err != nil {
 		return fmt.Errorf("failed to create worktree at %s from repo %s: %w", worktreePath, repoRoot, err)
 	}
 	return nil
 }
 
+// WorktreeAddBranch creates a git worktree at the specified path on a new
+// branch named branch
+func (g *Git) WorktreeAddBranch(repoRoot, worktreePath, branch string) error {
+	_, err := g.run(repoRoot, "worktree", "add", "-b", branch, worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to create worktree at %s on branch %s from repo %s: %w", worktreePath, branch, repoRoot, err)
+	}
+	return nil
+}
+
+// WorktreeAddExisting creates a git worktree at the specified path with the
+// existing branch checked out
+func (g *Git) WorktreeAddExisting(repoRoot, worktreePath, branch string) error {
+	_, err := g.run(repoRoot, "worktree", "add", worktreePath, branch)
+	if err != nil {
+		return fmt.Errorf("failed to create worktree at %s for branch %s from repo %s: %w", worktreePath, branch, repoRoot, err)
+	}
+	return nil
+}
+
+// DeleteBranch force-deletes the local branch, e.g. one created for a piece
+// that was rolled back
+func (g *Git) DeleteBranch(repoRoot, branch string) error {
+	output, err := g.run(repoRoot, "branch", "-D", branch)
+	if err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w\n%s", branch, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // WorktreeRemove removes a git worktree
 func (g *Git) WorktreeRemove(repoRoot, worktreePath string) error {
-	_, err := g.exec.RunWithDir(repoRoot, "git", "worktree", "remove", worktreePath)
+	output, err := g.run(repoRoot, "worktree", "remove", worktreePath)
+	if err != nil {
+		return classifyGitError(output, worktreePath, "",
+			fmt.Errorf("failed to remove worktree at %s from repo %s: %w", worktreePath, repoRoot, err))
+	}
+	return nil
+}
+
+// WorktreeRemoveForce runs git worktree remove --force, discarding uncommitted changes
+func (g *Git) WorktreeRemoveForce(repoRoot, worktreePath string) error {
+	_, err := g.run(repoRoot, "worktree", "remove", "--force", worktreePath)
 	if err != nil {
 		return fmt.Errorf("failed to remove worktree at %s from repo %s: %w", worktreePath, repoRoot, err)
 	}
 	return nil
 }
 
+// WorktreePrune runs git worktree prune, removing administrative data of worktrees
+// whose directories are gone. Returns the pruned entries reported by git.
+func (g *Git) WorktreePrune(repoRoot string) ([]string, error) {
+	output, err := g.run(repoRoot, "worktree", "prune", "--verbose")
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+
+	var pruned []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			pruned = append(pruned, line)
+		}
+	}
+	return pruned, nil
+}
+
+// GC runs git gc in gitDir, repacking the objects of the repository and every
+// worktree sharing it and pruning unreachable ones
+func (g *Git) GC(gitDir string) error {
+	output, err := g.run(gitDir, "gc", "--quiet")
+	if err != nil {
+		return fmt.Errorf("git gc failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Worktree is an entry of git worktree list
+type Worktree struct {
+	Path   string `json:"path"`
+	Head   string `json:"head,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Bare   bool   `json:"bare,omitempty"`
+	// Locked worktrees are protected from pruning and removal; LockReason may be empty
+	Locked     bool   `json:"locked,omitempty"`
+	LockReason string `json:"lock_reason,omitempty"`
+	// Prunable worktrees have lost their directory and can be pruned
+	Prunable       bool   `json:"prunable,omitempty"`
+	PrunableReason string `json:"prunable_reason,omitempty"`
+}
+
+// WorktreeList returns the worktrees of a repository, the main worktree first
+func (g *Git) WorktreeList(repoRoot string) ([]Worktree, error) {
+	output, err := g.run(repoRoot, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var worktrees []Worktree
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, _ := strings.Cut(strings.TrimRight(line, "\r"), " ")
+		if key == "worktree" {
+			worktrees = append(worktrees, Worktree{Path: value})
+			continue
+		}
+		if len(worktrees) == 0 {
+			continue
+		}
+		wt := &worktrees[len(worktrees)-1]
+		switch key {
+		case "HEAD":
+			wt.Head = value
+		case "branch":
+			wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			wt.Bare = true
+		case "locked":
+			wt.Locked = true
+			wt.LockReason = value
+		case "prunable":
+			wt.Prunable = true
+			wt.PrunableReason = value
+		}
+	}
+	return worktrees, nil
+}
+
+// WorktreeLock locks a worktree so git refuses to prune, move, or remove it
+func (g *Git) WorktreeLock(repoRoot, worktreePath, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	output, err := g.run(repoRoot, append(args, worktreePath)...)
+	if err != nil {
+		return fmt.Errorf("failed to lock worktree %s: %s: %w", worktreePath, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// WorktreeUnlock unlocks a worktree locked with WorktreeLock
+func (g *Git) WorktreeUnlock(repoRoot, worktreePath string) error {
+	output, err := g.run(repoRoot, "worktree", "unlock", worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to unlock worktree %s: %s: %w", worktreePath, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
 // RevParseGitDir runs git rev-parse --git-dir to get the git directory.
 // Returns the absolute path to the .git directory or worktree gitdir.
 func (g *Git) RevParseGitDir(workDir string) (string, error) {
-	output, err := g.exec.RunWithDir(workDir, "git", "rev-parse", "--git-dir")
+	output, err := g.run(workDir, "rev-parse", "--git-dir")
 	if err != nil {
 		return "", fmt.Errorf("failed to get git dir: %w", err)
 	}
@@ -52,19 +218,50 @@ func (g *Git) RevParseGitDir(workDir string) (string, error) {
 	return gitDir, nil
 }
 
-// IsWorktree checks if the git directory indicates a worktree
-// Worktrees have .git directories that are either:
-// - Files containing "gitdir: /path/to/main/.git/worktrees/name"
-// - Directories under .git/worktrees/
+// RevParseGitCommonDir runs git rev-parse --git-common-dir to get the git
+// directory shared by all worktrees of a repository, e.g. /repo/.git from a
+// linked worktree, or the bare repository. Returns an absolute path.
+func (g *Git) RevParseGitCommonDir(workDir string) (string, error) {
+	output, err := g.run(workDir, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git common dir: %w", err)
+	}
+	commonDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(workDir, commonDir)
+	}
+	commonDir, _ = filepath.Abs(commonDir)
+	return commonDir, nil
+}
+
+// HooksDir runs git rev-parse --git-path hooks to get the directory git runs
+// hooks from, shared by all worktrees and honouring core.hooksPath. Returns an
+// absolute path.
+func (g *Git) HooksDir(workDir string) (string, error) {
+	output, err := g.run(workDir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("failed to get hooks dir: %w", err)
+	}
+	hooksDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(workDir, hooksDir)
+	}
+	hooksDir, _ = filepath.Abs(hooksDir)
+	return hooksDir, nil
+}
+
+// IsWorktree checks if the git directory indicates a linked worktree, whose
+// git dir is <common dir>/worktrees/<name> wherever the common dir lives
+// (.git, a --separate-git-dir, or a bare repository)
 func (g *Git) IsWorktree(gitDir string) bool {
 	absGitDir, _ := filepath.Abs(gitDir)
-	return strings.Contains(absGitDir, "worktrees") || filepath.Base(filepath.Dir(absGitDir)) == "worktrees"
+	return filepath.Base(filepath.Dir(absGitDir)) == "worktrees"
 }
 
 // RepoRoot runs git rev-parse --show-toplevel to get the repository root.
 // Returns the absolute path to the top-level directory of the git repository.
 func (g *Git) RepoRoot(workDir string) (string, error) {
-	output, err := g.exec.RunWithDir(workDir, "git", "rev-parse", "--show-toplevel")
+	output, err := g.run(workDir, "rev-parse", "--show-toplevel")
 	if err != nil {
 		return "", fmt.Errorf("failed to get repo root: %w", err)
 	}
@@ -76,19 +273,45 @@ func (g *Git) RepoRoot(workDir string) (string, error) {
 // CurrentBranch gets the current branch name.
 // Returns the short name of the current branch (e.g., "main", "piece-1").
 func (g *Git) CurrentBranch(workDir string) (string, error) {
-	output, err := g.exec.RunWithDir(workDir, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	output, err := g.run(workDir, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 	branch := strings.TrimSpace(string(output))
+	if branch == "HEAD" {
+		return "", core.NewDetachedHeadError(workDir)
+	}
 	return branch, nil
 }
 
 // Merge merges the specified branch into the current branch
 func (g *Git) Merge(workDir, branch string) error {
-	_, err := g.exec.RunWithDir(workDir, "git", "merge", branch)
+	output, err := g.run(workDir, "merge", branch)
 	if err != nil {
-		return fmt.Errorf("failed to merge branch %s in %s: %w", branch, workDir, err)
+		return classifyGitError(output, workDir, branch,
+			fmt.Errorf("failed to merge branch %s in %s: %w", branch, workDir, err))
+	}
+	return nil
+}
+
+// MergeNoFF merges branch into the current branch with a merge commit, even
+// when it could fast-forward
+func (g *Git) MergeNoFF(workDir, branch, message string) error {
+	output, err := g.run(workDir, "merge", "--no-ff", "-m", message, branch)
+	if err != nil {
+		return classifyGitError(output, workDir, branch,
+			fmt.Errorf("failed to merge branch %s in %s: %w\n%s", branch, workDir, err, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// MergeFFOnly fast-forwards the current branch to branch, failing when the
+// current branch has commits branch lacks
+func (g *Git) MergeFFOnly(workDir, branch string) error {
+	output, err := g.run(workDir, "merge", "--ff-only", branch)
+	if err != nil {
+		return classifyGitError(output, workDir, branch,
+			fmt.Errorf("failed to fast-forward to %s in %s: %w\n%s", branch, workDir, err, strings.TrimSpace(string(output))))
 	}
 	return nil
 }
@@ -97,14 +320,14 @@ func (g *Git) Merge(workDir, branch string) error {
 // Returns true if main is ahead (has commits not in piece), false otherwise
 func (g *Git) IsMainAhead(workDir, mainBranch, pieceBranch string) (bool, error) {
 	// Get the merge-base between main and piece branch
-	output, err := g.exec.RunWithDir(workDir, "git", "merge-base", mainBranch, pieceBranch)
+	output, err := g.run(workDir, "merge-base", mainBranch, pieceBranch)
 	if err != nil {
-		return false, fmt.Errorf("failed to find merge-base: %w", err)
+		return false, classifyGitError(output, workDir, mainBranch, fmt.Errorf("failed to find merge-base: %w", err))
 	}
 	mergeBase := strings.TrimSpace(string(output))
 
 	// Check if main has commits ahead of the merge-base
-	output, err = g.exec.RunWithDir(workDir, "git", "rev-list", "--count", mergeBase+".."+mainBranch)
+	output, err = g.run(workDir, "rev-list", "--count", mergeBase+".."+mainBranch)
 	if err != nil {
 		return false, fmt.Errorf("failed to count commits: %w", err)
 	}
@@ -114,35 +337,179 @@ func (g *Git) IsMainAhead(workDir, mainBranch, pieceBranch string) (bool, error)
 	return count != "0", nil
 }
 
+// AheadBehind counts the commits branch has that base lacks (ahead) and the
+// commits base has that branch lacks (behind)
+func (g *Git) AheadBehind(workDir, base, branch string) (ahead, behind int, err error) {
+	output, err := g.run(workDir, "rev-list", "--left-right", "--count", base+"..."+branch)
+	if err != nil {
+		return 0, 0, classifyGitError(output, workDir, base, fmt.Errorf("failed to count commits: %w", err))
+	}
+	if _, err := fmt.Sscan(string(output), &behind, &ahead); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse commit counts %q: %w", strings.TrimSpace(string(output)), err)
+	}
+	return ahead, behind, nil
+}
+
+// BranchExists checks if a local branch exists
+func (g *Git) BranchExists(workDir, branch string) bool {
+	_, err := g.run(workDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// MergeBase returns the best common ancestor of two commits.
+// Returns an empty string if the commits share no history.
+func (g *Git) MergeBase(workDir, a, b string) (string, error) {
+	output, err := g.run(workDir, "merge-base", a, b)
+	if err != nil {
+		// merge-base exits 1 with no output when there is no common ancestor
+		if strings.TrimSpace(string(output)) == "" {
+			return "", nil
+		}
+		return "", classifyGitError(output, workDir, a, fmt.Errorf("failed to find merge-base: %w", err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// MergeConflicts predicts the files that would conflict when merging branch
+// into base, using `git merge-tree` so no worktree or index is touched.
+// Requires git 2.38 or later.
+func (g *Git) MergeConflicts(workDir, base, branch string) ([]string, error) {
+	output, err := g.run(workDir, "merge-tree", "--write-tree", "--name-only", "--no-messages", base, branch)
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if err == nil {
+		return nil, nil
+	}
+	// A conflicted merge exits 1 and prints the tree id followed by conflicted files
+	if !isObjectID(lines[0]) {
+		return nil, classifyGitError(output, workDir, base, fmt.Errorf("failed to predict merge conflicts: %s: %w", strings.TrimSpace(string(output)), err))
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, line := range lines[1:] {
+		file := strings.TrimSpace(line)
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// isObjectID reports whether s looks like a full SHA-1 or SHA-256 object id
+func isObjectID(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// MainWorktreeConfig is the git config key recording the main worktree of a
+// repository cloned with --separate-git-dir, which git itself doesn't track
+const MainWorktreeConfig = "monkeypuzzle.mainWorktree"
+
 // GetMainRepoRoot gets the main repository root from a worktree.
-// For worktrees, this finds the main repo by examining the gitdir structure.
-// For regular repositories, it returns the same as RepoRoot.
-func (g *Git) GetMainRepoRoot(workDir string) (string, error) {
+// For regular repositories, it returns the same as RepoRoot. For linked
+// worktrees it asks git for the main worktree (the first one git worktree list
+// prints) rather than deriving it from the git dir's path. Git can't tell
+// where the main worktree of a --separate-git-dir repository is, so it is read
+// from MainWorktreeConfig. A bare repository has no main worktree; the first
+// of its worktrees listed (git sorts them by path) outside piecesDir stands
+// in for it, so a piece never does.
+func (g *Git) GetMainRepoRoot(workDir, piecesDir string) (string, error) {
 	gitDir, err := g.RevParseGitDir(workDir)
 	if err != nil {
 		return "", err
 	}
+	commonDir, err := g.RevParseGitCommonDir(workDir)
+	if err != nil {
+		return "", err
+	}
 
-	// If it's a worktree, the gitdir will be in .git/worktrees/<name>
-	// The main repo root is the parent of .git
-	if g.IsWorktree(gitDir) {
-		// For worktrees, gitDir is something like /repo/.git/worktrees/piece-1
-		// We need to go up to /repo/.git, then to /repo
-		mainGitDir := filepath.Dir(filepath.Dir(gitDir))
-		mainRepoRoot := filepath.Dir(mainGitDir)
-		mainRepoRoot, _ = filepath.Abs(mainRepoRoot)
+	// Only linked worktrees have a git dir of their own
+	if gitDir == commonDir {
+		return g.RepoRoot(workDir)
+	}
+
+	worktrees, err := g.WorktreeList(workDir)
+	if err != nil {
+		return "", err
+	}
+	// Without a main worktree git lists the common dir in its place
+	if len(worktrees) > 0 && !worktrees[0].Bare && filepath.Clean(worktrees[0].Path) != commonDir {
+		mainRepoRoot, _ := filepath.Abs(worktrees[0].Path)
 		return mainRepoRoot, nil
 	}
 
-	// Not a worktree, just return the repo root
-	return g.RepoRoot(workDir)
+	if output, err := g.run(workDir, "config", "--get", MainWorktreeConfig); err == nil {
+		return filepath.Abs(strings.TrimSpace(string(output)))
+	}
+	if len(worktrees) > 0 && worktrees[0].Bare {
+		for _, wt := range worktrees[1:] {
+			if !wt.Prunable && !isInDir(wt.Path, piecesDir) {
+				mainRepoRoot, _ := filepath.Abs(wt.Path)
+				return mainRepoRoot, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to find the main worktree of %s: run mp in it once, or set it with `git config %s <path>`",
+		commonDir, MainWorktreeConfig)
+}
+
+// isInDir reports whether path is dir or inside it; nothing is inside an
+// empty dir. git may report dir with symlinks resolved.
+func isInDir(path, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	dirs := []string{filepath.Clean(dir)}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dirs = append(dirs, resolved)
+	}
+	for _, d := range dirs {
+		if rel, err := filepath.Rel(d, filepath.Clean(path)); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordMainWorktree records repoRoot, a main worktree whose git dir is not
+// repoRoot/.git, in MainWorktreeConfig so linked worktrees can find it. Other
+// repositories are left alone.
+func (g *Git) RecordMainWorktree(repoRoot string) error {
+	gitDir, err := g.RevParseGitDir(repoRoot)
+	if err != nil {
+		return err
+	}
+	commonDir, err := g.RevParseGitCommonDir(repoRoot)
+	if err != nil {
+		return err
+	}
+	if gitDir != commonDir || commonDir == filepath.Join(repoRoot, ".git") {
+		return nil
+	}
+	if output, err := g.run(repoRoot, "config", "--get", MainWorktreeConfig); err == nil && strings.TrimSpace(string(output)) == repoRoot {
+		return nil
+	}
+	if _, err := g.run(repoRoot, "config", MainWorktreeConfig, repoRoot); err != nil {
+		return fmt.Errorf("failed to record main worktree: %w", err)
+	}
+	return nil
 }
 
 // Checkout switches to the specified branch
 func (g *Git) Checkout(workDir, branch string) error {
-	_, err := g.exec.RunWithDir(workDir, "git", "checkout", branch)
+	output, err := g.run(workDir, "checkout", branch)
 	if err != nil {
-		return fmt.Errorf("failed to checkout branch %s in %s: %w", branch, workDir, err)
+		return classifyGitError(output, workDir, branch,
+			fmt.Errorf("failed to checkout branch %s in %s: %w", branch, workDir, err))
 	}
 	return nil
 }
@@ -150,7 +517,7 @@ func (g *Git) Checkout(workDir, branch string) error {
 // MergeSquash performs a squash merge of the specified branch into the current branch.
 // This stages all changes but does not commit - caller must commit with desired message.
 func (g *Git) MergeSquash(workDir, branch string) error {
-	_, err := g.exec.RunWithDir(workDir, "git", "merge", "--squash", branch)
+	_, err := g.run(workDir, "merge", "--squash", branch)
 	if err != nil {
 		return fmt.Errorf("failed to squash merge branch %s in %s: %w", branch, workDir, err)
 	}
@@ -159,16 +526,28 @@ func (g *Git) MergeSquash(workDir, branch string) error {
 
 // Commit creates a commit with the specified message
 func (g *Git) Commit(workDir, message string) error {
-	_, err := g.exec.RunWithDir(workDir, "git", "commit", "-m", message)
+	_, err := g.run(workDir, "commit", "-m", message)
 	if err != nil {
 		return fmt.Errorf("failed to commit in %s: %w", workDir, err)
 	}
 	return nil
 }
 
+// CommitAll stages every change in the working tree, untracked files
+// included, and commits it without running the commit hooks
+func (g *Git) CommitAll(workDir, message string) error {
+	if output, err := g.run(workDir, "add", "-A"); err != nil {
+		return fmt.Errorf("failed to stage changes in %s: %w\n%s", workDir, err, strings.TrimSpace(string(output)))
+	}
+	if output, err := g.run(workDir, "commit", "--no-verify", "-m", message); err != nil {
+		return fmt.Errorf("failed to commit in %s: %w\n%s", workDir, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // GetCommitMessages returns commit messages from branch that are not in base
 func (g *Git) GetCommitMessages(workDir, base, branch string) ([]string, error) {
-	output, err := g.exec.RunWithDir(workDir, "git", "log", "--format=%s", base+".."+branch)
+	output, err := g.run(workDir, "log", "--format=%s", base+".."+branch)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit messages: %w", err)
 	}
@@ -183,12 +562,115 @@ func (g *Git) GetCommitMessages(workDir, base, branch string) ([]string, error)
 	return messages, nil
 }
 
+// Commit is a commit in a CommitLog
+type Commit struct {
+	Hash        string `json:"hash"`
+	Author      string `json:"author"`
+	AuthorEmail string `json:"author_email"`
+	// Time is the author date
+	Time    time.Time `json:"time,omitzero"`
+	Subject string    `json:"subject"`
+	// Body is the message after the subject, without the trailer block
+	Body     string    `json:"body,omitempty"`
+	Trailers []Trailer `json:"trailers,omitempty"`
+}
+
+// Trailer is a "Key: value" line at the end of a commit message, e.g.
+// Co-authored-by: Name <email>
+type Trailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// String formats the trailer as it appears in a commit message
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// CommitLogFormat is the git log --format of CommitLog: per commit a record
+// separator, then hash, author name, email and date, subject, body and
+// trailers separated by unit separators
+const CommitLogFormat = "%x1e%H%x1f%an%x1f%ae%x1f%aI%x1f%s%x1f%b%x1f%(trailers:only,unfold)"
+
+const (
+	logRecordSep = "\x1e"
+	logFieldSep  = "\x1f"
+)
+
+// CommitLog returns the commits on branch that are not in base, newest first
+func (g *Git) CommitLog(workDir, base, branch string) ([]Commit, error) {
+	output, err := g.run(workDir, "log", "--format="+CommitLogFormat, base+".."+branch)
+	if err != nil {
+		return nil, classifyGitError(output, workDir, base, fmt.Errorf("failed to get commit log: %w", err))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), logRecordSep) {
+		fields := strings.Split(record, logFieldSep)
+		if len(fields) != 7 {
+			continue
+		}
+		commit := Commit{
+			Hash:        strings.TrimSpace(fields[0]),
+			Author:      fields[1],
+			AuthorEmail: fields[2],
+			Subject:     fields[4],
+		}
+		commit.Time, _ = time.Parse(time.RFC3339, fields[3])
+		for _, line := range strings.Split(fields[6], "\n") {
+			if key, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) != "" {
+				commit.Trailers = append(commit.Trailers, Trailer{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+			}
+		}
+		commit.Body = stripTrailers(strings.TrimSpace(fields[5]), len(commit.Trailers))
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// stripTrailers removes the trailer block, the last paragraph of a commit
+// body, when the commit has trailers
+func stripTrailers(body string, trailers int) string {
+	if trailers == 0 {
+		return body
+	}
+	if i := strings.LastIndex(body, "\n\n"); i >= 0 {
+		return strings.TrimSpace(body[:i])
+	}
+	return ""
+}
+
+// ChangedFiles lists the files branch changes since it diverged from base
+func (g *Git) ChangedFiles(workDir, base, branch string) ([]string, error) {
+	output, err := g.run(workDir, "diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, classifyGitError(output, workDir, base, fmt.Errorf("failed to list changed files: %w", err))
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// Diff returns the changes on HEAD since it diverged from base
+func (g *Git) Diff(workDir, base string) (string, error) {
+	output, err := g.run(workDir, "diff", base+"...HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to diff against %s: %w", base, err)
+	}
+	return string(output), nil
+}
+
 // IsBranchMerged checks if branchName is merged into mainBranch.
 // Uses git branch --merged to detect merged branches.
 func (g *Git) IsBranchMerged(workDir, mainBranch, branchName string) (bool, error) {
-	output, err := g.exec.RunWithDir(workDir, "git", "branch", "--merged", mainBranch)
+	output, err := g.run(workDir, "branch", "--merged", mainBranch)
 	if err != nil {
-		return false, fmt.Errorf("failed to list merged branches: %w", err)
+		return false, classifyGitError(output, workDir, mainBranch, fmt.Errorf("failed to list merged branches: %w", err))
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
@@ -204,16 +686,80 @@ func (g *Git) IsBranchMerged(workDir, mainBranch, branchName string) (bool, erro
 
 // BranchExistsOnRemote checks if a branch exists on the remote.
 func (g *Git) BranchExistsOnRemote(workDir, branchName string) (bool, error) {
-	output, err := g.exec.RunWithDir(workDir, "git", "ls-remote", "--heads", "origin", branchName)
+	output, err := g.run(workDir, "ls-remote", "--heads", g.remote, branchName)
 	if err != nil {
 		return false, fmt.Errorf("failed to check remote branches: %w", err)
 	}
 	return strings.TrimSpace(string(output)) != "", nil
 }
 
+// RemoteBranchCommit returns the commit a branch points to on the remote.
+// Returns an empty string if the branch does not exist on the remote.
+func (g *Git) RemoteBranchCommit(workDir, branchName string) (string, error) {
+	output, err := g.run(workDir, "ls-remote", "--heads", g.remote, branchName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check remote branches: %w", err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+// HasRemoteTrackingBranch checks if a local <remote>/<branch> tracking ref exists,
+// meaning the branch was pushed or fetched at some point.
+func (g *Git) HasRemoteTrackingBranch(workDir, branchName string) bool {
+	_, err := g.run(workDir, "rev-parse", "--verify", "--quiet", "refs/remotes/"+g.remote+"/"+branchName)
+	return err == nil
+}
+
+// FetchBranch fetches a single branch from the remote
+func (g *Git) FetchBranch(workDir, branchName string) error {
+	_, err := g.run(workDir, "fetch", g.remote, branchName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w", branchName, g.remote, err)
+	}
+	return nil
+}
+
+// PushBranch pushes a local branch to the same branch on the remote.
+// A push refused by GitHub branch protection is a protected_branch error.
+func (g *Git) PushBranch(workDir, branchName string) error {
+	output, err := g.run(workDir, "push", g.remote, branchName)
+	if err != nil {
+		err = fmt.Errorf("failed to push %s to %s: %w\n%s", branchName, g.remote, err, strings.TrimSpace(string(output)))
+		for _, fragment := range gitProtectedOutputs {
+			if strings.Contains(string(output), fragment) {
+				return core.NewProtectedBranchError(branchName, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// ResetHard resets the current branch and working tree to ref
+func (g *Git) ResetHard(workDir, ref string) error {
+	_, err := g.run(workDir, "reset", "--hard", ref)
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", ref, err)
+	}
+	return nil
+}
+
+// HasUncommittedChanges checks if the working tree has staged, unstaged, or untracked changes
+func (g *Git) HasUncommittedChanges(workDir string) (bool, error) {
+	output, err := g.run(workDir, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to check working tree status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
 // GetBranchCommit returns the commit hash of a branch.
 func (g *Git) GetBranchCommit(workDir, branchName string) (string, error) {
-	output, err := g.exec.RunWithDir(workDir, "git", "rev-parse", branchName)
+	output, err := g.run(workDir, "rev-parse", branchName)
 	if err != nil {
 		return "", fmt.Errorf("failed to get branch commit: %w", err)
 	}
@@ -223,7 +769,7 @@ func (g *Git) GetBranchCommit(workDir, branchName string) (string, error) {
 // IsCommitInBranch checks if a commit exists in a branch's history.
 func (g *Git) IsCommitInBranch(workDir, commit, branch string) (bool, error) {
 	// git merge-base --is-ancestor <commit> <branch> returns 0 if true
-	_, err := g.exec.RunWithDir(workDir, "git", "merge-base", "--is-ancestor", commit, branch)
+	_, err := g.run(workDir, "merge-base", "--is-ancestor", commit, branch)
 	if err != nil {
 		// Exit code 1 means not an ancestor, other errors are real errors
 		if strings.Contains(err.Error(), "exit status 1") {
@@ -233,3 +779,109 @@ func (g *Git) IsCommitInBranch(workDir, commit, branch string) (bool, error) {
 	}
 	return true, nil
 }
+
+// BisectStart starts a bisect in workDir between a bad and a good commit
+func (g *Git) BisectStart(workDir, bad, good string) error {
+	output, err := g.run(workDir, "bisect", "start", bad, good)
+	if err != nil {
+		return classifyGitError(output, workDir, good, fmt.Errorf("failed to start bisect: %w\n%s", err, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// BisectRun runs cmd with sh at each step of the bisect in workDir, with env
+// added to the environment, and returns git's output, which names the first
+// bad commit when one was found
+func (g *Git) BisectRun(workDir string, env []string, cmd string) (string, error) {
+	output, err := g.exec.RunWithEnv(workDir, env, "git", "bisect", "run", "sh", "-c", cmd)
+	return string(output), err
+}
+
+// BisectReset ends the bisect in workDir, checking out what was checked out
+// before it started
+func (g *Git) BisectReset(workDir string) error {
+	output, err := g.run(workDir, "bisect", "reset")
+	if err != nil {
+		return fmt.Errorf("failed to reset bisect: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// MergeCommits returns the merge commits on branch that are not in base
+func (g *Git) MergeCommits(workDir, base, branch string) ([]string, error) {
+	output, err := g.run(workDir, "rev-list", "--merges", base+".."+branch)
+	if err != nil {
+		return nil, classifyGitError(output, workDir, base, fmt.Errorf("failed to list merge commits: %w", err))
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// Rebase runs git rebase -i onto upstream in workDir with env added to the
+// environment, which sets the editors that supply the todo list, and
+// returns git's output
+func (g *Git) Rebase(workDir string, env []string, upstream string) (string, error) {
+	output, err := g.exec.RunWithEnv(workDir, env, "git", "rebase", "-i", "--no-autosquash", upstream)
+	return string(output), err
+}
+
+// RebaseOnto replays the commits of the current branch that upstream lacks
+// on top of upstream, leaving out merge commits
+func (g *Git) RebaseOnto(workDir, upstream string) error {
+	output, err := g.run(workDir, "rebase", upstream)
+	if err != nil {
+		return classifyGitError(output, workDir, upstream,
+			fmt.Errorf("failed to rebase onto %s in %s: %w\n%s", upstream, workDir, err, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// RebaseAbort abandons the rebase in progress in workDir
+func (g *Git) RebaseAbort(workDir string) error {
+	output, err := g.run(workDir, "rebase", "--abort")
+	if err != nil {
+		return fmt.Errorf("failed to abort rebase: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Fragments of git output identifying failures with a known fix
+var (
+	gitMissingRefOutputs = []string{
+		"not something we can merge",
+		"did not match any file(s) known to git",
+		"invalid reference",
+		"Not a valid object name",
+		"malformed object name",
+		"unknown revision",
+	}
+	gitDirtyOutputs = []string{
+		"contains modified or untracked files",
+		"would be overwritten by",
+		"commit your changes or stash them",
+	}
+	// GitHub refusing a push to a protected branch
+	gitProtectedOutputs = []string{
+		"GH006: Protected branch update failed",
+		"GH013: Repository rule violations found",
+		"protected branch hook declined",
+	}
+)
+
+// classifyGitError attaches a remediation hint to err when git's output shows
+// a missing branch or uncommitted changes in workDir. Otherwise err is returned as is.
+func classifyGitError(output []byte, workDir, branch string, err error) error {
+	out := string(output)
+	for _, fragment := range gitDirtyOutputs {
+		if strings.Contains(out, fragment) {
+			return core.NewDirtyWorktreeError(workDir, err)
+		}
+	}
+	if branch != "" {
+		for _, fragment := range gitMissingRefOutputs {
+			if strings.Contains(out, fragment) {
+				return core.NewMainBranchMissingError(branch, err)
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package adapters_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+)
+
+func TestGit_WrapsExecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		call func(g *adapters.Git) error
+		// succeeds lists git commands that run successfully before the failing one
+		succeeds [][]string
+	}{
+		{"bisect start", []string{"bisect", "start", "bad", "good"}, func(g *adapters.Git) error { return g.BisectStart("/repo", "bad", "good") }, nil},
+		{"bisect reset", []string{"bisect", "reset"}, func(g *adapters.Git) error { return g.BisectReset("/repo") }, nil},
+		{"stage all", []string{"add", "-A"}, func(g *adapters.Git) error { return g.CommitAll("/repo", "wip") }, nil},
+		{"commit all", []string{"commit", "--no-verify", "-m", "wip"}, func(g *adapters.Git) error { return g.CommitAll("/repo", "wip") }, [][]string{{"add", "-A"}}},
+		{"merge no-ff", []string{"merge", "--no-ff", "-m", "msg", "p1"}, func(g *adapters.Git) error { return g.MergeNoFF("/repo", "p1", "msg") }, nil},
+		{"merge ff-only", []string{"merge", "--ff-only", "p1"}, func(g *adapters.Git) error { return g.MergeFFOnly("/repo", "p1") }, nil},
+		{"rebase onto", []string{"rebase", "main"}, func(g *adapters.Git) error { return g.RebaseOnto("/repo", "main") }, nil},
+		{"push", []string{"push", "origin", "p1"}, func(g *adapters.Git) error { return g.PushBranch("/repo", "p1") }, nil},
+		{"rebase abort", []string{"rebase", "--abort"}, func(g *adapters.Git) error { return g.RebaseAbort("/repo") }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execErr := errors.New("exit status 128")
+			mockExec := adapters.NewMockExec()
+			for _, args := range tt.succeeds {
+				mockExec.AddResponse("git", args, nil, nil)
+			}
+			mockExec.AddResponse("git", tt.args, []byte("fatal: something went wrong\n"), execErr)
+
+			err := tt.call(adapters.NewGit(mockExec))
+			if !errors.Is(err, execErr) {
+				t.Errorf("expected the exec error to be wrapped, got %v", err)
+			}
+			if err == nil || !strings.Contains(err.Error(), "something went wrong") {
+				t.Errorf("expected git's output in the error, got %v", err)
+			}
+		})
+	}
+}
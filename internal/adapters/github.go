@@ -3,6 +3,10 @@ package adapters
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
@@ -10,12 +14,61 @@ import (
 
 // GitHub provides GitHub operations via gh CLI
 type GitHub struct {
-	exec core.Exec
+	exec   core.Exec
+	remote string
+	repo   string
+	host   string
 }
 
 // NewGitHub creates a GitHub adapter with the provided Exec interface
 func NewGitHub(exec core.Exec) *GitHub {
-	return &GitHub{exec: exec}
+	return &GitHub{exec: exec, remote: DefaultRemote}
+}
+
+// SetRemote sets the remote branches are pushed to. Empty keeps the current remote.
+func (g *GitHub) SetRemote(remote string) {
+	if remote != "" {
+		g.remote = remote
+	}
+}
+
+// SetRepo targets PR commands at an explicit "owner/name" repository
+// (e.g. the upstream of a fork). Empty lets gh resolve it from the checkout.
+func (g *GitHub) SetRepo(repo string) {
+	g.repo = repo
+}
+
+// SetHost targets gh at a GitHub Enterprise host (e.g. github.example.com) by
+// setting GH_HOST for every gh command. Empty lets gh resolve the host.
+func (g *GitHub) SetHost(host string) {
+	g.host = host
+}
+
+// env returns the environment for gh commands: nil (inherited) unless a host is set
+func (g *GitHub) env() []string {
+	if g.host == "" {
+		return nil
+	}
+	return append(os.Environ(), "GH_HOST="+g.host)
+}
+
+// withRepo appends --repo to gh arguments when a target repository is set
+func (g *GitHub) withRepo(args ...string) []string {
+	if g.repo != "" {
+		args = append(args, "--repo", g.repo)
+	}
+	return args
+}
+
+// run runs gh in workDir and returns its stdout, keeping hints gh prints to
+// stderr out of parsed output. When gh fails, stderr is appended so callers can
+// report and classify the failure.
+func (g *GitHub) run(workDir string, args ...string) ([]byte, error) {
+	stdout, stderr, err := g.exec.RunSplit(workDir, g.env(), "gh", args...)
+	if err != nil {
+		return append(stdout, stderr...), err
+	}
+	return stdout, nil
 }
 
 // PRCreateResult contains the result of creating a PR
@@ -29,6 +82,7 @@ type PRCreateInput struct {
 	Title string
 	Body  string
 	Base  string // Base branch (e.g., "main")
+	Head  string // Head branch, as "owner:branch" for PRs from a fork
 }
 
 // CreatePR creates a GitHub PR using gh CLI and returns the PR number and URL.
@@ -47,27 +101,29 @@ func (g *GitHub) CreatePR(workDir string, input PRCreateInput) (*PRCreateResult,
 		args = append(args, "--base", input.Base)
 	}
 
-	output, err := g.exec.RunWithDir(workDir, "gh", args...)
+	if input.Head != "" {
+		args = append(args, "--head", input.Head)
+	}
+
+	output, err := g.run(workDir, g.withRepo(args...)...)
 	if err != nil {
 		// Extract meaningful error message from gh output
 		errMsg := string(output)
 		if errMsg != "" {
-			return nil, fmt.Errorf("failed to create PR: %s", strings.TrimSpace(errMsg))
+			return nil, classifyGHError(output, fmt.Errorf("failed to create PR: %s", strings.TrimSpace(errMsg)))
 		}
-		return nil, fmt.Errorf("failed to create PR: %w", err)
-	}
-
-	// gh pr create outputs the PR URL
-	prURL := strings.TrimSpace(string(output))
-	if prURL == "" {
-		return nil, fmt.Errorf("gh pr create returned empty output")
+		return nil, classifyGHError(output, fmt.Errorf("failed to create PR: %w", err))
 	}
 
-	// Extract PR number from URL
-	// URL format: https://github.com/owner/repo/pull/123
-	prNumber, err := extractPRNumberFromURL(prURL)
-	if err != nil {
-		return nil, err
+	// gh pr create prints the PR URL, possibly among other lines
+	prURL, prNumber, parseErr := g.findPRURL(string(output))
+	if parseErr != nil {
+		// Ask gh for the branch's PR instead
+		view, err := g.ViewPR(workDir, input.Head)
+		if err != nil {
+			return nil, parseErr
+		}
+		prURL, prNumber = view.URL, view.Number
 	}
 
 	return &PRCreateResult{
@@ -76,29 +132,239 @@ func (g *GitHub) CreatePR(workDir string, input PRCreateInput) (*PRCreateResult,
 	}, nil
 }
 
+// ViewPR looks up the PR of a branch with gh pr view. An empty branch means
+// the branch checked out in workDir; "owner:branch" selects a fork's branch.
+func (g *GitHub) ViewPR(workDir, branch string) (*PRCreateResult, error) {
+	args := []string{"pr", "view"}
+	if branch != "" {
+		args = append(args, branch)
+	}
+	output, err := g.run(workDir, g.withRepo(append(args, "--json", "number,url")...)...)
+	if err != nil {
+		return nil, classifyGHError(output, fmt.Errorf("failed to view PR: %s: %w", strings.TrimSpace(string(output)), err))
+	}
+
+	var result PRCreateResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse PR view: %w", err)
+	}
+	if result.Number <= 0 {
+		return nil, fmt.Errorf("gh pr view returned no PR number")
+	}
+	return &result, nil
+}
+
+// findPRURL returns the last PR URL in gh output and its number, skipping
+// hints and other lines around it
+func (g *GitHub) findPRURL(output string) (string, int, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	var firstErr error
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, field := range strings.Fields(lines[i]) {
+			if !strings.Contains(field, "://") {
+				continue
+			}
+			prNumber, err := g.prNumberFromURL(field)
+			if err == nil {
+				return field, prNumber, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return "", 0, firstErr
+	}
+	if strings.TrimSpace(output) == "" {
+		return "", 0, fmt.Errorf("gh pr create returned empty output")
+	}
+	return "", 0, fmt.Errorf("no PR URL in gh pr create output: %s", strings.TrimSpace(output))
+}
+
+// PREditInput contains the fields to change on an existing PR.
+// Empty fields are left unchanged.
+type PREditInput struct {
+	Title string
+	Body  string
+	// AddReviewers requests reviews from these users or org/team handles
+	AddReviewers []string
+	// AddLabels adds these repository labels
+	AddLabels []string
+}
+
+// EditPR updates the title and/or body of an existing PR using gh pr edit
+func (g *GitHub) EditPR(workDir string, prNumber int, input PREditInput) error {
+	args := []string{"pr", "edit", fmt.Sprintf("%d", prNumber)}
+	if input.Title != "" {
+		args = append(args, "--title", input.Title)
+	}
+	if input.Body != "" {
+		args = append(args, "--body", input.Body)
+	}
+	if len(input.AddReviewers) > 0 {
+		args = append(args, "--add-reviewer", strings.Join(input.AddReviewers, ","))
+	}
+	if len(input.AddLabels) > 0 {
+		args = append(args, "--add-label", strings.Join(input.AddLabels, ","))
+	}
+
+	output, err := g.run(workDir, g.withRepo(args...)...)
+	if err != nil {
+		if errMsg := strings.TrimSpace(string(output)); errMsg != "" {
+			return classifyGHError(output, fmt.Errorf("failed to edit PR #%d: %s", prNumber, errMsg))
+		}
+		return classifyGHError(output, fmt.Errorf("failed to edit PR #%d: %w", prNumber, err))
+	}
+	return nil
+}
+
+// Labels returns the names of the repository's labels using gh label list
+func (g *GitHub) Labels(workDir string) ([]string, error) {
+	output, err := g.run(workDir, g.withRepo("label", "list", "--json", "name", "--limit", "1000")...)
+	if err != nil {
+		return nil, classifyGHError(output, fmt.Errorf("failed to list labels: %w", err))
+	}
+
+	var labels []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(output, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse labels: %w", err)
+	}
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+	return names, nil
+}
+
 // Push pushes the current branch to remote with upstream tracking
 func (g *GitHub) Push(workDir string) error {
-	_, err := g.exec.RunWithDir(workDir, "git", "push", "-u", "origin", "HEAD")
+	_, err := g.exec.RunWithDir(workDir, "git", "push", "-u", g.remote, "HEAD")
 	if err != nil {
 		return fmt.Errorf("failed to push to remote: %w", err)
 	}
 	return nil
 }
 
+// BranchProtection describes how GitHub protects a branch
+type BranchProtection struct {
+	Protected bool `json:"protected"`
+	// PushBlockers are the rules that refuse direct pushes, e.g. required
+	// pull request reviews
+	PushBlockers []string `json:"push_blockers,omitempty"`
+}
+
+// PushBlocked reports whether direct pushes to the branch are refused
+func (p BranchProtection) PushBlocked() bool {
+	return len(p.PushBlockers) > 0
+}
+
+// BranchProtection looks up the protection of branch with the GitHub API,
+// from branch protection rules and rulesets. Most protection details need
+// admin access to the repository; without it, only required status checks
+// and rulesets are seen.
+func (g *GitHub) BranchProtection(workDir, branch string) (BranchProtection, error) {
+	repo := "{owner}/{repo}"
+	if g.repo != "" {
+		repo = g.repo
+	}
+	path := "repos/" + repo + "/branches/" + escapeBranch(branch)
+	output, err := g.run(workDir, "api", path)
+	if err != nil {
+		return BranchProtection{}, classifyGHError(output, fmt.Errorf("failed to look up branch %s: %s", branch, strings.TrimSpace(string(output))))
+	}
+	var info struct {
+		Protected  bool `json:"protected"`
+		Protection struct {
+			RequiredStatusChecks struct {
+				EnforcementLevel string   `json:"enforcement_level"`
+				Contexts         []string `json:"contexts"`
+			} `json:"required_status_checks"`
+		} `json:"protection"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return BranchProtection{}, fmt.Errorf("failed to parse branch %s: %w", branch, err)
+	}
+
+	protection := BranchProtection{Protected: info.Protected}
+	block := func(rule string) {
+		if !slices.Contains(protection.PushBlockers, rule) {
+			protection.PushBlockers = append(protection.PushBlockers, rule)
+		}
+	}
+	if checks := info.Protection.RequiredStatusChecks; checks.EnforcementLevel != "off" && len(checks.Contexts) > 0 {
+		block("required status checks")
+	}
+	if info.Protected {
+		// Needs admin access; skipped without it
+		if output, err := g.run(workDir, "api", path+"/protection"); err == nil {
+			var rules struct {
+				RequiredPullRequestReviews *struct{} `json:"required_pull_request_reviews"`
+				Restrictions               *struct{} `json:"restrictions"`
+				LockBranch                 struct {
+					Enabled bool `json:"enabled"`
+				} `json:"lock_branch"`
+			}
+			if json.Unmarshal(output, &rules) == nil {
+				if rules.RequiredPullRequestReviews != nil {
+					block("required pull request reviews")
+				}
+				if rules.Restrictions != nil {
+					block("push restrictions")
+				}
+				if rules.LockBranch.Enabled {
+					block("locked branch")
+				}
+			}
+		}
+	}
+
+	// Rulesets apply whether or not the branch counts as protected
+	if output, err := g.run(workDir, "api", "repos/"+repo+"/rules/branches/"+escapeBranch(branch)); err == nil {
+		var rules []struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(output, &rules) == nil {
+			for _, rule := range rules {
+				switch rule.Type {
+				case "pull_request":
+					block("pull requests required by a ruleset")
+				case "required_status_checks":
+					block("status checks required by a ruleset")
+				case "update":
+					block("updates restricted by a ruleset")
+				}
+			}
+		}
+	}
+	return protection, nil
+}
+
+// escapeBranch escapes branch for an API path, keeping its slashes
+func escapeBranch(branch string) string {
+	parts := strings.Split(branch, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
 // GetPRStatus gets the status of a PR by number
 func (g *GitHub) GetPRStatus(workDir string, prNumber int) (string, error) {
-	output, err := g.exec.RunWithDir(workDir, "gh", "pr", "view", fmt.Sprintf("%d", prNumber), "--json", "state", "--jq", ".state")
+	output, err := g.run(workDir, g.withRepo("pr", "view", fmt.Sprintf("%d", prNumber), "--json", "state", "--jq", ".state")...)
 	if err != nil {
-		return "", fmt.Errorf("failed to get PR status: %w", err)
+		return "", classifyGHError(output, fmt.Errorf("failed to get PR status: %w", err))
 	}
 	return strings.TrimSpace(string(output)), nil
 }
 
 // IsPRMerged checks if a PR has been merged
 func (g *GitHub) IsPRMerged(workDir string, prNumber int) (bool, error) {
-	output, err := g.exec.RunWithDir(workDir, "gh", "pr", "view", fmt.Sprintf("%d", prNumber), "--json", "mergedAt")
+	output, err := g.run(workDir, g.withRepo("pr", "view", fmt.Sprintf("%d", prNumber), "--json", "mergedAt")...)
 	if err != nil {
-		return false, fmt.Errorf("failed to get PR merge status: %w", err)
+		return false, classifyGHError(output, fmt.Errorf("failed to get PR merge status: %w", err))
 	}
 
 	var result struct {
@@ -114,14 +380,14 @@ func (g *GitHub) IsPRMerged(workDir string, prNumber int) (bool, error) {
 // FindMergedPRByBranch checks if there's a merged PR for the given branch name.
 // Returns (merged, prNumber, error). If no merged PR exists, returns (false, 0, nil).
 func (g *GitHub) FindMergedPRByBranch(workDir, branchName string) (bool, int, error) {
-	output, err := g.exec.RunWithDir(workDir, "gh", "pr", "list",
+	output, err := g.run(workDir, g.withRepo("pr", "list",
 		"--head", branchName,
 		"--state", "merged",
 		"--json", "number",
 		"--limit", "1",
-	)
+	)...)
 	if err != nil {
-		return false, 0, fmt.Errorf("failed to list merged PRs: %w", err)
+		return false, 0, classifyGHError(output, fmt.Errorf("failed to list merged PRs: %w", err))
 	}
 
 	var results []struct {
@@ -138,21 +404,193 @@ func (g *GitHub) FindMergedPRByBranch(workDir, branchName string) (bool, int, er
 	return true, results[0].Number, nil
 }
 
-// extractPRNumberFromURL extracts the PR number from a GitHub PR URL
-func extractPRNumberFromURL(url string) (int, error) {
-	// URL format: https://github.com/owner/repo/pull/123
-	parts := strings.Split(url, "/")
-	if len(parts) < 2 {
-		return 0, fmt.Errorf("invalid PR URL format: %s", url)
+// PRCheck is a single CI check reported on a PR
+type PRCheck struct {
+	Name   string `json:"name"`
+	State  string `json:"state"`
+	Bucket string `json:"bucket"` // pass, fail, pending, skipping, or cancel
+	Link   string `json:"link,omitempty"`
+}
+
+// PRChecks returns the CI checks for a PR using gh pr checks.
+// gh exits non-zero while checks are pending or failing, so the output is
+// parsed whenever it is valid JSON regardless of the exit status.
+func (g *GitHub) PRChecks(workDir string, prNumber int) ([]PRCheck, error) {
+	stdout, stderr, err := g.exec.RunSplit(workDir, g.env(), "gh", g.withRepo("pr", "checks", fmt.Sprintf("%d", prNumber),
+		"--json", "name,state,bucket,link")...)
+
+	var checks []PRCheck
+	if jsonErr := json.Unmarshal(stdout, &checks); jsonErr == nil {
+		return checks, nil
+	}
+
+	output := append(stdout, stderr...)
+	if strings.Contains(string(output), "no checks reported") {
+		return nil, nil
+	}
+	if err != nil {
+		if errMsg := strings.TrimSpace(string(output)); errMsg != "" {
+			return nil, classifyGHError(output, fmt.Errorf("failed to get PR checks: %s", errMsg))
+		}
+		return nil, classifyGHError(output, fmt.Errorf("failed to get PR checks: %w", err))
+	}
+	return nil, fmt.Errorf("failed to parse PR checks output")
+}
+
+// ReviewComment is a single comment in a PR review thread
+type ReviewComment struct {
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	URL       string `json:"url,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// ReviewThread is a PR review thread anchored to a file and line
+type ReviewThread struct {
+	ID         string          `json:"id"`
+	Path       string          `json:"path"`
+	Line       int             `json:"line,omitempty"`
+	IsResolved bool            `json:"is_resolved"`
+	IsOutdated bool            `json:"is_outdated"`
+	Comments   []ReviewComment `json:"comments"`
+}
+
+// ReviewThreadsQuery is the GraphQL query used to fetch PR review threads
+const ReviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) {
+        nodes {
+          id
+          isResolved
+          isOutdated
+          path
+          line
+          comments(first: 50) {
+            nodes { author { login } body url createdAt }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// ReviewThreads returns the review threads of a PR using the GitHub GraphQL API.
+// The repository owner and name are resolved by gh from the current repository
+// unless a target repository is set.
+func (g *GitHub) ReviewThreads(workDir string, prNumber int) ([]ReviewThread, error) {
+	owner, name := "{owner}", "{repo}"
+	if o, n, ok := strings.Cut(g.repo, "/"); ok {
+		owner, name = o, n
 	}
 
-	// The PR number is the last part
-	prNumStr := parts[len(parts)-1]
-	var prNumber int
-	_, err := fmt.Sscanf(prNumStr, "%d", &prNumber)
+	output, err := g.run(workDir, "api", "graphql",
+		"-F", "owner="+owner,
+		"-F", "name="+name,
+		"-F", fmt.Sprintf("number=%d", prNumber),
+		"-f", "query="+ReviewThreadsQuery,
+	)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse PR number from URL %s: %w", url, err)
+		if errMsg := strings.TrimSpace(string(output)); errMsg != "" {
+			return nil, classifyGHError(output, fmt.Errorf("failed to fetch review threads: %s", errMsg))
+		}
+		return nil, classifyGHError(output, fmt.Errorf("failed to fetch review threads: %w", err))
 	}
 
+	var response struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ID         string `json:"id"`
+							IsResolved bool   `json:"isResolved"`
+							IsOutdated bool   `json:"isOutdated"`
+							Path       string `json:"path"`
+							Line       int    `json:"line"`
+							Comments   struct {
+								Nodes []struct {
+									Author struct {
+										Login string `json:"login"`
+									} `json:"author"`
+									Body      string `json:"body"`
+									URL       string `json:"url"`
+									CreatedAt string `json:"createdAt"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse review threads: %w", err)
+	}
+
+	var threads []ReviewThread
+	for _, node := range response.Data.Repository.PullRequest.ReviewThreads.Nodes {
+		thread := ReviewThread{
+			ID:         node.ID,
+			Path:       node.Path,
+			Line:       node.Line,
+			IsResolved: node.IsResolved,
+			IsOutdated: node.IsOutdated,
+			Comments:   []ReviewComment{},
+		}
+		for _, c := range node.Comments.Nodes {
+			thread.Comments = append(thread.Comments, ReviewComment{
+				Author:    c.Author.Login,
+				Body:      c.Body,
+				URL:       c.URL,
+				CreatedAt: c.CreatedAt,
+			})
+		}
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
+// prNumberFromURL extracts the PR number from a PR URL of the form
+// https://<host>[/<prefix>]/<owner>/<repo>/pull/<number>. When a host is set,
+// the URL must be on it.
+func (g *GitHub) prNumberFromURL(rawURL string) (int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return 0, fmt.Errorf("invalid PR URL format: %s", rawURL)
+	}
+	if g.host != "" && !strings.EqualFold(u.Hostname(), g.host) && !strings.EqualFold(u.Host, g.host) {
+		return 0, fmt.Errorf("PR URL %s is not on the configured host %s", rawURL, g.host)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[len(parts)-2] != "pull" {
+		return 0, fmt.Errorf("invalid PR URL format: %s", rawURL)
+	}
+	prNumber, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || prNumber <= 0 {
+		return 0, fmt.Errorf("failed to parse PR number from URL %s", rawURL)
+	}
 	return prNumber, nil
 }
+
+// ghAuthOutputs are fragments of gh output reporting missing or invalid credentials
+var ghAuthOutputs = []string{
+	"gh auth login",
+	"not logged into any",
+	"authentication required",
+	"Bad credentials",
+	"HTTP 401",
+}
+
+// classifyGHError attaches a remediation hint to err when gh's output shows
+// it is not authenticated. Otherwise err is returned as is.
+func classifyGHError(output []byte, err error) error {
+	out := string(output)
+	for _, fragment := range ghAuthOutputs {
+		if strings.Contains(out, fragment) {
+			return core.NewGHNotAuthenticatedError(err)
+		}
+	}
+	return err
+}
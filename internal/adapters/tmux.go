@@ -1,7 +1,11 @@
 package adapters
 
 import (
+	"errors"
 	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
 )
@@ -16,12 +20,28 @@ func NewTmux(exec core.Exec) *Tmux {
 	return &Tmux{exec: exec}
 }
 
+// ValidateSessionName checks that tmux accepts name as a session name as
+// given: tmux rejects empty names and silently replaces '.' and ':', which
+// would make later lookups by the original name fail
+func ValidateSessionName(name string) error {
+	if name == "" {
+		return errors.New("tmux session name must not be empty")
+	}
+	if i := strings.IndexAny(name, ".:"); i >= 0 {
+		return fmt.Errorf("invalid tmux session name %q: must not contain %q", name, name[i])
+	}
+	return nil
+}
+
 // NewSession creates a new detached tmux session in the specified directory.
 // The session is created in detached mode (-d) so it can be attached to later.
 func (t *Tmux) NewSession(sessionName, workDir string) error {
+	if err := ValidateSessionName(sessionName); err != nil {
+		return err
+	}
 	_, err := t.exec.Run("tmux", "new-session", "-d", "-s", sessionName, "-c", workDir)
 	if err != nil {
-		return fmt.Errorf("failed to create tmux session: %w", err)
+		return classifyTmuxError(fmt.Errorf("failed to create tmux session: %w", err))
 	}
 	return nil
 }
@@ -31,7 +51,7 @@ func (t *Tmux) NewSession(sessionName, workDir string) error {
 func (t *Tmux) AttachSession(sessionName string) error {
 	_, err := t.exec.Run("tmux", "attach-session", "-t", sessionName)
 	if err != nil {
-		return fmt.Errorf("failed to attach to tmux session: %w", err)
+		return classifyTmuxError(fmt.Errorf("failed to attach to tmux session: %w", err))
 	}
 	return nil
 }
@@ -44,3 +64,108 @@ func (t *Tmux) KillSession(sessionName string) error {
 	}
 	return nil
 }
+
+// HasSession reports whether a tmux session named exactly sessionName is
+// running. No running tmux server means no session.
+func (t *Tmux) HasSession(sessionName string) (bool, error) {
+	// "=" matches the name exactly instead of as a prefix
+	output, err := t.exec.Run("tmux", "has-session", "-t", "="+sessionName)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return false, classifyTmuxError(fmt.Errorf("failed to check tmux session: %w", err))
+		}
+		if out := string(output); strings.Contains(out, "can't find session") || strings.Contains(out, "no server running") ||
+			strings.Contains(out, "error connecting") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check tmux session %s: %w", sessionName, err)
+	}
+	return true, nil
+}
+
+// RenameSession renames a running tmux session.
+func (t *Tmux) RenameSession(sessionName, newName string) error {
+	if err := ValidateSessionName(newName); err != nil {
+		return err
+	}
+	_, err := t.exec.Run("tmux", "rename-session", "-t", "="+sessionName, newName)
+	if err != nil {
+		return classifyTmuxError(fmt.Errorf("failed to rename tmux session %s to %s: %w", sessionName, newName, err))
+	}
+	return nil
+}
+
+// NewWindow opens a window called windowName in a tmux session, started in
+// workDir, without switching to it.
+func (t *Tmux) NewWindow(sessionName, windowName, workDir string) error {
+	_, err := t.exec.Run("tmux", "new-window", "-d", "-t", sessionName, "-n", windowName, "-c", workDir)
+	if err != nil {
+		return fmt.Errorf("failed to open tmux window %s in session %s: %w", windowName, sessionName, err)
+	}
+	return nil
+}
+
+// RenameWindow renames a tmux window, which also stops tmux from renaming it
+// after the running command.
+func (t *Tmux) RenameWindow(target, name string) error {
+	_, err := t.exec.Run("tmux", "rename-window", "-t", target, name)
+	if err != nil {
+		return classifyTmuxError(fmt.Errorf("failed to rename tmux window %s: %w", target, err))
+	}
+	return nil
+}
+
+// SendKeys types a command line into a tmux session and presses Enter.
+func (t *Tmux) SendKeys(sessionName, keys string) error {
+	_, err := t.exec.Run("tmux", "send-keys", "-t", sessionName, keys, "Enter")
+	if err != nil {
+		return fmt.Errorf("failed to send keys to tmux session %s: %w", sessionName, err)
+	}
+	return nil
+}
+
+// PanePIDs returns the process IDs of the shells in every pane of a session.
+func (t *Tmux) PanePIDs(sessionName string) ([]int, error) {
+	output, err := t.exec.Run("tmux", "list-panes", "-s", "-t", "="+sessionName, "-F", "#{pane_pid}")
+	if err != nil {
+		return nil, classifyTmuxError(fmt.Errorf("failed to list panes of tmux session %s: %w", sessionName, err))
+	}
+
+	var pids []int
+	for _, line := range strings.Fields(string(output)) {
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected tmux pane pid %q", line)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// ListSessions returns the names of running tmux sessions.
+// No running tmux server means no sessions.
+func (t *Tmux) ListSessions() ([]string, error) {
+	output, err := t.exec.Run("tmux", "list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if strings.Contains(string(output), "no server running") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+
+	var sessions []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			sessions = append(sessions, line)
+		}
+	}
+	return sessions, nil
+}
+
+// classifyTmuxError attaches a remediation hint when tmux is not installed
+func classifyTmuxError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return core.NewTmuxNotInstalledError(err)
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package adapters_test
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+func TestTmux_HasSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		want    bool
+		wantErr bool
+	}{
+		{"running", "", nil, true, false},
+		{"missing", "can't find session: =mp-piece-p1\n", fmt.Errorf("exit status 1"), false, false},
+		{"no server", "no server running on /tmp/tmux-1000/default\n", fmt.Errorf("exit status 1"), false, false},
+		{"other failure", "lost server\n", fmt.Errorf("exit status 1"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := adapters.NewMockExec()
+			mockExec.AddResponse("tmux", []string{"has-session", "-t", "=mp-piece-p1"}, []byte(tt.output), tt.err)
+
+			got, err := adapters.NewTmux(mockExec).HasSession("mp-piece-p1")
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("expected (%v, error %v), got (%v, %v)", tt.want, tt.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func TestTmux_HasSession_NotInstalled(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("tmux", []string{"has-session", "-t", "=s"}, nil, exec.ErrNotFound)
+
+	_, err := adapters.NewTmux(mockExec).HasSession("s")
+	if remediable, ok := core.AsRemediable(err); !ok || remediable.Code != core.CodeTmuxNotInstalled {
+		t.Errorf("expected tmux_not_installed, got %v", err)
+	}
+}
+
+func TestTmux_RenameSession(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("tmux", []string{"rename-session", "-t", "=old", "new"}, nil, nil)
+
+	if err := adapters.NewTmux(mockExec).RenameSession("old", "new"); err != nil {
+		t.Fatalf("RenameSession failed: %v", err)
+	}
+	if !mockExec.WasCalled("tmux", "rename-session", "-t", "=old", "new") {
+		t.Error("expected rename-session to be called")
+	}
+
+	mockExec.AddResponse("tmux", []string{"rename-session", "-t", "=old", "new"}, nil, errors.New("exit status 1"))
+	if err := adapters.NewTmux(mockExec).RenameSession("old", "new"); err == nil {
+		t.Error("expected the failure to be returned")
+	}
+}
+
+func TestTmux_NewWindowAndSendKeys(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("tmux", []string{"new-window", "-d", "-t", "s", "-n", "server", "-c", "/work"}, nil, nil)
+	mockExec.AddResponse("tmux", []string{"send-keys", "-t", "s:server", "make run", "Enter"}, nil, nil)
+	tmux := adapters.NewTmux(mockExec)
+
+	if err := tmux.NewWindow("s", "server", "/work"); err != nil {
+		t.Fatalf("NewWindow failed: %v", err)
+	}
+	if err := tmux.SendKeys("s:server", "make run"); err != nil {
+		t.Fatalf("SendKeys failed: %v", err)
+	}
+	calls := mockExec.GetCalls()
+	if len(calls) != 2 || calls[0].Args[0] != "new-window" || calls[1].Args[0] != "send-keys" {
+		t.Errorf("expected new-window then send-keys, got %+v", calls)
+	}
+}
+
+func TestTmux_ListSessions(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("tmux", []string{"list-sessions", "-F", "#{session_name}"}, []byte("main\nmp-piece-p1\n"), nil)
+
+	sessions, err := adapters.NewTmux(mockExec).ListSessions()
+	if err != nil || len(sessions) != 2 || sessions[1] != "mp-piece-p1" {
+		t.Errorf("expected two sessions, got %v (%v)", sessions, err)
+	}
+
+	mockExec.AddResponse("tmux", []string{"list-sessions", "-F", "#{session_name}"}, []byte("no server running on /tmp/tmux-1000/default\n"), errors.New("exit status 1"))
+	sessions, err = adapters.NewTmux(mockExec).ListSessions()
+	if err != nil || sessions != nil {
+		t.Errorf("expected no sessions without a server, got %v (%v)", sessions, err)
+	}
+}
+
+func TestTmux_NewSession_InvalidName(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+
+	if err := adapters.NewTmux(mockExec).NewSession("mp-piece-v1.2", "/work"); err == nil {
+		t.Error("expected a session name with '.' to be rejected")
+	}
+	if len(mockExec.GetCalls()) != 0 {
+		t.Error("expected tmux not to be called")
+	}
+}
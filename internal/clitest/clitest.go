@@ -0,0 +1,128 @@
+// Package clitest runs mp command lines against in-memory fixtures and compares
+// their output with golden files, so output formats can't change unnoticed.
+//
+// Run tests with -update to rewrite golden files from the current output.
+package clitest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/cmd/mp"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// Default fixture locations
+const (
+	WorkDir = "/repo"
+	DataDir = "/test-data"
+	HomeDir = "/test-home"
+)
+
+// Fixture is the environment command lines run against
+type Fixture struct {
+	t       *testing.T
+	FS      *adapters.MemoryFS
+	Exec    *adapters.MockExec
+	WorkDir string
+}
+
+// New creates an empty fixture working in WorkDir, with XDG_DATA_HOME and HOME
+// pointed at fixture paths so nothing outside the MemoryFS is touched
+func New(t *testing.T) *Fixture {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", DataDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", HomeDir)
+
+	fs := adapters.NewMemoryFS()
+	_ = fs.MkdirAll(WorkDir, 0755)
+	return &Fixture{t: t, FS: fs, Exec: adapters.NewMockExec(), WorkDir: WorkDir}
+}
+
+// WriteFile writes a fixture file, creating parent directories
+func (f *Fixture) WriteFile(path, content string) {
+	f.t.Helper()
+	if err := f.FS.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		f.t.Fatalf("failed to create fixture dir for %s: %v", path, err)
+	}
+	if err := f.FS.WriteFile(path, []byte(content), 0644); err != nil {
+		f.t.Fatalf("failed to write fixture %s: %v", path, err)
+	}
+}
+
+// Result is the outcome of a command line run
+type Result struct {
+	Stdout string
+	Stderr string
+	Err    error
+}
+
+// Run executes mp with args. A non-empty stdin is piped to the command.
+func (f *Fixture) Run(stdin string, args ...string) Result {
+	f.t.Helper()
+	var stdout, stderr bytes.Buffer
+	e := mp.Env{
+		FS:      f.FS,
+		Exec:    f.Exec,
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+		WorkDir: f.WorkDir,
+	}
+	if stdin != "" {
+		e.Stdin = strings.NewReader(stdin)
+	}
+
+	err := mp.Run(e, args)
+	return Result{Stdout: stdout.String(), Stderr: stderr.String(), Err: err}
+}
+
+// String renders the result in the golden file format
+func (r Result) String() string {
+	var b strings.Builder
+	b.WriteString("-- stdout --\n")
+	b.WriteString(r.Stdout)
+	b.WriteString("-- stderr --\n")
+	b.WriteString(r.Stderr)
+	if r.Err != nil {
+		b.WriteString("-- error --\n")
+		b.WriteString(r.Err.Error() + "\n")
+	}
+	return b.String()
+}
+
+// Golden compares the result with testdata/<name>.golden
+func (r Result) Golden(t *testing.T, name string) {
+	t.Helper()
+	Golden(t, name, r.String())
+}
+
+// Golden compares got with testdata/<name>.golden, rewriting it with -update
+func Golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
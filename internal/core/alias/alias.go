@@ -0,0 +1,136 @@
+// Package alias resolves command aliases defined in the repository config
+// (.monkeypuzzle/monkeypuzzle.json) and the user config
+// ($XDG_CONFIG_HOME/monkeypuzzle/config.json), so teams and users can name
+// mp command lines in their own vocabulary.
+package alias
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// Alias sources
+const (
+	SourceRepo = "repo"
+	SourceUser = "user"
+)
+
+// Alias is a name expanding to an mp command line
+type Alias struct {
+	Name      string   `json:"name"`
+	Expansion string   `json:"expansion"`
+	Args      []string `json:"args"`
+	Source    string   `json:"source"`
+}
+
+// Load returns the aliases visible from workDir, sorted by name: those of the
+// repository containing workDir, overridden by the user's own.
+func Load(fs core.FS, workDir string) ([]Alias, error) {
+	defs := map[string]Alias{}
+
+	if root, ok := initcmd.FindRepoConfig(fs, workDir); ok {
+		var cfg initcmd.Config
+		if err := readJSON(fs, filepath.Join(root, initcmd.DirName, initcmd.ConfigFile), &cfg); err != nil {
+			return nil, err
+		}
+		if err := addAliases(defs, cfg.Aliases, SourceRepo); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := initcmd.ReadUserConfig(fs)
+	if err != nil {
+		return nil, err
+	}
+	if err := addAliases(defs, cfg.Aliases, SourceUser); err != nil {
+		return nil, err
+	}
+
+	aliases := make([]Alias, 0, len(defs))
+	for _, a := range defs {
+		aliases = append(aliases, a)
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Name < aliases[j].Name })
+	return aliases, nil
+}
+
+func addAliases(defs map[string]Alias, raw map[string]string, source string) error {
+	for name, expansion := range raw {
+		if strings.ContainsAny(name, " \t") || strings.HasPrefix(name, "-") || name == "" {
+			return fmt.Errorf("invalid alias name %q in %s config", name, source)
+		}
+		args, err := Split(expansion)
+		if err != nil {
+			return fmt.Errorf("invalid alias %q in %s config: %w", name, source, err)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("alias %q in %s config is empty", name, source)
+		}
+		defs[name] = Alias{Name: name, Expansion: expansion, Args: args, Source: source}
+	}
+	return nil
+}
+
+// Split splits an alias expansion into arguments like a shell would for plain
+// words, single quotes, double quotes, and backslash escapes (no expansion).
+func Split(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+
+	for i := 0; i < len(s); i++ {
+		c := rune(s[i])
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(s) {
+				i++
+				cur.WriteByte(s[i])
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			cur.WriteByte(s[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+func readJSON(fs core.FS, path string, v any) error {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
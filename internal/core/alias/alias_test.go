@@ -0,0 +1,72 @@
+package alias_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/alias"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"piece new --issue", []string{"piece", "new", "--issue"}},
+		{`issue list --status "in progress"`, []string{"issue", "list", "--status", "in progress"}},
+		{`pr create --title 'it''s' a\ b`, []string{"pr", "create", "--title", "its", "a b"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got, err := alias.Split(tt.input)
+		if err != nil {
+			t.Errorf("Split(%q) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := alias.Split(`piece new "oops`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
+
+func TestLoad_UserOverridesRepo(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/test-config")
+	fs := adapters.NewMemoryFS()
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(`{"aliases":{"start":"piece new --issue","land":"piece merge"}}`), 0644)
+	_ = fs.MkdirAll("test-config/monkeypuzzle", 0755)
+	_ = fs.WriteFile("test-config/monkeypuzzle/config.json",
+		[]byte(`{"aliases":{"land":"piece merge --delete"}}`), 0644)
+
+	aliases, err := alias.Load(fs, "/repo/sub/dir")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(aliases) != 2 {
+		t.Fatalf("expected 2 aliases, got %+v", aliases)
+	}
+	land, start := aliases[0], aliases[1]
+	if land.Name != "land" || land.Source != alias.SourceUser || !reflect.DeepEqual(land.Args, []string{"piece", "merge", "--delete"}) {
+		t.Errorf("expected user alias land to win, got %+v", land)
+	}
+	if start.Name != "start" || start.Source != alias.SourceRepo {
+		t.Errorf("expected repo alias start, got %+v", start)
+	}
+}
+
+func TestLoad_InvalidAlias(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/test-config")
+	fs := adapters.NewMemoryFS()
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"aliases":{"start":""}}`), 0644)
+
+	if _, err := alias.Load(fs, "/repo"); err == nil {
+		t.Error("expected error for empty alias")
+	}
+}
@@ -0,0 +1,121 @@
+// Package childenv injects the environment variables configured under env in
+// the repository config (.monkeypuzzle/monkeypuzzle.json) and the user config
+// into every command mp runs, e.g. proxy settings or GH_HOST for GitHub
+// Enterprise.
+package childenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// ProviderCommands maps env.providers keys to the executable they apply to
+var ProviderCommands = map[string]string{
+	"git":    "git",
+	"github": "gh",
+}
+
+// Env is a resolved set of variables to inject
+type Env struct {
+	// vars apply to every command, as KEY=value
+	vars []string
+	// commands holds variables for a single executable, as KEY=value
+	commands map[string][]string
+}
+
+// New resolves env configs, later configs overriding earlier ones. Values are
+// expanded against the process environment, so "${CORP_PROXY}" is replaced by
+// $CORP_PROXY.
+func New(configs ...initcmd.EnvConfig) (*Env, error) {
+	vars := map[string]string{}
+	commands := map[string]map[string]string{}
+	for _, cfg := range configs {
+		if err := addVars(vars, cfg.Vars, "env.vars"); err != nil {
+			return nil, err
+		}
+		for provider, pv := range cfg.Providers {
+			command, ok := ProviderCommands[provider]
+			if !ok {
+				return nil, fmt.Errorf("unknown provider %q in env.providers (providers: git, github)", provider)
+			}
+			if commands[command] == nil {
+				commands[command] = map[string]string{}
+			}
+			if err := addVars(commands[command], pv, "env.providers."+provider); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	env := &Env{vars: environ(vars), commands: map[string][]string{}}
+	for command, cv := range commands {
+		env.commands[command] = environ(cv)
+	}
+	return env, nil
+}
+
+// Load resolves the env config of the user, overridden by that of the
+// repository at repoRoot. repoRoot may be empty outside a repository.
+func Load(fs core.FS, repoRoot string) (*Env, error) {
+	user, err := initcmd.ReadUserConfig(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing repository config leaves only the user's variables
+	var repo initcmd.Config
+	if repoRoot != "" {
+		path := filepath.Join(repoRoot, initcmd.DirName, initcmd.ConfigFile)
+		data, err := fs.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &repo); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+		}
+	}
+	return New(user.Env, repo.Env)
+}
+
+// Empty reports whether no variables are configured
+func (e *Env) Empty() bool {
+	return len(e.vars) == 0 && len(e.commands) == 0
+}
+
+// For returns the variables to add for the executable name, as KEY=value
+func (e *Env) For(name string) []string {
+	extra := e.commands[filepath.Base(name)]
+	if len(extra) == 0 {
+		return e.vars
+	}
+	return append(append([]string(nil), e.vars...), extra...)
+}
+
+func addVars(dst, src map[string]string, section string) error {
+	for key, value := range src {
+		if key == "" || strings.ContainsAny(key, "= \t\n") {
+			return fmt.Errorf("invalid variable name %q in %s", key, section)
+		}
+		dst[key] = os.ExpandEnv(value)
+	}
+	return nil
+}
+
+// environ formats vars as KEY=value, sorted by key
+func environ(vars map[string]string) []string {
+	result := make([]string, 0, len(vars))
+	for key, value := range vars {
+		result = append(result, key+"="+value)
+	}
+	sort.Strings(result)
+	return result
+}
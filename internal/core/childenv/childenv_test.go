@@ -0,0 +1,104 @@
+package childenv_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/childenv"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+func TestNew_ExpandsAndOverrides(t *testing.T) {
+	t.Setenv("CORP_PROXY", "http://proxy:3128")
+	user := initcmd.EnvConfig{
+		Vars:      map[string]string{"HTTPS_PROXY": "${CORP_PROXY}", "LANG": "C"},
+		Providers: map[string]map[string]string{"github": {"GH_HOST": "github.com"}},
+	}
+	repo := initcmd.EnvConfig{
+		Vars:      map[string]string{"LANG": "en_US.UTF-8"},
+		Providers: map[string]map[string]string{"github": {"GH_HOST": "github.example.com"}},
+	}
+
+	env, err := childenv.New(user, repo)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got, want := env.For("git"), []string{"HTTPS_PROXY=http://proxy:3128", "LANG=en_US.UTF-8"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected git env %v, got %v", want, got)
+	}
+	if got := env.For("/usr/bin/gh"); !slices.Contains(got, "GH_HOST=github.example.com") || len(got) != 3 {
+		t.Errorf("expected gh env to add the repo's GH_HOST, got %v", got)
+	}
+}
+
+func TestNew_RejectsInvalidConfig(t *testing.T) {
+	for _, cfg := range []initcmd.EnvConfig{
+		{Providers: map[string]map[string]string{"gitlab": {"GITLAB_HOST": "x"}}},
+		{Vars: map[string]string{"A=B": "c"}},
+	} {
+		if _, err := childenv.New(cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestLoad_RepositoryConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	fs := adapters.NewMemoryFS()
+
+	env, err := childenv.Load(fs, "/repo")
+	if err != nil {
+		t.Fatalf("expected a missing repository config to be ignored, got %v", err)
+	}
+	if !env.Empty() {
+		t.Errorf("expected no variables, got %v", env.For("git"))
+	}
+
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"env":{"vars":{"LANG":"C"}}}`), 0644)
+	env, err = childenv.Load(fs, "/repo")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got, want := env.For("git"), []string{"LANG=C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected git env %v, got %v", want, got)
+	}
+
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"env":{"vars":`), 0644)
+	if _, err := childenv.Load(fs, "/repo"); err == nil {
+		t.Error("expected an error for a malformed repository config")
+	}
+}
+
+func TestWrap_InjectsIntoCommands(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("gh", []string{"pr", "list"}, []byte("[]"), nil)
+	mockExec.AddResponse("git", []string{"status"}, nil, nil)
+	env, err := childenv.New(initcmd.EnvConfig{
+		Providers: map[string]map[string]string{"github": {"GH_HOST": "github.example.com"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := childenv.Wrap(mockExec, env)
+
+	if _, _, err := exec.RunSplit("/repo", nil, "gh", "pr", "list"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exec.RunWithDir("/repo", "git", "status"); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := mockExec.GetCalls()
+	if !slices.Contains(calls[0].Env, "GH_HOST=github.example.com") {
+		t.Errorf("expected GH_HOST for gh, got %v", calls[0].Env)
+	}
+	if calls[1].Env != nil {
+		t.Errorf("expected git to inherit the environment unchanged, got %d vars", len(calls[1].Env))
+	}
+	if empty, _ := childenv.New(); childenv.Wrap(mockExec, empty) != mockExec {
+		t.Error("expected an empty env to leave the exec unwrapped")
+	}
+}
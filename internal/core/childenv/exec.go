@@ -0,0 +1,71 @@
+package childenv
+
+import (
+	"os"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+var _ core.Exec = (*Exec)(nil)
+
+// Exec wraps a core.Exec, adding the configured variables to the environment
+// of every command. Configured variables override inherited ones.
+type Exec struct {
+	inner core.Exec
+	env   *Env
+}
+
+// Wrap returns inner with env injected, or inner itself when env is empty
+func Wrap(inner core.Exec, env *Env) core.Exec {
+	if env == nil || env.Empty() {
+		return inner
+	}
+	return &Exec{inner: inner, env: env}
+}
+
+// Run executes a command with the configured variables
+func (e *Exec) Run(name string, args ...string) ([]byte, error) {
+	if len(e.env.For(name)) == 0 {
+		return e.inner.Run(name, args...)
+	}
+	return e.inner.RunWithEnv("", e.merge(nil, name), name, args...)
+}
+
+// RunWithDir executes a command in dir with the configured variables
+func (e *Exec) RunWithDir(dir, name string, args ...string) ([]byte, error) {
+	if len(e.env.For(name)) == 0 {
+		return e.inner.RunWithDir(dir, name, args...)
+	}
+	return e.inner.RunWithEnv(dir, e.merge(nil, name), name, args...)
+}
+
+// RunWithEnv executes a command with env plus the configured variables
+func (e *Exec) RunWithEnv(dir string, env []string, name string, args ...string) ([]byte, error) {
+	return e.inner.RunWithEnv(dir, e.merge(env, name), name, args...)
+}
+
+// RunSplit executes a command with the configured variables, returning stdout
+// and stderr separately
+func (e *Exec) RunSplit(dir string, env []string, name string, args ...string) ([]byte, []byte, error) {
+	return e.inner.RunSplit(dir, e.merge(env, name), name, args...)
+}
+
+// RunStream executes a long-lived command with the configured variables
+func (e *Exec) RunStream(opts core.StreamOptions, name string, args ...string) error {
+	opts.Env = e.merge(opts.Env, name)
+	return e.inner.RunStream(opts, name, args...)
+}
+
+// merge appends the variables for name to env; a nil env stands for the
+// process environment. Without variables for name, env is returned as is.
+func (e *Exec) merge(env []string, name string) []string {
+	extra := e.env.For(name)
+	if len(extra) == 0 {
+		return env
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	// Later entries win, so configured variables override inherited ones
+	return append(append([]string(nil), env...), extra...)
+}
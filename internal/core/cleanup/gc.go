@@ -0,0 +1,268 @@
+package cleanup
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// GCOptions configures GC
+type GCOptions struct {
+	// MainBranch is the branch merged pieces are detected against
+	MainBranch string
+	// MinFree is the free space, in bytes, to reach on the filesystem
+	// holding the pieces directory; zero skips removing pieces
+	MinFree int64
+	// Remove removes the chosen pieces; otherwise they are only suggested
+	Remove bool
+	// Repack runs git gc in the repository's common git dir
+	Repack bool
+}
+
+// GCPiece is a merged piece chosen to free disk space
+type GCPiece struct {
+	PieceName    string `json:"piece_name"`
+	WorktreePath string `json:"worktree_path"`
+	DiskBytes    int64  `json:"disk_bytes"`
+	// LastActive is the time of the piece's last event, if any was logged
+	LastActive time.Time `json:"last_active,omitzero"`
+	Removed    bool      `json:"removed,omitempty"`
+}
+
+// GCReport is the result of GC
+type GCReport struct {
+	FreeBytes    int64 `json:"free_bytes"`
+	MinFreeBytes int64 `json:"min_free_bytes"`
+	// FreedBytes is the space the chosen pieces use, freed once they are removed
+	FreedBytes int64 `json:"freed_bytes"`
+	// Met is true when free space reaches MinFreeBytes, counting the space
+	// the chosen pieces free
+	Met    bool      `json:"met"`
+	Pieces []GCPiece `json:"pieces"`
+	// Objects reports, for each piece, whether it shares the repository's
+	// object store
+	Objects []piece.ObjectSharing `json:"objects"`
+	Repack  *Repack               `json:"repack,omitempty"`
+}
+
+// Repack is the result of repacking the shared object store
+type Repack struct {
+	GitDir      string `json:"git_dir"`
+	BeforeBytes int64  `json:"before_bytes"`
+	AfterBytes  int64  `json:"after_bytes"`
+}
+
+// GC checks that every piece shares the repository's object store, then
+// frees disk space: with MinFree, by removing merged pieces, oldest first,
+// until the pieces' filesystem has MinFree bytes available; with Repack, by
+// running git gc on the shared object store. Pieces are ordered by their
+// last logged event; pieces without events count as oldest. Without Remove,
+// the pieces are only reported.
+func (h *Handler) GC(repoRoot string, opts GCOptions) (GCReport, error) {
+	pieces := piece.NewHandler(h.deps)
+	objects, err := pieces.ObjectSharing(repoRoot)
+	if err != nil {
+		return GCReport{}, err
+	}
+	report := GCReport{MinFreeBytes: opts.MinFree, Pieces: []GCPiece{}, Objects: objects}
+	for _, o := range objects {
+		if o.Shared {
+			continue
+		}
+		detail := o.Problem
+		if o.DuplicatedBytes > 0 {
+			detail += ", " + piece.FormatSize(o.DuplicatedBytes) + " duplicated"
+		}
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Piece %s does not share the repository's objects (%s); recreate it with mp piece new", o.PieceName, detail),
+			Data:    o,
+		})
+	}
+
+	if opts.MinFree > 0 {
+		if err := h.freeSpace(repoRoot, opts, &report); err != nil {
+			return report, err
+		}
+	} else {
+		report.Met = true
+	}
+
+	if opts.Repack {
+		repack, err := h.repack(repoRoot)
+		if err != nil {
+			return report, err
+		}
+		report.Repack = &repack
+	}
+	return report, nil
+}
+
+// freeSpace chooses, and with Remove removes, the merged pieces to remove
+// to reach MinFree
+func (h *Handler) freeSpace(repoRoot string, opts GCOptions, report *GCReport) error {
+	piecesDir, err := piece.PiecesDir()
+	if err != nil {
+		return fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	dfPath := piecesDir
+	if _, err := h.deps.FS.Stat(piecesDir); err != nil {
+		dfPath = repoRoot
+	}
+
+	pieces := piece.NewHandler(h.deps)
+	free, err := pieces.FreeSpace(dfPath)
+	if err != nil {
+		return err
+	}
+	report.FreeBytes = free
+	if free >= opts.MinFree {
+		report.Met = true
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgSuccess,
+			Content: fmt.Sprintf("%s free, nothing to remove (target %s)", piece.FormatSize(free), piece.FormatSize(opts.MinFree)),
+			Data:    report,
+		})
+		return nil
+	}
+
+	candidates, err := h.mergedPieces(repoRoot, opts.MainBranch)
+	if err != nil {
+		return err
+	}
+	lastActive := lastActivity(h.deps.FS, repoRoot)
+	slices.SortFunc(candidates, func(a, b GCPiece) int {
+		if c := lastActive[a.PieceName].Compare(lastActive[b.PieceName]); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PieceName, b.PieceName)
+	})
+
+	paths := make([]string, len(candidates))
+	for i, c := range candidates {
+		paths[i] = c.WorktreePath
+	}
+	usage := pieces.DiskUsage(paths, piece.DefaultDiskUsageMaxAge)
+	for _, c := range candidates {
+		if free+report.FreedBytes >= opts.MinFree {
+			break
+		}
+		c.DiskBytes = usage[c.WorktreePath]
+		c.LastActive = lastActive[c.PieceName]
+		report.Pieces = append(report.Pieces, c)
+		report.FreedBytes += c.DiskBytes
+	}
+	report.Met = free+report.FreedBytes >= opts.MinFree
+
+	if opts.Remove && len(report.Pieces) > 0 {
+		names := make([]string, len(report.Pieces))
+		for i, p := range report.Pieces {
+			names[i] = p.PieceName
+		}
+		results, err := pieces.CleanupMergedPieces(repoRoot, piece.CleanupOptions{MainBranch: opts.MainBranch, Pieces: names})
+		if err != nil {
+			return err
+		}
+		report.FreedBytes = 0
+		for i, p := range report.Pieces {
+			report.Pieces[i].Removed = slices.ContainsFunc(results, func(r piece.CleanupResult) bool {
+				return r.PieceName == p.PieceName && r.Cleaned()
+			})
+			if report.Pieces[i].Removed {
+				report.FreedBytes += p.DiskBytes
+			}
+		}
+		report.Met = free+report.FreedBytes >= opts.MinFree
+	}
+
+	verb := "would free"
+	if opts.Remove {
+		verb = "freed"
+	}
+	content := fmt.Sprintf("%s free of %s wanted; %d merged pieces %s %s",
+		piece.FormatSize(free), piece.FormatSize(opts.MinFree), len(report.Pieces), verb, piece.FormatSize(report.FreedBytes))
+	msgType := core.MsgSuccess
+	if !report.Met {
+		msgType = core.MsgWarning
+		content += ", short of the target (remove unmerged pieces with mp piece delete)"
+	} else if !opts.Remove {
+		msgType = core.MsgInfo
+		content += " (run with --remove to remove them)"
+	}
+	h.deps.Output.Write(core.Message{Type: msgType, Content: content, Data: report})
+	return nil
+}
+
+// repack runs git gc in the common git dir of repoRoot, measuring its
+// objects before and after
+func (h *Handler) repack(repoRoot string) (Repack, error) {
+	git := adapters.NewGit(h.deps.Exec)
+	gitDir, err := git.RevParseGitCommonDir(repoRoot)
+	if err != nil {
+		return Repack{}, err
+	}
+	objectsDir := filepath.Join(gitDir, "objects")
+	pieces := piece.NewHandler(h.deps)
+	result := Repack{GitDir: gitDir}
+	result.BeforeBytes, _ = pieces.DirSize(objectsDir)
+	if err := git.GC(gitDir); err != nil {
+		return result, err
+	}
+	result.AfterBytes, _ = pieces.DirSize(objectsDir)
+	h.deps.Output.Write(core.Message{
+		Type: core.MsgSuccess,
+		Content: fmt.Sprintf("Repacked %s: %s -> %s", gitDir,
+			piece.FormatSize(result.BeforeBytes), piece.FormatSize(result.AfterBytes)),
+		Data: result,
+	})
+	return result, nil
+}
+
+// mergedPieces returns the pieces mp piece cleanup would remove, without the
+// worktrees git already lost
+func (h *Handler) mergedPieces(repoRoot, mainBranch string) ([]GCPiece, error) {
+	deps := h.deps
+	deps.Output = warningsOnly{h.deps.Output}
+	results, err := piece.NewHandler(deps).CleanupMergedPieces(repoRoot, piece.CleanupOptions{DryRun: true, MainBranch: mainBranch})
+	if err != nil {
+		return nil, err
+	}
+	var merged []GCPiece
+	for _, r := range results {
+		if r.Discrepancy == "" {
+			merged = append(merged, GCPiece{PieceName: r.PieceName, WorktreePath: r.WorktreePath})
+		}
+	}
+	return merged, nil
+}
+
+// lastActivity returns the time of each piece's last logged event
+func lastActivity(fs core.FS, repoRoot string) map[string]time.Time {
+	last := map[string]time.Time{}
+	all, _ := events.Read(fs, repoRoot)
+	for _, e := range all {
+		if e.Piece != "" && e.Time.After(last[e.Piece]) {
+			last[e.Piece] = e.Time
+		}
+	}
+	return last
+}
+
+// warningsOnly passes on warnings and errors, dropping the dry-run listing
+// of a merge check
+type warningsOnly struct {
+	core.Output
+}
+
+func (o warningsOnly) Write(msg core.Message) {
+	if msg.Type == core.MsgWarning || msg.Type == core.MsgError {
+		o.Output.Write(msg)
+	}
+}
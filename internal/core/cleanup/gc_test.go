@@ -0,0 +1,124 @@
+package cleanup_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/cleanup"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+)
+
+const piecesDir = "/test-data/monkeypuzzle/pieces"
+
+// setupGC creates three merged pieces of 1G each; "new" was active last
+func setupGC(t *testing.T, availableKiB string) (*adapters.MemoryFS, *adapters.MockExec, *cleanup.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := cleanup.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1"}`), 0644)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"old", "mid", "new"} {
+		_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/"+name, 0755)
+		_ = events.Append(fs, "/repo", events.Event{Time: start.Add(time.Duration(i) * time.Hour), Type: "piece.create", Piece: name})
+		mockExec.AddResponse("du", []string{"-sk", piecesDir + "/" + name}, []byte("1048576\t"+name+"\n"), nil)
+	}
+
+	mockExec.AddResponse("df", []string{"-Pk", piecesDir},
+		[]byte("Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/sda1 0 0 "+availableKiB+" 0% /\n"), nil)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"},
+		[]byte("worktree /repo\nbranch refs/heads/main\n\n"+
+			"worktree "+piecesDir+"/new\nbranch refs/heads/new\n\n"+
+			"worktree "+piecesDir+"/mid\nbranch refs/heads/mid\n\n"+
+			"worktree "+piecesDir+"/old\nbranch refs/heads/old\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("feature\n"), nil)
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", "feature"}, nil, nil)
+	mockExec.AddResponse("git", []string{"branch", "--merged", "main"}, []byte("  feature\n  main\n"), nil)
+	for _, name := range []string{"old", "mid", "new"} {
+		mockExec.AddResponse("git", []string{"worktree", "remove", piecesDir + "/" + name}, nil, nil)
+	}
+	return fs, mockExec, handler
+}
+
+func TestHandler_GC_SuggestsOldestMergedPieces(t *testing.T) {
+	// 512M free, 2G wanted: the two oldest pieces are needed
+	_, mockExec, handler := setupGC(t, "524288")
+
+	report, err := handler.GC("/repo", cleanup.GCOptions{MainBranch: "main", MinFree: 2 << 30})
+	if err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	if !report.Met || len(report.Pieces) != 2 || report.Pieces[0].PieceName != "old" || report.Pieces[1].PieceName != "mid" {
+		t.Fatalf("expected old and mid suggested, got %+v", report)
+	}
+	if report.FreedBytes != 2<<30 || report.Pieces[0].Removed {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if mockExec.WasCalled("git", "worktree", "remove", piecesDir+"/old") {
+		t.Error("expected nothing removed without Remove")
+	}
+}
+
+func TestHandler_GC_Remove(t *testing.T) {
+	_, mockExec, handler := setupGC(t, "524288")
+
+	report, err := handler.GC("/repo", cleanup.GCOptions{MainBranch: "main", MinFree: 1 << 30, Remove: true})
+	if err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	if len(report.Pieces) != 1 || !report.Pieces[0].Removed || !report.Met {
+		t.Fatalf("expected the oldest piece removed, got %+v", report)
+	}
+	if !mockExec.WasCalled("git", "worktree", "remove", piecesDir+"/old") || mockExec.WasCalled("git", "worktree", "remove", piecesDir+"/mid") {
+		t.Error("expected only the oldest worktree removed")
+	}
+}
+
+func TestHandler_GC_TargetUnreachableOrMet(t *testing.T) {
+	_, _, handler := setupGC(t, "0")
+	report, err := handler.GC("/repo", cleanup.GCOptions{MainBranch: "main", MinFree: 10 << 30})
+	if err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	if report.Met || len(report.Pieces) != 3 {
+		t.Errorf("expected every merged piece suggested and the target missed, got %+v", report)
+	}
+
+	_, _, handler = setupGC(t, "20971520")
+	report, err = handler.GC("/repo", cleanup.GCOptions{MainBranch: "main", MinFree: 10 << 30})
+	if err != nil || !report.Met || len(report.Pieces) != 0 {
+		t.Errorf("expected nothing to do with enough free space, got %+v (%v)", report, err)
+	}
+}
+
+func TestHandler_GC_ObjectsAndRepack(t *testing.T) {
+	fs, mockExec, handler := setupGC(t, "0")
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/old/.git/objects", 0755)
+	mockExec.AddResponse("du", []string{"-sk", piecesDir + "/old/.git/objects"}, []byte("2048\tobjects\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-common-dir"}, []byte("/repo/.git\n"), nil)
+	mockExec.AddResponse("git", []string{"gc", "--quiet"}, nil, nil)
+	mockExec.AddResponse("du", []string{"-sk", "/repo/.git/objects"}, []byte("4096\t/repo/.git/objects\n"), nil)
+
+	report, err := handler.GC("/repo", cleanup.GCOptions{MainBranch: "main", Repack: true})
+	if err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	if len(report.Objects) != 1 || report.Objects[0].PieceName != "old" || report.Objects[0].Shared || report.Objects[0].DuplicatedBytes != 2<<20 {
+		t.Errorf("expected the standalone piece reported, got %+v", report.Objects)
+	}
+	if len(report.Pieces) != 0 || mockExec.WasCalled("df", "-Pk", piecesDir) {
+		t.Error("expected no pieces chosen without MinFree")
+	}
+	if report.Repack == nil || report.Repack.GitDir != "/repo/.git" || report.Repack.AfterBytes != 4<<20 {
+		t.Errorf("unexpected repack %+v", report.Repack)
+	}
+	if !mockExec.WasCalled("git", "gc", "--quiet") {
+		t.Error("expected git gc to run")
+	}
+}
@@ -0,0 +1,136 @@
+// Package cleanup runs all monkeypuzzle maintenance tasks in one pass.
+package cleanup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Maintenance steps, in the order they run
+const (
+	StepPieces   = "pieces"
+	StepPrune    = "worktree-prune"
+	StepSessions = "tmux-sessions"
+	StepArchive  = "issue-archive"
+	StepEvents   = "events-rotate"
+)
+
+// Options configures a maintenance run
+type Options struct {
+	// MainBranch is the branch merged pieces are detected against
+	MainBranch string
+	// MaxEventsBytes is the events log size that triggers rotation (default: events.DefaultMaxBytes)
+	MaxEventsBytes int64
+}
+
+// StepError records a failed maintenance step; later steps still run
+type StepError struct {
+	Step  string `json:"step"`
+	Error string `json:"error"`
+}
+
+// Report is the consolidated result of a maintenance run
+type Report struct {
+	Time            time.Time             `json:"time"`
+	RepoRoot        string                `json:"repo_root"`
+	OK              bool                  `json:"ok"`
+	Pieces          []piece.CleanupResult `json:"pieces"`
+	PrunedWorktrees []string              `json:"pruned_worktrees"`
+	KilledSessions  []string              `json:"killed_sessions"`
+	ArchivedIssues  []string              `json:"archived_issues"`
+	EventsRotated   bool                  `json:"events_rotated"`
+	Errors          []StepError           `json:"errors,omitempty"`
+}
+
+// Handler executes the cleanup command
+type Handler struct {
+	deps core.Deps
+}
+
+// NewHandler creates a new cleanup handler with dependencies
+func NewHandler(deps core.Deps) *Handler {
+	return &Handler{deps: deps}
+}
+
+// RunAll removes merged pieces, prunes stale worktree metadata, kills tmux
+// sessions of removed pieces, archives done issues, and rotates the events log.
+// A failing step is recorded in the report and does not stop the others.
+func (h *Handler) RunAll(repoRoot string, opts Options) (Report, error) {
+	if opts.MaxEventsBytes <= 0 {
+		opts.MaxEventsBytes = events.DefaultMaxBytes
+	}
+
+	report := Report{
+		Time:            time.Now().UTC(),
+		RepoRoot:        repoRoot,
+		Pieces:          []piece.CleanupResult{},
+		PrunedWorktrees: []string{},
+		KilledSessions:  []string{},
+		ArchivedIssues:  []string{},
+	}
+	pieces := piece.NewHandler(h.deps)
+
+	if results, err := pieces.CleanupMergedPieces(repoRoot, piece.CleanupOptions{MainBranch: opts.MainBranch}); err != nil {
+		report.fail(StepPieces, err)
+	} else if results != nil {
+		report.Pieces = results
+	}
+
+	if pruned, err := adapters.NewGit(h.deps.Exec).WorktreePrune(repoRoot); err != nil {
+		report.fail(StepPrune, err)
+	} else if pruned != nil {
+		report.PrunedWorktrees = pruned
+	}
+
+	if killed, err := pieces.KillStaleSessions(); err != nil {
+		report.fail(StepSessions, err)
+	} else if killed != nil {
+		report.KilledSessions = killed
+	}
+
+	if archived, err := issue.NewHandler(h.deps, repoRoot).ArchiveDone(); err != nil {
+		report.fail(StepArchive, err)
+	} else if archived != nil {
+		report.ArchivedIssues = archived
+	}
+
+	if rotated, err := events.Rotate(h.deps.FS, repoRoot, opts.MaxEventsBytes); err != nil {
+		report.fail(StepEvents, err)
+	} else {
+		report.EventsRotated = rotated
+	}
+
+	report.OK = len(report.Errors) == 0
+	for _, e := range report.Errors {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("%s: %s", e.Step, e.Error),
+		})
+	}
+	if report.OK {
+		cleaned := 0
+		for _, p := range report.Pieces {
+			if p.Cleaned() {
+				cleaned++
+			}
+		}
+		h.deps.Output.Write(core.Message{
+			Type: core.MsgSuccess,
+			Content: fmt.Sprintf("Cleanup done: %d pieces, %d pruned worktrees, %d stale sessions, %d archived issues",
+				cleaned, len(report.PrunedWorktrees), len(report.KilledSessions), len(report.ArchivedIssues)),
+			Data: report,
+		})
+	}
+
+	return report, nil
+}
+
+func (r *Report) fail(step string, err error) {
+	r.Errors = append(r.Errors, StepError{Step: step, Error: err.Error()})
+}
@@ -0,0 +1,101 @@
+package cleanup_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/cleanup"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+)
+
+func setupRepo(t *testing.T) (*adapters.MemoryFS, *adapters.MockExec, *cleanup.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := cleanup.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(`{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}}}`), 0644)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/issues/shipped.md", []byte("---\ntitle: Shipped\nstatus: done\n---\n"), 0644)
+	_ = fs.WriteFile("repo/issues/open.md", []byte("---\ntitle: Open\nstatus: todo\n---\n"), 0644)
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/alive", 0755)
+	_ = events.Append(fs, "/repo", events.Event{Type: "piece.create", Piece: "alive"})
+
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"},
+		[]byte("worktree /repo\nbranch refs/heads/main\n\nworktree /test-data/monkeypuzzle/pieces/alive\nbranch refs/heads/alive\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("alive\n"), nil)
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", "alive"}, []byte("abc\trefs/heads/alive\n"), nil)
+	mockExec.AddResponse("git", []string{"branch", "--merged", "main"}, []byte("  main\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "alive"}, []byte("abc\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "main"}, []byte("def\n"), nil)
+	mockExec.AddResponse("git", []string{"merge-base", "--is-ancestor", "abc", "main"}, nil, adapters.MockError("exit status 1"))
+	mockExec.AddResponse("git", []string{"worktree", "prune", "--verbose"},
+		[]byte("Removing worktrees/old: gitdir file points to non-existent location\n"), nil)
+
+	return fs, mockExec, handler
+}
+
+func TestHandler_RunAll(t *testing.T) {
+	fs, mockExec, handler := setupRepo(t)
+	mockExec.AddResponse("tmux", []string{"list-sessions", "-F", "#{session_name}"},
+		[]byte("mp-piece-alive\nmp-piece-gone\nwork\n"), nil)
+	mockExec.AddResponse("tmux", []string{"kill-session", "-t", "mp-piece-gone"}, nil, nil)
+
+	report, err := handler.RunAll("/repo", cleanup.Options{MainBranch: "main", MaxEventsBytes: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !report.OK {
+		t.Fatalf("expected all steps to succeed, got errors: %+v", report.Errors)
+	}
+
+	if len(report.Pieces) != 0 {
+		t.Errorf("expected unmerged piece to be kept, got %+v", report.Pieces)
+	}
+	if len(report.PrunedWorktrees) != 1 {
+		t.Errorf("expected one pruned worktree, got %v", report.PrunedWorktrees)
+	}
+	if len(report.KilledSessions) != 1 || report.KilledSessions[0] != "mp-piece-gone" {
+		t.Errorf("expected only the stale piece session to be killed, got %v", report.KilledSessions)
+	}
+	if len(report.ArchivedIssues) != 1 || report.ArchivedIssues[0] != "issues/archive/shipped.md" {
+		t.Errorf("expected done issue to be archived, got %v", report.ArchivedIssues)
+	}
+	if _, err := fs.ReadFile("repo/issues/shipped.md"); err == nil {
+		t.Error("expected archived issue to be moved out of the issues directory")
+	}
+	if _, err := fs.ReadFile("repo/issues/open.md"); err != nil {
+		t.Error("expected open issue to stay in place")
+	}
+	if !report.EventsRotated {
+		t.Error("expected events log to be rotated")
+	}
+	if _, err := fs.ReadFile("repo/.monkeypuzzle/" + events.RotatedFilename); err != nil {
+		t.Errorf("expected rotated events log: %v", err)
+	}
+}
+
+func TestHandler_RunAll_ContinuesAfterFailedStep(t *testing.T) {
+	_, mockExec, handler := setupRepo(t)
+	mockExec.AddResponse("tmux", []string{"list-sessions", "-F", "#{session_name}"},
+		nil, adapters.MockError("executable file not found"))
+
+	report, err := handler.RunAll("/repo", cleanup.Options{MainBranch: "main"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if report.OK || len(report.Errors) != 1 || report.Errors[0].Step != cleanup.StepSessions {
+		t.Fatalf("expected only the tmux step to fail, got %+v", report.Errors)
+	}
+	if len(report.ArchivedIssues) != 1 {
+		t.Error("expected later steps to still run")
+	}
+	if report.EventsRotated {
+		t.Error("expected small events log not to be rotated")
+	}
+}
@@ -0,0 +1,304 @@
+package cleanup
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Schedulers used to run cleanup in the background
+const (
+	SchedulerSystemd = "systemd"
+	SchedulerLaunchd = "launchd"
+)
+
+// DefaultScheduleTime is the daily time (HH:MM, local) scheduled cleanup runs at
+const DefaultScheduleTime = "03:00"
+
+// launchdLabelPrefix prefixes launchd job labels
+const launchdLabelPrefix = "com.monkeypuzzle."
+
+// ScheduleOptions configures scheduled cleanup
+type ScheduleOptions struct {
+	// MpPath is the mp binary the job runs
+	MpPath string
+	// Time is the daily run time as HH:MM (default: DefaultScheduleTime)
+	Time string
+	// GOOS selects the scheduler (default: runtime.GOOS)
+	GOOS string
+}
+
+// Schedule describes the scheduled cleanup job of a repository
+type Schedule struct {
+	Scheduler string   `json:"scheduler"`
+	Name      string   `json:"name"`
+	Files     []string `json:"files"`
+	LogPath   string   `json:"log_path"`
+	Time      string   `json:"time,omitempty"`
+	Installed bool     `json:"installed"`
+	Active    bool     `json:"active"`
+}
+
+// InstallSchedule writes a user-level systemd timer (Linux) or launchd agent (macOS)
+// running `mp cleanup --all --json` daily in repoRoot, appending to a log, and loads it.
+func (h *Handler) InstallSchedule(repoRoot string, opts ScheduleOptions) (Schedule, error) {
+	opts = withScheduleDefaults(opts)
+	hour, minute, err := parseScheduleTime(opts.Time)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	sched, err := scheduleFor(repoRoot, opts.GOOS)
+	if err != nil {
+		return Schedule{}, err
+	}
+	sched.Time = opts.Time
+
+	if err := h.deps.FS.MkdirAll(filepath.Dir(sched.LogPath), initcmd.DefaultDirPerm); err != nil {
+		return Schedule{}, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := h.deps.FS.MkdirAll(filepath.Dir(sched.Files[0]), initcmd.DefaultDirPerm); err != nil {
+		return Schedule{}, fmt.Errorf("failed to create %s directory: %w", sched.Scheduler, err)
+	}
+
+	command := fmt.Sprintf("cd %s && %s cleanup --all --json >> %s 2>&1",
+		adapters.ShellQuote(repoRoot), adapters.ShellQuote(opts.MpPath), adapters.ShellQuote(sched.LogPath))
+
+	switch sched.Scheduler {
+	case SchedulerSystemd:
+		service := fmt.Sprintf("[Unit]\nDescription=monkeypuzzle cleanup for %s\n\n[Service]\nType=oneshot\nExecStart=/bin/sh -c %s\n",
+			repoRoot, systemdQuote(command))
+		timer := fmt.Sprintf("[Unit]\nDescription=Daily monkeypuzzle cleanup for %s\n\n[Timer]\nOnCalendar=*-*-* %02d:%02d:00\nPersistent=true\n\n[Install]\nWantedBy=timers.target\n",
+			repoRoot, hour, minute)
+		if err := h.writeUnit(sched.Files[0], service); err != nil {
+			return Schedule{}, err
+		}
+		if err := h.writeUnit(sched.Files[1], timer); err != nil {
+			return Schedule{}, err
+		}
+		if err := h.run("systemctl", "--user", "daemon-reload"); err != nil {
+			return Schedule{}, err
+		}
+		if err := h.run("systemctl", "--user", "enable", "--now", sched.Name+".timer"); err != nil {
+			return Schedule{}, err
+		}
+
+	case SchedulerLaunchd:
+		plist := launchdPlist(launchdLabelPrefix+sched.Name, command, hour, minute)
+		if err := h.writeUnit(sched.Files[0], plist); err != nil {
+			return Schedule{}, err
+		}
+		// Reload so an updated plist takes effect
+		_ = h.run("launchctl", "unload", sched.Files[0])
+		if err := h.run("launchctl", "load", "-w", sched.Files[0]); err != nil {
+			return Schedule{}, err
+		}
+	}
+
+	sched.Installed = true
+	sched.Active = true
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Scheduled daily cleanup at %s via %s (log: %s)", opts.Time, sched.Scheduler, sched.LogPath),
+		Data:    sched,
+	})
+	return sched, nil
+}
+
+// ScheduleStatus reports whether scheduled cleanup is installed and loaded for repoRoot
+func (h *Handler) ScheduleStatus(repoRoot string, opts ScheduleOptions) (Schedule, error) {
+	opts = withScheduleDefaults(opts)
+	sched, err := scheduleFor(repoRoot, opts.GOOS)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	sched.Installed = true
+	for _, f := range sched.Files {
+		if _, err := h.deps.FS.Stat(f); err != nil {
+			sched.Installed = false
+		}
+	}
+
+	switch sched.Scheduler {
+	case SchedulerSystemd:
+		output, err := h.deps.Exec.Run("systemctl", "--user", "is-active", sched.Name+".timer")
+		sched.Active = err == nil && strings.TrimSpace(string(output)) == "active"
+	case SchedulerLaunchd:
+		_, err := h.deps.Exec.Run("launchctl", "list", launchdLabelPrefix+sched.Name)
+		sched.Active = err == nil
+	}
+
+	return sched, nil
+}
+
+// UninstallSchedule unloads and removes the scheduled cleanup job of repoRoot
+func (h *Handler) UninstallSchedule(repoRoot string, opts ScheduleOptions) (Schedule, error) {
+	opts = withScheduleDefaults(opts)
+	sched, err := scheduleFor(repoRoot, opts.GOOS)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	// Unloading fails if the job was never loaded; removal below still applies
+	switch sched.Scheduler {
+	case SchedulerSystemd:
+		_ = h.run("systemctl", "--user", "disable", "--now", sched.Name+".timer")
+	case SchedulerLaunchd:
+		_ = h.run("launchctl", "unload", "-w", sched.Files[0])
+	}
+
+	for _, f := range sched.Files {
+		if _, err := h.deps.FS.Stat(f); err != nil {
+			continue
+		}
+		if err := h.deps.FS.Remove(f); err != nil {
+			return Schedule{}, fmt.Errorf("failed to remove %s: %w", f, err)
+		}
+	}
+
+	if sched.Scheduler == SchedulerSystemd {
+		_ = h.run("systemctl", "--user", "daemon-reload")
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Removed scheduled cleanup %s", sched.Name),
+		Data:    sched,
+	})
+	return sched, nil
+}
+
+func withScheduleDefaults(opts ScheduleOptions) ScheduleOptions {
+	if opts.MpPath == "" {
+		opts.MpPath = "mp"
+	}
+	if opts.Time == "" {
+		opts.Time = DefaultScheduleTime
+	}
+	if opts.GOOS == "" {
+		opts.GOOS = runtime.GOOS
+	}
+	return opts
+}
+
+// scheduleFor resolves the job name, unit files, and log path of a repository
+func scheduleFor(repoRoot, goos string) (Schedule, error) {
+	dataDir, err := piece.DataDir()
+	if err != nil {
+		return Schedule{}, err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Schedule{}, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	name := ScheduleName(repoRoot)
+	sched := Schedule{
+		Name:    name,
+		LogPath: filepath.Join(dataDir, "logs", name+".log"),
+	}
+
+	switch goos {
+	case "linux":
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+		unitDir := filepath.Join(configHome, "systemd", "user")
+		sched.Scheduler = SchedulerSystemd
+		sched.Files = []string{
+			filepath.Join(unitDir, name+".service"),
+			filepath.Join(unitDir, name+".timer"),
+		}
+	case "darwin":
+		sched.Scheduler = SchedulerLaunchd
+		sched.Files = []string{filepath.Join(home, "Library", "LaunchAgents", launchdLabelPrefix+name+".plist")}
+	default:
+		return Schedule{}, fmt.Errorf("scheduled cleanup is not supported on %s (use cron with mp cleanup --all --json)", goos)
+	}
+
+	return sched, nil
+}
+
+// ScheduleName returns the job name for a repository: its directory name plus
+// a short hash of the full path, so same-named checkouts don't collide.
+func ScheduleName(repoRoot string) string {
+	sum := sha1.Sum([]byte(filepath.Clean(repoRoot)))
+	return fmt.Sprintf("mp-cleanup-%s-%s", piece.SanitizePieceName(filepath.Base(repoRoot)), hex.EncodeToString(sum[:])[:8])
+}
+
+// parseScheduleTime parses an HH:MM daily time
+func parseScheduleTime(value string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+func (h *Handler) writeUnit(path, content string) error {
+	if err := h.deps.FS.WriteFile(path, []byte(content), initcmd.DefaultFilePerm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (h *Handler) run(name string, args ...string) error {
+	output, err := h.deps.Exec.Run(name, args...)
+	if err != nil {
+		detail := strings.TrimSpace(string(output))
+		if detail == "" {
+			detail = err.Error()
+		}
+		return fmt.Errorf("%s %s failed: %s", name, strings.Join(args, " "), detail)
+	}
+	return nil
+}
+
+// launchdPlist renders a launchd agent running command daily at hour:minute
+func launchdPlist(label, command string, hour, minute int) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>%s</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>/bin/sh</string>
+    <string>-c</string>
+    <string>%s</string>
+  </array>
+  <key>StartCalendarInterval</key>
+  <dict>
+    <key>Hour</key>
+    <integer>%d</integer>
+    <key>Minute</key>
+    <integer>%d</integer>
+  </dict>
+</dict>
+</plist>
+`, xmlEscape(label), xmlEscape(command), hour, minute)
+}
+
+// systemdQuote wraps s in double quotes for a systemd ExecStart line
+func systemdQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$")
+	return `"` + r.Replace(s) + `"`
+}
+
+func xmlEscape(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return r.Replace(s)
+}
@@ -0,0 +1,129 @@
+package cleanup_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/cleanup"
+)
+
+func setupSchedule(t *testing.T) (*adapters.MemoryFS, *adapters.MockExec, *cleanup.Handler) {
+	t.Helper()
+	t.Setenv("HOME", "/home/dev")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := cleanup.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	return fs, mockExec, handler
+}
+
+func TestHandler_InstallSchedule_Systemd(t *testing.T) {
+	fs, mockExec, handler := setupSchedule(t)
+	name := cleanup.ScheduleName("/repo")
+	mockExec.AddResponse("systemctl", []string{"--user", "daemon-reload"}, nil, nil)
+	mockExec.AddResponse("systemctl", []string{"--user", "enable", "--now", name + ".timer"}, nil, nil)
+
+	sched, err := handler.InstallSchedule("/repo", cleanup.ScheduleOptions{MpPath: "/usr/bin/mp", Time: "04:30", GOOS: "linux"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if sched.Scheduler != cleanup.SchedulerSystemd || !sched.Installed {
+		t.Fatalf("unexpected schedule: %+v", sched)
+	}
+
+	service, err := fs.ReadFile("/home/dev/.config/systemd/user/" + name + ".service")
+	if err != nil {
+		t.Fatalf("expected service unit to be written: %v", err)
+	}
+	if !strings.Contains(string(service), "cd '/repo' && '/usr/bin/mp' cleanup --all --json >> '/test-data/monkeypuzzle/logs/"+name+".log'") {
+		t.Errorf("unexpected service unit:\n%s", service)
+	}
+
+	timer, err := fs.ReadFile("/home/dev/.config/systemd/user/" + name + ".timer")
+	if err != nil {
+		t.Fatalf("expected timer unit to be written: %v", err)
+	}
+	if !strings.Contains(string(timer), "OnCalendar=*-*-* 04:30:00") {
+		t.Errorf("unexpected timer unit:\n%s", timer)
+	}
+	if !mockExec.WasCalled("systemctl", "--user", "enable", "--now", name+".timer") {
+		t.Error("expected timer to be enabled")
+	}
+}
+
+func TestHandler_InstallSchedule_Launchd(t *testing.T) {
+	fs, mockExec, handler := setupSchedule(t)
+	name := cleanup.ScheduleName("/repo")
+	plistPath := "/home/dev/Library/LaunchAgents/com.monkeypuzzle." + name + ".plist"
+	mockExec.AddResponse("launchctl", []string{"load", "-w", plistPath}, nil, nil)
+
+	if _, err := handler.InstallSchedule("/repo", cleanup.ScheduleOptions{MpPath: "/usr/local/bin/mp", GOOS: "darwin"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	plist, err := fs.ReadFile(plistPath)
+	if err != nil {
+		t.Fatalf("expected plist to be written: %v", err)
+	}
+	if !strings.Contains(string(plist), "<key>Hour</key>\n    <integer>3</integer>") {
+		t.Errorf("expected default time in plist, got:\n%s", plist)
+	}
+	if !strings.Contains(string(plist), "cleanup --all --json &gt;&gt;") {
+		t.Errorf("expected escaped command in plist, got:\n%s", plist)
+	}
+}
+
+func TestHandler_InstallSchedule_InvalidTime(t *testing.T) {
+	_, _, handler := setupSchedule(t)
+
+	if _, err := handler.InstallSchedule("/repo", cleanup.ScheduleOptions{Time: "25:00", GOOS: "linux"}); err == nil {
+		t.Error("expected error for invalid time")
+	}
+	if _, err := handler.InstallSchedule("/repo", cleanup.ScheduleOptions{GOOS: "windows"}); err == nil {
+		t.Error("expected error for unsupported platform")
+	}
+}
+
+func TestHandler_ScheduleStatusAndUninstall(t *testing.T) {
+	fs, mockExec, handler := setupSchedule(t)
+	name := cleanup.ScheduleName("/repo")
+	opts := cleanup.ScheduleOptions{GOOS: "linux"}
+	mockExec.AddResponse("systemctl", []string{"--user", "daemon-reload"}, nil, nil)
+	mockExec.AddResponse("systemctl", []string{"--user", "enable", "--now", name + ".timer"}, nil, nil)
+	mockExec.AddResponse("systemctl", []string{"--user", "is-active", name + ".timer"}, []byte("active\n"), nil)
+	mockExec.AddResponse("systemctl", []string{"--user", "disable", "--now", name + ".timer"}, nil, nil)
+
+	if _, err := handler.InstallSchedule("/repo", opts); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	status, err := handler.ScheduleStatus("/repo", opts)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !status.Installed || !status.Active {
+		t.Errorf("expected installed and active schedule, got %+v", status)
+	}
+
+	if _, err := handler.UninstallSchedule("/repo", opts); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if _, err := fs.Stat("/home/dev/.config/systemd/user/" + name + ".timer"); err == nil {
+		t.Error("expected timer unit to be removed")
+	}
+}
+
+func TestScheduleName(t *testing.T) {
+	a := cleanup.ScheduleName("/work/app")
+	b := cleanup.ScheduleName("/other/app")
+	if !strings.HasPrefix(a, "mp-cleanup-app-") {
+		t.Errorf("unexpected name %q", a)
+	}
+	if a == b {
+		t.Error("expected same-named repositories to get distinct names")
+	}
+}
@@ -0,0 +1,127 @@
+// Package conventions reads the repository conventions file,
+// .monkeypuzzle/context.md: architecture notes, coding standards and a review
+// checklist, which piece prompts, PR bodies and the MCP server hand to agents
+// so every piece starts from the same project grounding.
+package conventions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// Filename is the conventions file in .monkeypuzzle
+const Filename = "context.md"
+
+// Headings of the "## " sections context.md has
+const (
+	SectionArchitecture = "Architecture"
+	SectionStandards    = "Coding standards"
+	SectionChecklist    = "Review checklist"
+)
+
+// Sections lists the sections context.md has, in order
+var Sections = []string{SectionArchitecture, SectionStandards, SectionChecklist}
+
+// Example is the context.md mp init --with-examples scaffolds, to fill in
+const Example = `# Project context
+
+Read by agents working on a piece (mp piece prompt, the MCP resource
+monkeypuzzle://context) and by reviewers: the review checklist is added to
+generated PR bodies. Keep it short and current.
+
+## Architecture
+
+- What the main packages or services are and how they depend on each other
+- Where new code of each kind goes
+
+## Coding standards
+
+- Error handling, logging and naming conventions
+- How tests are written and where they live
+
+## Review checklist
+
+- [ ] Tests cover the change
+- [ ] Docs are updated for user-facing changes
+- [ ] No unrelated changes
+`
+
+// Context is the contents of context.md
+type Context struct {
+	Content string `json:"content"`
+}
+
+// Path returns the conventions file of the repository at repoRoot
+func Path(repoRoot string) string {
+	return filepath.Join(repoRoot, initcmd.DirName, Filename)
+}
+
+// Load reads the conventions of the repository at repoRoot. A missing file
+// yields an empty Context.
+func Load(fs core.FS, repoRoot string) (Context, error) {
+	data, err := fs.ReadFile(Path(repoRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Context{}, nil
+		}
+		return Context{}, fmt.Errorf("failed to read %s: %w", Filename, err)
+	}
+	return Context{Content: strings.TrimSpace(string(data))}, nil
+}
+
+// Defined reports whether the repository has conventions
+func (c Context) Defined() bool {
+	return c.Content != ""
+}
+
+// Section returns the body of the "## heading" section, matched
+// case-insensitively, or "" when there is none
+func (c Context) Section(heading string) string {
+	var body []string
+	in := false
+	for _, line := range strings.Split(c.Content, "\n") {
+		if title, ok := strings.CutPrefix(line, "## "); ok {
+			if in {
+				break
+			}
+			in = strings.EqualFold(strings.TrimSpace(title), heading)
+			continue
+		}
+		if in {
+			body = append(body, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(body, "\n"))
+}
+
+// Missing returns the sections of Sections the conventions lack or leave empty
+func (c Context) Missing() []string {
+	var missing []string
+	for _, s := range Sections {
+		if c.Section(s) == "" {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// Scaffold writes Example as the conventions of the repository at repoRoot,
+// keeping an existing file. It reports whether the file was written.
+func Scaffold(fs core.FS, repoRoot string) (bool, error) {
+	path := Path(repoRoot)
+	if _, err := fs.Stat(path); err == nil {
+		return false, nil
+	}
+	if err := fs.MkdirAll(filepath.Dir(path), initcmd.DefaultDirPerm); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := fs.WriteFile(path, []byte(Example), initcmd.DefaultFilePerm); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", Filename, err)
+	}
+	return true, nil
+}
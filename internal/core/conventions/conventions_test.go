@@ -0,0 +1,51 @@
+package conventions_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/conventions"
+)
+
+func TestContext_Section(t *testing.T) {
+	c := conventions.Context{Content: "# Context\n\n## Architecture\n\nOne binary.\n\n## review CHECKLIST\n\n- [ ] Tests\n"}
+
+	if got := c.Section(conventions.SectionChecklist); got != "- [ ] Tests" {
+		t.Errorf("expected the checklist matched case-insensitively, got %q", got)
+	}
+	if got := c.Section(conventions.SectionArchitecture); got != "One binary." {
+		t.Errorf("expected the section to end at the next heading, got %q", got)
+	}
+	if got := c.Missing(); !reflect.DeepEqual(got, []string{conventions.SectionStandards}) {
+		t.Errorf("expected only coding standards missing, got %v", got)
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+
+	written, err := conventions.Scaffold(fs, "/repo")
+	if err != nil || !written {
+		t.Fatalf("expected context.md to be written, got %v, %v", written, err)
+	}
+	c, err := conventions.Load(fs, "/repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if missing := c.Missing(); len(missing) != 0 {
+		t.Errorf("expected the example to have every section, missing %v", missing)
+	}
+
+	_ = fs.WriteFile(conventions.Path("/repo"), []byte("## Architecture\n\nOurs\n"), 0644)
+	if written, err := conventions.Scaffold(fs, "/repo"); err != nil || written {
+		t.Errorf("expected an existing context.md to be kept, got %v, %v", written, err)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	c, err := conventions.Load(adapters.NewMemoryFS(), "/repo")
+	if err != nil || c.Defined() {
+		t.Errorf("expected no conventions without context.md, got %+v, %v", c, err)
+	}
+}
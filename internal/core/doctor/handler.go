@@ -0,0 +1,108 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Check is the result of a single diagnostic check
+type Check struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+	// Skippable checks need the network or credentials (e.g. gh auth) and
+	// can be skipped in CI with LintOptions.SkipProviders
+	Skippable bool `json:"skippable,omitempty"`
+	Skipped   bool `json:"skipped,omitempty"`
+}
+
+// Report contains the results of all doctor checks
+type Report struct {
+	RepoRoot string  `json:"repo_root"`
+	OK       bool    `json:"ok"`
+	Checks   []Check `json:"checks"`
+}
+
+// Handler executes the doctor command
+type Handler struct {
+	deps core.Deps
+}
+
+// NewHandler creates a new doctor handler with dependencies
+func NewHandler(deps core.Deps) *Handler {
+	return &Handler{deps: deps}
+}
+
+// Run diagnoses the monkeypuzzle setup of the repository at repoRoot.
+// Failed checks are written as warnings; the report is always returned.
+func (h *Handler) Run(repoRoot string) (Report, error) {
+	report := Report{RepoRoot: repoRoot}
+
+	cfg, err := piece.ReadConfig(repoRoot, h.deps.FS)
+	if err != nil {
+		report.add(Check{Name: "config", Message: fmt.Sprintf("%v (run mp init first)", err)})
+	} else {
+		report.add(Check{Name: "config", OK: true})
+		if cfg.RepoRoot != "" {
+			report.add(checkRepoRoot(repoRoot, cfg.RepoRoot))
+		}
+		for _, pc := range initcmd.CheckProviders(h.deps, repoRoot, *cfg) {
+			report.add(providerCheck(pc))
+		}
+	}
+
+	h.finish(&report)
+	return report, nil
+}
+
+// finish sets report.OK and writes a warning per failed check, or a success
+// message if all passed. Skipped checks count as passed.
+func (h *Handler) finish(report *Report) {
+	report.OK = true
+	for _, c := range report.Checks {
+		if !c.OK && !c.Skipped {
+			report.OK = false
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("%s: %s", c.Name, c.Message),
+			})
+		}
+	}
+
+	if report.OK {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgSuccess,
+			Content: "All checks passed",
+			Data:    *report,
+		})
+	}
+}
+
+// checkRepoRoot compares the repo_root recorded by mp init with the repository
+// the config was found in; a mismatch means the config was copied or moved
+func checkRepoRoot(repoRoot, recorded string) Check {
+	check := Check{Name: "repo_root", OK: true}
+	if resolved := filepath.Join(repoRoot, filepath.FromSlash(recorded)); resolved != filepath.Clean(repoRoot) {
+		check.OK = false
+		check.Message = fmt.Sprintf("config records repo_root %q (%s) but was found in %s (re-run mp init --yes here)", recorded, resolved, repoRoot)
+	}
+	return check
+}
+
+// providerCheck reports a provider self-check
+func providerCheck(pc initcmd.ProviderCheck) Check {
+	return Check{
+		Name:      fmt.Sprintf("%s provider (%s)", pc.Kind, pc.Provider),
+		OK:        pc.OK,
+		Message:   pc.Message,
+		Skippable: true,
+	}
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+}
@@ -0,0 +1,109 @@
+package doctor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/doctor"
+)
+
+const testConfig = `{
+  "version": "1",
+  "project": {"name": "test"},
+  "issues": {"provider": "markdown", "config": {"directory": "issues"}},
+  "pr": {"provider": "github", "config": {}}
+}`
+
+func TestHandler_Run_AllChecksPass(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := doctor.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(testConfig), 0644)
+	mockExec.AddResponse("gh", []string{"auth", "status"}, []byte("Logged in\n"), nil)
+
+	report, err := handler.Run("/repo")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !report.OK {
+		t.Errorf("expected report OK, got %+v", report.Checks)
+	}
+	if len(report.Checks) != 3 {
+		t.Errorf("expected 3 checks, got %d", len(report.Checks))
+	}
+	if !out.HasSuccess() {
+		t.Error("expected success message")
+	}
+}
+
+func TestHandler_Run_MissingConfig(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	handler := doctor.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: out, Exec: adapters.NewMockExec()})
+
+	report, err := handler.Run("/repo")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.OK {
+		t.Error("expected report to fail without config")
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Name != "config" {
+		t.Errorf("expected only config check, got %+v", report.Checks)
+	}
+	if !out.HasWarning() {
+		t.Error("expected warning for missing config")
+	}
+}
+
+func TestHandler_Run_ProviderFailure(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := doctor.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(testConfig), 0644)
+	mockExec.AddResponse("gh", []string{"auth", "status"}, nil, adapters.MockError("exit status 1"))
+
+	report, _ := handler.Run("/repo")
+	if report.OK {
+		t.Error("expected report to fail when gh is not authenticated")
+	}
+	if report.Checks[2].Name != "pr provider (github)" || report.Checks[2].OK {
+		t.Errorf("expected failed github check, got %+v", report.Checks[2])
+	}
+}
+
+func TestHandler_Run_RepoRootMismatch(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := doctor.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	config := strings.Replace(testConfig, `"version": "1",`, `"version": "1", "repo_root": "..",`, 1)
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(config), 0644)
+	mockExec.AddResponse("gh", []string{"auth", "status"}, []byte("Logged in\n"), nil)
+
+	report, _ := handler.Run("/repo")
+	if report.OK {
+		t.Fatal("expected report to fail for a mismatched repo_root")
+	}
+	if report.Checks[1].Name != "repo_root" || report.Checks[1].OK {
+		t.Errorf("expected failing repo_root check, got %+v", report.Checks[1])
+	}
+
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(strings.Replace(config, `"repo_root": ".."`, `"repo_root": "."`, 1)), 0644)
+	if report, _ := handler.Run("/repo"); !report.OK {
+		t.Errorf("expected repo_root . to pass, got %+v", report.Checks)
+	}
+}
@@ -0,0 +1,173 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/childenv"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+)
+
+// LintOptions configures Lint
+type LintOptions struct {
+	// SkipProviders skips the skippable provider checks, e.g. in CI without
+	// gh credentials
+	SkipProviders bool
+}
+
+// Lint checks the repository's monkeypuzzle configuration strictly enough to
+// gate changes to it in CI: everything Run checks, plus unknown or invalid
+// config fields and hook scripts that would not run.
+// Failed checks are written as warnings; the report is always returned.
+func (h *Handler) Lint(repoRoot string, opts LintOptions) (Report, error) {
+	report := Report{RepoRoot: repoRoot}
+
+	data, err := h.deps.FS.ReadFile(filepath.Join(repoRoot, initcmd.DirName, initcmd.ConfigFile))
+	if err != nil {
+		report.add(Check{Name: "config", Message: "config not found (run mp init first)"})
+		h.finish(&report)
+		return report, nil
+	}
+
+	var cfg initcmd.Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		// Retry leniently so an unknown field does not hide the other checks
+		if lenient := json.Unmarshal(data, &cfg); lenient != nil {
+			report.add(Check{Name: "config", Message: fmt.Sprintf("invalid JSON: %v", lenient)})
+			h.finish(&report)
+			return report, nil
+		}
+		report.add(Check{Name: "config", Message: err.Error()})
+	} else {
+		report.add(Check{Name: "config", OK: true})
+	}
+
+	report.add(checkConfigValues(cfg))
+	if cfg.RepoRoot != "" {
+		report.add(checkRepoRoot(repoRoot, cfg.RepoRoot))
+	}
+	for _, c := range h.checkHooks(repoRoot) {
+		report.add(c)
+	}
+	if opts.SkipProviders {
+		for _, pc := range []initcmd.ProviderCheck{
+			{Kind: initcmd.ProviderKindIssue, Provider: cfg.Issues.Provider},
+			{Kind: initcmd.ProviderKindPR, Provider: cfg.PR.Provider},
+		} {
+			check := providerCheck(pc)
+			check.Skipped = true
+			report.add(check)
+		}
+	} else {
+		for _, pc := range initcmd.CheckProviders(h.deps, repoRoot, cfg) {
+			report.add(providerCheck(pc))
+		}
+	}
+
+	h.finish(&report)
+	return report, nil
+}
+
+// checkConfigValues validates config values the JSON types do not constrain
+func checkConfigValues(cfg initcmd.Config) Check {
+	var problems []string
+	err := initcmd.Validate(initcmd.Input{Name: cfg.Project.Name, IssueProvider: cfg.Issues.Provider, PRProvider: cfg.PR.Provider})
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	if cfg.Version == "" {
+		problems = append(problems, "version is required")
+	}
+	if err := piece.ValidateSandbox(cfg.Hooks.Sandbox); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if cfg.Pieces.WIPLimit < 0 {
+		problems = append(problems, fmt.Sprintf("pieces.wip_limit must not be negative, got %d", cfg.Pieces.WIPLimit))
+	}
+	if err := piece.ValidateMaxNameLength(cfg.Pieces.MaxNameLength); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := piece.ValidateMergeStrategy(cfg.Merge.Strategy); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if cfg.Pieces.AutoCheckpointMinutes < 0 {
+		problems = append(problems, fmt.Sprintf("pieces.auto_checkpoint_minutes must not be negative, got %d", cfg.Pieces.AutoCheckpointMinutes))
+	}
+	if err := cfg.Issues.ValidateFields(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := piece.ValidateAgentLimits(cfg.Agent.Limits); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := redact.New(cfg.Redact.Patterns); err != nil {
+		problems = append(problems, fmt.Sprintf("redact.patterns: %v", err))
+	}
+	if _, err := childenv.New(cfg.Env); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSpace(cfg.Aliases[name]) == "" {
+			problems = append(problems, fmt.Sprintf("alias %s has no command", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return Check{Name: "config values", Message: strings.Join(problems, "; ")}
+	}
+	return Check{Name: "config values", OK: true}
+}
+
+// checkHooks checks that each script in the hooks directory is a known hook,
+// starts with a shebang line and is executable; hooks failing these are
+// skipped or fail when run
+func (h *Handler) checkHooks(repoRoot string) []Check {
+	hooksDir := filepath.Join(repoRoot, piece.HooksDir)
+	entries, err := h.deps.FS.ReadDir(hooksDir)
+	if err != nil {
+		return nil
+	}
+
+	var checks []Check
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		check := Check{Name: "hook " + entry.Name(), OK: true}
+		hookPath := filepath.Join(hooksDir, entry.Name())
+
+		var problems []string
+		if !slices.Contains(piece.Hooks, entry.Name()) {
+			problems = append(problems, fmt.Sprintf("not a known hook, it never runs (hooks: %s)", strings.Join(piece.Hooks, ", ")))
+		}
+		if content, err := h.deps.FS.ReadFile(hookPath); err != nil {
+			problems = append(problems, fmt.Sprintf("cannot read: %v", err))
+		} else if !bytes.HasPrefix(content, []byte("#!")) {
+			problems = append(problems, "missing shebang line (e.g. #!/bin/sh)")
+		}
+		if info, err := h.deps.FS.Stat(hookPath); err == nil && info.Mode()&0111 == 0 {
+			problems = append(problems, fmt.Sprintf("not executable (chmod +x %s)", filepath.Join(piece.HooksDir, entry.Name())))
+		}
+
+		if len(problems) > 0 {
+			check.OK = false
+			check.Message = strings.Join(problems, "; ")
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,114 @@
+package doctor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/doctor"
+)
+
+func setupLint(t *testing.T, config string) (*adapters.MemoryFS, *adapters.MockExec, *doctor.Handler) {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	_ = fs.MkdirAll("repo/.monkeypuzzle/hooks", 0755)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(config), 0644)
+	return fs, mockExec, doctor.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+}
+
+// failed returns "name: message" for each failed, unskipped check
+func failed(report doctor.Report) []string {
+	var names []string
+	for _, c := range report.Checks {
+		if !c.OK && !c.Skipped {
+			names = append(names, c.Name+": "+c.Message)
+		}
+	}
+	return names
+}
+
+func TestHandler_Lint_Passes(t *testing.T) {
+	fs, mockExec, handler := setupLint(t, testConfig)
+	_ = fs.WriteFile("repo/.monkeypuzzle/hooks/on-piece-create.sh", []byte("#!/bin/sh\nnpm install\n"), 0755)
+	mockExec.AddResponse("gh", []string{"auth", "status"}, []byte("Logged in\n"), nil)
+
+	report, err := handler.Lint("/repo", doctor.LintOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !report.OK {
+		t.Errorf("expected report OK, got %v", failed(report))
+	}
+}
+
+func TestHandler_Lint_ReportsProblems(t *testing.T) {
+	config := `{
+  "version": "1",
+  "project": {"name": "test"},
+  "issues": {"provider": "jira", "config": {"directory": "issues"}},
+  "pr": {"provider": "github", "config": {}},
+  "hooks": {"sandbox": "docker"},
+  "merge": {"strategy": "octopus"},
+  "redact": {"patterns": ["("]},
+  "aliasses": {"start": "piece new"}
+}`
+	fs, _, handler := setupLint(t, config)
+	_ = fs.WriteFile("repo/.monkeypuzzle/hooks/on-piece-create.sh", []byte("npm install\n"), 0644)
+	_ = fs.WriteFile("repo/.monkeypuzzle/hooks/after-create.sh", []byte("#!/bin/sh\n"), 0755)
+
+	report, err := handler.Lint("/repo", doctor.LintOptions{SkipProviders: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.OK {
+		t.Fatal("expected problems")
+	}
+
+	problems := failed(report)
+	want := []string{
+		`config: json: unknown field "aliasses"`,
+		"config values: validation failed: [issue_provider must be one of: [markdown]]",
+		`invalid hooks.sandbox "docker"`,
+		`merge.strategy must be one of squash, merge, rebase-ff, got "octopus"`,
+		"redact.patterns: invalid redaction pattern",
+		"hook after-create.sh: not a known hook",
+		"hook on-piece-create.sh: missing shebang line (e.g. #!/bin/sh); not executable",
+	}
+	joined := strings.Join(problems, "\n")
+	for _, w := range want {
+		if !strings.Contains(joined, w) {
+			t.Errorf("expected problem %q in:\n%s", w, joined)
+		}
+	}
+	if len(problems) != 4 {
+		t.Errorf("expected 4 failed checks, got %d:\n%s", len(problems), joined)
+	}
+}
+
+func TestHandler_Lint_SkipProviders(t *testing.T) {
+	_, mockExec, handler := setupLint(t, testConfig)
+
+	report, err := handler.Lint("/repo", doctor.LintOptions{SkipProviders: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !report.OK {
+		t.Errorf("expected report OK with providers skipped, got %v", failed(report))
+	}
+	if mockExec.WasCalled("gh", "auth", "status") {
+		t.Error("expected gh not to be called with providers skipped")
+	}
+
+	skipped := 0
+	for _, c := range report.Checks {
+		if c.Skipped {
+			skipped++
+		}
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 skipped provider checks, got %d", skipped)
+	}
+}
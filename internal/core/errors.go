@@ -0,0 +1,164 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/messages"
+)
+
+// Codes of common failure modes that have a known fix.
+// They are reported as "code" in JSON error output, and are the message IDs
+// of the error and its hint (code + ".hint") in the messages catalog.
+const (
+	CodeGHNotAuthenticated  = "gh_not_authenticated"
+	CodeTmuxNotInstalled    = "tmux_not_installed"
+	CodeMainBranchMissing   = "main_branch_missing"
+	CodeDetachedHead        = "detached_head"
+	CodeDirtyWorktree       = "dirty_worktree"
+	CodePieceLocked         = "piece_locked"
+	CodeNotInPiece          = "not_in_piece"
+	CodeNotInRepo           = "not_in_repo"
+	CodeOperationInProgress = "operation_in_progress"
+	CodeNestedRepo          = "nested_repo"
+	CodeBranchExists        = "branch_exists"
+	CodeProtectedBranch     = "protected_branch"
+)
+
+// RemediableError is an error with a short "how to fix" hint
+type RemediableError struct {
+	Code string
+	Hint string
+	Err  error
+}
+
+func (e *RemediableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RemediableError) Unwrap() error {
+	return e.Err
+}
+
+// AsRemediable finds a RemediableError in err's chain
+func AsRemediable(err error) (*RemediableError, bool) {
+	var re *RemediableError
+	if errors.As(err, &re) {
+		return re, true
+	}
+	return nil, false
+}
+
+// NewGHNotAuthenticatedError wraps a gh failure caused by missing GitHub credentials
+func NewGHNotAuthenticatedError(err error) error {
+	return &RemediableError{
+		Code: CodeGHNotAuthenticated,
+		Hint: messages.T(messages.GHNotAuthenticatedHint),
+		Err:  err,
+	}
+}
+
+// NewTmuxNotInstalledError wraps a tmux failure caused by tmux not being on PATH
+func NewTmuxNotInstalledError(err error) error {
+	return &RemediableError{
+		Code: CodeTmuxNotInstalled,
+		Hint: messages.T(messages.TmuxNotInstalledHint),
+		Err:  err,
+	}
+}
+
+// NewMainBranchMissingError wraps a failure caused by branch not existing
+func NewMainBranchMissingError(branch string, err error) error {
+	return &RemediableError{
+		Code: CodeMainBranchMissing,
+		Hint: messages.T(messages.MainBranchMissingHint, branch),
+		Err:  err,
+	}
+}
+
+// NewDetachedHeadError reports that workDir has no branch checked out
+func NewDetachedHeadError(workDir string) error {
+	return &RemediableError{
+		Code: CodeDetachedHead,
+		Hint: messages.T(messages.DetachedHeadHint, workDir),
+		Err:  errors.New(messages.T(messages.DetachedHead, workDir)),
+	}
+}
+
+// NewDirtyWorktreeError wraps a failure caused by uncommitted changes in path
+func NewDirtyWorktreeError(path string, err error) error {
+	return &RemediableError{
+		Code: CodeDirtyWorktree,
+		Hint: messages.T(messages.DirtyWorktreeHint, path, path),
+		Err:  err,
+	}
+}
+
+// NewPieceLockedError reports that a piece's worktree is locked against removal
+func NewPieceLockedError(pieceName string) error {
+	return &RemediableError{
+		Code: CodePieceLocked,
+		Hint: messages.T(messages.PieceLockedHint, pieceName),
+		Err:  errors.New(messages.T(messages.PieceLocked, pieceName)),
+	}
+}
+
+// NewNotInPieceError reports that a command needing a piece ran outside one
+func NewNotInPieceError() error {
+	return &RemediableError{
+		Code: CodeNotInPiece,
+		Hint: messages.T(messages.NotInPieceHint),
+		Err:  errors.New(messages.T(messages.NotInPiece)),
+	}
+}
+
+// NewNotInRepoError reports that a command needing a repository ran outside one
+func NewNotInRepoError() error {
+	return &RemediableError{
+		Code: CodeNotInRepo,
+		Hint: messages.T(messages.NotInRepoHint),
+		Err:  errors.New(messages.T(messages.NotInRepo)),
+	}
+}
+
+// NewOperationInProgressError reports that a git operation (rebase, merge,
+// cherry-pick, ...) was left unfinished in repoRoot
+func NewOperationInProgressError(operation, repoRoot string) error {
+	return &RemediableError{
+		Code: CodeOperationInProgress,
+		Hint: messages.T(messages.OperationInProgressHint, operation, repoRoot, repoRoot, operation),
+		Err:  errors.New(messages.T(messages.OperationInProgress, operation, repoRoot)),
+	}
+}
+
+// NewNestedRepoError reports that repoRoot, where a command ran, is a
+// repository nested inside the piece worktree pieceWorktree (e.g. a vendored
+// sub-repository), so the command would act on the wrong repository
+func NewNestedRepoError(repoRoot, pieceWorktree string) error {
+	return &RemediableError{
+		Code: CodeNestedRepo,
+		Hint: messages.T(messages.NestedRepoHint, pieceWorktree, repoRoot),
+		Err:  errors.New(messages.T(messages.NestedRepo, repoRoot, pieceWorktree)),
+	}
+}
+
+// NewBranchExistsError reports that a new piece's branch already exists, e.g.
+// left over from an earlier piece
+func NewBranchExistsError(branch string) error {
+	return &RemediableError{
+		Code: CodeBranchExists,
+		Hint: messages.T(messages.BranchExistsHint, branch, branch),
+		Err:  errors.New(messages.T(messages.BranchExists, branch)),
+	}
+}
+
+// NewProtectedBranchError reports that branch protection on GitHub blocks
+// pushing to branch, e.g. because it requires pull requests; err gives the
+// rules or the rejected push
+func NewProtectedBranchError(branch string, err error) error {
+	return &RemediableError{
+		Code: CodeProtectedBranch,
+		Hint: messages.T(messages.ProtectedBranchHint, branch, branch),
+		Err:  fmt.Errorf("%s: %w", messages.T(messages.ProtectedBranch, branch), err),
+	}
+}
@@ -0,0 +1,162 @@
+package events
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// Problem is an events log entry that failed verification
+type Problem struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// Verification is the result of checking the events log hash chain
+type Verification struct {
+	Valid bool `json:"valid"`
+	// Entries counts all entries, Chained those carrying a hash
+	Entries int `json:"entries"`
+	Chained int `json:"chained"`
+	// Anchor is the prev hash of the oldest chained entry still on disk. It is
+	// empty for a chain that starts in this log; otherwise it refers to an entry
+	// dropped by rotation and should match an archived copy.
+	Anchor string `json:"anchor,omitempty"`
+	// Head is the hash of the newest entry. Recording it elsewhere lets later
+	// verifications detect entries removed from the end of the log.
+	Head     string    `json:"head,omitempty"`
+	Problems []Problem `json:"problems,omitempty"`
+}
+
+// readConfig returns the repository config, or an empty config if it is missing
+func readConfig(fs core.FS, repoRoot string) (initcmd.Config, error) {
+	var cfg initcmd.Config
+	path := filepath.Join(repoRoot, initcmd.DirName, initcmd.ConfigFile)
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// chain links event to the newest entry of the log (or, right after rotation,
+// of the rotated log) and sets its hash
+func chain(fs core.FS, repoRoot string, existing []byte, event *Event) error {
+	prev := lastHash(existing)
+	if len(bytes.TrimSpace(existing)) == 0 {
+		rotated, _ := fs.ReadFile(filepath.Join(repoRoot, initcmd.DirName, RotatedFilename))
+		prev = lastHash(rotated)
+	}
+
+	event.Prev = prev
+	hash, err := eventHash(*event)
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+	return nil
+}
+
+// eventHash returns the SHA-256 of an event's JSON encoding without its hash.
+// Prev is part of the encoding, which links each entry to the one before it.
+func eventHash(event Event) (string, error) {
+	event.Hash = ""
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lastHash returns the hash of the last entry in a log, if it has one
+func lastHash(data []byte) string {
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	last := lines[len(lines)-1]
+	if len(last) == 0 {
+		return ""
+	}
+	var entry struct {
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(last, &entry); err != nil {
+		return ""
+	}
+	return entry.Hash
+}
+
+// Verify checks the hash chain of the repository's events log, including the
+// rotated log. Entries written before hash_chain was enabled are accepted only
+// ahead of the first chained entry.
+func Verify(fs core.FS, repoRoot string) (*Verification, error) {
+	v := &Verification{}
+	started := false
+
+	for _, name := range []string{RotatedFilename, Filename} {
+		data, err := fs.ReadFile(filepath.Join(repoRoot, initcmd.DirName, name))
+		if err != nil {
+			continue
+		}
+
+		for i, line := range bytes.Split(data, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			v.Entries++
+			fail := func(reason string) {
+				v.Problems = append(v.Problems, Problem{File: name, Line: i + 1, Reason: reason})
+			}
+
+			// Decode numbers as written so re-encoding reproduces the hashed bytes
+			var event Event
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+			if err := decoder.Decode(&event); err != nil {
+				fail(fmt.Sprintf("invalid JSON: %v", err))
+				continue
+			}
+
+			if event.Hash == "" {
+				if started {
+					fail("entry is not chained")
+				}
+				continue
+			}
+			v.Chained++
+
+			if !started {
+				started = true
+				v.Anchor = event.Prev
+			} else if event.Prev != v.Head {
+				fail("prev hash does not match the previous entry (entries removed or reordered)")
+			}
+
+			hash, err := eventHash(event)
+			if err != nil {
+				return nil, err
+			}
+			if hash != event.Hash {
+				fail("hash does not match entry contents (entry modified)")
+			}
+			v.Head = event.Hash
+		}
+	}
+
+	v.Valid = len(v.Problems) == 0
+	return v, nil
+}
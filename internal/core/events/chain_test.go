@@ -0,0 +1,118 @@
+package events_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+)
+
+// setupChainedLog writes a hash-chained log of three events
+func setupChainedLog(t *testing.T) *adapters.MemoryFS {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"events":{"hash_chain":true}}`), 0644)
+	for _, piece := range []string{"p1", "p2", "p3"} {
+		event := events.Event{Type: "piece.create", Piece: piece, Data: map[string]any{"n": 1.5, "tag": "<x>"}}
+		if err := events.Append(fs, "/repo", event); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+	return fs
+}
+
+func TestAppend_HashChain(t *testing.T) {
+	fs := setupChainedLog(t)
+
+	got, err := events.Read(fs, "/repo")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if got[0].Prev != "" || got[0].Hash == "" {
+		t.Errorf("expected first entry to start the chain, got %+v", got[0])
+	}
+	if got[1].Prev != got[0].Hash || got[2].Prev != got[1].Hash {
+		t.Error("expected each entry to reference the previous hash")
+	}
+
+	v, err := events.Verify(fs, "/repo")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !v.Valid || v.Entries != 3 || v.Chained != 3 || v.Head != got[2].Hash {
+		t.Errorf("expected valid chain of 3 ending at the last hash, got %+v", v)
+	}
+}
+
+func TestAppend_NoChainByDefault(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	if err := events.Append(fs, "/repo", events.Event{Type: "piece.create", Hash: "forged"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	data, _ := fs.ReadFile("repo/.monkeypuzzle/events.jsonl")
+	if bytes.Contains(data, []byte("hash")) {
+		t.Errorf("expected no hash without events.hash_chain, got %s", data)
+	}
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func([]byte) []byte
+		line   int
+	}{
+		{
+			name:   "modified entry",
+			tamper: func(b []byte) []byte { return bytes.Replace(b, []byte(`"piece":"p2"`), []byte(`"piece":"px"`), 1) },
+			line:   2,
+		},
+		{
+			name: "removed entry",
+			tamper: func(b []byte) []byte {
+				lines := bytes.SplitAfter(b, []byte("\n"))
+				return append(append([]byte{}, lines[0]...), lines[2]...)
+			},
+			line: 2,
+		},
+		{
+			name:   "inserted unchained entry",
+			tamper: func(b []byte) []byte { return append(b, []byte("{\"type\":\"piece.remove\"}\n")...) },
+			line:   4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := setupChainedLog(t)
+			data, _ := fs.ReadFile("repo/.monkeypuzzle/events.jsonl")
+			_ = fs.WriteFile("repo/.monkeypuzzle/events.jsonl", tt.tamper(data), 0644)
+
+			v, err := events.Verify(fs, "/repo")
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if v.Valid || len(v.Problems) != 1 || v.Problems[0].Line != tt.line {
+				t.Errorf("expected one problem on line %d, got %+v", tt.line, v)
+			}
+		})
+	}
+}
+
+func TestVerify_ContinuesAcrossRotation(t *testing.T) {
+	fs := setupChainedLog(t)
+	if _, err := events.Rotate(fs, "/repo", 0); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if err := events.Append(fs, "/repo", events.Event{Type: "piece.remove", Piece: "p1"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	v, err := events.Verify(fs, "/repo")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !v.Valid || v.Chained != 4 || v.Anchor != "" {
+		t.Errorf("expected the chain to continue across rotation, got %+v", v)
+	}
+}
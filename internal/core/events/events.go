@@ -0,0 +1,133 @@
+// Package events records an append-only log of notable monkeypuzzle actions.
+// Events are stored as newline-delimited JSON in .monkeypuzzle/events.jsonl
+// of the main repository.
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+)
+
+const (
+	// Filename is the events log file name inside .monkeypuzzle
+	Filename = "events.jsonl"
+	// RotatedFilename holds the previous log after rotation
+	RotatedFilename = Filename + ".1"
+	// DefaultMaxBytes is the log size above which Rotate starts a new log
+	DefaultMaxBytes = 1 << 20
+)
+
+// Event is a single entry in the events log
+type Event struct {
+	Time  time.Time      `json:"time"`
+	Type  string         `json:"type"`
+	Piece string         `json:"piece,omitempty"`
+	Data  map[string]any `json:"data,omitempty"`
+	// Prev and Hash chain entries together when events.hash_chain is enabled
+	Prev string `json:"prev,omitempty"`
+	Hash string `json:"hash,omitempty"`
+}
+
+// Path returns the events log path for a repository
+func Path(repoRoot string) string {
+	return filepath.Join(repoRoot, initcmd.DirName, Filename)
+}
+
+// Append adds an event to the repository's events log.
+// The event time defaults to now when unset. Secrets in event data are
+// redacted, and with events.hash_chain enabled the entry is linked to the
+// previous one by hash.
+func Append(fs core.FS, repoRoot string, event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	path := Path(repoRoot)
+	existing, err := fs.ReadFile(path)
+	if err != nil {
+		existing = nil
+	}
+
+	cfg, err := readConfig(fs, repoRoot)
+	if err != nil {
+		return err
+	}
+	if event.Data != nil {
+		r, err := redact.New(cfg.Redact.Patterns)
+		if err != nil {
+			return fmt.Errorf("invalid redact.patterns: %w", err)
+		}
+		event.Data = r.Value(event.Data).(map[string]any)
+	}
+
+	event.Prev, event.Hash = "", ""
+	if cfg.Events.HashChain {
+		if err := chain(fs, repoRoot, existing, &event); err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	data := append(existing, line...)
+	data = append(data, '\n')
+	if err := fs.WriteFile(path, data, initcmd.DefaultFilePerm); err != nil {
+		return fmt.Errorf("failed to write events log: %w", err)
+	}
+	return nil
+}
+
+// Read returns all events in the repository's events log.
+// A missing log yields no events.
+func Read(fs core.FS, repoRoot string) ([]Event, error) {
+	data, err := fs.ReadFile(Path(repoRoot))
+	if err != nil {
+		return nil, nil
+	}
+
+	var result []Event
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("invalid event on line %d: %w", i+1, err)
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+// Rotate moves the events log to RotatedFilename, replacing any earlier
+// rotation, once it grows beyond maxBytes. Returns true if the log was rotated.
+func Rotate(fs core.FS, repoRoot string, maxBytes int64) (bool, error) {
+	path := Path(repoRoot)
+	info, err := fs.Stat(path)
+	if err != nil || info.Size() <= maxBytes {
+		return false, nil
+	}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read events log: %w", err)
+	}
+	rotated := filepath.Join(filepath.Dir(path), RotatedFilename)
+	if err := fs.WriteFile(rotated, data, initcmd.DefaultFilePerm); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", RotatedFilename, err)
+	}
+	if err := fs.Remove(path); err != nil {
+		return false, fmt.Errorf("failed to remove events log: %w", err)
+	}
+	return true, nil
+}
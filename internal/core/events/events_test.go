@@ -0,0 +1,93 @@
+package events_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+
+	if err := events.Append(fs, "/repo", events.Event{Type: "pr.address", Piece: "p1", Data: map[string]any{"pr_number": 42}}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := events.Append(fs, "/repo", events.Event{Type: "pr.address", Piece: "p2"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	got, err := events.Read(fs, "/repo")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if got[0].Piece != "p1" || got[1].Piece != "p2" {
+		t.Errorf("events out of order: %+v", got)
+	}
+	if got[0].Time.IsZero() {
+		t.Error("expected event time to be set")
+	}
+	if got[0].Data["pr_number"] != float64(42) {
+		t.Errorf("expected pr_number 42, got %v", got[0].Data["pr_number"])
+	}
+}
+
+func TestRead_MissingLog(t *testing.T) {
+	got, err := events.Read(adapters.NewMemoryFS(), "/repo")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no events, got %d", len(got))
+	}
+}
+
+func TestRead_InvalidLine(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	_ = fs.WriteFile("repo/.monkeypuzzle/events.jsonl", []byte("{\"type\":\"ok\"}\nnot json\n"), 0644)
+
+	if _, err := events.Read(fs, "/repo"); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
+
+func TestAppend_RedactsData(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	token := "ghp_" + strings.Repeat("x", 36)
+	if err := events.Append(fs, "/repo", events.Event{Type: "hook.failed", Data: map[string]any{"output": "auth " + token}}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	data, _ := fs.ReadFile("repo/.monkeypuzzle/events.jsonl")
+	if strings.Contains(string(data), token) || !strings.Contains(string(data), "auth [REDACTED]") {
+		t.Errorf("expected token to be redacted, got %s", data)
+	}
+}
+
+func TestAppend_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"unparsable config", `{"redact":`},
+		{"invalid pattern", `{"redact":{"patterns":["("]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := adapters.NewMemoryFS()
+			_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(tt.config), 0644)
+
+			if err := events.Append(fs, "/repo", events.Event{Type: "hook.failed", Data: map[string]any{"output": "x"}}); err == nil {
+				t.Fatal("expected an error")
+			}
+			if _, err := fs.ReadFile("repo/.monkeypuzzle/events.jsonl"); err == nil {
+				t.Error("expected nothing to be logged")
+			}
+		})
+	}
+}
@@ -0,0 +1,399 @@
+// Package filter implements the filter expressions of list commands, e.g.
+// `status=todo AND labels~infra AND created<30d`.
+//
+// An expression is clauses joined by AND and OR (AND binds tighter). A clause
+// compares a field with a value:
+//
+//	=   equal (case-insensitive)        !=  not equal
+//	~   contains (case-insensitive)     !~  does not contain
+//	<   less than                       <=  less than or equal
+//	>   greater than                    >=  greater than or equal
+//
+// List fields match when any element does (none, for != and !~). Ordering
+// compares numbers numerically and times chronologically; a duration value
+// (30m, 12h, 30d, 2w) compares a time's age, so created<30d means created in
+// the last 30 days. Values with spaces are quoted: title~"login page".
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Fields are the values of a record by lowercase field name; list fields have
+// several values. Nested objects are flattened with dots (tasks.done).
+type Fields map[string][]string
+
+// Expr is a parsed filter expression
+type Expr struct {
+	source string
+	root   node
+}
+
+// String returns the expression as written
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Match reports whether fields satisfy the expression, measuring durations
+// back from now. A nil Expr matches everything.
+func (e *Expr) Match(fields Fields, now time.Time) bool {
+	if e == nil {
+		return true
+	}
+	return e.root.match(fields, now)
+}
+
+// Parse parses a filter expression
+func Parse(expr string) (*Expr, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+	return &Expr{source: expr, root: root}, nil
+}
+
+// ParseAll parses several expressions that must all match, e.g. from a
+// repeated --filter flag. No expressions give a nil Expr.
+func ParseAll(exprs []string) (*Expr, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	var all allOf
+	for _, expr := range exprs {
+		parsed, err := Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, parsed.root)
+	}
+	if len(all) == 1 {
+		return &Expr{source: exprs[0], root: all[0]}, nil
+	}
+	return &Expr{source: "(" + strings.Join(exprs, ") AND (") + ")", root: all}, nil
+}
+
+// JSONFields returns the fields of v's JSON encoding, named by their JSON keys
+func JSONFields(v any) (Fields, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	fields := Fields{}
+	flatten(fields, "", decoded)
+	return fields, nil
+}
+
+// flatten adds the values of a decoded JSON value to fields under prefix
+func flatten(fields Fields, prefix string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			name := strings.ToLower(key)
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			flatten(fields, name, value)
+		}
+	case []any:
+		if _, ok := fields[prefix]; !ok {
+			fields[prefix] = []string{}
+		}
+		for _, item := range v {
+			flatten(fields, prefix, item)
+		}
+	case nil:
+	case string:
+		fields[prefix] = append(fields[prefix], v)
+	case float64:
+		fields[prefix] = append(fields[prefix], strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		fields[prefix] = append(fields[prefix], fmt.Sprint(v))
+	}
+}
+
+// lookup returns the values of a field; a singular name (label) finds a
+// plural list field (labels)
+func (f Fields) lookup(name string) []string {
+	name = strings.ToLower(name)
+	if values, ok := f[name]; ok {
+		return values
+	}
+	return f[name+"s"]
+}
+
+type node interface {
+	match(fields Fields, now time.Time) bool
+}
+
+type anyOf []node
+
+func (n anyOf) match(fields Fields, now time.Time) bool {
+	for _, child := range n {
+		if child.match(fields, now) {
+			return true
+		}
+	}
+	return false
+}
+
+type allOf []node
+
+func (n allOf) match(fields Fields, now time.Time) bool {
+	for _, child := range n {
+		if !child.match(fields, now) {
+			return false
+		}
+	}
+	return true
+}
+
+// clause compares a field with a value
+type clause struct {
+	field, op, value string
+}
+
+func (c clause) match(fields Fields, now time.Time) bool {
+	values := fields.lookup(c.field)
+	switch c.op {
+	case "!=":
+		return !(clause{c.field, "=", c.value}).match(fields, now)
+	case "!~":
+		return !(clause{c.field, "~", c.value}).match(fields, now)
+	}
+	for _, value := range values {
+		if c.compare(value, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare applies the clause's operator to one field value
+func (c clause) compare(value string, now time.Time) bool {
+	switch c.op {
+	case "=":
+		return strings.EqualFold(value, c.value)
+	case "~":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(c.value))
+	}
+
+	cmp, ok := order(value, c.value, now)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// order compares a field value with a clause value: as ages when the clause
+// value is a duration, then as times, numbers, and strings
+func order(value, against string, now time.Time) (int, bool) {
+	if d, ok := parseDuration(against); ok {
+		t, ok := parseTime(value)
+		if !ok {
+			return 0, false
+		}
+		return compareFloat(float64(now.Sub(t)), float64(d)), true
+	}
+	if t, ok := parseTime(value); ok {
+		if u, ok := parseTime(against); ok {
+			return t.Compare(u), true
+		}
+	}
+	if a, err := strconv.ParseFloat(value, 64); err == nil {
+		if b, err := strconv.ParseFloat(against, 64); err == nil {
+			return compareFloat(a, b), true
+		}
+	}
+	return strings.Compare(strings.ToLower(value), strings.ToLower(against)), true
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// parseDuration parses durations with a unit of m, h, d or w, e.g. 30d
+func parseDuration(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	unit, ok := units[s[len(s)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// parseTime parses RFC 3339 times and dates (2006-01-02, as UTC midnight)
+func parseTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// token kinds
+const (
+	tokenWord = iota
+	tokenOp
+)
+
+type token struct {
+	kind int
+	text string
+	// quoted words are never keywords
+	quoted bool
+}
+
+// operators, longest first so "<=" is not read as "<"
+var operators = []string{"!=", "!~", "<=", ">=", "=", "~", "<", ">"}
+
+// tokenize splits an expression into words, quoted strings and operators
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[i+1 : i+1+end], quoted: true})
+			i += end + 2
+		default:
+			if op := operatorAt(s[i:]); op != "" {
+				tokens = append(tokens, token{kind: tokenOp, text: op})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(s) && !unicode.IsSpace(rune(s[i])) && s[i] != '"' && s[i] != '\'' && operatorAt(s[i:]) == "" {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+// operatorAt returns the operator s starts with, if any
+func operatorAt(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// keyword reports whether the next token is the keyword kw, consuming it
+func (p *parser) keyword(kw string) bool {
+	if p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		if t.kind == tokenWord && !t.quoted && strings.EqualFold(t.text, kw) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	var alternatives anyOf
+	for {
+		n, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, n)
+		if !p.keyword("OR") {
+			break
+		}
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return alternatives, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	var clauses allOf
+	for {
+		c, err := p.parseClause()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, c)
+		if !p.keyword("AND") {
+			break
+		}
+	}
+	if len(clauses) == 1 {
+		return clauses[0], nil
+	}
+	return clauses, nil
+}
+
+func (p *parser) parseClause() (node, error) {
+	if p.pos+3 > len(p.tokens) {
+		return nil, fmt.Errorf("expected field, operator and value, e.g. status=todo")
+	}
+	field, op, value := p.tokens[p.pos], p.tokens[p.pos+1], p.tokens[p.pos+2]
+	if field.kind != tokenWord || field.quoted {
+		return nil, fmt.Errorf("expected a field name, got %q", field.text)
+	}
+	if op.kind != tokenOp {
+		return nil, fmt.Errorf("expected an operator (%s) after %q, got %q", strings.Join(operators, " "), field.text, op.text)
+	}
+	if value.kind != tokenWord {
+		return nil, fmt.Errorf("expected a value after %s%s", field.text, op.text)
+	}
+	p.pos += 3
+	return clause{field: field.text, op: op.text, value: value.text}, nil
+}
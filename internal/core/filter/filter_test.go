@@ -0,0 +1,82 @@
+package filter_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+)
+
+func TestExpr_Match(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	fields, err := filter.JSONFields(map[string]any{
+		"status":  "todo",
+		"title":   "Add login page",
+		"labels":  []string{"infra", "ui"},
+		"created": "2026-02-15T00:00:00Z",
+		"tasks":   map[string]int{"done": 2, "total": 10},
+		"dirty":   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"status=todo", true},
+		{"Status = TODO", true},
+		{"status!=todo", false},
+		{"label~infra", true},
+		{"labels=infra AND labels=ui", true},
+		{"labels!=backend", true},
+		{"labels!~inf", false},
+		{`title~"login page"`, true},
+		{"created<30d", true},
+		{"created<1w", false},
+		{"created>2026-02-01", true},
+		{"tasks.done>=2 AND tasks.total<9", false},
+		{"tasks.total>9", true},
+		{"dirty=true", true},
+		{"status=done OR label=ui", true},
+		{"status=done OR label=backend AND dirty=true", false},
+		{"status=todo AND missing=x OR dirty=true", true},
+		{"missing!=x", true},
+	}
+	for _, tt := range tests {
+		expr, err := filter.Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := expr.Match(fields, now); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"status", "status=", "=todo", "status=todo AND", "status=todo status=done", `title~"open`} {
+		if _, err := filter.Parse(expr); err == nil || !strings.Contains(err.Error(), "invalid filter") {
+			t.Errorf("Parse(%q): expected invalid filter error, got %v", expr, err)
+		}
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	expr, err := filter.ParseAll([]string{"status=todo OR status=done", "label=ui"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := filter.Fields{"status": {"done"}, "labels": {"infra"}}
+	// Each flag is grouped: (todo OR done) AND ui
+	if expr.Match(fields, time.Now()) {
+		t.Errorf("expected %s not to match %v", expr, fields)
+	}
+
+	if expr, err := filter.ParseAll(nil); err != nil || !expr.Match(fields, time.Now()) {
+		t.Errorf("expected no filters to match everything, got %v, %v", expr, err)
+	}
+}
@@ -0,0 +1,151 @@
+package init
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// DefaultBranch is the initial branch name used by InitGit
+const DefaultBranch = "main"
+
+// InitialCommitMessage is the message of the scaffolding commit made by InitGit
+const InitialCommitMessage = "Initialize monkeypuzzle"
+
+// GitInitResult describes what InitGit did
+type GitInitResult struct {
+	Initialized bool   `json:"initialized"`
+	Branch      string `json:"branch,omitempty"`
+	Committed   bool   `json:"committed"`
+}
+
+// InitGit turns workDir into a git repository with branch as its default branch
+// and commits the monkeypuzzle scaffolding. Run after Run so the scaffolding exists.
+// If workDir is already inside a git repository, nothing is changed.
+func (h *Handler) InitGit(workDir, branch string) (*GitInitResult, error) {
+	if branch == "" {
+		branch = DefaultBranch
+	}
+
+	if _, err := h.deps.Exec.RunWithDir(workDir, "git", "rev-parse", "--git-dir"); err == nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: "Already a git repository; skipping git init",
+		})
+		return &GitInitResult{}, nil
+	}
+
+	if output, err := h.deps.Exec.RunWithDir(workDir, "git", "init", "--initial-branch", branch); err != nil {
+		return nil, fmt.Errorf("git init failed: %s", gitErrorDetail(output, err))
+	}
+	result := &GitInitResult{Initialized: true, Branch: branch}
+
+	// Record the new root before committing so the committed config includes it
+	if _, err := h.RecordRepoRoot(workDir); err != nil {
+		return nil, err
+	}
+
+	// Empty directories aren't tracked, so keep the issues directory with a placeholder
+	paths := []string{DirName}
+	if info, err := h.deps.FS.Stat("issues"); err == nil && info.IsDir() {
+		keep := filepath.Join("issues", ".gitkeep")
+		if err := h.deps.FS.WriteFile(keep, nil, DefaultFilePerm); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", keep, err)
+		}
+		paths = append(paths, "issues")
+	}
+
+	if output, err := h.deps.Exec.RunWithDir(workDir, "git", append([]string{"add", "--"}, paths...)...); err != nil {
+		return nil, fmt.Errorf("git add failed: %s", gitErrorDetail(output, err))
+	}
+	if output, err := h.deps.Exec.RunWithDir(workDir, "git", "commit", "-m", InitialCommitMessage); err != nil {
+		return nil, fmt.Errorf("git commit failed (is user.name/user.email configured?): %s", gitErrorDetail(output, err))
+	}
+	result.Committed = true
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Initialized git repository on branch %s and committed scaffolding", branch),
+		Data:    result,
+	})
+
+	return result, nil
+}
+
+// RepoRoot returns the top level of the git repository containing workDir,
+// or "" if workDir is not inside one
+func (h *Handler) RepoRoot(workDir string) string {
+	output, err := h.deps.Exec.RunWithDir(workDir, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// RecordRepoRoot stores the root of the repository containing workDir in the
+// config written by Run, relative to workDir. Outside a repository nothing is
+// recorded and a warning explains how to create one. Run from a subdirectory,
+// it warns that mp only finds config at the repository root.
+func (h *Handler) RecordRepoRoot(workDir string) (string, error) {
+	root := h.RepoRoot(workDir)
+	if root == "" {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: "Not a git repository: piece commands will fail until one exists. Run 'git init' here, or re-run with 'mp init --git --yes' to create it and commit the scaffolding",
+		})
+		return "", nil
+	}
+
+	rel, err := filepath.Rel(resolvePath(workDir), resolvePath(root))
+	if err != nil {
+		return "", fmt.Errorf("failed to locate repository root %s: %w", root, err)
+	}
+	rel = filepath.ToSlash(rel)
+
+	configPath := filepath.Join(DirName, ConfigFile)
+	data, err := h.deps.FS.ReadFile(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	cfg.RepoRoot = rel
+	data, err = json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := h.deps.FS.WriteFile(configPath, data, DefaultFilePerm); err != nil {
+		return "", err
+	}
+
+	if rel != "." {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Initialized in a subdirectory of the git repository at %s; mp looks for %s at the repository root, so piece commands won't find this config", root, DirName),
+		})
+	}
+
+	return rel, nil
+}
+
+// resolvePath follows symlinks so git's resolved top level compares equal to
+// the working directory, leaving paths that can't be resolved unchanged
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// gitErrorDetail prefers git's own message over the bare exit status
+func gitErrorDetail(output []byte, err error) string {
+	if detail := strings.TrimSpace(string(output)); detail != "" {
+		return detail
+	}
+	return err.Error()
+}
@@ -0,0 +1,166 @@
+package init_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+func TestHandler_InitGit_CreatesRepoAndCommits(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := initcmd.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	if err := handler.Run(initcmd.Input{Name: "p", IssueProvider: "markdown", PRProvider: "github"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, nil, errors.New("not a git repository"))
+	mockExec.AddResponse("git", []string{"init", "--initial-branch", "trunk"}, nil, nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/project\n"), nil)
+	mockExec.AddResponse("git", []string{"add", "--", ".monkeypuzzle", "issues"}, nil, nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", initcmd.InitialCommitMessage}, nil, nil)
+
+	result, err := handler.InitGit("/project", "trunk")
+	if err != nil {
+		t.Fatalf("InitGit failed: %v", err)
+	}
+	if !result.Initialized || !result.Committed || result.Branch != "trunk" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if _, err := fs.ReadFile("issues/.gitkeep"); err != nil {
+		t.Error("expected issues/.gitkeep placeholder")
+	}
+	if !mockExec.WasCalled("git", "commit", "-m", initcmd.InitialCommitMessage) {
+		t.Error("expected scaffolding commit")
+	}
+	if cfg := readConfig(t, fs); cfg.RepoRoot != "." {
+		t.Errorf("expected repo_root . in the committed config, got %q", cfg.RepoRoot)
+	}
+}
+
+func TestHandler_InitGit_SkipsExistingRepo(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := initcmd.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: out, Exec: mockExec})
+
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(".git\n"), nil)
+
+	result, err := handler.InitGit("/project", "")
+	if err != nil {
+		t.Fatalf("InitGit failed: %v", err)
+	}
+	if result.Initialized || result.Committed {
+		t.Errorf("expected nothing to be done, got %+v", result)
+	}
+	if !out.HasWarning() {
+		t.Error("expected warning about existing repository")
+	}
+	if mockExec.WasCalled("git", "init", "--initial-branch", "main") {
+		t.Error("expected git init not to run")
+	}
+}
+
+func TestHandler_InitGit_CommitFails(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := initcmd.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, nil, errors.New("not a git repository"))
+	mockExec.AddResponse("git", []string{"init", "--initial-branch", "main"}, nil, nil)
+	mockExec.AddResponse("git", []string{"add", "--", ".monkeypuzzle"}, nil, nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", initcmd.InitialCommitMessage},
+		[]byte("Author identity unknown\n"), errors.New("exit status 128"))
+
+	if _, err := handler.InitGit("/project", ""); err == nil {
+		t.Fatal("expected error when commit fails")
+	}
+}
+
+func TestHandler_RecordRepoRoot(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := initcmd.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	if err := handler.Run(initcmd.Input{Name: "p", IssueProvider: "markdown", PRProvider: "github"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/project\n"), nil)
+
+	root, err := handler.RecordRepoRoot("/project")
+	if err != nil {
+		t.Fatalf("RecordRepoRoot failed: %v", err)
+	}
+	if root != "." {
+		t.Errorf("expected ., got %q", root)
+	}
+	if cfg := readConfig(t, fs); cfg.RepoRoot != "." || cfg.Project.Name != "p" {
+		t.Errorf("expected repo_root recorded alongside existing config, got %+v", cfg)
+	}
+	if out.HasWarning() {
+		t.Errorf("expected no warning at the repository root, got %+v", out.Last())
+	}
+}
+
+func TestHandler_RecordRepoRoot_Subdirectory(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := initcmd.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = handler.Run(initcmd.Input{Name: "p", IssueProvider: "markdown", PRProvider: "github"})
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/mono\n"), nil)
+
+	root, err := handler.RecordRepoRoot("/mono/services/api")
+	if err != nil {
+		t.Fatalf("RecordRepoRoot failed: %v", err)
+	}
+	if root != "../.." {
+		t.Errorf("expected ../.., got %q", root)
+	}
+	if !out.HasWarning() {
+		t.Error("expected warning about initializing in a subdirectory")
+	}
+}
+
+func TestHandler_RecordRepoRoot_NotARepo(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := initcmd.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = handler.Run(initcmd.Input{Name: "p", IssueProvider: "markdown", PRProvider: "github"})
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"},
+		[]byte("fatal: not a git repository\n"), errors.New("exit status 128"))
+
+	root, err := handler.RecordRepoRoot("/project")
+	if err != nil {
+		t.Fatalf("RecordRepoRoot failed: %v", err)
+	}
+	if root != "" {
+		t.Errorf("expected nothing recorded, got %q", root)
+	}
+	if !out.HasWarning() {
+		t.Fatal("expected warning outside a git repository")
+	}
+	if cfg := readConfig(t, fs); cfg.RepoRoot != "" {
+		t.Errorf("expected no repo_root, got %q", cfg.RepoRoot)
+	}
+}
+
+func readConfig(t *testing.T, fs *adapters.MemoryFS) initcmd.Config {
+	t.Helper()
+	data, err := fs.ReadFile(".monkeypuzzle/monkeypuzzle.json")
+	if err != nil {
+		t.Fatalf("expected config: %v", err)
+	}
+	var cfg initcmd.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	return cfg
+}
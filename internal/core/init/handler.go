@@ -23,6 +23,22 @@ type Config struct {
 	Project ProjectConfig `json:"project"`
 	Issues  IssueConfig   `json:"issues"`
 	PR      PRConfig      `json:"pr"`
+	Hooks   HooksConfig   `json:"hooks,omitzero"`
+	Agent   AgentConfig   `json:"agent,omitzero"`
+	Git     GitConfig     `json:"git,omitzero"`
+	Pieces  PiecesConfig  `json:"pieces,omitzero"`
+	Merge   MergeConfig   `json:"merge,omitzero"`
+	Events  EventsConfig  `json:"events,omitzero"`
+	Redact  RedactConfig  `json:"redact,omitzero"`
+	Env     EnvConfig     `json:"env,omitzero"`
+	// Aliases maps command names to mp command lines, e.g. "start": "piece new --issue"
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Editor is the command line `mp piece open` opens pieces with, e.g. "code -n";
+	// $VISUAL and $EDITOR are used when it is empty
+	Editor string `json:"editor,omitempty"`
+	// RepoRoot is the git repository root relative to the directory holding
+	// .monkeypuzzle, recorded by init; "." when initialized at the root
+	RepoRoot string `json:"repo_root,omitempty"`
 }
 
 type ProjectConfig struct {
@@ -32,6 +48,13 @@ type ProjectConfig struct {
 type IssueConfig struct {
 	Provider string            `json:"provider"`
 	Config   map[string]string `json:"config"`
+	// RequireApproval stages edits agents make to issues through mp-mcp until
+	// a human runs mp issue approve
+	RequireApproval bool `json:"require_approval,omitempty"`
+	// Fields defines the project's own frontmatter fields, by name
+	Fields map[string]IssueField `json:"fields,omitempty"`
+	// Directories holds config.directories, the one list-valued setting; see Dirs
+	Directories []string `json:"-"`
 }
 
 type PRConfig struct {
@@ -39,6 +62,84 @@ type PRConfig struct {
 	Config   map[string]string `json:"config"`
 }
 
+// HooksConfig configures how hook scripts are executed
+type HooksConfig struct {
+	// Sandbox selects an optional sandbox runner for hooks ("bwrap" or "sandbox-exec")
+	Sandbox string `json:"sandbox,omitempty"`
+	// Writable lists extra paths left writable inside the sandbox
+	Writable []string `json:"writable,omitempty"`
+}
+
+// AgentConfig configures the coding agent monkeypuzzle hands work to
+type AgentConfig struct {
+	// Command is the agent command line, run in the piece's tmux session with a brief on stdin
+	Command string `json:"command,omitempty"`
+	// Limits caps the resources the agent command may use
+	Limits AgentLimits `json:"limits,omitzero"`
+}
+
+// AgentLimits is the resource budget of an agent command. Zero values mean no limit.
+type AgentLimits struct {
+	// CPUPercent caps CPU time as a percentage of one core (200 = two cores)
+	CPUPercent int `json:"cpu_percent,omitempty"`
+	// MemoryMB caps memory in megabytes
+	MemoryMB int `json:"memory_mb,omitempty"`
+	// Nice lowers the scheduling priority (1-19)
+	Nice int `json:"nice,omitempty"`
+}
+
+// GitConfig configures how monkeypuzzle talks to git remotes
+type GitConfig struct {
+	// Remote is the remote pushed to and checked for piece branches (default: origin)
+	Remote string `json:"remote,omitempty"`
+}
+
+// PiecesConfig configures limits on concurrent pieces
+type PiecesConfig struct {
+	// WIPLimit is the maximum number of active pieces (0 means no limit)
+	WIPLimit int `json:"wip_limit,omitempty"`
+	// MaxNameLength caps the length in bytes of piece names derived from
+	// issue titles and branches (0 means the default, 64)
+	MaxNameLength int `json:"max_name_length,omitempty"`
+	// AutoCheckpointMinutes is how often a piece's uncommitted work is
+	// committed as a wip: checkpoint while its session runs (0 means never)
+	AutoCheckpointMinutes int `json:"auto_checkpoint_minutes,omitempty"`
+	// IssuePrefix requires the commits of pieces with a linked issue to start
+	// with "[<issue ID>] ", checked by mp piece verify and before merging
+	IssuePrefix bool `json:"issue_prefix,omitempty"`
+}
+
+// MergeConfig configures how mp piece merge brings a piece into its base
+type MergeConfig struct {
+	// Strategy is "squash" (the default, one commit), "merge" (a merge
+	// commit) or "rebase-ff" (the piece rebased, then fast-forwarded to)
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// EventsConfig configures the events log
+type EventsConfig struct {
+	// HashChain links each entry to the previous one by hash so tampering can be
+	// detected with `mp events verify`
+	HashChain bool `json:"hash_chain,omitempty"`
+}
+
+// RedactConfig configures masking of secrets in output and logs
+type RedactConfig struct {
+	// Patterns are extra regular expressions to redact on top of known token
+	// formats; a group named "secret" masks only that part of a match
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// EnvConfig sets environment variables for the commands mp runs
+type EnvConfig struct {
+	// Vars are set for every command (git, gh, hooks); values may reference
+	// the environment as ${VAR}
+	Vars map[string]string `json:"vars,omitempty"`
+	// Providers sets variables only for one provider's commands, keyed by
+	// provider ("git", "github"), e.g. "github": {"GH_HOST": "github.example.com"}
+	Providers map[string]map[string]string `json:"providers,omitempty"`
+}
+
 // Handler executes the init command
 type Handler struct {
 	deps core.Deps
@@ -70,7 +171,7 @@ func (h *Handler) Run(input Input) error {
 		return err
 	}
 
-	issuesDir := "issues"
+	issuesDir := DefaultIssuesDir
 	if input.IssueProvider == "markdown" {
 		if err := h.deps.FS.MkdirAll(issuesDir, DefaultDirPerm); err != nil {
 			return err
@@ -123,6 +224,6 @@ func (h *Handler) Run(input Input) error {
 // ensureGitignore creates .monkeypuzzle/.gitignore with worktree-specific entries
 func (h *Handler) ensureGitignore() error {
 	gitignorePath := filepath.Join(DirName, ".gitignore")
-	content := "# Worktree-specific state (not tracked)\ncurrent-issue.json\n"
+	content := "# Worktree-specific state (not tracked)\ncurrent-issue.json\npr-metadata.json\nreview-brief.md\nevents.jsonl\nevents.jsonl.1\nissues.index.json\npending/\n"
 	return h.deps.FS.WriteFile(gitignorePath, []byte(content), DefaultFilePerm)
 }
@@ -256,3 +256,31 @@ func TestHandler_Run_CreatesNestedGitignore(t *testing.T) {
 		t.Errorf("expected .gitignore to contain current-issue.json, got: %s", content)
 	}
 }
+
+func TestIssueConfig_Directories(t *testing.T) {
+	var cfg initcmd.Config
+	data := `{"issues":{"provider":"markdown","config":{"directories":["issues/backend","issues/frontend/"],"directory":"issues"}}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	dirs := cfg.Issues.Dirs()
+	if len(dirs) != 2 || dirs[0] != "issues/backend" || dirs[1] != "issues/frontend" {
+		t.Errorf("expected directories to take precedence, got %v", dirs)
+	}
+	if cfg.Issues.Config["directory"] != "issues" {
+		t.Errorf("expected string settings to be kept, got %v", cfg.Issues.Config)
+	}
+
+	out, err := json.Marshal(cfg.Issues)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if !strings.Contains(string(out), `"directories":["issues/backend","issues/frontend/"]`) {
+		t.Errorf("expected directories to round-trip, got %s", out)
+	}
+
+	if dirs := (initcmd.IssueConfig{}).Dirs(); len(dirs) != 1 || dirs[0] != initcmd.DefaultIssuesDir {
+		t.Errorf("expected default directory, got %v", dirs)
+	}
+}
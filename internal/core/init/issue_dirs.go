@@ -0,0 +1,91 @@
+package init
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// DefaultIssuesDir is the markdown issues directory used when none is configured
+const DefaultIssuesDir = "issues"
+
+// directoriesKey is the issues config key listing several issue directories
+const directoriesKey = "directories"
+
+// Dirs returns the markdown issue directories: config.directories if set,
+// otherwise config.directory, otherwise DefaultIssuesDir. New issues go in
+// the first unless another is chosen.
+func (c IssueConfig) Dirs() []string {
+	if len(c.Directories) > 0 {
+		dirs := make([]string, len(c.Directories))
+		for i, dir := range c.Directories {
+			dirs[i] = filepath.Clean(dir)
+		}
+		return dirs
+	}
+	if dir := c.Config["directory"]; dir != "" {
+		return []string{filepath.Clean(dir)}
+	}
+	return []string{DefaultIssuesDir}
+}
+
+// Team names the team owning an issues directory, e.g. "backend" for
+// issues/backend. Teams only apply when several directories are configured.
+func Team(dir string) string {
+	return filepath.Base(dir)
+}
+
+// UnmarshalJSON reads config.directories as a list and every other config
+// value as a string
+func (c *IssueConfig) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Provider        string                     `json:"provider"`
+		Config          map[string]json.RawMessage `json:"config"`
+		RequireApproval bool                       `json:"require_approval"`
+		Fields          map[string]IssueField      `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = IssueConfig{Provider: raw.Provider, RequireApproval: raw.RequireApproval, Fields: raw.Fields}
+	if raw.Config == nil {
+		return nil
+	}
+	c.Config = make(map[string]string, len(raw.Config))
+	for key, value := range raw.Config {
+		if key == directoriesKey {
+			if err := json.Unmarshal(value, &c.Directories); err != nil {
+				return fmt.Errorf("issues.config.%s must be a list of paths: %w", key, err)
+			}
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return fmt.Errorf("issues.config.%s must be a string: %w", key, err)
+		}
+		c.Config[key] = s
+	}
+	return nil
+}
+
+// MarshalJSON writes Directories back into config.directories
+func (c IssueConfig) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Provider        string                `json:"provider"`
+		Config          map[string]any        `json:"config"`
+		RequireApproval bool                  `json:"require_approval,omitempty"`
+		Fields          map[string]IssueField `json:"fields,omitempty"`
+	}{Provider: c.Provider, RequireApproval: c.RequireApproval, Fields: c.Fields}
+
+	if c.Config != nil || len(c.Directories) > 0 {
+		out.Config = make(map[string]any, len(c.Config)+1)
+		for key, value := range c.Config {
+			out.Config[key] = value
+		}
+		if len(c.Directories) > 0 {
+			out.Config[directoriesKey] = c.Directories
+		}
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,134 @@
+package init
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of custom issue fields
+const (
+	FieldString  = "string"
+	FieldNumber  = "number"
+	FieldBoolean = "boolean"
+	FieldDate    = "date"
+	FieldList    = "list"
+)
+
+// fieldTypes are the valid IssueField types
+var fieldTypes = []string{FieldString, FieldNumber, FieldBoolean, FieldDate, FieldList}
+
+// builtinFields are validated by mp itself and can't be redefined
+var builtinFields = []string{"title", "status"}
+
+// fieldNameRegex matches the names frontmatter fields may have
+var fieldNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// IssueField defines a frontmatter field of the project's issues, from
+// issues.fields in the config
+type IssueField struct {
+	// Type is string (the default), number, boolean, date or list
+	Type string `json:"type,omitempty"`
+	// Required fields must be set in every issue
+	Required bool `json:"required,omitempty"`
+	// Values lists the allowed values, or the allowed items of a list
+	Values []string `json:"values,omitempty"`
+	// Default is given to new issues without a value, and added by
+	// mp issue lint --fix where a required field is missing
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// FieldNames returns the names of the custom issue fields, sorted
+func (c IssueConfig) FieldNames() []string {
+	names := make([]string, 0, len(c.Fields))
+	for name := range c.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateFields checks the custom issue field definitions
+func (c IssueConfig) ValidateFields() error {
+	var problems []string
+	for _, name := range c.FieldNames() {
+		field := c.Fields[name]
+		switch {
+		case !fieldNameRegex.MatchString(name):
+			problems = append(problems, fmt.Sprintf("issues.fields: invalid field name %q", name))
+		case slices.Contains(builtinFields, strings.ToLower(name)):
+			problems = append(problems, fmt.Sprintf("issues.fields.%s: %s is built in and can't be redefined", name, name))
+		case field.Type != "" && !slices.Contains(fieldTypes, field.Type):
+			problems = append(problems, fmt.Sprintf("issues.fields.%s: unknown type %q (valid: %s)", name, field.Type, strings.Join(fieldTypes, ", ")))
+		default:
+			for _, value := range field.Values {
+				if err := field.checkItem(value); err != nil {
+					problems = append(problems, fmt.Sprintf("issues.fields.%s: value %q is not a %s", name, value, field.Type))
+				}
+			}
+			if field.Default != "" {
+				if err := field.Check(field.Default); err != nil {
+					problems = append(problems, fmt.Sprintf("issues.fields.%s: default %v", name, err))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Check reports why value is not valid for the field. List values are
+// comma-separated items, optionally in brackets.
+func (f IssueField) Check(value string) error {
+	if f.Type != FieldList {
+		return f.checkItem(value)
+	}
+	for _, item := range SplitList(value) {
+		if err := f.checkItem(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkItem checks a scalar value, or an item of a list
+func (f IssueField) checkItem(value string) error {
+	switch f.Type {
+	case FieldNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case FieldBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+	case FieldDate:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return fmt.Errorf("%q is not a date (YYYY-MM-DD)", value)
+			}
+		}
+	}
+	if len(f.Values) > 0 && !slices.Contains(f.Values, value) {
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(f.Values, ", "))
+	}
+	return nil
+}
+
+// SplitList splits a list value, "a, b" or "[a, b]", into its items
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(strings.Trim(strings.TrimSpace(value), "[]"), ",") {
+		if item = strings.Trim(strings.TrimSpace(item), `"'`); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,73 @@
+package init_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+func TestIssueConfig_ValidateFields(t *testing.T) {
+	valid := initcmd.IssueConfig{Fields: map[string]initcmd.IssueField{
+		"priority":   {Values: []string{"low", "high"}, Required: true, Default: "low"},
+		"estimate":   {Type: initcmd.FieldNumber},
+		"components": {Type: initcmd.FieldList, Values: []string{"api", "db"}, Default: "api, db"},
+	}}
+	if err := valid.ValidateFields(); err != nil {
+		t.Errorf("expected valid fields, got %v", err)
+	}
+
+	invalid := initcmd.IssueConfig{Fields: map[string]initcmd.IssueField{
+		"status":   {},
+		"size":     {Type: "enum"},
+		"estimate": {Type: initcmd.FieldNumber, Default: "soon"},
+		"bad key":  {},
+	}}
+	err := invalid.ValidateFields()
+	if err == nil {
+		t.Fatal("expected invalid fields to be reported")
+	}
+	for _, want := range []string{"status is built in", `unknown type "enum"`, `"soon" is not a number`, `invalid field name "bad key"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestIssueField_Check(t *testing.T) {
+	tests := []struct {
+		field initcmd.IssueField
+		value string
+		ok    bool
+	}{
+		{initcmd.IssueField{}, "anything", true},
+		{initcmd.IssueField{Values: []string{"low", "high"}}, "medium", false},
+		{initcmd.IssueField{Type: initcmd.FieldBoolean}, "true", true},
+		{initcmd.IssueField{Type: initcmd.FieldBoolean}, "maybe", false},
+		{initcmd.IssueField{Type: initcmd.FieldDate}, "2026-10-16", true},
+		{initcmd.IssueField{Type: initcmd.FieldDate}, "next week", false},
+		{initcmd.IssueField{Type: initcmd.FieldList, Values: []string{"api", "db"}}, "[api, db]", true},
+		{initcmd.IssueField{Type: initcmd.FieldList, Values: []string{"api", "db"}}, "api, ui", false},
+	}
+	for _, tt := range tests {
+		if err := tt.field.Check(tt.value); (err == nil) != tt.ok {
+			t.Errorf("Check(%q) with %+v: got %v, want ok=%v", tt.value, tt.field, err, tt.ok)
+		}
+	}
+}
+
+func TestIssueConfig_FieldsRoundTrip(t *testing.T) {
+	data := []byte(`{"provider": "markdown", "config": {"directory": "issues"}, "fields": {"priority": {"values": ["low", "high"], "required": true}}}`)
+	var cfg initcmd.IssueConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Fields["priority"].Required {
+		t.Fatalf("expected fields parsed, got %+v", cfg)
+	}
+	out, _ := json.Marshal(cfg)
+	if !strings.Contains(string(out), `"fields":{"priority":{"required":true,"values":["low","high"]}}`) {
+		t.Errorf("expected fields written back, got %s", out)
+	}
+}
@@ -0,0 +1,151 @@
+package init
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Provider kinds
+const (
+	ProviderKindIssue = "issue"
+	ProviderKindPR    = "pr"
+)
+
+// writeProbeFile is created and removed to verify a directory is writable
+const writeProbeFile = ".mp-write-check"
+
+// ProviderCheck is the result of a provider self-check
+type ProviderCheck struct {
+	Kind     string `json:"kind"`
+	Provider string `json:"provider"`
+	OK       bool   `json:"ok"`
+	Message  string `json:"message,omitempty"`
+}
+
+// providerCheckFunc verifies a provider is usable, returning a descriptive error if not
+type providerCheckFunc func(deps core.Deps, repoRoot string, cfg Config) error
+
+// providerChecks maps provider kind and name to its self-check
+var providerChecks = map[string]map[string]providerCheckFunc{
+	ProviderKindIssue: {
+		"markdown": checkMarkdownProvider,
+	},
+	ProviderKindPR: {
+		"github": checkGitHubProvider,
+	},
+}
+
+// CheckProviders runs the self-check for each configured provider.
+// Providers without a registered check are unknown and reported as not OK.
+func CheckProviders(deps core.Deps, repoRoot string, cfg Config) []ProviderCheck {
+	return []ProviderCheck{
+		runProviderCheck(deps, repoRoot, ProviderKindIssue, cfg.Issues.Provider, cfg),
+		runProviderCheck(deps, repoRoot, ProviderKindPR, cfg.PR.Provider, cfg),
+	}
+}
+
+// CheckProviders runs provider self-checks and writes a warning for each failure
+func (h *Handler) CheckProviders(repoRoot string, cfg Config) []ProviderCheck {
+	checks := CheckProviders(h.deps, repoRoot, cfg)
+	for _, c := range checks {
+		if !c.OK {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("%s provider %q is not usable: %s", c.Kind, c.Provider, c.Message),
+				Data:    c,
+			})
+		}
+	}
+	return checks
+}
+
+func runProviderCheck(deps core.Deps, repoRoot, kind, provider string, cfg Config) ProviderCheck {
+	result := ProviderCheck{Kind: kind, Provider: provider}
+
+	if provider == "" {
+		result.Message = "no provider configured"
+		return result
+	}
+
+	check, ok := providerChecks[kind][provider]
+	if !ok {
+		result.Message = fmt.Sprintf("unknown %s provider (supported: %s)", kind, strings.Join(slices.Sorted(maps.Keys(providerChecks[kind])), ", "))
+		return result
+	}
+
+	if err := check(deps, repoRoot, cfg); err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	result.OK = true
+	return result
+}
+
+// checkMarkdownProvider verifies each issues directory exists and is writable
+func checkMarkdownProvider(deps core.Deps, repoRoot string, cfg Config) error {
+	for _, dir := range cfg.Issues.Dirs() {
+		if err := checkIssuesDir(deps, repoRoot, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkIssuesDir(deps core.Deps, repoRoot, dir string) error {
+	absDir := filepath.Join(repoRoot, dir)
+
+	info, err := deps.FS.Stat(absDir)
+	if err != nil {
+		return fmt.Errorf("issues directory %s does not exist", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("issues directory %s is not a directory", dir)
+	}
+
+	probe := filepath.Join(absDir, writeProbeFile)
+	if err := deps.FS.WriteFile(probe, nil, DefaultFilePerm); err != nil {
+		return fmt.Errorf("issues directory %s is not writable: %w", dir, err)
+	}
+	if err := deps.FS.Remove(probe); err != nil {
+		return fmt.Errorf("failed to remove write probe in %s: %w", dir, err)
+	}
+
+	return nil
+}
+
+// checkGitHubProvider verifies the gh CLI is installed and authenticated
+func checkGitHubProvider(deps core.Deps, repoRoot string, cfg Config) error {
+	if deps.Exec == nil {
+		return fmt.Errorf("no command runner available to check gh")
+	}
+	args := []string{"auth", "status"}
+	// A GitHub Enterprise host needs its own login
+	if host := cfg.PR.Config["host"]; host != "" {
+		args = append(args, "--hostname", host)
+	}
+	output, err := deps.Exec.RunWithDir(repoRoot, "gh", args...)
+	if err != nil {
+		detail := firstLine(string(output))
+		if detail == "" {
+			detail = err.Error()
+		}
+		return fmt.Errorf("gh auth status failed (run 'gh auth login'): %s", detail)
+	}
+	return nil
+}
+
+// firstLine returns the first non-empty trimmed line of s
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
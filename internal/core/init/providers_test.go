@@ -0,0 +1,103 @@
+package init_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+func testConfig() initcmd.Config {
+	return initcmd.Config{
+		Version: "1",
+		Issues:  initcmd.IssueConfig{Provider: "markdown", Config: map[string]string{"directory": "issues"}},
+		PR:      initcmd.PRConfig{Provider: "github", Config: map[string]string{}},
+	}
+}
+
+func TestCheckProviders_AllUsable(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	deps := core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}
+
+	_ = fs.MkdirAll("repo/issues", 0755)
+	mockExec.AddResponse("gh", []string{"auth", "status"}, []byte("Logged in to github.com\n"), nil)
+
+	checks := initcmd.CheckProviders(deps, "/repo", testConfig())
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 checks, got %d", len(checks))
+	}
+	for _, c := range checks {
+		if !c.OK {
+			t.Errorf("expected %s provider %s to be OK, got: %s", c.Kind, c.Provider, c.Message)
+		}
+	}
+
+	// Write probe must be cleaned up
+	if _, err := fs.ReadFile("repo/issues/.mp-write-check"); err == nil {
+		t.Error("expected write probe to be removed")
+	}
+}
+
+func TestCheckProviders_Failures(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	deps := core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}
+
+	// No issues directory, gh not logged in
+	mockExec.AddResponse("gh", []string{"auth", "status"},
+		[]byte("You are not logged into any GitHub hosts.\n"), adapters.MockError("exit status 1"))
+
+	checks := initcmd.CheckProviders(deps, "/repo", testConfig())
+
+	tests := []struct {
+		kind string
+		want string
+	}{
+		{initcmd.ProviderKindIssue, "issues directory issues does not exist"},
+		{initcmd.ProviderKindPR, "gh auth status failed (run 'gh auth login'): You are not logged into any GitHub hosts."},
+	}
+	for i, tt := range tests {
+		if checks[i].Kind != tt.kind {
+			t.Errorf("check %d: expected kind %s, got %s", i, tt.kind, checks[i].Kind)
+		}
+		if checks[i].OK {
+			t.Errorf("check %d: expected failure", i)
+		}
+		if checks[i].Message != tt.want {
+			t.Errorf("check %d: expected message %q, got %q", i, tt.want, checks[i].Message)
+		}
+	}
+}
+
+func TestCheckProviders_UnknownProviderFails(t *testing.T) {
+	deps := core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}
+	cfg := testConfig()
+	cfg.Issues.Provider = "githbu"
+	cfg.PR.Provider = ""
+
+	checks := initcmd.CheckProviders(deps, "/repo", cfg)
+	if checks[0].OK || checks[0].Message != "unknown issue provider (supported: markdown)" {
+		t.Errorf("expected unknown provider to fail, got %+v", checks[0])
+	}
+	if checks[1].OK {
+		t.Errorf("expected missing provider to fail, got %+v", checks[1])
+	}
+}
+
+func TestHandler_CheckProviders_WritesWarnings(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := initcmd.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/issues", 0755)
+	mockExec.AddResponse("gh", []string{"auth", "status"}, nil, adapters.MockError("executable file not found"))
+
+	handler.CheckProviders("/repo", testConfig())
+
+	if !out.HasWarning() {
+		t.Error("expected warning for unusable github provider")
+	}
+}
@@ -0,0 +1,91 @@
+package init
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// UserConfigFile is the user-level config file name in $XDG_CONFIG_HOME/monkeypuzzle
+const UserConfigFile = "config.json"
+
+// EnvSourceDir overrides the user config's tool.source_dir
+const EnvSourceDir = "MP_SOURCE_DIR"
+
+// UserConfig is the user-level config file
+type UserConfig struct {
+	Aliases map[string]string `json:"aliases,omitempty"`
+	Tool    ToolConfig        `json:"tool"`
+	// Env sets variables for the commands mp runs, overridden by the repository's
+	Env EnvConfig `json:"env,omitzero"`
+}
+
+// ToolConfig configures the mp installation itself
+type ToolConfig struct {
+	// SourceDir is the monkeypuzzle source checkout, recorded on new pieces
+	SourceDir string `json:"source_dir,omitempty"`
+}
+
+// UserConfigPath returns $XDG_CONFIG_HOME/monkeypuzzle/config.json (default ~/.config)
+func UserConfigPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "monkeypuzzle", UserConfigFile), nil
+}
+
+// ReadUserConfig reads the user config. Without a home directory or config
+// file it returns an empty config.
+func ReadUserConfig(fs core.FS) (UserConfig, error) {
+	var cfg UserConfig
+	path, err := UserConfigPath()
+	if err != nil {
+		return cfg, nil
+	}
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return UserConfig{}, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return UserConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// SourceDir returns the monkeypuzzle source directory: $MP_SOURCE_DIR, else
+// tool.source_dir from the user config, else "" (none configured).
+func SourceDir(fs core.FS) (string, error) {
+	if dir := os.Getenv(EnvSourceDir); dir != "" {
+		return dir, nil
+	}
+	cfg, err := ReadUserConfig(fs)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Tool.SourceDir, nil
+}
+
+// FindRepoConfig walks up from dir to the nearest directory with a monkeypuzzle config
+func FindRepoConfig(fs core.FS, dir string) (string, bool) {
+	for {
+		if _, err := fs.Stat(filepath.Join(dir, DirName, ConfigFile)); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,30 @@
+package init_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+func TestSourceDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/test-config")
+	t.Setenv(initcmd.EnvSourceDir, "")
+	fs := adapters.NewMemoryFS()
+
+	if dir, err := initcmd.SourceDir(fs); err != nil || dir != "" {
+		t.Errorf("expected no source dir without config, got %q, %v", dir, err)
+	}
+
+	_ = fs.MkdirAll("test-config/monkeypuzzle", 0755)
+	_ = fs.WriteFile("test-config/monkeypuzzle/config.json",
+		[]byte(`{"tool":{"source_dir":"/src/monkeypuzzle"}}`), 0644)
+	if dir, err := initcmd.SourceDir(fs); err != nil || dir != "/src/monkeypuzzle" {
+		t.Errorf("expected source dir from user config, got %q, %v", dir, err)
+	}
+
+	t.Setenv(initcmd.EnvSourceDir, "/override")
+	if dir, err := initcmd.SourceDir(fs); err != nil || dir != "/override" {
+		t.Errorf("expected env var to override user config, got %q, %v", dir, err)
+	}
+}
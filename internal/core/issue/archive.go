@@ -0,0 +1,66 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// ArchiveDir is the subdirectory of the issues directory done issues are moved to
+const ArchiveDir = "archive"
+
+// ArchiveDone moves issues with status done into the archive subdirectory of
+// their issues directory, rewriting their relative links to still resolve.
+// Returns the archived issues' new paths.
+func (h *Handler) ArchiveDone() ([]string, error) {
+	done, err := h.List(piece.StatusDone)
+	if err != nil {
+		return nil, err
+	}
+	if len(done) == 0 {
+		return nil, nil
+	}
+
+	var archived []string
+	for _, is := range done {
+		issuesDir := filepath.Dir(is.Path)
+		archiveDir := filepath.Join(h.workDir, issuesDir, ArchiveDir)
+		if err := h.deps.FS.MkdirAll(archiveDir, initcmd.DefaultDirPerm); err != nil {
+			return archived, fmt.Errorf("failed to create archive directory: %w", err)
+		}
+
+		src := filepath.Join(h.workDir, is.Path)
+		content, err := h.deps.FS.ReadFile(src)
+		if err != nil {
+			return archived, fmt.Errorf("failed to read %s: %w", is.Path, err)
+		}
+
+		filename, err := h.resolveUniqueFilename(archiveDir, strings.TrimSuffix(filepath.Base(is.Path), ".md"))
+		if err != nil {
+			return archived, err
+		}
+		dst := filepath.Join(issuesDir, ArchiveDir, filename)
+		if content, err = h.moveAssets(is.Path, dst, content); err != nil {
+			return archived, err
+		}
+		content = []byte(rebaseLinks(is.Path, dst, string(content)))
+		if err := h.deps.FS.WriteFile(filepath.Join(archiveDir, filename), content, defaultFilePerm); err != nil {
+			return archived, fmt.Errorf("failed to archive %s: %w", is.Path, err)
+		}
+		if err := h.deps.FS.Remove(src); err != nil {
+			return archived, fmt.Errorf("failed to remove %s: %w", is.Path, err)
+		}
+
+		archived = append(archived, dst)
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgInfo,
+			Content: fmt.Sprintf("Archived %s to %s", is.Path, dst),
+		})
+	}
+
+	return archived, nil
+}
@@ -0,0 +1,45 @@
+package issue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+func TestHandler_ArchiveDone_RewritesLinks(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues/shipped.assets", 0755)
+	_ = fs.WriteFile("issues/shipped.assets/screen.png", []byte("png"), 0644)
+	_ = fs.WriteFile("issues/open.md", []byte("---\ntitle: Open\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("issues/shipped.md", []byte("---\ntitle: Shipped\nstatus: done\n---\n"+
+		"Follows [open](open.md#plan) and [docs](../docs/design.md).\n"+
+		"![screen](shipped.assets/screen.png) [site](https://example.com) [root](/README.md) [top](#top)\n\n"+
+		"[spec]: ./open.md\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}, "")
+
+	archived, err := handler.ArchiveDone()
+	if err != nil {
+		t.Fatalf("ArchiveDone failed: %v", err)
+	}
+	if len(archived) != 1 || archived[0] != "issues/archive/shipped.md" {
+		t.Fatalf("expected the done issue archived, got %v", archived)
+	}
+	content, _ := fs.ReadFile("issues/archive/shipped.md")
+	for _, want := range []string{
+		"[open](../open.md#plan)",
+		"[docs](../../docs/design.md)",
+		"![screen](shipped.assets/screen.png)",
+		"[site](https://example.com)",
+		"[root](/README.md)",
+		"[top](#top)",
+		"[spec]: ../open.md",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in the archived issue, got:\n%s", want, content)
+		}
+	}
+}
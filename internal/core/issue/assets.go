@@ -0,0 +1,254 @@
+package issue
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// AssetsSuffix names the directory of an issue's attachments, next to the
+// issue: the assets of issues/add-login.md are in issues/add-login.assets/
+const AssetsSuffix = ".assets"
+
+// AssetsDir returns the assets directory of the issue at path
+func AssetsDir(path string) string {
+	return strings.TrimSuffix(path, ".md") + AssetsSuffix
+}
+
+// Attachment is a file copied into an issue's assets directory
+type Attachment struct {
+	// Source is the file as given
+	Source string `json:"source"`
+	// Path is the copy, relative to the working directory
+	Path string `json:"path"`
+	// Link is the link target in the issue, relative to the issue
+	Link string `json:"link"`
+	// Rewritten counts the links to Source now pointing at the copy
+	Rewritten int `json:"rewritten"`
+	// Appended is set when the issue had no link to rewrite, so one was added
+	Appended bool `json:"appended,omitempty"`
+}
+
+// AttachResult describes files attached with Attach
+type AttachResult struct {
+	Issue       IssueRef     `json:"issue"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+// markdownLinkRegex matches a markdown link or image, capturing the text up
+// to the target, the target, and the rest
+var markdownLinkRegex = regexp.MustCompile(`(!?\[[^\]]*\]\()([^)\s]+)((?:\s+"[^"]*")?\))`)
+
+// imageExtensions are attached as images rather than links
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true,
+}
+
+// Attach copies files into the assets directory of an issue and points the
+// issue's relative links to them at the copies. A link points to a file when
+// it resolves to it from the issue, or when it has the file's name and
+// resolves to nothing, e.g. a screenshot referenced before it was added. An
+// issue without such a link, or one to the copy, gets one appended.
+func (h *Handler) Attach(id string, files []string) (AttachResult, error) {
+	absPath, relPath, err := h.resolveIssue(id)
+	if err != nil {
+		return AttachResult{}, err
+	}
+	content, err := h.deps.FS.ReadFile(absPath)
+	if err != nil {
+		return AttachResult{}, fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	absAssets := AssetsDir(absPath)
+	if err := h.deps.FS.MkdirAll(absAssets, initcmd.DefaultDirPerm); err != nil {
+		return AttachResult{}, fmt.Errorf("failed to create %s: %w", AssetsDir(relPath), err)
+	}
+
+	result := AttachResult{Issue: IssueRef{ID: piece.IssueID(relPath), Path: relPath}, Attachments: []Attachment{}}
+	text := string(content)
+	for _, file := range files {
+		source := file
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(h.workDir, source)
+		}
+		data, err := h.deps.FS.ReadFile(source)
+		if err != nil {
+			return result, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		name, err := h.assetName(absAssets, filepath.Base(source), data)
+		if err != nil {
+			return result, err
+		}
+		if err := h.deps.FS.WriteFile(filepath.Join(absAssets, name), data, defaultFilePerm); err != nil {
+			return result, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+
+		attachment := Attachment{
+			Source: file,
+			Path:   filepath.Join(AssetsDir(relPath), name),
+			Link:   filepath.ToSlash(filepath.Join(filepath.Base(AssetsDir(relPath)), name)),
+		}
+		text, attachment.Rewritten = h.rewriteLinks(text, filepath.Dir(absPath), source, attachment.Link)
+		if attachment.Rewritten == 0 && !strings.Contains(text, "]("+attachment.Link) {
+			text = appendLink(text, name, attachment.Link)
+			attachment.Appended = true
+		}
+		result.Attachments = append(result.Attachments, attachment)
+	}
+
+	if err := h.deps.FS.WriteFile(absPath, []byte(text), defaultFilePerm); err != nil {
+		return result, fmt.Errorf("failed to write %s: %w", relPath, err)
+	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Attached %d file(s) to %s", len(result.Attachments), relPath),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// assetName returns the name to copy a file with content data to in dir:
+// name itself unless another file has it, else name with a number added.
+// Attaching the same file again reuses its copy.
+func (h *Handler) assetName(dir, name string, data []byte) (string, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; i < 1000; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+		}
+		existing, err := h.deps.FS.ReadFile(filepath.Join(dir, candidate))
+		if err != nil || bytes.Equal(existing, data) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("too many assets named %s", name)
+}
+
+// rewriteLinks points the relative links in text to source at link,
+// returning the new text and the number of links rewritten. issueDir is the
+// directory relative links resolve from.
+func (h *Handler) rewriteLinks(text, issueDir, source, link string) (string, int) {
+	count := 0
+	text = markdownLinkRegex.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownLinkRegex.FindStringSubmatch(match)
+		target := m[2]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || target == link {
+			return match
+		}
+		resolved := filepath.Join(issueDir, filepath.FromSlash(target))
+		if resolved != source {
+			if filepath.Base(resolved) != filepath.Base(source) {
+				return match
+			}
+			if _, err := h.deps.FS.Stat(resolved); err == nil {
+				return match
+			}
+		}
+		count++
+		return m[1] + link + m[3]
+	})
+	return text, count
+}
+
+// appendLink adds a link to an attachment at the end of text, as an image
+// when it is one
+func appendLink(text, name, link string) string {
+	prefix := ""
+	if imageExtensions[strings.ToLower(filepath.Ext(name))] {
+		prefix = "!"
+	}
+	return strings.TrimRight(text, "\n") + fmt.Sprintf("\n\n%s[%s](%s)\n", prefix, name, link)
+}
+
+// Assets lists the files in the assets directory of the issue at relPath,
+// relative to the working directory, sorted
+func (h *Handler) Assets(relPath string) []string {
+	var assets []string
+	var walk func(dir string)
+	walk = func(dir string) {
+		entries, err := h.deps.FS.ReadDir(filepath.Join(h.workDir, dir))
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() {
+				walk(path)
+			} else {
+				assets = append(assets, path)
+			}
+		}
+	}
+	walk(AssetsDir(relPath))
+	sort.Strings(assets)
+	return assets
+}
+
+// moveAssets moves the assets directory of the issue at oldPath to that of
+// newPath, both relative to the working directory, and returns content with
+// the links into it updated
+func (h *Handler) moveAssets(oldPath, newPath string, content []byte) ([]byte, error) {
+	assets := h.Assets(oldPath)
+	if len(assets) == 0 {
+		return content, nil
+	}
+	oldDir, newDir := AssetsDir(oldPath), AssetsDir(newPath)
+	for _, asset := range assets {
+		rel, _ := filepath.Rel(oldDir, asset)
+		dst := filepath.Join(h.workDir, newDir, rel)
+		data, err := h.deps.FS.ReadFile(filepath.Join(h.workDir, asset))
+		if err != nil {
+			return content, fmt.Errorf("failed to read %s: %w", asset, err)
+		}
+		if err := h.deps.FS.MkdirAll(filepath.Dir(dst), initcmd.DefaultDirPerm); err != nil {
+			return content, fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+		}
+		if err := h.deps.FS.WriteFile(dst, data, defaultFilePerm); err != nil {
+			return content, fmt.Errorf("failed to write %s: %w", dst, err)
+		}
+		if err := h.deps.FS.Remove(filepath.Join(h.workDir, asset)); err != nil {
+			return content, fmt.Errorf("failed to remove %s: %w", asset, err)
+		}
+	}
+	h.removeEmptyDirs(oldDir)
+
+	// The assets directory stays next to the issue, so only its name changes
+	// in the links into it
+	oldLink := filepath.Base(oldDir) + "/"
+	newLink := filepath.Base(newDir) + "/"
+	text := markdownLinkRegex.ReplaceAllStringFunc(string(content), func(match string) string {
+		m := markdownLinkRegex.FindStringSubmatch(match)
+		rest, ok := strings.CutPrefix(strings.TrimPrefix(m[2], "./"), oldLink)
+		if !ok {
+			return match
+		}
+		return m[1] + newLink + rest + m[3]
+	})
+	return []byte(text), nil
+}
+
+// removeEmptyDirs removes dir, relative to the working directory, and the
+// directories in it, when they hold no files
+func (h *Handler) removeEmptyDirs(dir string) {
+	abs := filepath.Join(h.workDir, dir)
+	entries, err := h.deps.FS.ReadDir(abs)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			h.removeEmptyDirs(filepath.Join(dir, entry.Name()))
+		}
+	}
+	if entries, err := h.deps.FS.ReadDir(abs); err == nil && len(entries) == 0 {
+		_ = h.deps.FS.Remove(abs)
+	}
+}
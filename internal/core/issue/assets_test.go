@@ -0,0 +1,112 @@
+package issue_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+func setupAssets(t *testing.T, body string) (*adapters.MemoryFS, *issue.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/data")
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/add-login.md", []byte("---\ntitle: Add login\nstatus: todo\n---\n"+body), 0644)
+	_ = fs.MkdirAll("/home/me", 0755)
+	_ = fs.WriteFile("/home/me/form.png", []byte("png"), 0644)
+	_ = fs.WriteFile("/home/me/spec.pdf", []byte("pdf"), 0644)
+	return fs, issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}, "")
+}
+
+func TestHandler_Attach(t *testing.T) {
+	fs, handler := setupAssets(t, "The form:\n\n![form](form.png \"Login\")\n\nSee [the docs](https://example.com/form.png).\n")
+
+	result, err := handler.Attach("add-login", []string{"/home/me/form.png", "/home/me/spec.pdf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Attachments) != 2 {
+		t.Fatalf("expected two attachments, got %+v", result)
+	}
+	form, spec := result.Attachments[0], result.Attachments[1]
+	if form.Path != "issues/add-login.assets/form.png" || form.Rewritten != 1 || form.Appended {
+		t.Errorf("expected the dangling link to be rewritten, got %+v", form)
+	}
+	if !spec.Appended {
+		t.Errorf("expected a link to be appended for the spec, got %+v", spec)
+	}
+	if data, err := fs.ReadFile("issues/add-login.assets/spec.pdf"); err != nil || string(data) != "pdf" {
+		t.Errorf("expected the spec to be copied, got %q (%v)", data, err)
+	}
+
+	content, _ := fs.ReadFile("issues/add-login.md")
+	for _, want := range []string{`![form](add-login.assets/form.png "Login")`, "(https://example.com/form.png)", "[spec.pdf](add-login.assets/spec.pdf)\n"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in the issue, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestHandler_Attach_NameTaken(t *testing.T) {
+	fs, handler := setupAssets(t, "")
+	if _, err := handler.Attach("add-login", []string{"/home/me/form.png"}); err != nil {
+		t.Fatal(err)
+	}
+	// The same file again reuses its copy; another with its name gets a new one
+	again, _ := handler.Attach("add-login", []string{"/home/me/form.png"})
+	_ = fs.MkdirAll("/other", 0755)
+	_ = fs.WriteFile("/other/form.png", []byte("other"), 0644)
+	other, err := handler.Attach("add-login", []string{"/other/form.png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Attachments[0].Path != "issues/add-login.assets/form.png" || other.Attachments[0].Path != "issues/add-login.assets/form-2.png" {
+		t.Errorf("unexpected asset paths %+v and %+v", again.Attachments, other.Attachments)
+	}
+	// A link already pointing at the copy is not appended again
+	content, _ := fs.ReadFile("issues/add-login.md")
+	if again.Attachments[0].Appended || strings.Count(string(content), "(add-login.assets/form.png)") != 1 {
+		t.Errorf("expected one link to the copy, got %+v in:\n%s", again.Attachments[0], content)
+	}
+}
+
+func TestHandler_Rename_MovesAssets(t *testing.T) {
+	fs, handler := setupAssets(t, "")
+	if _, err := handler.Attach("add-login", []string{"/home/me/form.png"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handler.Rename("add-login", "add-sso-login"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.ReadFile("issues/add-sso-login.assets/form.png"); err != nil {
+		t.Errorf("expected the assets to move with the issue: %v", err)
+	}
+	if _, err := fs.Stat("issues/add-login.assets"); err == nil {
+		t.Error("expected the old assets directory to be removed")
+	}
+	content, _ := fs.ReadFile("issues/add-sso-login.md")
+	if !strings.Contains(string(content), "(add-sso-login.assets/form.png)") {
+		t.Errorf("expected the link to follow the assets, got:\n%s", content)
+	}
+}
+
+func TestHandler_Export_Assets(t *testing.T) {
+	_, handler := setupAssets(t, "")
+	if _, err := handler.Attach("add-login", []string{"/home/me/spec.pdf", "/home/me/form.png"}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := handler.Export(issue.ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"issues/add-login.assets/form.png", "issues/add-login.assets/spec.pdf"}
+	if len(records) != 1 || !slices.Equal(records[0].Assets, want) {
+		t.Errorf("expected the assets in the export, got %+v", records)
+	}
+}
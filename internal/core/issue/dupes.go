@@ -0,0 +1,263 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// DefaultDupeThreshold is the score from which two issues are reported as
+// likely duplicates
+const DefaultDupeThreshold = 0.6
+
+// titleStopwords are left out when comparing titles
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "to": true, "of": true,
+	"in": true, "on": true, "for": true, "with": true, "is": true, "be": true,
+}
+
+// DupeCandidate is a pair of issues that are likely duplicates
+type DupeCandidate struct {
+	A IssueRef `json:"a"`
+	B IssueRef `json:"b"`
+	// Score combines title similarity and shared labels, from 0 to 1
+	Score        float64  `json:"score"`
+	SharedLabels []string `json:"shared_labels,omitempty"`
+}
+
+// DupesOptions configures Dupes
+type DupesOptions struct {
+	// Threshold is the lowest score reported (default DefaultDupeThreshold)
+	Threshold float64
+	// IncludeDone also compares issues with status done
+	IncludeDone bool
+}
+
+// Dupes returns pairs of issues whose titles are similar, most similar
+// first. The score is the overlap of the titles' words (Dice coefficient),
+// raised by a fifth of the way to 1 when the issues share labels.
+func (h *Handler) Dupes(opts DupesOptions) ([]DupeCandidate, error) {
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDupeThreshold
+	}
+
+	issues, err := h.List("")
+	if err != nil {
+		return nil, err
+	}
+	if !opts.IncludeDone {
+		issues = slices.DeleteFunc(issues, func(is IssueSummary) bool { return is.Status == piece.StatusDone })
+	}
+
+	words := make([]map[string]bool, len(issues))
+	for i, is := range issues {
+		words[i] = titleWords(is.Title)
+	}
+
+	candidates := []DupeCandidate{}
+	for i := range issues {
+		for j := i + 1; j < len(issues); j++ {
+			score := dice(words[i], words[j])
+			shared := sharedLabels(issues[i].Labels, issues[j].Labels)
+			if len(shared) > 0 {
+				score += (1 - score) / 5
+			}
+			if score < threshold {
+				continue
+			}
+			candidates = append(candidates, DupeCandidate{
+				A:            IssueRef{ID: issues[i].ID, Path: issues[i].Path},
+				B:            IssueRef{ID: issues[j].ID, Path: issues[j].Path},
+				Score:        float64(int(score*100+0.5)) / 100,
+				SharedLabels: shared,
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates, nil
+}
+
+// titleWords returns the lowercased words of a title, without stopwords
+func titleWords(title string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !titleStopwords[word] {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// dice returns the Dice coefficient of two word sets
+func dice(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range a {
+		if b[word] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// sharedLabels returns the labels in both a and b, sorted
+func sharedLabels(a, b []string) []string {
+	var shared []string
+	for _, label := range a {
+		if slices.Contains(b, label) && !slices.Contains(shared, label) {
+			shared = append(shared, label)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// MergeResult describes a duplicate issue merged into another with Merge
+type MergeResult struct {
+	Kept    IssueRef `json:"kept"`
+	Removed IssueRef `json:"removed"`
+	// CreatedAt is the kept issue's created_at: the earlier of the two issues
+	CreatedAt string `json:"created_at,omitempty"`
+	// Pieces and Issues list what referred to the removed issue and now
+	// refers to the kept one
+	Pieces []string `json:"pieces,omitempty"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// Merge combines the duplicate issue dupID into keepID: the duplicate's body
+// is appended under a "Merged from" heading, the kept issue gets the earlier
+// created_at of the two, references to the duplicate are redirected as by
+// Rename, and the duplicate's file is removed. Its old ID resolves to the
+// kept issue. As with Rename, a staged merge updates the alias and piece
+// references once approved.
+func (h *Handler) Merge(keepID, dupID string) (MergeResult, error) {
+	keepAbs, keepRel, err := h.resolveIssue(keepID)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	dupAbs, dupRel, err := h.resolveIssue(dupID)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	if keepRel == dupRel {
+		return MergeResult{}, fmt.Errorf("cannot merge %s into itself", keepRel)
+	}
+
+	keepContent, err := h.deps.FS.ReadFile(keepAbs)
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("failed to read %s: %w", keepRel, err)
+	}
+	dupContent, err := h.deps.FS.ReadFile(dupAbs)
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("failed to read %s: %w", dupRel, err)
+	}
+
+	// Work out created_at before the duplicate leaves git's view
+	createdAt := h.earliestCreatedAt(keepRel, string(keepContent), dupRel, string(dupContent))
+
+	staging, staged := h.staging(dupAbs)
+	if !staged {
+		if err := h.recordAlias(dupRel, keepRel); err != nil {
+			return MergeResult{}, err
+		}
+	}
+	merged := strings.TrimRight(string(keepContent), "\n") + "\n\n" +
+		fmt.Sprintf("## Merged from %s\n\n", piece.IssueID(dupRel)) +
+		strings.TrimSpace(issueBody(string(dupContent))) + "\n"
+	if err := h.deps.FS.WriteFile(keepAbs, []byte(merged), defaultFilePerm); err != nil {
+		return MergeResult{}, fmt.Errorf("failed to write %s: %w", keepRel, err)
+	}
+	if createdAt != "" {
+		if err := piece.SetIssueField(keepAbs, piece.IssueFieldCreatedAt, createdAt, h.deps.FS); err != nil {
+			return MergeResult{}, err
+		}
+	}
+	if err := h.deps.FS.Remove(dupAbs); err != nil {
+		return MergeResult{}, fmt.Errorf("failed to remove %s: %w", dupRel, err)
+	}
+	if staged {
+		if err := staging.markMoved(dupAbs, keepRel); err != nil {
+			return MergeResult{}, fmt.Errorf("failed to stage the merge of %s: %w", dupRel, err)
+		}
+	}
+
+	result := MergeResult{
+		Kept:      IssueRef{ID: piece.IssueID(keepRel), Path: keepRel},
+		Removed:   IssueRef{ID: piece.IssueID(dupRel), Path: dupRel},
+		CreatedAt: createdAt,
+	}
+	result.Issues, err = h.rewriteReferences(dupRel, keepRel)
+	if err != nil {
+		return result, err
+	}
+	if !staged {
+		result.Pieces = h.repointPieces(dupRel, keepRel)
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Merged %s into %s", dupRel, keepRel),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// earliestCreatedAt returns the earlier creation time of two issues, from
+// their created_at fields or else when they were first committed. It is
+// empty when neither time is known.
+func (h *Handler) earliestCreatedAt(pathA, contentA, pathB, contentB string) string {
+	var history map[string]issueTimes
+	created := func(path, content string) (time.Time, bool) {
+		if t, ok := parseCreatedAt(piece.ExtractField(content, piece.IssueFieldCreatedAt)); ok {
+			return t, true
+		}
+		if history == nil {
+			dirs := []string{filepath.Dir(pathA)}
+			if dir := filepath.Dir(pathB); dir != dirs[0] {
+				dirs = append(dirs, dir)
+			}
+			history = h.statusHistory(dirs)
+		}
+		t := history[path].created
+		return t, !t.IsZero()
+	}
+
+	a, okA := created(pathA, contentA)
+	b, okB := created(pathB, contentB)
+	switch {
+	case okA && (!okB || !b.Before(a)):
+		return a.Format(time.RFC3339)
+	case okB:
+		return b.Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// parseCreatedAt parses a created_at value, an RFC 3339 time or a date
+func parseCreatedAt(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// issueBody returns an issue's content after its frontmatter
+func issueBody(content string) string {
+	lines := strings.Split(content, "\n")
+	return strings.Join(lines[bodyStart(lines):], "\n")
+}
@@ -0,0 +1,106 @@
+package issue_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+func TestHandler_Dupes(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/add-login.md", []byte("---\ntitle: Add login page\nstatus: todo\nlabels: [auth]\n---\n"), 0644)
+	_ = fs.WriteFile("issues/login-page.md", []byte("---\ntitle: Add a login page\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("issues/sso.md", []byte("---\ntitle: Login with SSO\nstatus: todo\nlabels: [auth]\n---\n"), 0644)
+	_ = fs.WriteFile("issues/old-login.md", []byte("---\ntitle: Add login page\nstatus: done\n---\n"), 0644)
+	_ = fs.WriteFile("issues/docs.md", []byte("---\ntitle: Write the docs\nstatus: todo\n---\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	candidates, err := handler.Dupes(issue.DupesOptions{})
+	if err != nil {
+		t.Fatalf("Dupes failed: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].A.ID != "add-login" || candidates[0].B.ID != "login-page" || candidates[0].Score != 1 {
+		t.Fatalf("expected add-login and login-page, got %+v", candidates)
+	}
+
+	// Shared labels raise a weaker title match: 2*1/(3+2) = 0.4 -> 0.52
+	candidates, _ = handler.Dupes(issue.DupesOptions{Threshold: 0.4, IncludeDone: true})
+	var sso *issue.DupeCandidate
+	for i, c := range candidates {
+		if c.A.ID == "add-login" && c.B.ID == "sso" {
+			sso = &candidates[i]
+		}
+	}
+	if sso == nil || sso.Score != 0.52 || len(sso.SharedLabels) != 1 {
+		t.Errorf("expected add-login and sso to match on the shared label, got %+v", candidates)
+	}
+	if len(candidates) < 3 || candidates[0].Score < candidates[len(candidates)-1].Score {
+		t.Errorf("expected done issues included, most similar first, got %+v", candidates)
+	}
+}
+
+func TestHandler_Merge(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/add-login.md", []byte("---\ntitle: Add login page\nstatus: todo\n---\n\n# Add login page\n\nUse the form.\n"), 0644)
+	_ = fs.WriteFile("issues/login-page.md", []byte("---\ntitle: Add a login page\nstatus: todo\ncreated_at: 2024-01-02\n---\n\n# Add a login page\n\nRemember the password reset.\n"), 0644)
+	_ = fs.WriteFile("issues/child.md", []byte("---\ntitle: Child\nstatus: todo\nparent: login-page\n---\n"), 0644)
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\n"), nil)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+
+	result, err := handler.Merge("add-login", "login-page")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if result.CreatedAt != "2024-01-02T00:00:00Z" || len(result.Issues) != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	kept, _ := fs.ReadFile("issues/add-login.md")
+	for _, want := range []string{"created_at: 2024-01-02T00:00:00Z", "Use the form.", "## Merged from login-page\n\n# Add a login page\n\nRemember the password reset.\n"} {
+		if !strings.Contains(string(kept), want) {
+			t.Errorf("expected %q in the kept issue, got:\n%s", want, kept)
+		}
+	}
+	if _, err := fs.Stat("issues/login-page.md"); err == nil {
+		t.Error("expected the duplicate to be removed")
+	}
+	child, _ := fs.ReadFile("issues/child.md")
+	if !strings.Contains(string(child), "parent: add-login\n") {
+		t.Errorf("expected the reference to be redirected, got:\n%s", child)
+	}
+	if ref, err := handler.Resolve("login-page"); err != nil || ref.ID != "add-login" {
+		t.Errorf("expected the duplicate's ID to resolve to the kept issue, got %+v (%v)", ref, err)
+	}
+}
+
+func TestHandler_Merge_NoCreationTime(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("issues/b.md", []byte("---\ntitle: B\nstatus: todo\n---\n"), 0644)
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"log", "--reverse", "--no-renames", "--no-color", "--unified=0", "--format=%x1e%aI", "-G^status:", "-p", "--", "issues"}, nil, errors.New("not a git repository"))
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+
+	result, err := handler.Merge("a", "b")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	kept, _ := fs.ReadFile("issues/a.md")
+	if result.CreatedAt != "" || strings.Contains(string(kept), "created_at") {
+		t.Errorf("expected no created_at without creation times, got %+v:\n%s", result, kept)
+	}
+	if _, err := handler.Merge("a", "a"); err == nil {
+		t.Error("expected merging an issue into itself to fail")
+	}
+}
@@ -0,0 +1,327 @@
+package issue
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Export formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// ExportRecord is an issue with all its frontmatter and metrics computed from
+// the git history of its file
+type ExportRecord struct {
+	IssueSummary
+	// Fields holds the frontmatter fields not already in the summary
+	Fields map[string]string `json:"fields,omitempty"`
+	// Created is when the issue file was first committed (its modification
+	// time while uncommitted)
+	Created time.Time `json:"created,omitzero"`
+	// Started is when its status first became in-progress
+	Started time.Time `json:"started,omitzero"`
+	// Completed is when its status last became done, for done issues
+	Completed time.Time `json:"completed,omitzero"`
+	// AgeDays is the days from Created to Completed, or to now if not done
+	AgeDays *float64 `json:"age_days,omitempty"`
+	// CycleTimeDays is the days from Started to Completed
+	CycleTimeDays *float64 `json:"cycle_time_days,omitempty"`
+	// Assets are the files in the issue's assets directory (see AssetsDir)
+	Assets []string `json:"assets,omitempty"`
+}
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	// Filter keeps only matching issues (see ExportRecord.FilterFields)
+	Filter *filter.Expr
+	// Now is the time ages are measured to; zero means time.Now
+	Now time.Time
+}
+
+// frontmatter fields already in IssueSummary
+var summaryFields = map[string]bool{
+	"title":                  true,
+	"status":                 true,
+	"labels":                 true,
+	piece.IssueFieldPRNumber: true,
+	piece.IssueFieldPRURL:    true,
+}
+
+// Export returns every issue with its frontmatter and metrics, sorted by path
+func (h *Handler) Export(opts ExportOptions) ([]ExportRecord, error) {
+	issues, err := h.List("")
+	if err != nil {
+		return nil, err
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	all, err := h.records(issues, now)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []ExportRecord{}
+	for _, record := range all {
+		if opts.Filter.Match(record.FilterFields(), now) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// records adds the frontmatter and metrics of each issue to its summary
+func (h *Handler) records(issues []IssueSummary, now time.Time) ([]ExportRecord, error) {
+	issuesDirs, err := h.getIssuesDirectories()
+	if err != nil {
+		return nil, err
+	}
+	history := h.statusHistory(issuesDirs)
+
+	records := make([]ExportRecord, 0, len(issues))
+	for _, summary := range issues {
+		record := ExportRecord{IssueSummary: summary}
+		absPath := filepath.Join(h.workDir, summary.Path)
+		if content, err := h.deps.FS.ReadFile(absPath); err == nil {
+			for field, value := range piece.ExtractFields(string(content)) {
+				if !summaryFields[strings.ToLower(field)] {
+					if record.Fields == nil {
+						record.Fields = map[string]string{}
+					}
+					record.Fields[field] = value
+				}
+			}
+		}
+
+		record.Assets = h.Assets(summary.Path)
+
+		times := history[summary.Path]
+		record.Created, record.Started = times.created, times.started
+		if created, ok := parseCreatedAt(record.Fields[piece.IssueFieldCreatedAt]); ok {
+			record.Created = created
+		}
+		if record.Created.IsZero() {
+			if info, err := h.deps.FS.Stat(absPath); err == nil {
+				record.Created = info.ModTime()
+			}
+		}
+		end := now
+		if summary.Status == piece.StatusDone && !times.completed.IsZero() {
+			record.Completed = times.completed
+			end = times.completed
+		}
+		if !record.Created.IsZero() {
+			record.AgeDays = days(end.Sub(record.Created))
+		}
+		if !record.Started.IsZero() && !record.Completed.IsZero() {
+			record.CycleTimeDays = days(record.Completed.Sub(record.Started))
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// FilterFields returns the fields filter expressions match the record by: its
+// JSON fields, with the frontmatter fields also available by their own name
+// (priority as well as fields.priority)
+func (r ExportRecord) FilterFields() filter.Fields {
+	fields, err := filter.JSONFields(r)
+	if err != nil {
+		return filter.Fields{}
+	}
+	for field, value := range r.Fields {
+		name := strings.ToLower(field)
+		if _, taken := fields[name]; !taken {
+			fields[name] = []string{value}
+		}
+	}
+	return fields
+}
+
+// days converts d to days, rounded to one decimal
+func days(d time.Duration) *float64 {
+	value := math.Round(d.Hours()/24*10) / 10
+	return &value
+}
+
+// exportColumns are the CSV columns before the frontmatter fields
+var exportColumns = []string{
+	"id", "path", "title", "status", "team", "labels", "owners", "tasks_done", "tasks_total",
+	"pr_number", "pr_url", "created", "started", "completed", "age_days", "cycle_time_days", "assets",
+}
+
+// column returns the CSV value of one of exportColumns
+func (r ExportRecord) column(name string) string {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	formatDays := func(d *float64) string {
+		if d == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*d, 'f', -1, 64)
+	}
+
+	switch name {
+	case "id":
+		return r.ID
+	case "path":
+		return r.Path
+	case "title":
+		return r.Title
+	case "status":
+		return r.Status
+	case "team":
+		return r.Team
+	case "labels":
+		return strings.Join(r.Labels, ";")
+	case "owners":
+		return strings.Join(r.Owners, ";")
+	case "tasks_done", "tasks_total":
+		if r.Tasks == nil {
+			return ""
+		}
+		if name == "tasks_done" {
+			return strconv.Itoa(r.Tasks.Done)
+		}
+		return strconv.Itoa(r.Tasks.Total)
+	case "pr_number":
+		if r.PRNumber == 0 {
+			return ""
+		}
+		return strconv.Itoa(r.PRNumber)
+	case "pr_url":
+		return r.PRURL
+	case "created":
+		return formatTime(r.Created)
+	case "started":
+		return formatTime(r.Started)
+	case "completed":
+		return formatTime(r.Completed)
+	case "age_days":
+		return formatDays(r.AgeDays)
+	case "cycle_time_days":
+		return formatDays(r.CycleTimeDays)
+	case "assets":
+		return strings.Join(r.Assets, ";")
+	}
+	return ""
+}
+
+// ExportCSV writes records as CSV with a header row: the fixed columns, then
+// one column per frontmatter field found in any record, sorted by name
+func ExportCSV(records []ExportRecord) ([]byte, error) {
+	fieldSet := map[string]bool{}
+	for _, r := range records {
+		for field := range r.Fields {
+			fieldSet[field] = true
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(append(append([]string{}, exportColumns...), fields...)); err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		row := make([]string, 0, len(exportColumns)+len(fields))
+		for _, name := range exportColumns {
+			row = append(row, r.column(name))
+		}
+		for _, field := range fields {
+			row = append(row, r.Fields[field])
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// issueTimes are the points in an issue's life found in git history
+type issueTimes struct {
+	created, started, completed time.Time
+}
+
+// statusHistory reads when each issue file was first committed and when its
+// status changed from the git history of the repository. Without history
+// (e.g. outside a git repository) it returns no times.
+func (h *Handler) statusHistory(issuesDirs []string) map[string]issueTimes {
+	history := map[string]issueTimes{}
+	args := []string{"log", "--reverse", "--no-renames", "--no-color", "--unified=0", "--format=%x1e%aI", "-G^status:", "-p", "--"}
+	output, err := h.deps.Exec.RunWithDir(h.workDir, "git", append(args, issuesDirs...)...)
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("No git history for issue metrics: %v", err),
+		})
+		return history
+	}
+
+	for _, commit := range strings.Split(string(output), "\x1e") {
+		lines := strings.Split(commit, "\n")
+		when, err := time.Parse(time.RFC3339, strings.TrimSpace(lines[0]))
+		if err != nil {
+			continue
+		}
+		path := ""
+		for _, line := range lines[1:] {
+			if strings.HasPrefix(line, "+++ ") {
+				rest, ok := strings.CutPrefix(line, "+++ b/")
+				if !ok {
+					// Deleted file
+					path = ""
+					continue
+				}
+				path = filepath.FromSlash(rest)
+				times := history[path]
+				if times.created.IsZero() {
+					times.created = when
+				}
+				history[path] = times
+				continue
+			}
+			rest, ok := strings.CutPrefix(line, "+status:")
+			if !ok || path == "" {
+				continue
+			}
+			times := history[path]
+			switch strings.Trim(strings.TrimSpace(rest), `"'`) {
+			case piece.StatusInProgress:
+				if times.started.IsZero() {
+					times.started = when
+				}
+				times.completed = time.Time{}
+			case piece.StatusDone:
+				times.completed = when
+			default:
+				times.completed = time.Time{}
+			}
+			history[path] = times
+		}
+	}
+	return history
+}
@@ -0,0 +1,138 @@
+package issue_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+// statusLog is git log -p output: api.md created, started on Jan 2 and done on Jan 5
+const statusLog = "\x1e2026-01-01T00:00:00Z\n\n" +
+	"diff --git a/issues/api.md b/issues/api.md\nnew file mode 100644\n--- /dev/null\n+++ b/issues/api.md\n@@ -0,0 +1,4 @@\n+---\n+title: API\n+status: todo\n+---\n" +
+	"diff --git a/issues/login.md b/issues/login.md\nnew file mode 100644\n--- /dev/null\n+++ b/issues/login.md\n@@ -0,0 +1,3 @@\n+---\n+status: todo\n+---\n" +
+	"\x1e2026-01-02T00:00:00Z\n\ndiff --git a/issues/api.md b/issues/api.md\n--- a/issues/api.md\n+++ b/issues/api.md\n@@ -3 +3 @@\n-status: todo\n+status: in-progress\n" +
+	"\x1e2026-01-05T12:00:00Z\n\ndiff --git a/issues/api.md b/issues/api.md\n--- a/issues/api.md\n+++ b/issues/api.md\n@@ -3 +3 @@\n-status: in-progress\n+status: done\n"
+
+func setupExport(t *testing.T) (*adapters.MockExec, *issue.Handler) {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/api.md", []byte("---\ntitle: API\nstatus: done\npriority: high\nlabels: [infra]\n---\n"), 0644)
+	_ = fs.WriteFile("issues/login.md", []byte("---\ntitle: Login\nstatus: todo\n---\n"), 0644)
+
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"log", "--reverse", "--no-renames", "--no-color", "--unified=0", "--format=%x1e%aI", "-G^status:", "-p", "--", "issues"},
+		[]byte(statusLog), nil)
+	return mockExec, issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+}
+
+func TestHandler_Export(t *testing.T) {
+	_, handler := setupExport(t)
+	now := time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)
+
+	records, err := handler.Export(issue.ExportOptions{Now: now})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", records)
+	}
+
+	api := records[0]
+	if api.Fields["priority"] != "high" || api.Fields["title"] != "" {
+		t.Errorf("expected extra frontmatter fields only, got %v", api.Fields)
+	}
+	if *api.AgeDays != 4.5 || *api.CycleTimeDays != 3.5 {
+		t.Errorf("expected age 4.5 and cycle time 3.5 days, got %v and %v", *api.AgeDays, *api.CycleTimeDays)
+	}
+
+	login := records[1]
+	if *login.AgeDays != 10 || login.CycleTimeDays != nil || !login.Started.IsZero() {
+		t.Errorf("expected open issue aged 10 days without cycle time, got %+v", login)
+	}
+
+	csv, err := issue.ExportCSV(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	if !strings.HasSuffix(lines[0], ",age_days,cycle_time_days,assets,priority") {
+		t.Errorf("expected frontmatter columns after the fixed ones, got %q", lines[0])
+	}
+	if lines[1] != "api,issues/api.md,API,done,,infra,,,,,,2026-01-01T00:00:00Z,2026-01-02T00:00:00Z,2026-01-05T12:00:00Z,4.5,3.5,,high" {
+		t.Errorf("unexpected CSV row: %q", lines[1])
+	}
+}
+
+func TestHandler_Export_Filter(t *testing.T) {
+	_, handler := setupExport(t)
+	now := time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		filters []string
+		want    []string
+	}{
+		{[]string{"status=todo"}, []string{"login"}},
+		{[]string{"label=INFRA"}, []string{"api"}},
+		{[]string{"priority=high", "status=todo"}, nil},
+		{[]string{"cycle_time_days>3 OR title~log"}, []string{"api", "login"}},
+		{[]string{"created<30d AND completed>=2026-01-05"}, []string{"api"}},
+	} {
+		expr, err := filter.ParseAll(tt.filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := handler.Export(issue.ExportOptions{Filter: expr, Now: now})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range records {
+			ids = append(ids, r.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("filters %v: expected %v, got %v", tt.filters, tt.want, ids)
+		}
+	}
+}
+
+func TestHandler_ListWithOptions_Filter(t *testing.T) {
+	_, handler := setupExport(t)
+	expr, err := filter.Parse("fields.priority=high")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := handler.ListWithOptions(issue.ListOptions{Filter: expr})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != "api" {
+		t.Errorf("expected only api, got %+v", issues)
+	}
+}
+
+func TestHandler_Export_CreatedAtField(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/login.md", []byte("---\ntitle: Login\nstatus: todo\ncreated_at: 2026-01-06\n---\n"), 0644)
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"log", "--reverse", "--no-renames", "--no-color", "--unified=0", "--format=%x1e%aI", "-G^status:", "-p", "--", "issues"},
+		[]byte(statusLog), nil)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+
+	records, err := handler.Export(issue.ExportOptions{Now: time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected one record, got %+v (%v)", records, err)
+	}
+	if *records[0].AgeDays != 5 {
+		t.Errorf("expected created_at to override the first commit, got age %v", *records[0].AgeDays)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
 	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/labels"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
 )
 
@@ -32,16 +33,49 @@ func NewHandler(deps core.Deps, workDir string) *Handler {
 	return &Handler{deps: deps, workDir: workDir}
 }
 
-// Run creates an issue file with the given input
+// Run creates an issue file with the given input in the first issues directory
 func (h *Handler) Run(input Input) (IssueFile, error) {
+	return h.create(input, "", "")
+}
+
+// RunInDir creates an issue file in the issues directory named dir, either its
+// configured path or its team name (e.g. "backend" for issues/backend)
+func (h *Handler) RunInDir(input Input, dir string) (IssueFile, error) {
+	return h.create(input, "", dir)
+}
+
+// create writes a new issue file in the issues directory named dir (the first
+// if empty), linking it to a parent issue when parent is set
+func (h *Handler) create(input Input, parent, dir string) (IssueFile, error) {
 	// Apply defaults and validate
 	input = WithDefaults(input)
 	if err := Validate(input); err != nil {
 		return IssueFile{}, err
 	}
+	defs := h.issueFields()
+	input, err := withFields(input, defs)
+	if err != nil {
+		return IssueFile{}, err
+	}
+	if names := initcmd.SplitList(input.Fields["labels"]); len(names) > 0 {
+		taxonomy, err := labels.Load(h.deps.FS, h.workDir)
+		if err != nil {
+			return IssueFile{}, err
+		}
+		if err := taxonomy.Check(names); err != nil {
+			return IssueFile{}, err
+		}
+		// Write labels as defined, e.g. "bug" when given "Bug"
+		for i, name := range names {
+			if l, ok := taxonomy.Find(name); ok {
+				names[i] = l.Name
+			}
+		}
+		input.Fields["labels"] = strings.Join(names, ",")
+	}
 
 	// Get issues directory from config
-	issuesDir, err := h.getIssuesDirectory()
+	issuesDir, err := h.issuesDirectory(dir)
 	if err != nil {
 		return IssueFile{}, err
 	}
@@ -53,14 +87,14 @@ func (h *Handler) Run(input Input) (IssueFile, error) {
 	}
 
 	// Generate unique filename
-	baseName := piece.SanitizePieceName(input.Title)
+	baseName := piece.SanitizeName(input.Title)
 	filename, err := h.resolveUniqueFilename(fullIssuesDir, baseName)
 	if err != nil {
 		return IssueFile{}, err
 	}
 
 	// Build markdown content
-	content := h.buildMarkdownContent(input)
+	content := h.buildMarkdownContent(input, parent, defs)
 
 	// Write file
 	filePath := filepath.Join(fullIssuesDir, filename)
@@ -83,24 +117,52 @@ func (h *Handler) Run(input Input) (IssueFile, error) {
 	return result, nil
 }
 
-// getIssuesDirectory reads the issues directory from config
-func (h *Handler) getIssuesDirectory() (string, error) {
+// getIssuesDirectories reads the issues directories from config
+func (h *Handler) getIssuesDirectories() ([]string, error) {
 	cfg, err := piece.ReadConfig(h.workDir, h.deps.FS)
 	if err != nil {
-		return "", fmt.Errorf("failed to read config (run mp init first): %w", err)
+		return nil, fmt.Errorf("failed to read config (run mp init first): %w", err)
 	}
 
 	if cfg.Issues.Provider != "markdown" {
-		return "", fmt.Errorf("issue provider must be 'markdown', got: %s", cfg.Issues.Provider)
+		return nil, fmt.Errorf("issue provider must be 'markdown', got: %s", cfg.Issues.Provider)
+	}
+
+	return cfg.Issues.Dirs(), nil
+}
+
+// issuesDirectory finds the configured issues directory matching name by path
+// or team name. An empty name selects the first directory.
+func (h *Handler) issuesDirectory(name string) (string, error) {
+	dirs, err := h.getIssuesDirectories()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return dirs[0], nil
 	}
 
-	issuesDir, ok := cfg.Issues.Config["directory"]
-	if !ok || issuesDir == "" {
-		// Fallback to default
-		return "issues", nil
+	for _, dir := range dirs {
+		if dir == filepath.Clean(name) || initcmd.Team(dir) == name {
+			return dir, nil
+		}
 	}
+	return "", fmt.Errorf("unknown issues directory %q (configured: %s)", name, strings.Join(dirs, ", "))
+}
 
-	return issuesDir, nil
+// directoryOf returns the configured issues directory holding the issue at
+// relPath, or "" if it is outside all of them
+func (h *Handler) directoryOf(relPath string) string {
+	dirs, err := h.getIssuesDirectories()
+	if err != nil {
+		return ""
+	}
+	for _, dir := range dirs {
+		if filepath.Dir(relPath) == dir {
+			return dir
+		}
+	}
+	return ""
 }
 
 // resolveUniqueFilename generates a unique filename, adding numeric suffix if needed
@@ -127,7 +189,7 @@ func (h *Handler) resolveUniqueFilename(dir, baseName string) (string, error) {
 }
 
 // buildMarkdownContent creates the markdown file content with YAML frontmatter
-func (h *Handler) buildMarkdownContent(input Input) []byte {
+func (h *Handler) buildMarkdownContent(input Input, parent string, defs map[string]initcmd.IssueField) []byte {
 	var b strings.Builder
 
 	// YAML frontmatter
@@ -137,6 +199,10 @@ func (h *Handler) buildMarkdownContent(input Input) []byte {
 	if input.Description != "" {
 		b.WriteString(fmt.Sprintf("description: %s\n", escapeYAMLString(input.Description)))
 	}
+	if parent != "" {
+		b.WriteString(fmt.Sprintf("parent: %s\n", escapeYAMLString(parent)))
+	}
+	writeFields(&b, input.Fields, defs)
 	b.WriteString("---\n\n")
 
 	// Markdown body
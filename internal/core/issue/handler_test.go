@@ -95,6 +95,24 @@ func TestHandler_Run_SanitizesFilename(t *testing.T) {
 	}
 }
 
+func TestHandler_Run_KeepsLongTitleInFilename(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	title := strings.Repeat("Very Long Title ", 10)
+	result, err := handler.Run(issue.Input{Title: title})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Piece names are capped, issue filenames aren't
+	want := strings.TrimSuffix(strings.Repeat("very-long-title-", 10), "-") + ".md"
+	if result.Filename != want {
+		t.Errorf("expected filename %q, got %q", want, result.Filename)
+	}
+}
+
 func TestHandler_Run_DuplicateFilename_AddsNumericSuffix(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	out := adapters.NewBufferOutput()
@@ -0,0 +1,156 @@
+package issue
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// IndexFilename is the issue index in .monkeypuzzle, caching parsed issues
+const IndexFilename = piece.IssueIndexFilename
+
+// indexVersion is bumped when cached entries change shape, discarding old indexes
+const indexVersion = 4
+
+// index caches the summary of each issue file, keyed by relative path.
+// An entry is valid while the file's modification time and size are unchanged.
+type index struct {
+	Version int                   `json:"version"`
+	Issues  map[string]indexEntry `json:"issues"`
+}
+
+type indexEntry struct {
+	ModTime time.Time    `json:"mod_time"`
+	Size    int64        `json:"size"`
+	Summary IssueSummary `json:"summary"`
+}
+
+func (h *Handler) indexPath() string {
+	return filepath.Join(h.workDir, initcmd.DirName, IndexFilename)
+}
+
+// readIndex loads the issue index, returning an empty one if it is missing or stale
+func (h *Handler) readIndex() index {
+	empty := index{Version: indexVersion, Issues: map[string]indexEntry{}}
+	data, err := h.deps.FS.ReadFile(h.indexPath())
+	if err != nil {
+		return empty
+	}
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != indexVersion || idx.Issues == nil {
+		return empty
+	}
+	return idx
+}
+
+func (h *Handler) writeIndex(idx index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return h.deps.FS.WriteFile(h.indexPath(), data, initcmd.DefaultFilePerm)
+}
+
+// summaries returns a summary of every issue in issuesDirs, parsing only files
+// changed since they were indexed, and saves the updated index.
+// With reindex, every file is parsed again.
+func (h *Handler) summaries(issuesDirs []string, reindex bool) ([]IssueSummary, error) {
+	idx := h.readIndex()
+	if reindex {
+		idx.Issues = map[string]indexEntry{}
+	}
+	changed := reindex
+	seen := map[string]bool{}
+
+	var issues []IssueSummary
+	for _, issuesDir := range issuesDirs {
+		entries, err := h.deps.FS.ReadDir(filepath.Join(h.workDir, issuesDir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read issues directory: %w", err)
+		}
+
+		team := ""
+		if len(issuesDirs) > 1 {
+			team = initcmd.Team(issuesDir)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			relPath := filepath.Join(issuesDir, entry.Name())
+			seen[relPath] = true
+
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			if cached, ok := idx.Issues[relPath]; ok && cached.ModTime.Equal(info.ModTime()) && cached.Size == info.Size() {
+				cached.Summary.Team = team
+				issues = append(issues, cached.Summary)
+				continue
+			}
+
+			summary, err := h.summarize(relPath)
+			if err != nil {
+				continue
+			}
+			summary.Team = team
+			idx.Issues[relPath] = indexEntry{ModTime: info.ModTime(), Size: info.Size(), Summary: summary}
+			changed = true
+			issues = append(issues, summary)
+		}
+	}
+
+	for relPath := range idx.Issues {
+		if !seen[relPath] {
+			delete(idx.Issues, relPath)
+			changed = true
+		}
+	}
+
+	if changed {
+		// The index is only a cache; listing works without it
+		if err := h.writeIndex(idx); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to update issue index: %v", err),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// summarize parses the issue file at relPath
+func (h *Handler) summarize(relPath string) (IssueSummary, error) {
+	absPath := filepath.Join(h.workDir, relPath)
+
+	status, err := piece.ParseStatus(absPath, h.deps.FS)
+	if err != nil {
+		return IssueSummary{}, err
+	}
+
+	title, err := piece.ExtractIssueName(absPath, h.deps.FS)
+	if err != nil {
+		title = strings.TrimSuffix(filepath.Base(relPath), ".md")
+	}
+
+	summary := IssueSummary{ID: piece.IssueID(relPath), Path: relPath, Title: title, Status: status}
+	if content, err := h.deps.FS.ReadFile(absPath); err == nil {
+		summary.Labels = piece.ExtractLabels(string(content))
+		summary.PRNumber, _ = strconv.Atoi(piece.ExtractField(string(content), piece.IssueFieldPRNumber))
+		summary.PRURL = piece.ExtractField(string(content), piece.IssueFieldPRURL)
+		if tasks := ParseTasks(string(content)); len(tasks) > 0 {
+			ts := Summarize(tasks)
+			summary.Tasks = &ts
+		}
+	}
+	return summary, nil
+}
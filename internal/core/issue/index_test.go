@@ -0,0 +1,73 @@
+package issue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+const indexPath = ".monkeypuzzle/issues.index.json"
+
+func TestHandler_List_UsesIndex(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/small-fix.md", []byte("---\ntitle: Small Fix\nstatus: todo\n---\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	if _, err := handler.List(""); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	data, err := fs.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("expected index to be written: %v", err)
+	}
+
+	// Unchanged files are served from the index
+	_ = fs.WriteFile(indexPath, []byte(strings.Replace(string(data), "Small Fix", "Cached Fix", 1)), 0644)
+	issues, _ := handler.List("")
+	if len(issues) != 1 || issues[0].Title != "Cached Fix" {
+		t.Fatalf("expected cached summary, got %+v", issues)
+	}
+
+	reindexed, err := handler.ListWithOptions(issue.ListOptions{Reindex: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(reindexed) != 1 || reindexed[0].Title != "Small Fix" {
+		t.Errorf("expected reindex to re-parse, got %+v", reindexed)
+	}
+}
+
+func TestHandler_List_IndexTracksChanges(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/small-fix.md", []byte("---\ntitle: Small Fix\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("issues/old.md", []byte("---\ntitle: Old\nstatus: done\n---\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	if _, err := handler.List(""); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	_ = fs.WriteFile("issues/small-fix.md", []byte("---\ntitle: Small Fix\nstatus: in-progress\n---\n"), 0644)
+	_ = fs.Remove("issues/old.md")
+	_ = fs.WriteFile("issues/new.md", []byte("---\ntitle: New\nstatus: todo\n---\n"), 0644)
+
+	issues, err := handler.List("")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Path != "issues/new.md" || issues[1].Status != "in-progress" {
+		t.Fatalf("expected index to pick up changes, got %+v", issues)
+	}
+
+	data, _ := fs.ReadFile(indexPath)
+	if strings.Contains(string(data), "old.md") {
+		t.Errorf("expected removed issue to be dropped from the index, got %s", data)
+	}
+}
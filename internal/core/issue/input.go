@@ -34,6 +34,9 @@ var fields = []Field{
 type Input struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	// Fields sets frontmatter fields, e.g. those defined in issues.fields;
+	// list values are comma-separated
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // Schema returns the JSON schema with defaults for issue create
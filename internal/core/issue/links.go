@@ -0,0 +1,184 @@
+package issue
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// LintBrokenLink is the check of relative links in issue bodies, run with
+// LintOptions.Links
+const LintBrokenLink = "broken-link"
+
+// referenceDefinitionRegex matches a reference-style link definition,
+// "[label]: target"
+var referenceDefinitionRegex = regexp.MustCompile(`^(\s{0,3}\[[^\]]+\]:\s*)(\S+)(.*)$`)
+
+// codeSpanRegex matches inline code, whose links are not links
+var codeSpanRegex = regexp.MustCompile("`[^`]*`")
+
+// lintLinks reports relative links in the issue's markdown whose target does
+// not exist: other issues, repository files, and assets. Links starting with
+// / are relative to the repository root. A link to a missing issue file is
+// fixable when the issue was renamed (aliases maps old paths to new ones),
+// moved to another issues directory under the same ID, or archived.
+func (h *Handler) lintLinks(li *lintedIssue, issuesByID map[string][]string, aliases map[string]string) {
+	inFence := false
+	for _, line := range strings.Split(li.content, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = codeSpanRegex.ReplaceAllString(line, "")
+
+		var targets []string
+		for _, m := range markdownLinkRegex.FindAllStringSubmatch(line, -1) {
+			targets = append(targets, m[2])
+		}
+		if m := referenceDefinitionRegex.FindStringSubmatch(line); m != nil {
+			targets = append(targets, m[2])
+		}
+		for _, target := range targets {
+			h.lintLink(li, target, issuesByID, aliases)
+		}
+	}
+}
+
+// lintLink checks a single link target of the issue
+func (h *Handler) lintLink(li *lintedIssue, target string, issuesByID map[string][]string, aliases map[string]string) {
+	path, ok := linkPath(target)
+	if !ok {
+		return
+	}
+	var relPath string
+	if strings.HasPrefix(path, "/") {
+		relPath = filepath.Clean(strings.TrimPrefix(path, "/"))
+	} else {
+		relPath = filepath.Join(filepath.Dir(li.relPath), filepath.FromSlash(path))
+	}
+	if _, err := h.deps.FS.Stat(filepath.Join(h.workDir, relPath)); err == nil {
+		return
+	}
+
+	message := fmt.Sprintf("link to missing file %s", target)
+	var fix func(string) string
+	moved := aliases[relPath]
+	if moved == "" && strings.HasSuffix(relPath, ".md") {
+		archived := filepath.Join(filepath.Dir(relPath), ArchiveDir, filepath.Base(relPath))
+		if ids := issuesByID[piece.IssueID(relPath)]; len(ids) == 1 {
+			moved = ids[0]
+		} else if _, err := h.deps.FS.Stat(filepath.Join(h.workDir, archived)); err == nil {
+			moved = archived
+		}
+	}
+	if moved != "" {
+		if newTarget, err := filepath.Rel(filepath.Dir(li.relPath), moved); err == nil {
+			newTarget = filepath.ToSlash(newTarget)
+			if i := strings.IndexAny(target, "#?"); i >= 0 {
+				newTarget += target[i:]
+			}
+			message = fmt.Sprintf("link to missing file %s; the issue is now %s", target, newTarget)
+			fix = func(content string) string {
+				return replaceLinkTarget(content, target, newTarget)
+			}
+		}
+	}
+	li.report(LintBrokenLink, message, fix)
+}
+
+// linkPath returns the file a link target names, without its fragment or
+// query and unescaped, or false for URLs and in-page anchors
+func linkPath(target string) (string, bool) {
+	target = strings.Trim(target, "<>")
+	if target == "" || strings.HasPrefix(target, "#") || strings.Contains(target, "://") ||
+		strings.HasPrefix(target, "mailto:") {
+		return "", false
+	}
+	if i := strings.IndexAny(target, "#?"); i >= 0 {
+		target = target[:i]
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	return target, target != ""
+}
+
+// rebaseLinks rewrites the relative links in content, the issue moving from
+// oldPath to newPath in another directory, so they name the same files from
+// newPath. Links into the issue's assets directory move with it and are kept.
+func rebaseLinks(oldPath, newPath, content string) string {
+	oldDir, newDir := filepath.Dir(oldPath), filepath.Dir(newPath)
+	if oldDir == newDir {
+		return content
+	}
+	assets := filepath.Base(AssetsDir(newPath)) + "/"
+
+	var targets []string
+	for _, line := range strings.Split(content, "\n") {
+		for _, m := range markdownLinkRegex.FindAllStringSubmatch(line, -1) {
+			targets = append(targets, m[2])
+		}
+		if m := referenceDefinitionRegex.FindStringSubmatch(line); m != nil {
+			targets = append(targets, m[2])
+		}
+	}
+	rebased := make(map[string]string)
+	for _, target := range targets {
+		if _, ok := linkPath(target); !ok || strings.HasPrefix(target, "<") {
+			continue
+		}
+		// Work on the target as written, keeping its escaping and fragment
+		path, suffix := target, ""
+		if i := strings.IndexAny(target, "#?"); i >= 0 {
+			path, suffix = target[:i], target[i:]
+		}
+		path = strings.TrimPrefix(path, "./")
+		if strings.HasPrefix(path, "/") || strings.HasPrefix(path, assets) {
+			continue
+		}
+		newTarget, err := filepath.Rel(newDir, filepath.Join(oldDir, filepath.FromSlash(path)))
+		if err != nil {
+			continue
+		}
+		rebased[target] = filepath.ToSlash(newTarget) + suffix
+	}
+	return replaceLinkTargets(content, rebased)
+}
+
+// replaceLinkTarget points the inline links and reference definitions in
+// content with target at newTarget
+func replaceLinkTarget(content, target, newTarget string) string {
+	return replaceLinkTargets(content, map[string]string{target: newTarget})
+}
+
+// replaceLinkTargets points the inline links and reference definitions in
+// content with a target in newTargets at its new target
+func replaceLinkTargets(content string, newTargets map[string]string) string {
+	if len(newTargets) == 0 {
+		return content
+	}
+	content = markdownLinkRegex.ReplaceAllStringFunc(content, func(match string) string {
+		m := markdownLinkRegex.FindStringSubmatch(match)
+		newTarget, ok := newTargets[m[2]]
+		if !ok {
+			return match
+		}
+		return m[1] + newTarget + m[3]
+	})
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if m := referenceDefinitionRegex.FindStringSubmatch(line); m != nil {
+			if newTarget, ok := newTargets[m[2]]; ok {
+				lines[i] = m[1] + newTarget + m[3]
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
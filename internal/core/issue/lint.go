@@ -0,0 +1,360 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/labels"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Lint checks, reported in LintProblem.Check
+const (
+	LintFrontmatter     = "frontmatter"
+	LintRequiredField   = "required-field"
+	LintInvalidStatus   = "invalid-status"
+	LintUnknownStatus   = "unknown-status"
+	LintDuplicateID     = "duplicate-id"
+	LintBrokenReference = "broken-reference"
+	LintCustomField     = "custom-field"
+	LintUnknownLabel    = "unknown-label"
+)
+
+// referenceFields are the frontmatter fields naming other issues, by ID or path
+var referenceFields = []string{"parent", "depends_on"}
+
+// frontmatterKeyRegex matches a top-level "key: value" frontmatter line
+var frontmatterKeyRegex = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.*)$`)
+
+// LintProblem is a problem found in an issue file
+type LintProblem struct {
+	Path    string `json:"path"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+	// Fixable problems are corrected by LintOptions.Fix
+	Fixable bool `json:"fixable"`
+	Fixed   bool `json:"fixed,omitempty"`
+}
+
+// LintReport is the result of linting every issue file
+type LintReport struct {
+	Files    int           `json:"files"`
+	Problems []LintProblem `json:"problems"`
+	Fixed    int           `json:"fixed"`
+	// OK is set when no problems remain
+	OK bool `json:"ok"`
+}
+
+// LintOptions configures Lint
+type LintOptions struct {
+	// Fix rewrites issue files to correct fixable problems
+	Fix bool
+	// Links also checks that relative links in issue bodies resolve
+	Links bool
+}
+
+// lintedIssue is an issue file being linted
+type lintedIssue struct {
+	relPath  string
+	content  string
+	problems []LintProblem
+	// fixes rewrite the content to correct fixable problems
+	fixes []func(content string) string
+}
+
+func (l *lintedIssue) report(check, message string, fix func(string) string) {
+	l.problems = append(l.problems, LintProblem{Path: l.relPath, Check: check, Message: message, Fixable: fix != nil})
+	if fix != nil {
+		l.fixes = append(l.fixes, fix)
+	}
+}
+
+// Lint validates every issue file: parsable frontmatter with a title and a
+// known status, the custom fields of issues.fields, labels defined in
+// labels.json, unique short IDs, and parent/depends_on references naming
+// existing issues; with Links, also relative links in issue bodies (see
+// lintLinks). With Fix, missing frontmatter, titles and statuses, misspelled
+// statuses (e.g. "In Progress") and labels (e.g. "Bug" for "bug") and missing
+// required fields with a default are corrected in place.
+func (h *Handler) Lint(opts LintOptions) (LintReport, error) {
+	issuesDirs, err := h.getIssuesDirectories()
+	if err != nil {
+		return LintReport{}, err
+	}
+
+	var issues []*lintedIssue
+	for _, issuesDir := range issuesDirs {
+		entries, err := h.deps.FS.ReadDir(filepath.Join(h.workDir, issuesDir))
+		if err != nil {
+			return LintReport{}, fmt.Errorf("failed to read issues directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			relPath := filepath.Join(issuesDir, entry.Name())
+			content, err := h.deps.FS.ReadFile(filepath.Join(h.workDir, relPath))
+			if err != nil {
+				return LintReport{}, fmt.Errorf("failed to read issue file: %w", err)
+			}
+			issues = append(issues, &lintedIssue{relPath: relPath, content: string(content)})
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].relPath < issues[j].relPath })
+
+	// Index IDs and paths for duplicate and reference checks
+	byID := map[string][]string{}
+	known := map[string]bool{}
+	for _, li := range issues {
+		id := piece.IssueID(li.relPath)
+		byID[id] = append(byID[id], li.relPath)
+		known[li.relPath] = true
+		known[id] = true
+	}
+
+	defs := h.issueFields()
+	taxonomy, err := labels.Load(h.deps.FS, h.workDir)
+	if err != nil {
+		return LintReport{}, err
+	}
+	var aliases map[string]string
+	if opts.Links {
+		if aliases, err = piece.ReadIssueAliases(h.workDir, h.deps.FS); err != nil {
+			return LintReport{}, err
+		}
+	}
+	report := LintReport{Files: len(issues), Problems: []LintProblem{}}
+	for _, li := range issues {
+		h.lintFrontmatter(li, defs)
+		lintLabels(li, taxonomy)
+		if paths := byID[piece.IssueID(li.relPath)]; len(paths) > 1 {
+			li.report(LintDuplicateID, fmt.Sprintf("ID %s is shared by %s", piece.IssueID(li.relPath), strings.Join(paths, ", ")), nil)
+		}
+		for _, field := range referenceFields {
+			for _, ref := range piece.ExtractList(li.content, field) {
+				ref = filepath.Clean(ref)
+				if !known[ref] && !known[ref+".md"] && !known[strings.TrimSuffix(ref, ".md")] {
+					li.report(LintBrokenReference, fmt.Sprintf("%s refers to missing issue %s", field, ref), nil)
+				}
+			}
+		}
+		if opts.Links {
+			h.lintLinks(li, byID, aliases)
+		}
+
+		if opts.Fix && len(li.fixes) > 0 {
+			fixed := li.content
+			for _, fix := range li.fixes {
+				fixed = fix(fixed)
+			}
+			if err := h.deps.FS.WriteFile(filepath.Join(h.workDir, li.relPath), []byte(fixed), defaultFilePerm); err != nil {
+				return report, fmt.Errorf("failed to fix %s: %w", li.relPath, err)
+			}
+			for i := range li.problems {
+				if li.problems[i].Fixable {
+					li.problems[i].Fixed = true
+					report.Fixed++
+				}
+			}
+		}
+		report.Problems = append(report.Problems, li.problems...)
+	}
+
+	report.OK = true
+	for _, p := range report.Problems {
+		if p.Fixed {
+			continue
+		}
+		report.OK = false
+		hint := ""
+		if p.Fixable {
+			hint = " (fix with --fix)"
+		}
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("%s: %s%s", p.Path, p.Message, hint),
+		})
+	}
+
+	if report.OK {
+		content := fmt.Sprintf("%d issues OK", report.Files)
+		if report.Fixed > 0 {
+			content = fmt.Sprintf("Fixed %d problems, %d issues OK", report.Fixed, report.Files)
+		}
+		h.deps.Output.Write(core.Message{Type: core.MsgSuccess, Content: content, Data: report})
+	}
+
+	return report, nil
+}
+
+// lintFrontmatter checks that the issue has parsable frontmatter with a title,
+// a valid status and the custom fields defined in defs
+func (h *Handler) lintFrontmatter(li *lintedIssue, defs map[string]initcmd.IssueField) {
+	lines := strings.Split(li.content, "\n")
+	if strings.TrimSpace(strings.TrimSuffix(lines[0], "\r")) != "---" {
+		title := h.defaultTitle(li)
+		li.report(LintFrontmatter, "missing frontmatter", func(content string) string {
+			return fmt.Sprintf("---\ntitle: %s\nstatus: %s\n---\n\n%s", escapeYAMLString(title), piece.DefaultStatus, content)
+		})
+		return
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		li.report(LintFrontmatter, "frontmatter is not closed with ---", nil)
+		return
+	}
+
+	fields := map[string]string{}
+	for i, line := range lines[1:end] {
+		trimmed := strings.TrimSpace(line)
+		// Blank lines, comments, list items and indented continuations belong
+		// to the field above
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") || trimmed != strings.TrimLeft(line, " \t") {
+			continue
+		}
+		matches := frontmatterKeyRegex.FindStringSubmatch(strings.TrimSuffix(line, "\r"))
+		if matches == nil {
+			li.report(LintFrontmatter, fmt.Sprintf("cannot parse frontmatter line %d: %q", i+2, trimmed), nil)
+			continue
+		}
+		key := strings.ToLower(matches[1])
+		if _, dup := fields[key]; dup {
+			li.report(LintFrontmatter, fmt.Sprintf("field %s is set more than once", key), nil)
+			continue
+		}
+		fields[key] = strings.Trim(strings.TrimSpace(matches[2]), `"'`)
+	}
+
+	if fields["title"] == "" {
+		title := h.defaultTitle(li)
+		li.report(LintRequiredField, "missing title", func(content string) string {
+			return setFrontmatterField(content, "title", escapeYAMLString(title))
+		})
+	}
+
+	status, ok := fields["status"]
+	switch {
+	case !ok || status == "":
+		li.report(LintRequiredField, "missing status", func(content string) string {
+			return setFrontmatterField(content, "status", piece.DefaultStatus)
+		})
+	case piece.ValidateStatus(status):
+	case piece.ValidateStatus(normalizeStatus(status)):
+		normalized := normalizeStatus(status)
+		li.report(LintInvalidStatus, fmt.Sprintf("status %q should be written %q", status, normalized), func(content string) string {
+			return setFrontmatterField(content, "status", normalized)
+		})
+	default:
+		li.report(LintUnknownStatus, fmt.Sprintf("unknown status %q (valid: %s, %s, %s)", status, piece.StatusTodo, piece.StatusInProgress, piece.StatusDone), nil)
+	}
+
+	for _, problem := range checkFields(li.content, defs) {
+		var fix func(string) string
+		if problem.value != "" {
+			value := fieldValue(defs[problem.field], problem.value)
+			fix = func(content string) string {
+				return setFrontmatterField(content, problem.field, value)
+			}
+		}
+		li.report(LintCustomField, problem.message, fix)
+	}
+}
+
+// lintLabels checks that the issue's labels are defined in the taxonomy.
+// A label written in another case than its definition is fixable.
+func lintLabels(li *lintedIssue, taxonomy *labels.Taxonomy) {
+	if !taxonomy.Defined() {
+		return
+	}
+	issueLabels := piece.ExtractLabels(li.content)
+	canonical := make([]string, len(issueLabels))
+	for i, name := range issueLabels {
+		canonical[i] = name
+		if l, ok := taxonomy.Find(name); ok {
+			canonical[i] = l.Name
+		}
+	}
+	for i, name := range issueLabels {
+		switch {
+		case canonical[i] != name:
+			li.report(LintUnknownLabel, fmt.Sprintf("label %q should be written %q", name, canonical[i]), func(content string) string {
+				return setFrontmatterList(content, "labels", canonical)
+			})
+		case len(taxonomy.Unknown([]string{name})) > 0:
+			li.report(LintUnknownLabel, fmt.Sprintf("unknown label %q (defined in %s)", name, labels.Filename), nil)
+		}
+	}
+}
+
+// defaultTitle is the title a fix gives an issue: its first H1 heading, else
+// its file name
+func (h *Handler) defaultTitle(li *lintedIssue) string {
+	for _, line := range strings.Split(li.content, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "# ") {
+			if title := strings.TrimSpace(trimmed[2:]); title != "" {
+				return title
+			}
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(li.relPath), ".md")
+}
+
+// normalizeStatus rewrites common misspellings of a status, e.g. "In Progress"
+// or "in_progress" for "in-progress"
+func normalizeStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(status)
+}
+
+// setFrontmatterField replaces the value of a frontmatter field, or adds the
+// field after the opening --- if it is missing
+func setFrontmatterField(content, key, value string) string {
+	lines := strings.Split(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			break
+		}
+		if matches := frontmatterKeyRegex.FindStringSubmatch(lines[i]); matches != nil && strings.EqualFold(matches[1], key) {
+			lines[i] = fmt.Sprintf("%s: %s", key, value)
+			return strings.Join(lines, "\n")
+		}
+	}
+	lines = append(lines[:1], append([]string{fmt.Sprintf("%s: %s", key, value)}, lines[1:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+// setFrontmatterList sets a list field to items, written inline as [a, b]
+// in place of its inline or block list
+func setFrontmatterList(content, key string, items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = escapeYAMLString(item)
+	}
+	lines := strings.Split(setFrontmatterField(content, key, "["+strings.Join(escaped, ", ")+"]"), "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			break
+		}
+		if matches := frontmatterKeyRegex.FindStringSubmatch(lines[i]); matches != nil && strings.EqualFold(matches[1], key) {
+			end := i + 1
+			for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), "- ") {
+				end++
+			}
+			lines = append(lines[:i+1], lines[end:]...)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,247 @@
+package issue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+func setupLint(t *testing.T, files map[string]string) (*adapters.MemoryFS, *adapters.BufferOutput, *issue.Handler) {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	for name, content := range files {
+		_ = fs.WriteFile("issues/"+name, []byte(content), 0644)
+	}
+	return fs, out, issue.NewHandler(core.Deps{FS: fs, Output: out}, "")
+}
+
+// problemChecks returns "path check" for each problem, fixed ones marked with "(fixed)"
+func problemChecks(report issue.LintReport) []string {
+	var checks []string
+	for _, p := range report.Problems {
+		check := p.Path + " " + p.Check
+		if p.Fixed {
+			check += " (fixed)"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func TestHandler_Lint_Clean(t *testing.T) {
+	_, out, handler := setupLint(t, map[string]string{
+		"api.md":   "---\ntitle: API\nstatus: todo\nlabels:\n  - infra\n---\n",
+		"login.md": "---\ntitle: Login\nstatus: \"in-progress\"\nparent: issues/api.md\ndepends_on: [api]\n---\n",
+	})
+
+	report, err := handler.Lint(issue.LintOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !report.OK || report.Files != 2 || len(report.Problems) != 0 {
+		t.Errorf("expected clean report, got %+v", report)
+	}
+	if !out.HasSuccess() {
+		t.Error("expected success message")
+	}
+}
+
+func TestHandler_Lint_ReportsProblems(t *testing.T) {
+	_, out, handler := setupLint(t, map[string]string{
+		"bare.md":      "# Bare issue\n",
+		"open.md":      "---\ntitle: Open\nstatus: todo\n",
+		"broken.md":    "---\ntitle: Broken\nnot yaml\nstatus: todo\n---\n",
+		"untitled.md":  "---\nstatus: todo\n---\n",
+		"spelling.md":  "---\ntitle: Spelling\nstatus: In Progress\n---\n",
+		"unknown.md":   "---\ntitle: Unknown\nstatus: blocked\n---\n",
+		"orphan.md":    "---\ntitle: Orphan\nstatus: todo\nparent: issues/gone.md\ndepends_on:\n  - bare\n  - missing\n---\n",
+		"nostatus.md":  "---\ntitle: No status\n---\n",
+		"duplicate.md": "---\ntitle: Dup\nstatus: todo\nstatus: done\n---\n",
+	})
+
+	report, err := handler.Lint(issue.LintOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if report.OK {
+		t.Fatal("expected problems")
+	}
+
+	want := []string{
+		"issues/bare.md frontmatter",
+		"issues/broken.md frontmatter",
+		"issues/duplicate.md frontmatter",
+		"issues/nostatus.md required-field",
+		"issues/open.md frontmatter",
+		"issues/orphan.md broken-reference",
+		"issues/orphan.md broken-reference",
+		"issues/spelling.md invalid-status",
+		"issues/unknown.md unknown-status",
+		"issues/untitled.md required-field",
+	}
+	if got := problemChecks(report); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected problems:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if !out.HasWarning() {
+		t.Error("expected warnings for problems")
+	}
+}
+
+func TestHandler_Lint_Fix(t *testing.T) {
+	fs, _, handler := setupLint(t, map[string]string{
+		"bare.md":     "# Bare issue\n",
+		"untitled.md": "---\nstatus: in_progress\n---\n\n# Untitled issue\n",
+		"unknown.md":  "---\ntitle: Unknown\nstatus: blocked\n---\n",
+	})
+
+	report, err := handler.Lint(issue.LintOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if report.OK || report.Fixed != 3 {
+		t.Errorf("expected 3 fixes and the unknown status left, got %+v", report)
+	}
+
+	bare, _ := fs.ReadFile("issues/bare.md")
+	if string(bare) != "---\ntitle: Bare issue\nstatus: todo\n---\n\n# Bare issue\n" {
+		t.Errorf("unexpected fixed content:\n%s", bare)
+	}
+	untitled, _ := fs.ReadFile("issues/untitled.md")
+	if string(untitled) != "---\ntitle: Untitled issue\nstatus: in-progress\n---\n\n# Untitled issue\n" {
+		t.Errorf("unexpected fixed content:\n%s", untitled)
+	}
+
+	report, err = handler.Lint(issue.LintOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got := problemChecks(report); len(got) != 1 || got[0] != "issues/unknown.md unknown-status" {
+		t.Errorf("expected only the unknown status after fixing, got %v", got)
+	}
+}
+
+func TestHandler_Lint_DuplicateIDs(t *testing.T) {
+	fs, handler := setupTeams(t)
+	_ = fs.WriteFile("issues/frontend/api.md", []byte("---\ntitle: API client\nstatus: todo\n---\n"), 0644)
+
+	report, err := handler.Lint(issue.LintOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := []string{"issues/backend/api.md duplicate-id", "issues/frontend/api.md duplicate-id"}
+	if got := problemChecks(report); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestHandler_Lint_Links(t *testing.T) {
+	fs, _, handler := setupLint(t, map[string]string{
+		"api.md": "---\ntitle: API\nstatus: todo\n---\n",
+		"login.md": "---\ntitle: Login\nstatus: todo\n---\n\n" +
+			"See [API](api.md#design), [the README](/README.md) and ![shot](login.assets/shot.png).\n" +
+			"Also [gone](gone.md), [docs](https://example.com/gone.md) and [top](#login).\n" +
+			"Not links: `[x](nowhere.md)`\n\n```\n[y](nowhere.md)\n```\n\n" +
+			"[ref]: ../docs/missing.md\n",
+	})
+	_ = fs.WriteFile("README.md", []byte("# Readme\n"), 0644)
+	_ = fs.MkdirAll("issues/login.assets", 0755)
+	_ = fs.WriteFile("issues/login.assets/shot.png", []byte("png"), 0644)
+
+	report, err := handler.Lint(issue.LintOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(report.Problems) != 0 {
+		t.Errorf("expected links unchecked without Links, got %v", problemChecks(report))
+	}
+
+	report, err = handler.Lint(issue.LintOptions{Links: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := []string{"issues/login.md broken-link", "issues/login.md broken-link"}
+	if got := problemChecks(report); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if msg := report.Problems[0].Message; msg != "link to missing file gone.md" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if report.Problems[0].Fixable || report.Problems[1].Fixable {
+		t.Error("expected links to unknown files not to be fixable")
+	}
+}
+
+func TestHandler_Lint_LinksFixMovedIssues(t *testing.T) {
+	fs, _, handler := setupLint(t, map[string]string{
+		"login.md": "---\ntitle: Login\nstatus: todo\n---\n\n" +
+			"Needs [the API](api.md#endpoints) first.\n\n[api]: ./api.md\n",
+	})
+	_ = fs.MkdirAll("issues/archive", 0755)
+	_ = fs.WriteFile("issues/archive/api.md", []byte("---\ntitle: API\nstatus: done\n---\n"), 0644)
+
+	report, err := handler.Lint(issue.LintOptions{Links: true, Fix: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !report.OK || report.Fixed != 2 {
+		t.Errorf("expected both links fixed, got %+v", report)
+	}
+	login, _ := fs.ReadFile("issues/login.md")
+	want := "---\ntitle: Login\nstatus: todo\n---\n\n" +
+		"Needs [the API](archive/api.md#endpoints) first.\n\n[api]: archive/api.md\n"
+	if string(login) != want {
+		t.Errorf("unexpected fixed content:\n%s", login)
+	}
+}
+
+func TestHandler_Lint_LinksFixRenamedIssues(t *testing.T) {
+	fs, out, _ := setupLint(t, map[string]string{
+		"api.md":   "---\ntitle: API\nstatus: todo\n---\n",
+		"login.md": "---\ntitle: Login\nstatus: todo\n---\n\nNeeds [the API](api.md).\n",
+	})
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\n"), nil)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec}, "")
+	if _, err := handler.Rename("api", "rest-api"); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+
+	report, err := handler.Lint(issue.LintOptions{Links: true, Fix: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !report.OK || report.Fixed != 1 {
+		t.Errorf("expected the link fixed, got %+v", report)
+	}
+	login, _ := fs.ReadFile("issues/login.md")
+	if !strings.Contains(string(login), "[the API](rest-api.md)") {
+		t.Errorf("expected link to the renamed issue, got:\n%s", login)
+	}
+}
+
+func TestHandler_Lint_Labels(t *testing.T) {
+	fs, _, handler := setupLint(t, map[string]string{
+		"api.md":   "---\ntitle: API\nstatus: todo\nlabels:\n  - Bug\n  - infra\n---\n",
+		"login.md": "---\ntitle: Login\nstatus: todo\nlabels: [bug, ui]\n---\n",
+	})
+	_ = fs.WriteFile(".monkeypuzzle/labels.json", []byte(`{"labels": [{"name": "bug"}, {"name": "infra"}]}`), 0644)
+
+	report, err := handler.Lint(issue.LintOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := []string{"issues/api.md unknown-label (fixed)", "issues/login.md unknown-label"}
+	if got := problemChecks(report); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	api, _ := fs.ReadFile("issues/api.md")
+	if string(api) != "---\ntitle: API\nstatus: todo\nlabels: [bug, infra]\n---\n" {
+		t.Errorf("unexpected fixed content:\n%s", api)
+	}
+}
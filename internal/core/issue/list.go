@@ -0,0 +1,195 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/owners"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// IssueSummary describes an issue in a listing
+type IssueSummary struct {
+	// ID is the short ID accepted wherever an issue is expected
+	ID     string       `json:"id"`
+	Path   string       `json:"path"`
+	Title  string       `json:"title"`
+	Status string       `json:"status"`
+	Team   string       `json:"team,omitempty"` // set when several issues directories are configured
+	Labels []string     `json:"labels,omitempty"`
+	Owners []string     `json:"owners,omitempty"` // from .monkeypuzzle/owners
+	Tasks  *TaskSummary `json:"tasks,omitempty"`
+	// PRNumber and PRURL link the PR implementing the issue, from its frontmatter
+	PRNumber int    `json:"pr_number,omitempty"`
+	PRURL    string `json:"pr_url,omitempty"`
+}
+
+// ListOptions configures issue listing
+type ListOptions struct {
+	// Status keeps only issues with this status
+	Status string
+	// Team keeps only issues in this issues directory, by team name or path
+	Team string
+	// Owner keeps only issues owned by this handle or email (see owners.Includes)
+	Owner string
+	// Reindex parses every issue again instead of trusting the index
+	Reindex bool
+	// Filter keeps only issues matching the expression, evaluated over the
+	// fields of their export record (see ExportRecord.FilterFields)
+	Filter *filter.Expr
+}
+
+// List returns the issues in the issues directories, optionally filtered by status.
+// Issues with a task list include its completion.
+func (h *Handler) List(statusFilter string) ([]IssueSummary, error) {
+	return h.ListWithOptions(ListOptions{Status: statusFilter})
+}
+
+// ListWithOptions lists issues like List. Parsed issues are cached in
+// .monkeypuzzle/issues.index.json and reused while their files are unchanged.
+func (h *Handler) ListWithOptions(opts ListOptions) ([]IssueSummary, error) {
+	if opts.Status != "" && !piece.ValidateStatus(opts.Status) {
+		return nil, fmt.Errorf("invalid status: %q", opts.Status)
+	}
+
+	issuesDirs, err := h.getIssuesDirectories()
+	if err != nil {
+		return nil, err
+	}
+
+	teamDir := ""
+	if opts.Team != "" {
+		if teamDir, err = h.issuesDirectory(opts.Team); err != nil {
+			return nil, err
+		}
+	}
+
+	all, err := h.summaries(issuesDirs, opts.Reindex)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := owners.Load(h.deps.FS, h.workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []IssueSummary{}
+	for _, summary := range all {
+		if opts.Status != "" && summary.Status != opts.Status {
+			continue
+		}
+		if teamDir != "" && filepath.Dir(summary.Path) != teamDir {
+			continue
+		}
+		summary.Owners = rules.For(summary.Path, summary.Labels)
+		if opts.Owner != "" && !owners.Includes(summary.Owners, opts.Owner) {
+			continue
+		}
+		issues = append(issues, summary)
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+
+	if opts.Filter != nil {
+		now := time.Now()
+		records, err := h.records(issues, now)
+		if err != nil {
+			return nil, err
+		}
+		issues = issues[:0]
+		for _, record := range records {
+			if opts.Filter.Match(record.FilterFields(), now) {
+				issues = append(issues, record.IssueSummary)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// IssueRef identifies an issue by short ID and path relative to the repository root
+type IssueRef struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+}
+
+// Resolve finds an existing issue by short ID (file name without .md) or path
+func (h *Handler) Resolve(id string) (IssueRef, error) {
+	_, relPath, err := h.resolveIssue(id)
+	if err != nil {
+		return IssueRef{}, err
+	}
+	return IssueRef{ID: piece.IssueID(relPath), Path: relPath}, nil
+}
+
+// resolveIssue finds an issue by path or by file name (with or without .md)
+// in the issues directories, returning its absolute and relative paths. A file
+// name found in several issues directories is ambiguous.
+func (h *Handler) resolveIssue(id string) (absPath, relPath string, err error) {
+	if id == "" {
+		return "", "", fmt.Errorf("issue id is required")
+	}
+
+	issuesDirs, err := h.getIssuesDirectories()
+	if err != nil {
+		return "", "", err
+	}
+
+	names := []string{id}
+	if !strings.HasSuffix(id, ".md") {
+		names = append(names, id+".md")
+	}
+
+	for _, c := range names {
+		if abs, rel, ok := h.statIssue(c); ok {
+			return abs, rel, nil
+		}
+	}
+
+	var matches []string
+	for _, dir := range issuesDirs {
+		for _, name := range names {
+			if abs, rel, ok := h.statIssue(filepath.Join(dir, name)); ok {
+				absPath, relPath = abs, rel
+				matches = append(matches, rel)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		// An issue renamed with Rename is found by its old ID or path
+		if aliased, ok := piece.ResolveIssueAlias(h.workDir, id, h.deps.FS); ok {
+			if abs, rel, ok := h.statIssue(aliased); ok {
+				return abs, rel, nil
+			}
+		}
+		return "", "", fmt.Errorf("issue not found: %s", id)
+	case 1:
+		return absPath, relPath, nil
+	default:
+		return "", "", fmt.Errorf("issue %s is ambiguous, use its path: %s", id, strings.Join(matches, ", "))
+	}
+}
+
+// statIssue reports whether candidate, absolute or relative to the working
+// directory, is an issue file, returning its absolute and relative paths
+func (h *Handler) statIssue(candidate string) (absPath, relPath string, ok bool) {
+	absPath = candidate
+	if !filepath.IsAbs(candidate) {
+		absPath = filepath.Join(h.workDir, candidate)
+	}
+	info, err := h.deps.FS.Stat(absPath)
+	if err != nil || info.IsDir() {
+		return "", "", false
+	}
+	relPath, err = filepath.Rel(h.workDir, absPath)
+	if err != nil || h.workDir == "" {
+		relPath = candidate
+	}
+	return absPath, relPath, true
+}
@@ -0,0 +1,298 @@
+package issue
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// PendingDir holds staged issue edits in .monkeypuzzle, one JSON file per
+// issue at the issue's path plus ".json"
+const PendingDir = "pending"
+
+// Actions of a pending change
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// PendingChange is an edit to an issue file staged for approval
+type PendingChange struct {
+	IssueRef
+	Action string `json:"action"`
+	// Content is the issue file after the change; empty for deletes
+	Content string `json:"content,omitempty"`
+	// Base is the hash of the issue file when the change was first staged,
+	// empty for creates; approving fails if the file has changed since
+	Base string `json:"base,omitempty"`
+	// MovedTo is the issue a deleted issue was renamed or merged into.
+	// Approving the delete records the old path as an alias and points
+	// pieces working on it at MovedTo.
+	MovedTo  string    `json:"moved_to,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	StagedAt time.Time `json:"staged_at"`
+}
+
+// PendingOptions selects the pending changes Approve and Reject act on
+type PendingOptions struct {
+	// IDs names issues by short ID or path
+	IDs []string
+	// All selects every pending change
+	All bool
+	// Force approves changes to issues edited since they were staged
+	Force bool
+}
+
+// Pending returns the staged issue changes, by path
+func (h *Handler) Pending() ([]PendingChange, error) {
+	changes := []PendingChange{}
+	root := pendingRoot(h.workDir)
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := h.deps.FS.ReadDir(dir)
+		if err != nil {
+			return nil
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() {
+				if err := walk(path); err != nil {
+					return err
+				}
+				continue
+			}
+			if !strings.HasSuffix(entry.Name(), ".md.json") {
+				continue
+			}
+			change, err := readPendingFile(h.deps.FS, path)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+		return nil
+	}
+	if err := walk(root); err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// Approve applies the selected pending changes to the issue files. Unless
+// opts.Force is set, it refuses when an issue has changed since its edit was
+// staged. If applying one fails, the issues already written are restored.
+func (h *Handler) Approve(opts PendingOptions) ([]PendingChange, error) {
+	changes, err := h.selectPending(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	originals := map[string][]byte{}
+	var conflicts []string
+	for _, change := range changes {
+		current, err := h.deps.FS.ReadFile(filepath.Join(h.workDir, change.Path))
+		if err == nil {
+			originals[change.Path] = current
+		}
+		if hash := contentHash(current, err == nil); hash != change.Base {
+			conflicts = append(conflicts, change.Path)
+		}
+	}
+	if len(conflicts) > 0 && !opts.Force {
+		return nil, fmt.Errorf("%s changed since the edits were staged; review them with mp issue pending, then approve with --force or reject them",
+			strings.Join(conflicts, ", "))
+	}
+
+	op := operation.New(h.deps.Output, fmt.Sprintf("approve %d staged issue changes", len(changes)))
+	if err := h.recordMoves(op, changes); err != nil {
+		return nil, op.Fail(err)
+	}
+	for _, change := range changes {
+		absPath := filepath.Join(h.workDir, change.Path)
+		original, existed := originals[change.Path]
+		if err := h.applyPending(absPath, change); err != nil {
+			return nil, op.Fail(fmt.Errorf("failed to apply the staged change to %s: %w", change.Path, err))
+		}
+		op.Undo("restore "+change.Path, func() error {
+			if !existed {
+				return h.deps.FS.Remove(absPath)
+			}
+			return h.deps.FS.WriteFile(absPath, original, defaultFilePerm)
+		})
+	}
+	h.removePending(changes)
+	h.repointMovedPieces(changes)
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Applied %d staged issue changes", len(changes)),
+		Data:    changes,
+	})
+	return changes, nil
+}
+
+// Reject discards the selected pending changes
+func (h *Handler) Reject(opts PendingOptions) ([]PendingChange, error) {
+	changes, err := h.selectPending(opts)
+	if err != nil {
+		return nil, err
+	}
+	h.removePending(changes)
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Discarded %d staged issue changes", len(changes)),
+		Data:    changes,
+	})
+	return changes, nil
+}
+
+// recordMoves records the old paths of the moved issues among changes as
+// aliases, before any change is applied, registering with op the restoring
+// of the aliases file
+func (h *Handler) recordMoves(op *operation.Runner, changes []PendingChange) error {
+	path := filepath.Join(h.workDir, initcmd.DirName, piece.IssueAliasesFilename)
+	original, readErr := h.deps.FS.ReadFile(path)
+	recorded := false
+	for _, change := range changes {
+		if change.Action != ActionDelete || change.MovedTo == "" {
+			continue
+		}
+		if !recorded {
+			op.Undo("restore "+piece.IssueAliasesFilename, func() error {
+				if readErr != nil {
+					return h.deps.FS.Remove(path)
+				}
+				return h.deps.FS.WriteFile(path, original, defaultFilePerm)
+			})
+			recorded = true
+		}
+		if err := h.recordAlias(change.Path, change.MovedTo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repointMovedPieces points the pieces working on the moved issues among
+// applied changes at the issues they moved to
+func (h *Handler) repointMovedPieces(changes []PendingChange) {
+	for _, change := range changes {
+		if change.Action == ActionDelete && change.MovedTo != "" {
+			h.repointPieces(change.Path, change.MovedTo)
+		}
+	}
+}
+
+// selectPending returns the pending changes named in opts, or all of them
+func (h *Handler) selectPending(opts PendingOptions) ([]PendingChange, error) {
+	if !opts.All && len(opts.IDs) == 0 {
+		return nil, fmt.Errorf("no staged changes selected; give issue IDs or --all")
+	}
+	pending, err := h.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if opts.All {
+		if len(pending) == 0 {
+			return nil, fmt.Errorf("no staged issue changes")
+		}
+		return pending, nil
+	}
+
+	var selected []PendingChange
+	for _, id := range opts.IDs {
+		i := slices.IndexFunc(pending, func(c PendingChange) bool {
+			return c.Path == filepath.Clean(id) || c.ID == strings.TrimSuffix(id, ".md")
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("no staged change to issue %q (see mp issue pending)", id)
+		}
+		if !slices.ContainsFunc(selected, func(c PendingChange) bool { return c.Path == pending[i].Path }) {
+			selected = append(selected, pending[i])
+		}
+	}
+	return selected, nil
+}
+
+// applyPending writes a pending change to the issue file at absPath
+func (h *Handler) applyPending(absPath string, change PendingChange) error {
+	if change.Action == ActionDelete {
+		return h.deps.FS.Remove(absPath)
+	}
+	if err := h.deps.FS.MkdirAll(filepath.Dir(absPath), initcmd.DefaultDirPerm); err != nil {
+		return err
+	}
+	return h.deps.FS.WriteFile(absPath, []byte(change.Content), defaultFilePerm)
+}
+
+// removePending deletes the files of applied or discarded changes. A file
+// left behind only shows up in mp issue pending again, so failures warn.
+func (h *Handler) removePending(changes []PendingChange) {
+	for _, change := range changes {
+		if err := h.deps.FS.Remove(pendingPath(h.workDir, change.Path)); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to remove the staged change to %s: %v", change.Path, err),
+			})
+		}
+	}
+}
+
+// pendingRoot returns the pending directory of the repository at root
+func pendingRoot(root string) string {
+	return filepath.Join(root, initcmd.DirName, PendingDir)
+}
+
+// pendingPath returns the file holding the pending change to the issue at
+// relPath
+func pendingPath(root, relPath string) string {
+	return filepath.Join(pendingRoot(root), relPath+".json")
+}
+
+func readPendingFile(fs core.FS, path string) (PendingChange, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return PendingChange{}, err
+	}
+	var change PendingChange
+	if err := json.Unmarshal(data, &change); err != nil {
+		return PendingChange{}, fmt.Errorf("failed to parse staged change %s: %w", path, err)
+	}
+	return change, nil
+}
+
+func writePendingFile(fs core.FS, root string, change PendingChange) error {
+	path := pendingPath(root, change.Path)
+	if err := fs.MkdirAll(filepath.Dir(path), initcmd.DefaultDirPerm); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(change, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFile(path, data, initcmd.DefaultFilePerm)
+}
+
+// contentHash identifies the content of an issue file; a missing file
+// hashes to ""
+func contentHash(content []byte, exists bool) string {
+	if !exists {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
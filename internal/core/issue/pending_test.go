@@ -0,0 +1,217 @@
+package issue_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// setupApproval configures the repository to require approval of agent edits
+func setupApproval(t *testing.T, fs *adapters.MemoryFS) {
+	t.Helper()
+	cfg := initcmd.Config{
+		Version: "1",
+		Issues: initcmd.IssueConfig{
+			Provider:        "markdown",
+			Config:          map[string]string{"directory": "issues"},
+			RequireApproval: true,
+		},
+	}
+	data, _ := json.Marshal(cfg)
+	_ = fs.MkdirAll(".monkeypuzzle", 0755)
+	_ = fs.WriteFile(".monkeypuzzle/monkeypuzzle.json", data, 0644)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: todo\n---\n"), 0644)
+}
+
+func TestStagingFS_StagesIssueEdits(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupApproval(t, fs)
+	staging := issue.NewStagingFS(fs, "mp issue set-status a done")
+	agent := issue.NewHandler(core.Deps{FS: staging, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}, "")
+
+	if _, err := agent.SetStatus(piece.StatusDone, issue.SetStatusOptions{IDs: []string{"a"}}); err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	if _, err := agent.Run(issue.Input{Title: "New thing"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	_ = fs.WriteFile(".monkeypuzzle/notes.md", []byte("not an issue"), 0644)
+
+	if status, _ := piece.ParseStatus("issues/a.md", fs); status != piece.StatusTodo {
+		t.Errorf("expected the issue file untouched, got status %q", status)
+	}
+	if _, err := fs.Stat("issues/new-thing.md"); err == nil {
+		t.Error("expected the new issue to be staged, not written")
+	}
+	if status, _ := piece.ParseStatus("issues/a.md", staging); status != piece.StatusDone {
+		t.Errorf("expected reads through the staging FS to see the edit, got %q", status)
+	}
+	listed, err := agent.List("")
+	if err != nil || len(listed) != 2 {
+		t.Errorf("expected the staged issue listed, got %+v (%v)", listed, err)
+	}
+	if got := strings.Join(staging.Staged(), ","); got != "issues/a.md,issues/new-thing.md" {
+		t.Errorf("unexpected staged paths %q", got)
+	}
+
+	human := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+	pending, err := human.Pending()
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Action != issue.ActionUpdate || pending[1].Action != issue.ActionCreate {
+		t.Fatalf("unexpected pending changes %+v", pending)
+	}
+	if pending[0].Source != "mp issue set-status a done" {
+		t.Errorf("expected the source recorded, got %q", pending[0].Source)
+	}
+
+	if _, err := human.Approve(issue.PendingOptions{IDs: []string{"a"}}); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	if status, _ := piece.ParseStatus("issues/a.md", fs); status != piece.StatusDone {
+		t.Errorf("expected the approved status written, got %q", status)
+	}
+	if _, err := human.Reject(issue.PendingOptions{All: true}); err != nil {
+		t.Fatalf("Reject failed: %v", err)
+	}
+	if pending, _ := human.Pending(); len(pending) != 0 {
+		t.Errorf("expected nothing pending, got %+v", pending)
+	}
+	if _, err := fs.Stat("issues/new-thing.md"); err == nil {
+		t.Error("expected the rejected issue not to be created")
+	}
+}
+
+func TestStagingFS_PassesThroughWithoutApproval(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: todo\n---\n"), 0644)
+	staging := issue.NewStagingFS(fs, "")
+
+	if err := piece.UpdateStatus("issues/a.md", piece.StatusDone, staging); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := piece.ParseStatus("issues/a.md", fs); status != piece.StatusDone {
+		t.Errorf("expected the edit applied directly, got %q", status)
+	}
+	if len(staging.Staged()) != 0 {
+		t.Errorf("expected nothing staged, got %v", staging.Staged())
+	}
+}
+
+func TestStagingFS_RevertDropsChange(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupApproval(t, fs)
+	staging := issue.NewStagingFS(fs, "")
+
+	_ = piece.UpdateStatus("issues/a.md", piece.StatusDone, staging)
+	_ = piece.UpdateStatus("issues/a.md", piece.StatusTodo, staging)
+	if err := staging.Remove("issues/a.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := staging.Stat("issues/a.md"); err == nil {
+		t.Error("expected the staged removal to hide the issue")
+	}
+	_ = staging.WriteFile("issues/b.md", []byte("---\ntitle: B\n---\n"), 0644)
+	_ = staging.Remove("issues/b.md")
+
+	pending, _ := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "").Pending()
+	if len(pending) != 1 || pending[0].Action != issue.ActionDelete {
+		t.Errorf("expected only the removal of a pending, got %+v", pending)
+	}
+}
+
+func TestHandler_Approve_Conflict(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupApproval(t, fs)
+	_ = piece.UpdateStatus("issues/a.md", piece.StatusDone, issue.NewStagingFS(fs, ""))
+	// A human edits the issue after the agent's edit was staged
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: in-progress\n---\n"), 0644)
+	human := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	if _, err := human.Approve(issue.PendingOptions{All: true}); err == nil || !strings.Contains(err.Error(), "issues/a.md changed") {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if _, err := human.Approve(issue.PendingOptions{All: true, Force: true}); err != nil {
+		t.Fatalf("Approve --force failed: %v", err)
+	}
+	if status, _ := piece.ParseStatus("issues/a.md", fs); status != piece.StatusDone {
+		t.Errorf("expected the forced edit applied, got %q", status)
+	}
+	if _, err := human.Approve(issue.PendingOptions{}); err == nil {
+		t.Error("expected an error without a selection")
+	}
+}
+
+func TestStagingFS_DefersRenameSideEffects(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	fs := adapters.NewMemoryFS()
+	setupApproval(t, fs)
+
+	// A piece working on the issue
+	worktree := "/data/monkeypuzzle/pieces/a"
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\nworktree "+worktree+"\nHEAD bbb\nbranch refs/heads/a\n\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/a\n"), nil)
+	_ = fs.MkdirAll(worktree, 0755)
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/a", worktree)
+	_ = store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/a.md", IssueName: "A", PieceName: "a"})
+
+	agent := issue.NewHandler(core.Deps{FS: issue.NewStagingFS(fs, "mp issue rename a b"), Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+	human := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+	assertUnmoved := func(when string) {
+		t.Helper()
+		if _, err := fs.Stat(".monkeypuzzle/" + piece.IssueAliasesFilename); err == nil {
+			t.Errorf("expected no alias recorded %s", when)
+		}
+		if marker, _ := store.ReadIssueMarker(); marker.IssuePath != "issues/a.md" {
+			t.Errorf("expected the piece marker unchanged %s, got %+v", when, marker)
+		}
+	}
+
+	result, err := agent.Rename("a", "b")
+	if err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if len(result.Pieces) != 0 {
+		t.Errorf("expected no pieces repointed while staged, got %v", result.Pieces)
+	}
+	assertUnmoved("while staged")
+	pending, _ := human.Pending()
+	var movedTo string
+	for _, change := range pending {
+		if change.Action == issue.ActionDelete {
+			movedTo = change.MovedTo
+		}
+	}
+	if movedTo != "issues/b.md" {
+		t.Errorf("expected the staged delete to record the move, got %+v", pending)
+	}
+
+	if _, err := human.Reject(issue.PendingOptions{All: true}); err != nil {
+		t.Fatalf("Reject failed: %v", err)
+	}
+	assertUnmoved("after rejecting")
+
+	if _, err := agent.Rename("a", "b"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if _, err := human.Approve(issue.PendingOptions{All: true}); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	if got, ok := piece.ResolveIssueAlias("", "issues/a.md", fs); !ok || got != "issues/b.md" {
+		t.Errorf("expected the alias recorded once approved, got %q %v", got, ok)
+	}
+	if marker, _ := store.ReadIssueMarker(); marker.IssuePath != "issues/b.md" {
+		t.Errorf("expected the piece marker repointed once approved, got %+v", marker)
+	}
+}
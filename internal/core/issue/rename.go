@@ -0,0 +1,229 @@
+package issue
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// RenameResult describes an issue renamed with Rename
+type RenameResult struct {
+	From IssueRef `json:"from"`
+	To   IssueRef `json:"to"`
+	// Pieces lists the pieces whose issue marker or PR metadata now names the
+	// new path
+	Pieces []string `json:"pieces,omitempty"`
+	// Issues lists the issues whose parent: or depends_on: references were
+	// updated
+	Issues []string `json:"issues,omitempty"`
+}
+
+// referenceTokenRegex matches a single issue reference in a frontmatter
+// value, e.g. each item of "depends_on: [a, issues/b.md]"
+var referenceTokenRegex = regexp.MustCompile(`[^\s\[\],"']+`)
+
+// Rename moves an issue file to newID.md in the same issues directory and
+// updates what refers to it: the issue markers and PR metadata of pieces,
+// and parent:/depends_on: references in other issues. The old path is
+// recorded as an alias in .monkeypuzzle/issue-aliases.json, so the old ID
+// still resolves. When the move is staged for approval, the alias and piece
+// references are updated once it is approved.
+func (h *Handler) Rename(id, newID string) (RenameResult, error) {
+	newID = strings.TrimSuffix(newID, ".md")
+	if newID == "" || strings.ContainsAny(newID, `/\`) || newID == "." || newID == ".." {
+		return RenameResult{}, fmt.Errorf("invalid issue ID %q: must be a file name without directories", newID)
+	}
+
+	absPath, relPath, err := h.resolveIssue(id)
+	if err != nil {
+		return RenameResult{}, err
+	}
+	newRelPath := filepath.Join(filepath.Dir(relPath), newID+".md")
+	newAbsPath := filepath.Join(h.workDir, newRelPath)
+	if newRelPath == relPath {
+		return RenameResult{}, fmt.Errorf("%s is already called %s", relPath, newID)
+	}
+	if _, err := h.deps.FS.Stat(newAbsPath); err == nil {
+		return RenameResult{}, fmt.Errorf("issue %s already exists", newRelPath)
+	}
+
+	content, err := h.deps.FS.ReadFile(absPath)
+	if err != nil {
+		return RenameResult{}, fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	// Record the alias first: a rename without it would strand the old ID
+	staging, staged := h.staging(absPath)
+	if !staged {
+		if err := h.recordAlias(relPath, newRelPath); err != nil {
+			return RenameResult{}, err
+		}
+	}
+	if content, err = h.moveAssets(relPath, newRelPath, content); err != nil {
+		return RenameResult{}, err
+	}
+	if err := h.deps.FS.WriteFile(newAbsPath, content, defaultFilePerm); err != nil {
+		return RenameResult{}, fmt.Errorf("failed to write %s: %w", newRelPath, err)
+	}
+	if err := h.deps.FS.Remove(absPath); err != nil {
+		return RenameResult{}, fmt.Errorf("failed to remove %s: %w", relPath, err)
+	}
+	if staged {
+		if err := staging.markMoved(absPath, newRelPath); err != nil {
+			return RenameResult{}, fmt.Errorf("failed to stage the rename of %s: %w", relPath, err)
+		}
+	}
+
+	result := RenameResult{
+		From: IssueRef{ID: piece.IssueID(relPath), Path: relPath},
+		To:   IssueRef{ID: newID, Path: newRelPath},
+	}
+
+	result.Issues, err = h.rewriteReferences(relPath, newRelPath)
+	if err != nil {
+		return result, err
+	}
+	if !staged {
+		result.Pieces = h.repointPieces(relPath, newRelPath)
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Renamed %s to %s", relPath, newRelPath),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// repointPieces points the pieces working on the issue at oldPath at
+// newPath, returning their names. The issue has moved and its old ID
+// resolves, so a piece that can't be updated is reported rather than failing.
+func (h *Handler) repointPieces(oldPath, newPath string) []string {
+	pieces, err := piece.NewHandler(h.deps).RewriteIssueReferences(h.workDir, oldPath, newPath)
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to update piece references to %s: %v", oldPath, err),
+		})
+	}
+	return pieces
+}
+
+// staging returns the staging FS when edits to the issue file at absPath are
+// staged for approval instead of applied
+func (h *Handler) staging(absPath string) (*StagingFS, bool) {
+	staging, ok := h.deps.FS.(*StagingFS)
+	if !ok || !staging.Stages(absPath) {
+		return nil, false
+	}
+	return staging, true
+}
+
+// recordAlias records oldPath as an alias of newPath in the issue aliases
+// file, repointing earlier aliases of oldPath
+func (h *Handler) recordAlias(oldPath, newPath string) error {
+	aliases, err := piece.ReadIssueAliases(h.workDir, h.deps.FS)
+	if err != nil {
+		return err
+	}
+	for alias, target := range aliases {
+		if target == oldPath {
+			aliases[alias] = newPath
+		}
+	}
+	aliases[oldPath] = newPath
+	// Renaming an issue back to an old name makes the alias pointless
+	delete(aliases, newPath)
+
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(h.workDir, initcmd.DirName, piece.IssueAliasesFilename)
+	if err := h.deps.FS.WriteFile(path, append(data, '\n'), defaultFilePerm); err != nil {
+		return fmt.Errorf("failed to record the old issue ID in %s: %w", piece.IssueAliasesFilename, err)
+	}
+	return nil
+}
+
+// rewriteReferences replaces references to oldPath in the parent: and
+// depends_on: fields of every issue, keeping their form (ID or path, with or
+// without .md). Returns the issues updated.
+func (h *Handler) rewriteReferences(oldPath, newPath string) ([]string, error) {
+	issues, err := h.List("")
+	if err != nil {
+		return nil, err
+	}
+
+	replacements := map[string]string{
+		oldPath:                            newPath,
+		strings.TrimSuffix(oldPath, ".md"): strings.TrimSuffix(newPath, ".md"),
+		piece.IssueID(oldPath):             piece.IssueID(newPath),
+		piece.IssueID(oldPath) + ".md":     piece.IssueID(newPath) + ".md",
+	}
+
+	var updated []string
+	for _, is := range issues {
+		absPath := filepath.Join(h.workDir, is.Path)
+		content, err := h.deps.FS.ReadFile(absPath)
+		if err != nil {
+			continue
+		}
+		rewritten, changed := rewriteFrontmatterReferences(string(content), replacements)
+		if !changed {
+			continue
+		}
+		if err := h.deps.FS.WriteFile(absPath, []byte(rewritten), defaultFilePerm); err != nil {
+			return updated, fmt.Errorf("failed to update %s: %w", is.Path, err)
+		}
+		updated = append(updated, is.Path)
+	}
+	return updated, nil
+}
+
+// rewriteFrontmatterReferences applies replacements to the references in the
+// reference fields of content's frontmatter, including YAML list items
+// below the field
+func rewriteFrontmatterReferences(content string, replacements map[string]string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return content, false
+	}
+
+	changed := false
+	inReference := false
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+		value := line
+		if m := frontmatterKeyRegex.FindStringSubmatch(line); m != nil {
+			inReference = slices.Contains(referenceFields, strings.ToLower(m[1]))
+			value = m[2]
+		} else if !strings.HasPrefix(strings.TrimSpace(line), "-") {
+			inReference = false
+		}
+		if !inReference {
+			continue
+		}
+
+		rewritten := referenceTokenRegex.ReplaceAllStringFunc(value, func(ref string) string {
+			if replacement, ok := replacements[filepath.Clean(ref)]; ok && ref != "-" {
+				return replacement
+			}
+			return ref
+		})
+		if rewritten != value {
+			lines[i] = line[:len(line)-len(value)] + rewritten
+			changed = true
+		}
+	}
+	return strings.Join(lines, "\n"), changed
+}
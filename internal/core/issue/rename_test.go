@@ -0,0 +1,100 @@
+package issue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_Rename(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/add-login.md", []byte("---\ntitle: Add login\nstatus: in-progress\n---\n"), 0644)
+	_ = fs.WriteFile("issues/child.md", []byte("---\ntitle: Child\nstatus: todo\nparent: issues/add-login.md\ndepends_on:\n  - add-login\n  - other\n---\nMentions add-login.\n"), 0644)
+
+	// A piece working on the issue, with a PR
+	worktree := "/data/monkeypuzzle/pieces/add-login"
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\nworktree "+worktree+"\nHEAD bbb\nbranch refs/heads/add-login\n\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/add-login\n"), nil)
+	_ = fs.MkdirAll(worktree, 0755)
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/add-login", worktree)
+	_ = store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/add-login.md", IssueName: "Add login", PieceName: "add-login"})
+	_ = store.WritePRMetadata(piece.PRMetadata{PRNumber: 7, IssuePath: "issues/add-login.md"})
+
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+	result, err := handler.Rename("add-login", "add-sso-login")
+	if err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if result.To.Path != "issues/add-sso-login.md" || len(result.Pieces) != 1 || len(result.Issues) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	if _, err := fs.Stat("issues/add-login.md"); err == nil {
+		t.Error("expected the old file to be gone")
+	}
+	marker, _ := store.ReadIssueMarker()
+	metadata, _ := store.ReadPRMetadata()
+	if marker.IssuePath != "issues/add-sso-login.md" || metadata.IssuePath != "issues/add-sso-login.md" {
+		t.Errorf("expected piece references to be updated, got %+v and %+v", marker, metadata)
+	}
+
+	child, _ := fs.ReadFile("issues/child.md")
+	for _, want := range []string{"parent: issues/add-sso-login.md", "  - add-sso-login\n", "  - other\n", "Mentions add-login."} {
+		if !strings.Contains(string(child), want) {
+			t.Errorf("expected %q in the child issue, got:\n%s", want, child)
+		}
+	}
+
+	// The old ID resolves through the tracked alias, even without the index
+	_ = fs.Remove(".monkeypuzzle/" + issue.IndexFilename)
+	ref, err := handler.Resolve("add-login")
+	if err != nil || ref.Path != "issues/add-sso-login.md" {
+		t.Errorf("expected the old ID to resolve to the new path, got %+v (%v)", ref, err)
+	}
+	if got, ok := piece.ResolveIssueAlias("", "issues/add-login.md", fs); !ok || got != "issues/add-sso-login.md" {
+		t.Errorf("expected the old path to be an alias, got %q %v", got, ok)
+	}
+}
+
+func TestHandler_Rename_TargetExists(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("issues/b.md", []byte("---\ntitle: B\nstatus: todo\n---\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}, "")
+
+	if _, err := handler.Rename("a", "b"); err == nil {
+		t.Error("expected renaming onto an existing issue to fail")
+	}
+	if _, err := handler.Rename("a", "sub/b"); err == nil {
+		t.Error("expected a new ID with a directory to be rejected")
+	}
+}
+
+func TestHandler_Rename_UnreadableAliases(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile(".monkeypuzzle/"+piece.IssueAliasesFilename, []byte("{not json"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}, "")
+
+	if _, err := handler.Rename("a", "b"); err == nil || !strings.Contains(err.Error(), piece.IssueAliasesFilename) {
+		t.Fatalf("expected an aliases file that can't be parsed to fail the rename, got %v", err)
+	}
+	if data, _ := fs.ReadFile(".monkeypuzzle/" + piece.IssueAliasesFilename); string(data) != "{not json" {
+		t.Errorf("expected the aliases file to be left alone, got %q", data)
+	}
+	if _, err := fs.Stat("issues/a.md"); err != nil {
+		t.Error("expected the issue not to be moved")
+	}
+}
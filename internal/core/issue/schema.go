@@ -0,0 +1,155 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// issueFields returns the custom frontmatter fields configured in
+// issues.fields
+func (h *Handler) issueFields() map[string]initcmd.IssueField {
+	cfg, err := piece.ReadConfig(h.workDir, h.deps.FS)
+	if err != nil {
+		return nil
+	}
+	return cfg.Issues.Fields
+}
+
+// fieldProblem is a frontmatter field not matching its definition
+type fieldProblem struct {
+	message string
+	// field and value are what a fix sets: the default of a missing
+	// required field. value is empty when there is no fix.
+	field string
+	value string
+}
+
+// checkFields validates the frontmatter of content against the custom field
+// definitions
+func checkFields(content string, defs map[string]initcmd.IssueField) []fieldProblem {
+	if len(defs) == 0 {
+		return nil
+	}
+	fields := piece.ExtractFields(content)
+	var problems []fieldProblem
+	for _, name := range (initcmd.IssueConfig{Fields: defs}).FieldNames() {
+		def := defs[name]
+		value := fields[name]
+		if value == "" {
+			if def.Required {
+				problems = append(problems, fieldProblem{message: "missing required field " + name, field: name, value: def.Default})
+			}
+			continue
+		}
+		if err := def.Check(value); err != nil {
+			problems = append(problems, fieldProblem{message: fmt.Sprintf("%s: %v", name, err)})
+		}
+	}
+	return problems
+}
+
+// withFields gives input the defaults of the custom fields it leaves unset
+// and checks its fields against their definitions
+func withFields(input Input, defs map[string]initcmd.IssueField) (Input, error) {
+	fields := map[string]string{}
+	for name, value := range input.Fields {
+		if value = strings.TrimSpace(value); value != "" {
+			fields[name] = value
+		}
+	}
+	for name, def := range defs {
+		if _, ok := fields[name]; !ok && def.Default != "" {
+			fields[name] = def.Default
+		}
+	}
+
+	var errs []string
+	for name := range fields {
+		if slices.Contains([]string{"title", "status", "description", "parent"}, strings.ToLower(name)) {
+			errs = append(errs, fmt.Sprintf("field %s is set by mp", name))
+		}
+	}
+	for _, problem := range checkFields(frontmatterOf(fields, defs), defs) {
+		errs = append(errs, problem.message)
+	}
+	if len(errs) > 0 {
+		slices.Sort(errs)
+		return Input{}, fmt.Errorf("validation failed: %v", errs)
+	}
+
+	if len(fields) > 0 {
+		input.Fields = fields
+	}
+	return input, nil
+}
+
+// frontmatterOf returns frontmatter holding fields, formatted for defs
+func frontmatterOf(fields map[string]string, defs map[string]initcmd.IssueField) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	writeFields(&b, fields, defs)
+	b.WriteString("---\n")
+	return b.String()
+}
+
+// writeFields writes fields as frontmatter lines sorted by name, list fields
+// and labels as [a, b]
+func writeFields(b *strings.Builder, fields map[string]string, defs map[string]initcmd.IssueField) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		def := defs[name]
+		if name == "labels" && def.Type == "" {
+			def.Type = initcmd.FieldList
+		}
+		fmt.Fprintf(b, "%s: %s\n", name, fieldValue(def, fields[name]))
+	}
+}
+
+// fieldValue formats a value of a field for the frontmatter
+func fieldValue(def initcmd.IssueField, value string) string {
+	if def.Type == initcmd.FieldList {
+		items := initcmd.SplitList(value)
+		for i, item := range items {
+			items[i] = escapeYAMLString(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return escapeYAMLString(value)
+}
+
+// Columns returns the value of each column for each issue: a field of the
+// issue's listing (id, title, status, labels, tasks.percent, ...) or any
+// frontmatter field. List values are joined with ", ".
+func (h *Handler) Columns(issues []IssueSummary, columns []string) [][]string {
+	rows := make([][]string, 0, len(issues))
+	for _, summary := range issues {
+		listed, _ := filter.JSONFields(summary)
+		var frontmatter map[string]string
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			if values, ok := listed[strings.ToLower(column)]; ok {
+				row[i] = strings.Join(values, ", ")
+				continue
+			}
+			if frontmatter == nil {
+				frontmatter = map[string]string{}
+				if content, err := h.deps.FS.ReadFile(filepath.Join(h.workDir, summary.Path)); err == nil {
+					frontmatter = piece.ExtractFields(string(content))
+				}
+			}
+			row[i] = frontmatter[column]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
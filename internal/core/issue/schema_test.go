@@ -0,0 +1,117 @@
+package issue_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+// setupFields configures custom issue fields
+func setupFields(t *testing.T, fs *adapters.MemoryFS) {
+	t.Helper()
+	cfg := initcmd.Config{
+		Version: "1",
+		Issues: initcmd.IssueConfig{
+			Provider: "markdown",
+			Config:   map[string]string{"directory": "issues"},
+			Fields: map[string]initcmd.IssueField{
+				"priority":   {Values: []string{"low", "high"}, Required: true, Default: "low"},
+				"estimate":   {Type: initcmd.FieldNumber},
+				"components": {Type: initcmd.FieldList, Values: []string{"api", "db"}},
+				"team":       {Required: true},
+			},
+		},
+	}
+	data, _ := json.Marshal(cfg)
+	_ = fs.MkdirAll(".monkeypuzzle", 0755)
+	_ = fs.WriteFile(".monkeypuzzle/monkeypuzzle.json", data, 0644)
+	_ = fs.MkdirAll("issues", 0755)
+}
+
+func TestHandler_Run_CustomFields(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupFields(t, fs)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	_, err := handler.Run(issue.Input{Title: "Fix API", Fields: map[string]string{"estimate": "soon"}})
+	if err == nil || !strings.Contains(err.Error(), "missing required field team") || !strings.Contains(err.Error(), `"soon" is not a number`) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	file, err := handler.Run(issue.Input{Title: "Fix API", Fields: map[string]string{"team": "core", "components": "api,db"}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	content, _ := fs.ReadFile(file.Path)
+	for _, want := range []string{"components: [api, db]\n", "priority: low\n", "team: core\n"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in\n%s", want, content)
+		}
+	}
+}
+
+func TestHandler_Lint_CustomFields(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupFields(t, fs)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: todo\nteam: core\ncomponents: [api, ui]\n---\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	report, err := handler.Lint(issue.LintOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if got := strings.Join(problemChecks(report), ","); got != "issues/a.md custom-field,issues/a.md custom-field (fixed)" {
+		t.Errorf("unexpected problems %q", got)
+	}
+	content, _ := fs.ReadFile("issues/a.md")
+	if !strings.Contains(string(content), "priority: low\n") {
+		t.Errorf("expected the required field's default added, got\n%s", content)
+	}
+}
+
+func TestHandler_Columns(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupFields(t, fs)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: todo\nlabels: [bug, ui]\npriority: high\n---\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}, "")
+
+	issues, err := handler.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := handler.Columns(issues, []string{"id", "labels", "priority", "missing"})
+	if got := strings.Join(rows[0], "|"); got != "a|bug, ui|high|" {
+		t.Errorf("unexpected row %q", got)
+	}
+}
+
+func TestHandler_Run_Labels(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	// Without labels.json any label goes
+	if _, err := handler.Run(issue.Input{Title: "Anything", Fields: map[string]string{"labels": "whatever"}}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	_ = fs.WriteFile(".monkeypuzzle/labels.json", []byte(`{"labels": [{"name": "bug"}, {"name": "infra"}]}`), 0644)
+	_, err := handler.Run(issue.Input{Title: "Fix API", Fields: map[string]string{"labels": "bug,ui"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown label(s) ui") {
+		t.Fatalf("expected the unknown label rejected, got %v", err)
+	}
+
+	file, err := handler.Run(issue.Input{Title: "Fix API", Fields: map[string]string{"labels": "Bug, infra"}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	content, _ := fs.ReadFile(file.Path)
+	if !strings.Contains(string(content), "labels: [bug, infra]\n") {
+		t.Errorf("expected labels as defined, got\n%s", content)
+	}
+}
@@ -0,0 +1,117 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
+)
+
+// splitMarker prefixes the child reference appended to a split task
+const splitMarker = "(split into "
+
+// SplitResult describes child issues created from a parent's task list
+type SplitResult struct {
+	Parent   string      `json:"parent"`
+	Children []IssueFile `json:"children"`
+}
+
+// ParseTaskNumbers parses a comma-separated list of 1-based task numbers, e.g. "2,4"
+func ParseTaskNumbers(s string) ([]int, error) {
+	var numbers []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid task number %q", part)
+		}
+		if !seen[n] {
+			seen[n] = true
+			numbers = append(numbers, n)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("no task numbers given")
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// Split creates a child issue for each selected task of an issue. Children get a
+// parent: frontmatter link, and each split task in the parent references its child.
+// If a child or the parent can't be written, the children created are removed.
+func (h *Handler) Split(id string, numbers []int) (SplitResult, error) {
+	absPath, relPath, err := h.resolveIssue(id)
+	if err != nil {
+		return SplitResult{}, err
+	}
+
+	content, err := h.deps.FS.ReadFile(absPath)
+	if err != nil {
+		return SplitResult{}, fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	// Validate the whole selection before writing anything
+	tasks := ParseTasks(string(content))
+	selected := make([]Task, 0, len(numbers))
+	for _, n := range numbers {
+		if n < 1 || n > len(tasks) {
+			return SplitResult{}, fmt.Errorf("task %d not found (issue has %d tasks)", n, len(tasks))
+		}
+		if strings.Contains(tasks[n-1].Text, splitMarker) {
+			return SplitResult{}, fmt.Errorf("task %d was already split", n)
+		}
+		selected = append(selected, tasks[n-1])
+	}
+
+	// Children stay in the parent's issues directory, i.e. with the same team
+	result := SplitResult{Parent: relPath}
+	dir := h.directoryOf(relPath)
+	lines := strings.Split(string(content), "\n")
+	op := operation.New(h.deps.Output, "split "+relPath)
+	for _, task := range selected {
+		child, err := h.create(Input{
+			Title:       task.Text,
+			Description: fmt.Sprintf("Split from %s", relPath),
+		}, relPath, dir)
+		if err != nil {
+			return SplitResult{}, op.Fail(fmt.Errorf("failed to create issue for task %d: %w", task.Number, err))
+		}
+		childPath := filepath.Join(h.workDir, child.Path)
+		op.Undo("remove issue "+child.Path, func() error { return h.deps.FS.Remove(childPath) })
+		result.Children = append(result.Children, child)
+
+		link := childLink(relPath, child.Path)
+		lines[task.line] = strings.TrimRight(lines[task.line], " \r") +
+			fmt.Sprintf(" %s[%s](%s))", splitMarker, child.Filename, link)
+	}
+
+	if err := h.deps.FS.WriteFile(absPath, []byte(strings.Join(lines, "\n")), defaultFilePerm); err != nil {
+		return SplitResult{}, op.Fail(fmt.Errorf("failed to update parent issue: %w", err))
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Split %d tasks from %s", len(result.Children), relPath),
+		Data:    result,
+	})
+
+	return result, nil
+}
+
+// childLink returns the markdown link target of a child issue relative to its parent
+func childLink(parentPath, childPath string) string {
+	link, err := filepath.Rel(filepath.Dir(parentPath), childPath)
+	if err != nil {
+		return childPath
+	}
+	return filepath.ToSlash(link)
+}
@@ -0,0 +1,99 @@
+package issue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+func TestParseTaskNumbers(t *testing.T) {
+	numbers, err := issue.ParseTaskNumbers("4, 2,4")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(numbers) != 2 || numbers[0] != 2 || numbers[1] != 4 {
+		t.Errorf("expected [2 4], got %v", numbers)
+	}
+
+	for _, bad := range []string{"", "0", "two", "1,-3"} {
+		if _, err := issue.ParseTaskNumbers(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestHandler_Split(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/big-feature.md", []byte(tasksIssue), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	result, err := handler.Split("big-feature", []int{1, 3})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Parent != "issues/big-feature.md" || len(result.Children) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	child, err := fs.ReadFile("issues/design-the-api.md")
+	if err != nil {
+		t.Fatalf("expected child issue to be created: %v", err)
+	}
+	if !strings.Contains(string(child), "parent: issues/big-feature.md") {
+		t.Errorf("expected parent link in child frontmatter, got:\n%s", child)
+	}
+
+	parent, _ := fs.ReadFile("issues/big-feature.md")
+	if !strings.Contains(string(parent), "- [ ] Design the API (split into [design-the-api.md](design-the-api.md))") {
+		t.Errorf("expected parent task to reference child, got:\n%s", parent)
+	}
+	if !strings.Contains(string(parent), "* [X] Nested item (split into [nested-item.md](nested-item.md))") {
+		t.Errorf("expected nested task to reference child, got:\n%s", parent)
+	}
+
+	if _, err := handler.Split("big-feature", []int{1}); err == nil {
+		t.Error("expected error when splitting an already split task")
+	}
+}
+
+func TestHandler_Split_InvalidTaskWritesNothing(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/big-feature.md", []byte(tasksIssue), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	if _, err := handler.Split("big-feature", []int{1, 9}); err == nil {
+		t.Fatal("expected error for unknown task")
+	}
+	if _, err := fs.ReadFile("issues/design-the-api.md"); err == nil {
+		t.Error("expected no child issue to be created")
+	}
+}
+
+func TestHandler_Split_RollsBackOnFailure(t *testing.T) {
+	mem := adapters.NewMemoryFS()
+	setupConfig(t, mem)
+	_ = mem.MkdirAll("issues", 0755)
+	_ = mem.WriteFile("issues/big-feature.md", []byte(tasksIssue), 0644)
+	fs := failingFS{MemoryFS: mem, failPath: "issues/big-feature.md"}
+	out := adapters.NewBufferOutput()
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: out}, "")
+
+	if _, err := handler.Split("big-feature", []int{1, 3}); err == nil || !strings.Contains(err.Error(), "failed to update parent issue") {
+		t.Fatalf("expected the parent update to fail, got %v", err)
+	}
+	for _, child := range []string{"issues/design-the-api.md", "issues/nested-item.md"} {
+		if _, err := mem.Stat(child); err == nil {
+			t.Errorf("expected %s to be removed", child)
+		}
+	}
+	if !out.HasWarning() {
+		t.Error("expected the rollback to be reported")
+	}
+}
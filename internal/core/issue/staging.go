@@ -0,0 +1,267 @@
+package issue
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// StageEnv, when set, makes mp stage its edits to issue files for approval
+// in repositories with issues.require_approval. mp-mcp sets it for the mp
+// commands its tools run.
+const StageEnv = "MP_STAGE_ISSUE_EDITS"
+
+// StagingFS stages writes and removals of issue files in repositories with
+// issues.require_approval as pending changes, instead of applying them.
+// Reads see the staged content, so a command and the ones after it work on
+// the edited backlog until a human approves or rejects it. Other files pass
+// through to the wrapped FS.
+type StagingFS struct {
+	core.FS
+	source string
+	now    func() time.Time
+	repos  map[string]stagingRepo
+	staged []string
+}
+
+// stagingRepo is the issue configuration of a repository
+type stagingRepo struct {
+	enabled bool
+	dirs    []string
+}
+
+// NewStagingFS wraps fs, recording source (the command making the edits) on
+// the changes it stages
+func NewStagingFS(fs core.FS, source string) *StagingFS {
+	return &StagingFS{FS: fs, source: source, now: time.Now, repos: map[string]stagingRepo{}}
+}
+
+// Staged returns the paths of the issues with changes staged through s,
+// relative to their repositories
+func (s *StagingFS) Staged() []string {
+	return s.staged
+}
+
+// target returns the repository root and relative path of name when it is
+// an issue file of a repository requiring approval
+func (s *StagingFS) target(name string) (root, relPath string, ok bool) {
+	if !strings.HasSuffix(name, ".md") {
+		return "", "", false
+	}
+	root, repo, ok := s.repo(filepath.Dir(name))
+	if !ok {
+		return "", "", false
+	}
+	relPath, err := filepath.Rel(root, filepath.Clean(name))
+	if err != nil || !inIssueDirs(filepath.Dir(relPath), repo.dirs) {
+		return "", "", false
+	}
+	return root, relPath, true
+}
+
+// repo returns the root and issue configuration of the repository containing
+// dir, when it requires approval
+func (s *StagingFS) repo(dir string) (string, stagingRepo, bool) {
+	root, ok := initcmd.FindRepoConfig(s.FS, dir)
+	if !ok {
+		return "", stagingRepo{}, false
+	}
+	repo, ok := s.repos[root]
+	if !ok {
+		if cfg, err := piece.ReadConfig(root, s.FS); err == nil {
+			repo = stagingRepo{enabled: cfg.Issues.RequireApproval, dirs: cfg.Issues.Dirs()}
+		}
+		s.repos[root] = repo
+	}
+	return root, repo, repo.enabled
+}
+
+// inIssueDirs reports whether dir is one of dirs or below one
+func inIssueDirs(dir string, dirs []string) bool {
+	return slices.ContainsFunc(dirs, func(issuesDir string) bool {
+		return dir == issuesDir || strings.HasPrefix(dir, issuesDir+string(filepath.Separator))
+	})
+}
+
+// pending returns the staged change to the issue at relPath, if any
+func (s *StagingFS) pending(root, relPath string) (PendingChange, bool) {
+	change, err := readPendingFile(s.FS, pendingPath(root, relPath))
+	return change, err == nil
+}
+
+func (s *StagingFS) ReadFile(name string) ([]byte, error) {
+	if root, relPath, ok := s.target(name); ok {
+		if change, ok := s.pending(root, relPath); ok {
+			if change.Action == ActionDelete {
+				return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+			}
+			return []byte(change.Content), nil
+		}
+	}
+	return s.FS.ReadFile(name)
+}
+
+func (s *StagingFS) Stat(name string) (fs.FileInfo, error) {
+	if root, relPath, ok := s.target(name); ok {
+		if change, ok := s.pending(root, relPath); ok {
+			if change.Action == ActionDelete {
+				return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+			}
+			return stagedInfo{change}, nil
+		}
+	}
+	return s.FS.Stat(name)
+}
+
+// ReadDir lists issues directories as they are with the staged changes applied
+func (s *StagingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := s.FS.ReadDir(name)
+	root, repo, ok := s.repo(name)
+	if !ok {
+		return entries, err
+	}
+	relDir, relErr := filepath.Rel(root, filepath.Clean(name))
+	if relErr != nil || !inIssueDirs(relDir, repo.dirs) {
+		return entries, err
+	}
+	staged, stagedErr := s.FS.ReadDir(filepath.Join(pendingRoot(root), relDir))
+	if stagedErr != nil {
+		return entries, err
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	byName := map[string]fs.DirEntry{}
+	for _, entry := range entries {
+		byName[entry.Name()] = entry
+	}
+	for _, entry := range staged {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md.json") {
+			continue
+		}
+		change, ok := s.pending(root, filepath.Join(relDir, strings.TrimSuffix(entry.Name(), ".json")))
+		if !ok {
+			continue
+		}
+		issueName := filepath.Base(change.Path)
+		if change.Action == ActionDelete {
+			delete(byName, issueName)
+			continue
+		}
+		byName[issueName] = fs.FileInfoToDirEntry(stagedInfo{change})
+	}
+
+	merged := make([]fs.DirEntry, 0, len(byName))
+	for _, entry := range byName {
+		merged = append(merged, entry)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name() < merged[j].Name() })
+	return merged, nil
+}
+
+func (s *StagingFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if root, relPath, ok := s.target(name); ok {
+		return s.stage(root, relPath, name, data, true)
+	}
+	return s.FS.WriteFile(name, data, perm)
+}
+
+func (s *StagingFS) Remove(name string) error {
+	if root, relPath, ok := s.target(name); ok {
+		return s.stage(root, relPath, name, nil, false)
+	}
+	return s.FS.Remove(name)
+}
+
+// stage records writing data to the issue at relPath, or removing it when
+// write is false, on top of any change already staged. A change undone
+// (a new issue removed, or an issue written back as it was) is dropped.
+func (s *StagingFS) stage(root, relPath, name string, data []byte, write bool) error {
+	change, staged := s.pending(root, relPath)
+	if !staged {
+		change = PendingChange{IssueRef: IssueRef{ID: piece.IssueID(relPath), Path: relPath}, Action: ActionCreate}
+		if current, err := s.FS.ReadFile(name); err == nil {
+			change.Action = ActionUpdate
+			change.Base = contentHash(current, true)
+		} else if !write {
+			return s.FS.Remove(name)
+		}
+	}
+
+	undone := false
+	switch {
+	case !write && change.Base == "":
+		undone = true
+	case !write:
+		change.Action = ActionDelete
+		change.Content = ""
+	case change.Base != "" && contentHash(data, true) == change.Base:
+		undone = true
+	default:
+		change.Action = ActionUpdate
+		if change.Base == "" {
+			change.Action = ActionCreate
+		}
+		change.Content = string(data)
+		change.MovedTo = ""
+	}
+
+	if undone {
+		s.staged = slices.DeleteFunc(s.staged, func(p string) bool { return p == relPath })
+		if !staged {
+			return nil
+		}
+		return s.FS.Remove(pendingPath(root, relPath))
+	}
+	if !slices.Contains(s.staged, relPath) {
+		s.staged = append(s.staged, relPath)
+	}
+	change.Source = s.source
+	change.StagedAt = s.now()
+	return writePendingFile(s.FS, root, change)
+}
+
+// Stages reports whether edits to the issue file name are staged instead of
+// applied
+func (s *StagingFS) Stages(name string) bool {
+	_, _, ok := s.target(name)
+	return ok
+}
+
+// markMoved records on the staged delete of the issue file name that the
+// issue moved to newPath (relative to the repository), so approving the
+// delete records the alias and repoints pieces
+func (s *StagingFS) markMoved(name, newPath string) error {
+	root, relPath, ok := s.target(name)
+	if !ok {
+		return nil
+	}
+	change, ok := s.pending(root, relPath)
+	if !ok || change.Action != ActionDelete {
+		return nil
+	}
+	change.MovedTo = newPath
+	return writePendingFile(s.FS, root, change)
+}
+
+// stagedInfo describes an issue file as staged
+type stagedInfo struct {
+	change PendingChange
+}
+
+func (i stagedInfo) Name() string       { return filepath.Base(i.change.Path) }
+func (i stagedInfo) Size() int64        { return int64(len(i.change.Content)) }
+func (i stagedInfo) Mode() fs.FileMode  { return defaultFilePerm }
+func (i stagedInfo) ModTime() time.Time { return i.change.StagedAt }
+func (i stagedInfo) IsDir() bool        { return false }
+func (i stagedInfo) Sys() any           { return nil }
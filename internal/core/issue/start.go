@@ -0,0 +1,29 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// StartPiece creates an issue titled title and a piece from it, as
+// CreatePieceFromIssueWithOptions does, for ad-hoc work. The handler's
+// working directory must be the repository root. The issue is removed again
+// if the piece can't be created.
+func (h *Handler) StartPiece(title string, opts piece.CreateOptions) (piece.PieceInfo, error) {
+	created, err := h.Run(Input{Title: title})
+	if err != nil {
+		return piece.PieceInfo{}, fmt.Errorf("failed to create issue: %w", err)
+	}
+	issuePath := filepath.Join(h.workDir, created.Path)
+	op := operation.New(h.deps.Output, "start piece for new issue "+created.Path)
+	op.Undo("remove issue "+created.Path, func() error { return h.deps.FS.Remove(issuePath) })
+
+	info, err := piece.NewHandler(h.deps).CreatePieceFromIssueWithOptions(opts, issuePath)
+	if err != nil {
+		return piece.PieceInfo{}, op.Fail(err)
+	}
+	return info, nil
+}
@@ -0,0 +1,69 @@
+package issue_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// setupStart returns a handler for repo /repo whose piece worktree for
+// add-login is created by the mocked git unless worktreeErr is set
+func setupStart(t *testing.T, worktreeErr error) (*adapters.MemoryFS, *adapters.BufferOutput, *issue.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/data")
+	fs := adapters.NewMemoryFS()
+	_ = fs.MkdirAll("/repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(`{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}},"pr":{"provider":"github"}}`), 0644)
+	_ = fs.MkdirAll("/repo/issues", 0755)
+
+	worktree := "/data/monkeypuzzle/pieces/add-login"
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-common-dir"}, []byte("/repo/.git\n"), nil)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nHEAD aaa111\nbranch refs/heads/main\n\n"), nil)
+	mockExec.AddResponse("git", []string{"worktree", "add", worktree}, nil, worktreeErr)
+	mockExec.AddResponse("tmux", []string{"new-session", "-d", "-s", "mp-piece-add-login", "-c", worktree}, nil, nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/add-login\n"), nil)
+
+	out := adapters.NewBufferOutput()
+	return fs, out, issue.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec}, "/repo")
+}
+
+func TestHandler_StartPiece(t *testing.T) {
+	fs, _, handler := setupStart(t, nil)
+
+	info, err := handler.StartPiece("Add login", piece.CreateOptions{})
+	if err != nil {
+		t.Fatalf("StartPiece failed: %v", err)
+	}
+	if info.Name != "add-login" || info.IssuePath != "issues/add-login.md" {
+		t.Errorf("expected piece add-login for issues/add-login.md, got %+v", info)
+	}
+	content, err := fs.ReadFile("/repo/issues/add-login.md")
+	if err != nil {
+		t.Fatalf("expected the issue to be created: %v", err)
+	}
+	if !strings.Contains(string(content), "title: Add login") {
+		t.Errorf("expected the issue title, got:\n%s", content)
+	}
+}
+
+func TestHandler_StartPiece_RemovesIssueOnFailure(t *testing.T) {
+	fs, out, handler := setupStart(t, errors.New("fatal: worktree add failed"))
+
+	if _, err := handler.StartPiece("Add login", piece.CreateOptions{}); err == nil {
+		t.Fatal("expected an error when the piece can't be created")
+	}
+	if _, err := fs.Stat("/repo/issues/add-login.md"); err == nil {
+		t.Error("expected the new issue to be removed")
+	}
+	if !out.HasWarning() {
+		t.Error("expected the rollback to be reported")
+	}
+}
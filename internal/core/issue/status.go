@@ -0,0 +1,132 @@
+package issue
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// SetStatusOptions selects the issues SetStatus changes: those matching
+// Filter, plus those named in IDs
+type SetStatusOptions struct {
+	Filter *filter.Expr
+	IDs    []string
+	// DryRun reports the changes without writing them
+	DryRun bool
+}
+
+// StatusChange is an issue whose status SetStatus changes
+type StatusChange struct {
+	IssueRef
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SetStatusResult describes a batch status change
+type SetStatusResult struct {
+	Status  string         `json:"status"`
+	DryRun  bool           `json:"dry_run,omitempty"`
+	Changed []StatusChange `json:"changed"`
+	// Unchanged counts selected issues that already had the status
+	Unchanged int `json:"unchanged"`
+}
+
+// SetStatus sets the status of every selected issue in one pass: every issue
+// is resolved and read before any is written, and if a write fails the
+// issues already written are restored.
+func (h *Handler) SetStatus(status string, opts SetStatusOptions) (SetStatusResult, error) {
+	if !piece.ValidateStatus(status) {
+		return SetStatusResult{}, fmt.Errorf("invalid status: %q (valid: %s, %s, %s)", status, piece.StatusTodo, piece.StatusInProgress, piece.StatusDone)
+	}
+	if opts.Filter == nil && len(opts.IDs) == 0 {
+		return SetStatusResult{}, fmt.Errorf("no issues selected; give issue IDs or a filter")
+	}
+
+	selected, err := h.selectIssues(opts)
+	if err != nil {
+		return SetStatusResult{}, err
+	}
+
+	result := SetStatusResult{Status: status, DryRun: opts.DryRun, Changed: []StatusChange{}}
+	originals := map[string][]byte{}
+	for _, ref := range selected {
+		absPath := filepath.Join(h.workDir, ref.Path)
+		content, err := h.deps.FS.ReadFile(absPath)
+		if err != nil {
+			return SetStatusResult{}, fmt.Errorf("failed to read %s: %w", ref.Path, err)
+		}
+		current, err := piece.ParseStatus(absPath, h.deps.FS)
+		if err != nil {
+			return SetStatusResult{}, err
+		}
+		if current == status {
+			result.Unchanged++
+			continue
+		}
+		originals[ref.Path] = content
+		result.Changed = append(result.Changed, StatusChange{IssueRef: ref, From: current, To: status})
+	}
+	if opts.DryRun || len(result.Changed) == 0 {
+		return result, nil
+	}
+
+	op := operation.New(h.deps.Output, fmt.Sprintf("set status of %d issues to %s", len(result.Changed), status))
+	for _, change := range result.Changed {
+		absPath := filepath.Join(h.workDir, change.Path)
+		original := originals[change.Path]
+		if err := piece.UpdateStatus(absPath, status, h.deps.FS); err != nil {
+			return SetStatusResult{}, op.Fail(fmt.Errorf("failed to update %s: %w", change.Path, err))
+		}
+		op.Undo("restore "+change.Path, func() error {
+			return h.deps.FS.WriteFile(absPath, original, defaultFilePerm)
+		})
+	}
+
+	paths := make([]string, len(result.Changed))
+	for i, change := range result.Changed {
+		paths[i] = change.Path
+	}
+	piece.NewHandler(h.deps).SyncIssueTitles(h.workDir, paths...)
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Set %d issues to %s", len(result.Changed), status),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// selectIssues returns the issues matching opts.Filter and those named in
+// opts.IDs, each once, in that order
+func (h *Handler) selectIssues(opts SetStatusOptions) ([]IssueRef, error) {
+	var selected []IssueRef
+	seen := map[string]bool{}
+	add := func(ref IssueRef) {
+		if !seen[ref.Path] {
+			seen[ref.Path] = true
+			selected = append(selected, ref)
+		}
+	}
+
+	if opts.Filter != nil {
+		issues, err := h.ListWithOptions(ListOptions{Filter: opts.Filter})
+		if err != nil {
+			return nil, err
+		}
+		for _, is := range issues {
+			add(IssueRef{ID: is.ID, Path: is.Path})
+		}
+	}
+	for _, id := range opts.IDs {
+		ref, err := h.Resolve(id)
+		if err != nil {
+			return nil, err
+		}
+		add(ref)
+	}
+	return selected, nil
+}
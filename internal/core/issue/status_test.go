@@ -0,0 +1,136 @@
+package issue_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// failingFS fails writes to one file
+type failingFS struct {
+	*adapters.MemoryFS
+	failPath string
+}
+
+func (f failingFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if name == f.failPath {
+		return errors.New("disk full")
+	}
+	return f.MemoryFS.WriteFile(name, data, perm)
+}
+
+func setupStatusIssues(t *testing.T, fs *adapters.MemoryFS) {
+	t.Helper()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/a.md", []byte("---\ntitle: A\nstatus: in-progress\nlabels: [bug]\n---\n"), 0644)
+	_ = fs.WriteFile("issues/b.md", []byte("---\ntitle: B\nstatus: in-progress\nlabels: [bug]\n---\n"), 0644)
+	_ = fs.WriteFile("issues/c.md", []byte("---\ntitle: C\nstatus: in-progress\n---\n"), 0644)
+	_ = fs.WriteFile("issues/d.md", []byte("---\ntitle: D\nstatus: done\nlabels: [bug]\n---\n"), 0644)
+}
+
+func TestHandler_SetStatus_RetitlesPieceSessions(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	fs := adapters.NewMemoryFS()
+	setupStatusIssues(t, fs)
+
+	// A piece working on issue a, with its session running
+	worktree := "/data/monkeypuzzle/pieces/a"
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nHEAD aaa\nbranch refs/heads/main\n\nworktree "+worktree+"\nHEAD bbb\nbranch refs/heads/a\n\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/a\n"), nil)
+	mockExec.AddResponse("tmux", []string{"has-session", "-t", "=mp-piece-a"}, nil, nil)
+	mockExec.AddResponse("tmux", []string{"rename-window", "-t", "=mp-piece-a:^", "A [done]"}, nil, nil)
+	_ = fs.MkdirAll(worktree, 0755)
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/a", worktree)
+	_ = store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/a.md", IssueName: "A", PieceName: "a"})
+
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec}, "")
+	if _, err := handler.SetStatus(piece.StatusDone, issue.SetStatusOptions{IDs: []string{"a"}}); err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	if !mockExec.WasCalled("tmux", "rename-window", "-t", "=mp-piece-a:^", "A [done]") {
+		t.Error("expected the session of the piece working on the issue to be retitled")
+	}
+}
+
+func TestHandler_SetStatus(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupStatusIssues(t, fs)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()}, "")
+	expr, err := filter.Parse("label=bug")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := handler.SetStatus(piece.StatusDone, issue.SetStatusOptions{Filter: expr, DryRun: true})
+	if err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	if len(preview.Changed) != 2 || preview.Unchanged != 1 || preview.Changed[0].From != piece.StatusInProgress {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	if status, _ := piece.ParseStatus("issues/a.md", fs); status != piece.StatusInProgress {
+		t.Error("expected a dry run to write nothing")
+	}
+
+	result, err := handler.SetStatus(piece.StatusDone, issue.SetStatusOptions{Filter: expr, IDs: []string{"c", "a"}})
+	if err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	if len(result.Changed) != 3 {
+		t.Errorf("expected a, b and c to change once each, got %+v", result)
+	}
+	for _, path := range []string{"issues/a.md", "issues/b.md", "issues/c.md"} {
+		if status, _ := piece.ParseStatus(path, fs); status != piece.StatusDone {
+			t.Errorf("expected %s to be done, got %s", path, status)
+		}
+	}
+}
+
+func TestHandler_SetStatus_RestoresOnFailure(t *testing.T) {
+	mem := adapters.NewMemoryFS()
+	setupStatusIssues(t, mem)
+	out := adapters.NewBufferOutput()
+	handler := issue.NewHandler(core.Deps{FS: failingFS{mem, "issues/b.md"}, Output: out, Exec: adapters.NewMockExec()}, "")
+
+	_, err := handler.SetStatus(piece.StatusTodo, issue.SetStatusOptions{IDs: []string{"a", "b", "c"}})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected the failed write to be reported, got %v", err)
+	}
+	if status, _ := piece.ParseStatus("issues/a.md", mem); status != piece.StatusInProgress {
+		t.Errorf("expected a to be restored, got %s", status)
+	}
+	if status, _ := piece.ParseStatus("issues/c.md", mem); status != piece.StatusInProgress {
+		t.Errorf("expected c to be untouched, got %s", status)
+	}
+	if !out.HasWarning() {
+		t.Error("expected the rollback to be reported")
+	}
+}
+
+func TestHandler_SetStatus_Invalid(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupStatusIssues(t, fs)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	if _, err := handler.SetStatus("finished", issue.SetStatusOptions{IDs: []string{"a"}}); err == nil {
+		t.Error("expected an invalid status to be rejected")
+	}
+	if _, err := handler.SetStatus(piece.StatusDone, issue.SetStatusOptions{}); err == nil {
+		t.Error("expected an empty selection to be rejected")
+	}
+	if _, err := handler.SetStatus(piece.StatusDone, issue.SetStatusOptions{IDs: []string{"a", "missing"}}); err == nil {
+		t.Error("expected an unknown issue to be rejected")
+	}
+	if status, _ := piece.ParseStatus("issues/a.md", fs); status != piece.StatusInProgress {
+		t.Error("expected nothing to be written when an issue is unknown")
+	}
+}
@@ -0,0 +1,169 @@
+package issue
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// taskRegex matches a GitHub-style task list item: "- [ ] text" or "- [x] text"
+var taskRegex = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+)(.*)$`)
+
+// Task is a task list item in an issue body
+type Task struct {
+	// Number is the 1-based position of the task in the issue
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+	Done   bool   `json:"done"`
+
+	line int
+}
+
+// TaskSummary summarizes task list completion
+type TaskSummary struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Percent int `json:"percent"`
+}
+
+// TaskList is the task list of an issue
+type TaskList struct {
+	ID      string      `json:"id"`
+	Path    string      `json:"path"`
+	Tasks   []Task      `json:"tasks"`
+	Summary TaskSummary `json:"summary"`
+}
+
+// ParseTasks extracts task list items from issue content, skipping the
+// frontmatter and fenced code blocks.
+func ParseTasks(content string) []Task {
+	var tasks []Task
+	lines := strings.Split(content, "\n")
+	inFence := false
+
+	for i := bodyStart(lines); i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		matches := taskRegex.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if matches == nil {
+			continue
+		}
+		tasks = append(tasks, Task{
+			Number: len(tasks) + 1,
+			Text:   strings.TrimSpace(matches[4]),
+			Done:   matches[2] != " ",
+			line:   i,
+		})
+	}
+
+	return tasks
+}
+
+// Summarize returns completion counts for a task list
+func Summarize(tasks []Task) TaskSummary {
+	summary := TaskSummary{Total: len(tasks)}
+	for _, t := range tasks {
+		if t.Done {
+			summary.Done++
+		}
+	}
+	if summary.Total > 0 {
+		summary.Percent = summary.Done * 100 / summary.Total
+	}
+	return summary
+}
+
+// ToggleTask flips the checkbox of task n (1-based) and returns the updated
+// content and task.
+func ToggleTask(content string, n int) (string, Task, error) {
+	tasks := ParseTasks(content)
+	if n < 1 || n > len(tasks) {
+		return "", Task{}, fmt.Errorf("task %d not found (issue has %d tasks)", n, len(tasks))
+	}
+
+	task := tasks[n-1]
+	task.Done = !task.Done
+	mark := " "
+	if task.Done {
+		mark = "x"
+	}
+
+	lines := strings.Split(content, "\n")
+	lines[task.line] = taskRegex.ReplaceAllString(lines[task.line], "${1}"+mark+"${3}${4}")
+	return strings.Join(lines, "\n"), task, nil
+}
+
+// bodyStart returns the index of the first line after the YAML frontmatter
+func bodyStart(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Tasks returns the task list of an issue
+func (h *Handler) Tasks(id string) (TaskList, error) {
+	absPath, relPath, err := h.resolveIssue(id)
+	if err != nil {
+		return TaskList{}, err
+	}
+
+	content, err := h.deps.FS.ReadFile(absPath)
+	if err != nil {
+		return TaskList{}, fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	tasks := ParseTasks(string(content))
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	return TaskList{ID: piece.IssueID(relPath), Path: relPath, Tasks: tasks, Summary: Summarize(tasks)}, nil
+}
+
+// CheckTask toggles task n (1-based) of an issue
+func (h *Handler) CheckTask(id string, n int) (Task, error) {
+	absPath, relPath, err := h.resolveIssue(id)
+	if err != nil {
+		return Task{}, err
+	}
+
+	content, err := h.deps.FS.ReadFile(absPath)
+	if err != nil {
+		return Task{}, fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	updated, task, err := ToggleTask(string(content), n)
+	if err != nil {
+		return Task{}, err
+	}
+
+	if err := h.deps.FS.WriteFile(absPath, []byte(updated), defaultFilePerm); err != nil {
+		return Task{}, fmt.Errorf("failed to write issue file: %w", err)
+	}
+
+	state := "unchecked"
+	if task.Done {
+		state = "checked"
+	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Task %d %s in %s: %s", task.Number, state, relPath, task.Text),
+		Data:    task,
+	})
+
+	return task, nil
+}
@@ -0,0 +1,145 @@
+package issue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+const tasksIssue = `---
+title: Big Feature
+status: in-progress
+---
+
+# Big Feature
+
+- [ ] Design the API
+- [x] Write the parser
+  * [X] Nested item
+- not a task
+
+` + "```" + `
+- [ ] inside a code block
+` + "```" + `
+`
+
+func TestParseTasks(t *testing.T) {
+	tasks := issue.ParseTasks(tasksIssue)
+
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d: %+v", len(tasks), tasks)
+	}
+
+	expected := []struct {
+		text string
+		done bool
+	}{
+		{"Design the API", false},
+		{"Write the parser", true},
+		{"Nested item", true},
+	}
+	for i, want := range expected {
+		if tasks[i].Number != i+1 || tasks[i].Text != want.text || tasks[i].Done != want.done {
+			t.Errorf("task %d: expected %+v, got %+v", i+1, want, tasks[i])
+		}
+	}
+
+	summary := issue.Summarize(tasks)
+	if summary.Total != 3 || summary.Done != 2 || summary.Percent != 66 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestToggleTask(t *testing.T) {
+	updated, task, err := issue.ToggleTask(tasksIssue, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !task.Done {
+		t.Error("expected task 1 to be checked")
+	}
+	if !strings.Contains(updated, "- [x] Design the API") {
+		t.Errorf("expected checkbox to be checked, got:\n%s", updated)
+	}
+
+	updated, task, err = issue.ToggleTask(updated, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if task.Done || !strings.Contains(updated, "  * [ ] Nested item") {
+		t.Errorf("expected nested task to be unchecked, got:\n%s", updated)
+	}
+
+	if _, _, err := issue.ToggleTask(tasksIssue, 4); err == nil {
+		t.Error("expected error for out of range task")
+	}
+}
+
+func TestHandler_TasksAndCheckTask(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/big-feature.md", []byte(tasksIssue), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	list, err := handler.Tasks("big-feature")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if list.Path != "issues/big-feature.md" || len(list.Tasks) != 3 {
+		t.Fatalf("unexpected task list: %+v", list)
+	}
+
+	if _, err := handler.CheckTask("issues/big-feature.md", 1); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	list, _ = handler.Tasks("big-feature.md")
+	if list.Summary.Done != 3 || list.Summary.Percent != 100 {
+		t.Errorf("expected all tasks done, got %+v", list.Summary)
+	}
+
+	if _, err := handler.Tasks("missing"); err == nil {
+		t.Error("expected error for unknown issue")
+	}
+}
+
+func TestHandler_List(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	setupConfig(t, fs)
+	_ = fs.MkdirAll("issues", 0755)
+	_ = fs.WriteFile("issues/big-feature.md", []byte(tasksIssue), 0644)
+	_ = fs.WriteFile("issues/small-fix.md", []byte("---\ntitle: Small Fix\nstatus: todo\npr_number: 12\npr_url: \"https://github.com/o/r/pull/12\"\n---\n"), 0644)
+	handler := issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	issues, err := handler.List("")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].Title != "Big Feature" || issues[0].Tasks == nil || issues[0].Tasks.Percent != 66 {
+		t.Errorf("expected task completion on first issue, got %+v", issues[0])
+	}
+	if issues[1].Tasks != nil {
+		t.Errorf("expected no task summary for issue without tasks, got %+v", issues[1].Tasks)
+	}
+	if issues[1].PRNumber != 12 || issues[1].PRURL != "https://github.com/o/r/pull/12" {
+		t.Errorf("expected PR link from frontmatter, got %+v", issues[1])
+	}
+
+	todo, err := handler.List("todo")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(todo) != 1 || todo[0].Path != "issues/small-fix.md" {
+		t.Errorf("expected only todo issue, got %+v", todo)
+	}
+
+	if _, err := handler.List("blocked"); err == nil {
+		t.Error("expected error for invalid status filter")
+	}
+}
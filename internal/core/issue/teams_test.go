@@ -0,0 +1,130 @@
+package issue_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+// setupTeams configures issues/backend and issues/frontend with one issue each
+func setupTeams(t *testing.T) (*adapters.MemoryFS, *issue.Handler) {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	cfg := initcmd.Config{
+		Version: "1",
+		Issues: initcmd.IssueConfig{
+			Provider:    "markdown",
+			Config:      map[string]string{},
+			Directories: []string{"issues/backend", "issues/frontend"},
+		},
+	}
+	data, _ := json.Marshal(cfg)
+	_ = fs.MkdirAll(".monkeypuzzle", 0755)
+	_ = fs.WriteFile(".monkeypuzzle/monkeypuzzle.json", data, 0644)
+	_ = fs.MkdirAll("issues/backend", 0755)
+	_ = fs.MkdirAll("issues/frontend", 0755)
+	_ = fs.WriteFile("issues/backend/api.md", []byte("---\ntitle: API\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("issues/frontend/theme.md", []byte("---\ntitle: Theme\nstatus: done\n---\n"), 0644)
+
+	return fs, issue.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+}
+
+func TestHandler_List_SpansDirectories(t *testing.T) {
+	_, handler := setupTeams(t)
+
+	issues, err := handler.List("")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected issues from both directories, got %+v", issues)
+	}
+	if issues[0].Team != "backend" || issues[1].Team != "frontend" {
+		t.Errorf("expected teams backend and frontend, got %q and %q", issues[0].Team, issues[1].Team)
+	}
+
+	backend, err := handler.ListWithOptions(issue.ListOptions{Team: "backend"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(backend) != 1 || backend[0].Path != "issues/backend/api.md" {
+		t.Errorf("expected only the backend issue, got %+v", backend)
+	}
+
+	if _, err := handler.ListWithOptions(issue.ListOptions{Team: "mobile"}); err == nil {
+		t.Error("expected error for an unknown team")
+	}
+}
+
+func TestHandler_RunInDir(t *testing.T) {
+	fs, handler := setupTeams(t)
+
+	result, err := handler.RunInDir(issue.Input{Title: "Dark mode"}, "frontend")
+	if err != nil {
+		t.Fatalf("RunInDir failed: %v", err)
+	}
+	if result.Path != "issues/frontend/dark-mode.md" {
+		t.Errorf("expected issue in issues/frontend, got %s", result.Path)
+	}
+	if _, err := fs.ReadFile("issues/frontend/dark-mode.md"); err != nil {
+		t.Errorf("expected issue file: %v", err)
+	}
+
+	result, err = handler.Run(issue.Input{Title: "Rate limits"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Path != "issues/backend/rate-limits.md" {
+		t.Errorf("expected default in the first directory, got %s", result.Path)
+	}
+}
+
+func TestHandler_Resolve_AcrossDirectories(t *testing.T) {
+	fs, handler := setupTeams(t)
+
+	ref, err := handler.Resolve("theme")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if ref.Path != "issues/frontend/theme.md" {
+		t.Errorf("expected frontend issue, got %s", ref.Path)
+	}
+
+	_ = fs.WriteFile("issues/backend/theme.md", []byte("---\ntitle: Theme API\nstatus: todo\n---\n"), 0644)
+	if _, err := handler.Resolve("theme"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected ambiguous ID error, got %v", err)
+	}
+	if ref, err := handler.Resolve("issues/backend/theme.md"); err != nil || ref.ID != "theme" {
+		t.Errorf("expected path to resolve, got %+v, %v", ref, err)
+	}
+}
+
+func TestHandler_List_Owners(t *testing.T) {
+	fs, handler := setupTeams(t)
+	_ = fs.WriteFile(".monkeypuzzle/owners", []byte("issues/backend/ @backend\nlabel:ui @design\n"), 0644)
+	_ = fs.WriteFile("issues/frontend/theme.md", []byte("---\ntitle: Theme\nstatus: done\nlabels:\n  - ui\n---\n"), 0644)
+
+	issues, err := handler.List("")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Owners[0] != "@backend" || issues[1].Owners[0] != "@design" {
+		t.Fatalf("expected owners by path and label, got %+v", issues)
+	}
+	if len(issues[1].Labels) != 1 || issues[1].Labels[0] != "ui" {
+		t.Errorf("expected labels from frontmatter, got %v", issues[1].Labels)
+	}
+
+	mine, err := handler.ListWithOptions(issue.ListOptions{Owner: "design"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(mine) != 1 || mine[0].Path != "issues/frontend/theme.md" {
+		t.Errorf("expected only the design-owned issue, got %+v", mine)
+	}
+}
@@ -0,0 +1,227 @@
+// Package labels manages the label taxonomy of a repository: the labels
+// issues may carry, defined with their colors and descriptions in
+// .monkeypuzzle/labels.json.
+package labels
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// Filename is the label taxonomy in .monkeypuzzle
+const Filename = "labels.json"
+
+// Label is a label issues may carry
+type Label struct {
+	Name string `json:"name"`
+	// Color is a hex RGB color without the #, as GitHub takes it, e.g. "d73a4a"
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+	// GitHub is the repository label PRs get for this label, when it is
+	// named differently there
+	GitHub string `json:"github,omitempty"`
+}
+
+// GitHubName returns the name of the label on GitHub
+func (l Label) GitHubName() string {
+	if l.GitHub != "" {
+		return l.GitHub
+	}
+	return l.Name
+}
+
+// Taxonomy is the contents of labels.json. An empty taxonomy allows any label.
+type Taxonomy struct {
+	Labels []Label `json:"labels"`
+}
+
+// colorRegex matches a hex RGB color, with or without the leading #
+var colorRegex = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+
+// Parse decodes and validates a labels file
+func Parse(data []byte) (*Taxonomy, error) {
+	var t Taxonomy
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", Filename, err)
+	}
+	seen := map[string]bool{}
+	for i, l := range t.Labels {
+		if err := validate(l); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", Filename, err)
+		}
+		key := strings.ToLower(l.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("invalid %s: label %q is defined twice", Filename, l.Name)
+		}
+		seen[key] = true
+		t.Labels[i].Color = strings.ToLower(strings.TrimPrefix(l.Color, "#"))
+	}
+	return &t, nil
+}
+
+// validate checks a single label definition
+func validate(l Label) error {
+	if strings.TrimSpace(l.Name) == "" {
+		return fmt.Errorf("label without a name")
+	}
+	if strings.ContainsAny(l.Name, ",[]\"'\n") {
+		return fmt.Errorf("label %q: names cannot contain commas, brackets or quotes", l.Name)
+	}
+	if l.Color != "" && !colorRegex.MatchString(l.Color) {
+		return fmt.Errorf("label %q: color %q is not a hex color such as d73a4a", l.Name, l.Color)
+	}
+	return nil
+}
+
+// Path returns the labels file of the repository at repoRoot
+func Path(repoRoot string) string {
+	return filepath.Join(repoRoot, initcmd.DirName, Filename)
+}
+
+// Load reads the label taxonomy of the repository at repoRoot. A missing
+// file yields an empty taxonomy.
+func Load(fs core.FS, repoRoot string) (*Taxonomy, error) {
+	data, err := fs.ReadFile(Path(repoRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Taxonomy{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", Filename, err)
+	}
+	return Parse(data)
+}
+
+// Defined reports whether the taxonomy defines any labels, and so restricts
+// the labels issues may carry
+func (t *Taxonomy) Defined() bool {
+	return len(t.Labels) > 0
+}
+
+// Find returns the label named name, compared case-insensitively
+func (t *Taxonomy) Find(name string) (Label, bool) {
+	for _, l := range t.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return l, true
+		}
+	}
+	return Label{}, false
+}
+
+// Unknown returns the names the taxonomy does not define, in order. Every
+// name is known to an empty taxonomy.
+func (t *Taxonomy) Unknown(names []string) []string {
+	if !t.Defined() {
+		return nil
+	}
+	var unknown []string
+	for _, name := range names {
+		if _, ok := t.Find(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// Names returns the names of the labels, in file order
+func (t *Taxonomy) Names() []string {
+	names := make([]string, len(t.Labels))
+	for i, l := range t.Labels {
+		names[i] = l.Name
+	}
+	return names
+}
+
+// Check returns an error naming the labels the taxonomy does not define
+func (t *Taxonomy) Check(names []string) error {
+	unknown := t.Unknown(names)
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unknown label(s) %s (defined in %s: %s)",
+		strings.Join(unknown, ", "), filepath.Join(initcmd.DirName, Filename), strings.Join(t.Names(), ", "))
+}
+
+// GitHubLabels returns the repository labels a PR for an issue carrying
+// names gets: the GitHub names of its labels that exist in repoLabels,
+// spelled as the repository spells them
+func (t *Taxonomy) GitHubLabels(names, repoLabels []string) []string {
+	var result []string
+	for _, name := range names {
+		if l, ok := t.Find(name); ok {
+			name = l.GitHubName()
+		}
+		i := slices.IndexFunc(repoLabels, func(r string) bool { return strings.EqualFold(r, name) })
+		if i >= 0 && !slices.Contains(result, repoLabels[i]) {
+			result = append(result, repoLabels[i])
+		}
+	}
+	return result
+}
+
+// Handler executes the labels commands
+type Handler struct {
+	deps    core.Deps
+	workDir string
+}
+
+// NewHandler creates a new labels handler for the repository at workDir
+func NewHandler(deps core.Deps, workDir string) *Handler {
+	return &Handler{deps: deps, workDir: workDir}
+}
+
+// List returns the labels of the taxonomy
+func (h *Handler) List() ([]Label, error) {
+	t, err := Load(h.deps.FS, h.workDir)
+	if err != nil {
+		return nil, err
+	}
+	if t.Labels == nil {
+		return []Label{}, nil
+	}
+	return t.Labels, nil
+}
+
+// Add defines a label, creating labels.json if needed. A label already
+// defined is an error.
+func (h *Handler) Add(label Label) (Label, error) {
+	label.Name = strings.TrimSpace(label.Name)
+	if err := validate(label); err != nil {
+		return Label{}, err
+	}
+	label.Color = strings.ToLower(strings.TrimPrefix(label.Color, "#"))
+
+	t, err := Load(h.deps.FS, h.workDir)
+	if err != nil {
+		return Label{}, err
+	}
+	if existing, ok := t.Find(label.Name); ok {
+		return Label{}, fmt.Errorf("label %q is already defined", existing.Name)
+	}
+	t.Labels = append(t.Labels, label)
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return Label{}, err
+	}
+	path := Path(h.workDir)
+	if err := h.deps.FS.MkdirAll(filepath.Dir(path), initcmd.DefaultDirPerm); err != nil {
+		return Label{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := h.deps.FS.WriteFile(path, append(data, '\n'), initcmd.DefaultFilePerm); err != nil {
+		return Label{}, fmt.Errorf("failed to write %s: %w", Filename, err)
+	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Added label %s to %s", label.Name, filepath.Join(initcmd.DirName, Filename)),
+		Data:    label,
+	})
+	return label, nil
+}
@@ -0,0 +1,96 @@
+package labels_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/labels"
+)
+
+const testLabels = `{
+  "labels": [
+    {"name": "bug", "color": "#D73A4A", "description": "Something is broken"},
+    {"name": "infra", "github": "area: infrastructure"}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	taxonomy, err := labels.Parse([]byte(testLabels))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := taxonomy.Labels[0].Color; got != "d73a4a" {
+		t.Errorf("expected color normalized to d73a4a, got %q", got)
+	}
+
+	for _, data := range []string{
+		`{"labels": [{"name": ""}]}`,
+		`{"labels": [{"name": "bug", "color": "red"}]}`,
+		`{"labels": [{"name": "bug"}, {"name": "Bug"}]}`,
+		`{"labels": [{"name": "a,b"}]}`,
+		`not json`,
+	} {
+		if _, err := labels.Parse([]byte(data)); err == nil {
+			t.Errorf("expected %s to be invalid", data)
+		}
+	}
+}
+
+func TestTaxonomy_Check(t *testing.T) {
+	taxonomy, _ := labels.Parse([]byte(testLabels))
+	if err := taxonomy.Check([]string{"Bug", "infra"}); err != nil {
+		t.Errorf("expected defined labels to pass, got %v", err)
+	}
+	err := taxonomy.Check([]string{"bug", "ui", "docs"})
+	if err == nil || !strings.Contains(err.Error(), "unknown label(s) ui, docs") {
+		t.Errorf("expected the unknown labels named, got %v", err)
+	}
+
+	if err := (&labels.Taxonomy{}).Check([]string{"anything"}); err != nil {
+		t.Errorf("expected an empty taxonomy to allow any label, got %v", err)
+	}
+}
+
+func TestTaxonomy_GitHubLabels(t *testing.T) {
+	taxonomy, _ := labels.Parse([]byte(testLabels))
+	got := taxonomy.GitHubLabels([]string{"bug", "infra", "ui"}, []string{"Bug", "area: infrastructure", "enhancement"})
+	want := []string{"Bug", "area: infrastructure"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GitHubLabels = %v, want %v", got, want)
+	}
+}
+
+func TestHandler_Add(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	handler := labels.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()}, "")
+
+	list, err := handler.List()
+	if err != nil || len(list) != 0 {
+		t.Fatalf("expected no labels without labels.json, got %v, %v", list, err)
+	}
+
+	if _, err := handler.Add(labels.Label{Name: "bug", Color: "#D73A4A", Description: "Something is broken"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := handler.Add(labels.Label{Name: "infra"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := handler.Add(labels.Label{Name: "Bug"}); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("expected a duplicate label to fail, got %v", err)
+	}
+	if _, err := handler.Add(labels.Label{Name: "ui", Color: "blue"}); err == nil {
+		t.Error("expected an invalid color to fail")
+	}
+
+	list, err = handler.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	want := []labels.Label{{Name: "bug", Color: "d73a4a", Description: "Something is broken"}, {Name: "infra"}}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("List = %+v, want %+v", list, want)
+	}
+}
@@ -0,0 +1,187 @@
+// Package mcpconfig registers mp-mcp with MCP clients, writing the server
+// entry that points them at the mp-mcp and mp binaries by absolute path.
+package mcpconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Supported clients
+const (
+	// ClientClaudeDesktop is the Claude Desktop app, configured in
+	// claude_desktop_config.json
+	ClientClaudeDesktop = "claude-desktop"
+	// ClientGeneric is any client taking the common mcpServers JSON; its
+	// config file is given explicitly
+	ClientGeneric = "generic"
+)
+
+// Clients lists the supported clients
+var Clients = []string{ClientClaudeDesktop, ClientGeneric}
+
+// DefaultName is the name the server is registered under
+const DefaultName = "monkeypuzzle"
+
+// MPFlag is the mp-mcp flag naming the mp binary it runs
+const MPFlag = "--mp"
+
+// Server is an MCP client's entry for a stdio server
+type Server struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// Options configures an install
+type Options struct {
+	Client string
+	// Name is the server's name in the client config; empty means DefaultName
+	Name string
+	// ServerPath and MPPath are the absolute paths of mp-mcp and mp
+	ServerPath string
+	MPPath     string
+	// ConfigPath is the client config file; empty means the client's default.
+	// A generic client without one only prints its entry.
+	ConfigPath string
+	// DryRun reports the entry without writing the config
+	DryRun bool
+}
+
+// Result is the outcome of an install
+type Result struct {
+	Client     string `json:"client"`
+	Name       string `json:"name"`
+	ConfigPath string `json:"config_path,omitempty"`
+	// Written is whether the config file was written
+	Written bool `json:"written"`
+	// Replaced is whether an entry of the same name was replaced
+	Replaced bool   `json:"replaced,omitempty"`
+	Server   Server `json:"server"`
+	// Config is the mcpServers snippet for configuring a client by hand
+	Config map[string]map[string]Server `json:"config"`
+}
+
+// Handler executes the mcp install command
+type Handler struct {
+	deps core.Deps
+}
+
+// NewHandler creates a new mcp install handler with dependencies
+func NewHandler(deps core.Deps) *Handler {
+	return &Handler{deps: deps}
+}
+
+// DefaultConfigPath returns where client keeps its config, or "" for a
+// client without a default
+func DefaultConfigPath(client string) (string, error) {
+	switch client {
+	case ClientClaudeDesktop:
+		// ~/Library/Application Support on macOS, %AppData% on Windows,
+		// $XDG_CONFIG_HOME or ~/.config elsewhere
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "Claude", "claude_desktop_config.json"), nil
+	case ClientGeneric:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported client %q (supported: %s)", client, strings.Join(Clients, ", "))
+	}
+}
+
+// Install adds mp-mcp to the client config under opts.Name, keeping the
+// config's other servers and settings
+func (h *Handler) Install(opts Options) (Result, error) {
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	for _, path := range []string{opts.ServerPath, opts.MPPath} {
+		if !filepath.IsAbs(path) {
+			return Result{}, fmt.Errorf("binary path %q is not absolute", path)
+		}
+	}
+	if opts.ConfigPath == "" {
+		path, err := DefaultConfigPath(opts.Client)
+		if err != nil {
+			return Result{}, err
+		}
+		opts.ConfigPath = path
+	}
+
+	server := Server{Command: opts.ServerPath, Args: []string{MPFlag, opts.MPPath}}
+	result := Result{
+		Client:     opts.Client,
+		Name:       opts.Name,
+		ConfigPath: opts.ConfigPath,
+		Server:     server,
+		Config:     map[string]map[string]Server{"mcpServers": {opts.Name: server}},
+	}
+	if opts.ConfigPath == "" {
+		h.deps.Output.Write(core.Message{Type: core.MsgInfo, Content: "Add the config below to your MCP client", Data: result})
+		return result, nil
+	}
+
+	config, replaced, err := h.merge(opts.ConfigPath, opts.Name, server)
+	if err != nil {
+		return Result{}, err
+	}
+	result.Replaced = replaced
+	if opts.DryRun {
+		h.deps.Output.Write(core.Message{Type: core.MsgInfo, Content: fmt.Sprintf("Would add %s to %s", opts.Name, opts.ConfigPath), Data: result})
+		return result, nil
+	}
+
+	if err := h.deps.FS.MkdirAll(filepath.Dir(opts.ConfigPath), 0755); err != nil {
+		return Result{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(opts.ConfigPath), err)
+	}
+	if err := h.deps.FS.WriteFile(opts.ConfigPath, config, 0644); err != nil {
+		return Result{}, fmt.Errorf("failed to write %s: %w", opts.ConfigPath, err)
+	}
+	result.Written = true
+	h.deps.Output.Write(core.Message{Type: core.MsgSuccess, Content: fmt.Sprintf("Added %s to %s; restart the client to load it", opts.Name, opts.ConfigPath), Data: result})
+	return result, nil
+}
+
+// merge returns the config at path with server set under mcpServers.name,
+// and whether it replaced an entry. A missing config starts empty; an
+// invalid one is an error rather than overwritten.
+func (h *Handler) merge(path, name string, server Server) ([]byte, bool, error) {
+	config := map[string]json.RawMessage{}
+	if data, err := h.deps.FS.ReadFile(path); err == nil {
+		if len(strings.TrimSpace(string(data))) > 0 {
+			if err := json.Unmarshal(data, &config); err != nil {
+				return nil, false, fmt.Errorf("invalid JSON in %s: %w", path, err)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	servers := map[string]json.RawMessage{}
+	if raw, ok := config["mcpServers"]; ok {
+		if err := json.Unmarshal(raw, &servers); err != nil {
+			return nil, false, fmt.Errorf("invalid mcpServers in %s: %w", path, err)
+		}
+	}
+	_, replaced := servers[name]
+	entry, err := json.Marshal(server)
+	if err != nil {
+		return nil, false, err
+	}
+	servers[name] = entry
+	if config["mcpServers"], err = json.Marshal(servers); err != nil {
+		return nil, false, err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, false, err
+	}
+	return append(data, '\n'), replaced, nil
+}
@@ -0,0 +1,110 @@
+package mcpconfig_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/mcpconfig"
+)
+
+func setup() (*adapters.MemoryFS, *mcpconfig.Handler) {
+	fs := adapters.NewMemoryFS()
+	return fs, mcpconfig.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()})
+}
+
+func options(configPath string) mcpconfig.Options {
+	return mcpconfig.Options{
+		Client:     mcpconfig.ClientClaudeDesktop,
+		ServerPath: "/usr/local/bin/mp-mcp",
+		MPPath:     "/usr/local/bin/mp",
+		ConfigPath: configPath,
+	}
+}
+
+func TestHandler_Install_KeepsOtherSettings(t *testing.T) {
+	fs, handler := setup()
+	_ = fs.WriteFile("/config.json", []byte(`{"theme":"dark","mcpServers":{"other":{"command":"other"},"monkeypuzzle":{"command":"mp-mcp"}}}`), 0644)
+
+	result, err := handler.Install(options("/config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Written || !result.Replaced {
+		t.Errorf("expected the old entry to be replaced, got %+v", result)
+	}
+
+	data, _ := fs.ReadFile("/config.json")
+	var config struct {
+		Theme      string                      `json:"theme"`
+		MCPServers map[string]mcpconfig.Server `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("invalid config written: %v\n%s", err, data)
+	}
+	if config.Theme != "dark" || config.MCPServers["other"].Command != "other" {
+		t.Errorf("expected other settings to be kept, got %s", data)
+	}
+	server := config.MCPServers["monkeypuzzle"]
+	if server.Command != "/usr/local/bin/mp-mcp" || strings.Join(server.Args, " ") != "--mp /usr/local/bin/mp" {
+		t.Errorf("unexpected server entry %+v", server)
+	}
+}
+
+func TestHandler_Install_NewConfig(t *testing.T) {
+	fs, handler := setup()
+	if _, err := handler.Install(options("/home/me/.config/Claude/claude_desktop_config.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ReadFile("/home/me/.config/Claude/claude_desktop_config.json"); err != nil {
+		t.Errorf("expected the config to be created: %v", err)
+	}
+}
+
+func TestHandler_Install_DryRun(t *testing.T) {
+	fs, handler := setup()
+	opts := options("/config.json")
+	opts.DryRun = true
+	result, err := handler.Install(opts)
+	if err != nil || result.Written {
+		t.Fatalf("expected nothing to be written, got %+v (%v)", result, err)
+	}
+	if _, err := fs.ReadFile("/config.json"); err == nil {
+		t.Error("expected no config file")
+	}
+}
+
+func TestHandler_Install_InvalidConfig(t *testing.T) {
+	fs, handler := setup()
+	_ = fs.WriteFile("/config.json", []byte(`{"mcpServers": `), 0644)
+	if _, err := handler.Install(options("/config.json")); err == nil {
+		t.Fatal("expected an invalid config to be refused")
+	}
+	if data, _ := fs.ReadFile("/config.json"); string(data) != `{"mcpServers": ` {
+		t.Errorf("expected the invalid config to be left alone, got %s", data)
+	}
+}
+
+func TestHandler_Install_GenericPrintsOnly(t *testing.T) {
+	_, handler := setup()
+	opts := options("")
+	opts.Client = mcpconfig.ClientGeneric
+	result, err := handler.Install(opts)
+	if err != nil || result.Written || result.ConfigPath != "" {
+		t.Fatalf("expected only the entry, got %+v (%v)", result, err)
+	}
+	if result.Config["mcpServers"]["monkeypuzzle"].Command != "/usr/local/bin/mp-mcp" {
+		t.Errorf("unexpected config snippet %+v", result.Config)
+	}
+}
+
+func TestHandler_Install_RelativePath(t *testing.T) {
+	_, handler := setup()
+	opts := options("/config.json")
+	opts.MPPath = "mp"
+	if _, err := handler.Install(opts); err == nil {
+		t.Fatal("expected a relative binary path to be refused")
+	}
+}
@@ -0,0 +1,204 @@
+// Package messages is the catalog of user-facing strings, keyed by stable IDs.
+// Error IDs double as the "code" of --json-errors output. English is built in;
+// a translation replaces any subset of the messages.
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Message IDs. IDs of errors are their codes; ".hint" IDs are the "how to
+// fix" text of the error with the same prefix.
+const (
+	HowToFix = "how_to_fix"
+
+	GHNotAuthenticatedHint  = "gh_not_authenticated.hint"
+	TmuxNotInstalledHint    = "tmux_not_installed.hint"
+	MainBranchMissingHint   = "main_branch_missing.hint"
+	DetachedHead            = "detached_head"
+	DetachedHeadHint        = "detached_head.hint"
+	DirtyWorktreeHint       = "dirty_worktree.hint"
+	PieceLocked             = "piece_locked"
+	PieceLockedHint         = "piece_locked.hint"
+	NotInPiece              = "not_in_piece"
+	NotInPieceHint          = "not_in_piece.hint"
+	NotInRepo               = "not_in_repo"
+	NotInRepoHint           = "not_in_repo.hint"
+	OperationInProgress     = "operation_in_progress"
+	OperationInProgressHint = "operation_in_progress.hint"
+	NestedRepo              = "nested_repo"
+	NestedRepoHint          = "nested_repo.hint"
+	BranchExists            = "branch_exists"
+	BranchExistsHint        = "branch_exists.hint"
+	ProtectedBranch         = "protected_branch"
+	ProtectedBranchHint     = "protected_branch.hint"
+)
+
+// English is the built-in catalog. Messages are fmt format strings.
+var English = Catalog{
+	HowToFix: "How to fix:",
+
+	GHNotAuthenticatedHint:  "Run `gh auth login` (or set GH_TOKEN), then check with `gh auth status`.",
+	TmuxNotInstalledHint:    "Install tmux (e.g. `brew install tmux` or `sudo apt install tmux`) and make sure it is on your PATH.",
+	MainBranchMissingHint:   "Branch %q does not exist. List branches with `git branch -a` and pass the right one with --main-branch (e.g. --main-branch master).",
+	DetachedHead:            "HEAD is detached in %s",
+	DetachedHeadHint:        "Check out a branch in %s with `git switch <branch>` (or `git switch -c <branch>` to keep the current commits).",
+	DirtyWorktreeHint:       "Commit or stash the changes in %s (see `git -C %s status`), then retry.",
+	PieceLocked:             "piece %s is locked",
+	PieceLockedHint:         "The piece was locked with `mp piece lock`. Run `mp piece unlock %s` if it is safe to remove.",
+	NotInPiece:              "not in a piece worktree - run this command from within a piece",
+	NotInPieceHint:          "Change to a piece worktree, e.g. `cd \"$(mp piece open --print-path <name>)\"`. Commands that take a piece name also accept it as an argument.",
+	NotInRepo:               "not in a git repository",
+	NotInRepoHint:           "Run the command inside a git repository set up with `mp init`.",
+	OperationInProgress:     "a %s is in progress in %s",
+	OperationInProgressHint: "Finish the %s in %s, or abandon it with `git -C %s %s --abort`, then retry.",
+	NestedRepo:              "%s is a repository nested inside the piece worktree %s",
+	NestedRepoHint:          "Run the command from %s itself, or pass --allow-nested-repo (or set MP_ALLOW_NESTED_REPO=1) to act on %s anyway.",
+	BranchExists:            "branch %s already exists",
+	BranchExistsHint:        "Continue work on it as a piece with 'mp piece adopt %s', choose another name with --name, or delete it with 'git branch -D %s'.",
+	ProtectedBranch:         "%s is protected on GitHub and does not accept direct pushes",
+	ProtectedBranchHint:     "Changes reach %s through pull requests: open one from the piece with `mp piece pr create --base %s`, or merge without --push.",
+}
+
+// Catalog maps message IDs to format strings
+type Catalog map[string]string
+
+var (
+	mu     sync.RWMutex
+	active = English
+)
+
+// Use makes c the catalog T reads from and returns the previous one
+func Use(c Catalog) Catalog {
+	mu.Lock()
+	defer mu.Unlock()
+	previous := active
+	active = c
+	return previous
+}
+
+// Active returns a copy of the catalog T reads from, with English for the
+// messages it does not translate
+func Active() Catalog {
+	mu.RLock()
+	defer mu.RUnlock()
+	catalog := Catalog{}
+	for id, format := range English {
+		catalog[id] = format
+	}
+	for id, format := range active {
+		catalog[id] = format
+	}
+	return catalog
+}
+
+// T formats the message id of the active catalog with args, falling back to
+// English for messages the catalog does not translate. Unknown IDs are
+// returned as they are.
+func T(id string, args ...any) string {
+	mu.RLock()
+	format, ok := active[id]
+	mu.RUnlock()
+	if !ok {
+		if format, ok = English[id]; !ok {
+			return id
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+var (
+	// verbRegex matches fmt verbs, e.g. %s, %q, %d or %[1]s
+	verbRegex = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+	// indexRegex matches the argument index of a verb
+	indexRegex = regexp.MustCompile(`\[\d+\]`)
+)
+
+// Parse reads a translation: a JSON object of message IDs and format strings.
+// Messages missing from it stay English. A message with an unknown ID, or with
+// different format verbs than the English one, is an error.
+func Parse(data []byte) (Catalog, error) {
+	var translated map[string]string
+	if err := json.Unmarshal(data, &translated); err != nil {
+		return nil, fmt.Errorf("invalid messages JSON: %w", err)
+	}
+
+	catalog := Catalog{}
+	for id, english := range English {
+		catalog[id] = english
+	}
+
+	var problems []string
+	for id, format := range translated {
+		english, ok := English[id]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown message %q", id))
+			continue
+		}
+		if !sameVerbs(english, format) {
+			problems = append(problems, fmt.Sprintf("message %q must use the format verbs %v", id, verbs(english)))
+			continue
+		}
+		catalog[id] = format
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return catalog, nil
+}
+
+// verbs returns the fmt verbs of a format string in order
+func verbs(format string) []string {
+	found := verbRegex.FindAllString(format, -1)
+	kept := found[:0]
+	for _, v := range found {
+		if v != "%%" {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// sameVerbs reports whether two format strings take the same arguments.
+// Indexed verbs (%[2]s) may reorder them, so only the verb sets are compared.
+func sameVerbs(a, b string) bool {
+	strip := func(format string) []string {
+		var out []string
+		for _, v := range verbs(format) {
+			out = append(out, indexRegex.ReplaceAllString(v, ""))
+		}
+		sort.Strings(out)
+		return out
+	}
+	return slices.Equal(strip(a), strip(b))
+}
+
+// Lang returns the language of the user's locale, e.g. "de" for
+// LANG=de_DE.UTF-8. MP_LANG takes precedence over the POSIX locale variables.
+// The C and POSIX locales, and no locale, are English ("en").
+func Lang(getenv func(string) string) string {
+	for _, name := range []string{"MP_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+		if value == "C" || value == "POSIX" || strings.HasPrefix(value, "C.") {
+			return "en"
+		}
+		lang, _, _ := strings.Cut(value, ".")
+		lang, _, _ = strings.Cut(lang, "_")
+		lang, _, _ = strings.Cut(lang, "@")
+		return strings.ToLower(lang)
+	}
+	return "en"
+}
@@ -0,0 +1,84 @@
+package messages_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/messages"
+)
+
+func TestParse(t *testing.T) {
+	catalog, err := messages.Parse([]byte(`{"how_to_fix": "So geht's:", "piece_locked": "Piece %s ist gesperrt"}`))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if catalog[messages.HowToFix] != "So geht's:" {
+		t.Errorf("expected translated header, got %q", catalog[messages.HowToFix])
+	}
+	// Untranslated messages stay English
+	if catalog[messages.NotInRepo] != messages.English[messages.NotInRepo] {
+		t.Errorf("expected English fallback, got %q", catalog[messages.NotInRepo])
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not JSON", `{`, "invalid messages JSON"},
+		{"unknown ID", `{"no_such_message": "x"}`, `unknown message "no_such_message"`},
+		{"missing verb", `{"piece_locked": "gesperrt"}`, `message "piece_locked" must use the format verbs [%s]`},
+		{"wrong verb", `{"main_branch_missing.hint": "Branch %s fehlt"}`, "must use the format verbs [%q]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := messages.Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParse_ReorderedVerbs(t *testing.T) {
+	data := `{"dirty_worktree.hint": "%[2]s: %[1]s"}`
+	if _, err := messages.Parse([]byte(data)); err != nil {
+		t.Errorf("expected indexed verbs to be accepted, got: %v", err)
+	}
+}
+
+func TestT(t *testing.T) {
+	previous := messages.Use(messages.Catalog{messages.PieceLocked: "Piece %s ist gesperrt"})
+	defer messages.Use(previous)
+
+	if got := messages.T(messages.PieceLocked, "p1"); got != "Piece p1 ist gesperrt" {
+		t.Errorf("expected translated message, got %q", got)
+	}
+	if got := messages.T(messages.NotInRepo); got != "not in a git repository" {
+		t.Errorf("expected English fallback, got %q", got)
+	}
+	if got := messages.T("no_such_message"); got != "no_such_message" {
+		t.Errorf("expected unknown ID back, got %q", got)
+	}
+}
+
+func TestLang(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, "en"},
+		{map[string]string{"LANG": "de_DE.UTF-8"}, "de"},
+		{map[string]string{"LANG": "C.UTF-8"}, "en"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_ALL": "fr_FR@euro"}, "fr"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "MP_LANG": "pt_BR"}, "pt"},
+		{map[string]string{"LC_MESSAGES": "POSIX", "LANG": "de_DE"}, "en"},
+	}
+	for _, tt := range tests {
+		if got := messages.Lang(func(name string) string { return tt.env[name] }); got != tt.want {
+			t.Errorf("Lang(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
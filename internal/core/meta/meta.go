@@ -0,0 +1,141 @@
+// Package meta describes mp's commands, flags, arguments, and JSON input and
+// output as a machine-readable manifest, so tools built on mp can stay in sync.
+package meta
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ManifestVersion is bumped when the manifest format changes incompatibly
+const ManifestVersion = 1
+
+// Manifest describes every command of mp
+type Manifest struct {
+	Version int `json:"version"`
+	APIInfo
+	Commands []Command `json:"commands"`
+}
+
+// Command describes one command
+type Command struct {
+	// Path is the full command line without arguments, e.g. "mp piece new"
+	Path  string `json:"path"`
+	Use   string `json:"use"`
+	Short string `json:"short,omitempty"`
+	Long  string `json:"long,omitempty"`
+	// Args is the argument syntax from the usage line, e.g. "<issue> <n>"
+	Args    string   `json:"args,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	Flags   []Flag   `json:"flags,omitempty"`
+	// Runnable is false for commands that only group subcommands
+	Runnable bool `json:"runnable"`
+	// Input is the JSON schema of input accepted on stdin
+	Input map[string]any `json:"input,omitempty"`
+	// Output is the JSON schema of the result written to stdout
+	Output map[string]any `json:"output,omitempty"`
+}
+
+// Flag describes a command-line flag
+type Flag struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   string `json:"default,omitempty"`
+	Usage     string `json:"usage"`
+	// Persistent flags apply to the command and all its subcommands
+	Persistent bool `json:"persistent,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema returns a JSON schema of the JSON encoding of v's type,
+// following encoding/json rules for field names, omitempty, and embedding.
+func Schema(v any) map[string]any {
+	return schemaOf(reflect.TypeOf(v), map[reflect.Type]bool{})
+}
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte encodes as base64
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			// Recursive type; leave the nested value open
+			return map[string]any{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		properties := map[string]any{}
+		var required []string
+		addStructFields(t, properties, &required, visiting)
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		// interface values can hold anything
+		return map[string]any{}
+	}
+}
+
+func addStructFields(t reflect.Type, properties map[string]any, required *[]string, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addStructFields(ft, properties, required, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaOf(field.Type, visiting)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package meta_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
+)
+
+type inner struct {
+	Count int `json:"count"`
+}
+
+type sample struct {
+	Name    string            `json:"name"`
+	Tags    []string          `json:"tags,omitempty"`
+	When    time.Time         `json:"when"`
+	Nested  *inner            `json:"nested,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Skipped string            `json:"-"`
+	hidden  string
+	inner
+}
+
+func TestSchema(t *testing.T) {
+	got, err := json.Marshal(meta.Schema(sample{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"properties":{"count":{"type":"integer"},"labels":{"additionalProperties":{"type":"string"},"type":"object"},` +
+		`"name":{"type":"string"},"nested":{"properties":{"count":{"type":"integer"}},"required":["count"],"type":"object"},` +
+		`"tags":{"items":{"type":"string"},"type":"array"},"when":{"format":"date-time","type":"string"}},` +
+		`"required":["name","when","count"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("Schema() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := meta.Schema([]inner{})["type"]; got != "array" {
+		t.Errorf("expected array schema for a slice, got %v", got)
+	}
+}
@@ -0,0 +1,36 @@
+package meta
+
+import "runtime/debug"
+
+// APIVersion is the version of mp's machine-readable output: the JSON
+// results, errors, and manifest of the CLI and the MCP server. It changes
+// only with an incompatible change (a field removed, renamed, or retyped);
+// new fields and commands keep it.
+const APIVersion = "v1"
+
+// version is set at build time with
+// -ldflags "-X github.com/jewell-lgtm/monkeypuzzle/internal/core/meta.version=1.2.0"
+var version string
+
+// Version returns the version of mp: the one set at build time, else the
+// module version go install recorded, else "dev"
+func Version() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// APIInfo identifies the output format and mp version in JSON envelopes
+type APIInfo struct {
+	APIVersion string `json:"api_version"`
+	MPVersion  string `json:"mp_version"`
+}
+
+// Info returns the APIInfo of this build
+func Info() APIInfo {
+	return APIInfo{APIVersion: APIVersion, MPVersion: Version()}
+}
@@ -0,0 +1,96 @@
+// Package operation runs multi-step operations with best-effort rollback. Each
+// step that changes state registers an action undoing it; when a later step
+// fails, the registered actions run in reverse order and one report says what
+// was restored and what could not be.
+package operation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Report is the outcome of rolling back a failed operation, written as the
+// Data of its warning message
+type Report struct {
+	Operation string `json:"operation"`
+	Error     string `json:"error"`
+	// Restored is true when every undo action succeeded
+	Restored bool `json:"restored"`
+	// Undone lists the steps undone, most recent first
+	Undone []string      `json:"undone,omitempty"`
+	Failed []UndoFailure `json:"failed,omitempty"`
+}
+
+// UndoFailure is a step whose undo action failed
+type UndoFailure struct {
+	Step  string `json:"step"`
+	Error string `json:"error"`
+}
+
+type action struct {
+	step string
+	undo func() error
+}
+
+// Runner tracks the undo actions of an operation in progress
+type Runner struct {
+	output  core.Output
+	name    string
+	actions []action
+}
+
+// New starts an operation named name (e.g. "create piece foo"), reporting
+// rollbacks to output
+func New(output core.Output, name string) *Runner {
+	return &Runner{output: output, name: name}
+}
+
+// Undo registers undo as the action reverting step, described as what undo
+// does (e.g. "remove worktree /pieces/foo"). Register it once the step has
+// changed state, or before a step that can fail halfway.
+func (r *Runner) Undo(step string, undo func() error) {
+	r.actions = append(r.actions, action{step: step, undo: undo})
+}
+
+// Fail rolls back the operation after cause: every registered action runs,
+// most recent first, even when one fails. It reports the rollback as a warning
+// and returns cause, noting any action that failed. With nothing to undo, cause
+// is returned as is.
+func (r *Runner) Fail(cause error) error {
+	if len(r.actions) == 0 {
+		return cause
+	}
+	report := Report{Operation: r.name, Error: cause.Error()}
+	for i := len(r.actions) - 1; i >= 0; i-- {
+		a := r.actions[i]
+		if err := a.undo(); err != nil {
+			report.Failed = append(report.Failed, UndoFailure{Step: a.step, Error: err.Error()})
+			continue
+		}
+		report.Undone = append(report.Undone, a.step)
+	}
+	r.actions = nil
+	report.Restored = len(report.Failed) == 0
+
+	if report.Restored {
+		r.output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Operation failed: %s; state restored (%s)", r.name, strings.Join(report.Undone, ", ")),
+			Data:    report,
+		})
+		return cause
+	}
+
+	failures := make([]string, len(report.Failed))
+	for i, f := range report.Failed {
+		failures[i] = fmt.Sprintf("%s: %s", f.Step, f.Error)
+	}
+	r.output.Write(core.Message{
+		Type:    core.MsgWarning,
+		Content: fmt.Sprintf("Operation failed: %s; state only partly restored, could not %s", r.name, strings.Join(failures, "; ")),
+		Data:    report,
+	})
+	return fmt.Errorf("%w (rollback incomplete: could not %s)", cause, strings.Join(failures, "; "))
+}
@@ -0,0 +1,74 @@
+package operation_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
+)
+
+func TestRunner_Fail_UndoesInReverse(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	op := operation.New(out, "create piece foo")
+	var order []string
+	op.Undo("remove worktree", func() error { order = append(order, "worktree"); return nil })
+	op.Undo("kill session", func() error { order = append(order, "session"); return nil })
+
+	cause := core.NewNotInRepoError()
+	err := op.Fail(cause)
+	if err != cause {
+		t.Fatalf("expected cause to be returned as is, got %v", err)
+	}
+	if strings.Join(order, ",") != "session,worktree" {
+		t.Errorf("expected undo in reverse order, got %v", order)
+	}
+
+	if len(out.Messages) != 1 || out.Messages[0].Type != core.MsgWarning {
+		t.Fatalf("expected one warning, got %+v", out.Messages)
+	}
+	if got := out.Messages[0].Content; got != "Operation failed: create piece foo; state restored (kill session, remove worktree)" {
+		t.Errorf("unexpected report %q", got)
+	}
+	report := out.Messages[0].Data.(operation.Report)
+	if !report.Restored || report.Operation != "create piece foo" || report.Error != cause.Error() {
+		t.Errorf("unexpected report data %+v", report)
+	}
+}
+
+func TestRunner_Fail_ContinuesPastFailedUndo(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	op := operation.New(out, "merge")
+	checkedOut := false
+	op.Undo("check out feature", func() error { checkedOut = true; return nil })
+	op.Undo("reset main", func() error { return errors.New("locked index") })
+
+	cause := errors.New("commit failed")
+	err := op.Fail(cause)
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected error to wrap cause, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "could not reset main: locked index") {
+		t.Errorf("expected failed undo in error, got %v", err)
+	}
+	if !checkedOut {
+		t.Error("expected earlier undo actions to run after a failure")
+	}
+	report := out.Messages[0].Data.(operation.Report)
+	if report.Restored || len(report.Failed) != 1 || len(report.Undone) != 1 {
+		t.Errorf("unexpected report data %+v", report)
+	}
+}
+
+func TestRunner_Fail_NothingToUndo(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	cause := errors.New("boom")
+	if err := operation.New(out, "merge").Fail(cause); err != cause {
+		t.Fatalf("expected cause, got %v", err)
+	}
+	if len(out.Messages) != 0 {
+		t.Errorf("expected no report, got %+v", out.Messages)
+	}
+}
@@ -0,0 +1,176 @@
+// Package owners maps issues to the people responsible for them, using a
+// CODEOWNERS-style file at .monkeypuzzle/owners.
+package owners
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// Filename is the owners file in .monkeypuzzle
+const Filename = "owners"
+
+// LabelPrefix marks a rule matching issues by label rather than path
+const LabelPrefix = "label:"
+
+// IdentityKey is the git config key naming the current user's owner handle,
+// e.g. "@alice". Without it, git's user.email is used.
+const IdentityKey = "mp.user"
+
+// Rule assigns owners to the issues matching a pattern
+type Rule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	Line    int      `json:"line"`
+}
+
+// Owners holds the rules of an owners file, in file order
+type Owners struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Parse reads an owners file. Each non-comment line holds a pattern followed
+// by owner handles (@user, @org/team) or emails:
+//
+//	issues/backend/   @backend-team
+//	*-api.md          @alice
+//	label:security    @security-team alice@example.com
+//
+// A pattern with a slash matches issue paths from the repository root,
+// a trailing slash matching everything below a directory; a pattern without
+// one matches file names. As in CODEOWNERS, the last matching rule wins.
+func Parse(data []byte) (*Owners, error) {
+	o := &Owners{}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s line %d: expected a pattern followed by owners", Filename, n)
+		}
+		pattern := fields[0]
+		if !strings.HasPrefix(pattern, LabelPrefix) {
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/"), ""); err != nil {
+				return nil, fmt.Errorf("%s line %d: invalid pattern %q: %w", Filename, n, pattern, err)
+			}
+		}
+		o.Rules = append(o.Rules, Rule{Pattern: pattern, Owners: fields[1:], Line: n})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Load reads the owners file of the repository at repoRoot. A missing file
+// yields no rules.
+func Load(fs core.FS, repoRoot string) (*Owners, error) {
+	data, err := fs.ReadFile(filepath.Join(repoRoot, initcmd.DirName, Filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Owners{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", Filename, err)
+	}
+	return Parse(data)
+}
+
+// For returns the owners of the issue at relPath (relative to the repository
+// root) carrying labels, or nil if no rule matches
+func (o *Owners) For(relPath string, labels []string) []string {
+	relPath = filepath.ToSlash(relPath)
+	var owners []string
+	for _, rule := range o.Rules {
+		if rule.matches(relPath, labels) {
+			owners = rule.Owners
+		}
+	}
+	return owners
+}
+
+func (r Rule) matches(relPath string, labels []string) bool {
+	if label, ok := strings.CutPrefix(r.Pattern, LabelPrefix); ok {
+		for _, l := range labels {
+			if strings.EqualFold(l, label) {
+				return true
+			}
+		}
+		return false
+	}
+
+	pattern := strings.TrimPrefix(r.Pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(relPath))
+		return ok
+	}
+	if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+		for d := path.Dir(relPath); d != "." && d != "/"; d = path.Dir(d) {
+			if matched, _ := path.Match(dir, d); matched {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ := path.Match(pattern, relPath)
+	return ok
+}
+
+// Identity returns the current user's owner handle from git config mp.user,
+// falling back to user.email, or "" if neither is set
+func Identity(exec core.Exec, workDir string) string {
+	for _, key := range []string{IdentityKey, "user.email"} {
+		output, err := exec.RunWithDir(workDir, "git", "config", "--get", key)
+		if err != nil {
+			continue
+		}
+		if id := strings.TrimSpace(string(output)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// Includes reports whether identity is one of owners. Handles compare without
+// their leading @ and case-insensitively, so "alice" matches "@alice".
+func Includes(owners []string, identity string) bool {
+	id := normalize(identity)
+	if id == "" {
+		return false
+	}
+	for _, owner := range owners {
+		if normalize(owner) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Reviewers returns the GitHub handles among owners, without their @, that can
+// be requested as PR reviewers. Emails and the identity (the PR author) are
+// left out.
+func Reviewers(owners []string, identity string) []string {
+	var reviewers []string
+	for _, owner := range owners {
+		handle, ok := strings.CutPrefix(owner, "@")
+		if !ok || handle == "" || Includes([]string{owner}, identity) {
+			continue
+		}
+		reviewers = append(reviewers, handle)
+	}
+	return reviewers
+}
+
+func normalize(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
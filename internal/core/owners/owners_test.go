@@ -0,0 +1,114 @@
+package owners_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/owners"
+)
+
+const testOwners = `# Issue owners
+*.md               @triage
+issues/backend/    @backend-team
+issues/*/api-*.md  @alice
+label:security     @sec alice@example.com
+`
+
+func TestOwners_For(t *testing.T) {
+	o, err := owners.Parse([]byte(testOwners))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		labels []string
+		want   []string
+	}{
+		{"issues/readme.md", nil, []string{"@triage"}},
+		{"issues/backend/cache.md", nil, []string{"@backend-team"}},
+		{"issues/backend/sub/deep.md", nil, []string{"@backend-team"}},
+		{"issues/backend/api-keys.md", nil, []string{"@alice"}},
+		{"issues/backend/cache.md", []string{"Security"}, []string{"@sec", "alice@example.com"}},
+		{"notes.txt", nil, nil},
+	}
+	for _, tt := range tests {
+		if got := o.For(tt.path, tt.labels); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("For(%q, %v) = %v, want %v", tt.path, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := owners.Parse([]byte("issues/backend/\n")); err == nil {
+		t.Error("expected error for a rule without owners")
+	}
+	if _, err := owners.Parse([]byte("issues/[ @alice\n")); err == nil {
+		t.Error("expected error for an invalid pattern")
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	o, err := owners.Load(adapters.NewMemoryFS(), "/repo")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(o.Rules) != 0 {
+		t.Errorf("expected no rules, got %+v", o.Rules)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	_ = fs.MkdirAll("/repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("/repo/.monkeypuzzle/owners", []byte("issues/backend/\n"), 0644)
+	if _, err := owners.Load(fs, "/repo"); err == nil {
+		t.Error("expected an invalid owners file to fail")
+	}
+
+	if _, err := owners.Load(unreadableFS{fs}, "/repo"); err == nil || !errors.Is(err, os.ErrPermission) {
+		t.Errorf("expected an unreadable owners file to fail, got %v", err)
+	}
+}
+
+// unreadableFS fails every read
+type unreadableFS struct {
+	*adapters.MemoryFS
+}
+
+func (unreadableFS) ReadFile(string) ([]byte, error) {
+	return nil, os.ErrPermission
+}
+
+func TestIdentity(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"config", "--get", "mp.user"}, nil, errors.New("exit status 1"))
+	mockExec.AddResponse("git", []string{"config", "--get", "user.email"}, []byte("alice@example.com\n"), nil)
+
+	if got := owners.Identity(mockExec, "/repo"); got != "alice@example.com" {
+		t.Errorf("expected fallback to user.email, got %q", got)
+	}
+
+	mockExec.AddResponse("git", []string{"config", "--get", "mp.user"}, []byte("@alice\n"), nil)
+	if got := owners.Identity(mockExec, "/repo"); got != "@alice" {
+		t.Errorf("expected mp.user, got %q", got)
+	}
+}
+
+func TestIncludesAndReviewers(t *testing.T) {
+	list := []string{"@Alice", "@org/backend", "bob@example.com"}
+
+	if !owners.Includes(list, "alice") || !owners.Includes(list, "BOB@example.com") {
+		t.Error("expected handles and emails to match case-insensitively")
+	}
+	if owners.Includes(list, "") || owners.Includes(list, "@carol") {
+		t.Error("expected no match for other identities")
+	}
+
+	if got := owners.Reviewers(list, "@alice"); !reflect.DeepEqual(got, []string{"org/backend"}) {
+		t.Errorf("expected handles other than the author, got %v", got)
+	}
+}
@@ -0,0 +1,134 @@
+package piece
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+const pieceMetadataFilename = "piece.json"
+
+// PieceMetadata stores piece-level settings that outlive a single command
+type PieceMetadata struct {
+	// BaseBranch is the branch the piece merges into, set by `mp piece merge --into`
+	BaseBranch string `json:"base_branch,omitempty"`
+	// SourceDir is the monkeypuzzle source directory the piece was created from
+	SourceDir string `json:"source_dir,omitempty"`
+	// Template is the piece template the piece was created from, if any
+	Template string `json:"template,omitempty"`
+	// MergedCommit is the head of the piece branch mp piece merge last merged
+	MergedCommit string `json:"merged_commit,omitempty"`
+}
+
+// ReadPieceMetadata reads the piece's settings
+func (s *MetadataStore) ReadPieceMetadata() (*PieceMetadata, error) {
+	var metadata PieceMetadata
+	if err := s.read(pieceMetadataFilename, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to read piece metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
+// WritePieceMetadata writes the piece's settings
+func (s *MetadataStore) WritePieceMetadata(metadata PieceMetadata) error {
+	if err := s.write(pieceMetadataFilename, metadata); err != nil {
+		return fmt.Errorf("failed to write piece metadata: %w", err)
+	}
+	return nil
+}
+
+// updatePieceMetadata applies update to the piece's settings, keeping the others
+func (h *Handler) updatePieceMetadata(worktreePath string, update func(*PieceMetadata)) error {
+	store := OpenMetadataStore(h.deps, worktreePath)
+	metadata, err := store.ReadPieceMetadata()
+	if err != nil {
+		metadata = &PieceMetadata{}
+	}
+	update(metadata)
+	return store.WritePieceMetadata(*metadata)
+}
+
+// BaseBranch returns the branch a piece merges into: the base recorded by
+// `mp piece merge --into`, else the base of its PR, else fallback.
+func (h *Handler) BaseBranch(worktreePath, fallback string) string {
+	store := OpenMetadataStore(h.deps, worktreePath)
+	if metadata, err := store.ReadPieceMetadata(); err == nil && metadata.BaseBranch != "" {
+		return metadata.BaseBranch
+	}
+	if metadata, err := store.ReadPRMetadata(); err == nil && metadata.BaseBranch != "" {
+		return metadata.BaseBranch
+	}
+	return fallback
+}
+
+// MergePieceInto squash-merges the piece into target, an arbitrary local branch
+// such as release/1.2 or a hotfix branch. The target must exist, differ from the
+// piece branch, and share history with it. Once merged, the target is recorded
+// as the piece's base so later updates, PRs, and cleanup use it.
+func (h *Handler) MergePieceInto(workDir, target string) error {
+	return h.MergePieceIntoWithOptions(workDir, target, MergeOptions{})
+}
+
+// MergePieceIntoWithOptions squash-merges the piece into target as
+// MergePieceInto does, configured by opts
+func (h *Handler) MergePieceIntoWithOptions(workDir, target string, opts MergeOptions) error {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return fmt.Errorf("target branch is required")
+	}
+
+	status, err := h.Status(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return core.NewNotInPieceError()
+	}
+
+	pieceBranch, err := h.git.CurrentBranch(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if target == pieceBranch {
+		return fmt.Errorf("cannot merge %s into itself", pieceBranch)
+	}
+
+	if !h.git.BranchExists(workDir, target) {
+		return fmt.Errorf("target branch %s does not exist", target)
+	}
+
+	mergeBase, err := h.git.MergeBase(workDir, target, pieceBranch)
+	if err != nil {
+		return err
+	}
+	if mergeBase == "" {
+		return fmt.Errorf("cannot merge: %s and %s share no history", pieceBranch, target)
+	}
+
+	if err := h.MergePieceWithOptions(workDir, target, opts); err != nil {
+		return err
+	}
+	// The merge stands, so a base that can't be recorded is only reported
+	if err := h.recordBaseBranch(status.WorktreePath, target); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to record %s as the piece's base: %v", target, err),
+		})
+	}
+	return nil
+}
+
+// recordBaseBranch stores target as the piece's base branch
+func (h *Handler) recordBaseBranch(worktreePath, target string) error {
+	store := OpenMetadataStore(h.deps, worktreePath)
+	metadata, err := store.ReadPieceMetadata()
+	if err != nil {
+		metadata = &PieceMetadata{}
+	}
+	if metadata.BaseBranch == target {
+		return nil
+	}
+	metadata.BaseBranch = target
+	return store.WritePieceMetadata(*metadata)
+}
@@ -0,0 +1,128 @@
+package piece_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// setupMockPiece mocks piece-1 at /pieces/piece-1 of repo /repo, branched
+// from base, which exists and has no commits the piece lacks; the piece has
+// one commit of its own
+func setupMockPiece(t *testing.T, base string) (*adapters.MemoryFS, *adapters.BufferOutput, *adapters.MockExec, *piece.Handler) {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/piece-1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/pieces/piece-1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("piece-1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--verify", "--quiet", "refs/heads/" + base}, []byte("def456\n"), nil)
+	mockExec.AddResponse("git", []string{"merge-base", base, "piece-1"}, []byte("abc123\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-list", "--count", "abc123.." + base}, []byte("0\n"), nil)
+	mockCommitLog(mockExec, base+"..piece-1", adapters.Commit{Subject: "feat: add feature"})
+	return fs, out, mockExec, handler
+}
+
+func TestHandler_MergePieceInto(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "release/1.2")
+	mockExec.AddResponse("git", []string{"merge-base", "release/1.2", "piece-1"}, []byte("abc123\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-list", "--count", "abc123..release/1.2"}, []byte("0\n"), nil)
+	mockCommitLog(mockExec, "release/1.2..piece-1", adapters.Commit{Subject: "fix: hotfix"})
+	mockMainCheckout(mockExec, "release/1.2")
+	mockExec.AddResponse("git", []string{"checkout", "release/1.2"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge", "--squash", "piece-1"}, nil, nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", "feat: piece-1\n\nSquashed commits:\n- fix: hotfix\n"}, nil, nil)
+
+	if err := handler.MergePieceInto("/pieces/piece-1", "release/1.2"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !mockExec.WasCalled("git", "merge", "--squash", "piece-1") {
+		t.Error("expected piece to be squash merged")
+	}
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	metadata, err := store.ReadPieceMetadata()
+	if err != nil {
+		t.Fatalf("expected piece metadata, got: %v", err)
+	}
+	if metadata.BaseBranch != "release/1.2" {
+		t.Errorf("expected recorded base release/1.2, got %q", metadata.BaseBranch)
+	}
+	if got := handler.BaseBranch("/pieces/piece-1", "main"); got != "release/1.2" {
+		t.Errorf("expected BaseBranch to return release/1.2, got %q", got)
+	}
+}
+
+func TestHandler_MergePieceInto_MissingTarget(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "release/1.2")
+
+	err := handler.MergePieceInto("/pieces/piece-1", "release/9.9")
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected missing target error, got %v", err)
+	}
+	if mockExec.WasCalled("git", "checkout", "release/9.9") {
+		t.Error("expected no checkout of a missing target")
+	}
+}
+
+func TestHandler_MergePieceInto_Self(t *testing.T) {
+	_, _, _, handler := setupMockPiece(t, "release/1.2")
+
+	err := handler.MergePieceInto("/pieces/piece-1", "piece-1")
+	if err == nil || !strings.Contains(err.Error(), "into itself") {
+		t.Fatalf("expected self-merge error, got %v", err)
+	}
+}
+
+func TestHandler_MergePieceInto_UnrelatedHistory(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "release/1.2")
+	mockExec.AddResponse("git", []string{"merge-base", "release/1.2", "piece-1"}, nil, errors.New("exit status 1"))
+
+	err := handler.MergePieceInto("/pieces/piece-1", "release/1.2")
+	if err == nil || !strings.Contains(err.Error(), "share no history") {
+		t.Fatalf("expected unrelated history error, got %v", err)
+	}
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if _, err := store.ReadPieceMetadata(); err == nil {
+		t.Error("expected no base to be recorded when the merge is refused")
+	}
+}
+
+func TestHandler_MergePieceInto_FailedMergeRecordsNoBase(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "release/1.2")
+	mockExec.AddResponse("git", []string{"merge-base", "release/1.2", "piece-1"}, []byte("abc123\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-list", "--count", "abc123..release/1.2"}, []byte("2\n"), nil)
+
+	err := handler.MergePieceInto("/pieces/piece-1", "release/1.2")
+	if err == nil || !strings.Contains(err.Error(), "mp piece update --main-branch release/1.2") {
+		t.Fatalf("expected the merge to be refused, got %v", err)
+	}
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if _, err := store.ReadPieceMetadata(); err == nil {
+		t.Error("expected no base to be recorded when the merge fails")
+	}
+}
+
+func TestHandler_BaseBranch_FallsBackToPR(t *testing.T) {
+	fs, _, _, handler := setupMockPiece(t, "release/1.2")
+
+	if got := handler.BaseBranch("/pieces/piece-1", "main"); got != "main" {
+		t.Errorf("expected fallback main, got %q", got)
+	}
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	_ = store.WritePRMetadata(piece.PRMetadata{PRNumber: 3, BaseBranch: "develop"})
+	if got := handler.BaseBranch("/pieces/piece-1", "main"); got != "develop" {
+		t.Errorf("expected PR base develop, got %q", got)
+	}
+}
@@ -0,0 +1,186 @@
+package piece
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// BisectOptions configures a bisect of the current piece
+type BisectOptions struct {
+	// Good is a commit without the regression; empty means where the piece
+	// branched off its base branch
+	Good string
+	// Bad is a commit with the regression; empty means HEAD
+	Bad string
+	// Command is the test, exiting non-zero on bad commits: a shell command
+	// line when it is one element, else a command and its arguments. Empty
+	// means the verify commands of the piece's template.
+	Command []string
+}
+
+// BisectResult is the outcome of a bisect
+type BisectResult struct {
+	Piece   string `json:"piece"`
+	Good    string `json:"good"`
+	Bad     string `json:"bad"`
+	Command string `json:"command"`
+	// Commit is the first bad commit, with its subject and author
+	Commit  string `json:"commit"`
+	Subject string `json:"subject,omitempty"`
+	Author  string `json:"author,omitempty"`
+	// NotesPath is the piece's notes, where the finding was appended
+	NotesPath string `json:"notes_path"`
+}
+
+// firstBadRegex matches git bisect's report of the commit it found
+var firstBadRegex = regexp.MustCompile(`(?m)^([0-9a-f]{7,64}) is the first bad commit`)
+
+// Bisect runs git bisect in the piece's worktree to find the commit between
+// opts.Good and opts.Bad where the test command started failing, then
+// appends the finding to the piece's notes. The worktree must be clean, as
+// bisect checks out each commit it tests; it is returned to its branch
+// afterwards, whether or not a commit was found.
+func (h *Handler) Bisect(workDir string, opts BisectOptions) (BisectResult, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return BisectResult{}, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return BisectResult{}, core.NewNotInPieceError()
+	}
+	worktree := status.WorktreePath
+
+	command, err := h.bisectCommand(status, opts.Command)
+	if err != nil {
+		return BisectResult{}, err
+	}
+	gitDir, err := h.git.RevParseGitDir(worktree)
+	if err != nil {
+		return BisectResult{}, err
+	}
+	if _, err := h.deps.FS.Stat(filepath.Join(gitDir, "BISECT_LOG")); err == nil {
+		return BisectResult{}, fmt.Errorf("a bisect is already in progress in %s; end it with git bisect reset", worktree)
+	}
+	dirty, err := h.git.HasUncommittedChanges(worktree)
+	if err != nil {
+		return BisectResult{}, err
+	}
+	if dirty {
+		return BisectResult{}, core.NewDirtyWorktreeError(worktree,
+			fmt.Errorf("cannot bisect: the piece worktree %s has uncommitted changes", worktree))
+	}
+
+	result := BisectResult{Piece: status.PieceName, Good: opts.Good, Bad: opts.Bad, Command: command}
+	if result.Bad == "" {
+		result.Bad = "HEAD"
+	}
+	if result.Good == "" {
+		base := h.BaseBranch(worktree, "main")
+		forkPoint, err := h.git.MergeBase(worktree, base, result.Bad)
+		if err != nil {
+			return BisectResult{}, err
+		}
+		if forkPoint == "" {
+			return BisectResult{}, fmt.Errorf("%s shares no history with %s; pass --good", result.Bad, base)
+		}
+		result.Good = forkPoint
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgInfo,
+		Content: fmt.Sprintf("Bisecting %s..%s with: %s", result.Good, result.Bad, command),
+	})
+	if err := h.git.BisectStart(worktree, result.Bad, result.Good); err != nil {
+		_ = h.git.BisectReset(worktree)
+		return BisectResult{}, err
+	}
+	ctx := HookContext{
+		PieceName:    status.PieceName,
+		WorktreePath: worktree,
+		RepoRoot:     status.RepoRoot,
+		SessionName:  SessionName(status.PieceName),
+		SourceDir:    h.resolveSourceDir(worktree),
+	}
+	output, runErr := h.git.BisectRun(worktree, h.hooks.buildEnv(ctx), command)
+	resetErr := h.git.BisectReset(worktree)
+
+	match := firstBadRegex.FindStringSubmatch(output)
+	if match == nil {
+		if runErr == nil {
+			runErr = errors.New("no first bad commit reported")
+		}
+		return BisectResult{}, errors.Join(fmt.Errorf("bisect failed: %w\n%s", runErr, strings.TrimSpace(output)), resetErr)
+	}
+	if resetErr != nil {
+		return BisectResult{}, resetErr
+	}
+	result.Commit = match[1]
+	if commits, err := h.git.CommitLog(worktree, result.Commit+"~1", result.Commit); err == nil && len(commits) == 1 {
+		result.Subject = commits[0].Subject
+		result.Author = commits[0].Author
+	}
+
+	store := OpenMetadataStore(h.deps, worktree)
+	result.NotesPath = store.NotesPath()
+	if err := store.AppendNotes(bisectNotes(result)); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to write piece notes: %v", err),
+		})
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("First bad commit: %s %s", shortCommit(result.Commit), result.Subject),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// bisectCommand returns the shell command line to bisect with: the given
+// command, else the template's verify commands
+func (h *Handler) bisectCommand(status PieceStatus, command []string) (string, error) {
+	if len(command) == 1 {
+		return command[0], nil
+	}
+	if len(command) > 1 {
+		words := make([]string, len(command))
+		for i, word := range command {
+			words[i] = adapters.ShellQuote(word)
+		}
+		return strings.Join(words, " "), nil
+	}
+
+	tmpl, ok, err := h.pieceTemplate(status)
+	if err != nil {
+		return "", err
+	}
+	if !ok || len(tmpl.Verify) == 0 {
+		return "", fmt.Errorf("no test command: pass one after --, e.g. mp piece bisect -- go test ./..., or create the piece from a template with verify commands")
+	}
+	return strings.Join(tmpl.Verify, " && "), nil
+}
+
+// bisectNotes formats a bisect finding as a section of the piece notes
+func bisectNotes(r BisectResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Bisect %s\n\n", time.Now().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "- Command: `%s`\n", r.Command)
+	fmt.Fprintf(&b, "- Range: %s (good) to %s (bad)\n", r.Good, r.Bad)
+	fmt.Fprintf(&b, "- First bad commit: %s", r.Commit)
+	if r.Subject != "" {
+		fmt.Fprintf(&b, " %s", r.Subject)
+	}
+	if r.Author != "" {
+		fmt.Fprintf(&b, " (%s)", r.Author)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
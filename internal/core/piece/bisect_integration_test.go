@@ -0,0 +1,67 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_Bisect_FindsFirstBadCommit(t *testing.T) {
+	server := gitfake.NewServer(t)
+	_, worktree := setupPieceWorktree(t, server)
+	server.Commit(worktree, "status.txt", "ok\n", "add status")
+	server.Commit(worktree, "status.txt", "broken\n", "break status")
+	bad := server.Git(worktree, "rev-parse", "HEAD")
+	server.Commit(worktree, "other.txt", "more\n", "unrelated change")
+
+	result, err := newOSHandler().Bisect(worktree, piece.BisectOptions{
+		Command: []string{"! grep -q broken status.txt"},
+	})
+	if err != nil {
+		t.Fatalf("Bisect failed: %v", err)
+	}
+	if result.Commit != bad || result.Subject != "break status" {
+		t.Errorf("expected %s (break status) as first bad commit, got %+v", bad, result)
+	}
+	if got := server.Git(worktree, "rev-parse", "--abbrev-ref", "HEAD"); got != "piece-1" {
+		t.Errorf("expected the worktree back on piece-1, got %s", got)
+	}
+
+	notes, err := os.ReadFile(result.NotesPath)
+	if err != nil {
+		t.Fatalf("expected piece notes: %v", err)
+	}
+	if !strings.Contains(string(notes), "First bad commit: "+bad+" break status") {
+		t.Errorf("expected the finding in the notes, got:\n%s", notes)
+	}
+}
+
+func TestIntegration_Bisect_RefusesDirtyWorktree(t *testing.T) {
+	server := gitfake.NewServer(t)
+	_, worktree := setupPieceWorktree(t, server)
+	if err := os.WriteFile(filepath.Join(worktree, "feature.txt"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := newOSHandler().Bisect(worktree, piece.BisectOptions{Command: []string{"true"}})
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeDirtyWorktree {
+		t.Fatalf("expected dirty_worktree error, got %v", err)
+	}
+}
+
+func TestIntegration_Bisect_NeedsTestCommand(t *testing.T) {
+	server := gitfake.NewServer(t)
+	_, worktree := setupPieceWorktree(t, server)
+
+	_, err := newOSHandler().Bisect(worktree, piece.BisectOptions{})
+	if err == nil || !strings.Contains(err.Error(), "no test command") {
+		t.Fatalf("expected a missing test command error, got %v", err)
+	}
+}
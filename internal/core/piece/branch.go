@@ -0,0 +1,74 @@
+package piece
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// ValidateBranchName checks name against git's rules for branch names
+// (git check-ref-format --branch), so a bad name fails before any worktree
+// is created rather than with git's error halfway through
+func ValidateBranchName(name string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid branch name %q: %s", name, reason)
+	}
+	switch {
+	case name == "":
+		return invalid("must not be empty")
+	case name == "@":
+		return invalid(`must not be "@"`)
+	case strings.HasPrefix(name, "-"):
+		return invalid("must not start with '-'")
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/"):
+		return invalid("must not start or end with '/'")
+	case strings.HasSuffix(name, "."):
+		return invalid("must not end with '.'")
+	case strings.Contains(name, ".."):
+		return invalid(`must not contain ".."`)
+	case strings.Contains(name, "//"):
+		return invalid(`must not contain "//"`)
+	case strings.Contains(name, "@{"):
+		return invalid(`must not contain "@{"`)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) || strings.ContainsRune(" ~^:?*[\\", r) {
+			return invalid(fmt.Sprintf("must not contain %q", r))
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") {
+			return invalid("path components must not start with '.'")
+		}
+		if strings.HasSuffix(component, ".lock") {
+			return invalid(`path components must not end with ".lock"`)
+		}
+	}
+	return nil
+}
+
+// ValidatePieceName checks a piece name given with --name: it names the
+// piece's directory and branch, so it must be a valid branch name without '/'
+func ValidatePieceName(name string) error {
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("invalid piece name %q: must not contain '/'", name)
+	}
+	if err := ValidateBranchName(name); err != nil {
+		return fmt.Errorf("invalid piece name %q: %w", name, err)
+	}
+	return nil
+}
+
+// checkNewBranch fails if branch can't be created for a new piece in the
+// repository at repoRoot: it is not a valid name, or it already exists
+func (h *Handler) checkNewBranch(repoRoot, branch string) error {
+	if err := ValidateBranchName(branch); err != nil {
+		return err
+	}
+	if h.git.BranchExists(repoRoot, branch) {
+		return core.NewBranchExistsError(branch)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package piece_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestValidateBranchName(t *testing.T) {
+	valid := []string{"piece-1", "bugfix/login", "feature/a.b", "v1.2"}
+	invalid := []string{"", "@", "-piece", "/piece", "piece/", "piece.", "a..b", "a//b", "a@{1}",
+		"a b", "a~1", "a^", "a:b", "a?", "a*", "a[b", `a\b`, "a\tb", ".hidden", "a/.b", "piece.lock", "a.lock/b"}
+
+	for _, name := range valid {
+		if err := piece.ValidateBranchName(name); err != nil {
+			t.Errorf("expected %q to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range invalid {
+		if err := piece.ValidateBranchName(name); err == nil {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+	if err := piece.ValidatePieceName("bugfix/login"); err == nil {
+		t.Error("expected a piece name with '/' to be invalid")
+	}
+}
+
+func TestHandler_CreatePiece_InvalidName(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+
+	if _, err := handler.CreatePiece("/monkeypuzzle", "old.lock"); err == nil {
+		t.Fatal("expected an invalid name to be rejected")
+	}
+	if _, err := fs.Stat("/test-data/monkeypuzzle/pieces/old.lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no directory for an invalid name, got %v", err)
+	}
+}
+
+func TestHandler_CreatePiece_BranchExists(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--verify", "--quiet", "refs/heads/p1"}, []byte("abc123\n"), nil)
+
+	_, err := handler.CreatePiece("/monkeypuzzle", "p1")
+	remediable, ok := core.AsRemediable(err)
+	if !ok || remediable.Code != core.CodeBranchExists {
+		t.Fatalf("expected branch_exists, got %v", err)
+	}
+	if mockExec.WasCalled("git", "worktree", "add", "/test-data/monkeypuzzle/pieces/p1") {
+		t.Error("expected no worktree to be added")
+	}
+	if _, err := fs.Stat("/test-data/monkeypuzzle/pieces/p1"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the reserved directory to be removed, got %v", err)
+	}
+}
+
+func TestHandler_CreatePiece_AdoptBranch(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--verify", "--quiet", "refs/heads/bugfix/login"}, []byte("abc123\n"), nil)
+	worktreePath := "/test-data/monkeypuzzle/pieces/bugfix-login"
+	mockExec.AddResponse("git", []string{"worktree", "add", worktreePath, "bugfix/login"}, nil, nil)
+
+	info, err := handler.CreatePieceWithOptions(piece.CreateOptions{Branch: "bugfix/login"})
+	if err != nil {
+		t.Fatalf("CreatePieceWithOptions failed: %v", err)
+	}
+	if info.Name != "bugfix-login" || info.WorktreePath != worktreePath {
+		t.Errorf("unexpected piece %+v", info)
+	}
+
+	if _, err := handler.CreatePieceWithOptions(piece.CreateOptions{Branch: "missing"}); err == nil {
+		t.Error("expected adopting a missing branch to fail")
+	}
+}
@@ -0,0 +1,217 @@
+package piece
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// ValidateAgentLimits checks agent.limits from the config
+func ValidateAgentLimits(limits initcmd.AgentLimits) error {
+	switch {
+	case limits.CPUPercent < 0:
+		return fmt.Errorf("agent.limits.cpu_percent must not be negative, got %d", limits.CPUPercent)
+	case limits.MemoryMB < 0:
+		return fmt.Errorf("agent.limits.memory_mb must not be negative, got %d", limits.MemoryMB)
+	case limits.Nice < 0 || limits.Nice > 19:
+		return fmt.Errorf("agent.limits.nice must be between 0 and 19, got %d", limits.Nice)
+	}
+	return nil
+}
+
+// Platform describes what WrapAgentCommand can use to enforce limits
+type Platform struct {
+	// GOOS is the operating system, as runtime.GOOS
+	GOOS string
+	// SystemdRun is true when `systemd-run --user` can start cgroup scopes
+	SystemdRun bool
+}
+
+// DetectPlatform reports the limit mechanisms available on this machine
+func DetectPlatform(execer core.Exec, goos string) Platform {
+	p := Platform{GOOS: goos}
+	if goos == "linux" {
+		_, err := execer.Run("systemd-run", "--user", "--scope", "--quiet", "true")
+		p.SystemdRun = err == nil
+	}
+	return p
+}
+
+// WrapAgentCommand returns the shell command line running command within
+// limits, and warnings for limits the platform can't enforce:
+//   - Linux: a systemd-run cgroup scope (CPUQuota, MemoryMax), falling back
+//     to ulimit -v for memory when systemd-run --user isn't available
+//   - macOS: taskpolicy -b for CPU, which throttles rather than caps; memory
+//     limits are not enforced
+//
+// Nice applies everywhere.
+func WrapAgentCommand(command string, limits initcmd.AgentLimits, platform Platform) (string, []string) {
+	if limits == (initcmd.AgentLimits{}) {
+		return command, nil
+	}
+
+	var prefix []string
+	var warnings []string
+	inner := command
+	switch platform.GOOS {
+	case "linux":
+		if platform.SystemdRun && (limits.CPUPercent > 0 || limits.MemoryMB > 0) {
+			prefix = append(prefix, "systemd-run", "--user", "--scope", "--quiet")
+			if limits.CPUPercent > 0 {
+				prefix = append(prefix, "-p", fmt.Sprintf("CPUQuota=%d%%", limits.CPUPercent))
+			}
+			if limits.MemoryMB > 0 {
+				prefix = append(prefix, "-p", fmt.Sprintf("MemoryMax=%dM", limits.MemoryMB))
+			}
+			prefix = append(prefix, "--")
+			break
+		}
+		if limits.MemoryMB > 0 {
+			inner = fmt.Sprintf("ulimit -v %d && exec %s", limits.MemoryMB*1024, command)
+		}
+		if limits.CPUPercent > 0 {
+			warnings = append(warnings, "agent.limits.cpu_percent needs systemd-run --user; the agent runs without a CPU cap")
+		}
+	case "darwin":
+		if limits.CPUPercent > 0 {
+			prefix = append(prefix, "taskpolicy", "-b")
+		}
+		if limits.MemoryMB > 0 {
+			warnings = append(warnings, "agent.limits.memory_mb is not enforced on macOS")
+		}
+	default:
+		if limits.CPUPercent > 0 || limits.MemoryMB > 0 {
+			warnings = append(warnings, fmt.Sprintf("agent.limits are not enforced on %s", platform.GOOS))
+		}
+	}
+	if limits.Nice > 0 {
+		prefix = append(prefix, "nice", "-n", strconv.Itoa(limits.Nice))
+	}
+
+	if len(prefix) == 0 && inner == command {
+		return command, warnings
+	}
+	return strings.Join(append(prefix, "sh", "-c", adapters.ShellQuote(inner)), " "), warnings
+}
+
+// ResourceUsage is what the processes in a piece's tmux session use, as
+// reported by `mp piece doctor`
+type ResourceUsage struct {
+	Processes  int     `json:"processes"`
+	CPUPercent float64 `json:"cpu_percent"`
+	MemoryMB   int     `json:"memory_mb"`
+	// Limits is the configured agent budget, if any
+	Limits *initcmd.AgentLimits `json:"limits,omitempty"`
+	// OverBudget names the limits the session exceeds
+	OverBudget []string `json:"over_budget,omitempty"`
+}
+
+// sessionUsage sums the CPU and memory of the processes running in the
+// piece's tmux session, including their descendants. It reports false when
+// the session isn't running.
+func (h *Handler) sessionUsage(pieceName string, limits initcmd.AgentLimits) (ResourceUsage, bool, error) {
+	session := SessionName(pieceName)
+	running, err := h.tmux.HasSession(session)
+	if err != nil || !running {
+		return ResourceUsage{}, false, err
+	}
+	panes, err := h.tmux.PanePIDs(session)
+	if err != nil {
+		return ResourceUsage{}, false, err
+	}
+
+	output, err := h.deps.Exec.Run("ps", "-A", "-o", "pid=,ppid=,rss=,pcpu=")
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return ResourceUsage{}, false, fmt.Errorf("ps is not installed")
+		}
+		return ResourceUsage{}, false, fmt.Errorf("failed to list processes: %w", err)
+	}
+	usage := sumProcessTree(string(output), panes)
+
+	if limits != (initcmd.AgentLimits{}) {
+		usage.Limits = &limits
+		if limits.CPUPercent > 0 && usage.CPUPercent > float64(limits.CPUPercent) {
+			usage.OverBudget = append(usage.OverBudget, "cpu")
+		}
+		if limits.MemoryMB > 0 && usage.MemoryMB > limits.MemoryMB {
+			usage.OverBudget = append(usage.OverBudget, "memory")
+		}
+	}
+	return usage, true, nil
+}
+
+// checkResources adds the session's resource usage to a doctor result,
+// warning when it exceeds the agent budget. Usage is informational: failing
+// to measure it only skips it.
+func (h *Handler) checkResources(result *DoctorResult, status PieceStatus) {
+	var limits initcmd.AgentLimits
+	if cfg, err := ReadConfig(status.RepoRoot, h.deps.FS); err == nil {
+		limits = cfg.Agent.Limits
+	}
+	usage, running, err := h.sessionUsage(status.PieceName, limits)
+	if err != nil || !running {
+		return
+	}
+	result.Resources = &usage
+	if len(usage.OverBudget) > 0 {
+		h.deps.Output.Write(core.Message{
+			Type: core.MsgWarning,
+			Content: fmt.Sprintf("Session %s is over its agent budget (%s): %.0f%% CPU, %d MB memory",
+				SessionName(status.PieceName), strings.Join(usage.OverBudget, ", "), usage.CPUPercent, usage.MemoryMB),
+			Data: usage,
+		})
+	}
+}
+
+// sumProcessTree adds up the `ps -o pid=,ppid=,rss=,pcpu=` rows of roots and
+// their descendants
+func sumProcessTree(psOutput string, roots []int) ResourceUsage {
+	type process struct {
+		rssKB  int
+		cpuPct float64
+	}
+	processes := make(map[int]process)
+	children := make(map[int][]int)
+	for _, line := range strings.Split(psOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		rss, err3 := strconv.Atoi(fields[2])
+		cpu, err4 := strconv.ParseFloat(fields[3], 64)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		processes[pid] = process{rssKB: rss, cpuPct: cpu}
+		children[ppid] = append(children[ppid], pid)
+	}
+
+	var usage ResourceUsage
+	var rssKB int
+	seen := make(map[int]bool)
+	queue := append([]int{}, roots...)
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		p, ok := processes[pid]
+		if !ok || seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		usage.Processes++
+		usage.CPUPercent += p.cpuPct
+		rssKB += p.rssKB
+		queue = append(queue, children[pid]...)
+	}
+	usage.MemoryMB = rssKB / 1024
+	return usage
+}
@@ -0,0 +1,86 @@
+package piece_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestWrapAgentCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   initcmd.AgentLimits
+		platform piece.Platform
+		want     string
+		warnings int
+	}{
+		{"no limits", initcmd.AgentLimits{}, piece.Platform{GOOS: "linux"}, "claude -p", 0},
+		{"cgroups", initcmd.AgentLimits{CPUPercent: 150, MemoryMB: 2048, Nice: 10}, piece.Platform{GOOS: "linux", SystemdRun: true},
+			"systemd-run --user --scope --quiet -p CPUQuota=150% -p MemoryMax=2048M -- nice -n 10 sh -c 'claude -p'", 0},
+		{"ulimit fallback", initcmd.AgentLimits{CPUPercent: 150, MemoryMB: 2048}, piece.Platform{GOOS: "linux"},
+			"sh -c 'ulimit -v 2097152 && exec claude -p'", 1},
+		{"macOS", initcmd.AgentLimits{CPUPercent: 50, MemoryMB: 2048}, piece.Platform{GOOS: "darwin"},
+			"taskpolicy -b sh -c 'claude -p'", 1},
+		{"nice only", initcmd.AgentLimits{Nice: 5}, piece.Platform{GOOS: "windows"}, "nice -n 5 sh -c 'claude -p'", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings := piece.WrapAgentCommand("claude -p", tt.limits, tt.platform)
+			if got != tt.want || len(warnings) != tt.warnings {
+				t.Errorf("expected %q with %d warnings, got %q %v", tt.want, tt.warnings, got, warnings)
+			}
+		})
+	}
+}
+
+func TestValidateAgentLimits(t *testing.T) {
+	if err := piece.ValidateAgentLimits(initcmd.AgentLimits{CPUPercent: 100, MemoryMB: 512, Nice: 19}); err != nil {
+		t.Errorf("expected valid limits, got %v", err)
+	}
+	if err := piece.ValidateAgentLimits(initcmd.AgentLimits{Nice: 20}); err == nil {
+		t.Error("expected nice 20 to be rejected")
+	}
+}
+
+func TestHandler_Doctor_ReportsSessionResources(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+	setupDivergedPiece(mockExec)
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1","agent":{"command":"claude -p","limits":{"memory_mb":1024}}}`), 0644)
+
+	mockExec.AddResponse("tmux", []string{"has-session", "-t", "=mp-piece-p1"}, nil, nil)
+	mockExec.AddResponse("tmux", []string{"list-panes", "-s", "-t", "=mp-piece-p1", "-F", "#{pane_pid}"}, []byte("100\n200\n"), nil)
+	// 100 runs the agent (101) which runs a test (102); 300 is outside the session
+	ps := "  100     1   4096  0.0\n  101   100 1048576 85.5\n  102   101 524288 12.0\n  200     1   2048  0.0\n  300     1 999999 99.0\n"
+	mockExec.AddResponse("ps", []string{"-A", "-o", "pid=,ppid=,rss=,pcpu="}, []byte(ps), nil)
+
+	result, err := handler.Doctor("/pieces/p1", piece.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+	usage := result.Resources
+	if usage == nil || usage.Processes != 4 || usage.MemoryMB != 1542 || usage.CPUPercent != 97.5 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	if !slices.Equal(usage.OverBudget, []string{"memory"}) {
+		t.Errorf("expected the memory budget to be exceeded, got %v", usage.OverBudget)
+	}
+}
+
+func TestHandler_Doctor_NoSessionNoResources(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	setupDivergedPiece(mockExec)
+
+	result, err := handler.Doctor("/pieces/p1", piece.DoctorOptions{})
+	if err != nil || result.Resources != nil {
+		t.Errorf("expected no resources without a session, got %+v (%v)", result, err)
+	}
+}
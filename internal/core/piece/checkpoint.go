@@ -0,0 +1,199 @@
+package piece
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+const (
+	// CheckpointPrefix starts the subject of checkpoint commits
+	CheckpointPrefix = "wip:"
+	// CheckpointWindow is the tmux window of a piece's session that runs
+	// auto-checkpoints when pieces.auto_checkpoint_minutes is set
+	CheckpointWindow = "checkpoint"
+	// autoCheckpointMessage is the message of auto-checkpoints
+	autoCheckpointMessage = "auto-checkpoint"
+)
+
+// CheckpointResult is the outcome of a checkpoint
+type CheckpointResult struct {
+	Piece string `json:"piece"`
+	// Committed is false when there was nothing to commit
+	Committed bool   `json:"committed"`
+	Commit    string `json:"commit,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// IsCheckpoint reports whether a commit subject is that of a checkpoint
+func IsCheckpoint(subject string) bool {
+	return strings.HasPrefix(subject, CheckpointPrefix)
+}
+
+// Checkpoint commits all uncommitted work in the piece's worktree, untracked
+// files included, to the piece branch as "wip: <message>", so it survives a
+// crashed session or a lost worktree. Commit hooks are skipped, as the work
+// is unfinished. A clean worktree is left as it is. Fails during a rebase,
+// merge, cherry-pick, revert or bisect, where a commit would interfere.
+func (h *Handler) Checkpoint(workDir, message string) (CheckpointResult, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return CheckpointResult{}, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return CheckpointResult{}, core.NewNotInPieceError()
+	}
+	worktree := status.WorktreePath
+	result := CheckpointResult{Piece: status.PieceName}
+
+	if err := h.checkNoOperation(worktree); err != nil {
+		return CheckpointResult{}, err
+	}
+
+	dirty, err := h.git.HasUncommittedChanges(worktree)
+	if err != nil {
+		return CheckpointResult{}, err
+	}
+	if !dirty {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgInfo,
+			Content: "Nothing to checkpoint",
+			Data:    result,
+		})
+		return result, nil
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		message = "checkpoint " + time.Now().Format("2006-01-02 15:04")
+	}
+	result.Message = CheckpointPrefix + " " + message
+	if err := h.git.CommitAll(worktree, result.Message); err != nil {
+		return CheckpointResult{}, err
+	}
+	result.Committed = true
+	if commit, err := h.git.GetBranchCommit(worktree, "HEAD"); err == nil {
+		result.Commit = commit
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Checkpointed %s: %s", shortCommit(result.Commit), result.Message),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// checkNoOperation fails when a rebase, merge, cherry-pick, revert or bisect
+// is in progress in the worktree, where committing to its branch would
+// interfere
+func (h *Handler) checkNoOperation(worktree string) error {
+	gitDir, err := h.git.RevParseGitDir(worktree)
+	if err != nil {
+		return err
+	}
+	for _, marker := range inProgressMarkers {
+		if _, err := h.deps.FS.Stat(filepath.Join(gitDir, marker.file)); err == nil {
+			return core.NewOperationInProgressError(marker.operation, worktree)
+		}
+	}
+	if _, err := h.deps.FS.Stat(filepath.Join(gitDir, "BISECT_LOG")); err == nil {
+		return fmt.Errorf("a bisect is in progress in %s; end it with git bisect reset", worktree)
+	}
+	return nil
+}
+
+// CheckpointInterval returns the repository's pieces.auto_checkpoint_minutes
+// as a duration, 0 when auto-checkpoints are off
+func (h *Handler) CheckpointInterval(repoRoot string) time.Duration {
+	cfg, err := ReadConfig(repoRoot, h.deps.FS)
+	if err != nil || cfg.Pieces.AutoCheckpointMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.Pieces.AutoCheckpointMinutes) * time.Minute
+}
+
+// AutoCheckpoint checkpoints the piece at workDir every interval until ctx
+// is done. Failed checkpoints are reported as warnings and retried at the
+// next interval.
+func (h *Handler) AutoCheckpoint(ctx context.Context, workDir string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("checkpoint interval must be positive, got %s", interval)
+	}
+	status, err := h.Status(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return core.NewNotInPieceError()
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgInfo,
+		Content: fmt.Sprintf("Checkpointing %s every %s", status.PieceName, interval),
+	})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := h.Checkpoint(workDir, autoCheckpointMessage); err != nil {
+				h.deps.Output.Write(core.Message{
+					Type:    core.MsgWarning,
+					Content: fmt.Sprintf("Checkpoint failed: %v", err),
+				})
+			}
+		}
+	}
+}
+
+// openCheckpointWindow opens the tmux window running auto-checkpoints of a
+// new piece, when the repository configures them
+func (h *Handler) openCheckpointWindow(repoRoot, sessionName, worktreePath string) {
+	if h.CheckpointInterval(repoRoot) == 0 {
+		return
+	}
+	err := h.tmux.NewWindow(sessionName, CheckpointWindow, worktreePath)
+	if err == nil {
+		err = h.tmux.SendKeys(sessionName+":"+CheckpointWindow, "mp piece checkpoint --watch")
+	}
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to open tmux window %s: %v", CheckpointWindow, err),
+		})
+	}
+}
+
+// squashedCommitList lists the subjects of the squashed commits for a squash
+// commit message. With squashCheckpoints, each run of consecutive checkpoints
+// is collapsed into a single line.
+func squashedCommitList(commits []adapters.Commit, squashCheckpoints bool) []string {
+	var lines []string
+	run := 0
+	flush := func() {
+		if run == 1 {
+			lines = append(lines, CheckpointPrefix+" 1 checkpoint")
+		} else if run > 1 {
+			lines = append(lines, fmt.Sprintf("%s %d checkpoints", CheckpointPrefix, run))
+		}
+		run = 0
+	}
+	for _, commit := range commits {
+		if squashCheckpoints && IsCheckpoint(commit.Subject) {
+			run++
+			continue
+		}
+		flush()
+		lines = append(lines, commit.Subject)
+	}
+	flush()
+	return lines
+}
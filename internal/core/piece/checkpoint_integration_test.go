@@ -0,0 +1,47 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_Checkpoint_CommitsUntrackedWork(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	// Checkpoints skip commit hooks, which may reject unfinished work
+	hook := filepath.Join(clone, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "draft.txt"), []byte("half done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := newOSHandler()
+	result, err := handler.Checkpoint(worktree, "drafting")
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if !result.Committed || result.Message != "wip: drafting" {
+		t.Errorf("expected a wip: drafting checkpoint, got %+v", result)
+	}
+	if got := server.Git(worktree, "log", "-1", "--format=%s", "piece-1"); got != "wip: drafting" {
+		t.Errorf("expected the checkpoint on piece-1, got %q", got)
+	}
+	if got := server.Git(worktree, "status", "--porcelain"); got != "" {
+		t.Errorf("expected a clean worktree after the checkpoint, got %q", got)
+	}
+
+	result, err = handler.Checkpoint(worktree, "")
+	if err != nil {
+		t.Fatalf("Checkpoint of a clean worktree failed: %v", err)
+	}
+	if result.Committed {
+		t.Errorf("expected nothing to checkpoint, got %+v", result)
+	}
+}
@@ -0,0 +1,92 @@
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_Checkpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		setup    func(fs *adapters.MemoryFS, mockExec *adapters.MockExec)
+		want     piece.CheckpointResult
+		wantCode string
+	}{
+		{
+			name:    "dirty",
+			message: "  login form renders ",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				mockExec.AddResponse("git", []string{"status", "--porcelain"}, []byte("?? login.go\n"), nil)
+				mockExec.AddResponse("git", []string{"add", "-A"}, nil, nil)
+				mockExec.AddResponse("git", []string{"commit", "--no-verify", "-m", "wip: login form renders"}, nil, nil)
+				mockExec.AddResponse("git", []string{"rev-parse", "HEAD"}, []byte("abc1234def\n"), nil)
+			},
+			want: piece.CheckpointResult{Piece: "piece-1", Committed: true, Commit: "abc1234def", Message: "wip: login form renders"},
+		},
+		{
+			name: "clean",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+			},
+			want: piece.CheckpointResult{Piece: "piece-1"},
+		},
+		{
+			name: "operation in progress",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				_ = fs.MkdirAll("/repo/.git/worktrees/piece-1/rebase-merge", 0755)
+			},
+			wantCode: core.CodeOperationInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _, mockExec, handler := setupMockPiece(t, "main")
+			tt.setup(fs, mockExec)
+
+			result, err := handler.Checkpoint("/pieces/piece-1", tt.message)
+			if staged := mockExec.WasCalled("git", "add", "-A"); staged != tt.want.Committed {
+				t.Errorf("expected changes staged: %v, got %v", tt.want.Committed, staged)
+			}
+			if tt.wantCode != "" {
+				if re, ok := core.AsRemediable(err); !ok || re.Code != tt.wantCode {
+					t.Fatalf("expected %s error, got %v", tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Checkpoint failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("result = %+v, want %+v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_MergePiece_SquashCheckpoints(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockCommitLog(mockExec, "main..piece-1",
+		adapters.Commit{Subject: "wip: auto-checkpoint"},
+		adapters.Commit{Subject: "feat: login form"},
+		adapters.Commit{Subject: "wip: auto-checkpoint"},
+		adapters.Commit{Subject: "wip: form renders"},
+		adapters.Commit{Subject: "feat: login route"},
+	)
+	mockMainCheckout(mockExec, "main")
+	mockExec.AddResponse("git", []string{"checkout", "main"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge", "--squash", "piece-1"}, nil, nil)
+	commitMsg := "feat: piece-1\n\nSquashed commits:\n- wip: 1 checkpoint\n- feat: login form\n- wip: 2 checkpoints\n- feat: login route\n"
+	mockExec.AddResponse("git", []string{"commit", "-m", commitMsg}, nil, nil)
+
+	if err := handler.MergePieceWithOptions("/pieces/piece-1", "main", piece.MergeOptions{SquashCheckpoints: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !mockExec.WasCalled("git", "commit", "-m", commitMsg) {
+		t.Error("expected runs of checkpoints collapsed in the squash commit message")
+	}
+}
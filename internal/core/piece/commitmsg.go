@@ -0,0 +1,179 @@
+package piece
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+const (
+	// CommitMsgHook is the git hook that prefixes commit messages in pieces
+	// with the issue short ID. It runs once the message is written, so empty
+	// messages still abort the commit.
+	CommitMsgHook = "commit-msg"
+	// commitMsgHookMarker identifies the hook mp wrote, so it can be rewritten
+	commitMsgHookMarker = "# Installed by mp piece commit-hook"
+)
+
+// commitMsgHookScript is the commit-msg hook; it does nothing where mp is not
+// installed
+const commitMsgHookScript = `#!/bin/sh
+` + commitMsgHookMarker + `: prefixes commit messages in pieces
+# with the short ID of the piece's issue, e.g. "[add-login] Add login form".
+command -v mp >/dev/null 2>&1 || exit 0
+exec mp piece commit-msg "$1"
+`
+
+// IssuePrefix returns the prefix of commit messages in pieces of the issue
+// with the short ID id, e.g. "[add-login] "
+func IssuePrefix(id string) string {
+	return "[" + id + "] "
+}
+
+// CommitHookResult describes the installed commit-msg hook
+type CommitHookResult struct {
+	Path string `json:"path"`
+	// Written is false when the hook was already installed
+	Written bool `json:"written"`
+}
+
+// InstallCommitHook writes the commit-msg hook to the hooks directory
+// of the repository containing workDir, shared by all its worktrees. A hook of
+// another origin is left alone.
+func (h *Handler) InstallCommitHook(workDir string) (CommitHookResult, error) {
+	hooksDir, err := h.git.HooksDir(workDir)
+	if err != nil {
+		return CommitHookResult{}, err
+	}
+	result := CommitHookResult{Path: filepath.Join(hooksDir, CommitMsgHook)}
+
+	existing, err := h.deps.FS.ReadFile(result.Path)
+	switch {
+	case err == nil && string(existing) == commitMsgHookScript:
+		h.deps.Output.Write(core.Message{Type: core.MsgInfo, Content: fmt.Sprintf("%s is already installed", result.Path), Data: result})
+		return result, nil
+	case err == nil && !strings.Contains(string(existing), commitMsgHookMarker):
+		return CommitHookResult{}, fmt.Errorf("%s exists and was not written by mp; add 'mp piece commit-msg \"$1\"' to it instead", result.Path)
+	case err != nil && !os.IsNotExist(err):
+		return CommitHookResult{}, fmt.Errorf("failed to read %s: %w", result.Path, err)
+	}
+
+	if err := h.deps.FS.MkdirAll(hooksDir, DefaultDirPerm); err != nil {
+		return CommitHookResult{}, fmt.Errorf("failed to create %s: %w", hooksDir, err)
+	}
+	if err := h.deps.FS.WriteFile(result.Path, []byte(commitMsgHookScript), 0755); err != nil {
+		return CommitHookResult{}, fmt.Errorf("failed to write %s: %w", result.Path, err)
+	}
+	result.Written = true
+	h.deps.Output.Write(core.Message{Type: core.MsgSuccess, Content: fmt.Sprintf("Installed %s", result.Path), Data: result})
+	return result, nil
+}
+
+// PrefixCommitMessage is the commit-msg hook: it prefixes the subject of the
+// commit message in the file at path with the short ID of the issue linked to
+// the piece containing workDir. Merge commits are left alone, as are
+// messages without a subject, which git aborts, checkpoints, fixup!, squash!
+// and amend! commits, messages already prefixed, and commits outside pieces or
+// in pieces without an issue. Reports whether the message was changed.
+func (h *Handler) PrefixCommitMessage(workDir, path string) (bool, error) {
+	status, err := h.Status(workDir)
+	if err != nil || !status.InPiece {
+		return false, nil
+	}
+	marker, err := OpenMetadataStore(h.deps, status.WorktreePath).ReadIssueMarker()
+	if err != nil || marker.IssuePath == "" {
+		return false, nil
+	}
+	gitDir, err := h.git.RevParseGitDir(status.WorktreePath)
+	if err != nil {
+		return false, err
+	}
+	if _, err := h.deps.FS.Stat(filepath.Join(gitDir, "MERGE_HEAD")); err == nil {
+		return false, nil
+	}
+
+	data, err := h.deps.FS.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read commit message: %w", err)
+	}
+	prefix := IssuePrefix(IssueID(marker.IssuePath))
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, line := range lines {
+		subject := strings.TrimSpace(line)
+		if subject == "" || strings.HasPrefix(subject, "#") {
+			continue
+		}
+		if !needsIssuePrefix(subject, prefix) {
+			return false, nil
+		}
+		lines[i] = prefix + strings.TrimLeft(line, " \t")
+		if err := h.deps.FS.WriteFile(path, []byte(strings.Join(lines, "")), DefaultFilePerm); err != nil {
+			return false, fmt.Errorf("failed to write commit message: %w", err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// autosquashPrefixes start the subjects git commit --fixup and --squash
+// write, which git rebase --autosquash matches against the target's subject
+var autosquashPrefixes = []string{"fixup! ", "squash! ", "amend! "}
+
+// needsIssuePrefix reports whether a commit subject should carry prefix:
+// checkpoints and autosquash commits don't, and prefixed subjects have it
+func needsIssuePrefix(subject, prefix string) bool {
+	if IsCheckpoint(subject) || strings.HasPrefix(subject, strings.TrimSpace(prefix)) {
+		return false
+	}
+	for _, p := range autosquashPrefixes {
+		if strings.HasPrefix(subject, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyIssuePrefix checks, with pieces.issue_prefix set, that the piece's
+// commits other than checkpoints, autosquash and merge commits start with the
+// prefix of its issue. Reports false when there is nothing to check.
+func (h *Handler) verifyIssuePrefix(status PieceStatus) (VerifyResult, bool, error) {
+	cfg, err := ReadConfig(status.RepoRoot, h.deps.FS)
+	if err != nil || !cfg.Pieces.IssuePrefix {
+		return VerifyResult{}, false, nil
+	}
+	marker, err := OpenMetadataStore(h.deps, status.WorktreePath).ReadIssueMarker()
+	if err != nil || marker.IssuePath == "" {
+		return VerifyResult{}, false, nil
+	}
+	prefix := strings.TrimSpace(IssuePrefix(IssueID(marker.IssuePath)))
+
+	base := h.BaseBranch(status.WorktreePath, "main")
+	commits, err := h.git.CommitLog(status.WorktreePath, base, "HEAD")
+	if err != nil {
+		return VerifyResult{}, false, err
+	}
+	// Merge commits, e.g. from mp piece update, are never prefixed
+	merges, err := h.git.MergeCommits(status.WorktreePath, base, "HEAD")
+	if err != nil {
+		return VerifyResult{}, false, err
+	}
+	var missing []string
+	for _, c := range commits {
+		if slices.Contains(merges, c.Hash) {
+			continue
+		}
+		if needsIssuePrefix(c.Subject, prefix) {
+			missing = append(missing, shortCommit(c.Hash)+" "+c.Subject)
+		}
+	}
+	result := VerifyResult{Command: "issue prefix " + prefix, Passed: len(missing) == 0}
+	if !result.Passed {
+		result.Output = fmt.Sprintf("commits without the prefix %s (reword them with git rebase -i %s):\n%s",
+			prefix, base, strings.Join(missing, "\n"))
+	}
+	return result, true, nil
+}
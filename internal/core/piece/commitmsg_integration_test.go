@@ -0,0 +1,71 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_InstallCommitHook_SharedByWorktrees(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+
+	result, err := newOSHandler().InstallCommitHook(worktree)
+	if err != nil {
+		t.Fatalf("InstallCommitHook failed: %v", err)
+	}
+	want, _ := filepath.EvalSymlinks(filepath.Join(clone, ".git", "hooks", "commit-msg"))
+	if got, _ := filepath.EvalSymlinks(result.Path); got != want || !result.Written {
+		t.Errorf("expected the hook written to %s, got %+v", want, result)
+	}
+	if info, err := os.Stat(result.Path); err != nil || info.Mode()&0111 == 0 {
+		t.Errorf("expected an executable hook, got %v, %v", info, err)
+	}
+
+	if result, err := newOSHandler().InstallCommitHook(clone); err != nil || result.Written {
+		t.Errorf("expected the installed hook to be kept, got %+v, %v", result, err)
+	}
+
+	if err := os.WriteFile(result.Path, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newOSHandler().InstallCommitHook(clone); err == nil || !strings.Contains(err.Error(), "not written by mp") {
+		t.Errorf("expected another hook to be left alone, got %v", err)
+	}
+}
+
+func TestIntegration_IssuePrefix_MergesAfterUpdate(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	server.Git(worktree, "commit", "--amend", "-m", "[add-login] add feature")
+	if err := os.MkdirAll(filepath.Join(clone, ".monkeypuzzle"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The events log mp writes is not tracked
+	server.Commit(clone, ".monkeypuzzle/.gitignore", "events.jsonl\n", "ignore mp state")
+	server.Commit(clone, ".monkeypuzzle/monkeypuzzle.json", `{"version":"1","pieces":{"issue_prefix":true}}`, "require issue prefixes")
+	deps := core.Deps{FS: adapters.NewOSFS(""), Output: adapters.NewBufferOutput(), Exec: adapters.NewOSExec()}
+	if err := piece.OpenMetadataStore(deps, worktree).WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/add-login.md", PieceName: "piece-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := newOSHandler()
+	if err := handler.UpdatePiece(worktree, "main"); err != nil {
+		t.Fatalf("UpdatePiece failed: %v", err)
+	}
+	report, err := handler.VerifyPiece(worktree)
+	if err != nil || !report.Passed || len(report.Results) != 1 {
+		t.Fatalf("expected the update's merge commit to pass the prefix check, got %+v, %v", report, err)
+	}
+	if err := handler.MergePiece(worktree, "main"); err != nil {
+		t.Fatalf("MergePiece failed: %v", err)
+	}
+}
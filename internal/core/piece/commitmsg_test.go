@@ -0,0 +1,71 @@
+package piece_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_PrefixCommitMessage(t *testing.T) {
+	fs, _, _, handler := setupMockPiece(t, "main")
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/add-login.md", PieceName: "piece-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		message, want string
+	}{
+		{"Add login form\n", "[add-login] Add login form\n"},
+		{"\nAdd login form\n# Please enter the commit message\n", "\n[add-login] Add login form\n# Please enter the commit message\n"},
+		{"\n# Please enter the commit message\n", "\n# Please enter the commit message\n"},
+		{"[add-login] Add login form\n", "[add-login] Add login form\n"},
+		{"wip: auto-checkpoint\n", "wip: auto-checkpoint\n"},
+		{"fixup! [add-login] Add login form\n", "fixup! [add-login] Add login form\n"},
+	} {
+		path := "/pieces/piece-1/.git/COMMIT_EDITMSG"
+		_ = fs.WriteFile(path, []byte(tc.message), 0644)
+		changed, err := handler.PrefixCommitMessage("/pieces/piece-1", path)
+		if err != nil {
+			t.Fatalf("PrefixCommitMessage(%q) failed: %v", tc.message, err)
+		}
+		got, _ := fs.ReadFile(path)
+		if string(got) != tc.want || changed != (tc.message != tc.want) {
+			t.Errorf("PrefixCommitMessage(%q) = %q (changed %v), want %q", tc.message, got, changed, tc.want)
+		}
+	}
+
+	// Merge commits are left alone
+	_ = fs.WriteFile("/repo/.git/worktrees/piece-1/MERGE_HEAD", []byte("abc123\n"), 0644)
+	_ = fs.WriteFile("/pieces/piece-1/.git/COMMIT_EDITMSG", []byte("Merge branch 'main'\n"), 0644)
+	if changed, err := handler.PrefixCommitMessage("/pieces/piece-1", "/pieces/piece-1/.git/COMMIT_EDITMSG"); err != nil || changed {
+		t.Errorf("expected a merge commit message left alone, got %v, %v", changed, err)
+	}
+}
+
+func TestHandler_VerifyPiece_IssuePrefix(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1","pieces":{"issue_prefix":true}}`), 0644)
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/add-login.md", PieceName: "piece-1"}); err != nil {
+		t.Fatal(err)
+	}
+	mockCommitLog(mockExec, "main..HEAD",
+		adapters.Commit{Subject: "fix typo"},
+		adapters.Commit{Subject: "wip: auto-checkpoint"},
+		adapters.Commit{Subject: "[add-login] Add login form"})
+	mockExec.AddResponse("git", []string{"rev-list", "--merges", "main..HEAD"}, nil, nil)
+
+	report, err := handler.VerifyPiece("/pieces/piece-1")
+	if err != nil {
+		t.Fatalf("VerifyPiece failed: %v", err)
+	}
+	if report.Passed || len(report.Results) != 1 {
+		t.Fatalf("expected the prefix check to fail, got %+v", report)
+	}
+	if output := report.Results[0].Output; !strings.Contains(output, "fix typo") || strings.Contains(output, "checkpoint") {
+		t.Errorf("expected only the unprefixed commit listed, got:\n%s", output)
+	}
+}
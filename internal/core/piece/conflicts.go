@@ -0,0 +1,78 @@
+package piece
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// ConflictCheck predicts the outcome of merging the base branch into a piece
+type ConflictCheck struct {
+	PieceName  string   `json:"piece_name"`
+	Branch     string   `json:"branch"`
+	BaseBranch string   `json:"base_branch"`
+	Conflicts  []string `json:"conflicts"`
+}
+
+// HasConflicts reports whether the merge would stop with conflicts
+func (c ConflictCheck) HasConflicts() bool {
+	return len(c.Conflicts) > 0
+}
+
+// CheckConflicts predicts whether `mp piece update` would conflict, listing the
+// conflicting files. Nothing in the worktree or index is changed.
+func (h *Handler) CheckConflicts(workDir, mainBranch string) (*ConflictCheck, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
+	}
+
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+
+	branch, err := h.git.CurrentBranch(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	check, err := h.checkConflicts(workDir, status.PieceName, branch, mainBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	if check.HasConflicts() {
+		h.deps.Output.Write(core.Message{
+			Type: core.MsgWarning,
+			Content: fmt.Sprintf("Merging %s into %s would conflict in %d file(s): %s",
+				mainBranch, branch, len(check.Conflicts), strings.Join(check.Conflicts, ", ")),
+			Data: check,
+		})
+	} else {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgSuccess,
+			Content: fmt.Sprintf("%s merges cleanly into %s", mainBranch, branch),
+			Data:    check,
+		})
+	}
+
+	return check, nil
+}
+
+// checkConflicts runs the conflict prediction without reporting it
+func (h *Handler) checkConflicts(workDir, pieceName, branch, mainBranch string) (*ConflictCheck, error) {
+	conflicts, err := h.git.MergeConflicts(workDir, branch, mainBranch)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts == nil {
+		conflicts = []string{}
+	}
+	return &ConflictCheck{
+		PieceName:  pieceName,
+		Branch:     branch,
+		BaseBranch: mainBranch,
+		Conflicts:  conflicts,
+	}, nil
+}
@@ -0,0 +1,39 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_MergeConflicts(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	server.Git(clone, "checkout", "-b", "feature")
+	server.Commit(clone, "README.md", "feature\n", "feature readme")
+	server.Commit(clone, "feature.txt", "one\n", "add feature")
+	server.Git(clone, "checkout", "main")
+	server.Commit(clone, "README.md", "main\n", "main readme")
+	server.Commit(clone, "other.txt", "two\n", "add other")
+	head := server.Git(clone, "rev-parse", "feature")
+
+	git := adapters.NewGit(adapters.NewOSExec())
+	conflicts, err := git.MergeConflicts(clone, "feature", "main")
+	if err != nil {
+		t.Fatalf("MergeConflicts failed: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "README.md" {
+		t.Fatalf("expected README.md to conflict, got %v", conflicts)
+	}
+	if got := server.Git(clone, "rev-parse", "feature"); got != head {
+		t.Error("expected the branch to be untouched by the check")
+	}
+
+	conflicts, err = git.MergeConflicts(clone, "feature", "feature")
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts merging a branch with itself, got %v (%v)", conflicts, err)
+	}
+}
@@ -0,0 +1,93 @@
+package piece_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+const conflictTreeID = "197325a85118318dd343458c41c88c6088aa733c"
+
+// setupConflictPiece mocks piece p1 at /pieces/p1
+func setupConflictPiece(mockExec *adapters.MockExec) {
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/pieces/p1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("p1\n"), nil)
+}
+
+func TestHandler_CheckConflicts(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: out, Exec: mockExec})
+	setupConflictPiece(mockExec)
+	mockExec.AddResponse("git", []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", "p1", "main"},
+		[]byte(conflictTreeID+"\nREADME.md\nsrc/app.go\nREADME.md\n"), errors.New("exit status 1"))
+
+	check, err := handler.CheckConflicts("/pieces/p1", "main")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !check.HasConflicts() || len(check.Conflicts) != 2 || check.Conflicts[0] != "README.md" || check.Conflicts[1] != "src/app.go" {
+		t.Errorf("expected README.md and src/app.go to conflict, got %+v", check)
+	}
+	if !out.HasWarning() {
+		t.Error("expected warning listing conflicts")
+	}
+	if mockExec.WasCalled("git", "merge", "main") {
+		t.Error("expected no merge when only checking")
+	}
+}
+
+func TestHandler_CheckConflicts_Clean(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: out, Exec: mockExec})
+	setupConflictPiece(mockExec)
+	mockExec.AddResponse("git", []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", "p1", "main"},
+		[]byte(conflictTreeID+"\n"), nil)
+
+	check, err := handler.CheckConflicts("/pieces/p1", "main")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if check.HasConflicts() || check.Conflicts == nil {
+		t.Errorf("expected an empty conflict list, got %+v", check)
+	}
+	if !out.HasSuccess() {
+		t.Error("expected success message")
+	}
+}
+
+func TestHandler_CheckConflicts_MissingBranch(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	setupConflictPiece(mockExec)
+	mockExec.AddResponse("git", []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", "p1", "trunk"},
+		[]byte("merge-tree: trunk - not something we can merge\n"), errors.New("exit status 1"))
+
+	_, err := handler.CheckConflicts("/pieces/p1", "trunk")
+	var remediable *core.RemediableError
+	if !errors.As(err, &remediable) || remediable.Code != core.CodeMainBranchMissing {
+		t.Fatalf("expected main branch missing error, got %v", err)
+	}
+}
+
+func TestHandler_Doctor_ReportsConflicts(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	setupDivergedPiece(mockExec)
+	mockExec.AddResponse("git", []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", "p1", "main"},
+		[]byte(conflictTreeID+"\ngo.mod\n"), errors.New("exit status 1"))
+
+	result, err := handler.Doctor("/pieces/p1", piece.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Conflicts == nil || len(result.Conflicts.Conflicts) != 1 || result.Conflicts.BaseBranch != "main" {
+		t.Errorf("expected go.mod conflict against main, got %+v", result.Conflicts)
+	}
+}
@@ -0,0 +1,161 @@
+package piece
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
+)
+
+// EventIssueRollback is logged when an abandoned piece's issue is reverted to todo
+const EventIssueRollback = "issue.rollback"
+
+// DeleteOptions configures piece deletion
+type DeleteOptions struct {
+	Force bool // Remove the worktree even if it has uncommitted changes
+}
+
+// DeleteResult contains information about a deleted piece
+type DeleteResult struct {
+	PieceName       string `json:"piece_name"`
+	WorktreePath    string `json:"worktree_path"`
+	IssuePath       string `json:"issue_path,omitempty"`
+	IssueRolledBack bool   `json:"issue_rolled_back,omitempty"`
+}
+
+// DeletePiece abandons a piece: it removes the worktree and tmux session and,
+// unless the piece's PR was merged or its branch is already in its base branch,
+// reverts its in-progress issue to todo and removes the PR link from the
+// issue's frontmatter. When the merge status can't be checked, the issue is
+// left alone.
+func (h *Handler) DeletePiece(repoRoot, pieceName string, opts DeleteOptions) (DeleteResult, error) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return DeleteResult{}, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+
+	worktreePath := filepath.Join(piecesDir, pieceName)
+	if _, err := h.deps.FS.Stat(worktreePath); err != nil {
+		return DeleteResult{}, fmt.Errorf("piece %s not found at %s", pieceName, worktreePath)
+	}
+
+	// A locked piece is refused before its session is killed
+	if err := h.checkNotLocked(repoRoot, piecesDir, pieceName); err != nil {
+		return DeleteResult{}, err
+	}
+
+	result := DeleteResult{PieceName: pieceName, WorktreePath: worktreePath}
+
+	// Read piece metadata before the worktree's git dir is removed
+	if marker, err := h.readCurrentIssueMarker(worktreePath); err == nil {
+		result.IssuePath = marker.IssuePath
+	}
+	merged, known := h.abandonedPieceMerged(repoRoot, pieceName, worktreePath)
+
+	op := operation.New(h.deps.Output, "delete piece "+pieceName)
+	h.killPieceSession(op, pieceName, worktreePath)
+
+	removeWorktree := h.git.WorktreeRemove
+	if opts.Force {
+		removeWorktree = h.git.WorktreeRemoveForce
+	}
+	if err := removeWorktree(repoRoot, worktreePath); err != nil {
+		return DeleteResult{}, op.Fail(fmt.Errorf("failed to remove worktree (use --force to discard uncommitted changes): %w", err))
+	}
+	h.logPieceEvent(repoRoot, EventPieceRemove, pieceName, map[string]any{"reason": RemoveReasonDelete})
+
+	if result.IssuePath != "" && known && !merged {
+		result.IssueRolledBack = h.rollbackAbandonedIssue(repoRoot, pieceName, result.IssuePath,
+			"piece deleted without a merged PR")
+		// The unmerged PR no longer implements the issue
+		if err := ClearPRLink(filepath.Join(repoRoot, result.IssuePath), h.deps.FS); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to remove PR link from %s: %v", result.IssuePath, err),
+			})
+		}
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Deleted piece: %s", pieceName),
+		Data:    result,
+	})
+
+	return result, nil
+}
+
+// abandonedPieceMerged reports whether a piece's work already landed: its PR
+// was merged, or its branch head is in its base branch (e.g. after mp piece
+// merge). known is false, after a warning, if that couldn't be checked.
+func (h *Handler) abandonedPieceMerged(repoRoot, pieceName, worktreePath string) (merged, known bool) {
+	merged, _, err := h.checkPRMergeStatus(worktreePath)
+	if err != nil && !errors.Is(err, errNoPRMetadata) {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Leaving the issue of %s unchanged: %v", pieceName, err),
+		})
+		return false, false
+	}
+	if merged {
+		return true, true
+	}
+
+	branchName, err := h.git.CurrentBranch(worktreePath)
+	if err == nil {
+		merged, err = h.checkCommitMerged(repoRoot, branchName, h.BaseBranch(worktreePath, "main"))
+	}
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Leaving the issue of %s unchanged: failed to check merge status: %v", pieceName, err),
+		})
+		return false, false
+	}
+	return merged, true
+}
+
+// rollbackAbandonedIssue reverts an in-progress issue to todo after its piece
+// was removed unmerged, and records why in the events log.
+// Returns true if the status was changed.
+func (h *Handler) rollbackAbandonedIssue(repoRoot, pieceName, relIssuePath, reason string) bool {
+	absIssuePath := filepath.Join(repoRoot, relIssuePath)
+	currentStatus, err := ParseStatus(absIssuePath, h.deps.FS)
+	if err != nil || currentStatus != StatusInProgress {
+		return false
+	}
+
+	if err := UpdateStatus(absIssuePath, StatusTodo, h.deps.FS); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to revert status of %s: %v", relIssuePath, err),
+		})
+		return false
+	}
+	h.SyncIssueTitles(repoRoot, relIssuePath)
+
+	if err := events.Append(h.deps.FS, repoRoot, events.Event{
+		Type:  EventIssueRollback,
+		Piece: pieceName,
+		Data: map[string]any{
+			"issue":  relIssuePath,
+			"from":   StatusInProgress,
+			"to":     StatusTodo,
+			"reason": reason,
+		},
+	}); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to log issue rollback: %v", err),
+		})
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgInfo,
+		Content: fmt.Sprintf("Reverted %s to %s: %s", relIssuePath, StatusTodo, reason),
+	})
+	return true
+}
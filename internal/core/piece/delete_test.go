@@ -0,0 +1,181 @@
+package piece_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+const deleteTestWorktree = "/test-data/monkeypuzzle/pieces/p1"
+
+// setupDeletePiece mocks piece p1 of repo /repo linked to an in-progress issue
+func setupDeletePiece(t *testing.T) (*adapters.MemoryFS, *adapters.MockExec, *piece.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_ = fs.MkdirAll(deleteTestWorktree, 0755)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/issues/feature.md", []byte("---\ntitle: Feature\nstatus: in-progress\n---\n"), 0644)
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/p1", deleteTestWorktree)
+	_ = store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/feature.md", IssueName: "Feature", PieceName: "p1"})
+
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockExec.AddResponse("tmux", []string{"kill-session", "-t", "mp-piece-p1"}, nil, nil)
+	mockExec.AddResponse("git", []string{"worktree", "remove", deleteTestWorktree}, nil, nil)
+	// The branch has not been merged locally
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("p1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "p1"}, []byte("bbb222\n"), nil)
+	mockExec.AddResponse("git", []string{"merge-base", "--is-ancestor", "bbb222", "main"}, nil, errors.New("exit status 1"))
+
+	return fs, mockExec, handler
+}
+
+func TestHandler_DeletePiece(t *testing.T) {
+	const linkedIssue = "---\ntitle: Feature\nstatus: in-progress\npr_number: 7\npr_url: https://github.com/owner/repo/pull/7\n---\n"
+
+	tests := []struct {
+		name         string
+		piece        string
+		opts         piece.DeleteOptions
+		setup        func(fs *adapters.MemoryFS, mockExec *adapters.MockExec)
+		wantErr      string
+		wantRollback bool
+		wantIssue    string
+		wantEvents   []string
+		wantCalled   []string
+	}{
+		{
+			name:         "rolls back the issue of an unmerged piece",
+			wantRollback: true,
+			wantIssue:    "---\ntitle: Feature\nstatus: todo\n---\n",
+			wantEvents:   []string{piece.EventPieceRemove, piece.EventIssueRollback},
+		},
+		{
+			name: "keeps the issue of a merged PR",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				_ = fs.WriteFile("repo/issues/feature.md", []byte(linkedIssue), 0644)
+				writeDeletePRMetadata(fs)
+				mockExec.AddResponse("gh", []string{"pr", "view", "7", "--json", "mergedAt"}, []byte(`{"mergedAt":"2025-01-01T00:00:00Z"}`), nil)
+			},
+			wantIssue:  linkedIssue,
+			wantEvents: []string{piece.EventPieceRemove},
+		},
+		{
+			name: "unlinks an unmerged PR",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				_ = fs.WriteFile("repo/issues/feature.md", []byte(linkedIssue), 0644)
+				writeDeletePRMetadata(fs)
+				mockExec.AddResponse("gh", []string{"pr", "view", "7", "--json", "mergedAt"}, []byte(`{"mergedAt":null}`), nil)
+			},
+			wantRollback: true,
+			wantIssue:    "---\ntitle: Feature\nstatus: todo\n---\n",
+			wantEvents:   []string{piece.EventPieceRemove, piece.EventIssueRollback},
+		},
+		{
+			name: "keeps the issue when gh fails",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				_ = fs.WriteFile("repo/issues/feature.md", []byte(linkedIssue), 0644)
+				writeDeletePRMetadata(fs)
+				mockExec.AddResponse("gh", []string{"pr", "view", "7", "--json", "mergedAt"}, nil, errors.New("gh: not logged in"))
+			},
+			wantIssue:  linkedIssue,
+			wantEvents: []string{piece.EventPieceRemove},
+		},
+		{
+			name: "keeps the issue of a locally merged branch",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				mockExec.AddResponse("git", []string{"merge-base", "--is-ancestor", "bbb222", "main"}, nil, nil)
+			},
+			wantIssue:  "---\ntitle: Feature\nstatus: in-progress\n---\n",
+			wantEvents: []string{piece.EventPieceRemove},
+		},
+		{
+			name: "forces removal",
+			opts: piece.DeleteOptions{Force: true},
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				mockExec.AddResponse("git", []string{"worktree", "remove", "--force", deleteTestWorktree}, nil, nil)
+			},
+			wantRollback: true,
+			wantIssue:    "---\ntitle: Feature\nstatus: todo\n---\n",
+			wantEvents:   []string{piece.EventPieceRemove, piece.EventIssueRollback},
+			wantCalled:   []string{"git worktree remove --force " + deleteTestWorktree},
+		},
+		{
+			name:      "unknown piece",
+			piece:     "missing",
+			wantErr:   "not found",
+			wantIssue: "---\ntitle: Feature\nstatus: in-progress\n---\n",
+		},
+		{
+			name: "restarts the session when removal fails",
+			setup: func(fs *adapters.MemoryFS, mockExec *adapters.MockExec) {
+				mockExec.AddResponse("git", []string{"worktree", "remove", deleteTestWorktree}, nil, errors.New("contains modified files"))
+				mockExec.AddResponse("tmux", []string{"new-session", "-d", "-s", "mp-piece-p1", "-c", deleteTestWorktree}, nil, nil)
+			},
+			wantErr:    "use --force",
+			wantIssue:  "---\ntitle: Feature\nstatus: in-progress\n---\n",
+			wantCalled: []string{"tmux new-session -d -s mp-piece-p1 -c " + deleteTestWorktree},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, mockExec, handler := setupDeletePiece(t)
+			if tt.setup != nil {
+				tt.setup(fs, mockExec)
+			}
+			pieceName := tt.piece
+			if pieceName == "" {
+				pieceName = "p1"
+			}
+
+			result, err := handler.DeletePiece("/repo", pieceName, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			} else if result.IssueRolledBack != tt.wantRollback || result.IssuePath != "issues/feature.md" {
+				t.Errorf("expected rollback %v of issues/feature.md, got %+v", tt.wantRollback, result)
+			}
+
+			content, _ := fs.ReadFile("repo/issues/feature.md")
+			if string(content) != tt.wantIssue {
+				t.Errorf("expected issue:\n%s\ngot:\n%s", tt.wantIssue, content)
+			}
+
+			logged, _ := events.Read(fs, "/repo")
+			var types []string
+			for _, e := range logged {
+				types = append(types, e.Type)
+			}
+			if strings.Join(types, ",") != strings.Join(tt.wantEvents, ",") {
+				t.Errorf("expected events %v, got %v", tt.wantEvents, types)
+			}
+
+			for _, call := range tt.wantCalled {
+				args := strings.Fields(call)
+				if !mockExec.WasCalled(args[0], args[1:]...) {
+					t.Errorf("expected call: %s", call)
+				}
+			}
+		})
+	}
+}
+
+func writeDeletePRMetadata(fs *adapters.MemoryFS) {
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/p1", deleteTestWorktree)
+	_ = store.WritePRMetadata(piece.PRMetadata{PRNumber: 7, Branch: "p1"})
+}
@@ -0,0 +1,77 @@
+package piece
+
+import (
+	"fmt"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// DetailsOptions configures Details
+type DetailsOptions struct {
+	// MainBranch is the base for pieces that have no recorded base (default: main)
+	MainBranch string
+	// Fast skips checks that contact the remote
+	Fast bool
+}
+
+// Details returns Status enriched, inside a piece, with its branch, commits
+// ahead of and behind its base, uncommitted changes, linked issue, and PR.
+// Unless opts.Fast is set, the branch is also compared with the remote. When
+// the base or the remote can't be compared with (e.g. the base branch is
+// missing, or the remote unreachable), those fields are left empty with a
+// warning, so status and the statusline still work.
+func (h *Handler) Details(workDir string, opts DetailsOptions) (PieceStatus, error) {
+	if opts.MainBranch == "" {
+		opts.MainBranch = "main"
+	}
+
+	status, err := h.Status(workDir)
+	if err != nil || !status.InPiece {
+		return status, err
+	}
+
+	branch, err := h.git.CurrentBranch(status.WorktreePath)
+	if err != nil {
+		return status, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	status.Branch = branch
+	status.BaseBranch = h.BaseBranch(status.WorktreePath, opts.MainBranch)
+
+	if ahead, behind, err := h.git.AheadBehind(status.WorktreePath, status.BaseBranch, branch); err == nil {
+		status.Ahead, status.Behind = ahead, behind
+	} else {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to compare %s with %s: %v", branch, status.BaseBranch, err),
+		})
+	}
+	if status.Dirty, err = h.git.HasUncommittedChanges(status.WorktreePath); err != nil {
+		return status, err
+	}
+
+	store := OpenMetadataStore(h.deps, status.WorktreePath)
+	if marker, err := store.ReadIssueMarker(); err == nil {
+		status.IssuePath = marker.IssuePath
+		status.IssueID = IssueID(marker.IssuePath)
+	}
+	if metadata, err := store.ReadPieceMetadata(); err == nil {
+		status.Template = metadata.Template
+	}
+	if pr, err := store.ReadPRMetadata(); err == nil {
+		status.PRNumber = pr.PRNumber
+		status.PRURL = pr.PRURL
+	}
+	status.SourceDir = h.resolveSourceDir(status.WorktreePath)
+
+	if !opts.Fast {
+		if remote, err := h.CheckRemoteBranch(status.WorktreePath, branch); err == nil {
+			status.Remote = remote.State
+		} else {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to check remote branch: %v", err),
+			})
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,112 @@
+package piece_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_Details_Fast(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-list", "--left-right", "--count", "main...piece-1"}, []byte("2\t3\n"), nil)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, []byte(" M main.go\n"), nil)
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/add-login.md", PieceName: "piece-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.WritePRMetadata(piece.PRMetadata{PRNumber: 42, PRURL: "https://github.com/o/r/pull/42", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	_ = fs.Symlink("/src/monkeypuzzle", "/pieces/piece-1/.monkeypuzzle-source")
+
+	status, err := handler.Details("/pieces/piece-1", piece.DetailsOptions{Fast: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if status.Branch != "piece-1" || status.BaseBranch != "main" {
+		t.Errorf("expected piece-1 based on main, got %q based on %q", status.Branch, status.BaseBranch)
+	}
+	if status.Ahead != 3 || status.Behind != 2 {
+		t.Errorf("expected 3 ahead and 2 behind, got %d ahead and %d behind", status.Ahead, status.Behind)
+	}
+	if !status.Dirty {
+		t.Error("expected dirty worktree")
+	}
+	if status.IssueID != "add-login" || status.IssuePath != "issues/add-login.md" {
+		t.Errorf("expected linked issue add-login, got %q (%q)", status.IssueID, status.IssuePath)
+	}
+	if status.PRNumber != 42 {
+		t.Errorf("expected PR 42, got %d", status.PRNumber)
+	}
+	if status.SourceDir != "/src/monkeypuzzle" {
+		t.Errorf("expected the source dir migrated from the symlink, got %q", status.SourceDir)
+	}
+	if status.Remote != "" {
+		t.Errorf("expected no remote state with Fast, got %q", status.Remote)
+	}
+	if mockExec.WasCalled("git", "ls-remote", "--heads", "origin", "piece-1") {
+		t.Error("expected Fast to skip the remote")
+	}
+}
+
+func TestHandler_Details_Remote(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-list", "--left-right", "--count", "main...piece-1"}, []byte("0\t1\n"), nil)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "piece-1"}, []byte("aaa111\n"), nil)
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", "piece-1"}, []byte("aaa111\trefs/heads/piece-1\n"), nil)
+
+	status, err := handler.Details("/pieces/piece-1", piece.DetailsOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.Remote != piece.RemoteInSync {
+		t.Errorf("expected remote %s, got %q", piece.RemoteInSync, status.Remote)
+	}
+	if status.Dirty || status.IssueID != "" || status.PRNumber != 0 {
+		t.Errorf("expected clean piece without issue or PR, got %+v", status)
+	}
+}
+
+func TestHandler_Details_MainRepo(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(".git\n"), nil)
+
+	status, err := handler.Details("/repo", piece.DetailsOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.InPiece || status.Branch != "" {
+		t.Errorf("expected plain status outside a piece, got %+v", status)
+	}
+}
+
+func TestHandler_Details_WarnsWhenComparisonsFail(t *testing.T) {
+	_, out, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-list", "--left-right", "--count", "main...piece-1"}, []byte("fatal: bad revision 'main...piece-1'\n"), errors.New("exit status 128"))
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "piece-1"}, []byte("aaa111\n"), nil)
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", "piece-1"}, []byte("fatal: unable to access origin\n"), errors.New("exit status 128"))
+
+	status, err := handler.Details("/pieces/piece-1", piece.DetailsOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.Ahead != 0 || status.Behind != 0 || status.Remote != "" {
+		t.Errorf("expected the failed comparisons left empty, got %+v", status)
+	}
+	warnings := 0
+	for _, m := range out.Messages {
+		if m.Type == core.MsgWarning {
+			warnings++
+		}
+	}
+	if warnings != 2 {
+		t.Errorf("expected 2 warnings, got %d: %+v", warnings, out.Messages)
+	}
+}
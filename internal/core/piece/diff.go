@@ -0,0 +1,86 @@
+package piece
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+)
+
+// PieceLog lists a piece's commits since it diverged from its base branch
+type PieceLog struct {
+	PieceName  string `json:"piece_name"`
+	Branch     string `json:"branch"`
+	BaseBranch string `json:"base_branch"`
+	// Title is the name of the piece's issue, else the piece name
+	Title    string            `json:"title"`
+	PRNumber int               `json:"pr_number,omitempty"`
+	Commits  []adapters.Commit `json:"commits"`
+}
+
+// Log returns the commits of the piece containing workDir that are not on its
+// base branch (see BaseBranch; mainBranch is the fallback), newest first
+func (h *Handler) Log(workDir, mainBranch string) (PieceLog, error) {
+	status, err := h.requirePiece(workDir)
+	if err != nil {
+		return PieceLog{}, err
+	}
+
+	branch, err := h.git.CurrentBranch(status.WorktreePath)
+	if err != nil {
+		return PieceLog{}, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	log := PieceLog{
+		PieceName:  status.PieceName,
+		Branch:     branch,
+		BaseBranch: h.BaseBranch(status.WorktreePath, mainBranch),
+		Title:      status.PieceName,
+	}
+	store := OpenMetadataStore(h.deps, status.WorktreePath)
+	if marker, err := store.ReadIssueMarker(); err == nil && marker.IssueName != "" {
+		log.Title = marker.IssueName
+	}
+	if pr, err := store.ReadPRMetadata(); err == nil {
+		log.PRNumber = pr.PRNumber
+	}
+
+	if log.Commits, err = h.git.CommitLog(status.WorktreePath, log.BaseBranch, branch); err != nil {
+		return PieceLog{}, err
+	}
+	if log.Commits == nil {
+		log.Commits = []adapters.Commit{}
+	}
+	return log, nil
+}
+
+// Diff returns the changes of the piece containing workDir since it diverged
+// from its base branch
+func (h *Handler) Diff(workDir, mainBranch string) (string, error) {
+	status, err := h.requirePiece(workDir)
+	if err != nil {
+		return "", err
+	}
+	return h.git.Diff(status.WorktreePath, h.BaseBranch(status.WorktreePath, mainBranch))
+}
+
+// ChangelogFragment renders the piece's commits as a markdown changelog entry:
+// a heading with the title and PR number, then one item per commit subject
+func (l PieceLog) ChangelogFragment() string {
+	var b strings.Builder
+	b.WriteString("### " + l.Title)
+	if l.PRNumber != 0 {
+		fmt.Fprintf(&b, " (#%d)", l.PRNumber)
+	}
+	b.WriteString("\n\n")
+	// Oldest first, in the order the changes were made
+	for i := len(l.Commits) - 1; i >= 0; i-- {
+		commit := l.Commits[i]
+		hash := commit.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		fmt.Fprintf(&b, "- %s (%s)\n", commit.Subject, hash)
+	}
+	return b.String()
+}
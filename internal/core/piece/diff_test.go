@@ -0,0 +1,52 @@
+package piece_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_Log(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+	coAuthor := adapters.Trailer{Key: "Co-authored-by", Value: "Bob <bob@example.com>"}
+	want := []adapters.Commit{
+		{
+			Hash: "b2", Author: "Alice", AuthorEmail: "alice@example.com", Subject: "fix: handle empty input",
+			Body: "Empty input crashed the parser.\n\nReturn early instead.", Trailers: []adapters.Trailer{coAuthor},
+		},
+		{Hash: "a1", Author: "Alice", AuthorEmail: "alice@example.com", Subject: "feat: add parser"},
+	}
+	mockCommitLog(mockExec, "main..piece-1", want...)
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WritePRMetadata(piece.PRMetadata{PRNumber: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := handler.Log("/pieces/piece-1", "main")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if log.BaseBranch != "main" || log.Branch != "piece-1" || log.Title != "piece-1" || log.PRNumber != 42 {
+		t.Errorf("unexpected log header: %+v", log)
+	}
+	if !reflect.DeepEqual(log.Commits, want) {
+		t.Errorf("expected commits %+v, got %+v", want, log.Commits)
+	}
+
+	fragment := log.ChangelogFragment()
+	if fragment != "### piece-1 (#42)\n\n- feat: add parser (a1)\n- fix: handle empty input (b2)\n" {
+		t.Errorf("unexpected changelog fragment:\n%s", fragment)
+	}
+}
+
+func TestHandler_Log_NotInPiece(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(".git\n"), nil)
+
+	if _, err := handler.Log("/repo", "main"); err == nil {
+		t.Error("expected error outside a piece")
+	}
+}
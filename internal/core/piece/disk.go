@@ -0,0 +1,167 @@
+package piece
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDiskUsageMaxAge is how long a measured worktree size is reused
+const DefaultDiskUsageMaxAge = 10 * time.Minute
+
+// diskUsageFilename caches worktree sizes in the data directory
+const diskUsageFilename = "disk-usage.json"
+
+// diskUsageWorkers bounds the du processes run at once
+const diskUsageWorkers = 4
+
+// diskUsageEntry is the cached size of a worktree
+type diskUsageEntry struct {
+	Bytes      int64     `json:"bytes"`
+	MeasuredAt time.Time `json:"measured_at"`
+}
+
+// DiskUsage returns the disk space in bytes used by each of paths, measured
+// with du in parallel. Sizes measured within maxAge are served from a cache
+// in the data directory; a zero maxAge measures every path. Paths du fails
+// on are left out.
+func (h *Handler) DiskUsage(paths []string, maxAge time.Duration) map[string]int64 {
+	cachePath := ""
+	if dataDir, err := DataDir(); err == nil {
+		cachePath = filepath.Join(dataDir, diskUsageFilename)
+	}
+	cache := map[string]diskUsageEntry{}
+	if data, err := h.deps.FS.ReadFile(cachePath); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+
+	now := time.Now()
+	usage := map[string]int64{}
+	var stale []string
+	for _, path := range paths {
+		if entry, ok := cache[path]; ok && now.Sub(entry.MeasuredAt) < maxAge {
+			usage[path] = entry.Bytes
+			continue
+		}
+		stale = append(stale, path)
+	}
+	if len(stale) == 0 {
+		return usage
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan string)
+	for range min(diskUsageWorkers, len(stale)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range queue {
+				bytes, err := h.DirSize(path)
+				mu.Lock()
+				if err != nil {
+					delete(cache, path)
+				} else {
+					usage[path] = bytes
+					cache[path] = diskUsageEntry{Bytes: bytes, MeasuredAt: now}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, path := range stale {
+		queue <- path
+	}
+	close(queue)
+	wg.Wait()
+
+	if cachePath != "" {
+		if data, err := json.Marshal(cache); err == nil {
+			if err := h.deps.FS.MkdirAll(filepath.Dir(cachePath), DefaultDirPerm); err == nil {
+				_ = h.deps.FS.WriteFile(cachePath, data, 0644)
+			}
+		}
+	}
+	return usage
+}
+
+// DirSize measures the disk space used by path with du, uncached
+func (h *Handler) DirSize(path string) (int64, error) {
+	output, err := h.deps.Exec.Run("du", "-sk", path)
+	if err != nil {
+		return 0, fmt.Errorf("du failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected du output %q", output)
+	}
+	kib, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected du output %q", output)
+	}
+	return kib * 1024, nil
+}
+
+// FreeSpace returns the bytes available to unprivileged users on the
+// filesystem holding path, as reported by df
+func (h *Handler) FreeSpace(path string) (int64, error) {
+	output, err := h.deps.Exec.Run("df", "-Pk", path)
+	if err != nil {
+		return 0, fmt.Errorf("df failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	// Filesystem 1024-blocks Used Available Capacity Mounted-on
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) < 4 {
+		return 0, fmt.Errorf("unexpected df output %q", output)
+	}
+	kib, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected df output %q", output)
+	}
+	return kib * 1024, nil
+}
+
+// sizeUnits are the suffixes ParseSize accepts, as powers of 1024
+var sizeUnits = []string{"B", "K", "M", "G", "T"}
+
+// ParseSize parses a size such as 512M, 10G or 1.5T (powers of 1024; a
+// trailing B or iB is allowed) into bytes. A bare number is bytes.
+func ParseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "IB"), "B")
+	scale := 1.0
+	for i, unit := range sizeUnits[1:] {
+		if strings.HasSuffix(value, unit) {
+			value = strings.TrimSuffix(value, unit)
+			for range i + 1 {
+				scale *= 1024
+			}
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 500M or 10G)", s)
+	}
+	return int64(n * scale), nil
+}
+
+// FormatSize formats bytes with the largest unit that keeps the value at
+// least 1, e.g. 1.5G
+func FormatSize(bytes int64) string {
+	value := float64(bytes)
+	unit := 0
+	for value >= 1024 && unit < len(sizeUnits)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	return strconv.FormatFloat(value, 'f', 1, 64) + sizeUnits[unit]
+}
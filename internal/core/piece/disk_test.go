@@ -0,0 +1,84 @@
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"512", 512},
+		{"4K", 4 << 10},
+		{"10G", 10 << 30},
+		{"10gb", 10 << 30},
+		{"1.5GiB", 3 << 29},
+		{"2T", 2 << 40},
+	}
+	for _, tt := range tests {
+		got, err := piece.ParseSize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "ten", "-1G", "5X"} {
+		if _, err := piece.ParseSize(in); err == nil {
+			t.Errorf("expected ParseSize(%q) to fail", in)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for bytes, want := range map[int64]string{100: "100B", 1536: "1.5K", 10 << 30: "10.0G"} {
+		if got := piece.FormatSize(bytes); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
+
+func TestHandler_DiskUsage_Caches(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("du", []string{"-sk", "/pieces/a"}, []byte("2048\t/pieces/a\n"), nil)
+	mockExec.AddResponse("du", []string{"-sk", "/pieces/b"}, []byte("4\t/pieces/b\n"), nil)
+
+	usage := handler.DiskUsage([]string{"/pieces/a", "/pieces/b", "/pieces/gone"}, piece.DefaultDiskUsageMaxAge)
+	if usage["/pieces/a"] != 2<<20 || usage["/pieces/b"] != 4<<10 {
+		t.Errorf("unexpected usage %v", usage)
+	}
+	if _, ok := usage["/pieces/gone"]; ok {
+		t.Error("expected a path du fails on to be left out")
+	}
+
+	mockExec.ClearCalls()
+	usage = handler.DiskUsage([]string{"/pieces/a"}, piece.DefaultDiskUsageMaxAge)
+	if usage["/pieces/a"] != 2<<20 || mockExec.WasCalled("du", "-sk", "/pieces/a") {
+		t.Errorf("expected the cached size, got %v", usage)
+	}
+	handler.DiskUsage([]string{"/pieces/a"}, 0)
+	if !mockExec.WasCalled("du", "-sk", "/pieces/a") {
+		t.Error("expected a zero max age to measure again")
+	}
+}
+
+func TestHandler_FreeSpace(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("df", []string{"-Pk", "/pieces"},
+		[]byte("Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/sda1 100000 60000 40000 60% /\n"), nil)
+
+	free, err := handler.FreeSpace("/pieces")
+	if err != nil || free != 40000*1024 {
+		t.Errorf("expected 40000 KiB free, got %d (%v)", free, err)
+	}
+	if _, err := handler.FreeSpace("/missing"); err == nil {
+		t.Error("expected an error when df fails")
+	}
+}
@@ -0,0 +1,95 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// excludeHeader marks the block of .git/info/exclude entries managed by mp
+const excludeHeader = "# monkeypuzzle: worktree-local state (managed by mp)"
+
+// LocalArtifacts lists worktree-local files mp writes that must never be committed.
+// Paths are relative to the worktree root.
+var LocalArtifacts = []string{
+	"/" + legacySourceSymlink,
+	"/" + initcmd.DirName + "/current-issue.json",
+	"/" + initcmd.DirName + "/" + prMetadataFilename,
+	"/" + initcmd.DirName + "/review-brief.md",
+	"/" + initcmd.DirName + "/events.jsonl",
+	"/" + initcmd.DirName + "/events.jsonl.1",
+	"/" + initcmd.DirName + "/issues.index.json",
+}
+
+// ExcludePath returns the path of the repository's shared exclude file.
+// The file lives in the common git dir (usually .git), so it applies to every
+// worktree.
+func ExcludePath(gitCommonDir string) string {
+	return filepath.Join(gitCommonDir, "info", "exclude")
+}
+
+// EnsureExcludes adds any missing LocalArtifacts entries to info/exclude of the
+// repository whose common git dir is gitCommonDir. It is idempotent and returns
+// the entries it added. Running it migrates existing pieces too, since all
+// worktrees share the file.
+func EnsureExcludes(gitCommonDir string, fs core.FS) ([]string, error) {
+	path := ExcludePath(gitCommonDir)
+
+	existing := ""
+	if data, err := fs.ReadFile(path); err == nil {
+		existing = string(data)
+	}
+
+	present := strings.Split(existing, "\n")
+	for i := range present {
+		present[i] = strings.TrimSpace(present[i])
+	}
+
+	var missing []string
+	for _, entry := range LocalArtifacts {
+		if !slices.Contains(present, entry) {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(existing)
+	if existing != "" && !strings.HasSuffix(existing, "\n") {
+		b.WriteString("\n")
+	}
+	if !slices.Contains(present, excludeHeader) {
+		b.WriteString(excludeHeader + "\n")
+	}
+	for _, entry := range missing {
+		b.WriteString(entry + "\n")
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), DefaultDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := fs.WriteFile(path, []byte(b.String()), initcmd.DefaultFilePerm); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return missing, nil
+}
+
+// ensureExcludes runs EnsureExcludes, reporting failures as warnings
+func (h *Handler) ensureExcludes(repoRoot string) {
+	if repoRoot == "" {
+		return
+	}
+	if _, err := EnsureExcludes(gitCommonDir(h.deps.Exec, repoRoot), h.deps.FS); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to update git exclude file: %v", err),
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package piece_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestEnsureExcludes_AppendsManagedBlock(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	_ = fs.WriteFile("repo/.git/info/exclude", []byte("# user entry\n*.swp"), 0644)
+
+	added, err := piece.EnsureExcludes("/repo/.git", fs)
+	if err != nil {
+		t.Fatalf("EnsureExcludes failed: %v", err)
+	}
+	if len(added) != len(piece.LocalArtifacts) {
+		t.Errorf("expected %d entries added, got %v", len(piece.LocalArtifacts), added)
+	}
+
+	data, _ := fs.ReadFile("repo/.git/info/exclude")
+	content := string(data)
+	if !strings.HasPrefix(content, "# user entry\n*.swp\n") {
+		t.Errorf("expected existing entries preserved, got:\n%s", content)
+	}
+	for _, entry := range []string{"/.monkeypuzzle/current-issue.json", "/.monkeypuzzle/pr-metadata.json"} {
+		if !strings.Contains(content, entry+"\n") {
+			t.Errorf("expected exclude to contain %s, got:\n%s", entry, content)
+		}
+	}
+}
+
+func TestEnsureExcludes_Idempotent(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+
+	if _, err := piece.EnsureExcludes("/repo/.git", fs); err != nil {
+		t.Fatalf("first EnsureExcludes failed: %v", err)
+	}
+	first, _ := fs.ReadFile("repo/.git/info/exclude")
+
+	added, err := piece.EnsureExcludes("/repo/.git", fs)
+	if err != nil {
+		t.Fatalf("second EnsureExcludes failed: %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("expected nothing added on second run, got %v", added)
+	}
+
+	second, _ := fs.ReadFile("repo/.git/info/exclude")
+	if string(first) != string(second) {
+		t.Errorf("expected exclude file unchanged, got:\n%s", second)
+	}
+	if strings.Count(string(second), "managed by mp") != 1 {
+		t.Errorf("expected a single managed header, got:\n%s", second)
+	}
+}
@@ -1,21 +1,21 @@
 package piece
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
-	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
 )
 
 const (
-	symlinkName = ".monkeypuzzle-source"
-
 	// DefaultDirPerm is the default permission for directories (0755 = rwxr-xr-x)
 	DefaultDirPerm = 0755
 )
@@ -40,10 +40,31 @@ func NewHandler(deps core.Deps) *Handler {
 	}
 }
 
+// CreateOptions configures CreatePieceWithOptions
+type CreateOptions struct {
+	// SourceDir is the monkeypuzzle source directory recorded in the piece
+	SourceDir string
+	// Name is the piece name; a name is generated when empty
+	Name string
+	// Template names the piece template to apply (see LoadTemplate), if any
+	Template string
+	// Branch adopts an existing branch for the piece instead of creating one;
+	// the name defaults to the sanitized branch name
+	Branch string
+}
+
 // CreatePiece creates a new git worktree with tmux session.
 // If pieceName is provided and non-empty, it will be used (after checking it doesn't exist).
 // If pieceName is empty, a name will be generated automatically.
 func (h *Handler) CreatePiece(monkeypuzzleSourceDir string, pieceName string) (PieceInfo, error) {
+	return h.CreatePieceWithOptions(CreateOptions{SourceDir: monkeypuzzleSourceDir, Name: pieceName})
+}
+
+// CreatePieceWithOptions creates a piece like CreatePiece. With a template, the
+// piece's branch gets the template's prefix, its bootstrap commands run before
+// the on-piece-create hook, its tmux windows are opened, and the template name
+// is recorded in the piece metadata.
+func (h *Handler) CreatePieceWithOptions(opts CreateOptions) (PieceInfo, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return PieceInfo{}, fmt.Errorf("failed to get working directory: %w", err)
@@ -55,13 +76,47 @@ func (h *Handler) CreatePiece(monkeypuzzleSourceDir string, pieceName string) (P
 		return PieceInfo{}, fmt.Errorf("not in a git repository: %w", err)
 	}
 
+	var tmpl Template
+	if opts.Template != "" {
+		if tmpl, err = LoadTemplate(h.deps.FS, repoRoot, opts.Template); err != nil {
+			return PieceInfo{}, err
+		}
+	}
+	pieceName := opts.Name
+	maxNameLength := h.maxNameLength(repoRoot)
+	if opts.Branch != "" {
+		if err := ValidateBranchName(opts.Branch); err != nil {
+			return PieceInfo{}, err
+		}
+		if !h.git.BranchExists(repoRoot, opts.Branch) {
+			return PieceInfo{}, fmt.Errorf("branch %s does not exist", opts.Branch)
+		}
+		if pieceName == "" {
+			pieceName = SanitizePieceNameMax(opts.Branch, maxNameLength)
+		}
+	}
+	if pieceName != "" {
+		if err := ValidatePieceName(pieceName); err != nil {
+			return PieceInfo{}, err
+		}
+		if len(pieceName) > maxNameLength {
+			short := TruncatePieceName(pieceName, maxNameLength)
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Piece name %q is %d bytes long, over the limit of %d (pieces.max_name_length); using %q", pieceName, len(pieceName), maxNameLength, short),
+			})
+			pieceName = short
+		}
+	}
+
 	// Get pieces directory
-	piecesDir, err := getPiecesDir()
+	piecesDir, err := PiecesDir()
 	if err != nil {
 		return PieceInfo{}, fmt.Errorf("failed to get pieces directory: %w", err)
 	}
 
-	// Use provided name or generate one
+	// Use provided name or generate one. Either way the name is reserved by
+	// creating its directory, which git worktree add accepts while empty.
 	if pieceName == "" {
 		var err error
 		pieceName, err = h.GeneratePieceName(piecesDir)
@@ -69,72 +124,125 @@ func (h *Handler) CreatePiece(monkeypuzzleSourceDir string, pieceName string) (P
 			return PieceInfo{}, fmt.Errorf("failed to generate piece name: %w", err)
 		}
 	} else {
-		// Validate that the provided name doesn't already exist
+		if err := h.deps.FS.MkdirAll(piecesDir, DefaultDirPerm); err != nil {
+			return PieceInfo{}, fmt.Errorf("failed to create pieces directory at %s: %w", piecesDir, err)
+		}
 		piecePath := filepath.Join(piecesDir, pieceName)
-		_, err := h.deps.FS.Stat(piecePath)
-		if err == nil {
-			return PieceInfo{}, fmt.Errorf("piece name %q already exists at %s", pieceName, piecePath)
+		if err := h.reservePieceName(piecesDir, pieceName); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return PieceInfo{}, fmt.Errorf("piece name %q already exists at %s", pieceName, piecePath)
+			}
+			return PieceInfo{}, fmt.Errorf("failed to reserve piece name %q: %w", pieceName, err)
 		}
 	}
 
-	// Create pieces directory if it doesn't exist
-	if err := h.deps.FS.MkdirAll(piecesDir, DefaultDirPerm); err != nil {
-		return PieceInfo{}, fmt.Errorf("failed to create pieces directory at %s: %w", piecesDir, err)
+	// Check the branch up front: git worktree add fails opaquely on an
+	// existing branch, e.g. one left over from an earlier piece
+	worktreePath := filepath.Join(piecesDir, pieceName)
+	if err := CheckPathLength(worktreePath, runtime.GOOS); err != nil {
+		h.deps.Output.Write(core.Message{Type: core.MsgWarning, Content: err.Error()})
+	}
+	if opts.Branch == "" {
+		if err := h.checkNewBranch(repoRoot, tmpl.BranchPrefix+pieceName); err != nil {
+			_ = h.deps.FS.Remove(worktreePath)
+			return PieceInfo{}, err
+		}
 	}
 
 	// Create worktree
-	worktreePath := filepath.Join(piecesDir, pieceName)
-	if err := h.git.WorktreeAdd(repoRoot, worktreePath); err != nil {
+	op := operation.New(h.deps.Output, "create piece "+pieceName)
+	switch {
+	case opts.Branch != "":
+		err = h.git.WorktreeAddExisting(repoRoot, worktreePath, opts.Branch)
+	case tmpl.BranchPrefix != "":
+		err = h.git.WorktreeAddBranch(repoRoot, worktreePath, tmpl.BranchPrefix+pieceName)
+	default:
+		err = h.git.WorktreeAdd(repoRoot, worktreePath)
+	}
+	if err != nil {
+		// Release the name reserved above
+		_ = h.deps.FS.Remove(worktreePath)
 		return PieceInfo{}, fmt.Errorf("failed to create worktree at %s: %w", worktreePath, err)
 	}
+	if opts.Branch == "" {
+		// Undone after the worktree is removed, so a retry can create it again
+		branch := tmpl.BranchPrefix + pieceName
+		op.Undo("delete branch "+branch, func() error {
+			return h.git.DeleteBranch(repoRoot, branch)
+		})
+	}
+	op.Undo("remove worktree "+worktreePath, func() error {
+		return h.git.WorktreeRemove(repoRoot, worktreePath)
+	})
 
-	// Note: Currently, symlink and tmux creation failures are non-fatal (logged as warnings).
-	// If we decide to make them fatal in the future, we should add cleanup logic here to
-	// remove the worktree if those operations fail. The WorktreeRemove method is available
-	// in the Git adapter for this purpose.
+	// Keep mp's worktree-local files out of git status and commits
+	h.ensureExcludes(repoRoot)
+	h.recordMainWorktree(repoRoot)
 
-	// Create symlink to monkeypuzzle source
-	symlinkPath := filepath.Join(worktreePath, symlinkName)
-	if err := h.deps.FS.Symlink(monkeypuzzleSourceDir, symlinkPath); err != nil {
-		// If symlink creation fails, log but don't fail the operation
-		h.deps.Output.Write(core.Message{
-			Type:    core.MsgWarning,
-			Content: fmt.Sprintf("Failed to create symlink: %v", err),
-		})
+	// Source dir and tmux failures are non-fatal (logged as warnings); steps
+	// that become fatal must undo the earlier ones with op.Fail
+
+	// Record the monkeypuzzle source directory in the piece metadata
+	if opts.SourceDir != "" {
+		if err := h.setSourceDir(worktreePath, opts.SourceDir); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to record source directory: %v", err),
+			})
+		}
+	}
+	if tmpl.Name != "" {
+		if err := h.updatePieceMetadata(worktreePath, func(m *PieceMetadata) { m.Template = tmpl.Name }); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to record template: %v", err),
+			})
+		}
 	}
 
 	// Create tmux session
-	sessionName := fmt.Sprintf("mp-piece-%s", pieceName)
-	tmuxCreated := false
-	if err := h.tmux.NewSession(sessionName, worktreePath); err != nil {
+	sessionName := SessionName(pieceName)
+	sessionErr := h.tmux.NewSession(sessionName, worktreePath)
+	if sessionErr != nil {
 		// If tmux fails, log but don't fail the operation
 		h.deps.Output.Write(core.Message{
 			Type:    core.MsgWarning,
-			Content: fmt.Sprintf("Failed to create tmux session: %v", err),
+			Content: fmt.Sprintf("Failed to create tmux session: %v", sessionErr),
 		})
 	} else {
-		tmuxCreated = true
+		op.Undo("kill tmux session "+sessionName, func() error {
+			return h.tmux.KillSession(sessionName)
+		})
 	}
 
 	info := PieceInfo{
 		Name:         pieceName,
 		WorktreePath: worktreePath,
 		SessionName:  sessionName,
+		Template:     tmpl.Name,
 	}
 
-	// Run on-piece-create hook
+	// Run the template's bootstrap commands, then the on-piece-create hook
 	hookCtx := HookContext{
 		PieceName:    pieceName,
 		WorktreePath: worktreePath,
 		RepoRoot:     repoRoot,
 		SessionName:  sessionName,
+		SourceDir:    opts.SourceDir,
+	}
+	if err := h.bootstrapPiece(tmpl, hookCtx); err != nil {
+		return PieceInfo{}, op.Fail(err)
+	}
+	if sessionErr == nil {
+		h.openTemplateWindows(tmpl, sessionName, worktreePath)
+		h.openCheckpointWindow(repoRoot, sessionName, worktreePath)
 	}
 	if err := h.hooks.RunHook(repoRoot, HookOnPieceCreate, hookCtx); err != nil {
-		// Cleanup: remove worktree and tmux session on hook failure
-		h.cleanupPiece(repoRoot, worktreePath, sessionName, tmuxCreated)
-		return PieceInfo{}, fmt.Errorf("on-piece-create hook failed: %w", err)
+		return PieceInfo{}, op.Fail(fmt.Errorf("on-piece-create hook failed: %w", err))
 	}
 
+	h.logPieceEvent(repoRoot, EventPieceCreate, pieceName, nil)
+
 	h.deps.Output.Write(core.Message{
 		Type:    core.MsgSuccess,
 		Content: fmt.Sprintf("Created piece: %s at %s", pieceName, worktreePath),
@@ -155,6 +263,13 @@ type CurrentIssueMarker struct {
 // It extracts the issue name, sanitizes it for use as a piece name, creates the piece,
 // and writes a marker file in the worktree to track the current issue.
 func (h *Handler) CreatePieceFromIssue(monkeypuzzleSourceDir, issuePath string) (PieceInfo, error) {
+	return h.CreatePieceFromIssueWithOptions(CreateOptions{SourceDir: monkeypuzzleSourceDir}, issuePath)
+}
+
+// CreatePieceFromIssueWithOptions creates a piece from an issue like
+// CreatePieceFromIssue, applying opts as CreatePieceWithOptions does. The piece
+// is named after the issue, whatever opts.Name is.
+func (h *Handler) CreatePieceFromIssueWithOptions(opts CreateOptions, issuePath string) (PieceInfo, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return PieceInfo{}, fmt.Errorf("failed to get working directory: %w", err)
@@ -166,39 +281,11 @@ func (h *Handler) CreatePieceFromIssue(monkeypuzzleSourceDir, issuePath string)
 		return PieceInfo{}, fmt.Errorf("not in a git repository: %w", err)
 	}
 
-	// Read monkeypuzzle config to find issues directory
-	cfg, err := ReadConfig(repoRoot, h.deps.FS)
-	if err != nil {
-		return PieceInfo{}, fmt.Errorf("failed to read monkeypuzzle config: %w", err)
-	}
-
-	// Validate issue provider is markdown
-	if cfg.Issues.Provider != "markdown" {
-		return PieceInfo{}, fmt.Errorf("issue provider must be 'markdown', got: %s", cfg.Issues.Provider)
-	}
-
-	// Get and validate issues directory from config
-	issuesDir, ok := cfg.Issues.Config["directory"]
-	if !ok || issuesDir == "" {
-		return PieceInfo{}, fmt.Errorf("issues directory not found in config")
-	}
-
-	// Resolve issue path (absolute or relative to repo root)
-	// ResolveIssuePath already verifies the file exists
-	absIssuePath, err := ResolveIssuePath(repoRoot, issuePath, h.deps.FS)
+	absIssuePath, relIssuePath, err := h.resolveIssue(repoRoot, issuePath)
 	if err != nil {
 		return PieceInfo{}, err
 	}
 
-	// Validate that the issue file is within the configured issues directory
-	// This prevents path traversal and ensures issues are in the correct location
-	absIssuesDir := filepath.Join(repoRoot, issuesDir)
-	absIssuesDir = filepath.Clean(absIssuesDir)
-	relPath, err := filepath.Rel(absIssuesDir, absIssuePath)
-	if err != nil || strings.HasPrefix(relPath, "..") {
-		return PieceInfo{}, fmt.Errorf("issue file must be within the issues directory %q, got: %s", issuesDir, issuePath)
-	}
-
 	// Extract issue name
 	issueName, err := ExtractIssueName(absIssuePath, h.deps.FS)
 	if err != nil {
@@ -206,24 +293,16 @@ func (h *Handler) CreatePieceFromIssue(monkeypuzzleSourceDir, issuePath string)
 	}
 
 	// Sanitize issue name for piece name
-	pieceName := SanitizePieceName(issueName)
+	pieceName := SanitizePieceNameMax(issueName, h.maxNameLength(repoRoot))
 
 	// Create the piece using the sanitized name
-	info, err := h.CreatePiece(monkeypuzzleSourceDir, pieceName)
+	opts.Name = pieceName
+	info, err := h.CreatePieceWithOptions(opts)
 	if err != nil {
 		return PieceInfo{}, err
 	}
 
-	// Calculate relative issue path from repo root
-	// Note: filepath.Rel can fail on Windows if paths are on different drives
-	relIssuePath, err := filepath.Rel(repoRoot, absIssuePath)
-	if err != nil {
-		// If we can't compute relative path (e.g., different drives on Windows),
-		// use the original path provided by the user
-		relIssuePath = issuePath
-	}
-
-	// Write current issue marker file in worktree
+	// Record the current issue in the piece's metadata store
 	marker := CurrentIssueMarker{
 		IssuePath: relIssuePath,
 		IssueName: issueName,
@@ -239,30 +318,91 @@ func (h *Handler) CreatePieceFromIssue(monkeypuzzleSourceDir, issuePath string)
 
 	// Update issue status to in-progress (non-fatal)
 	h.updateIssueStatusToInProgress(absIssuePath)
+	// The session title is cosmetic: failing to set it is not worth a warning
+	_, _ = h.syncTitle(pieceName, info.WorktreePath, repoRoot)
 
+	info.IssueID = IssueID(relIssuePath)
+	info.IssuePath = relIssuePath
 	return info, nil
 }
 
-// writeCurrentIssueMarker writes the current issue marker file to the worktree.
-func (h *Handler) writeCurrentIssueMarker(worktreePath string, marker CurrentIssueMarker) error {
-	// Create .monkeypuzzle directory in worktree if it doesn't exist
-	mpDir := filepath.Join(worktreePath, initcmd.DirName)
-	if err := h.deps.FS.MkdirAll(mpDir, DefaultDirPerm); err != nil {
-		return fmt.Errorf("failed to create .monkeypuzzle directory: %w", err)
+// resolveIssue validates that an issue file exists within the configured issues
+// directory and returns its absolute path and its path relative to the repo root.
+func (h *Handler) resolveIssue(repoRoot, issuePath string) (absIssuePath, relIssuePath string, err error) {
+	// Read monkeypuzzle config to find issues directory
+	cfg, err := ReadConfig(repoRoot, h.deps.FS)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read monkeypuzzle config: %w", err)
+	}
+
+	// Validate issue provider is markdown
+	if cfg.Issues.Provider != "markdown" {
+		return "", "", fmt.Errorf("issue provider must be 'markdown', got: %s", cfg.Issues.Provider)
 	}
 
-	// Write marker file
-	markerPath := filepath.Join(mpDir, "current-issue.json")
-	data, err := json.MarshalIndent(marker, "", "  ")
+	// Get and validate issues directories from config
+	issuesDirs := cfg.Issues.Dirs()
+
+	// Resolve issue path (absolute or relative to repo root)
+	// ResolveIssuePath already verifies the file exists
+	absIssuePath, err = ResolveIssuePath(repoRoot, issuePath, h.deps.FS)
+	if err != nil && !strings.ContainsRune(issuePath, filepath.Separator) {
+		// A short ID names a file in one of the issues directories; a name
+		// found in several of them is ambiguous
+		var matches []string
+		for _, issuesDir := range issuesDirs {
+			candidate := filepath.Join(issuesDir, IssueID(issuePath)+".md")
+			if abs, resolveErr := ResolveIssuePath(repoRoot, candidate, h.deps.FS); resolveErr == nil {
+				absIssuePath, err = abs, nil
+				matches = append(matches, candidate)
+			}
+		}
+		if len(matches) > 1 {
+			return "", "", fmt.Errorf("issue %s is ambiguous, use its path: %s", issuePath, strings.Join(matches, ", "))
+		}
+	}
+	if err != nil {
+		// An issue renamed with mp issue rename is found by its old ID or path
+		if aliased, ok := ResolveIssueAlias(repoRoot, issuePath, h.deps.FS); ok {
+			absIssuePath, err = ResolveIssuePath(repoRoot, aliased, h.deps.FS)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to marshal marker: %w", err)
+		return "", "", err
 	}
 
-	if err := h.deps.FS.WriteFile(markerPath, data, initcmd.DefaultFilePerm); err != nil {
-		return fmt.Errorf("failed to write marker file: %w", err)
+	// Validate that the issue file is within a configured issues directory
+	// This prevents path traversal and ensures issues are in the correct location
+	if !withinAny(repoRoot, issuesDirs, absIssuePath) {
+		return "", "", fmt.Errorf("issue file must be within the issues directory %q, got: %s", strings.Join(issuesDirs, `", "`), issuePath)
 	}
 
-	return nil
+	// Calculate relative issue path from repo root
+	// Note: filepath.Rel can fail on Windows if paths are on different drives
+	relIssuePath, err = filepath.Rel(repoRoot, absIssuePath)
+	if err != nil {
+		// If we can't compute relative path (e.g., different drives on Windows),
+		// use the original path provided by the user
+		relIssuePath = issuePath
+	}
+
+	return absIssuePath, relIssuePath, nil
+}
+
+// withinAny reports whether path lies inside one of dirs, relative to repoRoot
+func withinAny(repoRoot string, dirs []string, path string) bool {
+	for _, dir := range dirs {
+		relPath, err := filepath.Rel(filepath.Clean(filepath.Join(repoRoot, dir)), path)
+		if err == nil && !strings.HasPrefix(relPath, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCurrentIssueMarker records the issue a piece was created from in its metadata store.
+func (h *Handler) writeCurrentIssueMarker(worktreePath string, marker CurrentIssueMarker) error {
+	return OpenMetadataStore(h.deps, worktreePath).WriteIssueMarker(marker)
 }
 
 // updateIssueStatusToInProgress updates the issue status to in-progress if it's currently todo.
@@ -292,29 +432,15 @@ func (h *Handler) updateIssueStatusToInProgress(issuePath string) {
 	}
 }
 
-// cleanupPiece removes a partially created piece (worktree and tmux session).
-// Errors during cleanup are logged as warnings but not returned.
-func (h *Handler) cleanupPiece(repoRoot, worktreePath, sessionName string, tmuxCreated bool) {
-	// Kill tmux session if it was created
-	if tmuxCreated {
-		if err := h.tmux.KillSession(sessionName); err != nil {
-			h.deps.Output.Write(core.Message{
-				Type:    core.MsgWarning,
-				Content: fmt.Sprintf("Failed to cleanup tmux session: %v", err),
-			})
-		}
-	}
-
-	// Remove worktree
-	if err := h.git.WorktreeRemove(repoRoot, worktreePath); err != nil {
-		h.deps.Output.Write(core.Message{
-			Type:    core.MsgWarning,
-			Content: fmt.Sprintf("Failed to cleanup worktree: %v", err),
-		})
-	}
+// SessionName returns the tmux session name for a piece. tmux doesn't allow
+// '.' or ':' in session names, so they become '_' as tmux itself would do.
+func SessionName(pieceName string) string {
+	return sessionPrefix + strings.NewReplacer(".", "_", ":", "_").Replace(pieceName)
 }
 
-// Status detects if we're currently in a piece worktree or main repo
+// Status detects if we're currently in a piece worktree or main repo.
+// A repository nested inside a piece worktree is refused with a nested_repo
+// error unless MP_ALLOW_NESTED_REPO=1.
 func (h *Handler) Status(workDir string) (PieceStatus, error) {
 	gitDir, err := h.git.RevParseGitDir(workDir)
 	if err != nil {
@@ -332,6 +458,9 @@ func (h *Handler) Status(workDir string) (PieceStatus, error) {
 			// If we can't get repo root, leave it empty
 			repoRoot = ""
 		}
+		if err := checkNestedRepo(repoRoot); err != nil {
+			return PieceStatus{}, err
+		}
 		return PieceStatus{
 			InPiece:  false,
 			RepoRoot: repoRoot,
@@ -344,15 +473,26 @@ func (h *Handler) Status(workDir string) (PieceStatus, error) {
 		// Fallback: use workDir if we can't get worktree path
 		worktreePath = workDir
 	}
+	if err := checkNestedRepo(worktreePath); err != nil {
+		return PieceStatus{}, err
+	}
 	pieceName := filepath.Base(worktreePath)
 
 	// Get main repo root from worktree
-	repoRoot, err := h.git.GetMainRepoRoot(workDir)
+	repoRoot, err := h.mainRepoRoot(workDir)
 	if err != nil {
 		// If we can't get main repo root, leave it empty
 		repoRoot = ""
 	}
 
+	// In a bare repository, a worktree stands in for the main repo
+	if repoRoot == worktreePath {
+		return PieceStatus{
+			InPiece:  false,
+			RepoRoot: repoRoot,
+		}, nil
+	}
+
 	return PieceStatus{
 		InPiece:      true,
 		PieceName:    pieceName,
@@ -361,32 +501,41 @@ func (h *Handler) Status(workDir string) (PieceStatus, error) {
 	}, nil
 }
 
-// GeneratePieceName generates a unique piece name with timestamp and counter
+// GeneratePieceName generates a unique piece name with timestamp and counter,
+// reserving it by creating its directory in baseDir. Creating a directory is
+// atomic, so concurrent creations (e.g. CLI and MCP) never pick the same name.
+// Callers that don't use the name must remove the directory.
 func (h *Handler) GeneratePieceName(baseDir string) (string, error) {
+	if err := h.deps.FS.MkdirAll(baseDir, DefaultDirPerm); err != nil {
+		return "", fmt.Errorf("failed to create pieces directory at %s: %w", baseDir, err)
+	}
+
 	timestamp := time.Now().Format("20060102-150405")
 	baseName := fmt.Sprintf("piece-%s", timestamp)
 
-	// Check for existing pieces and increment counter if needed
-	counter := 0
-	for {
+	// Retry with a counter while the name is taken; the limit avoids an
+	// endless loop
+	for counter := 0; counter <= 1000; counter++ {
 		pieceName := baseName
 		if counter > 0 {
 			pieceName = fmt.Sprintf("%s-%d", baseName, counter)
 		}
 
-		piecePath := filepath.Join(baseDir, pieceName)
-		_, err := h.deps.FS.Stat(piecePath)
-		if err != nil {
-			// Path doesn't exist, we can use this name
+		err := h.reservePieceName(baseDir, pieceName)
+		if err == nil {
 			return pieceName, nil
 		}
-
-		counter++
-		// Safety limit to avoid infinite loop
-		if counter > 1000 {
-			return "", fmt.Errorf("too many pieces with similar names")
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("failed to reserve piece name %q: %w", pieceName, err)
 		}
 	}
+	return "", fmt.Errorf("too many pieces with similar names")
+}
+
+// reservePieceName atomically creates the piece's directory, failing with
+// os.ErrExist if another piece has the name
+func (h *Handler) reservePieceName(baseDir, pieceName string) error {
+	return h.deps.FS.Mkdir(filepath.Join(baseDir, pieceName), DefaultDirPerm)
 }
 
 // UpdatePiece merges the main branch into the current piece's history
@@ -398,7 +547,7 @@ func (h *Handler) UpdatePiece(workDir, mainBranch string) error {
 	}
 
 	if !status.InPiece {
-		return fmt.Errorf("not in a piece worktree")
+		return core.NewNotInPieceError()
 	}
 
 	// Get current branch to verify we're on a branch
@@ -413,6 +562,7 @@ func (h *Handler) UpdatePiece(workDir, mainBranch string) error {
 		WorktreePath: status.WorktreePath,
 		RepoRoot:     status.RepoRoot,
 		MainBranch:   mainBranch,
+		SourceDir:    h.resolveSourceDir(status.WorktreePath),
 	}
 
 	// Run before-piece-update hook
@@ -430,6 +580,8 @@ func (h *Handler) UpdatePiece(workDir, mainBranch string) error {
 		return fmt.Errorf("after-piece-update hook failed: %w", err)
 	}
 
+	h.logPieceEvent(status.RepoRoot, EventPieceUpdate, status.PieceName, map[string]any{"from": mainBranch})
+
 	h.deps.Output.Write(core.Message{
 		Type:    core.MsgSuccess,
 		Content: fmt.Sprintf("Merged %s into %s", mainBranch, currentBranch),
@@ -438,9 +590,37 @@ func (h *Handler) UpdatePiece(workDir, mainBranch string) error {
 	return nil
 }
 
-// MergePiece squash-merges the piece branch back into main as a single commit.
-// Fails if main has commits that are not in the piece worktree.
+// MergeOptions configures MergePieceWithOptions
+type MergeOptions struct {
+	// Strategy overrides merge.strategy from the config: squash, merge or
+	// rebase-ff
+	Strategy string
+	// SquashCheckpoints collapses runs of checkpoint commits into one line in
+	// the squash commit message instead of listing each
+	SquashCheckpoints bool
+	// Push pushes the merged branch to the remote. Branch protection that
+	// blocks direct pushes is checked before merging, and a refused push
+	// rolls the merge back.
+	Push bool
+}
+
+// MergePiece merges the piece branch back into main, as a single squashed
+// commit unless merge.strategy says otherwise.
+// Fails if the verify commands of the piece's template or its issue prefix
+// check (see VerifyPiece) fail, if main has
+// commits that are not in the piece worktree, or if the main
+// repository has uncommitted changes or an unfinished rebase or merge. If the
+// checkout, merge or commit fails, main is reset and the previous checkout
+// restored; once the commit is made, the merge stands.
 func (h *Handler) MergePiece(workDir, mainBranch string) error {
+	return h.MergePieceWithOptions(workDir, mainBranch, MergeOptions{})
+}
+
+// MergePieceWithOptions merges the piece branch back into main as MergePiece
+// does, configured by opts. With the rebase-ff strategy, the piece branch is
+// first rebased onto main, so its worktree must be clean and main may be
+// ahead of it; it is reset if the fast-forward fails.
+func (h *Handler) MergePieceWithOptions(workDir, mainBranch string, opts MergeOptions) error {
 	// Check if we're in a piece worktree
 	status, err := h.Status(workDir)
 	if err != nil {
@@ -448,7 +628,7 @@ func (h *Handler) MergePiece(workDir, mainBranch string) error {
 	}
 
 	if !status.InPiece {
-		return fmt.Errorf("not in a piece worktree")
+		return core.NewNotInPieceError()
 	}
 
 	// Get current branch (piece branch)
@@ -458,17 +638,31 @@ func (h *Handler) MergePiece(workDir, mainBranch string) error {
 	}
 
 	// Get main repo root
-	mainRepoRoot, err := h.git.GetMainRepoRoot(workDir)
+	mainRepoRoot, err := h.mainRepoRoot(workDir)
 	if err != nil {
 		return fmt.Errorf("failed to get main repo root: %w", err)
 	}
 
+	strategy, err := h.MergeStrategy(mainRepoRoot, opts.Strategy)
+	if err != nil {
+		return err
+	}
+	if opts.SquashCheckpoints && strategy != MergeStrategySquash {
+		return fmt.Errorf("--squash-checkpoints only applies to the squash strategy; fold checkpoints with 'mp piece tidy' before a %s merge", strategy)
+	}
+	if opts.Push {
+		if err := h.CheckPushAllowed(mainRepoRoot, mainBranch); err != nil {
+			return err
+		}
+	}
+
 	// Build hook context
 	hookCtx := HookContext{
 		PieceName:    status.PieceName,
 		WorktreePath: status.WorktreePath,
 		RepoRoot:     mainRepoRoot,
 		MainBranch:   mainBranch,
+		SourceDir:    h.resolveSourceDir(status.WorktreePath),
 	}
 
 	// Run before-piece-merge hook
@@ -476,38 +670,103 @@ func (h *Handler) MergePiece(workDir, mainBranch string) error {
 		return fmt.Errorf("before-piece-merge hook failed: %w", err)
 	}
 
-	// Check if main has commits not in the piece branch
-	isAhead, err := h.git.IsMainAhead(mainRepoRoot, mainBranch, pieceBranch)
+	// Run the verify commands of the piece's template
+	verified, err := h.verify(status)
 	if err != nil {
-		return fmt.Errorf("failed to check if main is ahead: %w", err)
+		return err
+	}
+	if !verified.Passed {
+		what := "verification"
+		if verified.Template != "" {
+			what = "verify commands of template " + verified.Template
+		}
+		return fmt.Errorf("cannot merge: %s failed: %s. Run 'mp piece verify' for details",
+			what, strings.Join(verified.failed(), ", "))
 	}
 
-	if isAhead {
-		return fmt.Errorf("cannot merge: main branch has commits not in piece worktree. Run 'mp piece update' first")
+	// Check if main has commits not in the piece branch; the rebase of
+	// rebase-ff brings them in itself
+	if strategy != MergeStrategyRebaseFF {
+		isAhead, err := h.git.IsMainAhead(mainRepoRoot, mainBranch, pieceBranch)
+		if err != nil {
+			return fmt.Errorf("failed to check if main is ahead: %w", err)
+		}
+
+		if isAhead {
+			return fmt.Errorf("cannot merge: %s has commits not in piece worktree. Run 'mp piece update --main-branch %s' first", mainBranch, mainBranch)
+		}
 	}
 
-	// Get commit messages from piece branch for the squash commit message
-	commitMsgs, err := h.git.GetCommitMessages(mainRepoRoot, mainBranch, pieceBranch)
+	// Get the piece branch's commits for the commit message
+	commits, err := h.git.CommitLog(mainRepoRoot, mainBranch, pieceBranch)
 	if err != nil {
 		return fmt.Errorf("failed to get commit messages: %w", err)
 	}
 
-	// Build squash commit message
-	commitMsg := h.buildSquashCommitMessage(status.PieceName, commitMsgs)
+	// Refuse to touch a main checkout that is dirty or mid-operation, and
+	// remember its state to roll back to
+	checkout, err := h.prepareMainCheckout(mainRepoRoot, mainBranch)
+	if err != nil {
+		return err
+	}
+
+	op := operation.New(h.deps.Output, fmt.Sprintf("merge %s into %s", pieceBranch, mainBranch))
+	var rebasedFrom string
+	if strategy == MergeStrategyRebaseFF {
+		if rebasedFrom, err = h.rebasePiece(op, status.WorktreePath, mainBranch); err != nil {
+			return op.Fail(err)
+		}
+	}
 
 	// Switch to main branch
 	if err := h.git.Checkout(mainRepoRoot, mainBranch); err != nil {
-		return fmt.Errorf("failed to checkout main branch: %w", err)
+		return op.Fail(fmt.Errorf("failed to checkout main branch: %w", err))
 	}
+	h.undoMainCheckout(op, checkout)
 
-	// Squash merge the piece branch into main
-	if err := h.git.MergeSquash(mainRepoRoot, pieceBranch); err != nil {
-		return fmt.Errorf("failed to squash merge piece branch into main: %w", err)
+	var content string
+	switch strategy {
+	case MergeStrategyMerge:
+		if err := h.git.MergeNoFF(mainRepoRoot, pieceBranch, mergeCommitMessage(status.PieceName, mainBranch)); err != nil {
+			return op.Fail(err)
+		}
+		content = fmt.Sprintf("Merged %s into %s with a merge commit", pieceBranch, mainBranch)
+	case MergeStrategyRebaseFF:
+		if err := h.git.MergeFFOnly(mainRepoRoot, pieceBranch); err != nil {
+			return op.Fail(err)
+		}
+		content = fmt.Sprintf("Rebased %s onto %s and fast-forwarded %s", pieceBranch, mainBranch, mainBranch)
+		h.warnRewrittenRemote(status.WorktreePath, pieceBranch, rebasedFrom)
+	default:
+		// Squash merge the piece branch into main
+		if err := h.git.MergeSquash(mainRepoRoot, pieceBranch); err != nil {
+			return op.Fail(fmt.Errorf("failed to squash merge piece branch into main: %w", err))
+		}
+
+		// Commit the squashed changes
+		commitMsg := h.buildSquashCommitMessage(status.PieceName, commits, opts.SquashCheckpoints)
+		if err := h.git.Commit(mainRepoRoot, commitMsg); err != nil {
+			return op.Fail(fmt.Errorf("failed to commit squashed changes: %w", err))
+		}
+		content = fmt.Sprintf("Squash merged %s into %s", pieceBranch, mainBranch)
+	}
+
+	if opts.Push {
+		if err := h.git.PushBranch(mainRepoRoot, mainBranch); err != nil {
+			return op.Fail(err)
+		}
+		content += fmt.Sprintf(" and pushed %s to %s", mainBranch, h.git.Remote())
 	}
 
-	// Commit the squashed changes
-	if err := h.git.Commit(mainRepoRoot, commitMsg); err != nil {
-		return fmt.Errorf("failed to commit squashed changes: %w", err)
+	// A squash commit is not an ancestor of the branch; record what was
+	// merged so cleanup can tell
+	if head, err := h.git.GetBranchCommit(mainRepoRoot, pieceBranch); err == nil {
+		if err := h.updatePieceMetadata(status.WorktreePath, func(m *PieceMetadata) { m.MergedCommit = head }); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to record merged commit: %v", err),
+			})
+		}
 	}
 
 	// Run after-piece-merge hook
@@ -515,31 +774,118 @@ func (h *Handler) MergePiece(workDir, mainBranch string) error {
 		return fmt.Errorf("after-piece-merge hook failed: %w", err)
 	}
 
+	h.logPieceEvent(mainRepoRoot, EventPieceMerge, status.PieceName, map[string]any{"into": mainBranch, "strategy": strategy, "pushed": opts.Push})
+
 	h.deps.Output.Write(core.Message{
 		Type:    core.MsgSuccess,
-		Content: fmt.Sprintf("Squash merged %s into %s", pieceBranch, mainBranch),
+		Content: content,
 	})
 
 	return nil
 }
 
-// buildSquashCommitMessage creates a commit message for squash merge
-func (h *Handler) buildSquashCommitMessage(pieceName string, commitMsgs []string) string {
+// rebasePiece rebases the piece in worktree onto target for a rebase-ff
+// merge, registering the reset of the piece branch to its old head with op.
+// The worktree must be clean, as the rebase checks out each commit. Returns
+// the old head.
+func (h *Handler) rebasePiece(op *operation.Runner, worktree, target string) (string, error) {
+	if err := h.checkNoOperation(worktree); err != nil {
+		return "", err
+	}
+	dirty, err := h.git.HasUncommittedChanges(worktree)
+	if err != nil {
+		return "", err
+	}
+	if dirty {
+		return "", core.NewDirtyWorktreeError(worktree,
+			fmt.Errorf("cannot rebase: the piece worktree %s has uncommitted changes", worktree))
+	}
+	head, err := h.git.GetBranchCommit(worktree, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	op.Undo("reset the piece branch to "+shortCommit(head), func() error {
+		return h.git.ResetHard(worktree, head)
+	})
+	if err := h.git.RebaseOnto(worktree, target); err != nil {
+		_ = h.git.RebaseAbort(worktree)
+		return "", err
+	}
+	return head, nil
+}
+
+// warnRewrittenRemote warns when the piece branch was rewritten from oldHead,
+// by a rebase-ff merge or Tidy, while the remote still has its old history
+func (h *Handler) warnRewrittenRemote(worktree, branch, oldHead string) {
+	head, err := h.git.GetBranchCommit(worktree, "HEAD")
+	if err != nil || head == oldHead || !h.git.HasRemoteTrackingBranch(worktree, branch) {
+		return
+	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgWarning,
+		Content: fmt.Sprintf("%s is on the remote with its old history; push it with --force-with-lease", branch),
+	})
+}
+
+// mergeCommitMessage is the message of the merge commit of the merge strategy
+func mergeCommitMessage(pieceName, target string) string {
+	return fmt.Sprintf("Merge piece %s into %s", pieceName, target)
+}
+
+// buildSquashCommitMessage creates a commit message for squash merge. Trailers of
+// the squashed commits (e.g. Co-authored-by) are appended once each, so
+// attribution survives the squash. With squashCheckpoints, runs of checkpoint
+// commits are listed as one line each.
+func (h *Handler) buildSquashCommitMessage(pieceName string, commits []adapters.Commit, squashCheckpoints bool) string {
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("feat: %s\n", pieceName))
 
-	if len(commitMsgs) > 0 {
+	if len(commits) > 0 {
 		b.WriteString("\nSquashed commits:\n")
-		for _, msg := range commitMsgs {
-			b.WriteString(fmt.Sprintf("- %s\n", msg))
+		for _, subject := range squashedCommitList(commits, squashCheckpoints) {
+			b.WriteString(fmt.Sprintf("- %s\n", subject))
+		}
+	}
+
+	if trailers := squashTrailers(commits); len(trailers) > 0 {
+		b.WriteString("\n")
+		for _, t := range trailers {
+			b.WriteString(t.String() + "\n")
 		}
 	}
 
 	return b.String()
 }
 
-// getPiecesDir returns the directory for storing pieces, using XDG_DATA_HOME
-func getPiecesDir() (string, error) {
+// squashTrailers returns the trailers of commits (newest first, as logged) in
+// the order they were added, without duplicates. Keys compare case-insensitively.
+func squashTrailers(commits []adapters.Commit) []adapters.Trailer {
+	var trailers []adapters.Trailer
+	seen := map[string]bool{}
+	for i := len(commits) - 1; i >= 0; i-- {
+		for _, t := range commits[i].Trailers {
+			key := strings.ToLower(t.Key) + ":" + t.Value
+			if !seen[key] {
+				seen[key] = true
+				trailers = append(trailers, t)
+			}
+		}
+	}
+	return trailers
+}
+
+// PiecesDir returns the directory piece worktrees are created in, using XDG_DATA_HOME
+func PiecesDir() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "pieces"), nil
+}
+
+// DataDir returns monkeypuzzle's per-user data directory ($XDG_DATA_HOME/monkeypuzzle,
+// defaulting to ~/.local/share/monkeypuzzle)
+func DataDir() (string, error) {
 	dataHome := os.Getenv("XDG_DATA_HOME")
 	if dataHome == "" {
 		home, err := os.UserHomeDir()
@@ -548,14 +894,15 @@ func getPiecesDir() (string, error) {
 		}
 		dataHome = filepath.Join(home, ".local", "share")
 	}
-	return filepath.Join(dataHome, "monkeypuzzle", "pieces"), nil
+	return filepath.Join(dataHome, "monkeypuzzle"), nil
 }
 
 // MergeStatus represents the merge status of a branch
 type MergeStatus struct {
 	// IsMerged is true if the branch has been merged to main
 	IsMerged bool `json:"is_merged"`
-	// Method indicates how the merge was detected: "pr", "git", or "commit"
+	// Method indicates how the merge was detected: "pr", "pr-branch",
+	// "mp-merge", "git", or "commit"
 	Method string `json:"method,omitempty"`
 	// PRNumber is set if merge was detected via PR status
 	PRNumber int `json:"pr_number,omitempty"`
@@ -564,8 +911,10 @@ type MergeStatus struct {
 }
 
 // IsBranchMerged checks if a piece branch has been merged to main.
-// Detection priority: 1) PR metadata, 2) gh pr list by branch, 3) git branch --merged, 4) commit history
+// Detection priority: 1) PR metadata, 2) gh pr list by branch, 3) mp piece
+// merge, 4) git branch --merged, 5) commit history
 func (h *Handler) IsBranchMerged(repoRoot, branchName, mainBranch string) (MergeStatus, error) {
+	h.useConfiguredRemote(repoRoot)
 	status := MergeStatus{}
 
 	// Check if branch exists on remote
@@ -597,7 +946,16 @@ func (h *Handler) IsBranchMerged(repoRoot, branchName, mainBranch string) (Merge
 		return status, nil
 	}
 
-	// Method 3: Check via git branch --merged
+	// Method 3: Check for a squash merge by mp piece merge of the branch's head
+	if metadata, err := OpenMetadataStore(h.deps, repoRoot).ReadPieceMetadata(); err == nil && metadata.MergedCommit != "" {
+		if head, err := h.git.GetBranchCommit(repoRoot, branchName); err == nil && head == metadata.MergedCommit {
+			status.IsMerged = true
+			status.Method = "mp-merge"
+			return status, nil
+		}
+	}
+
+	// Method 4: Check via git branch --merged
 	merged, err = h.git.IsBranchMerged(repoRoot, mainBranch, branchName)
 	if err != nil {
 		// Log warning but continue to fallback
@@ -611,7 +969,7 @@ func (h *Handler) IsBranchMerged(repoRoot, branchName, mainBranch string) (Merge
 		return status, nil
 	}
 
-	// Method 4: Fallback - check if branch HEAD commit is in main history
+	// Method 5: Fallback - check if branch HEAD commit is in main history
 	merged, err = h.checkCommitMerged(repoRoot, branchName, mainBranch)
 	if err != nil {
 		// This is the last resort, so return error
@@ -626,18 +984,24 @@ func (h *Handler) IsBranchMerged(repoRoot, branchName, mainBranch string) (Merge
 	return status, nil
 }
 
+// errNoPRMetadata means a piece has no PR recorded, so there is no PR to check
+var errNoPRMetadata = errors.New("no PR metadata found")
+
 // checkPRMergeStatus checks if a PR associated with the piece has been merged.
 // Returns (merged, prNumber, error).
 func (h *Handler) checkPRMergeStatus(worktreePath string) (bool, int, error) {
 	// Try to read PR metadata from the piece
-	metadata, err := ReadPRMetadata(worktreePath, h.deps.FS)
+	metadata, err := OpenMetadataStore(h.deps, worktreePath).ReadPRMetadata()
 	if err != nil {
-		// No PR metadata - skip this check
-		return false, 0, fmt.Errorf("no PR metadata found: %w", err)
+		if errors.Is(err, os.ErrNotExist) {
+			// No PR metadata - skip this check
+			return false, 0, fmt.Errorf("%w: %w", errNoPRMetadata, err)
+		}
+		return false, 0, err
 	}
 
 	if metadata.PRNumber == 0 {
-		return false, 0, fmt.Errorf("PR number not set in metadata")
+		return false, 0, fmt.Errorf("%w: PR number not set", errNoPRMetadata)
 	}
 
 	// Check if PR is merged using gh CLI
@@ -667,6 +1031,15 @@ type CleanupResult struct {
 	WorktreePath string `json:"worktree_path"`
 	IssuePath    string `json:"issue_path,omitempty"`
 	IssueUpdated bool   `json:"issue_updated,omitempty"`
+	// Discrepancy is set for pieces whose directory and git worktree disagree
+	// (see the Discrepancy constants). Only missing worktrees are cleaned up.
+	Discrepancy string `json:"discrepancy,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Cleaned reports whether the piece was (or, in a dry run, would be) removed
+func (r CleanupResult) Cleaned() bool {
+	return r.Discrepancy == "" || r.Discrepancy == DiscrepancyMissing
 }
 
 // CleanupOptions configures the cleanup behavior
@@ -674,36 +1047,52 @@ type CleanupOptions struct {
 	DryRun     bool   // If true, only report what would be cleaned
 	Force      bool   // If true, skip confirmation prompts (unused for now)
 	MainBranch string // Main branch name to check for merged status
+	// Pieces limits cleanup to the named pieces (default: every piece)
+	Pieces []string
 }
 
 // CleanupMergedPieces finds and cleans up pieces whose branches have been merged.
-// It removes worktrees, kills tmux sessions, and updates issue status to done.
+// Pieces are taken from git worktree list as well as the pieces directory; pieces
+// where the two disagree, and locked pieces, are reported rather than skipped silently.
 func (h *Handler) CleanupMergedPieces(repoRoot string, opts CleanupOptions) ([]CleanupResult, error) {
 	// Get pieces directory
-	piecesDir, err := getPiecesDir()
+	piecesDir, err := PiecesDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pieces directory: %w", err)
 	}
 
-	// List all piece directories
-	entries, err := h.deps.FS.ReadDir(piecesDir)
+	pieces, err := h.pieceWorktrees(repoRoot, piecesDir)
 	if err != nil {
-		// If pieces directory doesn't exist, no pieces to clean
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read pieces directory: %w", err)
+		return nil, err
+	}
+	if len(pieces) == 0 {
+		return nil, nil
 	}
 
+	// Migrate pieces created before local artifacts were excluded, or with a
+	// source symlink
+	h.ensureExcludes(repoRoot)
+	h.migrateSourceSymlinks(pieces)
+
 	var results []CleanupResult
+	prune := false
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
+	for _, p := range pieces {
+		if len(opts.Pieces) > 0 && !slices.Contains(opts.Pieces, p.name) {
+			continue
+		}
+		pieceName := p.name
+		worktreePath := p.path
+
+		if result, ok := h.worktreeDiscrepancy(p, opts.DryRun); ok {
+			if result.Discrepancy == DiscrepancyMissing && !opts.DryRun {
+				prune = true
+				_ = h.tmux.KillSession(SessionName(pieceName))
+				h.logPieceEvent(repoRoot, EventPieceRemove, pieceName, map[string]any{"reason": RemoveReasonMissing})
+			}
+			results = append(results, result)
 			continue
 		}
-
-		pieceName := entry.Name()
-		worktreePath := filepath.Join(piecesDir, pieceName)
 
 		// Get the branch name from the worktree
 		branchName, err := h.git.CurrentBranch(worktreePath)
@@ -715,8 +1104,8 @@ func (h *Handler) CleanupMergedPieces(repoRoot string, opts CleanupOptions) ([]C
 			continue
 		}
 
-		// Check if branch is merged
-		mergeStatus, err := h.IsBranchMerged(worktreePath, branchName, opts.MainBranch)
+		// Check if branch is merged into the piece's own base, if one was recorded
+		mergeStatus, err := h.IsBranchMerged(worktreePath, branchName, h.BaseBranch(worktreePath, opts.MainBranch))
 		if err != nil {
 			h.deps.Output.Write(core.Message{
 				Type:    core.MsgWarning,
@@ -726,6 +1115,7 @@ func (h *Handler) CleanupMergedPieces(repoRoot string, opts CleanupOptions) ([]C
 		}
 
 		if !mergeStatus.IsMerged {
+			h.warnRemoteProblem(pieceName, worktreePath, branchName)
 			continue
 		}
 
@@ -758,6 +1148,8 @@ func (h *Handler) CleanupMergedPieces(repoRoot string, opts CleanupOptions) ([]C
 			continue
 		}
 
+		h.logPieceEvent(repoRoot, EventPieceRemove, pieceName, map[string]any{"reason": RemoveReasonCleanup})
+
 		// Update issue status to done if marker exists
 		if result.IssuePath != "" {
 			absIssuePath := filepath.Join(repoRoot, result.IssuePath)
@@ -768,6 +1160,7 @@ func (h *Handler) CleanupMergedPieces(repoRoot string, opts CleanupOptions) ([]C
 				})
 			} else {
 				result.IssueUpdated = true
+				h.SyncIssueTitles(repoRoot, result.IssuePath)
 			}
 		}
 
@@ -779,40 +1172,97 @@ func (h *Handler) CleanupMergedPieces(repoRoot string, opts CleanupOptions) ([]C
 		results = append(results, result)
 	}
 
+	if prune {
+		if _, err := h.git.WorktreePrune(repoRoot); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to prune missing worktrees: %v", err),
+			})
+		}
+	}
+
 	return results, nil
 }
 
-// readCurrentIssueMarker reads the current issue marker from a piece worktree.
-func (h *Handler) readCurrentIssueMarker(worktreePath string) (*CurrentIssueMarker, error) {
-	markerPath := filepath.Join(worktreePath, initcmd.DirName, "current-issue.json")
-	data, err := h.deps.FS.ReadFile(markerPath)
-	if err != nil {
-		return nil, err
+// worktreeDiscrepancy reports a piece whose directory and git worktree disagree,
+// or whose worktree is locked
+func (h *Handler) worktreeDiscrepancy(p pieceWorktree, dryRun bool) (CleanupResult, bool) {
+	result := CleanupResult{PieceName: p.name, WorktreePath: p.path}
+	var content string
+	msgType := core.MsgWarning
+	switch {
+	case p.worktree == nil:
+		result.Discrepancy = DiscrepancyNotWorktree
+		result.Reason = "git has no worktree for this directory"
+		content = fmt.Sprintf("Skipping %s: %s (remove the directory if it is no longer needed)", p.name, result.Reason)
+	case p.worktree.Locked:
+		result.Discrepancy = DiscrepancyLocked
+		result.Reason = p.worktree.LockReason
+		content = fmt.Sprintf("Skipping %s: worktree is locked", p.name)
+		if result.Reason != "" {
+			content += ": " + result.Reason
+		}
+		content += fmt.Sprintf(" (run 'mp piece unlock %s' to allow cleanup)", p.name)
+	case p.worktree.Prunable:
+		result.Discrepancy = DiscrepancyMissing
+		result.Reason = p.worktree.PrunableReason
+		msgType = core.MsgInfo
+		content = fmt.Sprintf("Pruned missing worktree: %s", p.name)
+		if dryRun {
+			content = fmt.Sprintf("[dry-run] Would prune missing worktree: %s", p.name)
+		}
+	default:
+		return result, false
 	}
+	h.deps.Output.Write(core.Message{Type: msgType, Content: content, Data: result})
+	return result, true
+}
 
-	var marker CurrentIssueMarker
-	if err := json.Unmarshal(data, &marker); err != nil {
-		return nil, err
+// warnRemoteProblem warns when an unmerged piece's branch was force-updated
+// or deleted upstream, since its merge status can't be trusted.
+func (h *Handler) warnRemoteProblem(pieceName, worktreePath, branchName string) {
+	remote, err := h.CheckRemoteBranch(worktreePath, branchName)
+	if err != nil || !remote.Problem() {
+		return
 	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgWarning,
+		Content: fmt.Sprintf("Skipping %s: %s (run 'mp piece doctor' in the piece to inspect)", pieceName, remote.Describe()),
+		Data:    remote,
+	})
+}
 
-	return &marker, nil
+// readCurrentIssueMarker reads the current issue marker from a piece's metadata store.
+func (h *Handler) readCurrentIssueMarker(worktreePath string) (*CurrentIssueMarker, error) {
+	return OpenMetadataStore(h.deps, worktreePath).ReadIssueMarker()
 }
 
-// removePiece removes a piece worktree and associated tmux session.
+// removePiece removes a piece worktree and associated tmux session. If the
+// worktree can't be removed, the session is started again.
 func (h *Handler) removePiece(repoRoot, pieceName, worktreePath string) error {
-	sessionName := fmt.Sprintf("mp-piece-%s", pieceName)
-
-	// Kill tmux session (ignore errors - session may not exist)
-	_ = h.tmux.KillSession(sessionName)
+	op := operation.New(h.deps.Output, "clean up piece "+pieceName)
+	h.killPieceSession(op, pieceName, worktreePath)
 
 	// Remove worktree
 	if err := h.git.WorktreeRemove(repoRoot, worktreePath); err != nil {
-		return fmt.Errorf("failed to remove worktree: %w", err)
+		return op.Fail(fmt.Errorf("failed to remove worktree: %w", err))
 	}
 
 	return nil
 }
 
+// killPieceSession kills a piece's tmux session, registering with op a new
+// session in its place. Errors are ignored: the session may not exist.
+func (h *Handler) killPieceSession(op *operation.Runner, pieceName, worktreePath string) {
+	sessionName := SessionName(pieceName)
+	if err := h.tmux.KillSession(sessionName); err != nil {
+		return
+	}
+	op.Undo("start tmux session "+sessionName, func() error {
+		return h.tmux.NewSession(sessionName, worktreePath)
+	})
+}
+
 // updateIssueStatusToDone updates the issue status to done if currently in-progress.
 func (h *Handler) updateIssueStatusToDone(issuePath string) error {
 	// Check current status
@@ -833,3 +1283,22 @@ func (h *Handler) updateIssueStatusToDone(issuePath string) error {
 
 	return nil
 }
+
+// updateIssueStatusToTodo reverts the issue status to todo if currently in-progress,
+// used when a piece stops working on the issue before it is done.
+func (h *Handler) updateIssueStatusToTodo(issuePath string) error {
+	currentStatus, err := ParseStatus(issuePath, h.deps.FS)
+	if err != nil {
+		return fmt.Errorf("failed to read issue status: %w", err)
+	}
+
+	if currentStatus != StatusInProgress {
+		return nil
+	}
+
+	if err := UpdateStatus(issuePath, StatusTodo, h.deps.FS); err != nil {
+		return fmt.Errorf("failed to update issue status: %w", err)
+	}
+
+	return nil
+}
@@ -3,7 +3,6 @@
 package piece_test
 
 import (
-	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -493,16 +492,18 @@ This is a great feature.
 		t.Errorf("expected piece name %q, got %q", expectedName, info.Name)
 	}
 
-	// Verify marker file exists
-	markerPath := filepath.Join(info.WorktreePath, ".monkeypuzzle", "current-issue.json")
-	markerData, err := os.ReadFile(markerPath)
-	if err != nil {
-		t.Fatalf("marker file not found: %v", err)
+	// Verify marker file exists in the worktree's git dir, not the worktree
+	store := piece.OpenMetadataStore(deps, info.WorktreePath)
+	if !strings.Contains(store.Dir(), filepath.Join(".git", "worktrees")) {
+		t.Errorf("expected metadata under .git/worktrees, got %s", store.Dir())
+	}
+	if _, err := os.Stat(filepath.Join(info.WorktreePath, ".monkeypuzzle", "current-issue.json")); err == nil {
+		t.Error("expected no marker file inside the worktree")
 	}
 
-	var marker piece.CurrentIssueMarker
-	if err := json.Unmarshal(markerData, &marker); err != nil {
-		t.Fatalf("failed to unmarshal marker: %v", err)
+	marker, err := store.ReadIssueMarker()
+	if err != nil {
+		t.Fatalf("marker file not found: %v", err)
 	}
 
 	if marker.IssueName != "My Awesome Feature" {
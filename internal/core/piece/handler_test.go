@@ -2,14 +2,18 @@ package piece_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
 )
 
@@ -103,6 +107,7 @@ func TestHandler_Status_InWorktree(t *testing.T) {
 	gitDir := "/repo/.git/worktrees/piece-1"
 	worktreePath := "/pieces/piece-1"
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 
 	status, err := handler.Status("/pieces/piece-1")
@@ -240,7 +245,7 @@ func TestHandler_CreatePiece_NameAlreadyExists(t *testing.T) {
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(repoRoot+"\n"), nil)
 
 	// Get the actual pieces directory that will be used
-	// This matches what getPiecesDir() returns
+	// This matches what PiecesDir() returns
 	piecesDir := "/test-data/monkeypuzzle/pieces"
 	existingPiecePath := filepath.Join(piecesDir, "existing-piece")
 
@@ -260,6 +265,54 @@ func TestHandler_CreatePiece_NameAlreadyExists(t *testing.T) {
 	}
 }
 
+func TestHandler_GeneratePieceName_Concurrent(t *testing.T) {
+	baseDir := t.TempDir()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewOSFS(""), Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()})
+
+	const creators = 20
+	names := make(chan string, creators)
+	var wg sync.WaitGroup
+	for range creators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name, err := handler.GeneratePieceName(baseDir)
+			if err != nil {
+				t.Errorf("GeneratePieceName failed: %v", err)
+			}
+			names <- name
+		}()
+	}
+	wg.Wait()
+	close(names)
+
+	seen := make(map[string]bool)
+	for name := range names {
+		if seen[name] {
+			t.Errorf("name %s was generated twice", name)
+		}
+		seen[name] = true
+		if info, err := os.Stat(filepath.Join(baseDir, name)); err != nil || !info.IsDir() {
+			t.Errorf("expected %s to be reserved, got %v", name, err)
+		}
+	}
+}
+
+func TestHandler_CreatePiece_WorktreeFailureReleasesName(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+
+	if _, err := handler.CreatePiece("/monkeypuzzle", "p1"); err == nil {
+		t.Fatal("expected the unmocked worktree add to fail")
+	}
+	if _, err := fs.Stat("/test-data/monkeypuzzle/pieces/p1"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the reserved directory to be removed, got %v", err)
+	}
+}
+
 func TestHandler_UpdatePiece_InWorktree(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	out := adapters.NewBufferOutput()
@@ -271,6 +324,7 @@ func TestHandler_UpdatePiece_InWorktree(t *testing.T) {
 	gitDir := "/repo/.git/worktrees/piece-1"
 	worktreePath := "/pieces/piece-1"
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 
 	// Setup mock responses for update
@@ -326,6 +380,7 @@ func TestHandler_MergePiece_Success(t *testing.T) {
 	gitDir := "/repo/.git/worktrees/piece-1"
 	worktreePath := "/pieces/piece-1"
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 
 	// Setup mock responses for merge piece
@@ -333,9 +388,10 @@ func TestHandler_MergePiece_Success(t *testing.T) {
 	// IsMainAhead: merge-base and rev-list
 	mockExec.AddResponse("git", []string{"merge-base", "main", "piece-1"}, []byte("abc123\n"), nil)
 	mockExec.AddResponse("git", []string{"rev-list", "--count", "abc123..main"}, []byte("0\n"), nil) // main is not ahead
-	// GetCommitMessages for squash commit message
-	mockExec.AddResponse("git", []string{"log", "--format=%s", "main..piece-1"}, []byte("feat: add feature\nfix: bug fix\n"), nil)
+	// Commit log for squash commit message
+	mockCommitLog(mockExec, "main..piece-1", adapters.Commit{Subject: "feat: add feature"}, adapters.Commit{Subject: "fix: bug fix"})
 	// Checkout, squash merge, and commit
+	mockMainCheckout(mockExec, "main")
 	mockExec.AddResponse("git", []string{"checkout", "main"}, nil, nil)
 	mockExec.AddResponse("git", []string{"merge", "--squash", "piece-1"}, nil, nil)
 	commitMsg := "feat: piece-1\n\nSquashed commits:\n- feat: add feature\n- fix: bug fix\n"
@@ -360,6 +416,30 @@ func TestHandler_MergePiece_Success(t *testing.T) {
 	}
 }
 
+func TestHandler_MergePiece_PropagatesTrailers(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"merge-base", "main", "piece-1"}, []byte("abc123\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-list", "--count", "abc123..main"}, []byte("0\n"), nil)
+	bob := adapters.Trailer{Key: "Co-authored-by", Value: "Bob <bob@example.com>"}
+	mockCommitLog(mockExec, "main..piece-1",
+		adapters.Commit{Subject: "fix: edge case", Trailers: []adapters.Trailer{{Key: "co-authored-by", Value: bob.Value}, {Key: "Reviewed-by", Value: "Carol <carol@example.com>"}}},
+		adapters.Commit{Subject: "feat: add feature", Trailers: []adapters.Trailer{bob}},
+	)
+	mockMainCheckout(mockExec, "main")
+	mockExec.AddResponse("git", []string{"checkout", "main"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge", "--squash", "piece-1"}, nil, nil)
+	commitMsg := "feat: piece-1\n\nSquashed commits:\n- fix: edge case\n- feat: add feature\n\n" +
+		"Co-authored-by: Bob <bob@example.com>\nReviewed-by: Carol <carol@example.com>\n"
+	mockExec.AddResponse("git", []string{"commit", "-m", commitMsg}, nil, nil)
+
+	if err := handler.MergePiece("/pieces/piece-1", "main"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !mockExec.WasCalled("git", "commit", "-m", commitMsg) {
+		t.Error("expected squash commit with deduplicated trailers")
+	}
+}
+
 func TestHandler_MergePiece_MainAhead(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	out := adapters.NewBufferOutput()
@@ -371,6 +451,7 @@ func TestHandler_MergePiece_MainAhead(t *testing.T) {
 	gitDir := "/repo/.git/worktrees/piece-1"
 	worktreePath := "/pieces/piece-1"
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 
 	// Setup mock responses - main is ahead
@@ -389,6 +470,66 @@ func TestHandler_MergePiece_MainAhead(t *testing.T) {
 	}
 }
 
+func TestHandler_MergePiece_DirtyMainRepo(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, []byte(" M README.md\n"), nil)
+
+	err := handler.MergePiece("/pieces/piece-1", "main")
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeDirtyWorktree {
+		t.Fatalf("expected dirty_worktree error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "/repo has uncommitted changes") {
+		t.Errorf("expected error to name the main repository, got %v", err)
+	}
+	if mockExec.WasCalled("git", "checkout", "main") {
+		t.Error("expected no checkout of a dirty main repository")
+	}
+}
+
+func TestHandler_MergePiece_OperationInProgress(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+	// The mock answers every git dir query with the piece's git dir
+	_ = fs.WriteFile("/repo/.git/worktrees/piece-1/MERGE_HEAD", []byte("abc123\n"), 0644)
+
+	err := handler.MergePiece("/pieces/piece-1", "main")
+	re, ok := core.AsRemediable(err)
+	if !ok || re.Code != core.CodeOperationInProgress {
+		t.Fatalf("expected operation_in_progress error, got %v", err)
+	}
+	if !strings.Contains(re.Hint, "git -C /repo merge --abort") {
+		t.Errorf("expected hint to abort the merge, got %q", re.Hint)
+	}
+	if mockExec.WasCalled("git", "checkout", "main") {
+		t.Error("expected no checkout during an unfinished merge")
+	}
+}
+
+func TestHandler_MergePiece_RollsBackFailedSquash(t *testing.T) {
+	_, out, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "HEAD"}, []byte("head0000\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "main"}, []byte("target00\n"), nil)
+	mockExec.AddResponse("git", []string{"checkout", "main"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge", "--squash", "piece-1"}, nil, errors.New("CONFLICT (content)"))
+	mockExec.AddResponse("git", []string{"reset", "--hard", "target00"}, nil, nil)
+	// The mock reports piece-1 as the branch of every checkout
+	mockExec.AddResponse("git", []string{"checkout", "piece-1"}, nil, nil)
+
+	err := handler.MergePiece("/pieces/piece-1", "main")
+	if err == nil || !strings.Contains(err.Error(), "failed to squash merge") {
+		t.Fatalf("expected squash merge error, got %v", err)
+	}
+	if !mockExec.WasCalled("git", "reset", "--hard", "target00") {
+		t.Error("expected main to be reset to its previous commit")
+	}
+	if !mockExec.WasCalled("git", "checkout", "piece-1") {
+		t.Error("expected the previous branch to be checked out again")
+	}
+	if !out.HasWarning() {
+		t.Error("expected a warning that the main repository was restored")
+	}
+}
+
 func TestHandler_MergePiece_NotInWorktree(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	out := adapters.NewBufferOutput()
@@ -411,6 +552,46 @@ func TestHandler_MergePiece_NotInWorktree(t *testing.T) {
 	}
 }
 
+func TestHandler_UpdatePiece_DetachedHead(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/piece-1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/pieces/piece-1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("HEAD\n"), nil)
+
+	err := handler.UpdatePiece("/pieces/piece-1", "main")
+	re, ok := core.AsRemediable(err)
+	if !ok || re.Code != core.CodeDetachedHead {
+		t.Fatalf("expected detached HEAD error, got: %v", err)
+	}
+	if re.Hint == "" {
+		t.Error("expected a remediation hint")
+	}
+}
+
+func TestHandler_MergePiece_MainBranchMissing(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/piece-1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/pieces/piece-1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("piece-1\n"), nil)
+	mockExec.AddResponse("git", []string{"merge-base", "trunk", "piece-1"},
+		[]byte("fatal: Not a valid object name trunk\n"), adapters.MockError("exit status 128"))
+
+	err := handler.MergePiece("/pieces/piece-1", "trunk")
+	re, ok := core.AsRemediable(err)
+	if !ok || re.Code != core.CodeMainBranchMissing {
+		t.Fatalf("expected main branch missing error, got: %v", err)
+	}
+	if !strings.Contains(re.Hint, "--main-branch") {
+		t.Errorf("expected hint to mention --main-branch, got %q", re.Hint)
+	}
+}
+
 // ============================================================================
 // Hook Integration Tests
 // ============================================================================
@@ -427,6 +608,7 @@ func TestHandler_UpdatePiece_BeforeHookFails(t *testing.T) {
 	worktreePath := "/pieces/piece-1"
 	repoRoot := "/repo"
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("piece-1\n"), nil)
 
@@ -467,6 +649,7 @@ func TestHandler_MergePiece_BeforeHookFails(t *testing.T) {
 	worktreePath := "/pieces/piece-1"
 	repoRoot := "/repo"
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("piece-1\n"), nil)
 
@@ -506,6 +689,7 @@ func TestHandler_UpdatePiece_NoHooks_Success(t *testing.T) {
 	gitDir := "/repo/.git/worktrees/piece-1"
 	worktreePath := "/pieces/piece-1"
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 
 	// Setup mock responses for update
@@ -556,6 +740,7 @@ func TestHandler_CreatePiece_OnPieceCreateHookFails_CleansUp(t *testing.T) {
 	// Mock cleanup commands
 	mockExec.AddResponse("tmux", []string{"kill-session", "-t", sessionName}, nil, nil)
 	mockExec.AddResponse("git", []string{"worktree", "remove", worktreePath}, nil, nil)
+	mockExec.AddResponse("git", []string{"branch", "-D", pieceName}, nil, nil)
 
 	// Execute
 	_, err := handler.CreatePiece("/monkeypuzzle", pieceName)
@@ -578,6 +763,22 @@ func TestHandler_CreatePiece_OnPieceCreateHookFails_CleansUp(t *testing.T) {
 	if !mockExec.WasCalled("git", "worktree", "remove", worktreePath) {
 		t.Error("expected git worktree remove to be called for cleanup")
 	}
+
+	// The new branch goes too, so a retry doesn't find it existing
+	if !mockExec.WasCalled("git", "branch", "-D", pieceName) {
+		t.Error("expected git branch -D to be called for cleanup")
+	}
+
+	// Verify the rollback was reported
+	var report operation.Report
+	for _, msg := range out.Messages {
+		if r, ok := msg.Data.(operation.Report); ok {
+			report = r
+		}
+	}
+	if !report.Restored || len(report.Undone) != 3 {
+		t.Errorf("expected a report of all three steps undone, got %+v", report)
+	}
 }
 
 // ============================================================================
@@ -631,6 +832,8 @@ Content here.
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(repoRoot+"\n"), nil)
 	mockExec.AddResponse("git", []string{"worktree", "add", worktreePath}, nil, nil)
 	mockExec.AddResponse("tmux", []string{"new-session", "-d", "-s", sessionName, "-c", worktreePath}, nil, nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(repoRoot+"/.git/worktrees/"+pieceName+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
 
 	// Execute
 	info, err := handler.CreatePieceFromIssue("/monkeypuzzle", issuePath)
@@ -642,8 +845,8 @@ Content here.
 		t.Errorf("expected piece name %q, got %q", pieceName, info.Name)
 	}
 
-	// Verify marker file was created
-	markerPath := filepath.Join(worktreePath, ".monkeypuzzle/current-issue.json")
+	// Verify marker file was created in the worktree's git dir
+	markerPath := filepath.Join(repoRoot, ".git/worktrees", pieceName, "monkeypuzzle/current-issue.json")
 	markerData, err := fs.ReadFile(markerPath)
 	if err != nil {
 		t.Fatalf("marker file not created: %v", err)
@@ -947,6 +1150,31 @@ func TestHandler_IsBranchMerged_ViaPR(t *testing.T) {
 	}
 }
 
+func TestHandler_IsBranchMerged_ForkQueriesUpstream(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	repoRoot := "/repo"
+	branchName := "feature-branch"
+
+	config := `{"version":"1","git":{"remote":"fork"},"pr":{"provider":"github","config":{"repo":"upstream/project","head_owner":"me"}}}`
+	_ = fs.MkdirAll(filepath.Join(repoRoot, ".monkeypuzzle"), 0755)
+	_ = fs.WriteFile(filepath.Join(repoRoot, ".monkeypuzzle/monkeypuzzle.json"), []byte(config), 0644)
+
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "fork", branchName}, []byte(""), nil)
+	mockExec.AddResponse("gh", []string{"pr", "list", "--head", branchName, "--state", "merged", "--json", "number", "--limit", "1", "--repo", "upstream/project"},
+		[]byte(`[{"number": 7}]`), nil)
+
+	status, err := handler.IsBranchMerged(repoRoot, branchName, "main")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !status.IsMerged || status.PRNumber != 7 {
+		t.Errorf("expected merged via upstream PR #7, got %+v", status)
+	}
+}
+
 func TestHandler_IsBranchMerged_ViaPRBranch(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	out := adapters.NewBufferOutput()
@@ -1018,6 +1246,40 @@ func TestHandler_IsBranchMerged_ViaGit(t *testing.T) {
 	}
 }
 
+func TestHandler_IsBranchMerged_ViaMPMerge(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	worktree := "/pieces/feature-branch"
+	branchName := "feature-branch"
+	if err := piece.OpenMetadataStore(core.Deps{FS: fs}, worktree).WritePieceMetadata(piece.PieceMetadata{MergedCommit: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// No remote, no PR; the squash commit on main is not an ancestor
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", branchName}, []byte(""), nil)
+	mockExec.AddResponse("gh", []string{"pr", "list", "--head", branchName, "--state", "merged", "--json", "number", "--limit", "1"}, []byte(`[]`), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", branchName}, []byte("abc\n"), nil)
+
+	status, err := handler.IsBranchMerged(worktree, branchName, "main")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !status.IsMerged || status.Method != "mp-merge" {
+		t.Errorf("expected merged by mp-merge, got %+v", status)
+	}
+
+	// Commits after the merge make the piece unmerged again
+	mockExec.AddResponse("git", []string{"rev-parse", branchName}, []byte("def\n"), nil)
+	mockExec.AddResponse("git", []string{"branch", "--merged", "main"}, []byte("  main\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "main"}, []byte("fff\n"), nil)
+	mockExec.AddResponse("git", []string{"merge-base", "--is-ancestor", "def", "main"}, nil, adapters.MockError("exit status 1"))
+	if status, err := handler.IsBranchMerged(worktree, branchName, "main"); err != nil || status.IsMerged {
+		t.Errorf("expected not merged after new commits, got %+v (%v)", status, err)
+	}
+}
+
 func TestHandler_IsBranchMerged_ViaCommit(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	out := adapters.NewBufferOutput()
@@ -1185,6 +1447,56 @@ func TestHandler_IsBranchMerged_GHError_FallsBackToGit(t *testing.T) {
 // CleanupMergedPieces Tests
 // ============================================================================
 
+// mockWorktreeList mocks git worktree list with /repo and the named pieces in
+// the test pieces directory
+func mockWorktreeList(m *adapters.MockExec, pieces ...string) {
+	output := "worktree /repo\nHEAD aaa111\nbranch refs/heads/main\n\n"
+	for _, name := range pieces {
+		output += fmt.Sprintf("worktree /test-data/monkeypuzzle/pieces/%s\nHEAD bbb222\nbranch refs/heads/%s\n\n", name, name)
+	}
+	m.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte(output), nil)
+}
+
+// mockMainWorktree mocks the git commands GetMainRepoRoot runs in a linked
+// worktree of the repository at repoRoot
+func mockMainWorktree(m *adapters.MockExec, repoRoot string) {
+	m.AddResponse("git", []string{"rev-parse", "--git-common-dir"}, []byte(repoRoot+"/.git\n"), nil)
+	m.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree "+repoRoot+"\nHEAD aaa111\nbranch refs/heads/main\n\n"), nil)
+}
+
+// mockCommitLog mocks git log of revRange (e.g. "main..piece-1") for CommitLog,
+// formatting commits the way git does with adapters.CommitLogFormat
+func mockCommitLog(m *adapters.MockExec, revRange string, commits ...adapters.Commit) {
+	var output strings.Builder
+	for i, c := range commits {
+		if c.Hash == "" {
+			c.Hash = fmt.Sprintf("%040d", i+1)
+		}
+		var trailers []string
+		for _, t := range c.Trailers {
+			trailers = append(trailers, t.String())
+		}
+		body := c.Body
+		if len(trailers) > 0 {
+			body = strings.TrimSpace(body + "\n\n" + strings.Join(trailers, "\n"))
+		}
+		date := ""
+		if !c.Time.IsZero() {
+			date = c.Time.Format(time.RFC3339)
+		}
+		fmt.Fprintf(&output, "\x1e%s\x1f%s\x1f%s\x1f%s\x1f%s\x1f%s\n\x1f%s\n\n", c.Hash, c.Author, c.AuthorEmail, date, c.Subject, body, strings.Join(trailers, "\n"))
+	}
+	m.AddResponse("git", []string{"log", "--format=" + adapters.CommitLogFormat, revRange}, []byte(output.String()), nil)
+}
+
+// mockMainCheckout mocks a clean main repository with HEAD at head0000 and
+// target at target00, as checked before a merge into target
+func mockMainCheckout(m *adapters.MockExec, target string) {
+	m.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	m.AddResponse("git", []string{"rev-parse", "HEAD"}, []byte("head0000\n"), nil)
+	m.AddResponse("git", []string{"rev-parse", target}, []byte("target00\n"), nil)
+}
+
 func TestHandler_CleanupMergedPieces_NoPieces(t *testing.T) {
 	t.Setenv("XDG_DATA_HOME", "/test-data")
 
@@ -1195,6 +1507,7 @@ func TestHandler_CleanupMergedPieces_NoPieces(t *testing.T) {
 	handler := piece.NewHandler(deps)
 
 	// Pieces directory doesn't exist
+	mockWorktreeList(mockExec)
 	opts := piece.CleanupOptions{MainBranch: "main"}
 	results, err := handler.CleanupMergedPieces("/repo", opts)
 	if err != nil {
@@ -1224,6 +1537,7 @@ func TestHandler_CleanupMergedPieces_DryRun(t *testing.T) {
 
 	// Mock git commands for the piece
 	fullWorktreePath := "/" + worktreePath
+	mockWorktreeList(mockExec, pieceName)
 	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte(pieceName+"\n"), nil)
 
 	// Mock branch check - no PR metadata, use git method
@@ -1292,6 +1606,7 @@ status: in-progress
 	_ = fs.WriteFile(issuePath, []byte(issueContent), 0644)
 
 	// Mock git commands for the piece
+	mockWorktreeList(mockExec, pieceName)
 	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte(pieceName+"\n"), nil)
 	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", pieceName}, []byte(""), nil)
 	mockExec.AddResponse("git", []string{"branch", "--merged", "main"}, []byte("  main\n  "+pieceName+"\n"), nil)
@@ -1347,6 +1662,7 @@ func TestHandler_CleanupMergedPieces_SkipsUnmerged(t *testing.T) {
 	_ = fs.MkdirAll(worktreePath, 0755)
 
 	// Mock git commands for the piece
+	mockWorktreeList(mockExec, pieceName)
 	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte(pieceName+"\n"), nil)
 
 	// Mock branch check - not merged
@@ -1384,6 +1700,7 @@ func TestHandler_CleanupMergedPieces_NoIssueMarker(t *testing.T) {
 	_ = fs.MkdirAll(worktreePath, 0755)
 
 	// Mock git commands for the piece
+	mockWorktreeList(mockExec, pieceName)
 	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte(pieceName+"\n"), nil)
 	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", pieceName}, []byte(""), nil)
 	mockExec.AddResponse("git", []string{"branch", "--merged", "main"}, []byte("  main\n  "+pieceName+"\n"), nil)
@@ -1410,3 +1727,91 @@ func TestHandler_CleanupMergedPieces_NoIssueMarker(t *testing.T) {
 		t.Error("expected IssueUpdated to be false when no issue marker")
 	}
 }
+
+func TestHandler_CleanupMergedPieces_ReportsWorktreeDiscrepancies(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	// "moved" has a directory but no worktree; "gone" has a worktree but no directory
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/moved", 0755)
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/kept", 0755)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte(`worktree /repo
+branch refs/heads/main
+
+worktree /test-data/monkeypuzzle/pieces/gone
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+
+worktree /test-data/monkeypuzzle/pieces/kept
+branch refs/heads/kept
+locked on a USB drive
+`), nil)
+	mockExec.AddResponse("git", []string{"worktree", "prune", "--verbose"}, []byte("Removing worktrees/gone\n"), nil)
+	mockExec.AddResponse("tmux", []string{"kill-session", "-t", "mp-piece-gone"}, nil, nil)
+
+	results, err := handler.CleanupMergedPieces("/repo", piece.CleanupOptions{MainBranch: "main"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	want := map[string]string{
+		"gone":  piece.DiscrepancyMissing,
+		"kept":  piece.DiscrepancyLocked,
+		"moved": piece.DiscrepancyNotWorktree,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %+v", len(want), results)
+	}
+	for _, r := range results {
+		if r.Discrepancy != want[r.PieceName] {
+			t.Errorf("expected %s to be reported as %s, got %q", r.PieceName, want[r.PieceName], r.Discrepancy)
+		}
+		if r.Cleaned() != (r.PieceName == "gone") {
+			t.Errorf("expected only the missing worktree to be cleaned, got %+v", r)
+		}
+	}
+	if !mockExec.WasCalled("git", "worktree", "prune", "--verbose") {
+		t.Error("expected the missing worktree to be pruned")
+	}
+	if mockExec.WasCalled("git", "rev-parse", "--abbrev-ref", "HEAD") {
+		t.Error("expected no merge checks for pieces with discrepancies")
+	}
+	if !out.HasWarning() {
+		t.Error("expected warnings for the locked and unregistered pieces")
+	}
+}
+
+func TestHandler_CleanupMergedPieces_IgnoresOtherRepositories(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	// "other" is a piece of another repository sharing the pieces directory;
+	// "moved" was a piece of this one
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/other", 0755)
+	_ = fs.WriteFile("test-data/monkeypuzzle/pieces/other/.git", []byte("gitdir: /other-repo/.git/worktrees/other\n"), 0644)
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/moved", 0755)
+	_ = fs.WriteFile("test-data/monkeypuzzle/pieces/moved/.git", []byte("gitdir: /repo/.git/worktrees/moved\n"), 0644)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nbranch refs/heads/main\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-common-dir"}, []byte("/repo/.git\n"), nil)
+
+	results, err := handler.CleanupMergedPieces("/repo", piece.CleanupOptions{MainBranch: "main"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(results) != 1 || results[0].PieceName != "moved" || results[0].Discrepancy != piece.DiscrepancyNotWorktree {
+		t.Fatalf("expected only moved to be reported, got %+v", results)
+	}
+	for _, msg := range out.Messages {
+		if strings.Contains(msg.Content, "other") {
+			t.Errorf("expected no message about the other repository's piece, got %q", msg.Content)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+)
+
+// Events logged over a piece's life besides creation and removal, read back by
+// History. PR events are logged by the pr package.
+const (
+	EventPieceUpdate = "piece.update"
+	EventPieceMerge  = "piece.merge"
+	EventPRCreate    = "pr.create"
+	EventPRUpdate    = "pr.update"
+	EventHookRun     = "hook.run"
+)
+
+// HistoryCommit is the type of history entries for the piece's commits
+const HistoryCommit = "commit"
+
+// HistoryEntry is one point in a piece's timeline
+type HistoryEntry struct {
+	Time time.Time `json:"time"`
+	// Type is an events log type, or HistoryCommit
+	Type    string         `json:"type"`
+	Summary string         `json:"summary"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// PieceHistory is the timeline of a piece, oldest first
+type PieceHistory struct {
+	PieceName string `json:"piece_name"`
+	// Active is true while the piece's worktree exists
+	Active  bool           `json:"active"`
+	Entries []HistoryEntry `json:"entries"`
+}
+
+// History reconstructs the timeline of the named piece from the events log
+// and, while its worktree exists, its commits and PR metadata. Commits are
+// listed relative to the piece's base branch (see BaseBranch; mainBranch is
+// the fallback), so commits already merged into it are not shown.
+func (h *Handler) History(repoRoot, pieceName, mainBranch string) (PieceHistory, error) {
+	history := PieceHistory{PieceName: pieceName, Entries: []HistoryEntry{}}
+
+	logged, err := events.Read(h.deps.FS, repoRoot)
+	if err != nil {
+		return PieceHistory{}, err
+	}
+	seen := map[string]bool{}
+	for _, e := range logged {
+		if e.Piece != pieceName {
+			continue
+		}
+		seen[e.Type] = true
+		history.Entries = append(history.Entries, HistoryEntry{Time: e.Time, Type: e.Type, Summary: describeEvent(e), Data: e.Data})
+	}
+
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return PieceHistory{}, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	worktreePath := filepath.Join(piecesDir, pieceName)
+	if _, err := h.deps.FS.Stat(worktreePath); err == nil {
+		history.Active = true
+		history.Entries = append(history.Entries, h.commitHistory(worktreePath, mainBranch)...)
+		history.Entries = append(history.Entries, h.prHistory(worktreePath, seen)...)
+	}
+
+	if !history.Active && len(history.Entries) == 0 {
+		return PieceHistory{}, fmt.Errorf("no history for piece %s: it does not exist and the events log does not mention it", pieceName)
+	}
+
+	sort.SliceStable(history.Entries, func(i, j int) bool {
+		return history.Entries[i].Time.Before(history.Entries[j].Time)
+	})
+	return history, nil
+}
+
+// commitHistory returns an entry per commit of the piece not on its base branch
+func (h *Handler) commitHistory(worktreePath, mainBranch string) []HistoryEntry {
+	branch, err := h.git.CurrentBranch(worktreePath)
+	if err != nil {
+		return nil
+	}
+	commits, err := h.git.CommitLog(worktreePath, h.BaseBranch(worktreePath, mainBranch), branch)
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to read commits: %v", err),
+		})
+		return nil
+	}
+
+	// Oldest first, so commits made within the same second keep their order
+	entries := make([]HistoryEntry, 0, len(commits))
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		hash := c.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		entries = append(entries, HistoryEntry{
+			Time:    c.Time,
+			Type:    HistoryCommit,
+			Summary: fmt.Sprintf("%s %s (%s)", hash, c.Subject, c.Author),
+			Data:    map[string]any{"hash": c.Hash, "author": c.Author},
+		})
+	}
+	return entries
+}
+
+// prHistory returns entries for a PR created or updated before PR events were
+// logged, from the piece's PR metadata
+func (h *Handler) prHistory(worktreePath string, seen map[string]bool) []HistoryEntry {
+	metadata, err := OpenMetadataStore(h.deps, worktreePath).ReadPRMetadata()
+	if err != nil {
+		return nil
+	}
+
+	var entries []HistoryEntry
+	data := map[string]any{"pr_number": metadata.PRNumber, "pr_url": metadata.PRURL}
+	if !seen[EventPRCreate] && !metadata.CreatedAt.IsZero() {
+		entries = append(entries, HistoryEntry{Time: metadata.CreatedAt, Type: EventPRCreate,
+			Summary: fmt.Sprintf("Opened PR #%d", metadata.PRNumber), Data: data})
+	}
+	if !seen[EventPRUpdate] && !metadata.UpdatedAt.IsZero() {
+		entries = append(entries, HistoryEntry{Time: metadata.UpdatedAt, Type: EventPRUpdate,
+			Summary: fmt.Sprintf("Updated PR #%d", metadata.PRNumber), Data: data})
+	}
+	return entries
+}
+
+// describeEvent returns a one-line description of an events log entry
+func describeEvent(e events.Event) string {
+	str := func(key string) string {
+		value, _ := e.Data[key].(string)
+		return value
+	}
+	number := func(key string) string {
+		if value, ok := e.Data[key].(float64); ok {
+			return fmt.Sprintf("%d", int(value))
+		}
+		return "?"
+	}
+
+	switch e.Type {
+	case EventPieceCreate:
+		return "Created piece"
+	case EventPieceUpdate:
+		return "Updated from " + str("from")
+	case EventPieceMerge:
+		return "Merged into " + str("into")
+	case EventPieceRemove:
+		switch str("reason") {
+		case RemoveReasonCleanup:
+			return "Cleaned up after merge"
+		case RemoveReasonDelete:
+			return "Deleted"
+		case RemoveReasonMissing:
+			return "Removed missing worktree"
+		}
+		return "Removed"
+	case EventPRCreate:
+		return "Opened PR #" + number("pr_number")
+	case EventPRUpdate:
+		return "Updated PR #" + number("pr_number")
+	case EventHookRun:
+		if ok, _ := e.Data["ok"].(bool); !ok {
+			return "Hook " + str("hook") + " failed"
+		}
+		return "Ran hook " + str("hook")
+	case EventIssueRollback:
+		return fmt.Sprintf("Reverted %s to %s", str("issue"), str("to"))
+	}
+
+	// Unknown types list their data
+	keys := make([]string, 0, len(e.Data))
+	for key := range e.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := []string{e.Type}
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, e.Data[key]))
+	}
+	return strings.Join(parts, " ")
+}
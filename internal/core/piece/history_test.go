@@ -0,0 +1,76 @@
+package piece_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_History(t *testing.T) {
+	fs, mockExec, handler := setupDeletePiece(t)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("p1\n"), nil)
+
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	for _, e := range []events.Event{
+		{Time: at(0), Type: piece.EventPieceCreate, Piece: "p1"},
+		{Time: at(1), Type: piece.EventHookRun, Piece: "p1", Data: map[string]any{"hook": piece.HookOnPieceCreate, "ok": true}},
+		{Time: at(2), Type: piece.EventPieceCreate, Piece: "other"},
+		{Time: at(30), Type: piece.EventPieceUpdate, Piece: "p1", Data: map[string]any{"from": "main"}},
+		{Time: at(40), Type: piece.EventPRCreate, Piece: "p1", Data: map[string]any{"pr_number": 7}},
+	} {
+		if err := events.Append(fs, "/repo", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mockCommitLog(mockExec, "main..p1",
+		adapters.Commit{Hash: "bbbbbbbbbb", Author: "Agent", Subject: "add tests", Time: at(35)},
+		adapters.Commit{Hash: "aaaaaaaaaa", Author: "Agent", Subject: "add feature", Time: at(10)},
+	)
+
+	history, err := handler.History("/repo", "p1", "main")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !history.Active {
+		t.Error("expected piece with a worktree to be active")
+	}
+
+	want := []string{
+		"Created piece",
+		"Ran hook on-piece-create.sh",
+		"aaaaaaa add feature (Agent)",
+		"Updated from main",
+		"bbbbbbb add tests (Agent)",
+		"Opened PR #7",
+	}
+	if len(history.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), history.Entries)
+	}
+	for i, w := range want {
+		if history.Entries[i].Summary != w {
+			t.Errorf("entry %d: expected %q, got %q", i, w, history.Entries[i].Summary)
+		}
+	}
+}
+
+func TestHandler_History_RemovedPiece(t *testing.T) {
+	fs, _, handler := setupDeletePiece(t)
+	_ = events.Append(fs, "/repo", events.Event{Type: piece.EventPieceMerge, Piece: "gone", Data: map[string]any{"into": "main"}})
+	_ = events.Append(fs, "/repo", events.Event{Type: piece.EventPieceRemove, Piece: "gone", Data: map[string]any{"reason": piece.RemoveReasonCleanup}})
+
+	history, err := handler.History("/repo", "gone", "main")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if history.Active || len(history.Entries) != 2 || history.Entries[1].Summary != "Cleaned up after merge" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+
+	if _, err := handler.History("/repo", "unknown", "main"); err == nil {
+		t.Error("expected error for a piece with no history")
+	}
+}
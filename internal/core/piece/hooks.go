@@ -1,11 +1,14 @@
 package piece
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
 )
 
 // Hook types for piece operations
@@ -17,6 +20,9 @@ const (
 	HookAfterPieceUpdate  = "after-piece-update.sh"
 )
 
+// Hooks lists the hook scripts monkeypuzzle runs
+var Hooks = []string{HookOnPieceCreate, HookBeforePieceMerge, HookAfterPieceMerge, HookBeforePieceUpdate, HookAfterPieceUpdate}
+
 // HooksDir is the directory name for hooks within the project
 const HooksDir = ".monkeypuzzle/hooks"
 
@@ -27,6 +33,7 @@ type HookContext struct {
 	RepoRoot     string // MP_REPO_ROOT
 	MainBranch   string // MP_MAIN_BRANCH (for merge/update hooks)
 	SessionName  string // MP_SESSION_NAME (for create hooks)
+	SourceDir    string // MP_SOURCE_DIR (if the piece recorded one)
 }
 
 // HookRunner executes hook scripts from the .monkeypuzzle/hooks directory
@@ -81,27 +88,46 @@ func (h *HookRunner) RunHook(repoRoot, hookName string, ctx HookContext) error {
 		Content: fmt.Sprintf("Running hook: %s", hookName),
 	})
 
-	output, err := h.execWithEnv(repoRoot, hookPath, env)
-	if err != nil {
-		// Output hook's stderr/stdout
-		if len(output) > 0 {
-			h.output.Write(core.Message{
-				Type:    core.MsgError,
-				Content: string(output),
-			})
-		}
-		return fmt.Errorf("hook %s failed: %w", hookName, err)
+	// Stream the hook's output as it runs, so long hooks show progress
+	runErr := h.execWithEnv(repoRoot, hookPath, env, ctx)
+	h.logRun(repoRoot, hookName, ctx.PieceName, runErr)
+	if runErr != nil {
+		return fmt.Errorf("hook %s failed: %w", hookName, runErr)
 	}
 
-	// Output hook's stdout if any
-	if len(output) > 0 {
+	return nil
+}
+
+// logRun records a hook run and whether it succeeded in the events log
+func (h *HookRunner) logRun(repoRoot, hookName, pieceName string, runErr error) {
+	err := events.Append(h.fs, repoRoot, events.Event{
+		Type:  EventHookRun,
+		Piece: pieceName,
+		Data:  map[string]any{"hook": hookName, "ok": runErr == nil},
+	})
+	if err != nil {
 		h.output.Write(core.Message{
-			Type:    core.MsgInfo,
-			Content: string(output),
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to log %s event: %v", EventHookRun, err),
 		})
 	}
+}
 
-	return nil
+// writeLine writes a line of hook output
+func (h *HookRunner) writeLine(line string) {
+	h.output.Write(core.Message{
+		Type:    core.MsgInfo,
+		Content: line,
+	})
+}
+
+// writeErrLine writes a line a hook wrote to stderr, as a warning so it
+// stays out of stdout
+func (h *HookRunner) writeErrLine(line string) {
+	h.output.Write(core.Message{
+		Type:    core.MsgWarning,
+		Content: line,
+	})
 }
 
 // buildEnv creates environment variable strings for the hook.
@@ -125,6 +151,9 @@ func (h *HookRunner) buildEnv(ctx HookContext) []string {
 	if ctx.SessionName != "" {
 		env = append(env, fmt.Sprintf("MP_SESSION_NAME=%s", ctx.SessionName))
 	}
+	if ctx.SourceDir != "" {
+		env = append(env, fmt.Sprintf("MP_SOURCE_DIR=%s", ctx.SourceDir))
+	}
 
 	return env
 }
@@ -152,9 +181,98 @@ func hasEnvPrefix(envVar, prefix string) bool {
 	return false
 }
 
-// execWithEnv executes a script with the given environment variables
-func (h *HookRunner) execWithEnv(dir, script string, env []string) ([]byte, error) {
+// execWithEnv executes a script with the given environment variables,
+// wrapping it in the project's sandbox runner when one is configured.
+// The script's stdout is written to the output line by line, and its stderr
+// as warnings.
+func (h *HookRunner) execWithEnv(dir, script string, env []string, ctx HookContext) error {
+	sandbox, err := h.sandboxFor(dir, ctx)
+	if err != nil {
+		return err
+	}
+
 	// Use bash to execute the script
-	return h.exec.RunWithEnv(dir, env, "bash", script)
+	name, args := "bash", []string{script}
+	if sandbox != nil {
+		if name, args, err = sandbox.Wrap("bash", script); err != nil {
+			return err
+		}
+	}
+	return h.exec.RunStream(core.StreamOptions{
+		Dir:    dir,
+		Env:    env,
+		Stdout: h.writeLine,
+		Stderr: h.writeErrLine,
+	}, name, args...)
+}
+
+// RunCommand runs cmd with sh in dir as hooks run: with their MP_* environment,
+// inside the sandbox configured for the repository at ctx.RepoRoot, and with
+// its output written line by line, stderr as warnings. Returns the combined
+// output.
+func (h *HookRunner) RunCommand(dir, cmd string, ctx HookContext) (string, error) {
+	sandbox, err := h.sandboxFor(ctx.RepoRoot, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name, args := "sh", []string{"-c", cmd}
+	if sandbox != nil {
+		if name, args, err = sandbox.Wrap(name, args...); err != nil {
+			return "", err
+		}
+	}
+	var output []string
+	collect := func(write func(string)) func(string) {
+		return func(line string) {
+			output = append(output, line)
+			write(line)
+		}
+	}
+	err = h.exec.RunStream(core.StreamOptions{
+		Dir:    dir,
+		Env:    h.buildEnv(ctx),
+		Stdout: collect(h.writeLine),
+		Stderr: collect(h.writeErrLine),
+	}, name, args...)
+	return strings.TrimSpace(strings.Join(output, "\n")), err
 }
 
+// sandboxFor returns the hook sandbox configured in the project config.
+// Returns nil if no config exists or no sandbox is configured. A config that
+// can't be read fails, rather than running hooks unsandboxed.
+func (h *HookRunner) sandboxFor(repoRoot string, ctx HookContext) (*Sandbox, error) {
+	cfg, err := ReadConfig(repoRoot, h.fs)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot tell whether hooks must be sandboxed: %w", err)
+	}
+	if cfg.Hooks.Sandbox == "" {
+		return nil, nil
+	}
+	if err := ValidateSandbox(cfg.Hooks.Sandbox); err != nil {
+		return nil, err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory for sandbox: %w", err)
+	}
+
+	// The worktree and the shared git dir must stay writable so hooks can commit
+	writable := []string{ctx.WorktreePath, gitCommonDir(h.exec, repoRoot)}
+	for _, p := range cfg.Hooks.Writable {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(repoRoot, p)
+		}
+		writable = append(writable, p)
+	}
+
+	return &Sandbox{
+		Runner:   cfg.Hooks.Sandbox,
+		HomeDir:  home,
+		Writable: writable,
+	}, nil
+}
@@ -146,6 +146,41 @@ func TestHookRunner_RunHook_Success(t *testing.T) {
 	}
 }
 
+func TestHookRunner_RunHook_StreamsOutputLines(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	runner := piece.NewHookRunner(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	hooksDir := ".monkeypuzzle/hooks"
+	_ = fs.MkdirAll(hooksDir, 0755)
+	_ = fs.WriteFile(filepath.Join(hooksDir, piece.HookOnPieceCreate), []byte("#!/bin/bash\nnpm install"), 0755)
+
+	fullHookPath := filepath.Join("/", hooksDir, piece.HookOnPieceCreate)
+	mockExec.AddSplitResponse("bash", []string{fullHookPath},
+		[]byte("installing\ndone\n"), []byte("npm warn deprecated\n"), errors.New("exit status 1"))
+
+	err := runner.RunHook("/", piece.HookOnPieceCreate, piece.HookContext{PieceName: "test-piece"})
+	if err == nil {
+		t.Fatal("expected error when hook fails")
+	}
+
+	var lines []string
+	for _, msg := range out.Messages {
+		lines = append(lines, msg.Content)
+	}
+	want := []string{"Running hook: " + piece.HookOnPieceCreate, "installing", "done", "npm warn deprecated"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected output lines %q, got %q", want, lines)
+	}
+	// stderr is kept out of stdout
+	for _, msg := range out.Messages {
+		if isStderr := msg.Content == "npm warn deprecated"; isStderr != (msg.Type == core.MsgWarning) {
+			t.Errorf("expected only the stderr line as a warning, got %+v", msg)
+		}
+	}
+}
+
 func TestHookRunner_RunHook_PassesEnvironmentVariables(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	out := adapters.NewBufferOutput()
@@ -170,6 +205,7 @@ func TestHookRunner_RunHook_PassesEnvironmentVariables(t *testing.T) {
 		RepoRoot:     "/repo",
 		MainBranch:   "main",
 		SessionName:  "mp-piece-my-piece",
+		SourceDir:    "/src/monkeypuzzle",
 	}
 
 	err := runner.RunHook("/repo", piece.HookBeforePieceMerge, ctx)
@@ -212,6 +248,9 @@ func TestHookRunner_RunHook_PassesEnvironmentVariables(t *testing.T) {
 	if envMap["MP_SESSION_NAME"] != "mp-piece-my-piece" {
 		t.Errorf("expected MP_SESSION_NAME=mp-piece-my-piece, got: %s", envMap["MP_SESSION_NAME"])
 	}
+	if envMap["MP_SOURCE_DIR"] != "/src/monkeypuzzle" {
+		t.Errorf("expected MP_SOURCE_DIR=/src/monkeypuzzle, got: %s", envMap["MP_SOURCE_DIR"])
+	}
 }
 
 func TestHookRunner_AllHookTypes(t *testing.T) {
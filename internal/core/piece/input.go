@@ -9,6 +9,11 @@ type PieceInfo struct {
 	WorktreePath string `json:"worktree_path"`
 	// SessionName is the name of the tmux session created for this piece
 	SessionName string `json:"session_name"`
+	// IssueID and IssuePath identify the issue the piece was created from, if any
+	IssueID   string `json:"issue_id,omitempty"`
+	IssuePath string `json:"issue_path,omitempty"`
+	// Template is the piece template the piece was created from, if any
+	Template string `json:"template,omitempty"`
 }
 
 // PieceStatus contains information about the current piece status.
@@ -22,5 +27,30 @@ type PieceStatus struct {
 	WorktreePath string `json:"worktree_path,omitempty"`
 	// RepoRoot is the path to the main repository root
 	RepoRoot string `json:"repo_root,omitempty"`
+
+	// The fields below are only filled in by Details, for a piece worktree
+
+	// Branch is the piece's current branch and BaseBranch the branch it merges into
+	Branch     string `json:"branch,omitempty"`
+	BaseBranch string `json:"base_branch,omitempty"`
+	// Ahead and Behind count the commits the branch has that BaseBranch lacks, and vice versa
+	Ahead  int `json:"ahead,omitempty"`
+	Behind int `json:"behind,omitempty"`
+	// Dirty is true when the worktree has uncommitted changes
+	Dirty bool `json:"dirty,omitempty"`
+	// IssueID and IssuePath identify the linked issue, if any
+	IssueID   string `json:"issue_id,omitempty"`
+	IssuePath string `json:"issue_path,omitempty"`
+	// Template is the piece template the piece was created from, if any
+	Template string `json:"template,omitempty"`
+	// SourceDir is the monkeypuzzle source directory recorded for the piece, if any
+	SourceDir string `json:"source_dir,omitempty"`
+	// PRNumber and PRURL identify the PR opened for the piece, if any
+	PRNumber int    `json:"pr_number,omitempty"`
+	PRURL    string `json:"pr_url,omitempty"`
+	// Remote is the branch's state on the remote (see RemoteState), empty when skipped
+	Remote string `json:"remote,omitempty"`
+	// DiskBytes is the disk space the worktree uses, only set by List with DiskUsage
+	DiskBytes int64 `json:"disk_bytes,omitempty"`
 }
 
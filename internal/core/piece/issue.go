@@ -3,8 +3,11 @@ package piece
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -23,6 +26,17 @@ const (
 
 var validStatuses = []string{StatusTodo, StatusInProgress, StatusDone}
 
+// Frontmatter fields linking an issue to the PR implementing it, written by
+// `mp piece pr create` and removed when the piece is deleted unmerged
+const (
+	IssueFieldPRNumber = "pr_number"
+	IssueFieldPRURL    = "pr_url"
+)
+
+// IssueFieldCreatedAt records when an issue was created, overriding the time
+// it was first committed; `mp issue dupes --merge` keeps the earlier one
+const IssueFieldCreatedAt = "created_at"
+
 var (
 	// titleRegex matches "title: value" in YAML frontmatter (case-insensitive)
 	titleRegex = regexp.MustCompile(`(?i)^title:\s*(.+)$`)
@@ -135,8 +149,21 @@ func extractFromFilename(issuePath string) string {
 
 // SanitizePieceName sanitizes an issue name for use as a piece name.
 // Converts to lowercase, replaces spaces and special chars with hyphens,
-// and removes invalid filesystem characters.
+// removes invalid filesystem characters, and truncates to
+// DefaultMaxNameLength bytes.
 func SanitizePieceName(name string) string {
+	return SanitizePieceNameMax(name, DefaultMaxNameLength)
+}
+
+// SanitizePieceNameMax is SanitizePieceName with a length cap of maxLen
+// bytes (see TruncatePieceName)
+func SanitizePieceNameMax(name string, maxLen int) string {
+	return TruncatePieceName(SanitizeName(name), maxLen)
+}
+
+// SanitizeName is SanitizePieceName without the length cap, for names that
+// aren't piece names, such as issue filenames
+func SanitizeName(name string) string {
 	// Characters that are invalid in filenames on most filesystems
 	invalidChars := []rune{'/', '\\', ':', '*', '?', '"', '<', '>', '|', '\x00'}
 
@@ -218,6 +245,60 @@ func ReadConfig(repoRoot string, fs core.FS) (*initcmd.Config, error) {
 	return &cfg, nil
 }
 
+// IssueID returns the short ID of an issue: its file name without the .md
+// extension, e.g. "add-login" for issues/add-login.md
+func IssueID(issuePath string) string {
+	return strings.TrimSuffix(filepath.Base(issuePath), ".md")
+}
+
+// IssueIndexFilename is the issue index in .monkeypuzzle, caching parsed issues
+const IssueIndexFilename = "issues.index.json"
+
+// IssueAliasesFilename is the file in .monkeypuzzle mapping the old paths of
+// renamed and merged issues to their new paths. Unlike the index it is
+// tracked, so old IDs resolve in every clone.
+const IssueAliasesFilename = "issue-aliases.json"
+
+// ReadIssueAliases reads the issue aliases of the repository at repoRoot,
+// keyed by old path. A missing file has none.
+func ReadIssueAliases(repoRoot string, fs core.FS) (map[string]string, error) {
+	path := filepath.Join(repoRoot, initcmd.DirName, IssueAliasesFilename)
+	data, err := fs.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	aliases := map[string]string{}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return aliases, nil
+}
+
+// ResolveIssueAlias returns the path, relative to repoRoot, that an issue
+// renamed with `mp issue rename` has now, given its old short ID or path
+func ResolveIssueAlias(repoRoot, id string, fs core.FS) (string, bool) {
+	aliases, err := ReadIssueAliases(repoRoot, fs)
+	if err != nil {
+		return "", false
+	}
+
+	id = filepath.Clean(id)
+	oldPaths := make([]string, 0, len(aliases))
+	for oldPath := range aliases {
+		oldPaths = append(oldPaths, oldPath)
+	}
+	sort.Strings(oldPaths)
+	for _, oldPath := range oldPaths {
+		if oldPath == id || oldPath == id+".md" || IssueID(oldPath) == id {
+			return aliases[oldPath], true
+		}
+	}
+	return "", false
+}
+
 // ResolveIssuePath resolves an issue path (absolute or relative) to an absolute path.
 // If relative, resolves from repoRoot. Uses fs to verify the file exists.
 func ResolveIssuePath(repoRoot, issuePath string, fs core.FS) (string, error) {
@@ -315,6 +396,174 @@ func extractStatusFromFrontmatter(text string) string {
 	return ""
 }
 
+// ExtractLabels returns the labels in an issue's YAML frontmatter, written
+// inline (labels: [a, b] or labels: a, b) or as a block list of "- a" lines
+func ExtractLabels(text string) []string {
+	return ExtractList(text, "labels")
+}
+
+// ExtractList returns the values of the list field in an issue's YAML
+// frontmatter, written like labels (see ExtractLabels)
+func ExtractList(text, field string) []string {
+	frontmatter, _ := splitFrontmatter(text)
+	if frontmatter == "" {
+		return nil
+	}
+
+	fieldRegex := fieldLineRegex(field)
+	var values []string
+	lines := strings.Split(frontmatter, "\n")
+	for i, line := range lines {
+		matches := fieldRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+
+		inline := strings.Trim(strings.TrimSpace(matches[1]), "[]")
+		for _, value := range strings.Split(inline, ",") {
+			if value = strings.Trim(strings.TrimSpace(value), `"'`); value != "" {
+				values = append(values, value)
+			}
+		}
+		for _, item := range lines[i+1:] {
+			item = strings.TrimSpace(item)
+			if !strings.HasPrefix(item, "- ") {
+				break
+			}
+			if value := strings.Trim(strings.TrimSpace(item[2:]), `"'`); value != "" {
+				values = append(values, value)
+			}
+		}
+		break
+	}
+	return values
+}
+
+// ExtractField returns the unquoted value of a scalar field in an issue's YAML
+// frontmatter, or "" if it is missing
+func ExtractField(text, field string) string {
+	frontmatter, _ := splitFrontmatter(text)
+	if frontmatter == "" {
+		return ""
+	}
+
+	re := fieldLineRegex(field)
+	for _, line := range strings.Split(frontmatter, "\n") {
+		if matches := re.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			return strings.Trim(strings.TrimSpace(matches[1]), `"'`)
+		}
+	}
+	return ""
+}
+
+// ExtractFields returns the top-level fields of an issue's YAML frontmatter:
+// scalars unquoted, and lists (see ExtractList) joined with ", "
+func ExtractFields(text string) map[string]string {
+	frontmatter, _ := splitFrontmatter(text)
+	fields := map[string]string{}
+	for _, line := range strings.Split(frontmatter, "\n") {
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || strings.HasPrefix(line, "- ") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if _, seen := fields[key]; seen {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" || strings.HasPrefix(value, "[") {
+			fields[key] = strings.Join(ExtractList(text, key), ", ")
+			continue
+		}
+		fields[key] = strings.Trim(value, `"'`)
+	}
+	return fields
+}
+
+// SetPRLink records the number and URL of the PR implementing an issue in the
+// issue file's frontmatter, replacing an earlier link
+func SetPRLink(issuePath string, prNumber int, prURL string, fs core.FS) error {
+	return rewriteFrontmatter(issuePath, fs, func(lines []string) []string {
+		lines = setFrontmatterLine(lines, IssueFieldPRNumber, strconv.Itoa(prNumber))
+		return setFrontmatterLine(lines, IssueFieldPRURL, prURL)
+	})
+}
+
+// SetIssueField sets a frontmatter field of an issue file, replacing an
+// earlier value
+func SetIssueField(issuePath, field, value string, fs core.FS) error {
+	return rewriteFrontmatter(issuePath, fs, func(lines []string) []string {
+		return setFrontmatterLine(lines, field, value)
+	})
+}
+
+// ClearPRLink removes the PR number and URL from an issue file's frontmatter.
+// Issues without a PR link are left untouched.
+func ClearPRLink(issuePath string, fs core.FS) error {
+	content, err := fs.ReadFile(issuePath)
+	if err != nil {
+		return fmt.Errorf("failed to read issue file: %w", err)
+	}
+	if ExtractField(string(content), IssueFieldPRNumber) == "" && ExtractField(string(content), IssueFieldPRURL) == "" {
+		return nil
+	}
+
+	return rewriteFrontmatter(issuePath, fs, func(lines []string) []string {
+		kept := lines[:0]
+		for _, line := range lines {
+			if !fieldLineRegex(IssueFieldPRNumber).MatchString(strings.TrimSpace(line)) &&
+				!fieldLineRegex(IssueFieldPRURL).MatchString(strings.TrimSpace(line)) {
+				kept = append(kept, line)
+			}
+		}
+		return kept
+	})
+}
+
+// rewriteFrontmatter replaces the frontmatter lines of an issue file with
+// edit's result, adding frontmatter to a file without it
+func rewriteFrontmatter(issuePath string, fs core.FS, edit func(lines []string) []string) error {
+	content, err := fs.ReadFile(issuePath)
+	if err != nil {
+		return fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	text := string(content)
+	frontmatter, rest := splitFrontmatter(text)
+	var lines []string
+	if frontmatter == "" {
+		rest = "\n" + text
+	} else {
+		lines = strings.Split(frontmatter, "\n")
+	}
+
+	updated := "---\n" + strings.Join(edit(lines), "\n") + "\n---" + rest
+	if err := fs.WriteFile(issuePath, []byte(updated), DefaultFilePerm); err != nil {
+		return fmt.Errorf("failed to write issue file: %w", err)
+	}
+	return nil
+}
+
+// setFrontmatterLine replaces the line of a frontmatter field, or appends it
+func setFrontmatterLine(lines []string, field, value string) []string {
+	line := fmt.Sprintf("%s: %s", field, value)
+	for i, existing := range lines {
+		if fieldLineRegex(field).MatchString(strings.TrimSpace(existing)) {
+			lines[i] = line
+			return lines
+		}
+	}
+	return append(lines, line)
+}
+
+// fieldLineRegex matches "field: value" in YAML frontmatter (case-insensitive)
+func fieldLineRegex(field string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(field) + `:\s*(.*)$`)
+}
+
 // updateStatusInFrontmatter updates or adds status field in frontmatter.
 func updateStatusInFrontmatter(text, status string) (string, error) {
 	frontmatter, rest := splitFrontmatter(text)
@@ -3,6 +3,7 @@ package piece_test
 import (
 	"encoding/json"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
@@ -372,3 +373,25 @@ func TestResolveIssuePath_NotFound(t *testing.T) {
 		t.Fatal("expected error when issue file doesn't exist")
 	}
 }
+
+func TestExtractLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"inline list", "---\ntitle: A\nlabels: [infra, \"good first issue\"]\n---\n", []string{"infra", "good first issue"}},
+		{"comma separated", "---\nlabels: infra, ui\n---\n", []string{"infra", "ui"}},
+		{"block list", "---\nlabels:\n  - infra\n  - ui\nstatus: todo\n---\n", []string{"infra", "ui"}},
+		{"none", "---\ntitle: A\n---\n", nil},
+		{"no frontmatter", "# labels: [x]\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := piece.ExtractLabels(tt.content)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("ExtractLabels() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+// realDir returns dir with symlinks resolved, as git reports paths
+func realDir(t *testing.T, dir string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
+
+func TestIntegration_SeparateGitDir(t *testing.T) {
+	server := gitfake.NewServer(t)
+	dir := realDir(t, t.TempDir())
+	repo := filepath.Join(dir, "src", "repo")
+	server.Git(dir, "clone", "--separate-git-dir", filepath.Join(dir, "repo.git"), server.URL(), repo)
+	server.Git(repo, "config", "user.email", "test@example.com")
+	server.Git(repo, "config", "user.name", "Test User")
+	worktree := filepath.Join(dir, "pieces", "p1")
+	server.Git(repo, "worktree", "add", "-b", "p1", worktree)
+	server.Commit(worktree, "feature.txt", "feature\n", "add feature")
+
+	// git doesn't know where the main worktree is until mp records it
+	git := adapters.NewGit(adapters.NewOSExec())
+	if root, err := git.GetMainRepoRoot(worktree, ""); err == nil {
+		t.Fatalf("expected no main worktree before it is recorded, got %s", root)
+	}
+	if err := git.RecordMainWorktree(repo); err != nil {
+		t.Fatalf("RecordMainWorktree failed: %v", err)
+	}
+	for _, workDir := range []string{repo, worktree} {
+		root, err := git.GetMainRepoRoot(workDir, "")
+		if err != nil || root != repo {
+			t.Errorf("GetMainRepoRoot(%s) = %q, %v; want %s", workDir, root, err, repo)
+		}
+	}
+
+	handler := newOSHandler()
+	status, err := handler.Status(worktree)
+	if err != nil || !status.InPiece || status.RepoRoot != repo {
+		t.Fatalf("expected piece p1 of %s, got %+v (%v)", repo, status, err)
+	}
+	if err := handler.MergePiece(worktree, "main"); err != nil {
+		t.Fatalf("MergePiece failed: %v", err)
+	}
+	if got := server.Git(repo, "log", "-1", "--format=%s", "main"); got != "feat: p1" {
+		t.Errorf("expected the squash commit on main, got %q", got)
+	}
+}
+
+func TestIntegration_BareRepoWithWorktrees(t *testing.T) {
+	server := gitfake.NewServer(t)
+	dir := realDir(t, t.TempDir())
+	bare := filepath.Join(dir, "proj", ".bare")
+	server.Git(dir, "clone", "--bare", server.URL(), bare)
+	mainWorktree := filepath.Join(dir, "proj", "main")
+	server.Git(bare, "worktree", "add", mainWorktree, "main")
+	piecePath := filepath.Join(dir, "proj", "p1")
+	server.Git(bare, "worktree", "add", "-b", "p1", piecePath)
+
+	git := adapters.NewGit(adapters.NewOSExec())
+	for _, workDir := range []string{mainWorktree, piecePath} {
+		root, err := git.GetMainRepoRoot(workDir, "")
+		if err != nil || root != mainWorktree {
+			t.Errorf("GetMainRepoRoot(%s) = %q, %v; want %s", workDir, root, err, mainWorktree)
+		}
+	}
+
+	handler := newOSHandler()
+	status, err := handler.Status(mainWorktree)
+	if err != nil || status.InPiece || status.RepoRoot != mainWorktree {
+		t.Errorf("expected the main worktree to stand in for the main repo, got %+v (%v)", status, err)
+	}
+	status, err = handler.Status(piecePath)
+	if err != nil || !status.InPiece || status.PieceName != "p1" || status.RepoRoot != mainWorktree {
+		t.Errorf("expected piece p1 of %s, got %+v (%v)", mainWorktree, status, err)
+	}
+}
+
+func TestIntegration_BareRepoSkipsPieces(t *testing.T) {
+	server := gitfake.NewServer(t)
+	dir := realDir(t, t.TempDir())
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	bare := filepath.Join(dir, "proj", ".bare")
+	server.Git(dir, "clone", "--bare", server.URL(), bare)
+	mainWorktree := filepath.Join(dir, "proj", "main")
+	server.Git(bare, "worktree", "add", mainWorktree, "main")
+	// The pieces directory sorts before the main worktree
+	piecesDir, err := piece.PiecesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	piecePath := filepath.Join(piecesDir, "p1")
+	server.Git(bare, "worktree", "add", "-b", "p1", piecePath)
+
+	git := adapters.NewGit(adapters.NewOSExec())
+	for _, workDir := range []string{mainWorktree, piecePath} {
+		root, err := git.GetMainRepoRoot(workDir, piecesDir)
+		if err != nil || root != mainWorktree {
+			t.Errorf("GetMainRepoRoot(%s) = %q, %v; want %s", workDir, root, err, mainWorktree)
+		}
+	}
+
+	status, err := newOSHandler().Status(piecePath)
+	if err != nil || !status.InPiece || status.RepoRoot != mainWorktree {
+		t.Errorf("expected piece p1 of %s, got %+v (%v)", mainWorktree, status, err)
+	}
+}
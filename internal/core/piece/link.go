@@ -0,0 +1,183 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// LinkResult describes a change to the issue a piece is working on
+type LinkResult struct {
+	PieceName         string `json:"piece_name"`
+	IssueID           string `json:"issue_id,omitempty"`
+	IssuePath         string `json:"issue_path,omitempty"`
+	IssueName         string `json:"issue_name,omitempty"`
+	PreviousIssuePath string `json:"previous_issue_path,omitempty"`
+}
+
+// LinkIssue associates the current piece with an issue after creation.
+// The issue is marked in-progress; an issue previously linked to the piece
+// is unlinked and reverted to todo.
+func (h *Handler) LinkIssue(workDir, issuePath string) (LinkResult, error) {
+	status, err := h.requirePiece(workDir)
+	if err != nil {
+		return LinkResult{}, err
+	}
+
+	absIssuePath, relIssuePath, err := h.resolveIssue(status.RepoRoot, issuePath)
+	if err != nil {
+		return LinkResult{}, err
+	}
+
+	issueName, err := ExtractIssueName(absIssuePath, h.deps.FS)
+	if err != nil {
+		return LinkResult{}, fmt.Errorf("failed to extract issue name: %w", err)
+	}
+
+	result := LinkResult{
+		PieceName: status.PieceName,
+		IssueID:   IssueID(relIssuePath),
+		IssuePath: relIssuePath,
+		IssueName: issueName,
+	}
+
+	store := OpenMetadataStore(h.deps, status.WorktreePath)
+	if previous, err := store.ReadIssueMarker(); err == nil {
+		if previous.IssuePath == relIssuePath {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgInfo,
+				Content: fmt.Sprintf("Piece %s is already linked to %s", status.PieceName, relIssuePath),
+				Data:    result,
+			})
+			return result, nil
+		}
+		result.PreviousIssuePath = previous.IssuePath
+	}
+
+	marker := CurrentIssueMarker{
+		IssuePath: relIssuePath,
+		IssueName: issueName,
+		PieceName: status.PieceName,
+	}
+	if err := store.WriteIssueMarker(marker); err != nil {
+		return LinkResult{}, err
+	}
+
+	if result.PreviousIssuePath != "" {
+		h.revertIssueStatus(status.RepoRoot, result.PreviousIssuePath)
+	}
+	h.updateIssueStatusToInProgress(absIssuePath)
+	_, _ = h.syncTitle(status.PieceName, status.WorktreePath, status.RepoRoot)
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Linked piece %s to %s", status.PieceName, relIssuePath),
+		Data:    result,
+	})
+
+	return result, nil
+}
+
+// UnlinkIssue removes the current piece's issue association and reverts
+// the issue to todo if it is still in progress.
+func (h *Handler) UnlinkIssue(workDir string) (LinkResult, error) {
+	status, err := h.requirePiece(workDir)
+	if err != nil {
+		return LinkResult{}, err
+	}
+
+	store := OpenMetadataStore(h.deps, status.WorktreePath)
+	marker, err := store.ReadIssueMarker()
+	if err != nil {
+		return LinkResult{}, fmt.Errorf("piece %s is not linked to an issue", status.PieceName)
+	}
+
+	if err := store.RemoveIssueMarker(); err != nil {
+		return LinkResult{}, err
+	}
+
+	h.revertIssueStatus(status.RepoRoot, marker.IssuePath)
+	_, _ = h.syncTitle(status.PieceName, status.WorktreePath, status.RepoRoot)
+
+	result := LinkResult{
+		PieceName:         status.PieceName,
+		PreviousIssuePath: marker.IssuePath,
+	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Unlinked piece %s from %s", status.PieceName, marker.IssuePath),
+		Data:    result,
+	})
+
+	return result, nil
+}
+
+// requirePiece returns the piece status for workDir, failing outside a piece worktree
+func (h *Handler) requirePiece(workDir string) (PieceStatus, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return PieceStatus{}, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return PieceStatus{}, core.NewNotInPieceError()
+	}
+	return status, nil
+}
+
+// revertIssueStatus sets an in-progress issue (relative to repoRoot) back to todo.
+// Failures are reported as warnings.
+func (h *Handler) revertIssueStatus(repoRoot, relIssuePath string) {
+	if err := h.updateIssueStatusToTodo(filepath.Join(repoRoot, relIssuePath)); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to revert status of %s: %v", relIssuePath, err),
+		})
+		return
+	}
+	h.SyncIssueTitles(repoRoot, relIssuePath)
+}
+
+// RewriteIssueReferences points the issue markers and PR metadata of the
+// repository's pieces that name oldPath at newPath (both relative to
+// repoRoot), e.g. after the issue was renamed. Returns the names of the
+// pieces updated.
+func (h *Handler) RewriteIssueReferences(repoRoot, oldPath, newPath string) ([]string, error) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	pieces, err := h.pieceWorktrees(repoRoot, piecesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	oldPath = filepath.Clean(oldPath)
+	var updated []string
+	for _, p := range pieces {
+		if p.worktree == nil {
+			continue
+		}
+		store := OpenMetadataStore(h.deps, p.path)
+		changed := false
+
+		if marker, err := store.ReadIssueMarker(); err == nil && filepath.Clean(marker.IssuePath) == oldPath {
+			marker.IssuePath = newPath
+			if err := store.WriteIssueMarker(*marker); err != nil {
+				return updated, fmt.Errorf("failed to update the issue marker of %s: %w", p.name, err)
+			}
+			changed = true
+		}
+		if metadata, err := store.ReadPRMetadata(); err == nil && metadata.IssuePath != "" && filepath.Clean(metadata.IssuePath) == oldPath {
+			metadata.IssuePath = newPath
+			if err := store.WritePRMetadata(*metadata); err != nil {
+				return updated, fmt.Errorf("failed to update the PR metadata of %s: %w", p.name, err)
+			}
+			changed = true
+		}
+		if changed {
+			updated = append(updated, p.name)
+		}
+	}
+	return updated, nil
+}
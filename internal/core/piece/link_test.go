@@ -0,0 +1,194 @@
+package piece_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+const linkTestConfig = `{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}}}`
+
+// setupLinkPiece mocks piece-1 of repo /repo with two issues
+func setupLinkPiece(t *testing.T) (*adapters.MemoryFS, *adapters.MockExec, *piece.Handler) {
+	t.Helper()
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(linkTestConfig), 0644)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/issues/first.md", []byte("---\ntitle: First\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("repo/issues/second.md", []byte("---\ntitle: Second\nstatus: todo\n---\n"), 0644)
+
+	return fs, mockExec, handler
+}
+
+func issueStatus(t *testing.T, fs *adapters.MemoryFS, path string) string {
+	t.Helper()
+	status, err := piece.ParseStatus(path, fs)
+	if err != nil {
+		t.Fatalf("failed to parse status of %s: %v", path, err)
+	}
+	return status
+}
+
+func TestHandler_LinkIssue(t *testing.T) {
+	fs, mockExec, handler := setupLinkPiece(t)
+
+	result, err := handler.LinkIssue("/pieces/piece-1", "issues/first.md")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.PieceName != "piece-1" || result.IssuePath != "issues/first.md" || result.IssueName != "First" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	marker, err := piece.OpenMetadataStore(core.Deps{FS: fs, Exec: mockExec}, "/pieces/piece-1").ReadIssueMarker()
+	if err != nil {
+		t.Fatalf("expected marker to be written: %v", err)
+	}
+	if marker.IssuePath != "issues/first.md" || marker.PieceName != "piece-1" {
+		t.Errorf("unexpected marker: %+v", marker)
+	}
+	if got := issueStatus(t, fs, "/repo/issues/first.md"); got != piece.StatusInProgress {
+		t.Errorf("expected issue to be in-progress, got %q", got)
+	}
+}
+
+func TestHandler_LinkIssue_ReplacesPreviousIssue(t *testing.T) {
+	fs, _, handler := setupLinkPiece(t)
+
+	if _, err := handler.LinkIssue("/pieces/piece-1", "issues/first.md"); err != nil {
+		t.Fatalf("first link failed: %v", err)
+	}
+	result, err := handler.LinkIssue("/pieces/piece-1", "issues/second.md")
+	if err != nil {
+		t.Fatalf("second link failed: %v", err)
+	}
+
+	if result.PreviousIssuePath != "issues/first.md" {
+		t.Errorf("expected previous issue first.md, got %q", result.PreviousIssuePath)
+	}
+	if got := issueStatus(t, fs, "/repo/issues/first.md"); got != piece.StatusTodo {
+		t.Errorf("expected previous issue reverted to todo, got %q", got)
+	}
+	if got := issueStatus(t, fs, "/repo/issues/second.md"); got != piece.StatusInProgress {
+		t.Errorf("expected new issue in-progress, got %q", got)
+	}
+}
+
+func TestHandler_LinkIssue_ShortID(t *testing.T) {
+	_, _, handler := setupLinkPiece(t)
+
+	result, err := handler.LinkIssue("/pieces/piece-1", "second")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IssueID != "second" || result.IssuePath != "issues/second.md" {
+		t.Errorf("expected short ID to resolve to issues/second.md, got %+v", result)
+	}
+
+	if _, err := handler.LinkIssue("/pieces/piece-1", "missing"); err == nil {
+		t.Error("expected error for unknown short ID")
+	}
+}
+
+func TestHandler_LinkIssue_TeamDirectories(t *testing.T) {
+	fs, _, handler := setupLinkPiece(t)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(`{"version":"1","issues":{"provider":"markdown","config":{"directories":["issues/backend","issues/frontend"]}}}`), 0644)
+	_ = fs.MkdirAll("repo/issues/frontend", 0755)
+	_ = fs.WriteFile("repo/issues/frontend/theme.md", []byte("---\ntitle: Theme\nstatus: todo\n---\n"), 0644)
+
+	result, err := handler.LinkIssue("/pieces/piece-1", "theme")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IssuePath != "issues/frontend/theme.md" {
+		t.Errorf("expected short ID to resolve in the second directory, got %+v", result)
+	}
+
+	if _, err := handler.LinkIssue("/pieces/piece-1", "issues/first.md"); err == nil {
+		t.Error("expected error for an issue outside the configured directories")
+	}
+}
+
+func TestHandler_LinkIssue_AmbiguousShortID(t *testing.T) {
+	fs, _, handler := setupLinkPiece(t)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(`{"version":"1","issues":{"provider":"markdown","config":{"directories":["issues/backend","issues/frontend"]}}}`), 0644)
+	_ = fs.MkdirAll("repo/issues/backend", 0755)
+	_ = fs.MkdirAll("repo/issues/frontend", 0755)
+	_ = fs.WriteFile("repo/issues/backend/login.md", []byte("---\ntitle: Login API\nstatus: todo\n---\n"), 0644)
+	_ = fs.WriteFile("repo/issues/frontend/login.md", []byte("---\ntitle: Login page\nstatus: todo\n---\n"), 0644)
+
+	_, err := handler.LinkIssue("/pieces/piece-1", "login")
+	if err == nil {
+		t.Fatal("expected an ambiguity error for a short ID in two directories")
+	}
+	for _, candidate := range []string{"issues/backend/login.md", "issues/frontend/login.md"} {
+		if !strings.Contains(err.Error(), candidate) {
+			t.Errorf("expected error to list %s, got: %v", candidate, err)
+		}
+	}
+
+	result, err := handler.LinkIssue("/pieces/piece-1", "issues/frontend/login.md")
+	if err != nil {
+		t.Fatalf("expected the full path to resolve, got: %v", err)
+	}
+	if result.IssuePath != "issues/frontend/login.md" {
+		t.Errorf("expected issues/frontend/login.md, got %+v", result)
+	}
+}
+
+func TestHandler_LinkIssue_OutsideIssuesDir(t *testing.T) {
+	fs, _, handler := setupLinkPiece(t)
+	_ = fs.WriteFile("repo/notes.md", []byte("# Notes\n"), 0644)
+
+	if _, err := handler.LinkIssue("/pieces/piece-1", "notes.md"); err == nil {
+		t.Fatal("expected error for issue outside issues directory")
+	}
+}
+
+func TestHandler_LinkIssue_NotInPiece(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+
+	if _, err := handler.LinkIssue("/repo", "issues/first.md"); err == nil {
+		t.Fatal("expected error outside a piece worktree")
+	}
+}
+
+func TestHandler_UnlinkIssue(t *testing.T) {
+	fs, mockExec, handler := setupLinkPiece(t)
+
+	if _, err := handler.LinkIssue("/pieces/piece-1", "issues/first.md"); err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+	result, err := handler.UnlinkIssue("/pieces/piece-1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if result.PreviousIssuePath != "issues/first.md" {
+		t.Errorf("expected unlinked issue first.md, got %q", result.PreviousIssuePath)
+	}
+	if _, err := piece.OpenMetadataStore(core.Deps{FS: fs, Exec: mockExec}, "/pieces/piece-1").ReadIssueMarker(); err == nil {
+		t.Error("expected marker to be removed")
+	}
+	if got := issueStatus(t, fs, "/repo/issues/first.md"); got != piece.StatusTodo {
+		t.Errorf("expected issue reverted to todo, got %q", got)
+	}
+}
+
+func TestHandler_UnlinkIssue_NotLinked(t *testing.T) {
+	_, _, handler := setupLinkPiece(t)
+
+	if _, err := handler.UnlinkIssue("/pieces/piece-1"); err == nil {
+		t.Fatal("expected error when piece has no linked issue")
+	}
+}
@@ -0,0 +1,68 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+)
+
+// ListOptions configures List
+type ListOptions struct {
+	// MainBranch is the base for pieces without a recorded base (default: main)
+	MainBranch string
+	// Filter keeps only pieces whose status fields (piece_name, branch, dirty,
+	// issue_id, pr_number, ...) match the expression
+	Filter *filter.Expr
+	// DiskUsage measures the disk space of each worktree (see DiskUsage)
+	DiskUsage bool
+}
+
+// List returns the details of every active piece of repoRoot, sorted by name.
+// Remote branches are not checked. A piece whose details cannot be read is
+// listed with its name and path only.
+func (h *Handler) List(repoRoot string, opts ListOptions) ([]PieceStatus, error) {
+	names, err := h.ActivePieces(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+
+	var usage map[string]int64
+	if opts.DiskUsage {
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = filepath.Join(piecesDir, name)
+		}
+		usage = h.DiskUsage(paths, DefaultDiskUsageMaxAge)
+	}
+
+	now := time.Now()
+	pieces := []PieceStatus{}
+	for _, name := range names {
+		worktreePath := filepath.Join(piecesDir, name)
+		status, err := h.Details(worktreePath, DetailsOptions{MainBranch: opts.MainBranch, Fast: true})
+		if err != nil || !status.InPiece {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to read piece %s: %v", name, err),
+			})
+			status = PieceStatus{InPiece: true, PieceName: name, WorktreePath: worktreePath}
+		}
+		status.DiskBytes = usage[worktreePath]
+
+		fields, err := filter.JSONFields(status)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Filter.Match(fields, now) {
+			pieces = append(pieces, status)
+		}
+	}
+	return pieces, nil
+}
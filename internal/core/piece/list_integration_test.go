@@ -0,0 +1,49 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/filter"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_List(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	piecesDir := filepath.Join(dataHome, "monkeypuzzle", "pieces")
+
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	for _, name := range []string{"ahead", "dirty"} {
+		server.Git(clone, "worktree", "add", "-b", name, filepath.Join(piecesDir, name))
+	}
+	server.Commit(filepath.Join(piecesDir, "ahead"), "new.txt", "new\n", "add new")
+	if err := os.WriteFile(filepath.Join(piecesDir, "dirty", "scratch.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := newOSHandler().List(clone, piece.ListOptions{MainBranch: "main"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(all) != 2 || all[0].PieceName != "ahead" || all[0].Ahead != 1 || !all[1].Dirty {
+		t.Fatalf("expected ahead and dirty pieces, got %+v", all)
+	}
+
+	expr, err := filter.Parse("dirty=true OR ahead>1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered, err := newOSHandler().List(clone, piece.ListOptions{MainBranch: "main", Filter: expr})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].PieceName != "dirty" {
+		t.Errorf("expected only the dirty piece, got %+v", filtered)
+	}
+}
@@ -0,0 +1,110 @@
+package piece
+
+import (
+	"fmt"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// LockResult describes a piece after locking or unlocking it
+type LockResult struct {
+	PieceName    string `json:"piece_name"`
+	WorktreePath string `json:"worktree_path"`
+	Locked       bool   `json:"locked"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// LockPiece locks a piece's worktree with git worktree lock, so cleanup and
+// delete leave it alone until it is unlocked
+func (h *Handler) LockPiece(repoRoot, pieceName, reason string) (LockResult, error) {
+	p, err := h.findPieceWorktree(repoRoot, pieceName)
+	if err != nil {
+		return LockResult{}, err
+	}
+	result := LockResult{PieceName: pieceName, WorktreePath: p.path, Locked: true, Reason: reason}
+
+	if p.worktree.Locked {
+		result.Reason = p.worktree.LockReason
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgInfo,
+			Content: fmt.Sprintf("Piece %s is already locked", pieceName),
+			Data:    result,
+		})
+		return result, nil
+	}
+
+	if err := h.git.WorktreeLock(repoRoot, p.worktree.Path, reason); err != nil {
+		return LockResult{}, err
+	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Locked piece: %s", pieceName),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// UnlockPiece unlocks a piece locked with LockPiece
+func (h *Handler) UnlockPiece(repoRoot, pieceName string) (LockResult, error) {
+	p, err := h.findPieceWorktree(repoRoot, pieceName)
+	if err != nil {
+		return LockResult{}, err
+	}
+	result := LockResult{PieceName: pieceName, WorktreePath: p.path}
+
+	if !p.worktree.Locked {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgInfo,
+			Content: fmt.Sprintf("Piece %s is not locked", pieceName),
+			Data:    result,
+		})
+		return result, nil
+	}
+
+	if err := h.git.WorktreeUnlock(repoRoot, p.worktree.Path); err != nil {
+		return LockResult{}, err
+	}
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Unlocked piece: %s", pieceName),
+		Data:    result,
+	})
+	return result, nil
+}
+
+// findPieceWorktree returns the git worktree registered for a piece
+func (h *Handler) findPieceWorktree(repoRoot, pieceName string) (pieceWorktree, error) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return pieceWorktree{}, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	pieces, err := h.pieceWorktrees(repoRoot, piecesDir)
+	if err != nil {
+		return pieceWorktree{}, err
+	}
+	for _, p := range pieces {
+		if p.name != pieceName {
+			continue
+		}
+		if p.worktree == nil {
+			return pieceWorktree{}, fmt.Errorf("piece %s at %s is not a git worktree", pieceName, p.path)
+		}
+		return p, nil
+	}
+	return pieceWorktree{}, fmt.Errorf("piece %s not found in %s", pieceName, piecesDir)
+}
+
+// checkNotLocked fails if the piece is locked, or if git can't tell whether
+// it is: a lock protects the piece, so an unknown state counts as locked
+func (h *Handler) checkNotLocked(repoRoot, piecesDir, pieceName string) error {
+	pieces, err := h.pieceWorktrees(repoRoot, piecesDir)
+	if err != nil {
+		return fmt.Errorf("failed to check whether piece %s is locked: %w", pieceName, err)
+	}
+	for _, p := range pieces {
+		if p.name == pieceName && p.worktree != nil && p.worktree.Locked {
+			return core.NewPieceLockedError(pieceName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package piece_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// mockLockedWorktreeList mocks git worktree list with piece p1 locked for reason
+func mockLockedWorktreeList(m *adapters.MockExec, reason string) {
+	m.AddResponse("git", []string{"worktree", "list", "--porcelain"},
+		[]byte("worktree /repo\nbranch refs/heads/main\n\nworktree "+deleteTestWorktree+"\nbranch refs/heads/p1\nlocked "+reason+"\n"), nil)
+}
+
+func TestHandler_LockPiece(t *testing.T) {
+	_, mockExec, handler := setupDeletePiece(t)
+	mockWorktreeList(mockExec, "p1")
+	mockExec.AddResponse("git", []string{"worktree", "lock", "--reason", "demo friday", deleteTestWorktree}, nil, nil)
+
+	result, err := handler.LockPiece("/repo", "p1", "demo friday")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !result.Locked || result.Reason != "demo friday" {
+		t.Errorf("expected locked result with reason, got %+v", result)
+	}
+	if !mockExec.WasCalled("git", "worktree", "lock", "--reason", "demo friday", deleteTestWorktree) {
+		t.Error("expected git worktree lock")
+	}
+}
+
+func TestHandler_LockPiece_AlreadyLocked(t *testing.T) {
+	_, mockExec, handler := setupDeletePiece(t)
+	mockLockedWorktreeList(mockExec, "keep")
+
+	result, err := handler.LockPiece("/repo", "p1", "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !result.Locked || result.Reason != "keep" {
+		t.Errorf("expected existing lock to be reported, got %+v", result)
+	}
+	if mockExec.WasCalled("git", "worktree", "lock", deleteTestWorktree) {
+		t.Error("expected no second lock")
+	}
+}
+
+func TestHandler_UnlockPiece(t *testing.T) {
+	_, mockExec, handler := setupDeletePiece(t)
+	mockLockedWorktreeList(mockExec, "keep")
+	mockExec.AddResponse("git", []string{"worktree", "unlock", deleteTestWorktree}, nil, nil)
+
+	result, err := handler.UnlockPiece("/repo", "p1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Locked {
+		t.Errorf("expected unlocked result, got %+v", result)
+	}
+	if !mockExec.WasCalled("git", "worktree", "unlock", deleteTestWorktree) {
+		t.Error("expected git worktree unlock")
+	}
+}
+
+func TestHandler_LockPiece_NotFound(t *testing.T) {
+	_, mockExec, handler := setupDeletePiece(t)
+	mockWorktreeList(mockExec, "p1")
+
+	if _, err := handler.LockPiece("/repo", "nope", ""); err == nil {
+		t.Fatal("expected error for unknown piece")
+	}
+}
+
+func TestHandler_DeletePiece_RefusesLocked(t *testing.T) {
+	fs, mockExec, handler := setupDeletePiece(t)
+	mockLockedWorktreeList(mockExec, "keep")
+
+	_, err := handler.DeletePiece("/repo", "p1", piece.DeleteOptions{Force: true})
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodePieceLocked {
+		t.Fatalf("expected piece_locked error, got %v", err)
+	}
+	if mockExec.WasCalled("tmux", "kill-session", "-t", "mp-piece-p1") {
+		t.Error("expected the session of a locked piece to be kept")
+	}
+	if got := issueStatus(t, fs, "/repo/issues/feature.md"); got != piece.StatusInProgress {
+		t.Errorf("expected issue to stay in progress, got %q", got)
+	}
+}
+
+func TestHandler_DeletePiece_RefusesUnknownLockState(t *testing.T) {
+	_, mockExec, handler := setupDeletePiece(t)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, nil, errors.New("fatal: not a git repository"))
+
+	_, err := handler.DeletePiece("/repo", "p1", piece.DeleteOptions{Force: true})
+	if err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Fatalf("expected the lock check to fail, got %v", err)
+	}
+	if mockExec.WasCalled("git", "worktree", "remove", "--force", deleteTestWorktree) {
+		t.Error("expected the worktree to be kept")
+	}
+}
@@ -0,0 +1,119 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/operation"
+)
+
+// inProgressMarkers are the files in a git directory that mark an unfinished
+// operation, each abandoned with `git <operation> --abort`
+var inProgressMarkers = []struct {
+	file, operation string
+}{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+}
+
+// mainCheckout is the state of the main repository before a merge changes
+// it: the checked-out branch (empty when detached), HEAD, and the commit of
+// the branch being merged into
+type mainCheckout struct {
+	repoRoot   string
+	branch     string
+	head       string
+	target     string
+	targetHead string
+}
+
+// prepareMainCheckout checks that the main repository can take a merge into
+// target, with no uncommitted changes and no unfinished rebase, merge,
+// cherry-pick or revert, and records its state for undoMainCheckout
+func (h *Handler) prepareMainCheckout(repoRoot, target string) (mainCheckout, error) {
+	gitDir, err := h.git.RevParseGitDir(repoRoot)
+	if err != nil {
+		return mainCheckout{}, err
+	}
+	for _, marker := range inProgressMarkers {
+		if _, err := h.deps.FS.Stat(filepath.Join(gitDir, marker.file)); err == nil {
+			return mainCheckout{}, core.NewOperationInProgressError(marker.operation, repoRoot)
+		}
+	}
+
+	dirty, err := h.git.HasUncommittedChanges(repoRoot)
+	if err != nil {
+		return mainCheckout{}, err
+	}
+	if dirty {
+		return mainCheckout{}, core.NewDirtyWorktreeError(repoRoot,
+			fmt.Errorf("cannot merge: the main repository %s has uncommitted changes", repoRoot))
+	}
+
+	checkout := mainCheckout{repoRoot: repoRoot, target: target}
+	if checkout.head, err = h.git.GetBranchCommit(repoRoot, "HEAD"); err != nil {
+		return mainCheckout{}, err
+	}
+	if checkout.targetHead, err = h.git.GetBranchCommit(repoRoot, target); err != nil {
+		return mainCheckout{}, err
+	}
+	// A detached HEAD is restored by commit
+	if branch, err := h.git.CurrentBranch(repoRoot); err == nil {
+		checkout.branch = branch
+	}
+	return checkout, nil
+}
+
+// undoMainCheckout registers the rollback of a merge into checkout.target,
+// once it is checked out: resetting target to the commit it had before,
+// discarding a partial squash, then checking out what was checked out before
+func (h *Handler) undoMainCheckout(op *operation.Runner, checkout mainCheckout) {
+	previous := checkout.branch
+	if previous == "" {
+		previous = checkout.head
+	}
+	if previous != checkout.target {
+		op.Undo("check out "+previous, func() error {
+			return h.git.Checkout(checkout.repoRoot, previous)
+		})
+	}
+	op.Undo(fmt.Sprintf("reset %s to %s", checkout.target, shortCommit(checkout.targetHead)), func() error {
+		return h.git.ResetHard(checkout.repoRoot, checkout.targetHead)
+	})
+}
+
+// mainRepoRoot returns the main repository root of the worktree at workDir,
+// as Git.GetMainRepoRoot does; no piece stands in for a bare repository's
+func (h *Handler) mainRepoRoot(workDir string) (string, error) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	return h.git.GetMainRepoRoot(workDir, piecesDir)
+}
+
+// gitCommonDir returns the git dir shared by the worktrees of the repository at
+// repoRoot. It is not repoRoot/.git in repositories cloned with
+// --separate-git-dir; when git can't tell, repoRoot/.git is assumed.
+func gitCommonDir(exec core.Exec, repoRoot string) string {
+	if dir, err := adapters.NewGit(exec).RevParseGitCommonDir(repoRoot); err == nil {
+		return dir
+	}
+	return filepath.Join(repoRoot, ".git")
+}
+
+// recordMainWorktree lets pieces of a --separate-git-dir repository find the
+// main worktree, reporting failures as warnings
+func (h *Handler) recordMainWorktree(repoRoot string) {
+	if err := h.git.RecordMainWorktree(repoRoot); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to record the main worktree: %v", err),
+		})
+	}
+}
@@ -0,0 +1,88 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+// setupPieceWorktree clones the server and adds a piece-1 worktree with one
+// commit, returning the clone and the worktree
+func setupPieceWorktree(t *testing.T, server *gitfake.Server) (string, string) {
+	t.Helper()
+	clone := server.Clone("work")
+	worktree := filepath.Join(server.Dir, "piece-1")
+	server.Git(clone, "worktree", "add", "-b", "piece-1", worktree)
+	server.Commit(worktree, "feature.txt", "feature\n", "add feature")
+	return clone, worktree
+}
+
+func TestIntegration_MergePiece_RollsBackFailedCommit(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	server.Git(clone, "checkout", "-b", "feature-x")
+	mainHead := server.Git(clone, "rev-parse", "main")
+
+	hook := filepath.Join(clone, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := newOSHandler().MergePiece(worktree, "main")
+	if err == nil || !strings.Contains(err.Error(), "failed to commit squashed changes") {
+		t.Fatalf("expected commit failure, got %v", err)
+	}
+	if got := server.Git(clone, "rev-parse", "main"); got != mainHead {
+		t.Errorf("expected main to stay at %s, got %s", mainHead, got)
+	}
+	if got := server.Git(clone, "rev-parse", "--abbrev-ref", "HEAD"); got != "feature-x" {
+		t.Errorf("expected feature-x to be checked out again, got %s", got)
+	}
+	if status := server.Git(clone, "status", "--porcelain"); status != "" {
+		t.Errorf("expected a clean main repository, got:\n%s", status)
+	}
+}
+
+func TestIntegration_MergePiece_RefusesDirtyMainRepo(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	if err := os.WriteFile(filepath.Join(clone, "README.md"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := newOSHandler().MergePiece(worktree, "main")
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeDirtyWorktree {
+		t.Fatalf("expected dirty_worktree error, got %v", err)
+	}
+	if got := server.Git(clone, "status", "--porcelain"); got != "M README.md" {
+		t.Errorf("expected the edit to be left alone, got %q", got)
+	}
+}
+
+func TestIntegration_MergePiece_RecordsMergedCommit(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	handler := newOSHandler()
+
+	if err := handler.MergePiece(worktree, "main"); err != nil {
+		t.Fatalf("MergePiece failed: %v", err)
+	}
+	// The squash commit on main is not an ancestor of the piece branch
+	if merged := server.Git(clone, "branch", "--merged", "main"); strings.Contains(merged, "piece-1") {
+		t.Fatalf("expected the piece branch not to be an ancestor of main after a squash merge, got %q", merged)
+	}
+
+	status, err := handler.IsBranchMerged(worktree, "piece-1", "main")
+	if err != nil {
+		t.Fatalf("IsBranchMerged failed: %v", err)
+	}
+	if !status.IsMerged || status.Method != "mp-merge" {
+		t.Errorf("expected the squash merge to be detected, got %+v", status)
+	}
+}
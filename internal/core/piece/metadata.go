@@ -0,0 +1,195 @@
+package piece
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+const (
+	// MetadataDirName is the directory inside a worktree's git dir holding piece metadata
+	MetadataDirName = "monkeypuzzle"
+
+	currentIssueFilename = "current-issue.json"
+	notesFilename        = "notes.md"
+)
+
+// MetadataStore persists piece-local bookkeeping (issue marker, PR metadata)
+// under the worktree's git dir, e.g. .git/worktrees/<name>/monkeypuzzle/,
+// so it can never be committed. Files left in the worktree's .monkeypuzzle/
+// by older versions are still read and are moved on the next write.
+type MetadataStore struct {
+	fs        core.FS
+	dir       string
+	legacyDir string
+	// err is why the git dir couldn't be resolved; writes fail with it
+	// rather than going to the committable legacy location
+	err error
+}
+
+// NewMetadataStore creates a store for a worktree with a known git dir.
+// An empty gitDir falls back to the legacy in-tree location.
+func NewMetadataStore(fs core.FS, gitDir, worktreePath string) *MetadataStore {
+	s := &MetadataStore{fs: fs, legacyDir: filepath.Join(worktreePath, initcmd.DirName)}
+	if gitDir != "" {
+		s.dir = filepath.Join(gitDir, MetadataDirName)
+	}
+	return s
+}
+
+// OpenMetadataStore creates a store for a worktree, resolving its git dir with
+// git. If that fails, the store can still read legacy in-tree files, but
+// writes return the error.
+func OpenMetadataStore(deps core.Deps, worktreePath string) *MetadataStore {
+	if deps.Exec == nil {
+		return NewMetadataStore(deps.FS, "", worktreePath)
+	}
+	gitDir, err := adapters.NewGit(deps.Exec).RevParseGitDir(worktreePath)
+	s := NewMetadataStore(deps.FS, gitDir, worktreePath)
+	if err != nil {
+		s.err = fmt.Errorf("failed to resolve git dir of %s: %w", worktreePath, err)
+	}
+	return s
+}
+
+// Dir returns the directory metadata is written to
+func (s *MetadataStore) Dir() string {
+	if s.dir == "" {
+		return s.legacyDir
+	}
+	return s.dir
+}
+
+// Path returns the path of a named metadata file
+func (s *MetadataStore) Path(name string) string {
+	return filepath.Join(s.Dir(), name)
+}
+
+// ReadPRMetadata reads the piece's PR metadata
+func (s *MetadataStore) ReadPRMetadata() (*PRMetadata, error) {
+	var metadata PRMetadata
+	if err := s.read(prMetadataFilename, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to read PR metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
+// WritePRMetadata writes the piece's PR metadata
+func (s *MetadataStore) WritePRMetadata(metadata PRMetadata) error {
+	if err := s.write(prMetadataFilename, metadata); err != nil {
+		return fmt.Errorf("failed to write PR metadata: %w", err)
+	}
+	return nil
+}
+
+// ReadIssueMarker reads the marker of the issue the piece was created from
+func (s *MetadataStore) ReadIssueMarker() (*CurrentIssueMarker, error) {
+	var marker CurrentIssueMarker
+	if err := s.read(currentIssueFilename, &marker); err != nil {
+		return nil, fmt.Errorf("failed to read current issue marker: %w", err)
+	}
+	return &marker, nil
+}
+
+// WriteIssueMarker writes the marker of the issue the piece was created from
+func (s *MetadataStore) WriteIssueMarker(marker CurrentIssueMarker) error {
+	if err := s.write(currentIssueFilename, marker); err != nil {
+		return fmt.Errorf("failed to write current issue marker: %w", err)
+	}
+	return nil
+}
+
+// RemoveIssueMarker removes the issue marker, including any legacy in-tree copy
+func (s *MetadataStore) RemoveIssueMarker() error {
+	if err := s.remove(currentIssueFilename); err != nil {
+		return fmt.Errorf("failed to remove current issue marker: %w", err)
+	}
+	return nil
+}
+
+// NotesPath returns the path of the piece's notes, markdown that tools such
+// as mp piece bisect append their findings to
+func (s *MetadataStore) NotesPath() string {
+	return s.Path(notesFilename)
+}
+
+// AppendNotes adds a section to the piece's notes, creating them if needed
+func (s *MetadataStore) AppendNotes(section string) error {
+	notes, err := s.fs.ReadFile(s.NotesPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", notesFilename, err)
+	}
+	if len(notes) > 0 {
+		notes = append(bytes.TrimRight(notes, "\n"), "\n\n"...)
+	}
+	notes = append(notes, strings.TrimRight(section, "\n")+"\n"...)
+	return s.writeFile(notesFilename, notes)
+}
+
+// read decodes a metadata file, falling back to the legacy in-tree location
+func (s *MetadataStore) read(name string, v any) error {
+	data, err := s.fs.ReadFile(s.Path(name))
+	if err != nil && s.dir != "" {
+		data, err = s.fs.ReadFile(filepath.Join(s.legacyDir, name))
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// write encodes a metadata file and removes any legacy in-tree copy
+func (s *MetadataStore) write(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	if err := s.writeFile(name, data); err != nil {
+		return err
+	}
+
+	if s.dir != "" {
+		_ = s.fs.Remove(filepath.Join(s.legacyDir, name))
+	}
+	return nil
+}
+
+// writeFile writes a named metadata file, creating the store's directory
+func (s *MetadataStore) writeFile(name string, data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.fs.MkdirAll(s.Dir(), DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create %s: %w", s.Dir(), err)
+	}
+	return s.fs.WriteFile(s.Path(name), data, initcmd.DefaultFilePerm)
+}
+
+// remove deletes a metadata file from the store and the legacy in-tree location
+func (s *MetadataStore) remove(name string) error {
+	paths := []string{s.Path(name)}
+	if s.dir != "" {
+		paths = append(paths, filepath.Join(s.legacyDir, name))
+	}
+	for _, path := range paths {
+		if _, err := s.fs.Stat(path); err != nil {
+			continue
+		}
+		if err := s.fs.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package piece_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+const (
+	testWorktree = "/workdir"
+	testGitDir   = "/repo/.git/worktrees/workdir"
+)
+
+func TestMetadataStore_WriteAndReadPRMetadata(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	store := piece.NewMetadataStore(fs, testGitDir, testWorktree)
+
+	metadata := piece.PRMetadata{
+		PRNumber:   123,
+		PRURL:      "https://github.com/owner/repo/pull/123",
+		Branch:     "feature-branch",
+		BaseBranch: "main",
+		CreatedAt:  time.Date(2025, 1, 27, 10, 0, 0, 0, time.UTC),
+		IssuePath:  "issues/my-issue.md",
+	}
+
+	// Write metadata
+	if err := store.WritePRMetadata(metadata); err != nil {
+		t.Fatalf("WritePRMetadata failed: %v", err)
+	}
+
+	// Stored under the git dir, not the working tree
+	if _, err := fs.ReadFile(filepath.Join(testGitDir, "monkeypuzzle", "pr-metadata.json")); err != nil {
+		t.Fatalf("expected metadata under git dir: %v", err)
+	}
+	if _, err := fs.ReadFile(filepath.Join(testWorktree, ".monkeypuzzle", "pr-metadata.json")); err == nil {
+		t.Error("expected no metadata file in the working tree")
+	}
+
+	// Read metadata back
+	readMetadata, err := store.ReadPRMetadata()
+	if err != nil {
+		t.Fatalf("ReadPRMetadata failed: %v", err)
+	}
+
+	// Verify fields
+	if readMetadata.PRNumber != 123 {
+		t.Errorf("expected PRNumber 123, got %d", readMetadata.PRNumber)
+	}
+	if readMetadata.PRURL != "https://github.com/owner/repo/pull/123" {
+		t.Errorf("expected PRURL 'https://github.com/owner/repo/pull/123', got %q", readMetadata.PRURL)
+	}
+	if readMetadata.Branch != "feature-branch" {
+		t.Errorf("expected Branch 'feature-branch', got %q", readMetadata.Branch)
+	}
+	if readMetadata.BaseBranch != "main" {
+		t.Errorf("expected BaseBranch 'main', got %q", readMetadata.BaseBranch)
+	}
+	if readMetadata.IssuePath != "issues/my-issue.md" {
+		t.Errorf("expected IssuePath 'issues/my-issue.md', got %q", readMetadata.IssuePath)
+	}
+}
+
+func TestMetadataStore_AppendNotes(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	store := piece.NewMetadataStore(fs, testGitDir, testWorktree)
+
+	if err := store.AppendNotes("## First\n\nfound it\n\n"); err != nil {
+		t.Fatalf("AppendNotes failed: %v", err)
+	}
+	if err := store.AppendNotes("## Second\n"); err != nil {
+		t.Fatalf("AppendNotes failed: %v", err)
+	}
+
+	if store.NotesPath() != filepath.Join(testGitDir, "monkeypuzzle", "notes.md") {
+		t.Errorf("expected notes under the git dir, got %s", store.NotesPath())
+	}
+	notes, _ := fs.ReadFile(store.NotesPath())
+	if string(notes) != "## First\n\nfound it\n\n## Second\n" {
+		t.Errorf("unexpected notes:\n%s", notes)
+	}
+}
+
+func TestMetadataStore_AppendNotes_KeepsUnreadableNotes(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	store := piece.NewMetadataStore(fs, testGitDir, testWorktree)
+	_ = store.AppendNotes("## First\n")
+
+	failing := piece.NewMetadataStore(unreadableFS{fs}, testGitDir, testWorktree)
+	if err := failing.AppendNotes("## Second\n"); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+	notes, _ := fs.ReadFile(store.NotesPath())
+	if string(notes) != "## First\n" {
+		t.Errorf("expected the notes to be kept, got:\n%s", notes)
+	}
+}
+
+// unreadableFS fails every read
+type unreadableFS struct {
+	*adapters.MemoryFS
+}
+
+func (unreadableFS) ReadFile(string) ([]byte, error) {
+	return nil, os.ErrPermission
+}
+
+func TestMetadataStore_ReadPRMetadata_FileNotFound(t *testing.T) {
+	store := piece.NewMetadataStore(adapters.NewMemoryFS(), testGitDir, testWorktree)
+
+	if _, err := store.ReadPRMetadata(); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestMetadataStore_ReadPRMetadata_InvalidJSON(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	_ = fs.WriteFile(filepath.Join(testGitDir, "monkeypuzzle", "pr-metadata.json"), []byte("not valid json"), 0644)
+
+	store := piece.NewMetadataStore(fs, testGitDir, testWorktree)
+	if _, err := store.ReadPRMetadata(); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestMetadataStore_MigratesLegacyFiles(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	legacyPath := filepath.Join(testWorktree, ".monkeypuzzle", "current-issue.json")
+	_ = fs.WriteFile(legacyPath, []byte(`{"issue_path":"issues/a.md","issue_name":"A","piece_name":"a"}`), 0644)
+
+	store := piece.NewMetadataStore(fs, testGitDir, testWorktree)
+
+	// Legacy in-tree marker is still readable
+	marker, err := store.ReadIssueMarker()
+	if err != nil {
+		t.Fatalf("ReadIssueMarker failed: %v", err)
+	}
+	if marker.IssuePath != "issues/a.md" {
+		t.Errorf("expected issue path issues/a.md, got %q", marker.IssuePath)
+	}
+
+	// Writing moves it under the git dir
+	if err := store.WriteIssueMarker(*marker); err != nil {
+		t.Fatalf("WriteIssueMarker failed: %v", err)
+	}
+	if _, err := fs.ReadFile(legacyPath); err == nil {
+		t.Error("expected legacy marker to be removed")
+	}
+	data, err := fs.ReadFile(filepath.Join(testGitDir, "monkeypuzzle", "current-issue.json"))
+	if err != nil {
+		t.Fatalf("expected migrated marker: %v", err)
+	}
+	var migrated piece.CurrentIssueMarker
+	if err := json.Unmarshal(data, &migrated); err != nil || migrated.IssueName != "A" {
+		t.Errorf("unexpected migrated marker %s (err %v)", data, err)
+	}
+}
+
+func TestMetadataStore_UnknownGitDirUsesWorktree(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	store := piece.NewMetadataStore(fs, "", testWorktree)
+
+	metadata := piece.PRMetadata{PRNumber: 789, Branch: "standalone-branch", BaseBranch: "develop", CreatedAt: time.Now()}
+	if err := store.WritePRMetadata(metadata); err != nil {
+		t.Fatalf("WritePRMetadata failed: %v", err)
+	}
+
+	if _, err := fs.ReadFile(filepath.Join(testWorktree, ".monkeypuzzle", "pr-metadata.json")); err != nil {
+		t.Errorf("expected metadata in worktree fallback location: %v", err)
+	}
+}
+
+func TestOpenMetadataStore_ResolvesGitDir(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(testGitDir+"\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+
+	store := piece.OpenMetadataStore(core.Deps{FS: adapters.NewMemoryFS(), Exec: mockExec}, testWorktree)
+	if store.Dir() != filepath.Join(testGitDir, "monkeypuzzle") {
+		t.Errorf("expected store under git dir, got %s", store.Dir())
+	}
+}
+
+func TestOpenMetadataStore_UnresolvedGitDirRefusesWrites(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, nil, errors.New("fatal: not a git repository"))
+	legacyPath := filepath.Join(testWorktree, ".monkeypuzzle", "current-issue.json")
+	_ = fs.WriteFile(legacyPath, []byte(`{"issue_path":"issues/a.md"}`), 0644)
+
+	store := piece.OpenMetadataStore(core.Deps{FS: fs, Exec: mockExec}, testWorktree)
+	if marker, err := store.ReadIssueMarker(); err != nil || marker.IssuePath != "issues/a.md" {
+		t.Errorf("expected the legacy marker to stay readable, got %+v (%v)", marker, err)
+	}
+	if err := store.WritePRMetadata(piece.PRMetadata{PRNumber: 1}); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	if _, err := fs.Stat(filepath.Join(testWorktree, ".monkeypuzzle", "pr-metadata.json")); err == nil {
+		t.Error("expected nothing written to the in-tree location")
+	}
+}
@@ -0,0 +1,84 @@
+package piece
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxNameLength caps piece names, in bytes, unless
+	// pieces.max_name_length says otherwise. It keeps worktree paths well
+	// below Windows' MAX_PATH and file name limits.
+	DefaultMaxNameLength = 64
+	// MinMaxNameLength is the smallest pieces.max_name_length: enough for a
+	// readable prefix and the hash suffix
+	MinMaxNameLength = 16
+	// maxWindowsPath is Windows' MAX_PATH, including the terminating NUL
+	maxWindowsPath = 260
+	// minPathHeadroom is left below MAX_PATH for files inside the worktree
+	minPathHeadroom = 100
+	// nameHashLength is the length of the hash suffix of truncated names
+	nameHashLength = 6
+)
+
+// ValidateMaxNameLength checks pieces.max_name_length from the config
+func ValidateMaxNameLength(n int) error {
+	if n != 0 && n < MinMaxNameLength {
+		return fmt.Errorf("pieces.max_name_length must be 0 (the default, %d) or at least %d, got %d", DefaultMaxNameLength, MinMaxNameLength, n)
+	}
+	return nil
+}
+
+// TruncatePieceName shortens name to at most maxLen bytes. Truncated names
+// keep as many whole words as fit and end in a hash of the full name, so
+// long names sharing a prefix stay distinct: "fix-the-login-a1b2c3".
+func TruncatePieceName(name string, maxLen int) string {
+	if maxLen <= 0 {
+		maxLen = DefaultMaxNameLength
+	}
+	if len(name) <= maxLen {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(sum[:])[:nameHashLength]
+
+	// Cut on a rune boundary, then back to the last word if that doesn't
+	// lose more than half of what's left
+	keep := maxLen - len(suffix) - 1
+	for keep > 0 && !utf8.RuneStart(name[keep]) {
+		keep--
+	}
+	prefix := name[:keep]
+	if i := strings.LastIndexByte(prefix, '-'); i > keep/2 {
+		prefix = prefix[:i]
+	}
+	prefix = strings.TrimRight(prefix, "-")
+	if prefix == "" {
+		return suffix
+	}
+	return prefix + "-" + suffix
+}
+
+// maxNameLength returns pieces.max_name_length for the repository at
+// repoRoot, or the default
+func (h *Handler) maxNameLength(repoRoot string) int {
+	if cfg, err := ReadConfig(repoRoot, h.deps.FS); err == nil && cfg.Pieces.MaxNameLength > 0 {
+		return cfg.Pieces.MaxNameLength
+	}
+	return DefaultMaxNameLength
+}
+
+// CheckPathLength reports a worktree path that leaves too little room below
+// Windows' MAX_PATH for the files inside it. Other systems have no
+// practical limit.
+func CheckPathLength(path, goos string) error {
+	if goos != "windows" || len(path) <= maxWindowsPath-minPathHeadroom {
+		return nil
+	}
+	return fmt.Errorf("worktree path %s is %d characters long; paths inside it may exceed Windows' %d character limit (enable long paths, shorten the piece name, or set XDG_DATA_HOME to a shorter directory)",
+		path, len(path), maxWindowsPath)
+}
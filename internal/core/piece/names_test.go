@@ -0,0 +1,89 @@
+package piece_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestTruncatePieceName(t *testing.T) {
+	long := "support-single-sign-on-with-every-identity-provider-we-have-ever-heard-of"
+	got := piece.TruncatePieceName(long, 32)
+	// The cut falls between words, before a 6 character hash
+	if len(got) != len("support-single-sign-on")+7 || !strings.HasPrefix(got, "support-single-sign-on-") {
+		t.Errorf("expected a truncated name cut between words, got %q", got)
+	}
+	other := piece.TruncatePieceName(long+"-too", 32)
+	if got == other {
+		t.Errorf("expected names sharing a prefix to stay distinct, both are %q", got)
+	}
+	if got := piece.TruncatePieceName("short", 32); got != "short" {
+		t.Errorf("expected short names to be kept, got %q", got)
+	}
+
+	unicode := piece.TruncatePieceName(strings.Repeat("é", 40), 20)
+	if len(unicode) > 20 || !strings.HasPrefix(unicode, "éééééé") {
+		t.Errorf("expected a cut on a rune boundary, got %q", unicode)
+	}
+}
+
+func TestSanitizePieceName_Truncates(t *testing.T) {
+	got := piece.SanitizePieceName(strings.Repeat("Very Long Title ", 10))
+	if len(got) > piece.DefaultMaxNameLength {
+		t.Errorf("expected at most %d bytes, got %d: %q", piece.DefaultMaxNameLength, len(got), got)
+	}
+}
+
+func TestValidateMaxNameLength(t *testing.T) {
+	if err := piece.ValidateMaxNameLength(0); err != nil {
+		t.Errorf("expected 0 to be valid, got %v", err)
+	}
+	if err := piece.ValidateMaxNameLength(8); err == nil {
+		t.Error("expected 8 to be rejected")
+	}
+}
+
+func TestCheckPathLength(t *testing.T) {
+	long := "C:\\Users\\me\\AppData\\Local\\" + strings.Repeat("x", 200)
+	if err := piece.CheckPathLength(long, "windows"); err == nil {
+		t.Error("expected a long path to be reported on Windows")
+	}
+	if err := piece.CheckPathLength(long, "linux"); err != nil {
+		t.Errorf("expected no limit on Linux, got %v", err)
+	}
+}
+
+func TestSessionName_ReplacesInvalidCharacters(t *testing.T) {
+	name := piece.SessionName("v1.2:fix")
+	if name != "mp-piece-v1_2_fix" {
+		t.Errorf("unexpected session name %q", name)
+	}
+	if err := adapters.ValidateSessionName(name); err != nil {
+		t.Errorf("expected a valid session name, got %v", err)
+	}
+}
+
+func TestHandler_CreatePiece_TruncatesLongName(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	output := adapters.NewBufferOutput()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: output, Exec: mockExec})
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1","pieces":{"max_name_length":20}}`), 0644)
+
+	// The branch of the shortened name exists, which stops the creation
+	short := piece.TruncatePieceName("a-name-longer-than-twenty", 20)
+	mockExec.AddResponse("git", []string{"rev-parse", "--verify", "--quiet", "refs/heads/" + short}, []byte("abc123\n"), nil)
+
+	_, err := handler.CreatePiece("/monkeypuzzle", "a-name-longer-than-twenty")
+	if remediable, ok := core.AsRemediable(err); !ok || remediable.Code != core.CodeBranchExists {
+		t.Fatalf("expected the piece to be created as %q, got %v", short, err)
+	}
+	if !output.HasWarning() {
+		t.Error("expected a warning that the name exceeds pieces.max_name_length")
+	}
+}
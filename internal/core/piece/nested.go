@@ -0,0 +1,37 @@
+package piece
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// AllowNestedRepoEnv, set to 1, lets commands run in a repository nested
+// inside a piece worktree (see checkNestedRepo)
+const AllowNestedRepoEnv = "MP_ALLOW_NESTED_REPO"
+
+// checkNestedRepo refuses a repository root that lies inside a piece worktree
+// without being the worktree itself, such as a vendored sub-repository: git
+// resolves commands run there to that repository, so an update or merge would
+// act on the wrong one. Without a pieces directory to compare against, it
+// refuses too.
+func checkNestedRepo(root string) error {
+	if root == "" || os.Getenv(AllowNestedRepoEnv) == "1" {
+		return nil
+	}
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(piecesDir, root)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	name, nested, ok := strings.Cut(rel, string(filepath.Separator))
+	if !ok || nested == "" {
+		return nil
+	}
+	return core.NewNestedRepoError(root, filepath.Join(piecesDir, name))
+}
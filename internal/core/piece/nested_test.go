@@ -0,0 +1,78 @@
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+const nestedRepo = "/test-data/monkeypuzzle/pieces/p1/vendor/lib"
+
+// setupNestedRepo mocks a vendored repository inside piece p1
+func setupNestedRepo(t *testing.T) (*adapters.MockExec, *piece.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(nestedRepo+"/.git\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(nestedRepo+"\n"), nil)
+	return mockExec, handler
+}
+
+func TestHandler_Status_RefusesNestedRepo(t *testing.T) {
+	_, handler := setupNestedRepo(t)
+
+	_, err := handler.Status(nestedRepo)
+	re, ok := core.AsRemediable(err)
+	if !ok || re.Code != core.CodeNestedRepo {
+		t.Fatalf("expected nested_repo error, got %v", err)
+	}
+	if err.Error() != nestedRepo+" is a repository nested inside the piece worktree /test-data/monkeypuzzle/pieces/p1" {
+		t.Errorf("unexpected error %q", err)
+	}
+}
+
+func TestHandler_Status_RefusesNestedWorktree(t *testing.T) {
+	mockExec, handler := setupNestedRepo(t)
+	// The vendored repository is itself a worktree of another repository
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/src/lib/.git/worktrees/lib\n"), nil)
+
+	_, err := handler.Status(nestedRepo)
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeNestedRepo {
+		t.Fatalf("expected nested_repo error, got %v", err)
+	}
+}
+
+func TestHandler_Status_RefusesWithoutPiecesDir(t *testing.T) {
+	_, handler := setupNestedRepo(t)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", "")
+
+	if _, err := handler.Status(nestedRepo); err == nil {
+		t.Fatal("expected an error when the pieces directory is unknown")
+	}
+}
+
+func TestHandler_Status_AllowsNestedRepoWhenOverridden(t *testing.T) {
+	_, handler := setupNestedRepo(t)
+	t.Setenv(piece.AllowNestedRepoEnv, "1")
+
+	status, err := handler.Status(nestedRepo)
+	if err != nil || status.InPiece || status.RepoRoot != nestedRepo {
+		t.Errorf("expected the nested repository as main repo, got %+v (%v)", status, err)
+	}
+}
+
+func TestHandler_Status_PieceWorktreeIsNotNested(t *testing.T) {
+	mockExec, handler := setupNestedRepo(t)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/test-data/monkeypuzzle/pieces/p1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+
+	status, err := handler.Status("/test-data/monkeypuzzle/pieces/p1/src")
+	if err != nil || !status.InPiece || status.PieceName != "p1" {
+		t.Errorf("expected piece p1, got %+v (%v)", status, err)
+	}
+}
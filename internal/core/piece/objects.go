@@ -0,0 +1,83 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Ways a piece can fail to share the repository's object store, reported by
+// ObjectSharing
+const (
+	// ObjectsStandalone is a piece with a .git directory of its own, e.g. a
+	// clone or copy written over the worktree
+	ObjectsStandalone = "standalone"
+	// ObjectsPrivate is a worktree whose git dir holds objects of its own,
+	// e.g. after a .git directory was copied over it
+	ObjectsPrivate = "private-objects"
+)
+
+// ObjectSharing reports whether a piece stores its git objects in the
+// repository's shared object store
+type ObjectSharing struct {
+	PieceName    string `json:"piece_name"`
+	WorktreePath string `json:"worktree_path"`
+	Shared       bool   `json:"shared"`
+	// Problem is one of the Objects constants when Shared is false
+	Problem string `json:"problem,omitempty"`
+	// DuplicatedBytes is the space taken by objects outside the shared store
+	DuplicatedBytes int64 `json:"duplicated_bytes,omitempty"`
+}
+
+// ObjectSharing checks that every piece uses the object store of repoRoot,
+// as git worktrees do, and measures the objects stored elsewhere. Only
+// worktrees of repoRoot are checked: the pieces directory is shared with
+// other repositories. Pieces without a .git are left out.
+func (h *Handler) ObjectSharing(repoRoot string) ([]ObjectSharing, error) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	pieces, err := h.pieceWorktrees(repoRoot, piecesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []ObjectSharing{}
+	for _, p := range pieces {
+		if p.worktree == nil {
+			continue
+		}
+		dotGit, err := h.deps.FS.Stat(filepath.Join(p.path, ".git"))
+		if err != nil {
+			continue
+		}
+		result := ObjectSharing{PieceName: p.name, WorktreePath: p.path, Shared: true}
+		var objectsDir string
+		if dotGit.IsDir() {
+			result.Problem = ObjectsStandalone
+			objectsDir = filepath.Join(p.path, ".git", "objects")
+		} else if gitDir, err := h.git.RevParseGitDir(p.path); err == nil {
+			if info, err := h.deps.FS.Stat(filepath.Join(gitDir, "objects")); err == nil && info.IsDir() {
+				result.Problem = ObjectsPrivate
+				objectsDir = filepath.Join(gitDir, "objects")
+			}
+		}
+		if result.Problem != "" {
+			result.Shared = false
+			if objectsDir != "" {
+				result.DuplicatedBytes, _ = h.DirSize(objectsDir)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// sameDir resolves symlinks in dir where possible, so paths git reports
+// differently compare equal
+func sameDir(dir string) string {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		return resolved
+	}
+	return filepath.Clean(dir)
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_ObjectSharing(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	piecesDir := filepath.Join(dataHome, "monkeypuzzle", "pieces")
+
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	server.Git(clone, "worktree", "add", "-b", "shared", filepath.Join(piecesDir, "shared"))
+	server.Git(clone, "worktree", "add", "-b", "private", filepath.Join(piecesDir, "private"))
+	server.Git(clone, "worktree", "add", "-b", "copy", filepath.Join(piecesDir, "copy"))
+	server.Git(clone, "clone", "--quiet", clone, filepath.Join(piecesDir, "stray"))
+	other := server.Clone("other")
+	server.Git(other, "worktree", "add", "-b", "foreign", filepath.Join(piecesDir, "foreign"))
+
+	// A clone copied over the copy worktree
+	copyDir := filepath.Join(piecesDir, "copy")
+	if err := os.Remove(filepath.Join(copyDir, ".git")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(piecesDir, "stray", ".git"), filepath.Join(copyDir, ".git")); err != nil {
+		t.Fatal(err)
+	}
+	// A stray object store in the private worktree's git dir
+	privateObjects := filepath.Join(clone, ".git", "worktrees", "private", "objects", "pack")
+	if err := os.MkdirAll(privateObjects, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(privateObjects, "stray.pack"), make([]byte, 64<<10), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := newOSHandler().ObjectSharing(clone)
+	if err != nil {
+		t.Fatalf("ObjectSharing failed: %v", err)
+	}
+	got := map[string]piece.ObjectSharing{}
+	for _, r := range results {
+		got[r.PieceName] = r
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected three pieces checked, got %+v", results)
+	}
+	if !got["shared"].Shared {
+		t.Errorf("expected the worktree to share objects, got %+v", got["shared"])
+	}
+	if r := got["copy"]; r.Shared || r.Problem != piece.ObjectsStandalone || r.DuplicatedBytes == 0 {
+		t.Errorf("expected the copied clone reported as standalone, got %+v", r)
+	}
+	for _, name := range []string{"foreign", "stray"} {
+		if r, ok := got[name]; ok {
+			t.Errorf("expected %s, which is not a worktree of the repository, left out, got %+v", name, r)
+		}
+	}
+	if r := got["private"]; r.Shared || r.Problem != piece.ObjectsPrivate || r.DuplicatedBytes < 64<<10 {
+		t.Errorf("expected the private objects reported, got %+v", r)
+	}
+}
@@ -0,0 +1,82 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Ways `mp piece open` opens a piece
+const (
+	OpenEditor      = "editor"
+	OpenFileManager = "file-manager"
+)
+
+// OpenTarget is a piece worktree and the command that opens it
+type OpenTarget struct {
+	PieceName    string `json:"piece_name"`
+	WorktreePath string `json:"worktree_path"`
+	// Via is OpenEditor or OpenFileManager
+	Via string `json:"via"`
+	// Command is the command line run, ending with the worktree path
+	Command []string `json:"command"`
+}
+
+// PieceWorktree returns the name and worktree path of the named piece, or of the
+// piece containing workDir when name is empty
+func (h *Handler) PieceWorktree(workDir, name string) (string, string, error) {
+	if name == "" {
+		status, err := h.requirePiece(workDir)
+		if err != nil {
+			return "", "", fmt.Errorf("%w (pass a piece name)", err)
+		}
+		return status.PieceName, status.WorktreePath, nil
+	}
+
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+	worktreePath := filepath.Join(piecesDir, name)
+	if _, err := h.deps.FS.Stat(worktreePath); err != nil {
+		return "", "", fmt.Errorf("piece %s not found at %s", name, worktreePath)
+	}
+	return name, worktreePath, nil
+}
+
+// OpenOptions selects how a piece is opened
+type OpenOptions struct {
+	// Editor is the configured editor command line (config "editor")
+	Editor string
+	// Getenv looks up $VISUAL and $EDITOR
+	Getenv func(string) string
+	// GOOS picks the file manager
+	GOOS string
+	// Reveal opens the file manager even when an editor is configured
+	Reveal bool
+}
+
+// OpenCommand returns the command line opening worktreePath: the configured
+// editor, else $VISUAL, else $EDITOR, else the OS file manager
+func OpenCommand(worktreePath string, opts OpenOptions) (via string, command []string) {
+	if !opts.Reveal {
+		editor := opts.Editor
+		for _, name := range []string{"VISUAL", "EDITOR"} {
+			if editor == "" && opts.Getenv != nil {
+				editor = opts.Getenv(name)
+			}
+		}
+		if fields := strings.Fields(editor); len(fields) > 0 {
+			return OpenEditor, append(fields, worktreePath)
+		}
+	}
+
+	switch opts.GOOS {
+	case "darwin":
+		return OpenFileManager, []string{"open", worktreePath}
+	case "windows":
+		return OpenFileManager, []string{"explorer", worktreePath}
+	default:
+		return OpenFileManager, []string{"xdg-open", worktreePath}
+	}
+}
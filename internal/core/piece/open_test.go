@@ -0,0 +1,89 @@
+package piece_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestOpenCommand(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	tests := []struct {
+		name    string
+		opts    piece.OpenOptions
+		wantVia string
+		want    []string
+	}{
+		{
+			name:    "configured editor with arguments",
+			opts:    piece.OpenOptions{Editor: "code -n", Getenv: env(map[string]string{"EDITOR": "vim"})},
+			wantVia: piece.OpenEditor,
+			want:    []string{"code", "-n", "/p"},
+		},
+		{
+			name:    "VISUAL before EDITOR",
+			opts:    piece.OpenOptions{Getenv: env(map[string]string{"VISUAL": "subl -w", "EDITOR": "vim"})},
+			wantVia: piece.OpenEditor,
+			want:    []string{"subl", "-w", "/p"},
+		},
+		{
+			name:    "EDITOR",
+			opts:    piece.OpenOptions{Getenv: env(map[string]string{"EDITOR": "vim"})},
+			wantVia: piece.OpenEditor,
+			want:    []string{"vim", "/p"},
+		},
+		{
+			name:    "no editor on macOS",
+			opts:    piece.OpenOptions{Getenv: env(nil), GOOS: "darwin"},
+			wantVia: piece.OpenFileManager,
+			want:    []string{"open", "/p"},
+		},
+		{
+			name:    "reveal on linux",
+			opts:    piece.OpenOptions{Editor: "code -n", GOOS: "linux", Reveal: true},
+			wantVia: piece.OpenFileManager,
+			want:    []string{"xdg-open", "/p"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			via, command := piece.OpenCommand("/p", tt.opts)
+			if via != tt.wantVia || !reflect.DeepEqual(command, tt.want) {
+				t.Errorf("expected %s %v, got %s %v", tt.wantVia, tt.want, via, command)
+			}
+		})
+	}
+}
+
+func TestHandler_PieceWorktree(t *testing.T) {
+	_, _, handler := setupDeletePiece(t)
+
+	name, path, err := handler.PieceWorktree("/repo", "p1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if name != "p1" || path != deleteTestWorktree {
+		t.Errorf("expected p1 at %s, got %s at %s", deleteTestWorktree, name, path)
+	}
+
+	if _, _, err := handler.PieceWorktree("/repo", "missing"); err == nil {
+		t.Error("expected error for a missing piece")
+	}
+}
+
+func TestHandler_PieceWorktree_Current(t *testing.T) {
+	_, _, _, handler := setupMockPiece(t, "main")
+
+	name, path, err := handler.PieceWorktree("/pieces/piece-1", "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if name != "piece-1" || path != "/pieces/piece-1" {
+		t.Errorf("expected current piece, got %s at %s", name, path)
+	}
+}
@@ -0,0 +1,168 @@
+package piece
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Operations summarized by a Plan
+const (
+	// OperationMerge merges a piece into its base branch
+	OperationMerge = "merge"
+	// OperationUpdate merges the base branch into a piece
+	OperationUpdate = "update"
+	// OperationReset resets a piece branch to its remote head, with
+	// mp piece doctor --reset-to-remote
+	OperationReset = "reset"
+)
+
+// planFileLimit caps the files listed in a plan's text summary
+const planFileLimit = 10
+
+// Plan summarizes a history-modifying operation before it runs, so callers can
+// confirm it
+type Plan struct {
+	Operation string `json:"operation"`
+	PieceName string `json:"piece_name"`
+	// Source is the branch whose commits are brought into Target
+	Source string `json:"source"`
+	// Target is the branch that gets a new commit
+	Target string `json:"target"`
+	// Strategy is the merge strategy of a merge: squash, merge or rebase-ff
+	Strategy string `json:"strategy,omitempty"`
+	// Push is set when a merge pushes Target to the remote afterwards
+	Push bool `json:"push,omitempty"`
+	// Commits counts the commits on Source that Target lacks; for a reset,
+	// the commits of Target that are discarded
+	Commits int `json:"commits"`
+	// Files changed on Source since it diverged from Target; for a reset,
+	// the files the discarded commits change
+	Files []string `json:"files"`
+}
+
+// Summary describes the plan for a confirmation prompt
+func (p Plan) Summary() string {
+	var b strings.Builder
+	switch {
+	case p.Operation == OperationReset:
+		fmt.Fprintf(&b, "Reset %s to %s, discarding the local commits %s lacks\n", p.Target, p.Source, p.Source)
+	case p.Operation == OperationMerge && p.Strategy == MergeStrategyMerge:
+		fmt.Fprintf(&b, "Merge %s into %s: checks out %s in the main repository and adds a merge commit\n", p.Source, p.Target, p.Target)
+	case p.Operation == OperationMerge && p.Strategy == MergeStrategyRebaseFF:
+		fmt.Fprintf(&b, "Rebase %s onto %s, rewriting the piece branch, then fast-forward %s in the main repository\n", p.Source, p.Target, p.Target)
+	case p.Operation == OperationMerge:
+		fmt.Fprintf(&b, "Squash merge %s into %s: checks out %s in the main repository and commits on it\n", p.Source, p.Target, p.Target)
+	default:
+		fmt.Fprintf(&b, "Merge %s into %s: adds a merge commit to the piece\n", p.Source, p.Target)
+	}
+	if p.Push {
+		fmt.Fprintf(&b, "  then pushes %s to the remote\n", p.Target)
+	}
+	fmt.Fprintf(&b, "  %d commit(s), %d file(s) changed\n", p.Commits, len(p.Files))
+	for i, f := range p.Files {
+		if i == planFileLimit {
+			fmt.Fprintf(&b, "  ... and %d more\n", len(p.Files)-planFileLimit)
+			break
+		}
+		fmt.Fprintf(&b, "  %s\n", f)
+	}
+	return b.String()
+}
+
+// PlanMerge summarizes merging the current piece into target with the
+// repository's merge strategy
+func (h *Handler) PlanMerge(workDir, target string) (*Plan, error) {
+	return h.plan(workDir, OperationMerge, target)
+}
+
+// PlanUpdate summarizes merging base into the current piece
+func (h *Handler) PlanUpdate(workDir, base string) (*Plan, error) {
+	return h.plan(workDir, OperationUpdate, base)
+}
+
+// PlanReset summarizes resetting the current piece branch to its remote
+// head, or returns nil when the reset discards no commits: the branch is in
+// sync with or behind the remote, or the remote can't be reset to
+func (h *Handler) PlanReset(workDir string) (*Plan, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+
+	branch, err := h.git.CurrentBranch(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	remote, err := h.CheckRemoteBranch(status.WorktreePath, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check remote branch: %w", err)
+	}
+	if remote.State != RemoteDiverged {
+		return nil, nil
+	}
+
+	remoteRef := h.git.Remote() + "/" + branch
+	p := &Plan{Operation: OperationReset, PieceName: status.PieceName, Source: remoteRef, Target: branch}
+	commits, err := h.git.GetCommitMessages(workDir, remoteRef, branch)
+	if err != nil {
+		return nil, err
+	}
+	p.Commits = len(commits)
+	if p.Files, err = h.git.ChangedFiles(workDir, remoteRef, branch); err != nil {
+		return nil, err
+	}
+	if p.Files == nil {
+		p.Files = []string{}
+	}
+	return p, nil
+}
+
+func (h *Handler) plan(workDir, operation, base string) (*Plan, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+
+	branch, err := h.git.CurrentBranch(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	p := &Plan{Operation: operation, PieceName: status.PieceName, Source: branch, Target: base}
+	if operation == OperationMerge {
+		if p.Strategy, err = h.MergeStrategy(status.RepoRoot, ""); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case operation == OperationUpdate:
+		p.Source, p.Target = base, branch
+	case base == branch:
+		return nil, fmt.Errorf("cannot merge %s into itself", branch)
+	case !h.git.BranchExists(workDir, base):
+		// Merging commits on the target, so it must be a local branch
+		return nil, fmt.Errorf("target branch %s does not exist", base)
+	}
+
+	commits, err := h.git.GetCommitMessages(workDir, p.Target, p.Source)
+	if err != nil {
+		return nil, err
+	}
+	p.Commits = len(commits)
+
+	if p.Files, err = h.git.ChangedFiles(workDir, p.Target, p.Source); err != nil {
+		return nil, err
+	}
+	if p.Files == nil {
+		p.Files = []string{}
+	}
+	return p, nil
+}
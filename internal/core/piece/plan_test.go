@@ -0,0 +1,101 @@
+package piece_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_PlanMerge(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "release/1.2")
+	mockExec.AddResponse("git", []string{"log", "--format=%s", "release/1.2..piece-1"}, []byte("fix: a\nfix: b\n"), nil)
+	mockExec.AddResponse("git", []string{"diff", "--name-only", "release/1.2...piece-1"}, []byte("a.go\nb.go\n"), nil)
+
+	plan, err := handler.PlanMerge("/pieces/piece-1", "release/1.2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if plan.Operation != piece.OperationMerge || plan.Source != "piece-1" || plan.Target != "release/1.2" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+	if plan.Commits != 2 || len(plan.Files) != 2 {
+		t.Errorf("expected 2 commits and 2 files, got %d and %v", plan.Commits, plan.Files)
+	}
+	if plan.Strategy != piece.MergeStrategySquash {
+		t.Errorf("expected the squash strategy by default, got %q", plan.Strategy)
+	}
+	if summary := plan.Summary(); !strings.Contains(summary, "Squash merge piece-1 into release/1.2") || !strings.Contains(summary, "a.go") {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+	if mockExec.WasCalled("git", "checkout", "release/1.2") {
+		t.Error("expected planning not to touch any branch")
+	}
+}
+
+func TestPlan_Summary_Strategies(t *testing.T) {
+	plan := piece.Plan{Operation: piece.OperationMerge, Source: "piece-1", Target: "main", Strategy: piece.MergeStrategyMerge}
+	if summary := plan.Summary(); !strings.Contains(summary, "adds a merge commit") {
+		t.Errorf("unexpected merge summary:\n%s", summary)
+	}
+	plan.Strategy = piece.MergeStrategyRebaseFF
+	if summary := plan.Summary(); !strings.Contains(summary, "Rebase piece-1 onto main, rewriting the piece branch") {
+		t.Errorf("unexpected rebase-ff summary:\n%s", summary)
+	}
+	plan.Push = true
+	if summary := plan.Summary(); !strings.Contains(summary, "then pushes main to the remote") {
+		t.Errorf("expected the push in the summary:\n%s", summary)
+	}
+}
+
+func TestHandler_PlanMerge_InvalidTarget(t *testing.T) {
+	_, _, _, handler := setupMockPiece(t, "release/1.2")
+
+	if _, err := handler.PlanMerge("/pieces/piece-1", "release/9.9"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected missing target error, got %v", err)
+	}
+	if _, err := handler.PlanMerge("/pieces/piece-1", "piece-1"); err == nil || !strings.Contains(err.Error(), "into itself") {
+		t.Errorf("expected self-merge error, got %v", err)
+	}
+}
+
+func TestHandler_PlanUpdate(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "release/1.2")
+	mockExec.AddResponse("git", []string{"log", "--format=%s", "piece-1..main"}, []byte("feat: upstream\n"), nil)
+	mockExec.AddResponse("git", []string{"diff", "--name-only", "piece-1...main"}, nil, nil)
+
+	plan, err := handler.PlanUpdate("/pieces/piece-1", "main")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if plan.Source != "main" || plan.Target != "piece-1" || plan.Commits != 1 {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+	if plan.Files == nil || len(plan.Files) != 0 {
+		t.Errorf("expected an empty file list, got %v", plan.Files)
+	}
+}
+
+func TestHandler_PlanReset(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	setupDivergedPiece(mockExec)
+	mockExec.AddResponse("git", []string{"log", "--format=%s", "origin/p1..p1"}, []byte("wip: local\n"), nil)
+	mockExec.AddResponse("git", []string{"diff", "--name-only", "origin/p1...p1"}, []byte("a.go\n"), nil)
+
+	plan, err := handler.PlanReset("/pieces/p1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if plan == nil || plan.Operation != piece.OperationReset || plan.Commits != 1 || len(plan.Files) != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if summary := plan.Summary(); !strings.Contains(summary, "Reset p1 to origin/p1, discarding") {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+	if mockExec.WasCalled("git", "reset", "--hard", "origin/p1") {
+		t.Error("expected planning not to reset")
+	}
+}
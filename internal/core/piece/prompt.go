@@ -0,0 +1,68 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/conventions"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// PiecePrompt is the brief an agent starts work on a piece from
+type PiecePrompt struct {
+	PieceName  string `json:"piece_name"`
+	Branch     string `json:"branch"`
+	BaseBranch string `json:"base_branch"`
+	// IssuePath is the linked issue, relative to the repository root
+	IssuePath string `json:"issue_path,omitempty"`
+	// Context is set when the repository's context.md is embedded
+	Context bool   `json:"context"`
+	Prompt  string `json:"prompt"`
+}
+
+// Prompt builds the brief for an agent working on the piece containing
+// workDir: where the piece is and where it merges (see BaseBranch; mainBranch
+// is the fallback), its linked issue, and the repository's conventions from
+// .monkeypuzzle/context.md, as the piece's branch has them
+func (h *Handler) Prompt(workDir, mainBranch string) (PiecePrompt, error) {
+	status, err := h.requirePiece(workDir)
+	if err != nil {
+		return PiecePrompt{}, err
+	}
+	branch, err := h.git.CurrentBranch(status.WorktreePath)
+	if err != nil {
+		return PiecePrompt{}, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	prompt := PiecePrompt{
+		PieceName:  status.PieceName,
+		Branch:     branch,
+		BaseBranch: h.BaseBranch(status.WorktreePath, mainBranch),
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are working on the piece %s in %s, on branch %s, which will be merged into %s.\n",
+		prompt.PieceName, status.WorktreePath, prompt.Branch, prompt.BaseBranch)
+
+	if marker, err := OpenMetadataStore(h.deps, status.WorktreePath).ReadIssueMarker(); err == nil && marker.IssuePath != "" {
+		content, err := h.deps.FS.ReadFile(filepath.Join(status.RepoRoot, marker.IssuePath))
+		if err != nil {
+			return PiecePrompt{}, fmt.Errorf("failed to read issue %s: %w", marker.IssuePath, err)
+		}
+		prompt.IssuePath = marker.IssuePath
+		fmt.Fprintf(&b, "\n## Issue\n\nFrom %s:\n\n%s\n", marker.IssuePath, strings.TrimSpace(string(content)))
+	}
+
+	projectContext, err := conventions.Load(h.deps.FS, status.WorktreePath)
+	if err != nil {
+		return PiecePrompt{}, err
+	}
+	if projectContext.Defined() {
+		prompt.Context = true
+		fmt.Fprintf(&b, "\n## Project conventions\n\nFrom %s; follow them:\n\n%s\n",
+			filepath.Join(initcmd.DirName, conventions.Filename), projectContext.Content)
+	}
+
+	prompt.Prompt = b.String()
+	return prompt, nil
+}
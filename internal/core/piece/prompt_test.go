@@ -0,0 +1,44 @@
+package piece_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/conventions"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_Prompt(t *testing.T) {
+	fs, _, _, handler := setupMockPiece(t, "main")
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/login.md", IssueName: "Login"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = fs.WriteFile("/repo/issues/login.md", []byte("# Login\n\nAdd a login form.\n"), 0644)
+	_ = fs.WriteFile(conventions.Path("/pieces/piece-1"), []byte("## Coding standards\n\nWrap errors.\n"), 0644)
+
+	prompt, err := handler.Prompt("/pieces/piece-1", "main")
+	if err != nil {
+		t.Fatalf("Prompt failed: %v", err)
+	}
+	if prompt.IssuePath != "issues/login.md" || !prompt.Context || prompt.BaseBranch != "main" {
+		t.Errorf("unexpected prompt: %+v", prompt)
+	}
+	for _, want := range []string{"on branch piece-1, which will be merged into main", "Add a login form.", "Wrap errors."} {
+		if !strings.Contains(prompt.Prompt, want) {
+			t.Errorf("expected the prompt to contain %q, got:\n%s", want, prompt.Prompt)
+		}
+	}
+}
+
+func TestHandler_Prompt_WithoutIssueOrContext(t *testing.T) {
+	_, _, _, handler := setupMockPiece(t, "main")
+
+	prompt, err := handler.Prompt("/pieces/piece-1", "main")
+	if err != nil {
+		t.Fatalf("Prompt failed: %v", err)
+	}
+	if prompt.Context || prompt.IssuePath != "" || strings.Contains(prompt.Prompt, "##") {
+		t.Errorf("expected only the piece's location, got %+v", prompt)
+	}
+}
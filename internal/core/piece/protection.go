@@ -0,0 +1,30 @@
+package piece
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// CheckPushAllowed fails with a protected_branch error when GitHub branch
+// protection or a ruleset blocks direct pushes to branch of the repository at
+// repoRoot, so a merge can switch to a pull request before changing anything.
+// When the protection can't be looked up, e.g. without gh or for a remote
+// that isn't on GitHub, it warns and leaves the push to try.
+func (h *Handler) CheckPushAllowed(repoRoot, branch string) error {
+	h.useConfiguredRemote(repoRoot)
+	protection, err := h.github.BranchProtection(repoRoot, branch)
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Could not check the branch protection of %s, pushing anyway: %v", branch, err),
+		})
+		return nil
+	}
+	if protection.PushBlocked() {
+		return core.NewProtectedBranchError(branch, errors.New(strings.Join(protection.PushBlockers, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package piece_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// mockBranchProtection answers the GitHub API lookups of main's protection
+func mockBranchProtection(m *adapters.MockExec, branch, protection, rules string) {
+	m.AddResponse("gh", []string{"api", "repos/{owner}/{repo}/branches/main"}, []byte(branch), nil)
+	if protection != "" {
+		m.AddResponse("gh", []string{"api", "repos/{owner}/{repo}/branches/main/protection"}, []byte(protection), nil)
+	}
+	m.AddResponse("gh", []string{"api", "repos/{owner}/{repo}/rules/branches/main"}, []byte(rules), nil)
+}
+
+func TestHandler_MergePiece_PushRefusesProtectedBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		branch     string
+		protection string
+		rules      string
+		want       string
+	}{
+		{"required reviews", `{"protected": true}`, `{"required_pull_request_reviews": {"required_approving_review_count": 1}}`, `[]`, "required pull request reviews"},
+		{"required checks", `{"protected": true, "protection": {"required_status_checks": {"enforcement_level": "everyone", "contexts": ["ci"]}}}`, "", `[]`, "required status checks"},
+		{"ruleset", `{"protected": false}`, "", `[{"type": "deletion"}, {"type": "pull_request"}]`, "pull requests required by a ruleset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, mockExec, handler := setupMockPiece(t, "main")
+			mockBranchProtection(mockExec, tt.branch, tt.protection, tt.rules)
+
+			err := handler.MergePieceWithOptions("/pieces/piece-1", "main", piece.MergeOptions{Push: true})
+			if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeProtectedBranch {
+				t.Fatalf("expected protected_branch error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected the error to name %q, got %v", tt.want, err)
+			}
+			if mockExec.WasCalled("git", "checkout", "main") {
+				t.Error("expected nothing to be merged")
+			}
+		})
+	}
+}
+
+func TestHandler_MergePiece_PushUnprotectedBranch(t *testing.T) {
+	_, out, mockExec, handler := setupMockPiece(t, "main")
+	// Protection without admin access to its details or blocking rules
+	mockBranchProtection(mockExec, `{"protected": true}`, "", `[{"type": "non_fast_forward"}]`)
+	mockExec.AddResponse("gh", []string{"api", "repos/{owner}/{repo}/branches/main/protection"}, []byte(`{"message": "Not Found"}`), errors.New("exit status 1"))
+	mockMainCheckout(mockExec, "main")
+	mockExec.AddResponse("git", []string{"checkout", "main"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge", "--squash", "piece-1"}, nil, nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", "feat: piece-1\n\nSquashed commits:\n- feat: add feature\n"}, nil, nil)
+	mockExec.AddResponse("git", []string{"push", "origin", "main"}, nil, nil)
+
+	if err := handler.MergePieceWithOptions("/pieces/piece-1", "main", piece.MergeOptions{Push: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !mockExec.WasCalled("git", "push", "origin", "main") {
+		t.Error("expected main to be pushed")
+	}
+	if last := out.Last(); last == nil || !strings.Contains(last.Content, "pushed main to origin") {
+		t.Errorf("expected the push to be reported, got %+v", last)
+	}
+}
+
+func TestHandler_MergePiece_RollsBackRefusedPush(t *testing.T) {
+	_, out, mockExec, handler := setupMockPiece(t, "main")
+	// gh can't tell, e.g. without credentials; GitHub refuses the push
+	mockExec.AddResponse("gh", []string{"api", "repos/{owner}/{repo}/branches/main"}, []byte("gh auth login"), errors.New("exit status 4"))
+	mockMainCheckout(mockExec, "main")
+	mockExec.AddResponse("git", []string{"checkout", "main"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge", "--squash", "piece-1"}, nil, nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", "feat: piece-1\n\nSquashed commits:\n- feat: add feature\n"}, nil, nil)
+	mockExec.AddResponse("git", []string{"push", "origin", "main"},
+		[]byte("remote: error: GH006: Protected branch update failed for refs/heads/main.\n ! [remote rejected] main -> main (protected branch hook declined)\n"),
+		errors.New("exit status 1"))
+	mockExec.AddResponse("git", []string{"reset", "--hard", "target00"}, nil, nil)
+	mockExec.AddResponse("git", []string{"checkout", "piece-1"}, nil, nil)
+
+	err := handler.MergePieceWithOptions("/pieces/piece-1", "main", piece.MergeOptions{Push: true})
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeProtectedBranch {
+		t.Fatalf("expected protected_branch error, got %v", err)
+	}
+	if !mockExec.WasCalled("git", "reset", "--hard", "target00") {
+		t.Error("expected the merge to be rolled back")
+	}
+	if len(out.Messages) == 0 || !strings.Contains(out.Messages[0].Content, "Could not check the branch protection of main") {
+		t.Errorf("expected a warning that protection was not checked, got %+v", out.Messages)
+	}
+}
@@ -0,0 +1,284 @@
+package piece
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Remote branch states relative to the local piece branch on the configured remote
+const (
+	RemoteInSync    = "in-sync"
+	RemoteAhead     = "ahead"      // local has commits not yet pushed
+	RemoteBehind    = "behind"     // remote has commits not yet pulled
+	RemoteDiverged  = "diverged"   // remote was force-updated
+	RemoteDeleted   = "deleted"    // branch was pushed but no longer exists on the remote
+	RemoteNotPushed = "not-pushed" // branch was never pushed
+)
+
+// RemoteState describes how a piece branch relates to its remote counterpart
+type RemoteState struct {
+	Branch       string `json:"branch"`
+	State        string `json:"state"`
+	LocalCommit  string `json:"local_commit"`
+	RemoteCommit string `json:"remote_commit,omitempty"`
+}
+
+// Problem reports whether the state needs attention (force-updated or deleted upstream)
+func (r RemoteState) Problem() bool {
+	return r.State == RemoteDiverged || r.State == RemoteDeleted
+}
+
+// Describe returns a human-readable explanation of a problematic state
+func (r RemoteState) Describe() string {
+	switch r.State {
+	case RemoteDiverged:
+		return fmt.Sprintf("remote branch %s was force-updated and has diverged from the local branch", r.Branch)
+	case RemoteDeleted:
+		return fmt.Sprintf("remote branch %s was deleted", r.Branch)
+	default:
+		return fmt.Sprintf("remote branch %s is %s", r.Branch, r.State)
+	}
+}
+
+// PR provider config keys for fork-based workflows
+const (
+	// PRConfigRepo is the "owner/name" repository PRs are opened against (the upstream)
+	PRConfigRepo = "repo"
+	// PRConfigHeadOwner is the fork owner whose branch is used as the PR head
+	PRConfigHeadOwner = "head_owner"
+	// PRConfigHost is the GitHub Enterprise host PRs live on, e.g. github.example.com
+	PRConfigHost = "host"
+)
+
+// EnvGHHost is gh's variable selecting the GitHub host, used when no host is configured
+const EnvGHHost = "GH_HOST"
+
+// RemoteConfig describes where piece branches are pushed and where PRs live
+type RemoteConfig struct {
+	// Remote is the git remote branches are pushed to
+	Remote string
+	// Repo is the repository PRs target; empty lets gh infer it
+	Repo string
+	// HeadOwner qualifies the PR head branch when pushing to a fork
+	HeadOwner string
+	// Host is the GitHub Enterprise host; empty lets gh infer it (github.com)
+	Host string
+}
+
+// ReadRemoteConfig returns the remote configuration for a repository.
+// Missing config falls back to pushing to origin with gh-inferred repositories.
+func ReadRemoteConfig(repoRoot string, fs core.FS) RemoteConfig {
+	rc := RemoteConfig{Remote: adapters.DefaultRemote, Host: os.Getenv(EnvGHHost)}
+	cfg, err := ReadConfig(repoRoot, fs)
+	if err != nil {
+		return rc
+	}
+	if cfg.Git.Remote != "" {
+		rc.Remote = cfg.Git.Remote
+	}
+	rc.Repo = cfg.PR.Config[PRConfigRepo]
+	rc.HeadOwner = cfg.PR.Config[PRConfigHeadOwner]
+	if host := cfg.PR.Config[PRConfigHost]; host != "" {
+		rc.Host = host
+	}
+	return rc
+}
+
+// HeadRef returns the PR head for a branch, qualified as "owner:branch" for forks
+func (rc RemoteConfig) HeadRef(branch string) string {
+	if rc.HeadOwner == "" {
+		return ""
+	}
+	return rc.HeadOwner + ":" + branch
+}
+
+// useConfiguredRemote points the git and GitHub adapters at the remote and
+// repository configured for the repository at repoRoot.
+func (h *Handler) useConfiguredRemote(repoRoot string) {
+	rc := ReadRemoteConfig(repoRoot, h.deps.FS)
+	h.git.SetRemote(rc.Remote)
+	h.github.SetRemote(rc.Remote)
+	h.github.SetRepo(rc.Repo)
+	h.github.SetHost(rc.Host)
+}
+
+// CheckRemoteBranch compares a local branch head with its head on the configured remote.
+// The remote branch is fetched when heads differ so ancestry can be checked.
+func (h *Handler) CheckRemoteBranch(worktreePath, branch string) (RemoteState, error) {
+	h.useConfiguredRemote(worktreePath)
+	state := RemoteState{Branch: branch}
+
+	local, err := h.git.GetBranchCommit(worktreePath, branch)
+	if err != nil {
+		return state, err
+	}
+	state.LocalCommit = local
+
+	remote, err := h.git.RemoteBranchCommit(worktreePath, branch)
+	if err != nil {
+		return state, err
+	}
+	state.RemoteCommit = remote
+
+	switch {
+	case remote == "":
+		if h.git.HasRemoteTrackingBranch(worktreePath, branch) {
+			state.State = RemoteDeleted
+		} else {
+			state.State = RemoteNotPushed
+		}
+		return state, nil
+	case remote == local:
+		state.State = RemoteInSync
+		return state, nil
+	}
+
+	if err := h.git.FetchBranch(worktreePath, branch); err != nil {
+		return state, err
+	}
+
+	remoteInLocal, err := h.git.IsCommitInBranch(worktreePath, remote, local)
+	if err != nil {
+		return state, err
+	}
+	if remoteInLocal {
+		state.State = RemoteAhead
+		return state, nil
+	}
+
+	localInRemote, err := h.git.IsCommitInBranch(worktreePath, local, remote)
+	if err != nil {
+		return state, err
+	}
+	if localInRemote {
+		state.State = RemoteBehind
+	} else {
+		state.State = RemoteDiverged
+	}
+	return state, nil
+}
+
+// DoctorOptions configures piece doctor
+type DoctorOptions struct {
+	// ResetToRemote resets the piece branch to the remote head when it was force-updated or is behind
+	ResetToRemote bool
+	// MainBranch is the branch update conflicts are predicted against
+	// (default: the piece's recorded base, else main)
+	MainBranch string
+}
+
+// DoctorResult reports the health of the current piece
+type DoctorResult struct {
+	PieceName string         `json:"piece_name"`
+	Remote    RemoteState    `json:"remote"`
+	Conflicts *ConflictCheck `json:"conflicts,omitempty"`
+	Reset     bool           `json:"reset,omitempty"`
+	// Resources is the usage of the piece's tmux session, when it is running
+	Resources *ResourceUsage `json:"resources,omitempty"`
+}
+
+// Doctor checks the current piece branch against its remote, warning when it was
+// force-updated or deleted upstream. With ResetToRemote, the local branch is
+// reset to the remote head (refusing if there are uncommitted changes).
+func (h *Handler) Doctor(workDir string, opts DoctorOptions) (*DoctorResult, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
+	}
+
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+
+	// Migrate pieces created before local artifacts were excluded
+	h.ensureExcludes(status.RepoRoot)
+
+	branch, err := h.git.CurrentBranch(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	remote, err := h.CheckRemoteBranch(status.WorktreePath, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check remote branch: %w", err)
+	}
+
+	result := &DoctorResult{PieceName: status.PieceName, Remote: remote}
+
+	if remote.Problem() {
+		content := remote.Describe()
+		if remote.State == RemoteDiverged && !opts.ResetToRemote {
+			content += " (run 'mp piece doctor --reset-to-remote' to discard local commits and match the remote)"
+		}
+		h.deps.Output.Write(core.Message{Type: core.MsgWarning, Content: content, Data: remote})
+	}
+
+	mainBranch := opts.MainBranch
+	if mainBranch == "" {
+		mainBranch = h.BaseBranch(status.WorktreePath, "main")
+	}
+	if check, err := h.checkConflicts(workDir, status.PieceName, branch, mainBranch); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Skipping conflict check: %v", err),
+		})
+	} else {
+		result.Conflicts = check
+		if check.HasConflicts() {
+			h.deps.Output.Write(core.Message{
+				Type: core.MsgWarning,
+				Content: fmt.Sprintf("Updating from %s would conflict in: %s (see 'mp piece update --check')",
+					mainBranch, strings.Join(check.Conflicts, ", ")),
+				Data: check,
+			})
+		}
+	}
+
+	h.checkResources(result, status)
+
+	if !opts.ResetToRemote {
+		return result, nil
+	}
+
+	switch remote.State {
+	case RemoteInSync:
+		return result, nil
+	case RemoteDeleted, RemoteNotPushed:
+		return nil, fmt.Errorf("cannot reset to remote: %s does not exist on %s", branch, h.git.Remote())
+	case RemoteAhead:
+		return nil, fmt.Errorf("cannot reset to remote: local branch has unpushed commits and the remote has nothing new")
+	}
+
+	dirty, err := h.git.HasUncommittedChanges(status.WorktreePath)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("cannot reset to remote: worktree has uncommitted changes")
+	}
+
+	remoteRef := h.git.Remote() + "/" + branch
+	if err := h.git.ResetHard(status.WorktreePath, remoteRef); err != nil {
+		return nil, err
+	}
+	result.Reset = true
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Reset %s to %s (%s)", branch, remoteRef, shortCommit(remote.RemoteCommit)),
+	})
+
+	return result, nil
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
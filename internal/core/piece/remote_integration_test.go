@@ -0,0 +1,101 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+// End-to-end remote checks against a local bare origin and a gh shim.
+// Run with: go test -tags=integration ./internal/core/piece/...
+
+func newOSHandler() *piece.Handler {
+	return piece.NewHandler(core.Deps{
+		FS:     adapters.NewOSFS(""),
+		Output: adapters.NewBufferOutput(),
+		Exec:   adapters.NewOSExec(),
+	})
+}
+
+func TestIntegration_CheckRemoteBranch_Lifecycle(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	server.Git(clone, "checkout", "-b", "feature")
+	server.Commit(clone, "feature.txt", "one\n", "add feature")
+	handler := newOSHandler()
+
+	check := func(want string) {
+		t.Helper()
+		state, err := handler.CheckRemoteBranch(clone, "feature")
+		if err != nil {
+			t.Fatalf("CheckRemoteBranch failed: %v", err)
+		}
+		if state.State != want {
+			t.Fatalf("expected state %s, got %+v", want, state)
+		}
+	}
+
+	check(piece.RemoteNotPushed)
+
+	if err := adapters.NewGitHub(adapters.NewOSExec()).Push(clone); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if server.BranchCommit("feature") == "" {
+		t.Fatal("expected feature branch on the server after push")
+	}
+	check(piece.RemoteInSync)
+
+	server.Commit(clone, "feature.txt", "two\n", "local change")
+	check(piece.RemoteAhead)
+	server.Git(clone, "push")
+
+	server.PushCommit("feature", "other.txt", "theirs\n")
+	check(piece.RemoteBehind)
+
+	server.RewriteBranch("feature")
+	check(piece.RemoteDiverged)
+
+	server.DeleteBranch("feature")
+	check(piece.RemoteDeleted)
+}
+
+func TestIntegration_IsBranchMerged_ViaGHShim(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	server.Git(clone, "checkout", "-b", "feature")
+	server.Commit(clone, "feature.txt", "one\n", "add feature")
+	server.Git(clone, "push", "-u", "origin", "feature")
+
+	gh := gitfake.InstallGH(t)
+	gh.MergedPRForBranch("feature", 42)
+
+	status, err := newOSHandler().IsBranchMerged(clone, "feature", "main")
+	if err != nil {
+		t.Fatalf("IsBranchMerged failed: %v", err)
+	}
+	if !status.IsMerged || status.Method != "pr-branch" || status.PRNumber != 42 {
+		t.Errorf("expected merge detected via gh pr list, got %+v", status)
+	}
+	if !status.ExistsOnRemote {
+		t.Error("expected branch to exist on the remote")
+	}
+	if len(gh.Calls()) == 0 {
+		t.Error("expected the gh shim to be called")
+	}
+}
+
+func TestIntegration_GHShim_Unauthenticated(t *testing.T) {
+	gh := gitfake.InstallGH(t)
+	gh.Unauthenticated()
+
+	_, err := adapters.NewGitHub(adapters.NewOSExec()).IsPRMerged(t.TempDir(), 1)
+	re, ok := core.AsRemediable(err)
+	if !ok || re.Code != core.CodeGHNotAuthenticated {
+		t.Fatalf("expected gh authentication error, got: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package piece_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_CheckRemoteBranch(t *testing.T) {
+	notAncestor := fmt.Errorf("exit status 1")
+
+	tests := []struct {
+		name   string
+		remote string
+		setup  func(m *adapters.MockExec)
+		want   string
+	}{
+		{
+			name:   "in sync",
+			remote: "aaa111\trefs/heads/p1\n",
+			want:   piece.RemoteInSync,
+		},
+		{
+			name:   "local ahead",
+			remote: "bbb222\trefs/heads/p1\n",
+			setup: func(m *adapters.MockExec) {
+				m.AddResponse("git", []string{"merge-base", "--is-ancestor", "bbb222", "aaa111"}, nil, nil)
+			},
+			want: piece.RemoteAhead,
+		},
+		{
+			name:   "local behind",
+			remote: "bbb222\trefs/heads/p1\n",
+			setup: func(m *adapters.MockExec) {
+				m.AddResponse("git", []string{"merge-base", "--is-ancestor", "bbb222", "aaa111"}, nil, notAncestor)
+				m.AddResponse("git", []string{"merge-base", "--is-ancestor", "aaa111", "bbb222"}, nil, nil)
+			},
+			want: piece.RemoteBehind,
+		},
+		{
+			name:   "force-updated",
+			remote: "bbb222\trefs/heads/p1\n",
+			setup: func(m *adapters.MockExec) {
+				m.AddResponse("git", []string{"merge-base", "--is-ancestor", "bbb222", "aaa111"}, nil, notAncestor)
+				m.AddResponse("git", []string{"merge-base", "--is-ancestor", "aaa111", "bbb222"}, nil, notAncestor)
+			},
+			want: piece.RemoteDiverged,
+		},
+		{
+			name: "deleted upstream",
+			setup: func(m *adapters.MockExec) {
+				m.AddResponse("git", []string{"rev-parse", "--verify", "--quiet", "refs/remotes/origin/p1"}, []byte("aaa111\n"), nil)
+			},
+			want: piece.RemoteDeleted,
+		},
+		{
+			name: "never pushed",
+			want: piece.RemoteNotPushed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := adapters.NewMockExec()
+			handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+			mockExec.AddResponse("git", []string{"rev-parse", "p1"}, []byte("aaa111\n"), nil)
+			mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", "p1"}, []byte(tt.remote), nil)
+			mockExec.AddResponse("git", []string{"fetch", "origin", "p1"}, nil, nil)
+			if tt.setup != nil {
+				tt.setup(mockExec)
+			}
+
+			state, err := handler.CheckRemoteBranch("/pieces/p1", "p1")
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if state.State != tt.want {
+				t.Errorf("expected state %s, got %s", tt.want, state.State)
+			}
+		})
+	}
+}
+
+func TestHandler_CheckRemoteBranch_ConfiguredRemote(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_ = fs.WriteFile("pieces/p1/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1","git":{"remote":"upstream"}}`), 0644)
+	mockExec.AddResponse("git", []string{"rev-parse", "p1"}, []byte("aaa111\n"), nil)
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "upstream", "p1"}, []byte("aaa111\trefs/heads/p1\n"), nil)
+
+	state, err := handler.CheckRemoteBranch("/pieces/p1", "p1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if state.State != piece.RemoteInSync {
+		t.Errorf("expected in-sync against upstream, got %s", state.State)
+	}
+}
+
+// setupDivergedPiece mocks a piece worktree whose branch was force-updated on origin
+func setupDivergedPiece(mockExec *adapters.MockExec) {
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/pieces/p1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("p1\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "p1"}, []byte("aaa111\n"), nil)
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", "p1"}, []byte("bbb222\trefs/heads/p1\n"), nil)
+	mockExec.AddResponse("git", []string{"fetch", "origin", "p1"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge-base", "--is-ancestor", "bbb222", "aaa111"}, nil, fmt.Errorf("exit status 1"))
+	mockExec.AddResponse("git", []string{"merge-base", "--is-ancestor", "aaa111", "bbb222"}, nil, fmt.Errorf("exit status 1"))
+}
+
+func TestHandler_Doctor_WarnsOnForceUpdate(t *testing.T) {
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: out, Exec: mockExec})
+	setupDivergedPiece(mockExec)
+
+	result, err := handler.Doctor("/pieces/p1", piece.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Remote.State != piece.RemoteDiverged {
+		t.Errorf("expected diverged, got %s", result.Remote.State)
+	}
+	if !out.HasWarning() {
+		t.Error("expected warning about force-updated branch")
+	}
+	if mockExec.WasCalled("git", "reset", "--hard", "origin/p1") {
+		t.Error("expected no reset without --reset-to-remote")
+	}
+}
+
+func TestHandler_Doctor_ResetToRemote(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	setupDivergedPiece(mockExec)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	mockExec.AddResponse("git", []string{"reset", "--hard", "origin/p1"}, nil, nil)
+
+	result, err := handler.Doctor("/pieces/p1", piece.DoctorOptions{ResetToRemote: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !result.Reset {
+		t.Error("expected branch to be reset")
+	}
+	if !mockExec.WasCalled("git", "reset", "--hard", "origin/p1") {
+		t.Error("expected git reset --hard origin/p1")
+	}
+}
+
+func TestHandler_Doctor_ResetRefusesDirtyWorktree(t *testing.T) {
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: mockExec})
+	setupDivergedPiece(mockExec)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, []byte(" M main.go\n"), nil)
+
+	if _, err := handler.Doctor("/pieces/p1", piece.DoctorOptions{ResetToRemote: true}); err == nil {
+		t.Fatal("expected error for dirty worktree")
+	}
+	if mockExec.WasCalled("git", "reset", "--hard", "origin/p1") {
+		t.Error("expected no reset with uncommitted changes")
+	}
+}
+
+func TestHandler_CleanupMergedPieces_WarnsOnDeletedRemote(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	pieceName := "gone-piece"
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/"+pieceName, 0755)
+
+	mockWorktreeList(mockExec, pieceName)
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte(pieceName+"\n"), nil)
+	mockExec.AddResponse("git", []string{"ls-remote", "--heads", "origin", pieceName}, []byte(""), nil)
+	mockExec.AddResponse("git", []string{"branch", "--merged", "main"}, []byte("  main\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", pieceName}, []byte("abc123\n"), nil)
+	mockExec.AddResponse("git", []string{"merge-base", "--is-ancestor", "abc123", "main"}, nil, fmt.Errorf("exit status 1"))
+	mockExec.AddResponse("git", []string{"rev-parse", "--verify", "--quiet", "refs/remotes/origin/" + pieceName}, []byte("abc123\n"), nil)
+
+	results, err := handler.CleanupMergedPieces("/repo", piece.CleanupOptions{MainBranch: "main"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected unmerged piece to be kept, got %d results", len(results))
+	}
+	if !out.HasWarning() {
+		t.Error("expected warning about deleted remote branch")
+	}
+}
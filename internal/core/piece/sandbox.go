@@ -0,0 +1,92 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Sandbox runners supported for hook execution
+const (
+	SandboxBwrap       = "bwrap"
+	SandboxSandboxExec = "sandbox-exec"
+)
+
+var validSandboxes = []string{SandboxBwrap, SandboxSandboxExec}
+
+// Sandbox describes how a hook is confined: the home directory is made
+// read-only while the listed writable paths stay read-write.
+type Sandbox struct {
+	// Runner is the sandbox program ("bwrap" or "sandbox-exec")
+	Runner string
+	// HomeDir is the directory made read-only inside the sandbox
+	HomeDir string
+	// Writable lists paths that remain writable (worktree, git dir, extras)
+	Writable []string
+}
+
+// ValidateSandbox checks if a sandbox runner name is supported.
+// An empty name means hooks run without a sandbox.
+func ValidateSandbox(runner string) error {
+	if runner == "" {
+		return nil
+	}
+	for _, v := range validSandboxes {
+		if v == runner {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid hooks.sandbox %q (valid: %v)", runner, validSandboxes)
+}
+
+// Wrap returns the command line that runs name/args inside the sandbox.
+func (s Sandbox) Wrap(name string, args ...string) (string, []string, error) {
+	switch s.Runner {
+	case SandboxBwrap:
+		return SandboxBwrap, append(s.bwrapArgs(), append([]string{"--", name}, args...)...), nil
+	case SandboxSandboxExec:
+		return SandboxSandboxExec, append([]string{"-p", s.seatbeltProfile(), name}, args...), nil
+	default:
+		return "", nil, ValidateSandbox(s.Runner)
+	}
+}
+
+// bwrapArgs builds bubblewrap arguments: the host filesystem is bound as-is,
+// the home directory is re-bound read-only, then writable paths are re-bound read-write.
+func (s Sandbox) bwrapArgs() []string {
+	args := []string{"--dev-bind", "/", "/", "--ro-bind", s.HomeDir, s.HomeDir}
+	for _, p := range s.writablePaths() {
+		args = append(args, "--bind", p, p)
+	}
+	return args
+}
+
+// seatbeltProfile builds a macOS sandbox profile denying writes under the
+// home directory except for the writable paths (later rules take precedence).
+func (s Sandbox) seatbeltProfile() string {
+	var b strings.Builder
+	b.WriteString("(version 1)\n(allow default)\n")
+	b.WriteString(fmt.Sprintf("(deny file-write* (subpath %q))\n", s.HomeDir))
+	for _, p := range s.writablePaths() {
+		b.WriteString(fmt.Sprintf("(allow file-write* (subpath %q))\n", p))
+	}
+	return b.String()
+}
+
+// writablePaths returns cleaned, de-duplicated writable paths, skipping empty entries.
+func (s Sandbox) writablePaths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, p := range s.Writable {
+		if p == "" {
+			continue
+		}
+		p = filepath.Clean(p)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
@@ -0,0 +1,193 @@
+package piece_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestSandbox_Wrap_Bwrap(t *testing.T) {
+	sandbox := piece.Sandbox{
+		Runner:   piece.SandboxBwrap,
+		HomeDir:  "/home/user",
+		Writable: []string{"/home/user/pieces/p1", "/home/user/repo/.git", "/home/user/pieces/p1", ""},
+	}
+
+	name, args, err := sandbox.Wrap("bash", "/repo/hook.sh")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if name != "bwrap" {
+		t.Errorf("expected runner bwrap, got %q", name)
+	}
+
+	got := strings.Join(args, " ")
+	expected := "--dev-bind / / --ro-bind /home/user /home/user " +
+		"--bind /home/user/pieces/p1 /home/user/pieces/p1 " +
+		"--bind /home/user/repo/.git /home/user/repo/.git " +
+		"-- bash /repo/hook.sh"
+	if got != expected {
+		t.Errorf("unexpected bwrap args:\n got: %s\nwant: %s", got, expected)
+	}
+}
+
+func TestSandbox_Wrap_SandboxExec(t *testing.T) {
+	sandbox := piece.Sandbox{
+		Runner:   piece.SandboxSandboxExec,
+		HomeDir:  "/Users/me",
+		Writable: []string{"/Users/me/pieces/p1"},
+	}
+
+	name, args, err := sandbox.Wrap("bash", "/repo/hook.sh")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if name != "sandbox-exec" {
+		t.Errorf("expected runner sandbox-exec, got %q", name)
+	}
+	if len(args) != 4 || args[0] != "-p" || args[2] != "bash" || args[3] != "/repo/hook.sh" {
+		t.Fatalf("unexpected sandbox-exec args: %v", args)
+	}
+
+	profile := args[1]
+	if !strings.Contains(profile, `(deny file-write* (subpath "/Users/me"))`) {
+		t.Errorf("expected profile to deny writes under home, got:\n%s", profile)
+	}
+	if !strings.Contains(profile, `(allow file-write* (subpath "/Users/me/pieces/p1"))`) {
+		t.Errorf("expected profile to allow writes in worktree, got:\n%s", profile)
+	}
+}
+
+func TestValidateSandbox(t *testing.T) {
+	tests := []struct {
+		runner  string
+		wantErr bool
+	}{
+		{"", false},
+		{"bwrap", false},
+		{"sandbox-exec", false},
+		{"firejail", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.runner, func(t *testing.T) {
+			err := piece.ValidateSandbox(tt.runner)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSandbox(%q) error = %v, wantErr %v", tt.runner, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHookRunner_RunHook_UsesConfiguredSandbox(t *testing.T) {
+	t.Setenv("HOME", "/home/user")
+
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	deps := core.Deps{FS: fs, Output: out, Exec: mockExec}
+	runner := piece.NewHookRunner(deps)
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle/hooks", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(`{"version":"1","hooks":{"sandbox":"bwrap","writable":["tmp-cache"]}}`), 0644)
+	_ = fs.WriteFile(filepath.Join("repo", piece.HooksDir, piece.HookBeforePieceMerge), []byte("#!/bin/bash\n"), 0755)
+
+	fullHookPath := filepath.Join("/repo", piece.HooksDir, piece.HookBeforePieceMerge)
+	mockExec.AddResponse("bwrap", []string{
+		"--dev-bind", "/", "/",
+		"--ro-bind", "/home/user", "/home/user",
+		"--bind", "/pieces/p1", "/pieces/p1",
+		"--bind", "/repo/.git", "/repo/.git",
+		"--bind", "/repo/tmp-cache", "/repo/tmp-cache",
+		"--", "bash", fullHookPath,
+	}, nil, nil)
+
+	err := runner.RunHook("/repo", piece.HookBeforePieceMerge, piece.HookContext{
+		PieceName:    "p1",
+		WorktreePath: "/pieces/p1",
+		RepoRoot:     "/repo",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if mockExec.WasCalled("bash", fullHookPath) {
+		t.Error("expected hook to run inside bwrap, not plain bash")
+	}
+}
+
+func TestHookRunner_RunHook_InvalidSandbox(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	deps := core.Deps{FS: fs, Output: out, Exec: mockExec}
+	runner := piece.NewHookRunner(deps)
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle/hooks", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"hooks":{"sandbox":"chroot"}}`), 0644)
+	_ = fs.WriteFile(filepath.Join("repo", piece.HooksDir, piece.HookOnPieceCreate), []byte("#!/bin/bash\n"), 0755)
+
+	err := runner.RunHook("/repo", piece.HookOnPieceCreate, piece.HookContext{PieceName: "p1"})
+	if err == nil {
+		t.Fatal("expected error for invalid sandbox runner")
+	}
+	if len(mockExec.GetCalls()) > 0 {
+		t.Error("expected hook not to be executed")
+	}
+}
+
+func TestHookRunner_RunHook_UnreadableConfig(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	runner := piece.NewHookRunner(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle/hooks", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"hooks":{"sandbox":"bwrap"},}`), 0644)
+	_ = fs.WriteFile(filepath.Join("repo", piece.HooksDir, piece.HookOnPieceCreate), []byte("#!/bin/bash\n"), 0755)
+
+	err := runner.RunHook("/repo", piece.HookOnPieceCreate, piece.HookContext{PieceName: "p1"})
+	if err == nil || !strings.Contains(err.Error(), "sandboxed") {
+		t.Fatalf("expected a config that can't be parsed to fail the hook, got %v", err)
+	}
+	if len(mockExec.GetCalls()) > 0 {
+		t.Error("expected hook not to be executed")
+	}
+}
+
+func TestHookRunner_RunCommand_UsesConfiguredSandbox(t *testing.T) {
+	t.Setenv("HOME", "/home/user")
+
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	runner := piece.NewHookRunner(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1","hooks":{"sandbox":"bwrap"}}`), 0644)
+	mockExec.AddResponse("bwrap", []string{
+		"--dev-bind", "/", "/",
+		"--ro-bind", "/home/user", "/home/user",
+		"--bind", "/pieces/p1", "/pieces/p1",
+		"--bind", "/repo/.git", "/repo/.git",
+		"--", "sh", "-c", "make deps",
+	}, []byte("deps ready\n"), nil)
+
+	output, err := runner.RunCommand("/pieces/p1", "make deps", piece.HookContext{
+		PieceName:    "p1",
+		WorktreePath: "/pieces/p1",
+		RepoRoot:     "/repo",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if output != "deps ready" {
+		t.Errorf("expected the command output, got %q", output)
+	}
+	if mockExec.WasCalled("sh", "-c", "make deps") {
+		t.Error("expected the command to run inside bwrap, not plain sh")
+	}
+}
@@ -0,0 +1,46 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// sessionPrefix prefixes the tmux session name of every piece
+const sessionPrefix = "mp-piece-"
+
+// KillStaleSessions kills piece tmux sessions whose worktree no longer exists.
+// Returns the killed session names.
+func (h *Handler) KillStaleSessions() ([]string, error) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+
+	sessions, err := h.tmux.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var killed []string
+	for _, session := range sessions {
+		pieceName, ok := strings.CutPrefix(session, sessionPrefix)
+		if !ok || pieceName == "" {
+			continue
+		}
+		if _, err := h.deps.FS.Stat(filepath.Join(piecesDir, pieceName)); err == nil {
+			continue
+		}
+		if err := h.tmux.KillSession(session); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to kill stale session %s: %v", session, err),
+			})
+			continue
+		}
+		killed = append(killed, session)
+	}
+	return killed, nil
+}
@@ -0,0 +1,87 @@
+package piece
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// legacySourceSymlink is the symlink to the monkeypuzzle source directory that
+// pieces carried before the directory was recorded in the piece metadata
+const legacySourceSymlink = ".monkeypuzzle-source"
+
+// SourceDir returns the monkeypuzzle source directory the piece at worktreePath
+// was created from, or "" if none was recorded. A legacy .monkeypuzzle-source
+// symlink is migrated into the piece metadata first.
+func (h *Handler) SourceDir(worktreePath string) (string, error) {
+	if _, err := h.MigrateSourceSymlink(worktreePath); err != nil {
+		return "", err
+	}
+
+	metadata, err := OpenMetadataStore(h.deps, worktreePath).ReadPieceMetadata()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return metadata.SourceDir, nil
+}
+
+// resolveSourceDir returns the piece's source directory, warning and returning ""
+// if it can't be resolved
+func (h *Handler) resolveSourceDir(worktreePath string) string {
+	dir, err := h.SourceDir(worktreePath)
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to resolve source directory: %v", err),
+		})
+	}
+	return dir
+}
+
+// MigrateSourceSymlink records the target of the piece's legacy
+// .monkeypuzzle-source symlink as its source directory, unless one is already
+// recorded, and removes the symlink. It reports whether a symlink was migrated.
+func (h *Handler) MigrateSourceSymlink(worktreePath string) (bool, error) {
+	linkPath := filepath.Join(worktreePath, legacySourceSymlink)
+	target, err := h.deps.FS.Readlink(linkPath)
+	if err != nil {
+		// No symlink (or not a symlink): nothing to migrate
+		return false, nil
+	}
+
+	store := OpenMetadataStore(h.deps, worktreePath)
+	if metadata, err := store.ReadPieceMetadata(); err != nil || metadata.SourceDir == "" {
+		if err := h.setSourceDir(worktreePath, target); err != nil {
+			return false, err
+		}
+	}
+
+	if err := h.deps.FS.Remove(linkPath); err != nil {
+		return false, fmt.Errorf("failed to remove %s: %w", linkPath, err)
+	}
+	return true, nil
+}
+
+// setSourceDir records dir as the piece's source directory, keeping its other settings
+func (h *Handler) setSourceDir(worktreePath, dir string) error {
+	return h.updatePieceMetadata(worktreePath, func(m *PieceMetadata) { m.SourceDir = dir })
+}
+
+// migrateSourceSymlinks runs MigrateSourceSymlink for each piece, reporting
+// failures as warnings
+func (h *Handler) migrateSourceSymlinks(pieces []pieceWorktree) {
+	for _, p := range pieces {
+		if _, err := h.MigrateSourceSymlink(p.path); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to migrate source symlink of %s: %v", p.name, err),
+			})
+		}
+	}
+}
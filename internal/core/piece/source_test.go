@@ -0,0 +1,74 @@
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_SourceDir_MigratesSymlink(t *testing.T) {
+	fs, _, _, handler := setupMockPiece(t, "main")
+	_ = fs.MkdirAll("/pieces/piece-1", 0755)
+	_ = fs.Symlink("/src/monkeypuzzle", "/pieces/piece-1/.monkeypuzzle-source")
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WritePieceMetadata(piece.PieceMetadata{BaseBranch: "release/1.2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := handler.SourceDir("/pieces/piece-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dir != "/src/monkeypuzzle" {
+		t.Errorf("expected source dir from symlink, got %q", dir)
+	}
+	if _, err := fs.Stat("/pieces/piece-1/.monkeypuzzle-source"); err == nil {
+		t.Error("expected legacy symlink to be removed")
+	}
+
+	metadata, err := store.ReadPieceMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.SourceDir != "/src/monkeypuzzle" || metadata.BaseBranch != "release/1.2" {
+		t.Errorf("expected source dir recorded alongside base branch, got %+v", metadata)
+	}
+
+	migrated, err := handler.MigrateSourceSymlink("/pieces/piece-1")
+	if err != nil || migrated {
+		t.Errorf("expected nothing left to migrate, got %v, %v", migrated, err)
+	}
+}
+
+func TestHandler_SourceDir_KeepsRecordedDir(t *testing.T) {
+	fs, _, _, handler := setupMockPiece(t, "main")
+	_ = fs.MkdirAll("/pieces/piece-1", 0755)
+	_ = fs.Symlink("/old/monkeypuzzle", "/pieces/piece-1/.monkeypuzzle-source")
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WritePieceMetadata(piece.PieceMetadata{SourceDir: "/src/monkeypuzzle"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := handler.SourceDir("/pieces/piece-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dir != "/src/monkeypuzzle" {
+		t.Errorf("expected recorded source dir to win over the symlink, got %q", dir)
+	}
+	if _, err := fs.Stat("/pieces/piece-1/.monkeypuzzle-source"); err == nil {
+		t.Error("expected legacy symlink to be removed")
+	}
+}
+
+func TestHandler_SourceDir_NoneRecorded(t *testing.T) {
+	_, _, _, handler := setupMockPiece(t, "main")
+
+	dir, err := handler.SourceDir("/pieces/piece-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dir != "" {
+		t.Errorf("expected no source dir, got %q", dir)
+	}
+}
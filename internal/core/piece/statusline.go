@@ -0,0 +1,137 @@
+package piece
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const statuslineFilename = "statusline.json"
+
+// Default cache lifetimes of the status line. Local git state is cheap to
+// refresh; the PR state costs a gh call and is refreshed in the background.
+const (
+	DefaultStatuslineMaxAge   = 5 * time.Second
+	DefaultStatuslinePRMaxAge = 2 * time.Minute
+)
+
+// Statusline is the compact piece summary shown by `mp statusline`, cached in
+// the piece's metadata store
+type Statusline struct {
+	PieceName string `json:"piece_name"`
+	IssueID   string `json:"issue_id,omitempty"`
+	Ahead     int    `json:"ahead,omitempty"`
+	Behind    int    `json:"behind,omitempty"`
+	Dirty     bool   `json:"dirty,omitempty"`
+	PRNumber  int    `json:"pr_number,omitempty"`
+	// PRState is gh's state of the PR (OPEN, CLOSED, MERGED), if checked
+	PRState     string    `json:"pr_state,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PRCheckedAt time.Time `json:"pr_checked_at,omitzero"`
+	// PRStale is set when the PR state is older than the allowed age and
+	// should be refreshed
+	PRStale bool `json:"-"`
+}
+
+// String formats the status line, e.g. "add-login #add-login ↑3↓1* PR#42 open"
+func (s Statusline) String() string {
+	parts := []string{s.PieceName}
+	if s.IssueID != "" {
+		parts = append(parts, "#"+s.IssueID)
+	}
+
+	var sync string
+	if s.Ahead > 0 {
+		sync += fmt.Sprintf("↑%d", s.Ahead)
+	}
+	if s.Behind > 0 {
+		sync += fmt.Sprintf("↓%d", s.Behind)
+	}
+	if s.Dirty {
+		sync += "*"
+	}
+	if sync != "" {
+		parts = append(parts, sync)
+	}
+
+	if s.PRNumber != 0 {
+		pr := fmt.Sprintf("PR#%d", s.PRNumber)
+		if s.PRState != "" {
+			pr += " " + strings.ToLower(s.PRState)
+		}
+		parts = append(parts, pr)
+	}
+	return strings.Join(parts, " ")
+}
+
+// StatuslineOptions configures Statusline
+type StatuslineOptions struct {
+	// MainBranch is the base for pieces without a recorded base (default: main)
+	MainBranch string
+	// MaxAge is how long the cached local state is used (default: DefaultStatuslineMaxAge)
+	MaxAge time.Duration
+	// PRMaxAge is how long the cached PR state is used (default: DefaultStatuslinePRMaxAge)
+	PRMaxAge time.Duration
+	// Refresh recomputes everything, including the PR state
+	Refresh bool
+}
+
+// Statusline returns the status line of the piece containing workDir, or nil
+// outside a piece. It serves the cache while it is fresh and otherwise reads
+// local git state; the PR state is only looked up with Refresh, and marked
+// PRStale once it is older than PRMaxAge.
+func (h *Handler) Statusline(workDir string, opts StatuslineOptions) (*Statusline, error) {
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultStatuslineMaxAge
+	}
+	if opts.PRMaxAge <= 0 {
+		opts.PRMaxAge = DefaultStatuslinePRMaxAge
+	}
+
+	status, err := h.Status(workDir)
+	if err != nil || !status.InPiece {
+		return nil, err
+	}
+
+	store := OpenMetadataStore(h.deps, status.WorktreePath)
+	var cached Statusline
+	hasCache := store.read(statuslineFilename, &cached) == nil && cached.PieceName == status.PieceName
+
+	now := time.Now()
+	line := cached
+	if opts.Refresh || !hasCache || now.Sub(cached.UpdatedAt) >= opts.MaxAge {
+		details, err := h.Details(workDir, DetailsOptions{MainBranch: opts.MainBranch, Fast: true})
+		if err != nil {
+			return nil, err
+		}
+		line = Statusline{
+			PieceName: details.PieceName,
+			IssueID:   details.IssueID,
+			Ahead:     details.Ahead,
+			Behind:    details.Behind,
+			Dirty:     details.Dirty,
+			PRNumber:  details.PRNumber,
+			UpdatedAt: now,
+		}
+		// Keep the PR state while it belongs to the same PR
+		if cached.PRNumber == line.PRNumber {
+			line.PRState = cached.PRState
+			line.PRCheckedAt = cached.PRCheckedAt
+		}
+
+		if opts.Refresh && line.PRNumber != 0 {
+			h.useConfiguredRemote(status.WorktreePath)
+			if state, err := h.github.GetPRStatus(status.WorktreePath, line.PRNumber); err == nil {
+				line.PRState = state
+				line.PRCheckedAt = now
+			}
+		}
+
+		if err := store.write(statuslineFilename, line); err != nil {
+			return nil, fmt.Errorf("failed to cache status line: %w", err)
+		}
+	}
+
+	line.PRStale = line.PRNumber != 0 && now.Sub(line.PRCheckedAt) >= opts.PRMaxAge
+	return &line, nil
+}
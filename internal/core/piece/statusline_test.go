@@ -0,0 +1,102 @@
+package piece_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+const statuslineCache = "/repo/.git/worktrees/piece-1/monkeypuzzle/statusline.json"
+
+func TestStatusline_String(t *testing.T) {
+	tests := []struct {
+		line piece.Statusline
+		want string
+	}{
+		{piece.Statusline{PieceName: "p1"}, "p1"},
+		{piece.Statusline{PieceName: "p1", IssueID: "login", Ahead: 3, Behind: 1, Dirty: true}, "p1 #login ↑3↓1*"},
+		{piece.Statusline{PieceName: "p1", PRNumber: 42, PRState: "OPEN"}, "p1 PR#42 open"},
+		{piece.Statusline{PieceName: "p1", Behind: 2, PRNumber: 42}, "p1 ↓2 PR#42"},
+	}
+	for _, tt := range tests {
+		if got := tt.line.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestHandler_Statusline_CachesLocalState(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-list", "--left-right", "--count", "main...piece-1"}, []byte("1\t2\n"), nil)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+
+	line, err := handler.Statusline("/pieces/piece-1", piece.StatuslineOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if line.String() != "piece-1 ↑2↓1" {
+		t.Errorf("unexpected status line %q", line.String())
+	}
+	if _, err := fs.ReadFile(statuslineCache); err != nil {
+		t.Fatalf("expected status line to be cached: %v", err)
+	}
+
+	// Served from the cache without recounting commits
+	mockExec.AddResponse("git", []string{"rev-list", "--left-right", "--count", "main...piece-1"}, []byte("0\t5\n"), nil)
+	line, err = handler.Statusline("/pieces/piece-1", piece.StatuslineOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if line.Ahead != 2 {
+		t.Errorf("expected cached ahead count 2, got %d", line.Ahead)
+	}
+}
+
+func TestHandler_Statusline_RefreshesPRState(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-list", "--left-right", "--count", "main...piece-1"}, []byte("0\t0\n"), nil)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	mockExec.AddResponse("gh", []string{"pr", "view", "42", "--json", "state", "--jq", ".state"}, []byte("MERGED\n"), nil)
+
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/piece-1", "/pieces/piece-1")
+	if err := store.WritePRMetadata(piece.PRMetadata{PRNumber: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	line, err := handler.Statusline("/pieces/piece-1", piece.StatuslineOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if line.PRState != "" || !line.PRStale {
+		t.Errorf("expected unchecked, stale PR state without refresh, got %+v", line)
+	}
+	if mockExec.WasCalled("gh", "pr", "view", "42", "--json", "state", "--jq", ".state") {
+		t.Error("expected no gh call without refresh")
+	}
+
+	line, err = handler.Statusline("/pieces/piece-1", piece.StatuslineOptions{Refresh: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if line.PRState != "MERGED" || line.PRStale {
+		t.Errorf("expected fresh merged PR state, got %+v", line)
+	}
+
+	data, _ := fs.ReadFile(statuslineCache)
+	var cached piece.Statusline
+	if err := json.Unmarshal(data, &cached); err != nil || cached.PRState != "MERGED" {
+		t.Errorf("expected PR state to be cached, got %s", data)
+	}
+}
+
+func TestHandler_Statusline_OutsidePiece(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(".git\n"), nil)
+
+	line, err := handler.Statusline("/repo", piece.StatuslineOptions{})
+	if err != nil || line != nil {
+		t.Fatalf("expected no status line outside a piece, got %+v, %v", line, err)
+	}
+}
@@ -0,0 +1,48 @@
+package piece
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Merge strategies of mp piece merge, set with merge.strategy
+const (
+	// MergeStrategySquash adds the piece to its base as one commit
+	MergeStrategySquash = "squash"
+	// MergeStrategyMerge adds the piece's commits and a merge commit
+	MergeStrategyMerge = "merge"
+	// MergeStrategyRebaseFF rebases the piece onto its base, then
+	// fast-forwards the base to it, for a linear history
+	MergeStrategyRebaseFF = "rebase-ff"
+)
+
+// MergeStrategies lists the valid merge strategies
+var MergeStrategies = []string{MergeStrategySquash, MergeStrategyMerge, MergeStrategyRebaseFF}
+
+// ValidateMergeStrategy checks merge.strategy from the config; empty means
+// the default, squash
+func ValidateMergeStrategy(strategy string) error {
+	if strategy != "" && !slices.Contains(MergeStrategies, strategy) {
+		return fmt.Errorf("merge.strategy must be one of %s, got %q", strings.Join(MergeStrategies, ", "), strategy)
+	}
+	return nil
+}
+
+// MergeStrategy returns the strategy to merge pieces of the repository at
+// repoRoot with: override when set, else merge.strategy, else squash
+func (h *Handler) MergeStrategy(repoRoot, override string) (string, error) {
+	strategy := override
+	if strategy == "" {
+		if cfg, err := ReadConfig(repoRoot, h.deps.FS); err == nil {
+			strategy = cfg.Merge.Strategy
+		}
+	}
+	if err := ValidateMergeStrategy(strategy); err != nil {
+		return "", err
+	}
+	if strategy == "" {
+		strategy = MergeStrategySquash
+	}
+	return strategy, nil
+}
@@ -0,0 +1,96 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_MergePiece_MergeCommit(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	pieceHead := server.Git(worktree, "rev-parse", "HEAD")
+
+	opts := piece.MergeOptions{Strategy: piece.MergeStrategyMerge}
+	if err := newOSHandler().MergePieceWithOptions(worktree, "main", opts); err != nil {
+		t.Fatalf("MergePieceWithOptions failed: %v", err)
+	}
+	if got := server.Git(clone, "log", "-1", "--format=%s", "main"); got != "Merge piece piece-1 into main" {
+		t.Errorf("expected a merge commit on main, got %q", got)
+	}
+	if got := server.Git(clone, "rev-parse", "main^2"); got != pieceHead {
+		t.Errorf("expected the piece head as second parent, got %s, want %s", got, pieceHead)
+	}
+}
+
+func TestIntegration_MergePiece_RebaseFF(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	server.Commit(clone, "main.txt", "main\n", "main moves on")
+	server.Git(worktree, "merge", "--no-edit", "main")
+	server.Commit(worktree, "feature.txt", "feature v2\n", "extend feature")
+
+	opts := piece.MergeOptions{Strategy: piece.MergeStrategyRebaseFF}
+	if err := newOSHandler().MergePieceWithOptions(worktree, "main", opts); err != nil {
+		t.Fatalf("MergePieceWithOptions failed: %v", err)
+	}
+	if got := server.Git(clone, "rev-parse", "main"); got != server.Git(worktree, "rev-parse", "piece-1") {
+		t.Errorf("expected main fast-forwarded to the rebased piece, got %s", got)
+	}
+	if got := server.Git(clone, "rev-list", "--merges", "main"); got != "" {
+		t.Errorf("expected a linear history, got merge commits %s", got)
+	}
+	if got := server.Git(clone, "log", "-3", "--format=%s", "main"); got != "extend feature\nadd feature\nmain moves on" {
+		t.Errorf("expected the piece's commits on top of main, got:\n%s", got)
+	}
+}
+
+func TestIntegration_MergePiece_RebaseFFWithMainAhead(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	server.Git(worktree, "push", "--quiet", "-u", "origin", "piece-1")
+	server.Commit(clone, "main.txt", "main\n", "main moves on")
+
+	out := adapters.NewBufferOutput()
+	handler := piece.NewHandler(core.Deps{FS: adapters.NewOSFS(""), Output: out, Exec: adapters.NewOSExec()})
+	opts := piece.MergeOptions{Strategy: piece.MergeStrategyRebaseFF}
+	if err := handler.MergePieceWithOptions(worktree, "main", opts); err != nil {
+		t.Fatalf("expected the rebase to bring in main without mp piece update, got %v", err)
+	}
+	if got := server.Git(clone, "log", "-2", "--format=%s", "main"); got != "add feature\nmain moves on" {
+		t.Errorf("expected the piece's commit on top of main, got:\n%s", got)
+	}
+	warned := false
+	for _, msg := range out.Messages {
+		warned = warned || (msg.Type == core.MsgWarning && strings.Contains(msg.Content, "--force-with-lease"))
+	}
+	if !warned {
+		t.Errorf("expected a force-push warning for the rewritten remote branch, got %+v", out.Messages)
+	}
+}
+
+func TestIntegration_MergePiece_RebaseFFRefusesDirtyPiece(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	mainHead := server.Git(clone, "rev-parse", "main")
+	if err := os.WriteFile(filepath.Join(worktree, "feature.txt"), []byte("unsaved\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := piece.MergeOptions{Strategy: piece.MergeStrategyRebaseFF}
+	err := newOSHandler().MergePieceWithOptions(worktree, "main", opts)
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeDirtyWorktree || !strings.Contains(err.Error(), "cannot rebase") {
+		t.Fatalf("expected dirty_worktree error, got %v", err)
+	}
+	if got := server.Git(clone, "rev-parse", "main"); got != mainHead {
+		t.Errorf("expected main to stay at %s, got %s", mainHead, got)
+	}
+}
@@ -0,0 +1,41 @@
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_MergeStrategy(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()})
+
+	if got, err := handler.MergeStrategy("/repo", ""); err != nil || got != piece.MergeStrategySquash {
+		t.Errorf("expected squash without config, got %q, %v", got, err)
+	}
+
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"merge": {"strategy": "rebase-ff"}}`), 0644)
+	if got, err := handler.MergeStrategy("/repo", ""); err != nil || got != piece.MergeStrategyRebaseFF {
+		t.Errorf("expected rebase-ff from the config, got %q, %v", got, err)
+	}
+	if got, err := handler.MergeStrategy("/repo", piece.MergeStrategyMerge); err != nil || got != piece.MergeStrategyMerge {
+		t.Errorf("expected the override to win, got %q, %v", got, err)
+	}
+	if _, err := handler.MergeStrategy("/repo", "octopus"); err == nil {
+		t.Error("expected an unknown strategy to be rejected")
+	}
+}
+
+func TestHandler_MergePiece_SquashCheckpointsNeedsSquash(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+
+	opts := piece.MergeOptions{Strategy: piece.MergeStrategyMerge, SquashCheckpoints: true}
+	if err := handler.MergePieceWithOptions("/pieces/piece-1", "main", opts); err == nil {
+		t.Fatal("expected --squash-checkpoints to be refused with the merge strategy")
+	}
+	if mockExec.WasCalled("git", "checkout", "main") {
+		t.Error("expected nothing to be merged")
+	}
+}
@@ -0,0 +1,305 @@
+package piece
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+)
+
+// TemplatesDirName is the directory under .monkeypuzzle holding piece templates,
+// one <name>.json file per template
+const TemplatesDirName = "piece-templates"
+
+var templateNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Template predefines a kind of work, such as a bugfix or a spike: how its
+// branch is named, how its worktree is prepared, and how it is checked
+type Template struct {
+	// Name is the template's file name without .json
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// BranchPrefix is prepended to the piece name to name its branch (e.g. "fix/")
+	BranchPrefix string `json:"branch_prefix,omitempty"`
+	// Bootstrap commands run in a new piece's worktree, before the
+	// on-piece-create hook; the piece is rolled back if one fails
+	Bootstrap []string `json:"bootstrap,omitempty"`
+	// Verify commands run in the worktree by `mp piece verify` and before
+	// `mp piece merge`, which is refused if one fails
+	Verify []string   `json:"verify,omitempty"`
+	Tmux   TmuxLayout `json:"tmux,omitempty"`
+}
+
+// TmuxLayout lists the windows opened in a piece's tmux session besides the
+// first one
+type TmuxLayout struct {
+	Windows []TmuxWindow `json:"windows,omitempty"`
+}
+
+// TmuxWindow is a tmux window started in the piece's worktree, running
+// Command if set
+type TmuxWindow struct {
+	Name    string `json:"name"`
+	Command string `json:"command,omitempty"`
+}
+
+// TemplatesDir returns the directory holding the piece templates of the
+// repository at repoRoot
+func TemplatesDir(repoRoot string) string {
+	return filepath.Join(repoRoot, initcmd.DirName, TemplatesDirName)
+}
+
+// ListTemplates returns the piece templates of the repository at repoRoot,
+// sorted by name. A missing templates directory means no templates.
+func ListTemplates(fs core.FS, repoRoot string) ([]Template, error) {
+	entries, err := fs.ReadDir(TemplatesDir(repoRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read piece templates: %w", err)
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		tmpl, err := LoadTemplate(fs, repoRoot, name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// LoadTemplate reads and validates the piece template called name
+func LoadTemplate(fs core.FS, repoRoot, name string) (Template, error) {
+	if !templateNameRegex.MatchString(name) {
+		return Template{}, fmt.Errorf("invalid template name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+
+	path := filepath.Join(TemplatesDir(repoRoot), name+".json")
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Template{}, unknownTemplateError(fs, repoRoot, name)
+		}
+		return Template{}, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	var tmpl Template
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&tmpl); err != nil {
+		return Template{}, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	tmpl.Name = name
+
+	if err := tmpl.validate(); err != nil {
+		return Template{}, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// validate checks the fields that would otherwise fail halfway through
+// creating a piece
+func (t Template) validate() error {
+	if prefix := t.BranchPrefix; prefix != "" {
+		if strings.ContainsAny(prefix, " ~^:?*[\\") || strings.Contains(prefix, "..") ||
+			strings.HasPrefix(prefix, "-") || strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("branch_prefix %q is not usable in a branch name", prefix)
+		}
+	}
+	for _, cmd := range append(append([]string{}, t.Bootstrap...), t.Verify...) {
+		if strings.TrimSpace(cmd) == "" {
+			return fmt.Errorf("commands must not be empty")
+		}
+	}
+	seen := make(map[string]bool)
+	for _, w := range t.Tmux.Windows {
+		if w.Name == "" || strings.ContainsAny(w.Name, ":.") {
+			return fmt.Errorf("tmux window name %q must be non-empty and contain no ':' or '.'", w.Name)
+		}
+		if seen[w.Name] {
+			return fmt.Errorf("duplicate tmux window %q", w.Name)
+		}
+		seen[w.Name] = true
+	}
+	return nil
+}
+
+// unknownTemplateError names the templates that do exist
+func unknownTemplateError(fs core.FS, repoRoot, name string) error {
+	var names []string
+	if entries, err := fs.ReadDir(TemplatesDir(repoRoot)); err == nil {
+		for _, entry := range entries {
+			if n, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+				names = append(names, n)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("unknown template %q: no templates in %s", name, TemplatesDir(repoRoot))
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// runTemplateCommand runs cmd with sh in the piece's worktree as hooks run:
+// with the same MP_* environment, in the configured sandbox, and streaming
+// its output
+func (h *Handler) runTemplateCommand(ctx HookContext, cmd string) (string, error) {
+	return h.hooks.RunCommand(ctx.WorktreePath, cmd, ctx)
+}
+
+// bootstrapPiece runs the template's bootstrap commands in order, stopping at
+// the first failure
+func (h *Handler) bootstrapPiece(tmpl Template, ctx HookContext) error {
+	for _, cmd := range tmpl.Bootstrap {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgInfo,
+			Content: fmt.Sprintf("Bootstrap: %s", cmd),
+		})
+		if output, err := h.runTemplateCommand(ctx, cmd); err != nil {
+			if output != "" {
+				return fmt.Errorf("bootstrap command %q failed: %w\n%s", cmd, err, output)
+			}
+			return fmt.Errorf("bootstrap command %q failed: %w", cmd, err)
+		}
+	}
+	return nil
+}
+
+// openTemplateWindows opens the template's tmux windows in the piece's
+// session, reporting failures as warnings
+func (h *Handler) openTemplateWindows(tmpl Template, sessionName, worktreePath string) {
+	for _, w := range tmpl.Tmux.Windows {
+		err := h.tmux.NewWindow(sessionName, w.Name, worktreePath)
+		if err == nil && w.Command != "" {
+			err = h.tmux.SendKeys(sessionName+":"+w.Name, w.Command)
+		}
+		if err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to open tmux window %s: %v", w.Name, err),
+			})
+		}
+	}
+}
+
+// VerifyResult is the outcome of one verify command
+type VerifyResult struct {
+	Command string `json:"command"`
+	Passed  bool   `json:"passed"`
+	Output  string `json:"output,omitempty"`
+}
+
+// VerifyReport is the outcome of running a piece's verify commands
+type VerifyReport struct {
+	Piece    string `json:"piece"`
+	Template string `json:"template,omitempty"`
+	// Passed is true when every command passed, including when there are none
+	Passed  bool           `json:"passed"`
+	Results []VerifyResult `json:"results"`
+}
+
+// VerifyPiece runs the verify commands of the template the piece was created
+// from, all of them even after a failure, and with pieces.issue_prefix set
+// checks the piece's commit messages start with its issue short ID. Pieces
+// without a template, or whose template has no verify commands, pass.
+func (h *Handler) VerifyPiece(workDir string) (VerifyReport, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return VerifyReport{}, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return VerifyReport{}, core.NewNotInPieceError()
+	}
+
+	report, err := h.verify(status)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	msg := core.Message{Type: core.MsgSuccess, Content: fmt.Sprintf("Verified %s", status.PieceName), Data: report}
+	if !report.Passed {
+		msg.Type = core.MsgWarning
+		msg.Content = fmt.Sprintf("Verification of %s failed: %s", status.PieceName, strings.Join(report.failed(), ", "))
+	}
+	h.deps.Output.Write(msg)
+	return report, nil
+}
+
+// verify runs the verify commands of the piece's template and checks the
+// issue prefix of its commits
+func (h *Handler) verify(status PieceStatus) (VerifyReport, error) {
+	report := VerifyReport{Piece: status.PieceName, Passed: true, Results: []VerifyResult{}}
+	prefixed, ok, err := h.verifyIssuePrefix(status)
+	if err != nil {
+		return report, err
+	}
+	if ok {
+		report.Results = append(report.Results, prefixed)
+		report.Passed = prefixed.Passed
+	}
+
+	tmpl, ok, err := h.pieceTemplate(status)
+	if err != nil || !ok {
+		return report, err
+	}
+	report.Template = tmpl.Name
+
+	ctx := HookContext{
+		PieceName:    status.PieceName,
+		WorktreePath: status.WorktreePath,
+		RepoRoot:     status.RepoRoot,
+		SessionName:  SessionName(status.PieceName),
+		SourceDir:    h.resolveSourceDir(status.WorktreePath),
+	}
+	for _, cmd := range tmpl.Verify {
+		output, err := h.runTemplateCommand(ctx, cmd)
+		result := VerifyResult{Command: cmd, Passed: err == nil, Output: output}
+		report.Results = append(report.Results, result)
+		if !result.Passed {
+			report.Passed = false
+		}
+	}
+	return report, nil
+}
+
+// failed returns the commands that failed
+func (r VerifyReport) failed() []string {
+	var commands []string
+	for _, result := range r.Results {
+		if !result.Passed {
+			commands = append(commands, result.Command)
+		}
+	}
+	return commands
+}
+
+// pieceTemplate loads the template recorded for the piece, reporting false
+// when none was recorded
+func (h *Handler) pieceTemplate(status PieceStatus) (Template, bool, error) {
+	metadata, err := OpenMetadataStore(h.deps, status.WorktreePath).ReadPieceMetadata()
+	if err != nil || metadata.Template == "" {
+		return Template{}, false, nil
+	}
+	tmpl, err := LoadTemplate(h.deps.FS, status.RepoRoot, metadata.Template)
+	if err != nil {
+		return Template{}, false, err
+	}
+	return tmpl, true, nil
+}
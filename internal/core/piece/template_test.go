@@ -0,0 +1,220 @@
+package piece_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+const bugfixTemplate = `{
+  "description": "Fix a reported bug",
+  "branch_prefix": "fix/",
+  "bootstrap": ["make deps"],
+  "verify": ["make test", "make lint"],
+  "tmux": {"windows": [{"name": "server", "command": "make run"}]}
+}`
+
+func writeTemplate(t *testing.T, fs *adapters.MemoryFS, name, content string) {
+	t.Helper()
+	if err := fs.MkdirAll(piece.TemplatesDir("/repo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile(piece.TemplatesDir("/repo")+"/"+name+".json", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	writeTemplate(t, fs, "bugfix", bugfixTemplate)
+
+	tmpl, err := piece.LoadTemplate(fs, "/repo", "bugfix")
+	if err != nil {
+		t.Fatalf("LoadTemplate failed: %v", err)
+	}
+	if tmpl.Name != "bugfix" || tmpl.BranchPrefix != "fix/" || len(tmpl.Verify) != 2 || tmpl.Tmux.Windows[0].Command != "make run" {
+		t.Errorf("unexpected template %+v", tmpl)
+	}
+
+	templates, err := piece.ListTemplates(fs, "/repo")
+	if err != nil || len(templates) != 1 || templates[0].Name != "bugfix" {
+		t.Errorf("expected the bugfix template to be listed, got %+v (%v)", templates, err)
+	}
+}
+
+func TestLoadTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown", "", `unknown template "unknown" (available: bad-prefix, bad-window, bugfix, typo)`},
+		{"typo", `{"branch-prefix": "fix/"}`, `unknown field "branch-prefix"`},
+		{"bad-prefix", `{"branch_prefix": "fix me/"}`, "is not usable in a branch name"},
+		{"bad-window", `{"tmux": {"windows": [{"name": "a:b"}]}}`, "tmux window name"},
+		{"Upper", "", "invalid template name"},
+	}
+
+	fs := adapters.NewMemoryFS()
+	writeTemplate(t, fs, "bugfix", bugfixTemplate)
+	for _, tt := range tests {
+		if tt.content != "" {
+			writeTemplate(t, fs, tt.name, tt.content)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := piece.LoadTemplate(fs, "/repo", tt.name)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestListTemplates_NoDirectory(t *testing.T) {
+	templates, err := piece.ListTemplates(adapters.NewMemoryFS(), "/repo")
+	if err != nil || templates != nil {
+		t.Errorf("expected no templates, got %+v (%v)", templates, err)
+	}
+}
+
+// setupTemplatePiece mocks creating piece p1 from the bugfix template up to
+// its bootstrap command
+func setupTemplatePiece(t *testing.T) (*adapters.MemoryFS, *adapters.BufferOutput, *adapters.MockExec, *piece.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+	writeTemplate(t, fs, "bugfix", bugfixTemplate)
+
+	worktreePath := "/test-data/monkeypuzzle/pieces/p1"
+	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte("/repo\n"), nil)
+	mockExec.AddResponse("git", []string{"worktree", "add", "-b", "fix/p1", worktreePath}, nil, nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockExec.AddResponse("tmux", []string{"new-session", "-d", "-s", "mp-piece-p1", "-c", worktreePath}, nil, nil)
+	mockExec.AddResponse("tmux", []string{"new-window", "-d", "-t", "mp-piece-p1", "-n", "server", "-c", worktreePath}, nil, nil)
+	mockExec.AddResponse("tmux", []string{"send-keys", "-t", "mp-piece-p1:server", "make run", "Enter"}, nil, nil)
+	return fs, out, mockExec, handler
+}
+
+func TestHandler_CreatePieceWithOptions_AppliesTemplate(t *testing.T) {
+	fs, _, mockExec, handler := setupTemplatePiece(t)
+	mockExec.AddResponse("sh", []string{"-c", "make deps"}, nil, nil)
+
+	info, err := handler.CreatePieceWithOptions(piece.CreateOptions{Name: "p1", Template: "bugfix"})
+	if err != nil {
+		t.Fatalf("CreatePieceWithOptions failed: %v", err)
+	}
+	if info.Template != "bugfix" {
+		t.Errorf("expected the template in the piece info, got %+v", info)
+	}
+	if !mockExec.WasCalled("sh", "-c", "make deps") {
+		t.Error("expected the bootstrap command to run")
+	}
+	if !mockExec.WasCalled("tmux", "send-keys", "-t", "mp-piece-p1:server", "make run", "Enter") {
+		t.Error("expected the server window to run its command")
+	}
+
+	metadata, err := piece.NewMetadataStore(fs, "/repo/.git/worktrees/p1", info.WorktreePath).ReadPieceMetadata()
+	if err != nil || metadata.Template != "bugfix" {
+		t.Errorf("expected the template to be recorded, got %+v (%v)", metadata, err)
+	}
+}
+
+func TestHandler_CreatePieceWithOptions_BootstrapFailureRollsBack(t *testing.T) {
+	_, _, mockExec, handler := setupTemplatePiece(t)
+	worktreePath := "/test-data/monkeypuzzle/pieces/p1"
+	mockExec.AddResponse("sh", []string{"-c", "make deps"}, []byte("no rule to make target"), fmt.Errorf("exit status 2"))
+	mockExec.AddResponse("tmux", []string{"kill-session", "-t", "mp-piece-p1"}, nil, nil)
+	mockExec.AddResponse("git", []string{"worktree", "remove", worktreePath}, nil, nil)
+
+	_, err := handler.CreatePieceWithOptions(piece.CreateOptions{Name: "p1", Template: "bugfix"})
+	if err == nil || !strings.Contains(err.Error(), `bootstrap command "make deps" failed`) {
+		t.Fatalf("expected bootstrap failure, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "no rule to make target") {
+		t.Errorf("expected the command output in the error, got %v", err)
+	}
+	if !mockExec.WasCalled("git", "worktree", "remove", worktreePath) {
+		t.Error("expected the worktree to be removed")
+	}
+	if mockExec.WasCalled("tmux", "new-window", "-d", "-t", "mp-piece-p1", "-n", "server", "-c", worktreePath) {
+		t.Error("expected no tmux windows before bootstrap succeeds")
+	}
+}
+
+func TestHandler_CreatePieceWithOptions_UnknownTemplate(t *testing.T) {
+	_, _, mockExec, handler := setupTemplatePiece(t)
+
+	_, err := handler.CreatePieceWithOptions(piece.CreateOptions{Name: "p1", Template: "spike"})
+	if err == nil || !strings.Contains(err.Error(), `unknown template "spike" (available: bugfix)`) {
+		t.Fatalf("expected unknown template error, got %v", err)
+	}
+	for _, call := range mockExec.GetCalls() {
+		if call.Name == "git" && call.Args[0] == "worktree" {
+			t.Errorf("expected no worktree to be created, got %v", call.Args)
+		}
+	}
+}
+
+// setupVerifyPiece sets up piece-1 as created from the bugfix template, with
+// make test passing and make lint failing
+func setupVerifyPiece(t *testing.T) (*adapters.BufferOutput, *adapters.MockExec, *piece.Handler) {
+	t.Helper()
+	fs, out, mockExec, handler := setupMockPiece(t, "main")
+	writeTemplate(t, fs, "bugfix", bugfixTemplate)
+	store := piece.OpenMetadataStore(core.Deps{FS: fs, Exec: mockExec}, "/pieces/piece-1")
+	if err := store.WritePieceMetadata(piece.PieceMetadata{Template: "bugfix"}); err != nil {
+		t.Fatal(err)
+	}
+	mockExec.AddResponse("sh", []string{"-c", "make test"}, []byte("ok\n"), nil)
+	mockExec.AddResponse("sh", []string{"-c", "make lint"}, []byte("main.go:3: unused import\n"), fmt.Errorf("exit status 1"))
+	return out, mockExec, handler
+}
+
+func TestHandler_VerifyPiece(t *testing.T) {
+	out, _, handler := setupVerifyPiece(t)
+
+	report, err := handler.VerifyPiece("/pieces/piece-1")
+	if err != nil {
+		t.Fatalf("VerifyPiece failed: %v", err)
+	}
+	if report.Passed || report.Template != "bugfix" || len(report.Results) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if !report.Results[0].Passed || report.Results[1].Passed || report.Results[1].Output != "main.go:3: unused import" {
+		t.Errorf("unexpected results %+v", report.Results)
+	}
+	if !out.HasWarning() {
+		t.Error("expected a warning naming the failed command")
+	}
+}
+
+func TestHandler_VerifyPiece_NoTemplate(t *testing.T) {
+	_, _, _, handler := setupMockPiece(t, "main")
+
+	report, err := handler.VerifyPiece("/pieces/piece-1")
+	if err != nil || !report.Passed || len(report.Results) != 0 {
+		t.Errorf("expected a piece without template to pass, got %+v (%v)", report, err)
+	}
+}
+
+func TestHandler_MergePiece_RefusesFailedVerify(t *testing.T) {
+	_, mockExec, handler := setupVerifyPiece(t)
+
+	err := handler.MergePiece("/pieces/piece-1", "main")
+	if err == nil || !strings.Contains(err.Error(), "verify commands of template bugfix failed: make lint") {
+		t.Fatalf("expected verify failure, got %v", err)
+	}
+	if mockExec.WasCalled("git", "checkout", "main") {
+		t.Error("expected no checkout after a failed verify")
+	}
+}
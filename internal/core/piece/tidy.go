@@ -0,0 +1,269 @@
+package piece
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// tidyTodoFilename is the prepared rebase todo list, in the piece's metadata
+const tidyTodoFilename = "tidy-todo"
+
+// Actions of tidy steps, as git rebase spells them
+const (
+	// TidyPick keeps a commit as it is
+	TidyPick = "pick"
+	// TidyFixup folds a commit into the one before, keeping that message
+	TidyFixup = "fixup"
+	// TidyFixupKeep folds a commit into the one before, keeping its own message
+	TidyFixupKeep = "fixup -C"
+	// TidyExec runs a command, here to give the commit a message
+	TidyExec = "exec"
+)
+
+// TidyOptions configures Tidy
+type TidyOptions struct {
+	// MainBranch is the base branch of pieces without a recorded base
+	MainBranch string
+	// Message is the commit message when every commit is a checkpoint
+	// (default: "feat: <piece>")
+	Message string
+	// DryRun plans the new history without rewriting the branch
+	DryRun bool
+}
+
+// TidyStep is a line of the rebase todo list
+type TidyStep struct {
+	Action  string `json:"action"`
+	Commit  string `json:"commit,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	// Exec is the command of an exec step
+	Exec string `json:"exec,omitempty"`
+}
+
+// TidyResult describes the tidied history of a piece
+type TidyResult struct {
+	Piece string `json:"piece"`
+	// Base is the commit the piece branched off, which the rebase starts from
+	Base        string     `json:"base"`
+	Checkpoints int        `json:"checkpoints"`
+	Steps       []TidyStep `json:"steps"`
+	// Before is the head of the piece branch before tidying; git reset
+	// --hard to it undoes the tidy
+	Before    string `json:"before"`
+	After     string `json:"after,omitempty"`
+	Rewritten bool   `json:"rewritten"`
+	DryRun    bool   `json:"dry_run,omitempty"`
+}
+
+// TidyRebase is an interactive rebase of the piece with a prepared todo
+// list, for the caller to run on the terminal
+type TidyRebase struct {
+	Dir  string
+	Args []string
+	// Env is added to the environment; it makes the editor start from the
+	// prepared todo list
+	Env []string
+}
+
+// Tidy rewrites the piece branch so no checkpoint commits remain: each is
+// folded into the commit before it, and checkpoints before the first other
+// commit into that commit, keeping its message. A branch of checkpoints only
+// becomes one commit with opts.Message. The order of changes is kept, so the
+// rebase cannot conflict. The worktree must be clean, and the branch free of
+// merge commits, which the rebase would drop.
+func (h *Handler) Tidy(workDir string, opts TidyOptions) (TidyResult, error) {
+	status, result, err := h.planTidy(workDir, opts)
+	if err != nil {
+		return TidyResult{}, err
+	}
+	result.DryRun = opts.DryRun
+	if result.Checkpoints == 0 || opts.DryRun {
+		content := fmt.Sprintf("%s has no checkpoints to tidy", status.PieceName)
+		if result.Checkpoints > 0 {
+			content = fmt.Sprintf("Would fold %d checkpoint(s) of %s into %d commit(s)", result.Checkpoints, status.PieceName, result.commits())
+		}
+		h.deps.Output.Write(core.Message{Type: core.MsgInfo, Content: content, Data: result})
+		return result, nil
+	}
+
+	worktree := status.WorktreePath
+	todoPath, err := h.writeTidyTodo(worktree, result.Steps)
+	if err != nil {
+		return TidyResult{}, err
+	}
+	defer func() { _ = h.deps.FS.Remove(todoPath) }()
+
+	env := []string{"GIT_SEQUENCE_EDITOR=cp " + adapters.ShellQuote(todoPath), "GIT_EDITOR=true"}
+	if output, err := h.git.Rebase(worktree, env, result.Base); err != nil {
+		abortErr := h.git.RebaseAbort(worktree)
+		return TidyResult{}, errors.Join(fmt.Errorf("tidy failed, branch left at %s: %w\n%s",
+			shortCommit(result.Before), err, strings.TrimSpace(output)), abortErr)
+	}
+	result.Rewritten = true
+	if head, err := h.git.GetBranchCommit(worktree, "HEAD"); err == nil {
+		result.After = head
+	}
+
+	if branch, err := h.git.CurrentBranch(worktree); err == nil {
+		h.warnRewrittenRemote(worktree, branch, result.Before)
+	}
+	h.deps.Output.Write(core.Message{
+		Type: core.MsgSuccess,
+		Content: fmt.Sprintf("Folded %d checkpoint(s) of %s into %d commit(s); undo with git reset --hard %s",
+			result.Checkpoints, status.PieceName, result.commits(), shortCommit(result.Before)),
+		Data: result,
+	})
+	return result, nil
+}
+
+// TidyInteractive prepares the todo list Tidy would run and returns the git
+// rebase -i that opens it in the editor, to adjust before it runs
+func (h *Handler) TidyInteractive(workDir string, opts TidyOptions) (TidyResult, TidyRebase, error) {
+	status, result, err := h.planTidy(workDir, opts)
+	if err != nil {
+		return TidyResult{}, TidyRebase{}, err
+	}
+	todoPath, err := h.writeTidyTodo(status.WorktreePath, result.Steps)
+	if err != nil {
+		return TidyResult{}, TidyRebase{}, err
+	}
+	// git appends the path of its todo list: copy ours over it, then edit it
+	editor := `cat "$0" > "$1" && eval "$(git var GIT_EDITOR)" '"$1"'`
+	return result, TidyRebase{
+		Dir:  status.WorktreePath,
+		Args: []string{"git", "rebase", "-i", "--no-autosquash", result.Base},
+		Env:  []string{"GIT_SEQUENCE_EDITOR=sh -c " + adapters.ShellQuote(editor) + " " + adapters.ShellQuote(todoPath)},
+	}, nil
+}
+
+// planTidy checks the piece can be tidied and plans its new history
+func (h *Handler) planTidy(workDir string, opts TidyOptions) (PieceStatus, TidyResult, error) {
+	status, err := h.Status(workDir)
+	if err != nil {
+		return PieceStatus{}, TidyResult{}, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return PieceStatus{}, TidyResult{}, core.NewNotInPieceError()
+	}
+	worktree := status.WorktreePath
+
+	if err := h.checkNoOperation(worktree); err != nil {
+		return PieceStatus{}, TidyResult{}, err
+	}
+	dirty, err := h.git.HasUncommittedChanges(worktree)
+	if err != nil {
+		return PieceStatus{}, TidyResult{}, err
+	}
+	if dirty {
+		return PieceStatus{}, TidyResult{}, core.NewDirtyWorktreeError(worktree,
+			fmt.Errorf("cannot tidy: the piece worktree %s has uncommitted changes; commit them or run mp piece checkpoint", worktree))
+	}
+
+	mainBranch := opts.MainBranch
+	if mainBranch == "" {
+		mainBranch = "main"
+	}
+	base := h.BaseBranch(worktree, mainBranch)
+	forkPoint, err := h.git.MergeBase(worktree, base, "HEAD")
+	if err != nil {
+		return PieceStatus{}, TidyResult{}, err
+	}
+	if forkPoint == "" {
+		return PieceStatus{}, TidyResult{}, fmt.Errorf("%s shares no history with %s", status.PieceName, base)
+	}
+	merges, err := h.git.MergeCommits(worktree, forkPoint, "HEAD")
+	if err != nil {
+		return PieceStatus{}, TidyResult{}, err
+	}
+	if len(merges) > 0 {
+		return PieceStatus{}, TidyResult{}, fmt.Errorf("cannot tidy: %s has %d merge commit(s), e.g. from mp piece update, which a rebase would drop; rebase it onto %s first",
+			status.PieceName, len(merges), base)
+	}
+	commits, err := h.git.CommitLog(worktree, forkPoint, "HEAD")
+	if err != nil {
+		return PieceStatus{}, TidyResult{}, err
+	}
+	before, err := h.git.GetBranchCommit(worktree, "HEAD")
+	if err != nil {
+		return PieceStatus{}, TidyResult{}, err
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	if message == "" {
+		message = "feat: " + status.PieceName
+	}
+	// The log is newest first; the todo list oldest first
+	slices.Reverse(commits)
+	result := TidyResult{Piece: status.PieceName, Base: forkPoint, Before: before}
+	result.Steps, result.Checkpoints = tidySteps(commits, message)
+	return status, result, nil
+}
+
+// tidySteps plans the rebase todo list folding the checkpoints among commits,
+// oldest first, into the other commits, and counts the checkpoints
+func tidySteps(commits []adapters.Commit, message string) ([]TidyStep, int) {
+	steps := []TidyStep{}
+	checkpoints := 0
+	picked := false
+	for _, c := range commits {
+		step := TidyStep{Action: TidyPick, Commit: c.Hash, Subject: c.Subject}
+		switch {
+		case IsCheckpoint(c.Subject):
+			checkpoints++
+			if len(steps) > 0 {
+				step.Action = TidyFixup
+			}
+		case len(steps) > 0 && !picked:
+			// Checkpoints came first: fold them in, keeping this message
+			step.Action = TidyFixupKeep
+		}
+		picked = picked || !IsCheckpoint(c.Subject)
+		steps = append(steps, step)
+	}
+	if !picked && len(steps) > 0 {
+		steps = append(steps, TidyStep{
+			Action: TidyExec,
+			Exec:   "git commit --amend --no-verify --quiet -m " + adapters.ShellQuote(message),
+		})
+	}
+	return steps, checkpoints
+}
+
+// commits counts the commits the tidied history has
+func (r TidyResult) commits() int {
+	n := 0
+	for _, s := range r.Steps {
+		if s.Action == TidyPick {
+			n++
+		}
+	}
+	return n
+}
+
+// tidyTodo formats steps as a git rebase todo list
+func tidyTodo(steps []TidyStep) string {
+	var b strings.Builder
+	for _, s := range steps {
+		if s.Action == TidyExec {
+			fmt.Fprintf(&b, "%s %s\n", s.Action, s.Exec)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", s.Action, s.Commit, s.Subject)
+	}
+	return b.String()
+}
+
+// writeTidyTodo writes the todo list of steps to the piece's metadata and
+// returns its path
+func (h *Handler) writeTidyTodo(worktree string, steps []TidyStep) (string, error) {
+	store := OpenMetadataStore(h.deps, worktree)
+	if err := store.writeFile(tidyTodoFilename, []byte(tidyTodo(steps))); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", tidyTodoFilename, err)
+	}
+	return store.Path(tidyTodoFilename), nil
+}
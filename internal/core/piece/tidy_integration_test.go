@@ -0,0 +1,118 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_Tidy_FoldsCheckpoints(t *testing.T) {
+	server := gitfake.NewServer(t)
+	_, worktree := setupPieceWorktree(t, server)
+	server.Commit(worktree, "feature.txt", "feature v2\n", "wip: auto-checkpoint")
+	server.Commit(worktree, "parser.txt", "parse\n", "add parser")
+	server.Commit(worktree, "parser.txt", "parse more\n", "wip: auto-checkpoint")
+	tree := server.Git(worktree, "rev-parse", "HEAD^{tree}")
+	before := server.Git(worktree, "rev-parse", "HEAD")
+
+	result, err := newOSHandler().Tidy(worktree, piece.TidyOptions{})
+	if err != nil {
+		t.Fatalf("Tidy failed: %v", err)
+	}
+	if !result.Rewritten || result.Checkpoints != 2 || result.Before != before {
+		t.Errorf("expected 2 checkpoints folded from %s, got %+v", before, result)
+	}
+	if got := server.Git(worktree, "log", "--format=%s", "main..piece-1"); got != "add parser\nadd feature" {
+		t.Errorf("expected the checkpoints folded away, got:\n%s", got)
+	}
+	if got := server.Git(worktree, "rev-parse", "HEAD^{tree}"); got != tree {
+		t.Errorf("expected the same tree after tidying, got %s, want %s", got, tree)
+	}
+	if got := server.Git(worktree, "show", "piece-1~1:feature.txt"); got != "feature v2" {
+		t.Errorf("expected the first checkpoint folded into add feature, got %q", got)
+	}
+}
+
+func TestIntegration_Tidy_OnlyCheckpoints(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	worktree := filepath.Join(server.Dir, "piece-2")
+	server.Git(clone, "worktree", "add", "-b", "piece-2", worktree)
+	server.Commit(worktree, "draft.txt", "one\n", "wip: checkpoint 1")
+	server.Commit(worktree, "draft.txt", "two\n", "wip: checkpoint 2")
+
+	dry, err := newOSHandler().Tidy(worktree, piece.TidyOptions{Message: "Add draft", DryRun: true})
+	if err != nil {
+		t.Fatalf("Tidy --dry-run failed: %v", err)
+	}
+	if dry.Rewritten || len(dry.Steps) != 3 || dry.Steps[2].Action != piece.TidyExec {
+		t.Errorf("expected a planned pick, fixup and exec, got %+v", dry)
+	}
+
+	if _, err := newOSHandler().Tidy(worktree, piece.TidyOptions{Message: "Add draft"}); err != nil {
+		t.Fatalf("Tidy failed: %v", err)
+	}
+	if got := server.Git(worktree, "log", "--format=%s", "main..piece-2"); got != "Add draft" {
+		t.Errorf("expected one commit titled Add draft, got:\n%s", got)
+	}
+}
+
+func TestIntegration_Tidy_LeadingCheckpoints(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	worktree := filepath.Join(server.Dir, "piece-2")
+	server.Git(clone, "worktree", "add", "-b", "piece-2", worktree)
+	server.Commit(worktree, "login.txt", "form\n", "wip: auto-checkpoint")
+	server.Commit(worktree, "login.txt", "form and route\n", "Add login")
+
+	result, err := newOSHandler().Tidy(worktree, piece.TidyOptions{})
+	if err != nil {
+		t.Fatalf("Tidy failed: %v", err)
+	}
+	if result.Steps[1].Action != piece.TidyFixupKeep {
+		t.Errorf("expected Add login to absorb the checkpoint before it, got %+v", result.Steps)
+	}
+	if got := server.Git(worktree, "log", "--format=%s", "main..piece-2"); got != "Add login" {
+		t.Errorf("expected one commit titled Add login, got:\n%s", got)
+	}
+}
+
+func TestIntegration_Tidy_RefusesMergeCommits(t *testing.T) {
+	server := gitfake.NewServer(t)
+	clone, worktree := setupPieceWorktree(t, server)
+	server.Commit(clone, "main.txt", "main\n", "main moves on")
+	server.Git(worktree, "merge", "--no-edit", "main")
+	server.Commit(worktree, "feature.txt", "more\n", "wip: auto-checkpoint")
+
+	_, err := newOSHandler().Tidy(worktree, piece.TidyOptions{})
+	if err == nil || !strings.Contains(err.Error(), "merge commit") {
+		t.Fatalf("expected merge commits to be refused, got %v", err)
+	}
+}
+
+func TestIntegration_TidyInteractive_StartsFromPreparedTodo(t *testing.T) {
+	server := gitfake.NewServer(t)
+	_, worktree := setupPieceWorktree(t, server)
+	server.Commit(worktree, "feature.txt", "feature v2\n", "wip: auto-checkpoint")
+
+	_, rebase, err := newOSHandler().TidyInteractive(worktree, piece.TidyOptions{})
+	if err != nil {
+		t.Fatalf("TidyInteractive failed: %v", err)
+	}
+	// An editor that accepts the todo list as prepared
+	run := exec.Command(rebase.Args[0], rebase.Args[1:]...)
+	run.Dir = rebase.Dir
+	run.Env = append(run.Environ(), append(rebase.Env, "GIT_EDITOR=true")...)
+	if output, err := run.CombinedOutput(); err != nil {
+		t.Fatalf("rebase failed: %v\n%s", err, output)
+	}
+	if got := server.Git(worktree, "log", "--format=%s", "main..piece-1"); got != "add feature" {
+		t.Errorf("expected the checkpoint folded away, got:\n%s", got)
+	}
+}
@@ -0,0 +1,42 @@
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_Tidy_RefusesDirtyWorktree(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, []byte(" M login.go\n"), nil)
+
+	_, err := handler.Tidy("/pieces/piece-1", piece.TidyOptions{})
+	if re, ok := core.AsRemediable(err); !ok || re.Code != core.CodeDirtyWorktree {
+		t.Fatalf("expected dirty_worktree error, got %v", err)
+	}
+}
+
+func TestHandler_Tidy_NoCheckpoints(t *testing.T) {
+	_, _, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge-base", "main", "HEAD"}, []byte("abc123\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-list", "--merges", "abc123..HEAD"}, nil, nil)
+	mockCommitLog(mockExec, "abc123..HEAD", adapters.Commit{Subject: "fix: login"}, adapters.Commit{Subject: "feat: login"})
+	mockExec.AddResponse("git", []string{"rev-parse", "HEAD"}, []byte("def456\n"), nil)
+
+	result, err := handler.Tidy("/pieces/piece-1", piece.TidyOptions{})
+	if err != nil {
+		t.Fatalf("Tidy failed: %v", err)
+	}
+	if result.Rewritten || result.Checkpoints != 0 || len(result.Steps) != 2 {
+		t.Errorf("expected two picks and no rewrite, got %+v", result)
+	}
+	if result.Steps[0].Subject != "feat: login" {
+		t.Errorf("expected the steps oldest first, got %+v", result.Steps)
+	}
+	if mockExec.WasCalled("git", "rebase", "-i", "--no-autosquash", "abc123") {
+		t.Error("expected no rebase without checkpoints")
+	}
+}
@@ -0,0 +1,117 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// maxTitleLength caps the issue title in a session title, in characters
+const maxTitleLength = 32
+
+// TitleResult is the output of `mp piece refresh-title`
+type TitleResult struct {
+	PieceName string `json:"piece_name"`
+	Session   string `json:"session"`
+	Title     string `json:"title"`
+	// Updated is false when the piece's tmux session isn't running
+	Updated bool `json:"updated"`
+}
+
+// SessionTitle names the first window of a piece's tmux session after the
+// piece's issue, truncated, and its status, e.g. "Add login [in-progress]".
+// Pieces without an issue are titled with their name.
+func SessionTitle(pieceName, issueName, status string) string {
+	if issueName == "" {
+		return pieceName
+	}
+	title := issueName
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = strings.TrimSpace(string(runes[:maxTitleLength-1])) + "…"
+	}
+	if status != "" {
+		title += " [" + status + "]"
+	}
+	return title
+}
+
+// RefreshTitle sets the title of the current piece's tmux session from its
+// issue's current title and status. A session that isn't running is left
+// alone, so hooks can call this unconditionally.
+func (h *Handler) RefreshTitle(workDir string) (TitleResult, error) {
+	status, err := h.requirePiece(workDir)
+	if err != nil {
+		return TitleResult{}, err
+	}
+
+	result, err := h.syncTitle(status.PieceName, status.WorktreePath, status.RepoRoot)
+	if err != nil {
+		return TitleResult{}, err
+	}
+
+	msg := core.Message{Type: core.MsgSuccess, Content: fmt.Sprintf("Titled session %s %q", result.Session, result.Title), Data: result}
+	if !result.Updated {
+		msg.Type = core.MsgInfo
+		msg.Content = fmt.Sprintf("Session %s is not running", result.Session)
+	}
+	h.deps.Output.Write(msg)
+	return result, nil
+}
+
+// SyncIssueTitles retitles the running sessions of the repository's pieces
+// working on one of issuePaths (relative to repoRoot), after the issues'
+// status changed. Titles are cosmetic, so failures are ignored.
+func (h *Handler) SyncIssueTitles(repoRoot string, issuePaths ...string) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return
+	}
+	pieces, err := h.pieceWorktrees(repoRoot, piecesDir)
+	if err != nil {
+		return
+	}
+	changed := map[string]bool{}
+	for _, path := range issuePaths {
+		changed[filepath.Clean(path)] = true
+	}
+	for _, p := range pieces {
+		if p.worktree == nil {
+			continue
+		}
+		marker, err := OpenMetadataStore(h.deps, p.path).ReadIssueMarker()
+		if err != nil || !changed[filepath.Clean(marker.IssuePath)] {
+			continue
+		}
+		_, _ = h.syncTitle(p.name, p.path, repoRoot)
+	}
+}
+
+// syncTitle titles the piece's session, if it is running
+func (h *Handler) syncTitle(pieceName, worktreePath, repoRoot string) (TitleResult, error) {
+	var issueName, issueStatus string
+	if marker, err := OpenMetadataStore(h.deps, worktreePath).ReadIssueMarker(); err == nil {
+		issuePath := filepath.Join(repoRoot, marker.IssuePath)
+		issueName = marker.IssueName
+		if name, err := ExtractIssueName(issuePath, h.deps.FS); err == nil {
+			issueName = name
+		}
+		issueStatus, _ = ParseStatus(issuePath, h.deps.FS)
+	}
+
+	result := TitleResult{
+		PieceName: pieceName,
+		Session:   SessionName(pieceName),
+		Title:     SessionTitle(pieceName, issueName, issueStatus),
+	}
+	running, err := h.tmux.HasSession(result.Session)
+	if err != nil || !running {
+		return result, err
+	}
+	if err := h.tmux.RenameWindow("="+result.Session+":^", result.Title); err != nil {
+		return result, err
+	}
+	result.Updated = true
+	return result, nil
+}
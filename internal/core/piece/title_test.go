@@ -0,0 +1,60 @@
+package piece_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestSessionTitle(t *testing.T) {
+	tests := []struct {
+		issueName, status, want string
+	}{
+		{"", "", "piece-1"},
+		{"Add login", "in-progress", "Add login [in-progress]"},
+		{"Add login", "", "Add login"},
+		{"Support single sign-on with every provider", "todo", "Support single sign-on with eve… [todo]"},
+	}
+	for _, tt := range tests {
+		if got := piece.SessionTitle("piece-1", tt.issueName, tt.status); got != tt.want {
+			t.Errorf("SessionTitle(%q, %q) = %q, want %q", tt.issueName, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestHandler_RefreshTitle(t *testing.T) {
+	fs, _, mockExec, handler := setupMockPiece(t, "main")
+	_ = fs.WriteFile("/repo/issues/add-login.md", []byte("---\ntitle: Add login\nstatus: in-progress\n---\n"), 0644)
+	store := piece.OpenMetadataStore(core.Deps{FS: fs, Exec: mockExec}, "/pieces/piece-1")
+	if err := store.WriteIssueMarker(piece.CurrentIssueMarker{IssuePath: "issues/add-login.md", IssueName: "Add login", PieceName: "piece-1"}); err != nil {
+		t.Fatal(err)
+	}
+	mockExec.AddResponse("tmux", []string{"has-session", "-t", "=mp-piece-piece-1"}, nil, nil)
+	mockExec.AddResponse("tmux", []string{"rename-window", "-t", "=mp-piece-piece-1:^", "Add login [in-progress]"}, nil, nil)
+
+	result, err := handler.RefreshTitle("/pieces/piece-1")
+	if err != nil {
+		t.Fatalf("RefreshTitle failed: %v", err)
+	}
+	if !result.Updated || result.Title != "Add login [in-progress]" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if !mockExec.WasCalled("tmux", "rename-window", "-t", "=mp-piece-piece-1:^", "Add login [in-progress]") {
+		t.Error("expected the session's first window to be renamed")
+	}
+}
+
+func TestHandler_RefreshTitle_SessionNotRunning(t *testing.T) {
+	_, out, mockExec, handler := setupMockPiece(t, "main")
+	mockExec.AddResponse("tmux", []string{"has-session", "-t", "=mp-piece-piece-1"}, []byte("can't find session: =mp-piece-piece-1\n"), errors.New("exit status 1"))
+
+	result, err := handler.RefreshTitle("/pieces/piece-1")
+	if err != nil || result.Updated || result.Title != "piece-1" {
+		t.Errorf("expected nothing to update, got %+v (%v)", result, err)
+	}
+	if out.HasWarning() {
+		t.Error("expected no warning for a session that isn't running")
+	}
+}
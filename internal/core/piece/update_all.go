@@ -0,0 +1,133 @@
+package piece
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Outcomes of updating a piece with `mp piece update --all`
+const (
+	UpdateUpdated  = "updated"
+	UpdateUpToDate = "up-to-date"
+	UpdateSkipped  = "skipped"
+	UpdateFailed   = "failed"
+)
+
+// UpdateAllOptions configures updating every active piece
+type UpdateAllOptions struct {
+	// MainBranch is merged into pieces that have no recorded base
+	MainBranch string
+	// DryRun reports what would happen without merging
+	DryRun bool
+}
+
+// UpdateResult reports how one piece was updated
+type UpdateResult struct {
+	PieceName  string   `json:"piece_name"`
+	Branch     string   `json:"branch,omitempty"`
+	BaseBranch string   `json:"base_branch"`
+	Status     string   `json:"status"`
+	Reason     string   `json:"reason,omitempty"`
+	Conflicts  []string `json:"conflicts,omitempty"`
+}
+
+// UpdateAllPieces merges each active piece's base branch into it, for every
+// piece of repoRoot, skipping pieces with uncommitted changes or predicted
+// conflicts, and reports a summary. A failure in one piece does not stop the
+// others.
+func (h *Handler) UpdateAllPieces(repoRoot string, opts UpdateAllOptions) ([]UpdateResult, error) {
+	if opts.MainBranch == "" {
+		opts.MainBranch = "main"
+	}
+
+	names, err := h.ActivePieces(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+
+	results := make([]UpdateResult, 0, len(names))
+	counts := make(map[string]int)
+	for _, name := range names {
+		result := h.updateOne(name, filepath.Join(piecesDir, name), opts)
+		counts[result.Status]++
+		results = append(results, result)
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type: core.MsgSuccess,
+		Content: fmt.Sprintf("%d updated, %d up to date, %d skipped, %d failed",
+			counts[UpdateUpdated], counts[UpdateUpToDate], counts[UpdateSkipped], counts[UpdateFailed]),
+		Data: results,
+	})
+
+	return results, nil
+}
+
+// updateOne updates a single piece for UpdateAllPieces
+func (h *Handler) updateOne(name, worktreePath string, opts UpdateAllOptions) UpdateResult {
+	result := UpdateResult{PieceName: name, BaseBranch: h.BaseBranch(worktreePath, opts.MainBranch)}
+	fail := func(status, reason string) UpdateResult {
+		result.Status = status
+		result.Reason = reason
+		content := fmt.Sprintf("Skipping %s: %s", name, reason)
+		if status == UpdateFailed {
+			content = fmt.Sprintf("Failed to update %s: %s", name, reason)
+		}
+		h.deps.Output.Write(core.Message{Type: core.MsgWarning, Content: content})
+		return result
+	}
+
+	branch, err := h.git.CurrentBranch(worktreePath)
+	if err != nil {
+		return fail(UpdateSkipped, err.Error())
+	}
+	result.Branch = branch
+
+	dirty, err := h.git.HasUncommittedChanges(worktreePath)
+	if err != nil {
+		return fail(UpdateFailed, err.Error())
+	}
+	if dirty {
+		return fail(UpdateSkipped, "worktree has uncommitted changes")
+	}
+
+	behind, err := h.git.IsMainAhead(worktreePath, result.BaseBranch, branch)
+	if err != nil {
+		return fail(UpdateFailed, err.Error())
+	}
+	if !behind {
+		result.Status = UpdateUpToDate
+		return result
+	}
+
+	check, err := h.checkConflicts(worktreePath, name, branch, result.BaseBranch)
+	if err != nil {
+		return fail(UpdateFailed, err.Error())
+	}
+	if check.HasConflicts() {
+		result.Conflicts = check.Conflicts
+		return fail(UpdateSkipped, fmt.Sprintf("%d file(s) would conflict (run 'mp piece update --check' in the piece)", len(check.Conflicts)))
+	}
+
+	if opts.DryRun {
+		result.Status = UpdateUpdated
+		result.Reason = "dry run"
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgInfo,
+			Content: fmt.Sprintf("[dry-run] Would merge %s into %s", result.BaseBranch, branch),
+		})
+		return result
+	}
+
+	if err := h.UpdatePiece(worktreePath, result.BaseBranch); err != nil {
+		return fail(UpdateFailed, err.Error())
+	}
+	result.Status = UpdateUpdated
+	return result
+}
@@ -0,0 +1,69 @@
+//go:build integration
+
+package piece_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/gitfake"
+)
+
+func TestIntegration_UpdateAllPieces(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	piecesDir := filepath.Join(dataHome, "monkeypuzzle", "pieces")
+
+	server := gitfake.NewServer(t)
+	clone := server.Clone("work")
+	addPiece := func(name string) string {
+		path := filepath.Join(piecesDir, name)
+		server.Git(clone, "worktree", "add", "-b", name, path)
+		return path
+	}
+
+	clean := addPiece("clean")
+	server.Commit(clean, "clean.txt", "clean\n", "clean change")
+	conflicting := addPiece("conflicting")
+	server.Commit(conflicting, "README.md", "theirs\n", "conflicting change")
+	dirty := addPiece("dirty")
+	if err := os.WriteFile(filepath.Join(dirty, "scratch.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Commit(clone, "README.md", "ours\n", "main change")
+	addPiece("current")
+
+	results, err := newOSHandler().UpdateAllPieces(clone, piece.UpdateAllOptions{MainBranch: "main"})
+	if err != nil {
+		t.Fatalf("UpdateAllPieces failed: %v", err)
+	}
+
+	got := make(map[string]piece.UpdateResult)
+	for _, r := range results {
+		got[r.PieceName] = r
+	}
+	want := map[string]string{
+		"clean":       piece.UpdateUpdated,
+		"conflicting": piece.UpdateSkipped,
+		"dirty":       piece.UpdateSkipped,
+		"current":     piece.UpdateUpToDate,
+	}
+	for name, status := range want {
+		if got[name].Status != status {
+			t.Errorf("expected %s to be %s, got %+v", name, status, got[name])
+		}
+	}
+	if c := got["conflicting"].Conflicts; len(c) != 1 || c[0] != "README.md" {
+		t.Errorf("expected README.md conflict to be reported, got %v", c)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(clean, "README.md")); string(data) != "ours\n" {
+		t.Errorf("expected main's change merged into clean piece, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(conflicting, "README.md")); string(data) != "theirs\n" {
+		t.Errorf("expected conflicting piece to be untouched, got %q", data)
+	}
+}
@@ -0,0 +1,87 @@
+package piece_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func TestHandler_UpdateAllPieces_DryRun(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = fs.MkdirAll("/test-data/monkeypuzzle/pieces/p1", 0755)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockWorktreeList(mockExec, "p1")
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("p1\n"), nil)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, nil, nil)
+	mockExec.AddResponse("git", []string{"merge-base", "main", "p1"}, []byte("abc123\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-list", "--count", "abc123..main"}, []byte("3\n"), nil)
+	mockExec.AddResponse("git", []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", "p1", "main"},
+		[]byte(conflictTreeID+"\n"), nil)
+
+	results, err := handler.UpdateAllPieces("/repo", piece.UpdateAllOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != piece.UpdateUpdated || results[0].BaseBranch != "main" {
+		t.Fatalf("expected p1 to be reported as updated from main, got %+v", results)
+	}
+	if mockExec.WasCalled("git", "merge", "main") {
+		t.Error("expected no merge in a dry run")
+	}
+}
+
+func TestHandler_UpdateAllPieces_ContinuesAfterFailure(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	_ = fs.MkdirAll("/test-data/monkeypuzzle/pieces/p1", 0755)
+	_ = fs.MkdirAll("/test-data/monkeypuzzle/pieces/p2", 0755)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockMainWorktree(mockExec, "/repo")
+	mockWorktreeList(mockExec, "p1", "p2")
+	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, nil, errors.New("not a git repository"))
+
+	results, err := handler.UpdateAllPieces("/repo", piece.UpdateAllOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(results) != 2 || results[0].Status != piece.UpdateSkipped || results[1].Status != piece.UpdateSkipped {
+		t.Fatalf("expected both pieces to be skipped, got %+v", results)
+	}
+	if !out.HasWarning() || !out.HasSuccess() {
+		t.Error("expected warnings and a summary")
+	}
+}
+
+func TestHandler_UpdateAllPieces_SkipsOtherRepositories(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	mockExec := adapters.NewMockExec()
+	handler := piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+
+	// p1 is a piece of another repository sharing the pieces directory
+	_ = fs.MkdirAll("/test-data/monkeypuzzle/pieces/p1", 0755)
+	_ = fs.WriteFile("/test-data/monkeypuzzle/pieces/p1/.git", []byte("gitdir: /other/.git/worktrees/p1\n"), 0644)
+	mockMainWorktree(mockExec, "/repo")
+
+	results, err := handler.UpdateAllPieces("/repo", piece.UpdateAllOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected the other repository's piece to be left alone, got %+v", results)
+	}
+}
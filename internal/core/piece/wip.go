@@ -0,0 +1,103 @@
+package piece
+
+import (
+	"fmt"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+)
+
+// Piece lifecycle events, used to report work in progress over time
+const (
+	EventPieceCreate = "piece.create"
+	EventPieceRemove = "piece.remove"
+)
+
+// Why a piece was removed, the "reason" of its EventPieceRemove
+const (
+	RemoveReasonCleanup = "cleanup" // merged and cleaned up
+	RemoveReasonDelete  = "delete"  // abandoned with mp piece delete
+	RemoveReasonMissing = "missing" // worktree directory was already gone
+)
+
+// WIPStatus compares the number of active pieces with the configured limit
+type WIPStatus struct {
+	Active int `json:"active"`
+	// Limit is pieces.wip_limit; 0 means no limit
+	Limit int `json:"limit,omitempty"`
+}
+
+// AtLimit reports whether starting another piece would exceed the limit
+func (w WIPStatus) AtLimit() bool {
+	return w.Limit > 0 && w.Active >= w.Limit
+}
+
+// ActivePieces returns the names of the existing piece worktrees of repoRoot,
+// sorted. The pieces directory is shared by every repository, so only
+// directories in repoRoot's git worktree list count.
+func (h *Handler) ActivePieces(repoRoot string) ([]string, error) {
+	piecesDir, err := PiecesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pieces directory: %w", err)
+	}
+
+	pieces, err := h.pieceWorktrees(repoRoot, piecesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, p := range pieces {
+		if p.worktree != nil && !p.worktree.Prunable {
+			names = append(names, p.name)
+		}
+	}
+	return names, nil
+}
+
+// WIP returns the active piece count and the repository's pieces.wip_limit
+func (h *Handler) WIP(repoRoot string) (WIPStatus, error) {
+	active, err := h.ActivePieces(repoRoot)
+	if err != nil {
+		return WIPStatus{}, err
+	}
+
+	status := WIPStatus{Active: len(active)}
+	if cfg, err := ReadConfig(repoRoot, h.deps.FS); err == nil {
+		status.Limit = cfg.Pieces.WIPLimit
+	}
+	return status, nil
+}
+
+// CheckWIPLimit warns when starting another piece would exceed pieces.wip_limit.
+// With enforce, it returns an error instead.
+func (h *Handler) CheckWIPLimit(repoRoot string, enforce bool) (WIPStatus, error) {
+	status, err := h.WIP(repoRoot)
+	if err != nil || !status.AtLimit() {
+		return status, err
+	}
+
+	msg := fmt.Sprintf("%d active pieces already at WIP limit of %d; finish or clean up a piece before starting another",
+		status.Active, status.Limit)
+	if enforce {
+		return status, fmt.Errorf("%s", msg)
+	}
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgWarning,
+		Content: msg,
+		Data:    status,
+	})
+	return status, nil
+}
+
+// logPieceEvent appends a piece lifecycle event with optional data to the
+// events log. Failures are reported as warnings.
+func (h *Handler) logPieceEvent(repoRoot, eventType, pieceName string, data map[string]any) {
+	if err := events.Append(h.deps.FS, repoRoot, events.Event{Type: eventType, Piece: pieceName, Data: data}); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to log %s event: %v", eventType, err),
+		})
+	}
+}
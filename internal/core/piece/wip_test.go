@@ -0,0 +1,63 @@
+package piece_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+func setupWIP(t *testing.T, config string) (*adapters.BufferOutput, *piece.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/a", 0755)
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/b", 0755)
+	_ = fs.WriteFile("test-data/monkeypuzzle/pieces/stray-file", nil, 0644)
+	// A piece of another repository doesn't count against this one's limit
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/other", 0755)
+	_ = fs.WriteFile("test-data/monkeypuzzle/pieces/other/.git", []byte("gitdir: /other/.git/worktrees/other\n"), 0644)
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(config), 0644)
+
+	mockExec := adapters.NewMockExec()
+	mockMainWorktree(mockExec, "/repo")
+	mockWorktreeList(mockExec, "a", "b")
+	return out, piece.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec})
+}
+
+func TestHandler_CheckWIPLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		enforce   bool
+		wantErr   bool
+		wantWarn  bool
+		wantLimit int
+	}{
+		{"no limit", `{"version":"1"}`, true, false, false, 0},
+		{"below limit", `{"version":"1","pieces":{"wip_limit":3}}`, true, false, false, 3},
+		{"at limit warns", `{"version":"1","pieces":{"wip_limit":2}}`, false, false, true, 2},
+		{"at limit enforced", `{"version":"1","pieces":{"wip_limit":2}}`, true, true, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, handler := setupWIP(t, tt.config)
+
+			status, err := handler.CheckWIPLimit("/repo", tt.enforce)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckWIPLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status.Active != 2 || status.Limit != tt.wantLimit {
+				t.Errorf("unexpected status: %+v", status)
+			}
+			if out.HasWarning() != tt.wantWarn {
+				t.Errorf("expected warning %v, got %v", tt.wantWarn, out.HasWarning())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package piece
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Discrepancies between the pieces directory and git's worktree list, reported
+// by cleanup
+const (
+	// DiscrepancyNotWorktree is a directory in the pieces directory that git has
+	// no worktree for, e.g. after the worktree was moved or pruned
+	DiscrepancyNotWorktree = "not-a-worktree"
+	// DiscrepancyMissing is a piece worktree whose directory is gone; cleanup
+	// prunes it
+	DiscrepancyMissing = "missing"
+	// DiscrepancyLocked is a piece worktree locked with git worktree lock
+	DiscrepancyLocked = "locked"
+)
+
+// pieceWorktree pairs a piece with the git worktree registered for it
+type pieceWorktree struct {
+	name string
+	path string
+	// worktree is nil when git has no worktree at path
+	worktree *adapters.Worktree
+}
+
+// pieceWorktrees cross-references the pieces directory with git worktree list,
+// returning every piece found in either, sorted by name. The pieces directory
+// is shared by every repository, so worktrees of other repositories are left out.
+func (h *Handler) pieceWorktrees(repoRoot, piecesDir string) ([]pieceWorktree, error) {
+	worktrees, err := h.git.WorktreeList(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// git may report the pieces directory with symlinks resolved
+	dirs := map[string]bool{filepath.Clean(piecesDir): true}
+	if resolved, err := filepath.EvalSymlinks(piecesDir); err == nil {
+		dirs[resolved] = true
+	}
+
+	pieces := make(map[string]pieceWorktree)
+	for i := range worktrees {
+		wt := &worktrees[i]
+		if wt.Bare || !dirs[filepath.Dir(wt.Path)] {
+			continue
+		}
+		name := filepath.Base(wt.Path)
+		pieces[name] = pieceWorktree{name: name, path: filepath.Join(piecesDir, name), worktree: wt}
+	}
+
+	entries, err := h.deps.FS.ReadDir(piecesDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read pieces directory: %w", err)
+	}
+	commonDir := ""
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, ok := pieces[entry.Name()]; ok {
+			continue
+		}
+		path := filepath.Join(piecesDir, entry.Name())
+		gitDir := worktreeGitDir(h.deps.FS, path)
+		if gitDir != "" && commonDir == "" {
+			if commonDir, err = h.git.RevParseGitCommonDir(repoRoot); err != nil {
+				return nil, err
+			}
+		}
+		if gitDir != "" && sameDir(filepath.Dir(filepath.Dir(gitDir))) != sameDir(commonDir) {
+			continue
+		}
+		pieces[entry.Name()] = pieceWorktree{name: entry.Name(), path: path}
+	}
+
+	result := make([]pieceWorktree, 0, len(pieces))
+	for _, p := range pieces {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].name < result[j].name })
+	return result, nil
+}
+
+// worktreeGitDir returns the git dir named by the .git file of the linked
+// worktree at dir, or "" if dir has none
+func worktreeGitDir(fs core.FS, dir string) string {
+	data, err := fs.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return ""
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	return filepath.Clean(gitDir)
+}
@@ -0,0 +1,91 @@
+// Package plugin discovers external mp-<name> executables on PATH, which mp
+// runs as `mp <name>` (like git and kubectl plugins), and builds the
+// environment that tells them where they were invoked.
+package plugin
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+)
+
+// Prefix is the executable name prefix of plugins
+const Prefix = "mp-"
+
+// Environment variables passed to plugins
+const (
+	EnvRepoRoot = "MP_REPO_ROOT"
+	EnvPiece    = "MP_PIECE"
+	EnvWorktree = "MP_WORKTREE"
+	EnvConfig   = "MP_CONFIG"
+	EnvBin      = "MP_BIN"
+)
+
+// Plugin is an mp-<name> executable
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Discover returns the plugins in the directories of pathList (a PATH value),
+// sorted by name. When several directories contain the same plugin, the first wins.
+func Discover(fs core.FS, pathList string) []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := fs.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), Prefix)
+			if !ok || name == "" || seen[name] {
+				continue
+			}
+			info, err := fs.Stat(filepath.Join(dir, entry.Name()))
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: filepath.Join(dir, entry.Name())})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Context describes where a plugin was invoked
+type Context struct {
+	// RepoRoot is the main repository root, empty outside a repository
+	RepoRoot string
+	// PieceName and WorktreePath are set when invoked inside a piece
+	PieceName    string
+	WorktreePath string
+	// ConfigPath is the repository's monkeypuzzle.json, empty if not initialized
+	ConfigPath string
+	// MpPath is the mp executable, so plugins can call back into mp
+	MpPath string
+}
+
+// Environ returns ctx as MP_* environment variables, omitting unset values
+func (ctx Context) Environ() []string {
+	var vars []string
+	add := func(key, value string) {
+		if value != "" {
+			vars = append(vars, key+"="+value)
+		}
+	}
+	add(EnvRepoRoot, ctx.RepoRoot)
+	add(EnvPiece, ctx.PieceName)
+	add(EnvWorktree, ctx.WorktreePath)
+	add(EnvConfig, ctx.ConfigPath)
+	add(EnvBin, ctx.MpPath)
+	return vars
+}
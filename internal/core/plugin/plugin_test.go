@@ -0,0 +1,41 @@
+package plugin_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/plugin"
+)
+
+func TestDiscover(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	_ = fs.MkdirAll("bin", 0755)
+	_ = fs.MkdirAll("usr/bin", 0755)
+	_ = fs.WriteFile("bin/mp-deploy", []byte("#!/bin/sh\n"), 0755)
+	_ = fs.WriteFile("bin/mp-notes.txt", []byte("not executable"), 0644)
+	_ = fs.WriteFile("bin/mpx", []byte("#!/bin/sh\n"), 0755)
+	_ = fs.WriteFile("usr/bin/mp-deploy", []byte("#!/bin/sh\n"), 0755)
+	_ = fs.WriteFile("usr/bin/mp-board", []byte("#!/bin/sh\n"), 0755)
+
+	got := plugin.Discover(fs, "/bin:/missing:/usr/bin")
+	want := []plugin.Plugin{
+		{Name: "board", Path: "/usr/bin/mp-board"},
+		{Name: "deploy", Path: "/bin/mp-deploy"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %+v, want %+v", got, want)
+	}
+}
+
+func TestContext_Environ(t *testing.T) {
+	ctx := plugin.Context{RepoRoot: "/repo", ConfigPath: "/repo/.monkeypuzzle/monkeypuzzle.json", MpPath: "/usr/bin/mp"}
+	want := []string{
+		"MP_REPO_ROOT=/repo",
+		"MP_CONFIG=/repo/.monkeypuzzle/monkeypuzzle.json",
+		"MP_BIN=/usr/bin/mp",
+	}
+	if got := ctx.Environ(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Environ() = %q, want %q", got, want)
+	}
+}
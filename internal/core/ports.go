@@ -1,6 +1,7 @@
 package core
 
 import (
+	"io"
 	"io/fs"
 	"os"
 )
@@ -8,11 +9,15 @@ import (
 // FS abstracts filesystem operations for testability
 type FS interface {
 	MkdirAll(path string, perm os.FileMode) error
+	// Mkdir creates a single directory, failing with fs.ErrExist if the path
+	// exists; it is atomic, so it can reserve a name
+	Mkdir(path string, perm os.FileMode) error
 	WriteFile(name string, data []byte, perm os.FileMode) error
 	ReadFile(name string) ([]byte, error)
 	Stat(name string) (fs.FileInfo, error)
 	Remove(name string) error
 	Symlink(oldname, newname string) error
+	Readlink(name string) (string, error)
 	ReadDir(name string) ([]fs.DirEntry, error)
 }
 
@@ -43,6 +48,26 @@ type Exec interface {
 	Run(name string, args ...string) ([]byte, error)
 	RunWithDir(dir, name string, args ...string) ([]byte, error)
 	RunWithEnv(dir string, env []string, name string, args ...string) ([]byte, error)
+	// RunSplit executes a command in dir, returning stdout and stderr
+	// separately. A nil env inherits the environment.
+	RunSplit(dir string, env []string, name string, args ...string) (stdout, stderr []byte, err error)
+	// RunStream executes a long-lived command, passing its output to callbacks
+	// line by line as it is written
+	RunStream(opts StreamOptions, name string, args ...string) error
+}
+
+// StreamOptions configures Exec.RunStream
+type StreamOptions struct {
+	// Dir is the working directory; empty uses the current directory
+	Dir string
+	// Env replaces the environment; nil inherits it
+	Env []string
+	// Stdin, when set, is piped to the command
+	Stdin io.Reader
+	// Stdout and Stderr receive each line without its newline. They are never
+	// called concurrently; nil discards the stream.
+	Stdout func(line string)
+	Stderr func(line string)
 }
 
 // Deps holds all injectable dependencies for handlers
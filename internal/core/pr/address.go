@@ -0,0 +1,213 @@
+package pr
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// EventPRAddress is the events log type recorded by Address
+const EventPRAddress = "pr.address"
+
+// ReviewBriefFile is the metadata store file holding the latest review brief
+const ReviewBriefFile = "review-brief.md"
+
+// Address delivery modes
+const (
+	AddressModeAgent = "agent"
+	AddressModePrint = "print"
+	AddressModeNone  = "none"
+)
+
+// AddressOptions controls how review feedback is handed off
+type AddressOptions struct {
+	// Print writes the brief to the result instead of sending it to the agent
+	Print bool
+	// Retry includes threads already attempted by a previous run
+	Retry bool
+}
+
+// AddressResult describes a review hand-off
+type AddressResult struct {
+	PRNumber  int      `json:"pr_number"`
+	PRURL     string   `json:"pr_url"`
+	Mode      string   `json:"mode"`
+	Threads   []string `json:"threads"`
+	Session   string   `json:"session,omitempty"`
+	BriefPath string   `json:"brief_path,omitempty"`
+	Brief     string   `json:"-"`
+}
+
+// Address collects unresolved review threads and the piece diff into a brief.
+// With an agent command configured, the brief is fed to the agent inside the
+// piece's tmux session; otherwise (or with Print) it is returned for printing.
+// Threads sent to the agent are recorded as attempted in PR metadata, and the
+// hand-off is logged to the events file.
+func (h *Handler) Address(workDir string, opts AddressOptions) (*AddressResult, error) {
+	status, err := piece.NewHandler(h.deps).Status(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
+	}
+
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+	h.configureRemote(status.RepoRoot)
+
+	store := piece.OpenMetadataStore(h.deps, status.WorktreePath)
+	metadata, err := store.ReadPRMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("no PR found for this piece (run 'mp piece pr create' first): %w", err)
+	}
+
+	threads, err := h.github.ReviewThreads(workDir, metadata.PRNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []adapters.ReviewThread
+	for _, t := range threads {
+		if t.IsResolved {
+			continue
+		}
+		if !opts.Retry && slices.Contains(metadata.AttemptedThreads, t.ID) {
+			continue
+		}
+		pending = append(pending, t)
+	}
+
+	result := &AddressResult{
+		PRNumber: metadata.PRNumber,
+		PRURL:    metadata.PRURL,
+		Mode:     AddressModeNone,
+		Threads:  []string{},
+	}
+
+	if len(pending) == 0 {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgInfo,
+			Content: fmt.Sprintf("No unresolved review threads to address on PR #%d", metadata.PRNumber),
+		})
+		return result, nil
+	}
+
+	diff, err := h.git.Diff(workDir, metadata.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range pending {
+		result.Threads = append(result.Threads, t.ID)
+	}
+	result.Brief = buildReviewBrief(metadata, pending, diff)
+
+	agentCommand := ""
+	var agentLimits initcmd.AgentLimits
+	if cfg, err := piece.ReadConfig(status.RepoRoot, h.deps.FS); err == nil {
+		agentCommand = strings.TrimSpace(cfg.Agent.Command)
+		agentLimits = cfg.Agent.Limits
+	}
+
+	if opts.Print || agentCommand == "" {
+		result.Mode = AddressModePrint
+	} else {
+		briefPath := store.Path(ReviewBriefFile)
+		if err := h.deps.FS.WriteFile(briefPath, []byte(result.Brief), initcmd.DefaultFilePerm); err != nil {
+			return nil, fmt.Errorf("failed to write review brief: %w", err)
+		}
+
+		session := piece.SessionName(status.PieceName)
+		tmux := adapters.NewTmux(h.deps.Exec)
+		command := agentCommand
+		if agentLimits != (initcmd.AgentLimits{}) {
+			var warnings []string
+			command, warnings = piece.WrapAgentCommand(agentCommand, agentLimits, piece.DetectPlatform(h.deps.Exec, runtime.GOOS))
+			for _, w := range warnings {
+				h.deps.Output.Write(core.Message{Type: core.MsgWarning, Content: w})
+			}
+		}
+		if err := tmux.SendKeys(session, fmt.Sprintf("%s < %s", command, adapters.ShellQuote(briefPath))); err != nil {
+			return nil, err
+		}
+
+		result.Mode = AddressModeAgent
+		result.Session = session
+		result.BriefPath = briefPath
+	}
+
+	// Mark threads sent to the agent as attempted so later runs only pick up
+	// new feedback; a printed brief may never be acted on
+	if result.Mode == AddressModeAgent {
+		for _, id := range result.Threads {
+			if !slices.Contains(metadata.AttemptedThreads, id) {
+				metadata.AttemptedThreads = append(metadata.AttemptedThreads, id)
+			}
+		}
+		if err := store.WritePRMetadata(*metadata); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to write PR metadata: %v", err),
+			})
+		}
+	}
+
+	if err := events.Append(h.deps.FS, status.RepoRoot, events.Event{
+		Type:  EventPRAddress,
+		Piece: status.PieceName,
+		Data: map[string]any{
+			"pr_number": metadata.PRNumber,
+			"mode":      result.Mode,
+			"threads":   result.Threads,
+			"agent":     agentCommand,
+		},
+	}); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to log event: %v", err),
+		})
+	}
+
+	if result.Mode == AddressModeAgent {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgSuccess,
+			Content: fmt.Sprintf("Sent %d review thread(s) to agent in tmux session %s", len(result.Threads), result.Session),
+			Data:    result,
+		})
+	}
+
+	return result, nil
+}
+
+// buildReviewBrief renders review threads and the piece diff as a markdown brief
+func buildReviewBrief(metadata *piece.PRMetadata, threads []adapters.ReviewThread, diff string) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("# Review feedback for PR #%d\n\n", metadata.PRNumber))
+	b.WriteString(fmt.Sprintf("Address the unresolved review comments below on %s.\n", metadata.PRURL))
+	b.WriteString("Make the requested changes, commit them, and push with `mp piece pr update`.\n\n")
+
+	b.WriteString("## Threads\n")
+	for _, t := range threads {
+		location := t.Path
+		if t.Line > 0 {
+			location = fmt.Sprintf("%s:%d", t.Path, t.Line)
+		}
+		b.WriteString(fmt.Sprintf("\n### %s (thread %s)\n\n", location, t.ID))
+		for _, c := range t.Comments {
+			b.WriteString(fmt.Sprintf("**%s**: %s\n\n", c.Author, strings.TrimSpace(c.Body)))
+		}
+	}
+
+	b.WriteString("## Diff\n\n```diff\n")
+	b.WriteString(strings.TrimRight(diff, "\n"))
+	b.WriteString("\n```\n")
+
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package pr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+)
+
+const testDiff = "diff --git a/main.go b/main.go\n+\tdoThing()\n"
+
+func setupAddressTest(t *testing.T, config string) (*adapters.MemoryFS, *adapters.MockExec, string) {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	if config != "" {
+		_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(config), 0644)
+	}
+
+	mockExec.AddResponse("gh", reviewThreadsArgs, []byte(reviewThreadsResponse), nil)
+	mockExec.AddResponse("git", []string{"diff", "main...HEAD"}, []byte(testDiff), nil)
+	return fs, mockExec, worktreePath
+}
+
+func TestAddress_SendsBriefToAgent(t *testing.T) {
+	fs, mockExec, worktreePath := setupAddressTest(t, `{"version":"1","agent":{"command":"claude -p"}}`)
+
+	briefPath := "/repo/.git/worktrees/test-piece/monkeypuzzle/review-brief.md"
+	sendArgs := []string{"send-keys", "-t", "mp-piece-test-piece", "claude -p < '" + briefPath + "'", "Enter"}
+	mockExec.AddResponse("tmux", sendArgs, nil, nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.Address(worktreePath, pr.AddressOptions{})
+	if err != nil {
+		t.Fatalf("Address failed: %v", err)
+	}
+	if result.Mode != pr.AddressModeAgent {
+		t.Errorf("expected agent mode, got %s", result.Mode)
+	}
+	if !mockExec.WasCalled("tmux", sendArgs...) {
+		t.Error("expected brief to be sent to the piece's tmux session")
+	}
+
+	brief, err := fs.ReadFile(briefPath)
+	if err != nil {
+		t.Fatalf("expected brief file: %v", err)
+	}
+	for _, want := range []string{"main.go:12", "**alice**: Handle the error here", "doThing()"} {
+		if !strings.Contains(string(brief), want) {
+			t.Errorf("expected brief to contain %q, got:\n%s", want, brief)
+		}
+	}
+	if strings.Contains(string(brief), "Typo") {
+		t.Error("expected resolved thread to be excluded from brief")
+	}
+
+	metadata, err := testMetadataStore(fs, worktreePath).ReadPRMetadata()
+	if err != nil {
+		t.Fatalf("failed to read metadata: %v", err)
+	}
+	if len(metadata.AttemptedThreads) != 1 || metadata.AttemptedThreads[0] != "T1" {
+		t.Errorf("expected T1 marked attempted, got %v", metadata.AttemptedThreads)
+	}
+
+	logged, err := events.Read(fs, "/repo")
+	if err != nil {
+		t.Fatalf("failed to read events: %v", err)
+	}
+	if len(logged) != 1 || logged[0].Type != pr.EventPRAddress || logged[0].Piece != "test-piece" {
+		t.Errorf("expected pr.address event, got %+v", logged)
+	}
+}
+
+func TestAddress_PrintsBriefWithoutAgent(t *testing.T) {
+	fs, mockExec, worktreePath := setupAddressTest(t, `{"version":"1"}`)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.Address(worktreePath, pr.AddressOptions{})
+	if err != nil {
+		t.Fatalf("Address failed: %v", err)
+	}
+	if result.Mode != pr.AddressModePrint {
+		t.Errorf("expected print mode, got %s", result.Mode)
+	}
+	if !strings.Contains(result.Brief, "# Review feedback for PR #42") {
+		t.Errorf("unexpected brief:\n%s", result.Brief)
+	}
+	for _, call := range mockExec.GetCalls() {
+		if call.Name == "tmux" {
+			t.Error("expected no tmux interaction in print mode")
+		}
+	}
+
+	// Nothing was run, so the thread is still pending
+	if metadata, _ := testMetadataStore(fs, worktreePath).ReadPRMetadata(); len(metadata.AttemptedThreads) != 0 {
+		t.Errorf("expected no thread marked attempted, got %v", metadata.AttemptedThreads)
+	}
+	if result, _ := handler.Address(worktreePath, pr.AddressOptions{}); len(result.Threads) != 1 {
+		t.Errorf("expected the printed thread to be addressed again, got %v", result.Threads)
+	}
+}
+
+func TestAddress_SkipsAttemptedThreads(t *testing.T) {
+	fs, mockExec, worktreePath := setupAddressTest(t, `{"version":"1","agent":{"command":"claude -p"}}`)
+	briefPath := "/repo/.git/worktrees/test-piece/monkeypuzzle/review-brief.md"
+	mockExec.AddResponse("tmux", []string{"send-keys", "-t", "mp-piece-test-piece", "claude -p < '" + briefPath + "'", "Enter"}, nil, nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	if _, err := handler.Address(worktreePath, pr.AddressOptions{}); err != nil {
+		t.Fatalf("first Address failed: %v", err)
+	}
+
+	result, err := handler.Address(worktreePath, pr.AddressOptions{Print: true})
+	if err != nil {
+		t.Fatalf("second Address failed: %v", err)
+	}
+	if result.Mode != pr.AddressModeNone || len(result.Threads) != 0 {
+		t.Errorf("expected nothing left to address, got %+v", result)
+	}
+
+	result, err = handler.Address(worktreePath, pr.AddressOptions{Print: true, Retry: true})
+	if err != nil {
+		t.Fatalf("retry Address failed: %v", err)
+	}
+	if len(result.Threads) != 1 {
+		t.Errorf("expected retry to include attempted thread, got %v", result.Threads)
+	}
+}
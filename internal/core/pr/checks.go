@@ -0,0 +1,154 @@
+package pr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Overall CI states for a PR
+const (
+	ChecksPending = "pending"
+	ChecksSuccess = "success"
+	ChecksFailure = "failure"
+	// ChecksNone means no checks are reported yet: CI may not have picked up
+	// the PR, or the repository may have none
+	ChecksNone = "none"
+)
+
+// DefaultChecksInterval is the default delay between CI status polls
+const DefaultChecksInterval = 10 * time.Second
+
+// ChecksResult summarizes the CI checks of a piece's PR
+type ChecksResult struct {
+	PRNumber int                `json:"pr_number"`
+	PRURL    string             `json:"pr_url"`
+	State    string             `json:"state"`
+	Passed   int                `json:"passed"`
+	Failed   int                `json:"failed"`
+	Pending  int                `json:"pending"`
+	Checks   []adapters.PRCheck `json:"checks"`
+}
+
+// WaitOptions configures WaitForChecks
+type WaitOptions struct {
+	// Interval is the delay between polls (default: DefaultChecksInterval)
+	Interval time.Duration
+	// Timeout aborts waiting after this duration (0 = no timeout)
+	Timeout time.Duration
+	// OnPoll is called with each intermediate result
+	OnPoll func(*ChecksResult)
+}
+
+// Checks polls the CI status of the current piece's PR once.
+func (h *Handler) Checks(workDir string) (*ChecksResult, error) {
+	metadata, err := h.currentPR(workDir)
+	if err != nil {
+		return nil, err
+	}
+	return h.pollChecks(workDir, metadata)
+}
+
+// WaitForChecks polls the CI status of the current piece's PR until every
+// check has finished. Polling goes on while no checks are reported, since CI
+// may not have picked up the PR yet. Returns an error if checks fail or the
+// timeout expires.
+func (h *Handler) WaitForChecks(workDir string, opts WaitOptions) (*ChecksResult, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultChecksInterval
+	}
+
+	metadata, err := h.currentPR(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	for {
+		result, err := h.pollChecks(workDir, metadata)
+		if err != nil {
+			return nil, err
+		}
+		if opts.OnPoll != nil {
+			opts.OnPoll(result)
+		}
+
+		switch result.State {
+		case ChecksSuccess:
+			return result, nil
+		case ChecksFailure:
+			return result, fmt.Errorf("%d check(s) failed on PR #%d", result.Failed, result.PRNumber)
+		}
+
+		if opts.Timeout > 0 && time.Since(start)+opts.Interval > opts.Timeout {
+			if result.State == ChecksNone {
+				return result, fmt.Errorf("timed out after %s: no checks were reported on PR #%d", opts.Timeout, result.PRNumber)
+			}
+			return result, fmt.Errorf("timed out after %s waiting for %d pending check(s)", opts.Timeout, result.Pending)
+		}
+		time.Sleep(opts.Interval)
+	}
+}
+
+// pollChecks fetches and summarizes the checks for a PR
+func (h *Handler) pollChecks(workDir string, metadata *piece.PRMetadata) (*ChecksResult, error) {
+	checks, err := h.github.PRChecks(workDir, metadata.PRNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ChecksResult{
+		PRNumber: metadata.PRNumber,
+		PRURL:    metadata.PRURL,
+		Checks:   checks,
+	}
+	if result.Checks == nil {
+		result.Checks = []adapters.PRCheck{}
+	}
+
+	for _, c := range checks {
+		switch c.Bucket {
+		case "pass", "skipping":
+			result.Passed++
+		case "fail", "cancel":
+			result.Failed++
+		default:
+			result.Pending++
+		}
+	}
+
+	switch {
+	case len(checks) == 0:
+		result.State = ChecksNone
+	case result.Failed > 0:
+		result.State = ChecksFailure
+	case result.Pending > 0:
+		result.State = ChecksPending
+	default:
+		result.State = ChecksSuccess
+	}
+
+	return result, nil
+}
+
+// currentPR returns the PR metadata of the piece containing workDir
+func (h *Handler) currentPR(workDir string) (*piece.PRMetadata, error) {
+	status, err := piece.NewHandler(h.deps).Status(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
+	}
+
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+	h.configureRemote(status.RepoRoot)
+
+	metadata, err := piece.OpenMetadataStore(h.deps, status.WorktreePath).ReadPRMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("no PR found for this piece (run 'mp piece pr create' first): %w", err)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,147 @@
+package pr_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+)
+
+var checksArgs = []string{"pr", "checks", "42", "--json", "name,state,bucket,link"}
+
+func TestChecks_Summarizes(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+
+	// gh exits 8 while checks are pending but still prints JSON
+	mockExec.AddResponse("gh", checksArgs, []byte(`[
+		{"name":"build","state":"SUCCESS","bucket":"pass"},
+		{"name":"lint","state":"IN_PROGRESS","bucket":"pending"},
+		{"name":"docs","state":"SKIPPED","bucket":"skipping"}
+	]`), adapters.MockError("exit status 8"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.Checks(worktreePath)
+	if err != nil {
+		t.Fatalf("Checks failed: %v", err)
+	}
+	if result.State != pr.ChecksPending {
+		t.Errorf("expected state pending, got %s", result.State)
+	}
+	if result.Passed != 2 || result.Pending != 1 || result.Failed != 0 {
+		t.Errorf("unexpected counts: passed=%d pending=%d failed=%d", result.Passed, result.Pending, result.Failed)
+	}
+}
+
+func TestChecks_NoChecksReported(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", checksArgs, []byte("no checks reported on the 'test-piece' branch\n"), adapters.MockError("exit status 1"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.Checks(worktreePath)
+	if err != nil {
+		t.Fatalf("Checks failed: %v", err)
+	}
+	if result.State != pr.ChecksNone || len(result.Checks) != 0 {
+		t.Errorf("expected state none with no checks, got %+v", result)
+	}
+}
+
+func TestWaitForChecks_PollsUntilComplete(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", checksArgs, []byte(`[{"name":"build","bucket":"pending"}]`), adapters.MockError("exit status 8"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	polls := 0
+	result, err := handler.WaitForChecks(worktreePath, pr.WaitOptions{
+		Interval: time.Millisecond,
+		OnPoll: func(r *pr.ChecksResult) {
+			polls++
+			// Complete the build after the first poll
+			mockExec.AddResponse("gh", checksArgs, []byte(`[{"name":"build","bucket":"pass"}]`), nil)
+		},
+	})
+	if err != nil {
+		t.Fatalf("WaitForChecks failed: %v", err)
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 polls, got %d", polls)
+	}
+	if result.State != pr.ChecksSuccess {
+		t.Errorf("expected success, got %s", result.State)
+	}
+}
+
+func TestWaitForChecks_FailureReturnsError(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", checksArgs, []byte(`[{"name":"build","bucket":"fail"}]`), adapters.MockError("exit status 1"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.WaitForChecks(worktreePath, pr.WaitOptions{Interval: time.Millisecond})
+	if err == nil {
+		t.Fatal("expected error when checks fail")
+	}
+	if result == nil || result.State != pr.ChecksFailure {
+		t.Errorf("expected failure result, got %+v", result)
+	}
+}
+
+func TestWaitForChecks_Timeout(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", checksArgs, []byte(`[{"name":"build","bucket":"pending"}]`), adapters.MockError("exit status 8"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_, err := handler.WaitForChecks(worktreePath, pr.WaitOptions{Interval: 5 * time.Millisecond, Timeout: 12 * time.Millisecond})
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
+
+func TestWaitForChecks_WaitsForChecksToBeReported(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", checksArgs, []byte("no checks reported on the 'test-piece' branch\n"), adapters.MockError("exit status 1"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_, err := handler.WaitForChecks(worktreePath, pr.WaitOptions{Interval: 5 * time.Millisecond, Timeout: 12 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "no checks were reported") {
+		t.Fatalf("expected waiting with no checks to time out, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package pr
+
+import (
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+)
+
+// CommentsResult contains the review threads of a piece's PR
+type CommentsResult struct {
+	PRNumber int                     `json:"pr_number"`
+	PRURL    string                  `json:"pr_url"`
+	Threads  []adapters.ReviewThread `json:"threads"`
+}
+
+// Comments fetches review threads for the current piece's PR.
+// Resolved threads are omitted unless includeResolved is set.
+func (h *Handler) Comments(workDir string, includeResolved bool) (*CommentsResult, error) {
+	metadata, err := h.currentPR(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := h.github.ReviewThreads(workDir, metadata.PRNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CommentsResult{
+		PRNumber: metadata.PRNumber,
+		PRURL:    metadata.PRURL,
+		Threads:  []adapters.ReviewThread{},
+	}
+	for _, t := range threads {
+		if t.IsResolved && !includeResolved {
+			continue
+		}
+		result.Threads = append(result.Threads, t)
+	}
+
+	return result, nil
+}
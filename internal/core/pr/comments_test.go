@@ -0,0 +1,84 @@
+package pr_test
+
+import (
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+)
+
+var reviewThreadsArgs = []string{"api", "graphql",
+	"-F", "owner={owner}", "-F", "name={repo}", "-F", "number=42",
+	"-f", "query=" + adapters.ReviewThreadsQuery}
+
+const reviewThreadsResponse = `{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[
+	{"id":"T1","isResolved":false,"isOutdated":false,"path":"main.go","line":12,
+	 "comments":{"nodes":[{"author":{"login":"alice"},"body":"Handle the error here","url":"https://github.com/o/r/pull/42#c1","createdAt":"2025-01-01T00:00:00Z"}]}},
+	{"id":"T2","isResolved":true,"isOutdated":false,"path":"README.md","line":3,
+	 "comments":{"nodes":[{"author":{"login":"bob"},"body":"Typo","url":"","createdAt":""}]}}
+]}}}}}`
+
+func TestComments_ReturnsUnresolvedThreads(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", reviewThreadsArgs, []byte(reviewThreadsResponse), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.Comments(worktreePath, false)
+	if err != nil {
+		t.Fatalf("Comments failed: %v", err)
+	}
+	if len(result.Threads) != 1 {
+		t.Fatalf("expected 1 unresolved thread, got %d", len(result.Threads))
+	}
+
+	thread := result.Threads[0]
+	if thread.Path != "main.go" || thread.Line != 12 {
+		t.Errorf("unexpected thread location: %s:%d", thread.Path, thread.Line)
+	}
+	if len(thread.Comments) != 1 || thread.Comments[0].Author != "alice" || thread.Comments[0].Body != "Handle the error here" {
+		t.Errorf("unexpected comments: %+v", thread.Comments)
+	}
+}
+
+func TestComments_IncludeResolved(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", reviewThreadsArgs, []byte(reviewThreadsResponse), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.Comments(worktreePath, true)
+	if err != nil {
+		t.Fatalf("Comments failed: %v", err)
+	}
+	if len(result.Threads) != 2 {
+		t.Errorf("expected 2 threads, got %d", len(result.Threads))
+	}
+}
+
+func TestComments_GhFails(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("gh", reviewThreadsArgs, []byte("HTTP 401: Bad credentials"), adapters.MockError("exit status 1"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	if _, err := handler.Comments(worktreePath, false); err == nil {
+		t.Error("expected error when gh api fails")
+	}
+}
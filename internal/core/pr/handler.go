@@ -1,14 +1,17 @@
 package pr
 
 import (
-	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
-	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/conventions"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/labels"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/owners"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
 )
 
@@ -17,6 +20,10 @@ type PRCreateResult struct {
 	PRNumber int    `json:"pr_number"`
 	PRURL    string `json:"pr_url"`
 	Branch   string `json:"branch"`
+	// Reviewers were requested from the issue's owners
+	Reviewers []string `json:"reviewers,omitempty"`
+	// Labels are the repository labels added for the issue's labels
+	Labels []string `json:"labels,omitempty"`
 }
 
 // Handler executes PR-related commands
@@ -38,9 +45,6 @@ func NewHandler(deps core.Deps) *Handler {
 // CreatePR creates a GitHub PR for the current piece.
 // Must be run from within a piece worktree.
 func (h *Handler) CreatePR(workDir string, input Input) (*PRCreateResult, error) {
-	// Apply defaults
-	input = WithDefaults(input)
-
 	// Check if we're in a piece worktree
 	pieceHandler := piece.NewHandler(h.deps)
 	status, err := pieceHandler.Status(workDir)
@@ -49,9 +53,17 @@ func (h *Handler) CreatePR(workDir string, input Input) (*PRCreateResult, error)
 	}
 
 	if !status.InPiece {
-		return nil, fmt.Errorf("not in a piece worktree - run this command from within a piece")
+		return nil, core.NewNotInPieceError()
+	}
+
+	// Target the piece's recorded base unless a base was given
+	if strings.TrimSpace(input.Base) == "" {
+		input.Base = pieceHandler.BaseBranch(status.WorktreePath, "")
 	}
 
+	// Apply defaults
+	input = WithDefaults(input)
+
 	// Get current branch
 	branch, err := h.git.CurrentBranch(workDir)
 	if err != nil {
@@ -71,10 +83,20 @@ func (h *Handler) CreatePR(workDir string, input Input) (*PRCreateResult, error)
 		input.Title = status.PieceName
 	}
 
+	// Without a body, the PR starts from the repository's review checklist
+	if input.Body == "" {
+		projectContext, err := conventions.Load(h.deps.FS, status.WorktreePath)
+		if err != nil {
+			return nil, err
+		}
+		input.Body = buildPRBody(nil, nil, projectContext.Section(conventions.SectionChecklist))
+	}
+
 	// Push branch to remote
+	remotes := h.configureRemote(status.RepoRoot)
 	h.deps.Output.Write(core.Message{
 		Type:    core.MsgInfo,
-		Content: fmt.Sprintf("Pushing branch %s to origin...", branch),
+		Content: fmt.Sprintf("Pushing branch %s to %s...", branch, remotes.Remote),
 	})
 
 	if err := h.github.Push(workDir); err != nil {
@@ -91,6 +113,7 @@ func (h *Handler) CreatePR(workDir string, input Input) (*PRCreateResult, error)
 		Title: input.Title,
 		Body:  input.Body,
 		Base:  input.Base,
+		Head:  remotes.HeadRef(branch),
 	})
 	if err != nil {
 		return nil, err
@@ -106,19 +129,54 @@ func (h *Handler) CreatePR(workDir string, input Input) (*PRCreateResult, error)
 		IssuePath:  issuePath,
 	}
 
-	if err := piece.WritePRMetadata(status.WorktreePath, metadata, h.deps.FS); err != nil {
+	if err := piece.OpenMetadataStore(h.deps, status.WorktreePath).WritePRMetadata(metadata); err != nil {
 		h.deps.Output.Write(core.Message{
 			Type:    core.MsgWarning,
 			Content: fmt.Sprintf("Failed to write PR metadata: %v", err),
 		})
 	}
 
+	// Link the PR from the issue so the issue file tells where it is implemented
+	if issuePath != "" {
+		h.linkIssue(status.RepoRoot, issuePath, prResult)
+	}
+
 	result := &PRCreateResult{
 		PRNumber: prResult.Number,
 		PRURL:    prResult.URL,
 		Branch:   branch,
 	}
 
+	h.logPREvent(status.RepoRoot, piece.EventPRCreate, status.PieceName, map[string]any{
+		"pr_number": prResult.Number,
+		"pr_url":    prResult.URL,
+		"base":      input.Base,
+	})
+
+	// Ask the issue's owners for review; the PR exists either way
+	if reviewers := h.issueReviewers(status.RepoRoot, workDir, issuePath); len(reviewers) > 0 {
+		if err := h.github.EditPR(workDir, prResult.Number, adapters.PREditInput{AddReviewers: reviewers}); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to request reviews from %s: %v", strings.Join(reviewers, ", "), err),
+			})
+		} else {
+			result.Reviewers = reviewers
+		}
+	}
+
+	// Carry the issue's labels over where the repository has them
+	if prLabels := h.issueLabels(status.RepoRoot, workDir, issuePath); len(prLabels) > 0 {
+		if err := h.github.EditPR(workDir, prResult.Number, adapters.PREditInput{AddLabels: prLabels}); err != nil {
+			h.deps.Output.Write(core.Message{
+				Type:    core.MsgWarning,
+				Content: fmt.Sprintf("Failed to add labels %s: %v", strings.Join(prLabels, ", "), err),
+			})
+		} else {
+			result.Labels = prLabels
+		}
+	}
+
 	h.deps.Output.Write(core.Message{
 		Type:    core.MsgSuccess,
 		Content: fmt.Sprintf("Created PR #%d: %s", prResult.Number, prResult.URL),
@@ -128,19 +186,239 @@ func (h *Handler) CreatePR(workDir string, input Input) (*PRCreateResult, error)
 	return result, nil
 }
 
-// readIssueMarker reads the current issue marker from the piece worktree.
-// Returns nil if no marker exists.
-func (h *Handler) readIssueMarker(worktreePath string) (*piece.CurrentIssueMarker, string) {
-	markerPath := filepath.Join(worktreePath, initcmd.DirName, "current-issue.json")
-	data, err := h.deps.FS.ReadFile(markerPath)
+// PRUpdateResult contains the result of updating a PR
+type PRUpdateResult struct {
+	PRNumber     int    `json:"pr_number"`
+	PRURL        string `json:"pr_url"`
+	Branch       string `json:"branch"`
+	TitleUpdated bool   `json:"title_updated"`
+	BodyUpdated  bool   `json:"body_updated"`
+}
+
+// UpdatePR pushes the current piece branch and refreshes its existing PR.
+// The title and body are edited only when provided (or when regenerating the body).
+// Must be run from within a piece worktree that already has PR metadata.
+func (h *Handler) UpdatePR(workDir string, input UpdateInput) (*PRUpdateResult, error) {
+	input.Title = strings.TrimSpace(input.Title)
+	input.Body = strings.TrimSpace(input.Body)
+	if input.RegenerateBody && input.Body != "" {
+		return nil, fmt.Errorf("cannot use both a body and body regeneration")
+	}
+
+	pieceHandler := piece.NewHandler(h.deps)
+	status, err := pieceHandler.Status(workDir)
 	if err != nil {
-		return nil, ""
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
 	}
 
-	var marker piece.CurrentIssueMarker
-	if err := json.Unmarshal(data, &marker); err != nil {
-		return nil, ""
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+
+	store := piece.OpenMetadataStore(h.deps, status.WorktreePath)
+	metadata, err := store.ReadPRMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("no PR found for this piece (run 'mp piece pr create' first): %w", err)
+	}
+
+	branch, err := h.git.CurrentBranch(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	remotes := h.configureRemote(status.RepoRoot)
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgInfo,
+		Content: fmt.Sprintf("Pushing branch %s to %s...", branch, remotes.Remote),
+	})
+
+	if err := h.github.Push(workDir); err != nil {
+		return nil, fmt.Errorf("failed to push branch: %w", err)
+	}
+
+	if input.RegenerateBody {
+		commits, err := h.git.CommitLog(workDir, metadata.BaseBranch, branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit messages: %w", err)
+		}
+		issueMarker, _ := h.readIssueMarker(status.WorktreePath)
+		projectContext, err := conventions.Load(h.deps.FS, status.WorktreePath)
+		if err != nil {
+			return nil, err
+		}
+		input.Body = buildPRBody(issueMarker, commits, projectContext.Section(conventions.SectionChecklist))
+	}
+
+	result := &PRUpdateResult{
+		PRNumber:     metadata.PRNumber,
+		PRURL:        metadata.PRURL,
+		Branch:       branch,
+		TitleUpdated: input.Title != "",
+		BodyUpdated:  input.Body != "",
+	}
+
+	if result.TitleUpdated || result.BodyUpdated {
+		if err := h.github.EditPR(workDir, metadata.PRNumber, adapters.PREditInput{
+			Title: input.Title,
+			Body:  input.Body,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record the update in PR metadata
+	metadata.Branch = branch
+	metadata.UpdatedAt = time.Now()
+	if err := store.WritePRMetadata(*metadata); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to write PR metadata: %v", err),
+		})
+	}
+
+	h.logPREvent(status.RepoRoot, piece.EventPRUpdate, status.PieceName, map[string]any{
+		"pr_number":     metadata.PRNumber,
+		"title_updated": result.TitleUpdated,
+		"body_updated":  result.BodyUpdated,
+	})
+
+	h.deps.Output.Write(core.Message{
+		Type:    core.MsgSuccess,
+		Content: fmt.Sprintf("Updated PR #%d: %s", metadata.PRNumber, metadata.PRURL),
+		Data:    result,
+	})
+
+	return result, nil
+}
+
+// configureRemote points the GitHub adapter at the configured push remote and
+// PR repository, returning the configuration for callers that need the head ref.
+func (h *Handler) configureRemote(repoRoot string) piece.RemoteConfig {
+	rc := piece.ReadRemoteConfig(repoRoot, h.deps.FS)
+	h.github.SetRemote(rc.Remote)
+	h.github.SetRepo(rc.Repo)
+	h.github.SetHost(rc.Host)
+	return rc
+}
+
+// buildPRBody generates a PR description from the linked issue and the piece's
+// commits, listing each subject with its body indented below it, followed by
+// the review checklist of the repository's context.md
+func buildPRBody(issueMarker *piece.CurrentIssueMarker, commits []adapters.Commit, checklist string) string {
+	var b strings.Builder
+
+	if issueMarker != nil {
+		b.WriteString(fmt.Sprintf("Implements %s (`%s`)\n", issueMarker.IssueName, issueMarker.IssuePath))
+	}
+
+	if len(commits) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## Commits\n\n")
+		for _, commit := range commits {
+			b.WriteString(fmt.Sprintf("- %s\n", commit.Subject))
+			if commit.Body != "" {
+				for _, line := range strings.Split(commit.Body, "\n") {
+					b.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+				}
+			}
+		}
+	}
+
+	if checklist != "" {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## " + conventions.SectionChecklist + "\n\n" + checklist + "\n")
 	}
 
-	return &marker, marker.IssuePath
+	return strings.TrimSpace(b.String())
+}
+
+// issueReviewers returns the owners of the piece's issue, per .monkeypuzzle/owners,
+// to request as PR reviewers. The current user is left out.
+func (h *Handler) issueReviewers(repoRoot, workDir, issuePath string) []string {
+	if issuePath == "" {
+		return nil
+	}
+	rules, err := owners.Load(h.deps.FS, repoRoot)
+	if err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Skipping reviewers: %v", err),
+		})
+		return nil
+	}
+	if len(rules.Rules) == 0 {
+		return nil
+	}
+
+	var labels []string
+	if content, err := h.deps.FS.ReadFile(filepath.Join(repoRoot, issuePath)); err == nil {
+		labels = piece.ExtractLabels(string(content))
+	}
+	return owners.Reviewers(rules.For(issuePath, labels), owners.Identity(h.deps.Exec, workDir))
+}
+
+// issueLabels returns the repository labels for the labels of the piece's
+// issue: each label, or the GitHub label it maps to in .monkeypuzzle/labels.json,
+// that exists in the repository. Labels the repository lacks are skipped.
+func (h *Handler) issueLabels(repoRoot, workDir, issuePath string) []string {
+	if issuePath == "" {
+		return nil
+	}
+	content, err := h.deps.FS.ReadFile(filepath.Join(repoRoot, issuePath))
+	if err != nil {
+		return nil
+	}
+	names := piece.ExtractLabels(string(content))
+	if len(names) == 0 {
+		return nil
+	}
+	taxonomy, err := labels.Load(h.deps.FS, repoRoot)
+	if err != nil {
+		h.deps.Output.Write(core.Message{Type: core.MsgWarning, Content: fmt.Sprintf("Skipping labels: %v", err)})
+		return nil
+	}
+	repoLabels, err := h.github.Labels(workDir)
+	if err != nil {
+		h.deps.Output.Write(core.Message{Type: core.MsgWarning, Content: fmt.Sprintf("Skipping labels: %v", err)})
+		return nil
+	}
+	return taxonomy.GitHubLabels(names, repoLabels)
+}
+
+// logPREvent appends a PR event of a piece to the events log. Failure is only a warning.
+func (h *Handler) logPREvent(repoRoot, eventType, pieceName string, data map[string]any) {
+	if err := events.Append(h.deps.FS, repoRoot, events.Event{Type: eventType, Piece: pieceName, Data: data}); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to log %s event: %v", eventType, err),
+		})
+	}
+}
+
+// linkIssue writes the PR's number and URL into the frontmatter of the issue
+// at issuePath (relative to repoRoot). Failure is only a warning.
+func (h *Handler) linkIssue(repoRoot, issuePath string, prResult *adapters.PRCreateResult) {
+	if !filepath.IsAbs(issuePath) {
+		issuePath = filepath.Join(repoRoot, issuePath)
+	}
+	if err := piece.SetPRLink(issuePath, prResult.Number, prResult.URL, h.deps.FS); err != nil {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("Failed to link PR from issue: %v", err),
+		})
+	}
+}
+
+// readIssueMarker reads the current issue marker from the piece's metadata store.
+// Returns nil if no marker exists.
+func (h *Handler) readIssueMarker(worktreePath string) (*piece.CurrentIssueMarker, string) {
+	marker, err := piece.OpenMetadataStore(h.deps, worktreePath).ReadIssueMarker()
+	if err != nil {
+		return nil, ""
+	}
+	return marker, marker.IssuePath
 }
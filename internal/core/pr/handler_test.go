@@ -2,8 +2,12 @@ package pr_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
 	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
@@ -23,6 +27,8 @@ func setupTestPieceWorktree(t *testing.T, mockExec *adapters.MockExec, fs *adapt
 	// The gitdir for a worktree is under .git/worktrees/
 	gitDir := filepath.Join(mainRepoPath, ".git", "worktrees", "test-piece")
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-common-dir"}, []byte(filepath.Join(mainRepoPath, ".git")+"\n"), nil)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree "+mainRepoPath+"\n"), nil)
 
 	// Mock git rev-parse --show-toplevel to return worktree path
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
@@ -78,7 +84,7 @@ func TestCreatePR_HappyPath(t *testing.T) {
 	}
 
 	// Verify PR metadata was written
-	metadata, err := piece.ReadPRMetadata(worktreePath, fs)
+	metadata, err := testMetadataStore(fs, worktreePath).ReadPRMetadata()
 	if err != nil {
 		t.Fatalf("failed to read PR metadata: %v", err)
 	}
@@ -90,6 +96,182 @@ func TestCreatePR_HappyPath(t *testing.T) {
 	}
 }
 
+func TestCreatePR_PushesToConfiguredRemote(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1","git":{"remote":"fork"}}`), 0644)
+
+	mockExec.AddResponse("git", []string{"push", "-u", "fork", "HEAD"}, []byte(""), nil)
+	mockExec.AddResponse("gh", []string{"pr", "create", "--title", "Test PR", "--body", "", "--base", "main"},
+		[]byte("https://github.com/owner/repo/pull/42\n"), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	if _, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"}); err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if !mockExec.WasCalled("git", "push", "-u", "fork", "HEAD") {
+		t.Error("expected push to configured remote 'fork'")
+	}
+	if mockExec.WasCalled("git", "push", "-u", "origin", "HEAD") {
+		t.Error("expected no push to origin")
+	}
+}
+
+func TestCreatePR_IgnoresGhStderr(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+	mockExec.AddSplitResponse("gh", []string{"pr", "create", "--title", "Test PR", "--body", "", "--base", "main"},
+		[]byte("https://github.com/owner/repo/pull/42\n"),
+		[]byte("Warning: 1 uncommitted change\n\nCreating pull request for test-piece into main in owner/repo\n"), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if result.PRNumber != 42 || result.PRURL != "https://github.com/owner/repo/pull/42" {
+		t.Errorf("expected PR URL parsed from stdout only, got %+v", result)
+	}
+}
+
+func TestCreatePR_ParsesURLAmongOutputLines(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+	mockExec.AddResponse("gh", []string{"pr", "create", "--title", "Test PR", "--body", "", "--base", "main"},
+		[]byte("Created: https://github.com/owner/repo/pull/42\nA new release of gh is available: 2.40.0 -> 2.41.0\n"), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if result.PRNumber != 42 || result.PRURL != "https://github.com/owner/repo/pull/42" {
+		t.Errorf("expected PR #42 found among output lines, got %+v", result)
+	}
+}
+
+func TestCreatePR_FallsBackToPRView(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+	mockExec.AddResponse("gh", []string{"pr", "create", "--title", "Test PR", "--body", "", "--base", "main"},
+		[]byte("Pull request created\n"), nil)
+	mockExec.AddResponse("gh", []string{"pr", "view", "--json", "number,url"},
+		[]byte(`{"number":43,"url":"https://git.corp/owner/repo/pull/43"}`), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if result.PRNumber != 43 || result.PRURL != "https://git.corp/owner/repo/pull/43" {
+		t.Errorf("expected PR from gh pr view, got %+v", result)
+	}
+}
+
+func TestCreatePR_GhFailureReportsStderr(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+	mockExec.AddSplitResponse("gh", []string{"pr", "create", "--title", "Test PR", "--body", "", "--base", "main"},
+		nil, []byte("a pull request for branch \"test-piece\" already exists\n"), adapters.MockError("exit status 1"))
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected gh's stderr in the error, got %v", err)
+	}
+}
+
+func TestCreatePR_GitHubEnterprise(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json",
+		[]byte(`{"version":"1","pr":{"provider":"github","config":{"host":"github.example.com"}}}`), 0644)
+
+	createArgs := []string{"pr", "create", "--title", "Test PR", "--body", "", "--base", "main"}
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+	mockExec.AddResponse("gh", createArgs, []byte("https://github.example.com/platform/api/pull/7\n"), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if result.PRNumber != 7 {
+		t.Errorf("expected PR #7 parsed from the enterprise URL, got %d", result.PRNumber)
+	}
+	for _, call := range mockExec.GetCalls() {
+		if call.Name == "gh" && !slices.Contains(call.Env, "GH_HOST=github.example.com") {
+			t.Errorf("expected GH_HOST for gh %v, got %v", call.Args, call.Env)
+		}
+	}
+
+	// A URL on another host means gh talked to the wrong server
+	mockExec.AddResponse("gh", createArgs, []byte("https://github.com/platform/api/pull/7\n"), nil)
+	if _, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"}); err == nil || !strings.Contains(err.Error(), "github.example.com") {
+		t.Errorf("expected host mismatch error, got %v", err)
+	}
+}
+
+func TestCreatePR_FromFork(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	config := `{"version":"1","git":{"remote":"fork"},"pr":{"provider":"github","config":{"repo":"upstream/project","head_owner":"me"}}}`
+	_ = fs.WriteFile("/repo/.monkeypuzzle/monkeypuzzle.json", []byte(config), 0644)
+
+	createArgs := []string{"pr", "create", "--title", "Test PR", "--body", "", "--base", "main",
+		"--head", "me:test-piece", "--repo", "upstream/project"}
+	mockExec.AddResponse("git", []string{"push", "-u", "fork", "HEAD"}, []byte(""), nil)
+	mockExec.AddResponse("gh", createArgs, []byte("https://github.com/upstream/project/pull/9\n"), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if result.PRNumber != 9 {
+		t.Errorf("expected PR number 9, got %d", result.PRNumber)
+	}
+	if !mockExec.WasCalled("gh", createArgs...) {
+		t.Error("expected PR to be opened against upstream with fork head")
+	}
+}
+
 func TestCreatePR_UsesIssueTitleWhenAvailable(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	mockExec := adapters.NewMockExec()
@@ -142,7 +324,7 @@ func TestCreatePR_UsesIssueTitleWhenAvailable(t *testing.T) {
 	}
 
 	// Verify issue path was stored in metadata
-	metadata, err := piece.ReadPRMetadata(worktreePath, fs)
+	metadata, err := testMetadataStore(fs, worktreePath).ReadPRMetadata()
 	if err != nil {
 		t.Fatalf("failed to read PR metadata: %v", err)
 	}
@@ -151,6 +333,98 @@ func TestCreatePR_UsesIssueTitleWhenAvailable(t *testing.T) {
 	}
 }
 
+func TestCreatePR_RequestsOwnerReviews(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	marker, _ := json.Marshal(piece.CurrentIssueMarker{IssuePath: "issues/backend/api.md", IssueName: "API", PieceName: "test-piece"})
+	_ = fs.WriteFile(filepath.Join(worktreePath, ".monkeypuzzle", "current-issue.json"), marker, 0644)
+	_ = fs.MkdirAll("repo/issues/backend", 0755)
+	_ = fs.WriteFile("repo/issues/backend/api.md", []byte("---\ntitle: API\nlabels: [security]\n---\n"), 0644)
+	_ = fs.WriteFile("repo/.monkeypuzzle/owners", []byte("issues/backend/ @backend-team\nlabel:security @sec @me ops@example.com\n"), 0644)
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, nil, nil)
+	mockExec.AddResponse("gh", []string{"pr", "create", "--title", "API", "--body", "", "--base", "main"},
+		[]byte("https://github.com/owner/repo/pull/7\n"), nil)
+	mockExec.AddResponse("git", []string{"config", "--get", "mp.user"}, []byte("@me\n"), nil)
+	mockExec.AddResponse("gh", []string{"pr", "edit", "7", "--add-reviewer", "sec"}, nil, nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	result, err := handler.CreatePR(worktreePath, pr.Input{})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+
+	if len(result.Reviewers) != 1 || result.Reviewers[0] != "sec" {
+		t.Errorf("expected the label owner other than the author as reviewer, got %v", result.Reviewers)
+	}
+	if !mockExec.WasCalled("gh", "pr", "edit", "7", "--add-reviewer", "sec") {
+		t.Error("expected reviewers to be requested")
+	}
+}
+
+func TestCreatePR_AddsIssueLabels(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	marker, _ := json.Marshal(piece.CurrentIssueMarker{IssuePath: "issues/api.md", IssueName: "API", PieceName: "test-piece"})
+	_ = fs.WriteFile(filepath.Join(worktreePath, ".monkeypuzzle", "current-issue.json"), marker, 0644)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/issues/api.md", []byte("---\ntitle: API\nlabels: [bug, infra, ui]\n---\n"), 0644)
+	_ = fs.WriteFile("repo/.monkeypuzzle/labels.json", []byte(`{"labels": [{"name": "bug"}, {"name": "infra", "github": "area: infra"}, {"name": "ui"}]}`), 0644)
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, nil, nil)
+	mockExec.AddResponse("gh", []string{"pr", "create", "--title", "API", "--body", "", "--base", "main"},
+		[]byte("https://github.com/owner/repo/pull/7\n"), nil)
+	mockExec.AddResponse("gh", []string{"label", "list", "--json", "name", "--limit", "1000"},
+		[]byte(`[{"name": "Bug"}, {"name": "area: infra"}, {"name": "enhancement"}]`), nil)
+	mockExec.AddResponse("gh", []string{"pr", "edit", "7", "--add-label", "Bug,area: infra"}, nil, nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	result, err := handler.CreatePR(worktreePath, pr.Input{})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+
+	if !slices.Equal(result.Labels, []string{"Bug", "area: infra"}) {
+		t.Errorf("expected the labels the repository has, got %v", result.Labels)
+	}
+	if !mockExec.WasCalled("gh", "pr", "edit", "7", "--add-label", "Bug,area: infra") {
+		t.Error("expected labels to be added")
+	}
+}
+
+func TestCreatePR_LinksIssue(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	marker, _ := json.Marshal(piece.CurrentIssueMarker{IssuePath: "issues/api.md", IssueName: "API", PieceName: "test-piece"})
+	_ = fs.WriteFile(filepath.Join(worktreePath, ".monkeypuzzle", "current-issue.json"), marker, 0644)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/issues/api.md", []byte("---\ntitle: API\nstatus: in-progress\n---\n\n# API\n"), 0644)
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, nil, nil)
+	mockExec.AddResponse("gh", []string{"pr", "create", "--title", "API", "--body", "", "--base", "main"},
+		[]byte("https://github.com/owner/repo/pull/7\n"), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	if _, err := handler.CreatePR(worktreePath, pr.Input{}); err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+
+	content, _ := fs.ReadFile("repo/issues/api.md")
+	want := "---\ntitle: API\nstatus: in-progress\npr_number: 7\npr_url: https://github.com/owner/repo/pull/7\n---\n\n# API\n"
+	if string(content) != want {
+		t.Errorf("expected PR linked in frontmatter:\n%s\ngot:\n%s", want, content)
+	}
+}
+
 func TestCreatePR_UsesPieceNameAsFallback(t *testing.T) {
 	fs := adapters.NewMemoryFS()
 	mockExec := adapters.NewMockExec()
@@ -166,6 +440,8 @@ func TestCreatePR_UsesPieceNameAsFallback(t *testing.T) {
 	// Mock git commands for a worktree named "my-feature-piece"
 	gitDir := filepath.Join(mainRepoPath, ".git", "worktrees", "my-feature-piece")
 	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte(gitDir+"\n"), nil)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-common-dir"}, []byte(filepath.Join(mainRepoPath, ".git")+"\n"), nil)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree "+mainRepoPath+"\n"), nil)
 	mockExec.AddResponse("git", []string{"rev-parse", "--show-toplevel"}, []byte(worktreePath+"\n"), nil)
 	mockExec.AddResponse("git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, []byte("my-feature-piece\n"), nil)
 
@@ -340,3 +616,157 @@ func TestWithDefaults(t *testing.T) {
 		})
 	}
 }
+
+// testMetadataStore returns the metadata store of the worktree mocked by setupTestPieceWorktree
+func testMetadataStore(fs *adapters.MemoryFS, worktreePath string) *piece.MetadataStore {
+	return piece.NewMetadataStore(fs, "/repo/.git/worktrees/test-piece", worktreePath)
+}
+
+// mockCommitLog mocks git log of revRange (e.g. "main..piece-1") for CommitLog,
+// formatting commits the way git does with adapters.CommitLogFormat
+func mockCommitLog(m *adapters.MockExec, revRange string, commits ...adapters.Commit) {
+	var output strings.Builder
+	for i, c := range commits {
+		if c.Hash == "" {
+			c.Hash = fmt.Sprintf("%040d", i+1)
+		}
+		var trailers []string
+		for _, t := range c.Trailers {
+			trailers = append(trailers, t.String())
+		}
+		body := c.Body
+		if len(trailers) > 0 {
+			body = strings.TrimSpace(body + "\n\n" + strings.Join(trailers, "\n"))
+		}
+		date := ""
+		if !c.Time.IsZero() {
+			date = c.Time.Format(time.RFC3339)
+		}
+		fmt.Fprintf(&output, "\x1e%s\x1f%s\x1f%s\x1f%s\x1f%s\x1f%s\n\x1f%s\n\n", c.Hash, c.Author, c.AuthorEmail, date, c.Subject, body, strings.Join(trailers, "\n"))
+	}
+	m.AddResponse("git", []string{"log", "--format=" + adapters.CommitLogFormat, revRange}, []byte(output.String()), nil)
+}
+
+func writeTestPRMetadata(t *testing.T, fs *adapters.MemoryFS, worktreePath string) {
+	t.Helper()
+	err := testMetadataStore(fs, worktreePath).WritePRMetadata(piece.PRMetadata{
+		PRNumber:   42,
+		PRURL:      "https://github.com/owner/repo/pull/42",
+		Branch:     "test-piece",
+		BaseBranch: "main",
+	})
+	if err != nil {
+		t.Fatalf("failed to write PR metadata: %v", err)
+	}
+}
+
+func TestUpdatePR_PushesAndRegeneratesBody(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	output := adapters.NewBufferOutput()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+
+	marker, _ := json.Marshal(piece.CurrentIssueMarker{
+		IssuePath: "issues/my-feature.md",
+		IssueName: "My Feature",
+		PieceName: "test-piece",
+	})
+	_ = fs.WriteFile(filepath.Join(worktreePath, ".monkeypuzzle", "current-issue.json"), marker, 0644)
+	_ = fs.WriteFile(filepath.Join(worktreePath, ".monkeypuzzle", "context.md"), []byte("## Architecture\n\nOne binary.\n\n## Review checklist\n\n- [ ] Docs updated\n"), 0644)
+
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+	mockCommitLog(mockExec, "main..test-piece",
+		adapters.Commit{Subject: "fix tests", Body: "The fixture was stale.\n\nRegenerated it.", Trailers: []adapters.Trailer{{Key: "Co-authored-by", Value: "Bob <bob@example.com>"}}},
+		adapters.Commit{Subject: "add feature"})
+	expectedBody := "Implements My Feature (`issues/my-feature.md`)\n\n## Commits\n\n- fix tests\n  The fixture was stale.\n\n  Regenerated it.\n- add feature\n\n## Review checklist\n\n- [ ] Docs updated"
+	mockExec.AddResponse("gh", []string{"pr", "edit", "42", "--title", "New title", "--body", expectedBody}, nil, nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: output, Exec: mockExec})
+
+	result, err := handler.UpdatePR(worktreePath, pr.UpdateInput{Title: "New title", RegenerateBody: true})
+	if err != nil {
+		t.Fatalf("UpdatePR failed: %v", err)
+	}
+
+	if !result.TitleUpdated || !result.BodyUpdated {
+		t.Errorf("expected title and body updated, got %+v", result)
+	}
+	if !mockExec.WasCalled("gh", "pr", "edit", "42", "--title", "New title", "--body", expectedBody) {
+		t.Error("expected gh pr edit with regenerated body")
+	}
+
+	metadata, err := testMetadataStore(fs, worktreePath).ReadPRMetadata()
+	if err != nil {
+		t.Fatalf("failed to read PR metadata: %v", err)
+	}
+	if metadata.UpdatedAt.IsZero() {
+		t.Error("expected UpdatedAt to be recorded in PR metadata")
+	}
+}
+
+func TestUpdatePR_PushOnly(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	result, err := handler.UpdatePR(worktreePath, pr.UpdateInput{})
+	if err != nil {
+		t.Fatalf("UpdatePR failed: %v", err)
+	}
+	if result.TitleUpdated || result.BodyUpdated {
+		t.Errorf("expected no edits, got %+v", result)
+	}
+	for _, call := range mockExec.GetCalls() {
+		if call.Name == "gh" {
+			t.Errorf("expected no gh calls, got %v", call.Args)
+		}
+	}
+}
+
+func TestUpdatePR_NoMetadata(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	if _, err := handler.UpdatePR(worktreePath, pr.UpdateInput{}); err == nil {
+		t.Error("expected error when piece has no PR")
+	}
+}
+
+func TestUpdatePR_BodyAndRegenerateConflict(t *testing.T) {
+	handler := pr.NewHandler(core.Deps{FS: adapters.NewMemoryFS(), Output: adapters.NewBufferOutput(), Exec: adapters.NewMockExec()})
+
+	_, err := handler.UpdatePR("/pieces/test-piece", pr.UpdateInput{Body: "manual", RegenerateBody: true})
+	if err == nil {
+		t.Error("expected error when combining body and regenerate")
+	}
+}
+
+func TestCreatePR_BodyFromReviewChecklist(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	_ = fs.WriteFile(filepath.Join(worktreePath, ".monkeypuzzle", "context.md"), []byte("## Review checklist\n\n- [ ] Docs updated\n"), 0644)
+	mockExec.AddResponse("git", []string{"push", "-u", "origin", "HEAD"}, []byte(""), nil)
+	mockExec.AddResponse("gh", []string{"pr", "create", "--title", "Test PR", "--body", "## Review checklist\n\n- [ ] Docs updated", "--base", "main"},
+		[]byte("https://github.com/owner/repo/pull/42\n"), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+	if _, err := handler.CreatePR(worktreePath, pr.Input{Title: "Test PR", Base: "main"}); err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+}
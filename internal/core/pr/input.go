@@ -42,6 +42,13 @@ type Input struct {
 	Base  string `json:"base"`
 }
 
+// UpdateInput holds input for refreshing an existing PR
+type UpdateInput struct {
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	RegenerateBody bool   `json:"regenerate_body"`
+}
+
 // Schema returns the JSON schema with defaults for PR create
 func Schema() ([]byte, error) {
 	schema := map[string]any{}
@@ -0,0 +1,65 @@
+package pr
+
+import (
+	"fmt"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Where PRLink found the PR URL
+const (
+	LinkSourceMetadata = "metadata"
+	LinkSourceGitHub   = "gh"
+)
+
+// PRLink is the URL of a piece's PR and the command that opens it
+type PRLink struct {
+	PieceName string `json:"piece_name"`
+	PRNumber  int    `json:"pr_number"`
+	PRURL     string `json:"pr_url"`
+	// Source is LinkSourceMetadata or LinkSourceGitHub
+	Source string `json:"source"`
+	// Command is the command line run to open the URL, empty with --print
+	Command []string `json:"command,omitempty"`
+}
+
+// Link returns the PR URL of the piece containing workDir, from its PR
+// metadata or, when none is recorded, from gh pr view for the piece's branch
+func (h *Handler) Link(workDir string) (*PRLink, error) {
+	status, err := piece.NewHandler(h.deps).Status(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get piece status: %w", err)
+	}
+	if !status.InPiece {
+		return nil, core.NewNotInPieceError()
+	}
+
+	link := &PRLink{PieceName: status.PieceName}
+	metadata, err := piece.OpenMetadataStore(h.deps, status.WorktreePath).ReadPRMetadata()
+	if err == nil && metadata.PRURL != "" {
+		link.PRNumber, link.PRURL, link.Source = metadata.PRNumber, metadata.PRURL, LinkSourceMetadata
+		return link, nil
+	}
+
+	// PR created outside monkeypuzzle, or metadata lost
+	h.configureRemote(status.RepoRoot)
+	view, err := h.github.ViewPR(status.WorktreePath, "")
+	if err != nil {
+		return nil, fmt.Errorf("no PR found for this piece (run 'mp piece pr create' first): %w", err)
+	}
+	link.PRNumber, link.PRURL, link.Source = view.Number, view.URL, LinkSourceGitHub
+	return link, nil
+}
+
+// BrowserCommand returns the command line opening url with the OS opener for goos
+func BrowserCommand(url, goos string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"open", url}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}
+	default:
+		return []string{"xdg-open", url}
+	}
+}
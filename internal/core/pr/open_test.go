@@ -0,0 +1,66 @@
+package pr_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+)
+
+func TestLink_FromMetadata(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	writeTestPRMetadata(t, fs, worktreePath)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	link, err := handler.Link(worktreePath)
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+	if link.PRURL != "https://github.com/owner/repo/pull/42" || link.PRNumber != 42 || link.Source != pr.LinkSourceMetadata {
+		t.Errorf("unexpected link: %+v", link)
+	}
+	if mockExec.WasCalled("gh", "pr", "view", "--json", "number,url") {
+		t.Error("expected gh not to be called when metadata has the URL")
+	}
+}
+
+func TestLink_FallsBackToPRView(t *testing.T) {
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+
+	worktreePath := "/pieces/test-piece"
+	setupTestPieceWorktree(t, mockExec, fs, worktreePath, "/repo")
+	mockExec.AddResponse("gh", []string{"pr", "view", "--json", "number,url"},
+		[]byte(`{"number":7,"url":"https://github.com/owner/repo/pull/7"}`), nil)
+
+	handler := pr.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	link, err := handler.Link(worktreePath)
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+	if link.PRURL != "https://github.com/owner/repo/pull/7" || link.PRNumber != 7 || link.Source != pr.LinkSourceGitHub {
+		t.Errorf("unexpected link: %+v", link)
+	}
+}
+
+func TestBrowserCommand(t *testing.T) {
+	url := "https://github.com/owner/repo/pull/42"
+	tests := map[string][]string{
+		"darwin":  {"open", url},
+		"linux":   {"xdg-open", url},
+		"windows": {"rundll32", "url.dll,FileProtocolHandler", url},
+	}
+	for goos, want := range tests {
+		if got := pr.BrowserCommand(url, goos); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", goos, want, got)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package render writes command results to stdout in the format selected
+// with --output: JSON (the default), YAML, a table, or a Go template.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// Output formats
+const (
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatTable    = "table"
+	FormatTemplate = "template"
+)
+
+// Formats are the valid --output values, template taking its text after =
+var Formats = []string{FormatJSON, FormatYAML, FormatTable, FormatTemplate + "=<template>"}
+
+// Format is a parsed --output value
+type Format struct {
+	Name string
+	// Template is set for FormatTemplate
+	Template *template.Template
+}
+
+// templateFuncs are available to --output templates besides the builtins
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": func(items []string, sep string) string {
+		return strings.Join(items, sep)
+	},
+}
+
+// Parse parses an --output value: json, yaml, table, or template=<text>.
+// An empty value is json.
+func Parse(value string) (Format, error) {
+	name, text, hasText := strings.Cut(value, "=")
+	switch name {
+	case "":
+		return Format{Name: FormatJSON}, nil
+	case FormatJSON, FormatYAML, FormatTable:
+		if hasText {
+			return Format{}, fmt.Errorf("output format %s takes no value", name)
+		}
+		return Format{Name: name}, nil
+	case FormatTemplate:
+		if text == "" {
+			return Format{}, fmt.Errorf("output format template needs a template, e.g. --output template='{{.Title}}'")
+		}
+		tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return Format{}, fmt.Errorf("invalid output template: %w", err)
+		}
+		return Format{Name: FormatTemplate, Template: tmpl}, nil
+	}
+	return Format{}, fmt.Errorf("invalid output format %q (valid: %s)", value, strings.Join(Formats, ", "))
+}
+
+// Table configures FormatTable
+type Table struct {
+	// Columns are the JSON fields shown, dotted for nested fields (default:
+	// the fields of any row holding a value or a list of values)
+	Columns []string
+	// Rows are the table's rows (default: the elements of the result, or the
+	// result itself when it is not a list). Rows of [][]string are shown as
+	// they are, under Columns.
+	Rows any
+}
+
+// Write writes v to w in the format
+func (f Format) Write(w io.Writer, v any, table Table) error {
+	switch f.Name {
+	case FormatYAML:
+		node, err := decode(v)
+		if err != nil {
+			return err
+		}
+		var b strings.Builder
+		writeYAML(&b, node, 0)
+		_, err = io.WriteString(w, b.String())
+		return err
+	case FormatTable:
+		return writeTable(w, v, table)
+	case FormatTemplate:
+		return f.writeTemplate(w, v)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeTemplate executes the template for each element of a list result,
+// or once for any other result, ending each output with a newline
+func (f Format) writeTemplate(w io.Writer, v any) error {
+	items := []any{v}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items = make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+	}
+	for _, item := range items {
+		var b bytes.Buffer
+		if err := f.Template.Execute(&b, item); err != nil {
+			return fmt.Errorf("failed to execute output template: %w", err)
+		}
+		if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+			b.WriteByte('\n')
+		}
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable writes the rows of table as columns aligned with spaces, under
+// a header of the upper-cased column names. Without columns, nothing is
+// written.
+func writeTable(w io.Writer, v any, table Table) error {
+	columns := table.Columns
+	cells, ok := table.Rows.([][]string)
+	if !ok {
+		rows := table.Rows
+		if rows == nil {
+			rows = v
+		}
+		node, err := decode(rows)
+		if err != nil {
+			return err
+		}
+		var objects []*object
+		switch n := node.(type) {
+		case []any:
+			for _, item := range n {
+				if o, ok := item.(*object); ok {
+					objects = append(objects, o)
+				}
+			}
+		case *object:
+			objects = []*object{n}
+		}
+		if len(columns) == 0 {
+			for _, o := range objects {
+				for _, key := range o.flatKeys() {
+					if !slices.Contains(columns, key) {
+						columns = append(columns, key)
+					}
+				}
+			}
+		}
+		for _, o := range objects {
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				row[i] = cell(o.lookup(column))
+			}
+			cells = append(cells, row)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range cells {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// cell formats a value for a table cell: lists are joined with ", "
+func cell(v any) string {
+	switch n := v.(type) {
+	case nil, *object:
+		return ""
+	case []any:
+		items := make([]string, 0, len(n))
+		for _, item := range n {
+			items = append(items, cell(item))
+		}
+		return strings.Join(items, ", ")
+	case string:
+		return n
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,140 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/render"
+)
+
+type tasks struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type item struct {
+	Name   string   `json:"name"`
+	Status string   `json:"status"`
+	Labels []string `json:"labels"`
+	Tasks  tasks    `json:"tasks"`
+}
+
+var items = []item{
+	{Name: "login", Status: "todo", Labels: []string{"ui", "auth"}, Tasks: tasks{Done: 1, Total: 3}},
+	{Name: "billing-api", Status: "true", Labels: []string{}, Tasks: tasks{Total: 2}},
+}
+
+func write(t *testing.T, output string, v any, table render.Table) string {
+	t.Helper()
+	format, err := render.Parse(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := format.Write(&b, v, table); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestParse(t *testing.T) {
+	for _, value := range []string{"", "json", "yaml", "table", "template={{.Name}}"} {
+		if _, err := render.Parse(value); err != nil {
+			t.Errorf("Parse(%q): %v", value, err)
+		}
+	}
+	for _, value := range []string{"xml", "table=x", "template=", "template={{.Name"} {
+		if _, err := render.Parse(value); err == nil {
+			t.Errorf("Parse(%q) should fail", value)
+		}
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	got := write(t, "json", items[:1], render.Table{})
+	want := `[
+  {
+    "name": "login",
+    "status": "todo",
+    "labels": [
+      "ui",
+      "auth"
+    ],
+    "tasks": {
+      "done": 1,
+      "total": 3
+    }
+  }
+]
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWrite_YAML(t *testing.T) {
+	got := write(t, "yaml", items, render.Table{})
+	want := `- name: login
+  status: todo
+  labels:
+    - ui
+    - auth
+  tasks:
+    done: 1
+    total: 3
+- name: billing-api
+  status: "true"
+  labels: []
+  tasks:
+    done: 0
+    total: 2
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = write(t, "yaml", map[string]any{"note": "a: b", "empty": "", "none": nil, "yes": "no"}, render.Table{})
+	want = "empty: \"\"\nnone: null\nnote: \"a: b\"\n\"yes\": \"no\"\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWrite_Table(t *testing.T) {
+	got := write(t, "table", items, render.Table{})
+	want := `NAME         STATUS  LABELS    TASKS.DONE  TASKS.TOTAL
+login        todo    ui, auth  1           3
+billing-api  true              0           2
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = write(t, "table", items, render.Table{Columns: []string{"name", "tasks.total", "missing"}})
+	want = "NAME         TASKS.TOTAL  MISSING\nlogin        3            \nbilling-api  2            \n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = write(t, "table", items, render.Table{Columns: []string{"id"}, Rows: [][]string{{"1"}, {"2"}}})
+	if got != "ID\n1\n2\n" {
+		t.Errorf("got %q", got)
+	}
+
+	if got = write(t, "table", []item{}, render.Table{}); got != "" {
+		t.Errorf("empty list: got %q", got)
+	}
+}
+
+func TestWrite_Template(t *testing.T) {
+	got := write(t, `template={{.Name}} {{.Status}} {{join .Labels ","}}`, items, render.Table{})
+	want := "login todo ui,auth\nbilling-api true \n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = write(t, "template={{json .Tasks}}", items[0], render.Table{})
+	if got != "{\"done\":1,\"total\":3}\n" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,217 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// object is a decoded JSON object, keeping its keys in the order written
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+// flatKeys returns the keys of the object's values and lists of values,
+// nested objects' keys joined with dots
+func (o *object) flatKeys() []string {
+	var keys []string
+	for _, key := range o.keys {
+		switch v := o.values[key].(type) {
+		case *object:
+			for _, nested := range v.flatKeys() {
+				keys = append(keys, key+"."+nested)
+			}
+		case []any:
+			if scalars(v) {
+				keys = append(keys, key)
+			}
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// lookup returns the value at a dotted path, or nil
+func (o *object) lookup(path string) any {
+	key, rest, nested := strings.Cut(path, ".")
+	v, ok := o.values[key]
+	if !ok {
+		return nil
+	}
+	if !nested {
+		return v
+	}
+	if child, ok := v.(*object); ok {
+		return child.lookup(rest)
+	}
+	return nil
+}
+
+// scalars reports whether a list holds no objects or lists
+func scalars(items []any) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case *object, []any:
+			return false
+		}
+	}
+	return true
+}
+
+// decode returns the JSON encoding of v decoded into *object, []any,
+// json.Number, string, bool and nil values
+func decode(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		o := &object{values: map[string]any{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, seen := o.values[key]; !seen {
+				o.keys = append(o.keys, key)
+			}
+			o.values[key] = value
+		}
+		_, err := dec.Token()
+		return o, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			item, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := dec.Token()
+		return items, err
+	}
+	return tok, nil
+}
+
+// writeYAML writes a decoded value as a YAML block at indent, ending with a
+// newline
+func writeYAML(b *strings.Builder, v any, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch n := v.(type) {
+	case *object:
+		if len(n.keys) == 0 {
+			b.WriteString(pad + "{}\n")
+			return
+		}
+		for _, key := range n.keys {
+			b.WriteString(pad + yamlScalar(key) + ":")
+			writeYAMLChild(b, n.values[key], indent+1)
+		}
+	case []any:
+		if len(n) == 0 {
+			b.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range n {
+			b.WriteString(pad + "-")
+			if o, ok := item.(*object); ok && len(o.keys) > 0 {
+				// the first key shares the dash's line
+				var nested strings.Builder
+				writeYAML(&nested, o, indent+1)
+				b.WriteString(" " + strings.TrimPrefix(nested.String(), pad+"  "))
+				continue
+			}
+			writeYAMLChild(b, item, indent+1)
+		}
+	default:
+		b.WriteString(pad + yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLChild writes a value following a key or dash: scalars and empty
+// collections on the same line, others as an indented block
+func writeYAMLChild(b *strings.Builder, v any, indent int) {
+	switch n := v.(type) {
+	case *object:
+		if len(n.keys) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+	case []any:
+		if len(n) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+		return
+	}
+	b.WriteString("\n")
+	writeYAML(b, v, indent)
+}
+
+// yamlScalar formats a scalar, double-quoting strings YAML would read as
+// something else
+func yamlScalar(v any) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(n)
+	case json.Number:
+		return n.String()
+	case string:
+		if plainString(n) {
+			return n
+		}
+		return strconv.Quote(n)
+	}
+	return fmt.Sprint(v)
+}
+
+// plainString reports whether s reads back as the same string unquoted
+func plainString(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,299 @@
+// Package selftest runs mp end to end in a throwaway repository, to check
+// that git, tmux, and mp work together on this machine.
+package selftest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Stages, in the order they run
+const (
+	StageInit    = "init"
+	StageIssue   = "issue"
+	StagePiece   = "piece"
+	StageCommit  = "commit"
+	StageUpdate  = "update"
+	StageMerge   = "merge"
+	StageCleanup = "cleanup"
+)
+
+// Stages lists every stage in order
+var Stages = []string{StageInit, StageIssue, StagePiece, StageCommit, StageUpdate, StageMerge, StageCleanup}
+
+// Stage statuses
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Options configures a self-test
+type Options struct {
+	// Binary is the mp executable under test
+	Binary string
+	// Dir is an empty scratch directory for the repository and its pieces
+	Dir string
+	// Now names the test issue, keeping it apart from earlier runs; zero
+	// means time.Now
+	Now time.Time
+}
+
+// StageResult is the outcome of one stage
+type StageResult struct {
+	Stage  string `json:"stage"`
+	Status string `json:"status"`
+	// Commands are the command lines the stage ran
+	Commands   []string `json:"commands,omitempty"`
+	DurationMS int64    `json:"duration_ms"`
+	Error      string   `json:"error,omitempty"`
+	// Warnings are the warnings mp printed, e.g. about a missing tmux
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Report is the result of a self-test
+type Report struct {
+	Passed bool   `json:"passed"`
+	Dir    string `json:"dir"`
+	// FailedStage is the stage that failed, if any; later stages are skipped
+	FailedStage string        `json:"failed_stage,omitempty"`
+	Stages      []StageResult `json:"stages"`
+}
+
+// Handler executes the selftest command
+type Handler struct {
+	deps core.Deps
+}
+
+// NewHandler creates a new selftest handler with dependencies
+func NewHandler(deps core.Deps) *Handler {
+	return &Handler{deps: deps}
+}
+
+// run is the state of a self-test shared by its stages
+type run struct {
+	h      *Handler
+	binary string
+	env    []string
+	repo   string
+	title  string
+	// worktree and session are the piece's, once created
+	worktree string
+	session  string
+	// stage collects what the running stage did
+	stage *StageResult
+}
+
+// Run creates a repository in opts.Dir and takes an issue through a piece to
+// a merge and cleanup with the mp binary, as a user would: mp init, mp issue
+// create, mp piece new, a commit, mp piece update, mp piece merge and mp
+// piece cleanup. Pieces are kept in opts.Dir too, so the user's pieces and
+// event logs are not touched. The first failing stage stops the test.
+func (h *Handler) Run(opts Options) Report {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	r := &run{
+		h:      h,
+		binary: opts.Binary,
+		env:    append(os.Environ(), "XDG_DATA_HOME="+filepath.Join(opts.Dir, "data")),
+		repo:   filepath.Join(opts.Dir, "repo"),
+		title:  "Selftest " + now.UTC().Format("20060102150405"),
+	}
+	steps := map[string]func() error{
+		StageInit:    r.init,
+		StageIssue:   r.issue,
+		StagePiece:   r.piece,
+		StageCommit:  r.commit,
+		StageUpdate:  r.update,
+		StageMerge:   r.merge,
+		StageCleanup: r.cleanup,
+	}
+
+	report := Report{Passed: true, Dir: opts.Dir, Stages: []StageResult{}}
+	for _, name := range Stages {
+		result := StageResult{Stage: name, Status: StatusSkipped}
+		if report.Passed {
+			r.stage = &result
+			start := time.Now()
+			err := steps[name]()
+			result.DurationMS = time.Since(start).Milliseconds()
+			result.Status = StatusPassed
+			if err != nil {
+				result.Status = StatusFailed
+				result.Error = err.Error()
+				report.Passed = false
+				report.FailedStage = name
+			}
+			h.report(result)
+		}
+		report.Stages = append(report.Stages, result)
+	}
+
+	// A piece left behind by a failed stage must not leave its tmux session
+	// running; its worktree goes with opts.Dir
+	if !report.Passed && r.session != "" {
+		_ = adapters.NewTmux(h.deps.Exec).KillSession(r.session)
+	}
+	return report
+}
+
+// report writes the outcome of a stage
+func (h *Handler) report(result StageResult) {
+	for _, w := range result.Warnings {
+		h.deps.Output.Write(core.Message{Type: core.MsgWarning, Content: fmt.Sprintf("%s: %s", result.Stage, w)})
+	}
+	if result.Status == StatusFailed {
+		h.deps.Output.Write(core.Message{Type: core.MsgError, Content: fmt.Sprintf("%s failed: %s", result.Stage, result.Error), Data: result})
+		return
+	}
+	h.deps.Output.Write(core.Message{Type: core.MsgSuccess, Content: fmt.Sprintf("%s (%dms)", result.Stage, result.DurationMS), Data: result})
+}
+
+func (r *run) init() error {
+	if err := r.h.deps.FS.MkdirAll(r.repo, piece.DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create %s: %w", r.repo, err)
+	}
+	_, err := r.mp(r.repo, "init", "--git", "--yes", "--name", "selftest", "--issue-provider", "markdown", "--pr-provider", "github")
+	return err
+}
+
+func (r *run) issue() error {
+	if _, err := r.mp(r.repo, "issue", "create", "--title", r.title); err != nil {
+		return err
+	}
+	return r.commitAll(r.repo, "Add selftest issue")
+}
+
+func (r *run) piece() error {
+	stdout, err := r.mp(r.repo, "issue", "list")
+	if err != nil {
+		return err
+	}
+	var issues []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(stdout, &issues); err != nil {
+		return fmt.Errorf("unexpected mp issue list output: %w", err)
+	}
+	id := ""
+	for _, i := range issues {
+		if i.Title == r.title {
+			id = i.ID
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("mp issue list does not list the issue %q", r.title)
+	}
+
+	if stdout, err = r.mp(r.repo, "piece", "new", "--issue", id); err != nil {
+		return err
+	}
+	var info piece.PieceInfo
+	if err := json.Unmarshal(stdout, &info); err != nil || info.WorktreePath == "" {
+		return fmt.Errorf("unexpected mp piece new output: %s", strings.TrimSpace(string(stdout)))
+	}
+	r.worktree, r.session = info.WorktreePath, info.SessionName
+	return nil
+}
+
+// commit commits a file in the piece, and in the main repository the issue
+// status mp piece new set
+func (r *run) commit() error {
+	path := filepath.Join(r.worktree, "selftest.txt")
+	if err := r.h.deps.FS.WriteFile(path, []byte(r.title+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := r.commitAll(r.worktree, "Add selftest.txt"); err != nil {
+		return err
+	}
+	return r.commitAll(r.repo, "Start selftest issue")
+}
+
+func (r *run) update() error {
+	_, err := r.mp(r.worktree, "piece", "update", "--yes")
+	return err
+}
+
+func (r *run) merge() error {
+	_, err := r.mp(r.worktree, "piece", "merge", "--yes")
+	return err
+}
+
+func (r *run) cleanup() error {
+	stdout, err := r.mp(r.repo, "piece", "cleanup", "--force")
+	if err != nil {
+		return err
+	}
+	var results []piece.CleanupResult
+	if err := json.Unmarshal(stdout, &results); err != nil {
+		return fmt.Errorf("unexpected mp piece cleanup output: %w", err)
+	}
+	for _, result := range results {
+		if result.WorktreePath == r.worktree && result.Cleaned() {
+			return nil
+		}
+	}
+	return fmt.Errorf("mp piece cleanup did not remove the merged piece at %s", r.worktree)
+}
+
+// mp runs the mp binary in dir, returning its stdout. Warnings it prints are
+// added to the stage; on failure, the error is what mp printed.
+func (r *run) mp(dir string, args ...string) ([]byte, error) {
+	return r.exec(dir, r.binary, args...)
+}
+
+// commitAll commits every change in dir, if there are any
+func (r *run) commitAll(dir, message string) error {
+	status, err := r.exec(dir, "git", "status", "--porcelain")
+	if err != nil || len(strings.TrimSpace(string(status))) == 0 {
+		return err
+	}
+	if _, err := r.exec(dir, "git", "add", "-A"); err != nil {
+		return err
+	}
+	_, err = r.exec(dir, "git", "commit", "-m", message)
+	return err
+}
+
+func (r *run) exec(dir, name string, args ...string) ([]byte, error) {
+	command := name
+	if name == r.binary {
+		command = "mp"
+	}
+	for _, arg := range args {
+		if strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		command += " " + arg
+	}
+	r.stage.Commands = append(r.stage.Commands, command)
+	stdout, stderr, err := r.h.deps.Exec.RunSplit(dir, r.env, name, args...)
+	var messages []string
+	for _, line := range strings.Split(string(stderr), "\n") {
+		line = strings.TrimSpace(line)
+		if warning, ok := strings.CutPrefix(line, "⚠ "); ok {
+			r.stage.Warnings = append(r.stage.Warnings, warning)
+		} else if line != "" && !strings.HasPrefix(line, "✓ ") {
+			messages = append(messages, strings.TrimPrefix(line, "Error: "))
+		}
+	}
+	if err != nil {
+		if len(messages) == 0 {
+			messages = append(messages, strings.TrimSpace(string(stdout)))
+		}
+		return stdout, fmt.Errorf("%s: %w: %s", command, err, strings.Join(messages, " "))
+	}
+	return stdout, nil
+}
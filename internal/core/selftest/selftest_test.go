@@ -0,0 +1,101 @@
+package selftest_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/selftest"
+)
+
+const worktree = "/tmp/st/data/monkeypuzzle/pieces/selftest-20260301093000"
+
+// setupLifecycle mocks a successful run of every stage with binary /bin/mp
+func setupLifecycle(t *testing.T) (*adapters.MemoryFS, *adapters.MockExec, *selftest.Handler) {
+	t.Helper()
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := selftest.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	mockExec.AddSplitResponse("/bin/mp", []string{"init", "--git", "--yes", "--name", "selftest", "--issue-provider", "markdown", "--pr-provider", "github"},
+		nil, []byte("✓ Created .monkeypuzzle/monkeypuzzle.json\n⚠ pr provider \"github\" is not usable\n"), nil)
+	mockExec.AddResponse("/bin/mp", []string{"issue", "create", "--title", "Selftest 20260301093000"}, nil, nil)
+	mockExec.AddResponse("git", []string{"status", "--porcelain"}, []byte(" M issues/selftest-20260301093000.md\n"), nil)
+	mockExec.AddResponse("git", []string{"add", "-A"}, nil, nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", "Add selftest issue"}, nil, nil)
+	mockExec.AddResponse("/bin/mp", []string{"issue", "list"},
+		[]byte(`[{"id":"selftest-20260301093000","title":"Selftest 20260301093000","status":"todo"}]`), nil)
+	mockExec.AddResponse("/bin/mp", []string{"piece", "new", "--issue", "selftest-20260301093000"},
+		[]byte(`{"name":"selftest-20260301093000","worktree_path":"`+worktree+`","session_name":"mp-piece-selftest-20260301093000"}`), nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", "Add selftest.txt"}, nil, nil)
+	mockExec.AddResponse("git", []string{"commit", "-m", "Start selftest issue"}, nil, nil)
+	mockExec.AddResponse("/bin/mp", []string{"piece", "update", "--yes"}, []byte(`{"operation":"update"}`), nil)
+	mockExec.AddResponse("/bin/mp", []string{"piece", "merge", "--yes"}, []byte(`{"operation":"merge"}`), nil)
+	mockExec.AddResponse("/bin/mp", []string{"piece", "cleanup", "--force"},
+		[]byte(`[{"piece_name":"selftest-20260301093000","worktree_path":"`+worktree+`"}]`), nil)
+	return fs, mockExec, handler
+}
+
+func run(handler *selftest.Handler) selftest.Report {
+	return handler.Run(selftest.Options{Binary: "/bin/mp", Dir: "/tmp/st", Now: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)})
+}
+
+func TestHandler_Run(t *testing.T) {
+	fs, mockExec, handler := setupLifecycle(t)
+
+	report := run(handler)
+	if !report.Passed || report.FailedStage != "" {
+		t.Fatalf("expected the self-test to pass, got %+v", report)
+	}
+	if len(report.Stages) != len(selftest.Stages) {
+		t.Fatalf("expected %d stages, got %d", len(selftest.Stages), len(report.Stages))
+	}
+	for i, stage := range report.Stages {
+		if stage.Stage != selftest.Stages[i] || stage.Status != selftest.StatusPassed {
+			t.Errorf("stage %d: expected %s passed, got %+v", i, selftest.Stages[i], stage)
+		}
+	}
+	if init := report.Stages[0]; len(init.Warnings) != 1 || !strings.Contains(init.Warnings[0], "github") {
+		t.Errorf("expected the gh warning on init, got %v", init.Warnings)
+	}
+	if issue := report.Stages[1]; issue.Commands[0] != `mp issue create --title "Selftest 20260301093000"` {
+		t.Errorf("unexpected command line %q", issue.Commands[0])
+	}
+
+	if _, err := fs.ReadFile(worktree + "/selftest.txt"); err != nil {
+		t.Errorf("expected a file to commit in the piece: %v", err)
+	}
+	for _, call := range mockExec.GetCalls() {
+		if !slices.Contains(call.Env, "XDG_DATA_HOME=/tmp/st/data") {
+			t.Errorf("expected %s %v to keep pieces in the scratch directory", call.Name, call.Args)
+		}
+	}
+}
+
+func TestHandler_Run_StopsAtFailure(t *testing.T) {
+	_, mockExec, handler := setupLifecycle(t)
+	mockExec.AddSplitResponse("/bin/mp", []string{"piece", "merge", "--yes"}, nil,
+		[]byte("Error: cannot merge: the main repository has uncommitted changes\n"), adapters.MockError("exit status 1"))
+	mockExec.AddResponse("tmux", []string{"kill-session", "-t", "mp-piece-selftest-20260301093000"}, nil, nil)
+
+	report := run(handler)
+	if report.Passed || report.FailedStage != selftest.StageMerge {
+		t.Fatalf("expected the self-test to fail at merge, got %+v", report)
+	}
+	merge := report.Stages[5]
+	if merge.Status != selftest.StatusFailed || !strings.Contains(merge.Error, "uncommitted changes") {
+		t.Errorf("expected mp's error on the merge stage, got %+v", merge)
+	}
+	if cleanup := report.Stages[6]; cleanup.Status != selftest.StatusSkipped {
+		t.Errorf("expected cleanup to be skipped, got %+v", cleanup)
+	}
+	if mockExec.WasCalled("/bin/mp", "piece", "cleanup", "--force") {
+		t.Error("expected no stage to run after the failure")
+	}
+	if !mockExec.WasCalled("tmux", "kill-session", "-t", "mp-piece-selftest-20260301093000") {
+		t.Error("expected the piece's tmux session to be killed")
+	}
+}
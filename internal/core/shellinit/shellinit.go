@@ -0,0 +1,112 @@
+// Package shellinit generates the shell integration installed with
+// eval "$(mp shell-init zsh)": an mp wrapper adding `mp cd <piece>`, a prompt
+// segment naming the current piece and issue, and tab completion.
+package shellinit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Shells lists the supported shells
+var Shells = []string{"bash", "zsh", "fish"}
+
+// PromptFunction is the shell function printing the prompt segment
+const PromptFunction = "mp_prompt_info"
+
+// Script returns the integration script for shell. piecesDir is where piece
+// worktrees live; the prompt segment is empty outside it.
+func Script(shell, piecesDir string) (string, error) {
+	switch shell {
+	case "bash", "zsh":
+		return posixScript(shell, piecesDir), nil
+	case "fish":
+		return fishScript(piecesDir), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(Shells, ", "))
+	}
+}
+
+// quote single-quotes s for POSIX shells and fish
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func posixScript(shell, piecesDir string) string {
+	return fmt.Sprintf(`# mp shell integration for %[1]s: eval "$(mp shell-init %[1]s)"
+__mp_pieces_dir=%[2]s
+
+# mp cd [piece] changes into a piece (default: the current piece's root)
+mp() {
+  if [ "$1" = cd ]; then
+    shift
+    local __mp_dir
+    __mp_dir="$(command mp piece open --print-path "$@")" || return
+    cd "$__mp_dir"
+  else
+    command mp "$@"
+  fi
+}
+
+# %[3]s prints "<piece>" or "<piece>:<issue>" inside a piece, nothing elsewhere
+%[3]s() {
+  case "$PWD/" in
+    "$__mp_pieces_dir"/*) ;;
+    *) return 0 ;;
+  esac
+  local __mp_rel="${PWD#"$__mp_pieces_dir"/}"
+  local __mp_piece="${__mp_rel%%%%/*}"
+  local __mp_gitdir __mp_issue
+  if [ -f "$__mp_pieces_dir/$__mp_piece/.git" ]; then
+    __mp_gitdir="$(sed -n 's/^gitdir: //p' "$__mp_pieces_dir/$__mp_piece/.git")"
+    __mp_issue="$(sed -n 's/.*"issue_path": *"\([^"]*\)".*/\1/p' "$__mp_gitdir/monkeypuzzle/current-issue.json" 2>/dev/null)"
+    __mp_issue="${__mp_issue##*/}"
+    __mp_issue="${__mp_issue%%.md}"
+  fi
+  if [ -n "$__mp_issue" ]; then
+    printf '%%s:%%s' "$__mp_piece" "$__mp_issue"
+  else
+    printf '%%s' "$__mp_piece"
+  fi
+}
+
+# Tab completion
+source <(command mp completion %[1]s)
+`, shell, quote(piecesDir), PromptFunction)
+}
+
+func fishScript(piecesDir string) string {
+	return fmt.Sprintf(`# mp shell integration for fish: mp shell-init fish | source
+set -g __mp_pieces_dir %[1]s
+
+# mp cd [piece] changes into a piece (default: the current piece's root)
+function mp
+    if test "$argv[1]" = cd
+        set -l dir (command mp piece open --print-path $argv[2..-1]); or return
+        cd $dir
+    else
+        command mp $argv
+    end
+end
+
+# %[2]s prints "<piece>" or "<piece>:<issue>" inside a piece, nothing elsewhere
+function %[2]s
+    string match -q -- "$__mp_pieces_dir/*" "$PWD/"; or return 0
+    set -l piece (string split -m1 / (string replace -- "$__mp_pieces_dir/" "" $PWD))[1]
+    set -l issue
+    if test -f "$__mp_pieces_dir/$piece/.git"
+        set -l gitdir (string replace -r '^gitdir: ' '' <"$__mp_pieces_dir/$piece/.git")
+        set issue (string match -r -g '"issue_path": *"([^"]*)"' <"$gitdir/monkeypuzzle/current-issue.json" 2>/dev/null)
+        set issue (string replace -r '^.*/' '' -- $issue | string replace -r '\.md$' '')
+    end
+    if test -n "$issue"
+        printf '%%s:%%s' $piece $issue
+    else
+        printf '%%s' $piece
+    end
+end
+
+# Tab completion
+command mp completion fish | source
+`, quote(piecesDir), PromptFunction)
+}
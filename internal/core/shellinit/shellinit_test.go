@@ -0,0 +1,45 @@
+package shellinit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/shellinit"
+)
+
+func TestScript(t *testing.T) {
+	for _, shell := range shellinit.Shells {
+		t.Run(shell, func(t *testing.T) {
+			script, err := shellinit.Script(shell, "/data/monkeypuzzle/pieces")
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			for _, want := range []string{
+				"'/data/monkeypuzzle/pieces'",
+				"command mp piece open --print-path",
+				shellinit.PromptFunction,
+				"mp completion " + shell,
+			} {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestScript_QuotesPiecesDir(t *testing.T) {
+	script, err := shellinit.Script("bash", "/home/o'neil/pieces")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(script, `__mp_pieces_dir='/home/o'\''neil/pieces'`) {
+		t.Errorf("expected pieces dir to be single-quoted, got:\n%s", script)
+	}
+}
+
+func TestScript_UnsupportedShell(t *testing.T) {
+	if _, err := shellinit.Script("tcsh", "/pieces"); err == nil {
+		t.Fatal("expected error for unsupported shell")
+	}
+}
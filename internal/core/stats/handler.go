@@ -0,0 +1,113 @@
+// Package stats reports on monkeypuzzle activity recorded in the events log.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// dateLayout formats timeline days
+const dateLayout = "2006-01-02"
+
+// DayWIP is the work in progress at the end of a day
+type DayWIP struct {
+	Date    string `json:"date"`
+	WIP     int    `json:"wip"`
+	Created int    `json:"created"`
+	Removed int    `json:"removed"`
+}
+
+// Report summarizes current and historical work in progress
+type Report struct {
+	Active   int      `json:"active"`
+	Limit    int      `json:"wip_limit,omitempty"`
+	Timeline []DayWIP `json:"timeline"`
+}
+
+// Handler executes the stats command
+type Handler struct {
+	deps core.Deps
+}
+
+// NewHandler creates a new stats handler with dependencies
+func NewHandler(deps core.Deps) *Handler {
+	return &Handler{deps: deps}
+}
+
+// Run builds the WIP report for the repository at repoRoot
+func (h *Handler) Run(repoRoot string) (Report, error) {
+	wip, err := piece.NewHandler(h.deps).WIP(repoRoot)
+	if err != nil {
+		return Report{}, err
+	}
+
+	logged, err := events.Read(h.deps.FS, repoRoot)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to read events log: %w", err)
+	}
+
+	report := Report{
+		Active:   wip.Active,
+		Limit:    wip.Limit,
+		Timeline: WIPTimeline(logged),
+	}
+
+	if wip.AtLimit() {
+		h.deps.Output.Write(core.Message{
+			Type:    core.MsgWarning,
+			Content: fmt.Sprintf("%d active pieces at WIP limit of %d", wip.Active, wip.Limit),
+		})
+	}
+
+	return report, nil
+}
+
+// WIPTimeline replays piece create/remove events into a per-day (UTC) WIP count,
+// including days without activity between the first and last event.
+func WIPTimeline(logged []events.Event) []DayWIP {
+	var lifecycle []events.Event
+	for _, e := range logged {
+		if e.Type == piece.EventPieceCreate || e.Type == piece.EventPieceRemove {
+			lifecycle = append(lifecycle, e)
+		}
+	}
+	if len(lifecycle) == 0 {
+		return []DayWIP{}
+	}
+	sort.SliceStable(lifecycle, func(i, j int) bool { return lifecycle[i].Time.Before(lifecycle[j].Time) })
+
+	var timeline []DayWIP
+	wip := 0
+	day := truncateDay(lifecycle[0].Time)
+	i := 0
+	for !day.After(truncateDay(lifecycle[len(lifecycle)-1].Time)) {
+		entry := DayWIP{Date: day.Format(dateLayout)}
+		next := day.AddDate(0, 0, 1)
+		for ; i < len(lifecycle) && lifecycle[i].Time.Before(next); i++ {
+			if lifecycle[i].Type == piece.EventPieceCreate {
+				entry.Created++
+				wip++
+			} else {
+				entry.Removed++
+				if wip > 0 {
+					wip--
+				}
+			}
+		}
+		entry.WIP = wip
+		timeline = append(timeline, entry)
+		day = next
+	}
+	return timeline
+}
+
+// truncateDay returns midnight UTC of t's day
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,82 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/events"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/stats"
+)
+
+func at(day, hour int) time.Time {
+	return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestWIPTimeline(t *testing.T) {
+	logged := []events.Event{
+		{Time: at(1, 9), Type: piece.EventPieceCreate, Piece: "a"},
+		{Time: at(1, 10), Type: piece.EventPieceCreate, Piece: "b"},
+		{Time: at(1, 11), Type: "pr.address", Piece: "a"},
+		{Time: at(3, 12), Type: piece.EventPieceRemove, Piece: "a"},
+		{Time: at(3, 8), Type: piece.EventPieceCreate, Piece: "c"},
+	}
+
+	timeline := stats.WIPTimeline(logged)
+
+	expected := []stats.DayWIP{
+		{Date: "2025-03-01", WIP: 2, Created: 2},
+		{Date: "2025-03-02", WIP: 2},
+		{Date: "2025-03-03", WIP: 2, Created: 1, Removed: 1},
+	}
+	if len(timeline) != len(expected) {
+		t.Fatalf("expected %d days, got %+v", len(expected), timeline)
+	}
+	for i, want := range expected {
+		if timeline[i] != want {
+			t.Errorf("day %d: expected %+v, got %+v", i, want, timeline[i])
+		}
+	}
+}
+
+func TestWIPTimeline_NoEvents(t *testing.T) {
+	if timeline := stats.WIPTimeline(nil); len(timeline) != 0 {
+		t.Errorf("expected empty timeline, got %+v", timeline)
+	}
+}
+
+func TestHandler_Run(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+
+	fs := adapters.NewMemoryFS()
+	out := adapters.NewBufferOutput()
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/a", 0755)
+	_ = fs.MkdirAll("test-data/monkeypuzzle/pieces/b", 0755)
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(`{"version":"1","pieces":{"wip_limit":2}}`), 0644)
+	_ = events.Append(fs, "/repo", events.Event{Time: at(1, 9), Type: piece.EventPieceCreate, Piece: "a"})
+
+	mockExec := adapters.NewMockExec()
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte(`worktree /repo
+branch refs/heads/main
+
+worktree /test-data/monkeypuzzle/pieces/a
+branch refs/heads/a
+
+worktree /test-data/monkeypuzzle/pieces/b
+branch refs/heads/b
+`), nil)
+
+	report, err := stats.NewHandler(core.Deps{FS: fs, Output: out, Exec: mockExec}).Run("/repo")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if report.Active != 2 || report.Limit != 2 || len(report.Timeline) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if !out.HasWarning() {
+		t.Error("expected warning when at the WIP limit")
+	}
+}
@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	initcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/init"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// UsageFilename is the local usage counter file in the monkeypuzzle data directory.
+// Usage counts are opt-in and never leave the machine.
+const UsageFilename = "usage.json"
+
+// maxErrorTypeLen caps the length of a recorded error type
+const maxErrorTypeLen = 80
+
+// CommandUsage counts runs and failures of one command
+type CommandUsage struct {
+	Runs     int `json:"runs"`
+	Failures int `json:"failures"`
+}
+
+// Usage holds local usage counters
+type Usage struct {
+	Enabled  bool                    `json:"enabled"`
+	Since    string                  `json:"since,omitempty"`
+	Commands map[string]CommandUsage `json:"commands"`
+	// Errors counts failures by error type (the leading part of the error message)
+	Errors map[string]int `json:"errors"`
+}
+
+// ErrorCount is an error type and its number of occurrences
+type ErrorCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// TopErrors returns error types ordered by descending count
+func (u Usage) TopErrors() []ErrorCount {
+	counts := make([]ErrorCount, 0, len(u.Errors))
+	for errType, n := range u.Errors {
+		counts = append(counts, ErrorCount{Type: errType, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Type < counts[j].Type
+	})
+	return counts
+}
+
+// Usage returns the local usage counters (disabled and empty if never enabled)
+func (h *Handler) Usage() (Usage, error) {
+	path, err := usagePath()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	usage := Usage{Commands: map[string]CommandUsage{}, Errors: map[string]int{}}
+	data, err := h.deps.FS.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usage, nil
+		}
+		return Usage{}, fmt.Errorf("failed to read usage file: %w", err)
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return Usage{}, fmt.Errorf("failed to parse usage file: %w", err)
+	}
+	if usage.Commands == nil {
+		usage.Commands = map[string]CommandUsage{}
+	}
+	if usage.Errors == nil {
+		usage.Errors = map[string]int{}
+	}
+	return usage, nil
+}
+
+// SetUsageTracking opts in to or out of local usage counting.
+// Opting out deletes the collected counters.
+func (h *Handler) SetUsageTracking(enabled bool) (Usage, error) {
+	if !enabled {
+		path, err := usagePath()
+		if err != nil {
+			return Usage{}, err
+		}
+		if _, err := h.deps.FS.Stat(path); err == nil {
+			if err := h.deps.FS.Remove(path); err != nil {
+				return Usage{}, fmt.Errorf("failed to remove usage file: %w", err)
+			}
+		}
+		return Usage{Commands: map[string]CommandUsage{}, Errors: map[string]int{}}, nil
+	}
+
+	usage, err := h.Usage()
+	if err != nil {
+		return Usage{}, err
+	}
+	if !usage.Enabled {
+		usage.Enabled = true
+		usage.Since = time.Now().UTC().Format(time.RFC3339)
+	}
+	return usage, h.writeUsage(usage)
+}
+
+// RecordUsage counts a run of command and, if runErr is set, its failure.
+// It does nothing unless usage counting was enabled, and never fails the command.
+func (h *Handler) RecordUsage(command string, runErr error) {
+	usage, err := h.Usage()
+	if err != nil || !usage.Enabled {
+		return
+	}
+
+	counts := usage.Commands[command]
+	counts.Runs++
+	if runErr != nil {
+		counts.Failures++
+		usage.Errors[ErrorType(runErr)]++
+	}
+	usage.Commands[command] = counts
+
+	_ = h.writeUsage(usage)
+}
+
+// ErrorType reduces an error to a stable type: the message up to its first
+// ": ", with path-like words replaced so counts group across repositories.
+func ErrorType(err error) string {
+	msg, _, _ := strings.Cut(err.Error(), ": ")
+	words := strings.Fields(msg)
+	for i, w := range words {
+		if strings.Contains(w, "/") {
+			words[i] = "<path>"
+		}
+	}
+	errType := strings.Join(words, " ")
+	if len(errType) > maxErrorTypeLen {
+		errType = errType[:maxErrorTypeLen]
+	}
+	return errType
+}
+
+func (h *Handler) writeUsage(usage Usage) error {
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+	if err := h.deps.FS.MkdirAll(filepath.Dir(path), initcmd.DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %w", err)
+	}
+	if err := h.deps.FS.WriteFile(path, append(data, '\n'), initcmd.DefaultFilePerm); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	return nil
+}
+
+func usagePath() (string, error) {
+	dataDir, err := piece.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, UsageFilename), nil
+}
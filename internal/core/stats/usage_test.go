@@ -0,0 +1,66 @@
+package stats_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/stats"
+)
+
+func newUsageHandler(t *testing.T) (*adapters.MemoryFS, *stats.Handler) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	return fs, stats.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput()})
+}
+
+func TestHandler_RecordUsage_DisabledByDefault(t *testing.T) {
+	fs, handler := newUsageHandler(t)
+
+	handler.RecordUsage("piece new", nil)
+
+	if _, err := fs.Stat("/test-data/monkeypuzzle/usage.json"); err == nil {
+		t.Error("expected nothing to be recorded before opting in")
+	}
+}
+
+func TestHandler_RecordUsage(t *testing.T) {
+	_, handler := newUsageHandler(t)
+	if _, err := handler.SetUsageTracking(true); err != nil {
+		t.Fatalf("failed to enable usage: %v", err)
+	}
+
+	handler.RecordUsage("piece new", nil)
+	handler.RecordUsage("piece new", fmt.Errorf("failed to create worktree: %w", fmt.Errorf("exit status 128")))
+	handler.RecordUsage("piece merge", fmt.Errorf("failed to create worktree: branch exists"))
+
+	usage, err := handler.Usage()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got := usage.Commands["piece new"]; got.Runs != 2 || got.Failures != 1 {
+		t.Errorf("expected 2 runs and 1 failure, got %+v", got)
+	}
+
+	top := usage.TopErrors()
+	if len(top) != 1 || top[0].Type != "failed to create worktree" || top[0].Count != 2 {
+		t.Errorf("unexpected error counts: %+v", top)
+	}
+
+	if _, err := handler.SetUsageTracking(false); err != nil {
+		t.Fatalf("failed to disable usage: %v", err)
+	}
+	usage, _ = handler.Usage()
+	if usage.Enabled || len(usage.Commands) != 0 {
+		t.Errorf("expected counters to be cleared, got %+v", usage)
+	}
+}
+
+func TestErrorType(t *testing.T) {
+	got := stats.ErrorType(fmt.Errorf("piece p1 not found at /data/pieces/p1"))
+	if got != "piece p1 not found at <path>" {
+		t.Errorf("unexpected error type %q", got)
+	}
+}
@@ -0,0 +1,238 @@
+package trace
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Placeholders for the Locations of the recording machine
+const (
+	RootPlaceholder = "{root}"
+	DataPlaceholder = "{data}"
+	HomePlaceholder = "{home}"
+)
+
+// Locations are the machine-specific directories a trace refers to. A trace
+// stores paths under them as placeholders, so it replays on another machine.
+type Locations struct {
+	// Root is the repository root, or the working directory outside one
+	Root string
+	// Data is monkeypuzzle's data directory, holding the pieces
+	Data string
+	Home string
+}
+
+// LocalLocations returns the locations of this machine for the repository
+// root root
+func LocalLocations(root string) Locations {
+	loc := Locations{Root: root}
+	loc.Data, _ = piece.DataDir()
+	loc.Home, _ = os.UserHomeDir()
+	return loc
+}
+
+// pairs returns each location with its placeholder, longest location first
+// so that nested locations are replaced by the most specific placeholder
+func (l Locations) pairs() [][2]string {
+	var pairs [][2]string
+	for _, p := range [][2]string{{l.Root, RootPlaceholder}, {l.Data, DataPlaceholder}, {l.Home, HomePlaceholder}} {
+		if p[0] != "" && p[0] != string(filepath.Separator) {
+			pairs = append(pairs, p)
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return len(pairs[i][0]) > len(pairs[j][0]) })
+	return pairs
+}
+
+// collapse replaces the locations in s with their placeholders
+func (l Locations) collapse(s string) string {
+	for _, p := range l.pairs() {
+		s = strings.ReplaceAll(s, p[0], p[1])
+	}
+	return s
+}
+
+// expand replaces the placeholders in s with the locations
+func (l Locations) expand(s string) string {
+	for _, p := range l.pairs() {
+		s = strings.ReplaceAll(s, p[1], p[0])
+	}
+	return s
+}
+
+// File is a file mp read. Files only seen in a directory listing have no
+// content.
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+}
+
+// Relocate replaces the placeholders in t with the locations of the replaying
+// machine and returns the directory to replay in
+func (t *Trace) Relocate(loc Locations) string {
+	for i := range t.Calls {
+		c := &t.Calls[i]
+		c.Dir = loc.expand(c.Dir)
+		for j := range c.Args {
+			c.Args[j] = loc.expand(c.Args[j])
+		}
+		c.Stdout = loc.expand(c.Stdout)
+		c.Stderr = loc.expand(c.Stderr)
+		c.Error = loc.expand(c.Error)
+	}
+	for i := range t.Files {
+		t.Files[i].Path = loc.expand(t.Files[i].Path)
+		t.Files[i].Content = loc.expand(t.Files[i].Content)
+	}
+	for i := range t.Dirs {
+		t.Dirs[i] = loc.expand(t.Dirs[i])
+	}
+	return filepath.Join(loc.Root, t.WorkDir)
+}
+
+// FS returns an in-memory filesystem holding the recorded files and
+// directories. What mp writes during a replay stays in memory.
+func (t *Trace) FS() core.FS {
+	mem := adapters.NewMemoryFS()
+	for _, dir := range t.Dirs {
+		_ = mem.MkdirAll(dir, 0755)
+	}
+	for _, f := range t.Files {
+		_ = mem.MkdirAll(filepath.Dir(f.Path), 0755)
+		_ = mem.WriteFile(f.Path, []byte(f.Content), 0644)
+	}
+	return mem
+}
+
+// FS returns fs recording the files and directories read through it to the
+// trace, as they were before mp wrote to them
+func (rec *Recorder) FS(fs core.FS) core.FS {
+	return &recordingFS{inner: fs, rec: rec}
+}
+
+// recordingFS records what is read through it to a Recorder
+type recordingFS struct {
+	inner core.FS
+	rec   *Recorder
+}
+
+var _ core.FS = (*recordingFS)(nil)
+
+func (f *recordingFS) MkdirAll(path string, perm os.FileMode) error {
+	f.rec.wrote(path)
+	return f.inner.MkdirAll(path, perm)
+}
+
+func (f *recordingFS) Mkdir(path string, perm os.FileMode) error {
+	f.rec.wrote(path)
+	return f.inner.Mkdir(path, perm)
+}
+
+func (f *recordingFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	f.rec.wrote(name)
+	return f.inner.WriteFile(name, data, perm)
+}
+
+func (f *recordingFS) ReadFile(name string) ([]byte, error) {
+	data, err := f.inner.ReadFile(name)
+	if err == nil {
+		content := string(data)
+		f.rec.read(name, &content, false)
+	}
+	return data, err
+}
+
+func (f *recordingFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := f.inner.Stat(name)
+	if err == nil {
+		f.rec.read(name, nil, info.IsDir())
+	}
+	return info, err
+}
+
+func (f *recordingFS) Remove(name string) error {
+	f.rec.wrote(name)
+	return f.inner.Remove(name)
+}
+
+func (f *recordingFS) Symlink(oldname, newname string) error {
+	f.rec.wrote(newname)
+	return f.inner.Symlink(oldname, newname)
+}
+
+func (f *recordingFS) Readlink(name string) (string, error) {
+	return f.inner.Readlink(name)
+}
+
+func (f *recordingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := f.inner.ReadDir(name)
+	if err == nil {
+		f.rec.read(name, nil, true)
+		for _, e := range entries {
+			f.rec.read(filepath.Join(name, e.Name()), nil, e.IsDir())
+		}
+	}
+	return entries, err
+}
+
+// read records a file with content, if known, or a directory, unless mp
+// wrote to it first
+func (rec *Recorder) read(path string, content *string, dir bool) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	path = rec.abs(path)
+	if rec.written[path] || path == rec.abs(rec.path) {
+		return
+	}
+	switch {
+	case dir:
+		if rec.dirs[path] {
+			return
+		}
+		rec.dirs[path] = true
+	case content == nil:
+		if _, ok := rec.files[path]; ok {
+			return
+		}
+		rec.files[path] = nil
+	default:
+		if rec.files[path] != nil {
+			return
+		}
+		rec.files[path] = content
+	}
+	rec.save()
+}
+
+// wrote marks path as changed by mp, so later reads are not recorded
+func (rec *Recorder) wrote(path string) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.written[rec.abs(path)] = true
+}
+
+// abs returns path cleaned and made absolute against the recorded working
+// directory
+func (rec *Recorder) abs(path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(rec.loc.Root, rec.trace.WorkDir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
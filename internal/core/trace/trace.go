@@ -0,0 +1,347 @@
+// Package trace records the external commands mp runs, with their output, and
+// the files it reads to a file, and replays them from it: a recorded trace
+// reproduces a bug report without the reporter's checkout, git, gh, or tmux.
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/meta"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/redact"
+)
+
+// Environment variables naming the trace file to record to or replay from
+const (
+	RecordEnv = "MP_RECORD"
+	ReplayEnv = "MP_REPLAY"
+)
+
+// Trace is the commands one mp invocation ran, in order, and the files it
+// read. Paths under the recording's Locations are stored as placeholders.
+type Trace struct {
+	meta.APIInfo
+	// Command is the mp command line that was recorded
+	Command string `json:"command"`
+	// WorkDir is the directory mp ran in, relative to the repository root
+	WorkDir string `json:"work_dir,omitempty"`
+	Calls   []Call `json:"calls"`
+	// Files are the files mp read, as they were before it wrote to them, and
+	// Dirs the directories it looked at
+	Files []File   `json:"files,omitempty"`
+	Dirs  []string `json:"dirs,omitempty"`
+}
+
+// Call is one command and its result. Commands run with combined output
+// have it all in Stdout.
+type Call struct {
+	Name   string   `json:"name"`
+	Args   []string `json:"args"`
+	Dir    string   `json:"dir,omitempty"`
+	Stdout string   `json:"stdout,omitempty"`
+	Stderr string   `json:"stderr,omitempty"`
+	// Error is the error the command failed with, ExitCode its exit code;
+	// NotFound means the executable was not found
+	Error    string `json:"error,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty"`
+	NotFound bool   `json:"not_found,omitempty"`
+}
+
+// Parse decodes a trace file
+func Parse(data []byte) (*Trace, error) {
+	var t Trace
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid trace: %w", err)
+	}
+	return &t, nil
+}
+
+// newCall records the result of running name with args in dir, with secrets
+// masked by r and paths under loc replaced by placeholders
+func newCall(r *redact.Redactor, loc Locations, dir, name string, args []string, stdout, stderr []byte, err error) Call {
+	clean := func(s string) string { return loc.collapse(r.String(s)) }
+	c := Call{Name: name, Dir: clean(dir), Args: make([]string, len(args))}
+	for i, arg := range args {
+		c.Args[i] = clean(arg)
+	}
+	c.Stdout = clean(string(stdout))
+	c.Stderr = clean(string(stderr))
+	if err != nil {
+		c.Error = clean(err.Error())
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			c.ExitCode = exitErr.ExitCode()
+		}
+		c.NotFound = errors.Is(err, exec.ErrNotFound)
+	}
+	return c
+}
+
+// err returns the error the call failed with, keeping its exit code and
+// whether the executable was missing
+func (c Call) err() error {
+	if c.Error == "" {
+		return nil
+	}
+	return &replayError{msg: c.Error, code: c.ExitCode, notFound: c.NotFound}
+}
+
+// replayError is a recorded error
+type replayError struct {
+	msg      string
+	code     int
+	notFound bool
+}
+
+func (e *replayError) Error() string { return e.msg }
+
+// ExitCode returns the recorded exit code
+func (e *replayError) ExitCode() int { return e.code }
+
+// Is reports a missing executable as exec.ErrNotFound
+func (e *replayError) Is(target error) bool {
+	return e.notFound && target == exec.ErrNotFound
+}
+
+var _ core.Exec = (*Recorder)(nil)
+
+// Recorder wraps a core.Exec, appending every command and its result to a
+// trace file, and records the files read through FS. The file is rewritten
+// after each command or file, so a trace survives mp exiting early. Secrets in
+// arguments, output, and files are masked; environments and stdin are not
+// recorded.
+type Recorder struct {
+	inner  core.Exec
+	fs     core.FS
+	path   string
+	loc    Locations
+	redact *redact.Redactor
+
+	mu    sync.Mutex
+	trace Trace
+	// files holds the recorded content of each file by path, nil for files
+	// only seen in a directory listing; written holds the paths mp changed
+	files   map[string]*string
+	dirs    map[string]bool
+	written map[string]bool
+}
+
+// Record returns inner recording to the trace file at path, for the mp
+// command line command run in workDir. Paths under loc are recorded as
+// placeholders.
+func Record(inner core.Exec, fs core.FS, path, command, workDir string, loc Locations, r *redact.Redactor) *Recorder {
+	rel, err := filepath.Rel(loc.Root, workDir)
+	if err != nil || rel == "." {
+		rel = ""
+	}
+	return &Recorder{
+		inner:   inner,
+		fs:      fs,
+		path:    path,
+		loc:     loc,
+		redact:  r,
+		trace:   Trace{APIInfo: meta.Info(), Command: loc.collapse(r.String(command)), WorkDir: rel, Calls: []Call{}},
+		files:   map[string]*string{},
+		dirs:    map[string]bool{},
+		written: map[string]bool{},
+	}
+}
+
+// Run executes and records a command
+func (rec *Recorder) Run(name string, args ...string) ([]byte, error) {
+	out, err := rec.inner.Run(name, args...)
+	rec.add("", name, args, out, nil, err)
+	return out, err
+}
+
+// RunWithDir executes and records a command in dir
+func (rec *Recorder) RunWithDir(dir, name string, args ...string) ([]byte, error) {
+	out, err := rec.inner.RunWithDir(dir, name, args...)
+	rec.add(dir, name, args, out, nil, err)
+	return out, err
+}
+
+// RunWithEnv executes and records a command in dir with env
+func (rec *Recorder) RunWithEnv(dir string, env []string, name string, args ...string) ([]byte, error) {
+	out, err := rec.inner.RunWithEnv(dir, env, name, args...)
+	rec.add(dir, name, args, out, nil, err)
+	return out, err
+}
+
+// RunSplit executes and records a command, returning stdout and stderr
+// separately
+func (rec *Recorder) RunSplit(dir string, env []string, name string, args ...string) ([]byte, []byte, error) {
+	stdout, stderr, err := rec.inner.RunSplit(dir, env, name, args...)
+	rec.add(dir, name, args, stdout, stderr, err)
+	return stdout, stderr, err
+}
+
+// RunStream executes a long-lived command, recording the lines it wrote
+func (rec *Recorder) RunStream(opts core.StreamOptions, name string, args ...string) error {
+	var stdout, stderr strings.Builder
+	emitStdout, emitStderr := opts.Stdout, opts.Stderr
+	opts.Stdout = func(line string) {
+		stdout.WriteString(line + "\n")
+		if emitStdout != nil {
+			emitStdout(line)
+		}
+	}
+	opts.Stderr = func(line string) {
+		stderr.WriteString(line + "\n")
+		if emitStderr != nil {
+			emitStderr(line)
+		}
+	}
+	err := rec.inner.RunStream(opts, name, args...)
+	rec.add(opts.Dir, name, args, []byte(stdout.String()), []byte(stderr.String()), err)
+	return err
+}
+
+// add appends a call to the trace and writes the trace file
+func (rec *Recorder) add(dir, name string, args []string, stdout, stderr []byte, err error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.trace.Calls = append(rec.trace.Calls, newCall(rec.redact, rec.loc, dir, name, args, stdout, stderr, err))
+	rec.save()
+}
+
+// save writes the trace file, readable only by the user as files and output
+// may hold private data. A trace that cannot be written must not fail the
+// command, so write errors are dropped. The caller holds rec.mu.
+func (rec *Recorder) save() {
+	rec.trace.Files = rec.trace.Files[:0]
+	for _, path := range sortedKeys(rec.files) {
+		f := File{Path: rec.loc.collapse(path)}
+		if content := rec.files[path]; content != nil {
+			f.Content = rec.loc.collapse(rec.redact.String(*content))
+		}
+		rec.trace.Files = append(rec.trace.Files, f)
+	}
+	rec.trace.Dirs = rec.trace.Dirs[:0]
+	for _, dir := range sortedKeys(rec.dirs) {
+		rec.trace.Dirs = append(rec.trace.Dirs, rec.loc.collapse(dir))
+	}
+	data, err := json.MarshalIndent(rec.trace, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = rec.fs.WriteFile(rec.path, append(data, '\n'), 0600)
+}
+
+var _ core.Exec = (*Replayer)(nil)
+
+// Replayer implements core.Exec from a trace, running nothing. A command is
+// answered by the first recorded call with the same name and arguments not
+// answered yet; once all are used, the last one answers again, so polling
+// commands replay their final result. A redacted part of a recorded argument
+// matches any text. Directories are not matched, as they differ between
+// machines. A command the trace does not have fails.
+type Replayer struct {
+	mu    sync.Mutex
+	calls []Call
+	used  []bool
+}
+
+// Replay returns a core.Exec answering commands from t
+func Replay(t *Trace) *Replayer {
+	return &Replayer{calls: t.Calls, used: make([]bool, len(t.Calls))}
+}
+
+// Run replays a command
+func (rep *Replayer) Run(name string, args ...string) ([]byte, error) {
+	stdout, stderr, err := rep.RunSplit("", nil, name, args...)
+	return append(stdout, stderr...), err
+}
+
+// RunWithDir replays a command
+func (rep *Replayer) RunWithDir(dir, name string, args ...string) ([]byte, error) {
+	return rep.Run(name, args...)
+}
+
+// RunWithEnv replays a command
+func (rep *Replayer) RunWithEnv(dir string, env []string, name string, args ...string) ([]byte, error) {
+	return rep.Run(name, args...)
+}
+
+// RunSplit replays a command, returning stdout and stderr separately
+func (rep *Replayer) RunSplit(dir string, env []string, name string, args ...string) ([]byte, []byte, error) {
+	call, ok := rep.next(name, args)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s %s was not recorded in the trace", name, strings.Join(args, " "))
+	}
+	return []byte(call.Stdout), []byte(call.Stderr), call.err()
+}
+
+// RunStream replays a long-lived command, passing its recorded stdout, then
+// its stderr, to the callbacks
+func (rep *Replayer) RunStream(opts core.StreamOptions, name string, args ...string) error {
+	call, ok := rep.next(name, args)
+	if !ok {
+		return fmt.Errorf("%s %s was not recorded in the trace", name, strings.Join(args, " "))
+	}
+	emit(call.Stdout, opts.Stdout)
+	emit(call.Stderr, opts.Stderr)
+	return call.err()
+}
+
+// next returns the recorded call answering name with args
+func (rep *Replayer) next(name string, args []string) (Call, bool) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	last := -1
+	for i, c := range rep.calls {
+		if c.Name != name || !argsMatch(c.Args, args) {
+			continue
+		}
+		if !rep.used[i] {
+			rep.used[i] = true
+			return c, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return Call{}, false
+	}
+	return rep.calls[last], true
+}
+
+// argsMatch reports whether args match the recorded ones, where a redacted
+// part of a recorded argument matches any text
+func argsMatch(recorded, args []string) bool {
+	if len(recorded) != len(args) {
+		return false
+	}
+	for i, rec := range recorded {
+		if rec == args[i] {
+			continue
+		}
+		if !strings.Contains(rec, redact.Placeholder) {
+			return false
+		}
+		parts := strings.Split(rec, redact.Placeholder)
+		for j, part := range parts {
+			parts[j] = regexp.QuoteMeta(part)
+		}
+		if !regexp.MustCompile("(?s)^" + strings.Join(parts, ".*") + "$").MatchString(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// emit passes each line of output to fn
+func emit(output string, fn func(string)) {
+	if fn == nil || output == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		fn(line)
+	}
+}
@@ -0,0 +1,253 @@
+// Package watch polls issue, piece, and PR state and reports changes as events,
+// so dashboards and agents can react without running commands in a loop.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+)
+
+// Event types
+const (
+	EventIssueCreated = "issue.created"
+	EventIssueStatus  = "issue.status"
+	EventIssueRemoved = "issue.removed"
+	EventPieceCreated = "piece.created"
+	EventPieceRemoved = "piece.removed"
+	EventPROpened     = "pr.opened"
+	EventPRState      = "pr.state"
+	EventPRMerged     = "pr.merged"
+)
+
+// PRMerged is the state gh reports for merged PRs
+const PRMerged = "MERGED"
+
+// Default polling intervals; PRs are polled less often since each costs a gh call
+const (
+	DefaultInterval   = 2 * time.Second
+	DefaultPRInterval = 30 * time.Second
+)
+
+// Event is a state change
+type Event struct {
+	Time  time.Time `json:"time"`
+	Type  string    `json:"type"`
+	Issue string    `json:"issue,omitempty"`
+	Piece string    `json:"piece,omitempty"`
+	PR    int       `json:"pr,omitempty"`
+	From  string    `json:"from,omitempty"`
+	To    string    `json:"to,omitempty"`
+}
+
+// PRState is the state of a piece's PR
+type PRState struct {
+	Number int    `json:"number"`
+	State  string `json:"state"`
+}
+
+// Snapshot is the watched state at one point in time
+type Snapshot struct {
+	// Issues maps issue paths to their status
+	Issues map[string]string `json:"issues"`
+	// Pieces holds the names of existing pieces
+	Pieces map[string]bool `json:"pieces"`
+	// PRs maps piece names to their PR; nil when PRs were not polled
+	PRs map[string]PRState `json:"prs,omitempty"`
+}
+
+// Options configures Watch
+type Options struct {
+	// Interval is the delay between issue and piece polls (default: DefaultInterval)
+	Interval time.Duration
+	// PRInterval is the delay between PR polls (default: DefaultPRInterval)
+	PRInterval time.Duration
+	// NoPRs disables PR polling
+	NoPRs bool
+}
+
+// Handler watches repository state
+type Handler struct {
+	deps   core.Deps
+	pieces *piece.Handler
+	github *adapters.GitHub
+}
+
+// NewHandler creates a new watch handler with dependencies
+func NewHandler(deps core.Deps) *Handler {
+	return &Handler{
+		deps:   deps,
+		pieces: piece.NewHandler(deps),
+		github: adapters.NewGitHub(deps.Exec),
+	}
+}
+
+// Watch polls the state of repoRoot until ctx is done, calling emit with each
+// change. The initial state produces no events. Polling errors are reported as
+// warnings and retried on the next poll; an error from emit stops watching.
+func (h *Handler) Watch(ctx context.Context, repoRoot string, opts Options, emit func(Event) error) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.PRInterval <= 0 {
+		opts.PRInterval = DefaultPRInterval
+	}
+
+	prev, err := h.Snapshot(repoRoot, !opts.NoPRs)
+	if err != nil {
+		return err
+	}
+	lastPRPoll := time.Now()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			withPRs := !opts.NoPRs && now.Sub(lastPRPoll) >= opts.PRInterval
+			next, err := h.Snapshot(repoRoot, withPRs)
+			if err != nil {
+				h.deps.Output.Write(core.Message{
+					Type:    core.MsgWarning,
+					Content: fmt.Sprintf("Failed to poll state: %v", err),
+				})
+				continue
+			}
+			if withPRs {
+				lastPRPoll = now
+			}
+
+			for _, e := range Diff(prev, next, now.UTC()) {
+				if err := emit(e); err != nil {
+					return err
+				}
+			}
+			if next.PRs == nil {
+				next.PRs = prev.PRs
+			}
+			prev = next
+		}
+	}
+}
+
+// Snapshot reads the current issue statuses and pieces of repoRoot and,
+// with withPRs, the state of each piece's PR.
+func (h *Handler) Snapshot(repoRoot string, withPRs bool) (Snapshot, error) {
+	snap := Snapshot{Issues: map[string]string{}, Pieces: map[string]bool{}}
+
+	issues, err := issue.NewHandler(h.deps, repoRoot).List("")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, i := range issues {
+		snap.Issues[i.Path] = i.Status
+	}
+
+	names, err := h.pieces.ActivePieces(repoRoot)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, name := range names {
+		snap.Pieces[name] = true
+	}
+
+	if withPRs {
+		snap.PRs = h.prStates(repoRoot, names)
+	}
+	return snap, nil
+}
+
+// prStates looks up the PR of each piece that has one
+func (h *Handler) prStates(repoRoot string, names []string) map[string]PRState {
+	states := map[string]PRState{}
+	h.github.SetHost(piece.ReadRemoteConfig(repoRoot, h.deps.FS).Host)
+	dataDir, err := piece.DataDir()
+	if err != nil {
+		return states
+	}
+
+	for _, name := range names {
+		worktreePath := filepath.Join(dataDir, "pieces", name)
+		metadata, err := piece.OpenMetadataStore(h.deps, worktreePath).ReadPRMetadata()
+		if err != nil || metadata.PRNumber == 0 {
+			continue
+		}
+		state, err := h.github.GetPRStatus(worktreePath, metadata.PRNumber)
+		if err != nil {
+			continue
+		}
+		states[name] = PRState{Number: metadata.PRNumber, State: state}
+	}
+	return states
+}
+
+// Diff returns the events that turn prev into next, stamped with at.
+// PR changes are only reported when both snapshots include PRs.
+func Diff(prev, next Snapshot, at time.Time) []Event {
+	var out []Event
+	add := func(e Event) {
+		e.Time = at
+		out = append(out, e)
+	}
+
+	for _, path := range sortedKeys(next.Issues) {
+		status := next.Issues[path]
+		old, existed := prev.Issues[path]
+		switch {
+		case !existed:
+			add(Event{Type: EventIssueCreated, Issue: path, To: status})
+		case old != status:
+			add(Event{Type: EventIssueStatus, Issue: path, From: old, To: status})
+		}
+	}
+	for _, path := range sortedKeys(prev.Issues) {
+		if _, ok := next.Issues[path]; !ok {
+			add(Event{Type: EventIssueRemoved, Issue: path, From: prev.Issues[path]})
+		}
+	}
+
+	for _, name := range sortedKeys(next.Pieces) {
+		if !prev.Pieces[name] {
+			add(Event{Type: EventPieceCreated, Piece: name})
+		}
+	}
+	for _, name := range sortedKeys(prev.Pieces) {
+		if !next.Pieces[name] {
+			add(Event{Type: EventPieceRemoved, Piece: name})
+		}
+	}
+
+	if prev.PRs != nil && next.PRs != nil {
+		for _, name := range sortedKeys(next.PRs) {
+			pr := next.PRs[name]
+			old, existed := prev.PRs[name]
+			switch {
+			case !existed || old.Number != pr.Number:
+				add(Event{Type: EventPROpened, Piece: name, PR: pr.Number, To: pr.State})
+			case old.State != pr.State && pr.State == PRMerged:
+				add(Event{Type: EventPRMerged, Piece: name, PR: pr.Number, From: old.State, To: pr.State})
+			case old.State != pr.State:
+				add(Event{Type: EventPRState, Piece: name, PR: pr.Number, From: old.State, To: pr.State})
+			}
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
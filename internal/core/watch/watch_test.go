@@ -0,0 +1,86 @@
+package watch_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/adapters"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/piece"
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/watch"
+)
+
+const testConfig = `{"version":"1","issues":{"provider":"markdown","config":{"directory":"issues"}},"pr":{"provider":"github"}}`
+
+func TestHandler_Snapshot(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/test-data")
+	fs := adapters.NewMemoryFS()
+	mockExec := adapters.NewMockExec()
+	handler := watch.NewHandler(core.Deps{FS: fs, Output: adapters.NewBufferOutput(), Exec: mockExec})
+
+	_ = fs.MkdirAll("repo/.monkeypuzzle", 0755)
+	_ = fs.WriteFile("repo/.monkeypuzzle/monkeypuzzle.json", []byte(testConfig), 0644)
+	_ = fs.MkdirAll("repo/issues", 0755)
+	_ = fs.WriteFile("repo/issues/feature.md", []byte("---\ntitle: Feature\nstatus: in-progress\n---\n"), 0644)
+
+	worktree := "/test-data/monkeypuzzle/pieces/p1"
+	_ = fs.MkdirAll(worktree, 0755)
+	mockExec.AddResponse("git", []string{"rev-parse", "--git-dir"}, []byte("/repo/.git/worktrees/p1\n"), nil)
+	mockExec.AddResponse("git", []string{"worktree", "list", "--porcelain"}, []byte("worktree /repo\nbranch refs/heads/main\n\nworktree "+worktree+"\nbranch refs/heads/p1\n"), nil)
+	store := piece.NewMetadataStore(fs, "/repo/.git/worktrees/p1", worktree)
+	_ = store.WritePRMetadata(piece.PRMetadata{PRNumber: 7, Branch: "p1"})
+	mockExec.AddResponse("gh", []string{"pr", "view", "7", "--json", "state", "--jq", ".state"}, []byte("OPEN\n"), nil)
+
+	snap, err := handler.Snapshot("/repo", true)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := watch.Snapshot{
+		Issues: map[string]string{"issues/feature.md": "in-progress"},
+		Pieces: map[string]bool{"p1": true},
+		PRs:    map[string]watch.PRState{"p1": {Number: 7, State: "OPEN"}},
+	}
+	if !reflect.DeepEqual(snap, want) {
+		t.Errorf("Snapshot() = %+v, want %+v", snap, want)
+	}
+
+	withoutPRs, _ := handler.Snapshot("/repo", false)
+	if withoutPRs.PRs != nil {
+		t.Errorf("expected no PRs when not polled, got %+v", withoutPRs.PRs)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	prev := watch.Snapshot{
+		Issues: map[string]string{"issues/a.md": "todo", "issues/b.md": "done"},
+		Pieces: map[string]bool{"p1": true, "p2": true},
+		PRs:    map[string]watch.PRState{"p1": {Number: 7, State: "OPEN"}},
+	}
+	next := watch.Snapshot{
+		Issues: map[string]string{"issues/a.md": "in-progress", "issues/c.md": "todo"},
+		Pieces: map[string]bool{"p1": true, "p3": true},
+		PRs:    map[string]watch.PRState{"p1": {Number: 7, State: "MERGED"}, "p3": {Number: 9, State: "OPEN"}},
+	}
+
+	want := []watch.Event{
+		{Time: at, Type: watch.EventIssueStatus, Issue: "issues/a.md", From: "todo", To: "in-progress"},
+		{Time: at, Type: watch.EventIssueCreated, Issue: "issues/c.md", To: "todo"},
+		{Time: at, Type: watch.EventIssueRemoved, Issue: "issues/b.md", From: "done"},
+		{Time: at, Type: watch.EventPieceCreated, Piece: "p3"},
+		{Time: at, Type: watch.EventPieceRemoved, Piece: "p2"},
+		{Time: at, Type: watch.EventPRMerged, Piece: "p1", PR: 7, From: "OPEN", To: "MERGED"},
+		{Time: at, Type: watch.EventPROpened, Piece: "p3", PR: 9, To: "OPEN"},
+	}
+	if got := watch.Diff(prev, next, at); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	next.PRs = nil
+	for _, e := range watch.Diff(prev, next, at) {
+		if e.PR != 0 {
+			t.Errorf("expected no PR events without polled PRs, got %+v", e)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+//go:build integration
+
+package gitfake
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ghShim dispatches gh invocations to canned responses. Each response file holds
+// the argument glob on line 1, the exit code on line 2, and the output after that.
+// Calls are appended to calls.log.
+const ghShim = `#!/bin/sh
+args="$*"
+dir="$(dirname "$0")"
+printf '%s\n' "$args" >> "$dir/calls.log"
+for f in "$dir"/responses/*; do
+  [ -e "$f" ] || continue
+  pattern="$(head -n 1 "$f")"
+  case "$args" in
+    $pattern)
+      tail -n +3 "$f"
+      exit "$(sed -n 2p "$f")"
+      ;;
+  esac
+done
+echo "gh shim: no response for: $args" >&2
+exit 1
+`
+
+// GH is a fake gh CLI placed first on PATH
+type GH struct {
+	t   *testing.T
+	dir string
+	n   int
+}
+
+// InstallGH puts a gh shim first on PATH for the rest of the test
+func InstallGH(t *testing.T) *GH {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "responses"), 0755); err != nil {
+		t.Fatalf("gitfake: failed to create gh shim dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(ghShim), 0755); err != nil {
+		t.Fatalf("gitfake: failed to write gh shim: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return &GH{t: t, dir: dir}
+}
+
+// AddResponse makes gh print output and exit with exitCode when its space-joined
+// arguments match the shell glob pattern. Earlier responses take precedence.
+func (g *GH) AddResponse(pattern, output string, exitCode int) {
+	g.t.Helper()
+	g.n++
+	path := filepath.Join(g.dir, "responses", fmt.Sprintf("%04d", g.n))
+	content := fmt.Sprintf("%s\n%d\n%s", pattern, exitCode, output)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		g.t.Fatalf("gitfake: failed to write gh response: %v", err)
+	}
+}
+
+// PRMerged answers PR merge status queries for PR number
+func (g *GH) PRMerged(number int, merged bool) {
+	mergedAt := "null"
+	if merged {
+		mergedAt = `"2025-01-01T00:00:00Z"`
+	}
+	g.AddResponse(fmt.Sprintf("pr view %d --json mergedAt*", number), fmt.Sprintf(`{"mergedAt":%s}`, mergedAt)+"\n", 0)
+}
+
+// MergedPRForBranch answers merged-PR lookups by head branch. Number 0 means none.
+func (g *GH) MergedPRForBranch(branch string, number int) {
+	output := "[]"
+	if number > 0 {
+		output = fmt.Sprintf(`[{"number":%d}]`, number)
+	}
+	g.AddResponse(fmt.Sprintf("pr list --head %s --state merged *", branch), output+"\n", 0)
+}
+
+// Unauthenticated makes every gh call fail as if no credentials were configured
+func (g *GH) Unauthenticated() {
+	g.AddResponse("*", "To get started with GitHub CLI, please run:  gh auth login\n", 4)
+}
+
+// Calls returns the argument lists gh was invoked with, space-joined
+func (g *GH) Calls() []string {
+	g.t.Helper()
+	data, err := os.ReadFile(filepath.Join(g.dir, "calls.log"))
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
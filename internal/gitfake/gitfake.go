@@ -0,0 +1,147 @@
+//go:build integration
+
+// Package gitfake provides a scriptable stand-in for a git host in integration
+// tests: a local bare repository acting as origin, and an optional gh shim that
+// answers PR queries with canned responses. Push, ls-remote, fetch, and PR
+// detection paths then run end to end without network access or the gh CLI.
+//
+// Build with -tags integration.
+package gitfake
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Server is a bare repository serving as the origin remote
+type Server struct {
+	t *testing.T
+	// Dir holds the bare repository and clones
+	Dir string
+	// BareDir is the bare repository; its path is the remote URL
+	BareDir string
+	clones  int
+}
+
+// NewServer creates a bare repository whose main branch has one initial commit
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+	dir := t.TempDir()
+	s := &Server{t: t, Dir: dir, BareDir: filepath.Join(dir, "origin.git")}
+
+	s.git(dir, "init", "--bare", "--initial-branch=main", s.BareDir)
+
+	seed := filepath.Join(dir, "seed")
+	s.git(dir, "clone", s.BareDir, seed)
+	s.configure(seed)
+	s.Commit(seed, "README.md", "# fixture\n", "initial commit")
+	s.git(seed, "push", "origin", "HEAD:main")
+	return s
+}
+
+// URL returns the remote URL of the server
+func (s *Server) URL() string {
+	return s.BareDir
+}
+
+// Clone clones the server into a new directory named name, with a committer identity set
+func (s *Server) Clone(name string) string {
+	s.t.Helper()
+	path := filepath.Join(s.Dir, name)
+	s.git(s.Dir, "clone", s.BareDir, path)
+	s.configure(path)
+	return path
+}
+
+// Commit writes file in the clone at dir and commits it, returning the new commit
+func (s *Server) Commit(dir, file, content, message string) string {
+	s.t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+		s.t.Fatalf("gitfake: failed to write %s: %v", file, err)
+	}
+	s.git(dir, "add", file)
+	s.git(dir, "commit", "-m", message)
+	return s.Git(dir, "rev-parse", "HEAD")
+}
+
+// PushCommit adds a commit to branch on the server from a separate clone,
+// as another contributor would
+func (s *Server) PushCommit(branch, file, content string) string {
+	s.t.Helper()
+	dir := s.scratchClone()
+	s.git(dir, "checkout", branch)
+	commit := s.Commit(dir, file, content, "remote change to "+file)
+	s.git(dir, "push", "origin", branch)
+	return commit
+}
+
+// RewriteBranch replaces branch on the server with a single new commit on top of
+// main, as a force-push would
+func (s *Server) RewriteBranch(branch string) string {
+	s.t.Helper()
+	dir := s.scratchClone()
+	s.git(dir, "checkout", "-b", branch, "origin/main")
+	commit := s.Commit(dir, "rewritten.txt", "rewritten\n", "rewrite "+branch)
+	s.git(dir, "push", "--force", "origin", branch)
+	return commit
+}
+
+// DeleteBranch removes branch from the server, as merging a PR with branch deletion would
+func (s *Server) DeleteBranch(branch string) {
+	s.t.Helper()
+	s.git(s.Dir, "--git-dir", s.BareDir, "branch", "-D", branch)
+}
+
+// Branches returns the branches on the server
+func (s *Server) Branches() []string {
+	s.t.Helper()
+	out := s.Git(s.Dir, "--git-dir", s.BareDir, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// BranchCommit returns the commit branch points to on the server, or "" if it doesn't exist
+func (s *Server) BranchCommit(branch string) string {
+	s.t.Helper()
+	cmd := exec.Command("git", "--git-dir", s.BareDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Git runs git in dir and returns its trimmed output, failing the test on error
+func (s *Server) Git(dir string, args ...string) string {
+	s.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		s.t.Fatalf("gitfake: git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// scratchClone clones the server into a fresh directory for server-side changes
+func (s *Server) scratchClone() string {
+	s.clones++
+	return s.Clone(fmt.Sprintf("scratch-%d", s.clones))
+}
+
+func (s *Server) git(dir string, args ...string) {
+	s.t.Helper()
+	s.Git(dir, args...)
+}
+
+func (s *Server) configure(dir string) {
+	s.t.Helper()
+	s.git(dir, "config", "user.email", "test@example.com")
+	s.git(dir, "config", "user.name", "Test User")
+}
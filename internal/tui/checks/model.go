@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	prcmd "github.com/jewell-lgtm/monkeypuzzle/internal/core/pr"
+)
+
+// PollFunc fetches the current CI status
+type PollFunc func() (*prcmd.ChecksResult, error)
+
+type Model struct {
+	Result    *prcmd.ChecksResult
+	Err       error
+	Cancelled bool
+	poll      PollFunc
+	interval  time.Duration
+}
+
+type resultMsg struct {
+	result *prcmd.ChecksResult
+	err    error
+}
+
+func New(poll PollFunc, interval time.Duration) Model {
+	return Model{poll: poll, interval: interval}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.pollCmd()
+}
+
+func (m Model) pollCmd() tea.Cmd {
+	return func() tea.Msg {
+		result, err := m.poll()
+		return resultMsg{result: result, err: err}
+	}
+}
+
+// Done returns true once every check has finished. It keeps polling while no
+// checks are reported, as CI may not have picked up the PR yet.
+func (m Model) Done() bool {
+	return m.Result != nil && m.Result.State != prcmd.ChecksPending && m.Result.State != prcmd.ChecksNone
+}
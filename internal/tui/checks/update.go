@@ -0,0 +1,31 @@
+package checks
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			m.Cancelled = true
+			return m, tea.Quit
+		}
+	case resultMsg:
+		if msg.err != nil {
+			m.Err = msg.err
+			return m, tea.Quit
+		}
+		m.Result = msg.result
+		if m.Done() {
+			return m, tea.Quit
+		}
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg {
+			return m.pollCmd()()
+		})
+	}
+	return m, nil
+}
@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/pkg/styles"
+)
+
+func (m Model) View() string {
+	if m.Err != nil {
+		return fmt.Sprintf("✗ %v\n", m.Err)
+	}
+	if m.Result == nil {
+		return styles.Subtle.Render("Fetching checks...\n")
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render(fmt.Sprintf("PR #%d checks", m.Result.PRNumber)))
+	b.WriteString("\n\n")
+
+	for _, c := range m.Result.Checks {
+		b.WriteString(fmt.Sprintf("  %s %s\n", bucketIcon(c.Bucket), c.Name))
+	}
+	if len(m.Result.Checks) == 0 {
+		b.WriteString(styles.Subtle.Render("  No checks reported"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	summary := fmt.Sprintf("%d passed • %d failed • %d pending", m.Result.Passed, m.Result.Failed, m.Result.Pending)
+	if m.Done() {
+		b.WriteString(styles.Label.Render(fmt.Sprintf("%s (%s)", summary, m.Result.State)))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(styles.Subtle.Render(summary + " • q to quit"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func bucketIcon(bucket string) string {
+	switch bucket {
+	case "pass":
+		return styles.Success.Render("✓")
+	case "fail", "cancel":
+		return "✗"
+	case "skipping":
+		return styles.Subtle.Render("-")
+	default:
+		return styles.Subtle.Render("•")
+	}
+}
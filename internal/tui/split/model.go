@@ -0,0 +1,39 @@
+package split
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jewell-lgtm/monkeypuzzle/internal/core/issue"
+)
+
+type Model struct {
+	Issue     string
+	Tasks     []issue.Task
+	Cursor    int
+	Chosen    map[int]bool
+	Done      bool
+	Cancelled bool
+}
+
+func New(issuePath string, tasks []issue.Task) Model {
+	return Model{
+		Issue:  issuePath,
+		Tasks:  tasks,
+		Chosen: make(map[int]bool),
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Selected returns the chosen task numbers in task order
+func (m Model) Selected() []int {
+	var numbers []int
+	for _, t := range m.Tasks {
+		if m.Chosen[t.Number] {
+			numbers = append(numbers, t.Number)
+		}
+	}
+	return numbers
+}
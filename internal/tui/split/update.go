@@ -0,0 +1,38 @@
+package split
+
+import tea "github.com/charmbracelet/bubbletea"
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc", "q":
+		m.Cancelled = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Tasks)-1 {
+			m.Cursor++
+		}
+	case " ", "x":
+		if len(m.Tasks) > 0 {
+			n := m.Tasks[m.Cursor].Number
+			m.Chosen[n] = !m.Chosen[n]
+		}
+	case "enter":
+		// Require at least one task
+		if len(m.Selected()) == 0 {
+			return m, nil
+		}
+		m.Done = true
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
@@ -0,0 +1,43 @@
+package split
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jewell-lgtm/monkeypuzzle/pkg/styles"
+)
+
+func (m Model) View() string {
+	if m.Cancelled {
+		return styles.Subtle.Render("Cancelled.\n")
+	}
+	if m.Done {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Split Issue"))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Label.Render(fmt.Sprintf("Tasks in %s to split into new issues:", m.Issue)))
+	b.WriteString("\n")
+
+	for i, t := range m.Tasks {
+		cursor := "  "
+		if i == m.Cursor {
+			cursor = styles.Cursor.Render("> ")
+		}
+		check := "[ ]"
+		if m.Chosen[t.Number] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %d. %s", check, t.Number, t.Text)
+		if i == m.Cursor {
+			line = styles.Selected.Render(line)
+		}
+		b.WriteString(cursor + line + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.Subtle.Render("space to select • enter to split • esc to cancel"))
+	return b.String()
+}
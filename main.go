@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/jewell-lgtm/monkeypuzzle/cmd/mp"
@@ -8,6 +9,11 @@ import (
 
 func main() {
 	if err := mp.Execute(); err != nil {
+		// Plugins exit with their own code
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(1)
 	}
 }